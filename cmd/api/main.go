@@ -29,8 +29,11 @@ import (
 // @externalDocs.description  User API Documentation
 // @externalDocs.url          https://docs.google.com/document/d/1uDNY5pHNrR1YQpE2YbsyZawDvMV-9mEekDRtLomjBlk/edit?usp=sharing
 func main() {
-	config := apiconfig.LoadConfig() // lee las variables de entorno
 	ctx := context.Background()
+	config, err := apiconfig.LoadConfig() // lee las variables de entorno
+	if err != nil {
+		log.Fatal(ctx, "Error loading config", slog.String("error", err.Error()))
+	}
 
 	router.SetEnviroment(config.Environment)
 
@@ -12,7 +12,10 @@ import (
 
 func main() {
 	ctx := context.Background()
-	cfg := config.LoadConfig() // lee las variables de entorno
+	cfg, err := config.LoadConfig() // lee las variables de entorno
+	if err != nil {
+		log.Fatal(ctx, "Error loading config", slog.String("error", err.Error()))
+	}
 
 	db, err := cfg.CreateDatabase()
 	if err != nil {
@@ -15,53 +15,92 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/admins": {
-            "post": {
+        "/admin/audit": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Registers a new Admin",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Lists the privileged actions, newest first, a page at a time. With format=csv every matching event is exported, oldest first",
                 "produces": [
-                    "text/plain"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Register a new Admin",
+                "summary": "Get the audit log",
                 "parameters": [
                     {
-                        "description": "User Registration Details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateUserRequest"
-                        }
+                        "type": "integer",
+                        "description": "Only actions of this admin",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this action, like user.block",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only actions on this kind of resource, like user or rule",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only actions on this resource",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only actions on or after this day (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only actions on or before this day (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Events per page, 50 by default and 500 at most",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "json by default",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User created successfully",
+                    "200": {
+                        "description": "Events in data and the page in pagination",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid request format",
-                        "schema": {
-                            "$ref": "#/definitions/utils.HTTPError"
-                        }
-                    },
-                    "409": {
-                        "description": "Email already exists",
+                        "description": "Invalid filter",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
@@ -75,52 +114,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/google": {
-            "post": {
-                "description": "Authenticate a user using Google OAuth2",
-                "consumes": [
-                    "application/json"
+        "/admin/audit/checkpoints": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Lists the latest signed snapshots of the head of the rules audit hash chain, newest first, along with the key they are verified with. Keep copies outside the system: removing or editing audit entries breaks them",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Authenticate with Google",
+                "summary": "Get the signed checkpoints of the rules audit",
                 "parameters": [
                     {
-                        "description": "Google OAuth2 Token",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.AuthRequest"
-                        }
+                        "type": "integer",
+                        "description": "Checkpoints to return, 50 by default and 500 at most",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Checkpoints in data and the base64 Ed25519 key in public_key",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/utils.HTTPError"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid limit",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
@@ -128,58 +160,97 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/login": {
-            "post": {
-                "description": "Logs in a user with email and password, returning user information and a JWT token.",
-                "consumes": [
-                    "application/json"
+        "/admin/emails": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Lists the names of the emails the platform sends and the locales they are written in, the default one first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "User login",
-                "parameters": [
-                    {
-                        "description": "Login Credentials",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                "summary": "List the email templates",
+                "responses": {
+                    "200": {
+                        "description": "Names in data.templates and locales in data.locales",
                         "schema": {
-                            "$ref": "#/definitions/models.LoginRequest"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
+                }
+            }
+        },
+        "/admin/emails/{name}/preview": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Renders the email template with sample data. Without a locale it is rendered in the first one of Accept-Language there is a variant for, or in English",
+                "produces": [
+                    "application/json",
+                    "text/html",
+                    "text/plain"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Preview an email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale to render, like es or en",
+                        "name": "locale",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "html",
+                            "text"
+                        ],
+                        "type": "string",
+                        "description": "json with the subject, text and html by default",
+                        "name": "format",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successful login returns user and token",
+                        "description": "Rendered email in data",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid request format or input validation failed",
-                        "schema": {
-                            "$ref": "#/definitions/utils.HTTPError"
-                        }
-                    },
-                    "401": {
-                        "description": "Invalid email or password",
+                        "description": "Invalid format",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
                     },
-                    "403": {
-                        "description": "User is blocked",
+                    "404": {
+                        "description": "Unknown template",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
                     },
                     "500": {
-                        "description": "Internal server error (e.g., token generation failed)",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
@@ -187,71 +258,98 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/logout": {
+        "/admin/jobs/dead": {
             "get": {
-                "description": "Logout the user by clearing the cookie",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Lists the background jobs, like emails and push notifications, that failed too many times to be retried, newest first. Jobs with secrets, like reset tokens, are listed without their payload.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
+                ],
+                "summary": "List the dead jobs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Jobs to return, 50 by default and 500 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Logout",
                 "responses": {
-                    "307": {
-                        "description": "Redirected to home page",
+                    "200": {
+                        "description": "Dead jobs in data",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
                         }
                     }
                 }
             }
         },
-        "/auth/users": {
+        "/admin/jobs/dead/{id}/requeue": {
             "post": {
-                "description": "Registers a new user",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Moves a dead job back to the queue to run right away, with its attempts reset",
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Register a new user",
+                "summary": "Requeue a dead job",
                 "parameters": [
                     {
-                        "description": "User Registration Details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateUserRequest"
-                        }
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User created successfully",
+                    "200": {
+                        "description": "Requeued job in data",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid request format",
+                        "description": "Invalid job ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Dead job not found",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
                     },
                     "409": {
-                        "description": "Email already exists",
+                        "description": "The job had secrets and its payload was dropped",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
@@ -265,38 +363,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/users/verify": {
+        "/admin/notifications/types": {
             "post": {
-                "description": "Verify the new user's registration using a Pin sent to the users email",
+                "description": "Adds a notification type to the registry, users get it by every channel until they turn it off. Mandatory types can't be turned off.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Verify a new user's registration",
+                "summary": "Register a notification type",
                 "parameters": [
                     {
-                        "description": "Email Verification Details",
+                        "description": "Notification type",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.EmailVerifiaction"
+                            "$ref": "#/definitions/models.NotificationType"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "User Verified and created successfully",
+                        "description": "Registered type",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
+                            "$ref": "#/definitions/models.NotificationType"
                         }
                     },
                     "400": {
@@ -305,6 +400,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.HTTPError"
                         }
                     },
+                    "409": {
+                        "description": "There's a type with the name",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -314,31 +415,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/users/verify/resend": {
-            "put": {
-                "description": "Sends a new Verification Pin to email saved in verification cookie",
-                "consumes": [
-                    "application/json"
+        "/admin/rules/acceptance": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "For every active rule, how many of the users bound by the rules accepted its current version, only an earlier one, or none",
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "Auth"
-                ],
-                "summary": "Sends a new Verification",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Email",
-                        "name": "email",
-                        "in": "query",
-                        "required": true
-                    }
+                    "Admin"
                 ],
+                "summary": "Get the acceptance coverage of the rules",
                 "responses": {
                     "200": {
-                        "description": "New Pin sent successfully"
+                        "description": "Coverage of each rule",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.RuleAcceptanceReport"
+                            }
+                        }
                     },
                     "500": {
                         "description": "Internal server error",
@@ -349,76 +449,60 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/verify": {
+        "/admin/stats": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Verifies the JWT token and returns a success message if valid",
+                "description": "Aggregates of the user base: users by role and verification state, signups per day or week, logins,\nblocked users, notification tokens by provider and AI chat usage. Activity is counted within the\nrange, the last 30 days by default. Results are cached for a minute",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Verify a JWT token",
+                "summary": "Get dashboard statistics",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bearer token",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
+                        "description": "First day of the range (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day of the range (YYYY-MM-DD), today by default",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week"
+                        ],
+                        "type": "string",
+                        "description": "Size of the signup buckets, day by default",
+                        "name": "interval",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Token is valid",
+                        "description": "Statistics",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "string"
+                                "$ref": "#/definitions/models.AdminStats"
                             }
                         }
                     },
-                    "401": {
-                        "description": "Invalid or expired token",
+                    "400": {
+                        "description": "Invalid range or interval",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
-                    }
-                }
-            }
-        },
-        "/chat": {
-            "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Gets The messages of the last 2 days",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "text/plain"
-                ],
-                "tags": [
-                    "Chat"
-                ],
-                "summary": "Get recent messages",
-                "responses": {
-                    "200": {
-                        "description": "messages",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.ChatMessage"
-                            }
-                        }
                     },
                     "500": {
                         "description": "Internal server error",
@@ -427,44 +511,49 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/users/bulk": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Send a new message to the assitant",
+                "description": "Blocks, unblocks, changes the role of, verifies or soft deletes the users with the given ids, or the ones matching\na filter like the one of the user listing (dates in RFC 3339). Everything runs in one transaction and is recorded\nas a single audit entry. Up to 1000 users can be changed at once and the admin running the action is always skipped",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "Chat"
+                    "Admin"
                 ],
-                "summary": "Send a new message to the assitant",
+                "summary": "Run an action on many users",
                 "parameters": [
                     {
-                        "description": "User Registration Details",
+                        "description": "Users and action",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ChatMessageRequest"
+                            "$ref": "#/definitions/models.BulkUserActionRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Ai assitant response",
+                        "description": "Result for every user",
                         "schema": {
-                            "$ref": "#/definitions/models.ChatMessage"
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.BulkUserActionReport"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid request format",
+                        "description": "Invalid request or too many users",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
@@ -478,45 +567,95 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/{message_id}/feedback": {
-            "put": {
+        "/admin/users/export": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Add feedback to a message so the ai can better generate a response",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Streams the users matching the same filters as the user listing as CSV, XLSX or JSON lines.\nThe columns and their order can be chosen, the password is never exported",
                 "produces": [
-                    "text/plain"
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/x-ndjson"
                 ],
                 "tags": [
-                    "Chat"
+                    "Admin"
                 ],
-                "summary": "Add feedback to a message",
+                "summary": "Export users",
                 "parameters": [
                     {
-                        "description": "Rating",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ChatFeedbackRequest"
-                        }
+                        "enum": [
+                            "csv",
+                            "xlsx",
+                            "jsonl"
+                        ],
+                        "type": "string",
+                        "description": "Format of the file, csv by default",
+                        "name": "format",
+                        "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Message Id",
-                        "name": "message_id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Comma separated columns: id, name, surname, email, location, role, verified, blocked, profile_photo, description, created_at, updated_at. All by default",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "student",
+                            "teacher",
+                            "admin"
+                        ],
+                        "type": "string",
+                        "description": "Only users with this role",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only verified or unverified users",
+                        "name": "verified",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only blocked or unblocked users",
+                        "name": "blocked",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text to search in the name, surname and email",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users created on or after this day (YYYY-MM-DD)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users created on or before this day (YYYY-MM-DD)",
+                        "name": "created_to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "Exported users",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format, column or filter",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
                     },
                     "500": {
                         "description": "Internal server error",
@@ -527,117 +666,153 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/{message_id}/rate": {
-            "put": {
+        "/admin/users/import": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Add rating to a message beteween 1 and 5",
+                "description": "Creates users from a CSV file (with an email, name, surname and optional role and password header) or from JSON lines.\nEvery row is validated like a registration. Users without password get a random one and can be invited to choose theirs.\nFiles with more than 200 rows, or with async=true, are imported in the background and the response has the id of the job to poll",
                 "consumes": [
-                    "application/json"
+                    "text/csv",
+                    "application/x-ndjson"
                 ],
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "Chat"
+                    "Admin"
                 ],
-                "summary": "Add rating to a message",
+                "summary": "Import users",
                 "parameters": [
                     {
-                        "description": "Rating",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ChatRatingRequest"
-                        }
+                        "type": "boolean",
+                        "description": "Only validate the rows",
+                        "name": "dry_run",
+                        "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Message Id",
-                        "name": "message_id",
-                        "in": "path",
-                        "required": true
+                        "enum": [
+                            "transactional",
+                            "best_effort"
+                        ],
+                        "type": "string",
+                        "description": "transactional (default) creates all rows or none, best_effort creates every valid row",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Email created users a link to choose their password",
+                        "name": "invite",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Run the import in the background",
+                        "name": "async",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "Import report with the outcome of every row",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.UserImportReport"
+                            }
+                        }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "202": {
+                        "description": "Id of the job running the import",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the import job"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid options or malformed file",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
-                    }
-                }
-            }
-        },
-        "/health": {
-            "get": {
-                "description": "Check the health of the service and database connection",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Health"
-                ],
-                "summary": "Health check",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "413": {
+                        "description": "File too large",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported content type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/utils.HTTPError"
                         }
                     }
                 }
             }
         },
-        "/rules": {
+        "/admin/users/import/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Returns a list of all rules in the system",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the status of a background import and its report once it is done",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rules"
+                    "Admin"
+                ],
+                "summary": "Get an import job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all rules",
                 "responses": {
                     "200": {
-                        "description": "List of rules",
+                        "description": "Import job",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/models.Rule"
-                                }
+                                "$ref": "#/definitions/models.UserImportJob"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Invalid job ID format",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -645,14 +820,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/admins": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new rule",
+                "description": "Registers a new Admin",
                 "consumes": [
                     "application/json"
                 ],
@@ -660,26 +837,38 @@ const docTemplate = `{
                     "text/plain"
                 ],
                 "tags": [
-                    "Rules"
+                    "Auth"
                 ],
-                "summary": "Create a new rule",
+                "summary": "Register a new Admin",
                 "parameters": [
                     {
-                        "description": "Rule creation Details",
+                        "description": "User Registration Details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Rule"
+                            "$ref": "#/definitions/models.CreateUserRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Rule created correctly"
+                        "description": "User created successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
                     },
                     "400": {
-                        "description": "Invalid request format",
+                        "description": "Invalid request format or password rejected by the password policy",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already exists",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
@@ -693,14 +882,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/rules/audit": {
-            "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Returns a list of all audits in the system",
+        "/auth/google": {
+            "post": {
+                "description": "Authenticate a user using Google OAuth2",
                 "consumes": [
                     "application/json"
                 ],
@@ -708,24 +892,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Rules"
+                    "Auth"
+                ],
+                "summary": "Authenticate with Google",
+                "parameters": [
+                    {
+                        "description": "Google OAuth2 Token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AuthRequest"
+                        }
+                    }
                 ],
-                "summary": "Get all audits",
                 "responses": {
                     "200": {
-                        "description": "List of audits",
+                        "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/models.AuditData"
-                                }
-                            }
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
@@ -733,95 +935,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/rules/{id}": {
-            "put": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Updates the contents of a rule",
+        "/auth/login": {
+            "post": {
+                "description": "Logs in a user with email and password, returning user information and a JWT token.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Rules"
+                    "Auth"
                 ],
-                "summary": "Modify rule password",
+                "summary": "User login",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Rule ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Elements to modify",
-                        "name": "modifications",
+                        "description": "Login Credentials",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.RuleModify"
+                            "$ref": "#/definitions/models.LoginRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "rule updated successfully"
+                        "description": "Successful login returns user and token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
                     "400": {
-                        "description": "Invalid user ID format or request",
+                        "description": "Invalid request format or input validation failed",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Invalid email or password",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Removes a user from the database",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Rules"
-                ],
-                "summary": "Delete user by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Rule ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "Rule successfully deleted"
                     },
-                    "400": {
-                        "description": "Invalid user ID format",
+                    "403": {
+                        "description": "User is blocked",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal server error (e.g., token generation failed)",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
@@ -829,14 +994,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users": {
+        "/auth/logout": {
             "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Returns a list of all users in the system",
+                "description": "Logout the user by clearing the cookie",
                 "consumes": [
                     "application/json"
                 ],
@@ -844,62 +1004,61 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Auth"
                 ],
-                "summary": "Get all users",
+                "summary": "Logout",
                 "responses": {
-                    "200": {
-                        "description": "List of users",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/models.User"
-                                }
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                    "307": {
+                        "description": "Redirected to home page",
                         "schema": {
-                            "$ref": "#/definitions/utils.HTTPError"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/users/:id/notifications/preference": {
-            "get": {
-                "description": "Modify the preference of a notification type between exam_notification homework_notification or social_notification",
+        "/auth/users": {
+            "post": {
+                "description": "Registers a new user",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "Users"
+                    "Auth"
                 ],
-                "summary": "Modify the preference of a notification type",
+                "summary": "Register a new user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "User Registration Details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateUserRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "preferences",
+                    "201": {
+                        "description": "User created successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.NotificationPreference"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid request format",
+                        "description": "Invalid request format or password rejected by the password policy",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already exists",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
@@ -911,40 +1070,41 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Modify the preference of a notification type between exam_notification homework_notification or social_notification",
+            }
+        },
+        "/auth/users/verify": {
+            "post": {
+                "description": "Verify the new user's registration using a Pin sent to the users email",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "Users"
+                    "Auth"
                 ],
-                "summary": "Modify the preference of a notification type",
+                "summary": "Verify a new user's registration",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "NotificationPreferenceRequest payload",
-                        "name": "NotificationPreferenceRequest",
+                        "description": "Email Verification Details",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.NotificationPreferenceRequest"
+                            "$ref": "#/definitions/models.EmailVerifiaction"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "preference changed successfully"
+                    "201": {
+                        "description": "User Verified and created successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Invalid request format",
@@ -961,14 +1121,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/block/{id}": {
+        "/auth/users/verify/resend": {
             "put": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Blocks a user by ID",
+                "description": "Sends a new Verification Pin to email saved in verification cookie",
                 "consumes": [
                     "application/json"
                 ],
@@ -976,30 +1131,21 @@ const docTemplate = `{
                     "text/plain"
                 ],
                 "tags": [
-                    "Users"
+                    "Auth"
                 ],
-                "summary": "Block user",
+                "summary": "Sends a new Verification",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Email",
+                        "name": "email",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User blocked successfully",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid user ID format",
-                        "schema": {
-                            "$ref": "#/definitions/utils.HTTPError"
-                        }
+                        "description": "New Pin sent successfully"
                     },
                     "500": {
                         "description": "Internal server error",
@@ -1010,92 +1156,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/notify": {
-            "post": {
-                "description": "Send a notification to users sent in body",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Users"
-                ],
-                "summary": "Send a notification to users",
-                "parameters": [
+        "/auth/verify": {
+            "get": {
+                "security": [
                     {
-                        "description": "NotificationToken payload",
-                        "name": "NotificationToken",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.NotifyRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "Users notified successfully"
-                    },
-                    "400": {
-                        "description": "Invalid request",
-                        "schema": {
-                            "$ref": "#/definitions/utils.HTTPError"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/utils.HTTPError"
-                        }
+                        "Bearer": []
                     }
-                }
-            }
-        },
-        "/users/password": {
-            "put": {
-                "description": "Updates the password of a specific user",
-                "consumes": [
-                    "application/json"
                 ],
+                "description": "Verifies the JWT token and returns a success message if valid",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Auth"
                 ],
-                "summary": "Modify user password",
+                "summary": "Verify a JWT token",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
                         "required": true
-                    },
-                    {
-                        "description": "User with updated password",
-                        "name": "password",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.PasswordModifyRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Pasword updated successfully"
-                    },
-                    "400": {
-                        "description": "Invalid user ID format or request",
+                        "description": "Token is valid",
                         "schema": {
-                            "$ref": "#/definitions/utils.HTTPError"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Invalid or expired token",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
@@ -1103,33 +1199,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/reset/password": {
-            "post": {
-                "description": "Start the process to reset password, sends and email with a link to make a new password",
+        "/chat": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets The messages of the last 2 days",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "Users"
-                ],
-                "summary": "Start the process to reset password",
-                "parameters": [
-                    {
-                        "description": "PasswordResetRequest payload",
-                        "name": "PasswordResetRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.PasswordResetRequest"
-                        }
-                    }
+                    "Chat"
                 ],
+                "summary": "Get recent messages",
                 "responses": {
                     "200": {
-                        "description": "Link sent successfully"
+                        "description": "messages",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ChatMessage"
+                            }
+                        }
                     },
                     "500": {
                         "description": "Internal server error",
@@ -1138,109 +1234,96 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/users/{id}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Returns a specific user by their ID",
+                "description": "Send a new message to the assitant",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "Users"
+                    "Chat"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Send a new message to the assitant",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "User Registration Details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ChatMessageRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User data",
+                        "description": "Ai assitant response",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "$ref": "#/definitions/models.User"
-                            }
+                            "$ref": "#/definitions/models.ChatMessage"
                         }
                     },
                     "400": {
-                        "description": "Invalid user ID format",
+                        "description": "Invalid request format",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/chat/{message_id}/feedback": {
             "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Updates user information",
+                "description": "Add feedback to a message so the ai can better generate a response",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "Users"
+                    "Chat"
                 ],
-                "summary": "Modify user",
+                "summary": "Add feedback to a message",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated user data",
-                        "name": "user",
+                        "description": "Rating",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UserUpdateDto"
+                            "$ref": "#/definitions/models.ChatFeedbackRequest"
                         }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Message Id",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated user data",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "$ref": "#/definitions/models.UserUpdateDto"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid user ID format or request format",
-                        "schema": {
-                            "$ref": "#/definitions/utils.HTTPError"
-                        }
+                        "description": "OK"
                     },
                     "500": {
                         "description": "Internal server error",
@@ -1249,42 +1332,47 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/chat/{message_id}/rate": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Removes a user from the database",
+                "description": "Add rating to a message beteween 1 and 5",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "Users"
+                    "Chat"
                 ],
-                "summary": "Delete user by ID",
+                "summary": "Add rating to a message",
                 "parameters": [
+                    {
+                        "description": "Rating",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ChatRatingRequest"
+                        }
+                    },
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Message Id",
+                        "name": "message_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "User successfully deleted"
-                    },
-                    "400": {
-                        "description": "Invalid user ID format",
-                        "schema": {
-                            "$ref": "#/definitions/utils.HTTPError"
-                        }
+                    "200": {
+                        "description": "OK"
                     },
                     "500": {
                         "description": "Internal server error",
@@ -1295,9 +1383,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/notifications": {
+        "/health": {
             "get": {
-                "description": "Send a notification to users sent in body",
+                "description": "Check the health of the service and database connection",
                 "consumes": [
                     "application/json"
                 ],
@@ -1305,23 +1393,160 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Health"
                 ],
-                "summary": "Send a notification to users",
-                "parameters": [
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/types": {
+            "get": {
+                "description": "Returns the registry of notification types, users choose by which channels they get each one",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List the notification types",
+                "responses": {
+                    "200": {
+                        "description": "Notification types",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.NotificationType"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules": {
+            "get": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns a list of all rules in the system, drafts included. With at, only the rules in force at the end of that day",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Get all rules",
+                "parameters": [
+                    {
+                        "enum": [
+                            "draft",
+                            "scheduled",
+                            "active",
+                            "retired"
+                        ],
+                        "type": "string",
+                        "description": "Only rules in this stage of their lifecycle",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only rules in force at the end of this day (YYYY-MM-DD)",
+                        "name": "at",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "general",
+                            "academic",
+                            "conduct",
+                            "privacy",
+                            "communication",
+                            "evaluation"
+                        ],
+                        "type": "string",
+                        "description": "Only rules in this category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only rules with all of these tags",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return the title and description in the first of these locales the rule is translated to, without the translations",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Users notified successfully",
+                        "description": "List of rules",
                         "schema": {
-                            "$ref": "#/definitions/models.NotificationTokens"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/models.Rule"
+                                }
+                            }
+                        },
+                        "headers": {
+                            "Content-Language": {
+                                "type": "string",
+                                "description": "Locale of the rules, when Accept-Language was sent"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the rule list"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Rules not modified since the given ETag"
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
                         }
                     },
                     "500": {
@@ -1333,38 +1558,110 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Set a notification token to users in order to be able to send push notifications",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a new rule",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "Users"
+                    "Rules"
                 ],
-                "summary": "Set a notification token to users",
+                "summary": "Create a new rule",
                 "parameters": [
                     {
-                        "description": "NotificationSetUpRequest payload",
-                        "name": "NotificationToken",
+                        "description": "Rule creation Details",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.NotificationSetUpRequest"
+                            "$ref": "#/definitions/models.Rule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Rule created correctly"
+                    },
+                    "202": {
+                        "description": "In four-eyes mode, proposal waiting for another admin",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.RuleProposal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request format or application condition",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
                         }
+                    }
+                }
+            }
+        },
+        "/rules/active": {
+            "get": {
+                "description": "Returns the active rules, the ones every user is bound by. No login is needed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Get the rules in force",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Return the title and description in the first of these locales the rule is translated to, without the translations",
+                        "name": "Accept-Language",
+                        "in": "header"
                     },
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Token Setup successful"
+                        "description": "Active rules",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/models.Rule"
+                                }
+                            }
+                        },
+                        "headers": {
+                            "Content-Language": {
+                                "type": "string",
+                                "description": "Locale of the rules, when Accept-Language was sent"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the rule list"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Rules not modified since the given ETag"
                     },
                     "500": {
                         "description": "Internal server error",
@@ -1375,42 +1672,87 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/teacher": {
-            "put": {
+        "/rules/audit": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "makes the user role \"teacher\"",
+                "description": "Lists the changes made to rules, newest first, a page at a time, with the state of the rule before and after each one",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Rules"
                 ],
-                "summary": "Make a user a \"teacher\"",
+                "summary": "Get the rules audit",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Only changes to this rule",
+                        "name": "rule_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only changes made by this user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "rule.create",
+                            "rule.update",
+                            "rule.delete",
+                            "rule.rollback",
+                            "rule.publish",
+                            "rule.activate",
+                            "rule.retire"
+                        ],
+                        "type": "string",
+                        "description": "Only this action",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only changes on or after this day (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only changes on or before this day (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entries per page, 50 by default and 500 at most",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User blocked successfully",
+                        "description": "Entries (models.AuditData) in data and the page in pagination",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid user ID format",
+                        "description": "Invalid filter",
                         "schema": {
                             "$ref": "#/definitions/utils.HTTPError"
                         }
@@ -1423,291 +1765,3783 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.AuditData": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "integer"
-                },
-                "modificationDate": {
-                    "type": "string"
-                },
-                "natureOfModification": {
-                    "type": "string"
-                },
-                "ruleId": {
-                    "type": "integer"
-                },
-                "userId": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.AuthRequest": {
-            "type": "object",
-            "properties": {
-                "token": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.ChatFeedbackRequest": {
-            "type": "object",
-            "required": [
-                "feedback"
-            ],
-            "properties": {
-                "feedback": {
-                    "type": "string"
-                }
-            }
         },
-        "models.ChatMessage": {
-            "type": "object",
-            "properties": {
-                "feedback": {
-                    "type": "string"
-                },
-                "message": {
-                    "type": "string"
-                },
-                "message_id": {
-                    "type": "integer"
+        "/rules/evaluate": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Evaluates a condition against a user, or against the given attributes, without saving anything. Times are given as dates (2006-01-02) or RFC 3339 timestamps.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Evaluate an application condition",
+                "parameters": [
+                    {
+                        "description": "Condition and who to evaluate it for",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RuleEvaluateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Whether the condition holds",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.RuleEvaluateResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request format, condition or attributes",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Evaluating for another user without being an admin",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/pending": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns the mandatory rules the logged in user hasn't accepted in their current version",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Get the rules the user must accept",
+                "responses": {
+                    "200": {
+                        "description": "Rules to accept",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/models.PendingRule"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/proposals": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lists the proposed changes to the rules, newest first, without their comments",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Get the rule proposals",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected"
+                        ],
+                        "type": "string",
+                        "description": "Only proposals in this status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Proposals",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/models.RuleProposal"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/proposals/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns the proposal with its comments, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Get a rule proposal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Proposal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Proposal",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.RuleProposal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid proposal ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Proposal not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/proposals/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Applies the proposed change. The rules audit records both the admin who proposed it and the one who approved it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Approve a rule proposal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Proposal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Proposal approved and applied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.RuleProposal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid proposal ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Admins can't approve their own proposals",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Proposal not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Proposal already reviewed, or the rule changed since it was proposed",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/proposals/{id}/comments": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Comment on a rule proposal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Proposal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Comment",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RuleProposalCommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Comment added",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.RuleProposalComment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid proposal ID or request format",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Proposal not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/proposals/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Discards the proposed change. Admins can reject their own proposals to withdraw them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Reject a rule proposal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Proposal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Why the proposal is rejected",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RuleProposalRejectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Proposal rejected",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.RuleProposal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid proposal ID or request format",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Proposal not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Proposal already reviewed",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Updates the contents of a rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Modify rule password",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Elements to modify",
+                        "name": "modifications",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RuleModify"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the rule, the rule version in quotes",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "rule updated successfully"
+                    },
+                    "202": {
+                        "description": "In four-eyes mode, proposal waiting for another admin",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.RuleProposal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format or request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "The effective date of a rule in force can't change",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "Rule was modified since the given ETag",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Removes a user from the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Delete user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the rule, the rule version in quotes",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "In four-eyes mode, proposal waiting for another admin",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.RuleProposal"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": "Rule successfully deleted"
+                    },
+                    "400": {
+                        "description": "Invalid user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "Rule was modified since the given ETag",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/{id}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Records that the logged in user accepted the current version of an active rule, along with when and from which IP",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Accept a rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the rule the user read, the rule version in quotes",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Acceptance recorded",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.RuleAcceptance"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Rule is not active",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "Rule was modified since the given ETag",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/{id}/publish": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Schedules the draft, it comes into force on its effective date and every user is notified then",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Publish a draft rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the rule, the rule version in quotes",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rule scheduled"
+                    },
+                    "202": {
+                        "description": "In four-eyes mode, proposal waiting for another admin",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.RuleProposal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Rule is not a draft",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "Rule was modified since the given ETag",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/{id}/retire": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Takes a scheduled or active rule out of force from now on",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Retire a rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the rule, the rule version in quotes",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rule retired"
+                    },
+                    "202": {
+                        "description": "In four-eyes mode, proposal waiting for another admin",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.RuleProposal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Rule is a draft or already retired",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "Rule was modified since the given ETag",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/{id}/rollback/{v}": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Restores the content of the given version as a new version of the rule",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Roll a rule back to an earlier version",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version to restore",
+                        "name": "v",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the rule, the rule version in quotes",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rule rolled back"
+                    },
+                    "202": {
+                        "description": "In four-eyes mode, proposal waiting for another admin",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.RuleProposal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule ID or version",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule or version not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Rule is already at that version, or is in force and the version has another effective date",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "Rule was modified since the given ETag",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/{id}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns every version kept of the rule, newest first. Versions of deleted rules are kept too",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Get the versions of a rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Versions of the rule",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/models.RuleVersion"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/{id}/versions/{v}/diff": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lists the fields that changed between the versions, and a word by word diff of the description",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Compare two versions of a rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version to compare",
+                        "name": "v",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version to compare it against, by default the one before it",
+                        "name": "against",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Changes from the against version to v",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.RuleDiff"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule ID or version",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule or version not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns a list of all users in the system, optionally filtered",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get all users",
+                "parameters": [
+                    {
+                        "enum": [
+                            "student",
+                            "teacher",
+                            "admin"
+                        ],
+                        "type": "string",
+                        "description": "Only users with this role",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only verified or unverified users",
+                        "name": "verified",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only blocked or unblocked users",
+                        "name": "blocked",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text to search in the name, surname and email",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users created on or after this day (YYYY-MM-DD)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users created on or before this day (YYYY-MM-DD)",
+                        "name": "created_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of users",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/models.User"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/:id/notifications/preference": {
+            "get": {
+                "description": "Tells whether exam_notification, homework_notification and social_notification are sent by any channel. Use GET /users/{id}/notifications/preferences for every type by channel.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get the preferences of the original notification types",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "preferences",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreference"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request format",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Turn a notification type on or off by every channel. Use PUT /users/{id}/notifications/preferences to set each channel.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Modify the preference of a notification type",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "NotificationPreferenceRequest payload",
+                        "name": "NotificationPreferenceRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreferenceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "preference changed successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request format or unknown or mandatory notification type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/block/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Blocks a user by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Block user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User blocked successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/email/cancel": {
+            "get": {
+                "description": "Cancels a pending email change using the link sent to the current email",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Cancel an email change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cancel token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Email change cancelled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "No pending email change for the token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/notify": {
+            "post": {
+                "description": "Send a notification to users sent in body, by the channels each user has enabled for its type. It's stored in the inbox unless the user turned that channel off. Mandatory types can't be sent.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Send a notification to users",
+                "parameters": [
+                    {
+                        "description": "NotificationToken payload",
+                        "name": "NotificationToken",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NotifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Users notified successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Mandatory notification type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password": {
+            "put": {
+                "description": "Updates the password of a specific user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Modify user password",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User with updated password",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PasswordModifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pasword updated successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request, invalid or expired token, or password rejected by the password policy",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many wrong attempts for the token, a new one must be requested",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/reset/password": {
+            "post": {
+                "description": "Start the process to reset password, sends and email with a link to make a new password.\nThe response is the same whether the email has an account or not",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Start the process to reset password",
+                "parameters": [
+                    {
+                        "description": "PasswordResetRequest payload",
+                        "name": "PasswordResetRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PasswordResetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Link sent successfully if the email has an account"
+                    },
+                    "400": {
+                        "description": "Invalid request format",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns a specific user by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User data",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the user"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "User not modified since the given ETag"
+                    },
+                    "400": {
+                        "description": "Invalid user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Updates user information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Modify user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated user data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserUpdateDto"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the user being modified",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated user data",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the user"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format or request format",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "User was modified since the given ETag",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Removes a user from the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the user being deleted",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "User successfully deleted"
+                    },
+                    "400": {
+                        "description": "Invalid user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "User was modified since the given ETag",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Applies a JSON Merge Patch (RFC 7396) to the user profile. Omitted fields are kept and fields set to null are cleared.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Partially update user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge patch document",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserPatch"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the user being modified",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated user data",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the user"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format, malformed patch or validation error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "User was modified since the given ETag",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported content type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/applicable-rules": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Evaluates the application condition of every active rule against the user. Rules whose condition can't be evaluated are listed in unevaluable.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Get the rules that apply to a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rules that apply to the user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.ApplicableRules"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/email": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sends a confirmation pin to the new email and a cancel link to the current one. The email is only changed once the pin is confirmed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Request an email change",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EmailChangeStartRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Confirmation pin sent",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or same email as the current one",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already exists",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/email/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Applies a pending email change using the pin sent to the new email. Every session of the user is closed and pending password resets are discarded",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Confirm an email change",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Confirmation pin",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EmailChangeConfirmRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Email changed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or pin",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already exists",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "410": {
+                        "description": "Pin expired",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/inbox": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lists the notifications sent to the user, newest first, along with how many are unread",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get the inbox of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entries per page, 50 by default and 500 at most",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only list the unread entries",
+                        "name": "unread",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entries in data, with the pagination and the unread count",
+                        "schema": {
+                            "$ref": "#/definitions/models.Inbox"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/inbox/read": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Marks every unread notification of the inbox as read",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Mark every notification as read",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "How many entries were marked in marked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/inbox/{entry_id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns a notification sent to the user, push notifications and emails link to it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get a notification of the inbox",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Inbox entry ID",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entry in data",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Removes a notification from the inbox of the user",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete a notification of the inbox",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Inbox entry ID",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Entry deleted"
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/inbox/{entry_id}/read": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Marks a notification of the inbox as read, keeping the time it was first read",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Mark a notification as read",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Inbox entry ID",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entry in data",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/notifications": {
+            "get": {
+                "description": "Send a notification to users sent in body",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Send a notification to users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Users notified successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationTokens"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Set a notification token to users in order to be able to send push notifications",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Set a notification token to users",
+                "parameters": [
+                    {
+                        "description": "NotificationSetUpRequest payload",
+                        "name": "NotificationToken",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationSetUpRequest"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token Setup successful"
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/notifications/preferences": {
+            "get": {
+                "description": "Returns by which channels the user gets every notification type, along with the registered types and the channels. Mandatory types are on by every channel.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get the notification preferences matrix",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Preferences matrix",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Sets whether the user gets each notification type by each channel. Only the cells sent are changed, nothing is saved if any of them is invalid.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update the notification preferences matrix",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Preferences by type and channel",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Whole preferences matrix",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, unknown type or channel, or mandatory type turned off",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/password": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Changes the password of a user who knows the current one. The new password must follow the password policy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Change password",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Current and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PasswordChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password changed"
+                    },
+                    "400": {
+                        "description": "Invalid request or password rejected by the password policy",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Current password is incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/stream": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Streams new inbox entries, blocks and forced logouts with Server-Sent Events, or as JSON messages over WebSocket when the request asks to upgrade. Inbox events carry the entry id as event id: reconnecting with it in the Last-Event-ID header, or the last_event_id query for WebSocket, first sends the entries that were missed. The stream ends after a block or logout event, and a heartbeat is sent while it's idle",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Stream the events of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id of the last inbox event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id of the last inbox event received, for clients that can't set headers",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of events",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user or event ID",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/teacher": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "makes the user role \"teacher\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Make a user a \"teacher\"",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User blocked successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HTTPError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AdminStats": {
+            "type": "object",
+            "properties": {
+                "chat": {
+                    "$ref": "#/definitions/models.ChatStats"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "logins": {
+                    "$ref": "#/definitions/models.LoginStats"
+                },
+                "notification_tokens": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "signups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StatsBucket"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "users": {
+                    "$ref": "#/definitions/models.UserStats"
+                }
+            }
+        },
+        "models.ApplicableRules": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Rule"
+                    }
+                },
+                "unevaluable": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AuthRequest": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BulkUserActionReport": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "audit_id": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BulkUserResult"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BulkUserActionRequest": {
+            "type": "object",
+            "required": [
+                "action"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "block",
+                        "unblock",
+                        "change_role",
+                        "verify",
+                        "soft_delete"
+                    ]
+                },
+                "duration_minutes": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "filter": {
+                    "$ref": "#/definitions/models.UserFilter"
+                },
+                "ids": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "reason": {
+                    "description": "Reason and DurationMinutes are for block, a block without duration is permanent",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "role": {
+                    "description": "Role is for change_role",
+                    "type": "string",
+                    "enum": [
+                        "student",
+                        "teacher",
+                        "admin"
+                    ]
+                }
+            }
+        },
+        "models.BulkUserResult": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChatFeedbackRequest": {
+            "type": "object",
+            "required": [
+                "feedback"
+            ],
+            "properties": {
+                "feedback": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChatMessage": {
+            "type": "object",
+            "properties": {
+                "feedback": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "message_id": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "sender": {
+                    "type": "string"
+                },
+                "time_sent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ChatMessageRequest": {
+            "type": "object",
+            "required": [
+                "message"
+            ],
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChatRatingRequest": {
+            "type": "object",
+            "required": [
+                "rating"
+            ],
+            "properties": {
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1
+                }
+            }
+        },
+        "models.ChatStats": {
+            "type": "object",
+            "properties": {
+                "active_users": {
+                    "type": "integer"
+                },
+                "average_rating": {
+                    "type": "number"
+                },
+                "messages": {
+                    "type": "integer"
+                },
+                "rated_messages": {
+                    "type": "integer"
+                },
+                "user_messages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password",
+                "role",
+                "surname"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 60,
+                    "minLength": 3
+                },
+                "password": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "student",
+                        "teacher",
+                        "admin"
+                    ]
+                },
+                "surname": {
+                    "type": "string",
+                    "maxLength": 60,
+                    "minLength": 3
+                }
+            }
+        },
+        "models.EmailChangeConfirmRequest": {
+            "type": "object",
+            "required": [
+                "pin"
+            ],
+            "properties": {
+                "pin": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EmailChangeStartRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EmailVerifiaction": {
+            "type": "object",
+            "properties": {
+                "pin": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Inbox": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InboxEntry"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/models.Pagination"
+                },
+                "unread": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.InboxEntry": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "read_at": {
+                    "description": "Nil until the user reads the entry",
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoginStats": {
+            "type": "object",
+            "properties": {
+                "active_users": {
+                    "type": "integer"
+                },
+                "attempts": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "failed_rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.NotificationPreference": {
+            "type": "object",
+            "required": [
+                "exam_notification",
+                "homework_notification",
+                "social_notification"
+            ],
+            "properties": {
+                "exam_notification": {
+                    "type": "boolean"
+                },
+                "homework_notification": {
+                    "type": "boolean"
+                },
+                "social_notification": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.NotificationPreferenceRequest": {
+            "type": "object",
+            "required": [
+                "notification_preference",
+                "notification_type"
+            ],
+            "properties": {
+                "notification_preference": {
+                    "type": "boolean"
+                },
+                "notification_type": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "models.NotificationPreferences": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "object",
+                "additionalProperties": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.NotificationPreferencesRequest": {
+            "type": "object",
+            "required": [
+                "preferences"
+            ],
+            "properties": {
+                "preferences": {
+                    "$ref": "#/definitions/models.NotificationPreferences"
+                }
+            }
+        },
+        "models.NotificationSetUpRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NotificationSettings": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "preferences": {
+                    "$ref": "#/definitions/models.NotificationPreferences"
+                },
+                "types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NotificationType"
+                    }
+                }
+            }
+        },
+        "models.NotificationToken": {
+            "type": "object",
+            "required": [
+                "notification_token"
+            ],
+            "properties": {
+                "created_time": {
+                    "type": "string"
+                },
+                "notification_token": {
+                    "type": "string",
+                    "maxLength": 225,
+                    "minLength": 1
+                }
+            }
+        },
+        "models.NotificationTokens": {
+            "type": "object",
+            "properties": {
+                "notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NotificationToken"
+                    }
+                }
+            }
+        },
+        "models.NotificationType": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "mandatory": {
+                    "description": "Mandatory notifications are sent by every channel, whatever the preferences",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "models.NotifyRequest": {
+            "type": "object",
+            "required": [
+                "notification_text",
+                "notification_title",
+                "notification_type",
+                "users"
+            ],
+            "properties": {
+                "notification_text": {
+                    "type": "string",
+                    "maxLength": 225,
+                    "minLength": 1
+                },
+                "notification_title": {
+                    "type": "string",
+                    "maxLength": 225,
+                    "minLength": 1
+                },
+                "notification_type": {
+                    "description": "NotificationType is one of the registered notification types",
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.Pagination": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PasswordChangeRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PasswordModifyRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PasswordResetRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PendingRule": {
+            "type": "object",
+            "required": [
+                "ApplicationCondition",
+                "Description",
+                "Title"
+            ],
+            "properties": {
+                "ApplicationCondition": {
+                    "type": "string"
                 },
-                "rating": {
+                "Description": {
+                    "type": "string"
+                },
+                "Title": {
+                    "type": "string"
+                },
+                "accepted_version": {
+                    "description": "Latest version of the rule the user accepted, 0 if they never did",
                     "type": "integer"
                 },
-                "sender": {
+                "category": {
+                    "description": "RuleGeneral when not given",
+                    "enum": [
+                        "general",
+                        "academic",
+                        "conduct",
+                        "privacy",
+                        "communication",
+                        "evaluation"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RuleCategory"
+                        }
+                    ]
+                },
+                "effectiveDate": {
                     "type": "string"
                 },
-                "time_sent": {
+                "id": {
+                    "type": "integer"
+                },
+                "locale": {
+                    "description": "Locale Title and Description are in, only set when the rule was localized for the client",
                     "type": "string"
                 },
-                "user_id": {
+                "mandatory": {
+                    "description": "Users must accept the current version of mandatory rules, like the terms of service",
+                    "type": "boolean"
+                },
+                "retired_at": {
+                    "description": "When the rule stopped being in force, only for retired rules",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Set by the server, new rules are drafts",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RuleStatus"
+                        }
+                    ]
+                },
+                "tags": {
+                    "description": "Free labels, stored lowercase",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "translations": {
+                    "description": "Title and description in other languages, by locale",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.RuleTranslation"
+                    }
+                },
+                "version": {
                     "type": "integer"
                 }
             }
         },
-        "models.ChatMessageRequest": {
+        "models.Rule": {
             "type": "object",
             "required": [
-                "message"
+                "ApplicationCondition",
+                "Description",
+                "Title"
             ],
             "properties": {
-                "message": {
+                "ApplicationCondition": {
+                    "type": "string"
+                },
+                "Description": {
+                    "type": "string"
+                },
+                "Title": {
+                    "type": "string"
+                },
+                "category": {
+                    "description": "RuleGeneral when not given",
+                    "enum": [
+                        "general",
+                        "academic",
+                        "conduct",
+                        "privacy",
+                        "communication",
+                        "evaluation"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RuleCategory"
+                        }
+                    ]
+                },
+                "effectiveDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "locale": {
+                    "description": "Locale Title and Description are in, only set when the rule was localized for the client",
+                    "type": "string"
+                },
+                "mandatory": {
+                    "description": "Users must accept the current version of mandatory rules, like the terms of service",
+                    "type": "boolean"
+                },
+                "retired_at": {
+                    "description": "When the rule stopped being in force, only for retired rules",
                     "type": "string"
+                },
+                "status": {
+                    "description": "Set by the server, new rules are drafts",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RuleStatus"
+                        }
+                    ]
+                },
+                "tags": {
+                    "description": "Free labels, stored lowercase",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "translations": {
+                    "description": "Title and description in other languages, by locale",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.RuleTranslation"
+                    }
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.ChatRatingRequest": {
+        "models.RuleAcceptance": {
             "type": "object",
-            "required": [
-                "rating"
-            ],
             "properties": {
-                "rating": {
-                    "type": "integer",
-                    "maximum": 5,
-                    "minimum": 1
+                "accepted_at": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "rule_id": {
+                    "type": "integer"
+                },
+                "rule_version": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateUserRequest": {
+        "models.RuleAcceptanceCoverage": {
             "type": "object",
-            "required": [
-                "email",
-                "name",
-                "password",
-                "role",
-                "surname"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "accepted": {
+                    "description": "Users that accepted the current version",
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 60,
-                    "minLength": 3
+                "accepted_earlier_version": {
+                    "description": "Users that only accepted an earlier version",
+                    "type": "integer"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8
+                "coverage": {
+                    "description": "Share of the users that accepted the current version, between 0 and 1",
+                    "type": "number"
                 },
-                "role": {
-                    "type": "string",
-                    "enum": [
-                        "student",
-                        "teacher",
-                        "admin"
-                    ]
+                "mandatory": {
+                    "type": "boolean"
                 },
-                "surname": {
-                    "type": "string",
-                    "maxLength": 60,
-                    "minLength": 3
+                "pending": {
+                    "description": "Users that haven't accepted the current version",
+                    "type": "integer"
+                },
+                "rule_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.EmailVerifiaction": {
+        "models.RuleAcceptanceReport": {
             "type": "object",
             "properties": {
-                "pin": {
-                    "type": "string"
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RuleAcceptanceCoverage"
+                    }
+                },
+                "users": {
+                    "description": "Users bound by the rules: not deleted and not admins",
+                    "type": "integer"
                 }
             }
         },
-        "models.LoginRequest": {
+        "models.RuleCategory": {
+            "type": "string",
+            "enum": [
+                "general",
+                "academic",
+                "conduct",
+                "privacy",
+                "communication",
+                "evaluation"
+            ],
+            "x-enum-varnames": [
+                "RuleGeneral",
+                "RuleAcademic",
+                "RuleConduct",
+                "RulePrivacy",
+                "RuleCommunication",
+                "RuleEvaluation"
+            ]
+        },
+        "models.RuleDiff": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RuleFieldChange"
+                    }
+                },
+                "description_diff": {
+                    "description": "Word by word changes of the description",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.DiffChunk"
+                    }
+                },
+                "from": {
+                    "description": "Zero when comparing against a rule that didn't exist yet",
+                    "type": "integer"
+                },
+                "rule_id": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
+                },
+                "unknown_fields": {
+                    "description": "Fields one of the versions didn't record, so they can't be compared",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.RuleEvaluateRequest": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "condition"
             ],
             "properties": {
-                "email": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "condition": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.NotificationPreference": {
+        "models.RuleEvaluateResponse": {
             "type": "object",
-            "required": [
-                "exam_notification",
-                "homework_notification",
-                "social_notification"
-            ],
             "properties": {
-                "exam_notification": {
+                "applies": {
                     "type": "boolean"
                 },
-                "homework_notification": {
-                    "type": "boolean"
+                "condition": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RuleFieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
                 },
-                "social_notification": {
+                "from": {},
+                "to": {}
+            }
+        },
+        "models.RuleModify": {
+            "type": "object",
+            "properties": {
+                "ApplicationCondition": {
+                    "type": "string"
+                },
+                "Description": {
+                    "type": "string"
+                },
+                "Title": {
+                    "type": "string"
+                },
+                "category": {
+                    "enum": [
+                        "general",
+                        "academic",
+                        "conduct",
+                        "privacy",
+                        "communication",
+                        "evaluation"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RuleCategory"
+                        }
+                    ]
+                },
+                "effectiveDate": {
+                    "description": "Only drafts and scheduled rules can change their effective date",
+                    "type": "string"
+                },
+                "mandatory": {
                     "type": "boolean"
+                },
+                "tags": {
+                    "description": "Replace all the tags or translations of the rule, an empty list or object removes them",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "translations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.RuleTranslation"
+                    }
                 }
             }
         },
-        "models.NotificationPreferenceRequest": {
+        "models.RuleProposal": {
             "type": "object",
-            "required": [
-                "notification_preference",
-                "notification_type"
-            ],
             "properties": {
-                "notification_preference": {
-                    "type": "boolean"
+                "action": {
+                    "$ref": "#/definitions/models.RuleProposalAction"
+                },
+                "comments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RuleProposalComment"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "modification": {
+                    "description": "Modification to apply, only for modify proposals",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RuleModify"
+                        }
+                    ]
+                },
+                "proposed_by": {
+                    "type": "integer"
+                },
+                "review_reason": {
+                    "description": "Why the proposal was rejected",
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "integer"
+                },
+                "rule": {
+                    "description": "Rule to create, only for create proposals",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Rule"
+                        }
+                    ]
                 },
-                "notification_type": {
-                    "type": "string"
+                "rule_id": {
+                    "description": "Rule being changed or deleted, and the version the proposal was made against",
+                    "type": "integer"
+                },
+                "rule_version": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.RuleProposalStatus"
+                },
+                "target": {
+                    "description": "Version to restore, only for rollback proposals",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RuleVersion"
+                        }
+                    ]
                 }
             }
         },
-        "models.NotificationSetUpRequest": {
-            "type": "object",
-            "required": [
-                "token"
+        "models.RuleProposalAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "modify",
+                "delete",
+                "rollback",
+                "publish",
+                "retire"
             ],
-            "properties": {
-                "token": {
-                    "type": "string"
-                }
-            }
+            "x-enum-varnames": [
+                "RuleProposalCreate",
+                "RuleProposalModify",
+                "RuleProposalDelete",
+                "RuleProposalRollback",
+                "RuleProposalPublish",
+                "RuleProposalRetire"
+            ]
         },
-        "models.NotificationToken": {
+        "models.RuleProposalComment": {
             "type": "object",
-            "required": [
-                "notification_token"
-            ],
             "properties": {
-                "created_time": {
+                "comment": {
                     "type": "string"
                 },
-                "notification_token": {
-                    "type": "string",
-                    "maxLength": 225,
-                    "minLength": 1
-                }
-            }
-        },
-        "models.NotificationTokens": {
-            "type": "object",
-            "properties": {
-                "notifications": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.NotificationToken"
-                    }
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "proposal_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.NotifyRequest": {
+        "models.RuleProposalCommentRequest": {
             "type": "object",
             "required": [
-                "notification_text",
-                "notification_title",
-                "notification_type",
-                "users"
+                "comment"
             ],
             "properties": {
-                "notification_text": {
-                    "type": "string",
-                    "maxLength": 225,
-                    "minLength": 1
-                },
-                "notification_title": {
+                "comment": {
                     "type": "string",
-                    "maxLength": 225,
-                    "minLength": 1
-                },
-                "notification_type": {
-                    "type": "string"
-                },
-                "users": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                    "maxLength": 2000
                 }
             }
         },
-        "models.PasswordModifyRequest": {
+        "models.RuleProposalRejectRequest": {
             "type": "object",
             "required": [
-                "password",
-                "token"
+                "reason"
             ],
             "properties": {
-                "password": {
-                    "type": "string",
-                    "maxLength": 60,
-                    "minLength": 8
-                },
-                "token": {
+                "reason": {
                     "type": "string",
-                    "maxLength": 6
+                    "maxLength": 2000
                 }
             }
         },
-        "models.PasswordResetRequest": {
+        "models.RuleProposalStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "RuleProposalPending",
+                "RuleProposalApproved",
+                "RuleProposalRejected"
+            ]
+        },
+        "models.RuleStatus": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "scheduled",
+                "active",
+                "retired"
+            ],
+            "x-enum-varnames": [
+                "RuleDraft",
+                "RuleScheduled",
+                "RuleActive",
+                "RuleRetired"
+            ]
+        },
+        "models.RuleTranslation": {
             "type": "object",
             "required": [
-                "email"
+                "description",
+                "title"
             ],
             "properties": {
-                "email": {
+                "description": {
+                    "type": "string"
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "models.Rule": {
+        "models.RuleVersion": {
             "type": "object",
             "required": [
                 "ApplicationCondition",
@@ -1724,24 +5558,89 @@ const docTemplate = `{
                 "Title": {
                     "type": "string"
                 },
+                "category": {
+                    "description": "RuleGeneral when not given",
+                    "enum": [
+                        "general",
+                        "academic",
+                        "conduct",
+                        "privacy",
+                        "communication",
+                        "evaluation"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RuleCategory"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "description": "Zero for versions from before history was kept",
+                    "type": "integer"
+                },
                 "effectiveDate": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
+                },
+                "locale": {
+                    "description": "Locale Title and Description are in, only set when the rule was localized for the client",
+                    "type": "string"
+                },
+                "mandatory": {
+                    "description": "Users must accept the current version of mandatory rules, like the terms of service",
+                    "type": "boolean"
+                },
+                "retired_at": {
+                    "description": "When the rule stopped being in force, only for retired rules",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Set by the server, new rules are drafts",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RuleStatus"
+                        }
+                    ]
+                },
+                "tags": {
+                    "description": "Free labels, stored lowercase",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "translations": {
+                    "description": "Title and description in other languages, by locale",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.RuleTranslation"
+                    }
+                },
+                "unknown_fields": {
+                    "description": "Fields the version didn't record, by their JSON key. Versions from before mandatory, category,\ntags and translations were kept don't know them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.RuleModify": {
+        "models.StatsBucket": {
             "type": "object",
             "properties": {
-                "ApplicationCondition": {
-                    "type": "string"
-                },
-                "Description": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 },
-                "Title": {
+                "start": {
                     "type": "string"
                 }
             }
@@ -1788,6 +5687,155 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UserFilter": {
+            "type": "object",
+            "properties": {
+                "blocked": {
+                    "type": "boolean"
+                },
+                "created_from": {
+                    "type": "string"
+                },
+                "created_to": {
+                    "type": "string"
+                },
+                "q": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "student",
+                        "teacher",
+                        "admin"
+                    ]
+                },
+                "verified": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.UserImportJob": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "report": {
+                    "$ref": "#/definitions/models.UserImportReport"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserImportReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserImportRowResult"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.UserImportRowResult": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserPatch": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "profile_photo": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserStats": {
+            "type": "object",
+            "properties": {
+                "blocked": {
+                    "type": "integer"
+                },
+                "by_role": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "unverified": {
+                    "type": "integer"
+                },
+                "verified": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.UserUpdateDto": {
             "type": "object",
             "properties": {
@@ -1808,6 +5856,30 @@ const docTemplate = `{
                 }
             }
         },
+        "utils.DiffChunk": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "$ref": "#/definitions/utils.DiffOp"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.DiffOp": {
+            "type": "string",
+            "enum": [
+                "equal",
+                "insert",
+                "delete"
+            ],
+            "x-enum-varnames": [
+                "DiffEqual",
+                "DiffInsert",
+                "DiffDelete"
+            ]
+        },
         "utils.HTTPError": {
             "type": "object",
             "properties": {
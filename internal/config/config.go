@@ -5,10 +5,16 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/DataDog/datadog-go/v5/statsd"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/go-core/pkg/log"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/go-core/pkg/telemetry"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/mailer"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
 	_ "github.com/jackc/pgx/v5"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
@@ -18,6 +24,8 @@ type Config struct {
 	Host        string
 	Port        string
 	Environment string
+	// Base URL of the API used in links sent to users, without the trailing slash
+	PublicURL string
 
 	// Database configuration
 	DatabaseURL string
@@ -32,6 +40,28 @@ type Config struct {
 	GoogleSecret string
 	// JWT
 	JWTSecret string
+
+	// Rules every new password must follow
+	PasswordPolicy models.PasswordPolicy
+	// Algorithm and parameters new password hashes are created with
+	PasswordHashing utils.HashParams
+
+	// Base64 Ed25519 key the rules audit checkpoints are signed with
+	AuditSigningKey string
+	// How often a checkpoint of the rules audit is signed
+	AuditCheckpointInterval time.Duration
+
+	// How often scheduled rules whose effective date has come are activated
+	RulesSchedulerInterval time.Duration
+	// Whether changes to the rules must be approved by a second admin
+	RulesFourEyes bool
+
+	// Provider emails are sent with and the address they come from
+	Mail mailer.Config
+	// How often the worker looks for due background jobs, like emails and push notifications
+	JobsWorkerInterval time.Duration
+	// How often an idle stream of user events sends a heartbeat, so proxies don't close it
+	StreamHeartbeat time.Duration
 }
 
 func getEnvOrDefault(key, defaultValue string) string {
@@ -42,8 +72,112 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return value
 }
 
-// LoadConfig loads environment variables a Config Struct containing relevant variables
-func LoadConfig() Config {
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvUintOrDefault reads an unsigned integer that fits in bitSize bits, failing if it's set to anything else
+func getEnvUintOrDefault(key string, defaultValue uint64, bitSize int) (uint64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseUint(value, 10, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number between 0 and %d", key, uint64(1)<<bitSize-1)
+	}
+	return parsed, nil
+}
+
+func getEnvBoolOrDefault(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// loadPasswordPolicy reads the password policy from the environment, falling back to the defaults
+func loadPasswordPolicy() models.PasswordPolicy {
+	policy := models.DefaultPasswordPolicy()
+	return models.PasswordPolicy{
+		MinLength:          getEnvIntOrDefault("PASSWORD_MIN_LENGTH", policy.MinLength),
+		MaxLength:          getEnvIntOrDefault("PASSWORD_MAX_LENGTH", policy.MaxLength),
+		RequireUppercase:   getEnvBoolOrDefault("PASSWORD_REQUIRE_UPPERCASE", policy.RequireUppercase),
+		RequireLowercase:   getEnvBoolOrDefault("PASSWORD_REQUIRE_LOWERCASE", policy.RequireLowercase),
+		RequireDigit:       getEnvBoolOrDefault("PASSWORD_REQUIRE_DIGIT", policy.RequireDigit),
+		RequireSymbol:      getEnvBoolOrDefault("PASSWORD_REQUIRE_SYMBOL", policy.RequireSymbol),
+		RejectPersonalInfo: getEnvBoolOrDefault("PASSWORD_REJECT_PERSONAL_INFO", policy.RejectPersonalInfo),
+		RejectCommon:       getEnvBoolOrDefault("PASSWORD_REJECT_COMMON", policy.RejectCommon),
+		HistorySize:        getEnvIntOrDefault("PASSWORD_HISTORY_SIZE", policy.HistorySize),
+	}
+}
+
+// loadHashParams reads the password hashing parameters from the environment, falling back to the defaults.
+// It fails if any of them can't be used to hash, instead of failing on the first login.
+func loadHashParams() (utils.HashParams, error) {
+	params := utils.DefaultHashParams()
+	params.Algorithm = getEnvOrDefault("PASSWORD_HASH_ALGORITHM", params.Algorithm)
+	params.BcryptCost = getEnvIntOrDefault("PASSWORD_BCRYPT_COST", params.BcryptCost)
+
+	memory, err := getEnvUintOrDefault("PASSWORD_ARGON2_MEMORY", uint64(params.Argon2Memory), 32)
+	if err != nil {
+		return params, err
+	}
+	iterations, err := getEnvUintOrDefault("PASSWORD_ARGON2_ITERATIONS", uint64(params.Argon2Iterations), 32)
+	if err != nil {
+		return params, err
+	}
+	parallelism, err := getEnvUintOrDefault("PASSWORD_ARGON2_PARALLELISM", uint64(params.Argon2Parallelism), 8)
+	if err != nil {
+		return params, err
+	}
+	params.Argon2Memory = uint32(memory)
+	params.Argon2Iterations = uint32(iterations)
+	params.Argon2Parallelism = uint8(parallelism)
+
+	if err := params.Validate(); err != nil {
+		return params, fmt.Errorf("invalid password hashing: %w", err)
+	}
+	return params, nil
+}
+
+// loadMailConfig reads how emails are sent from the environment. SendGrid is used unless another provider is set.
+// Providers that really send emails need MAIL_FROM, they reject senders that weren't verified with them.
+func loadMailConfig() (mailer.Config, error) {
+	provider := getEnvOrDefault("MAIL_PROVIDER", mailer.ProviderSendGrid)
+	fromEmail := os.Getenv("MAIL_FROM")
+	if fromEmail == "" {
+		if provider != mailer.ProviderFile {
+			return mailer.Config{}, fmt.Errorf("MAIL_FROM is required with the %s mail provider", provider)
+		}
+		fromEmail = "no-reply@localhost"
+	}
+
+	from := mailer.Address{
+		Name:  getEnvOrDefault("MAIL_FROM_NAME", "ClassConnect service"),
+		Email: fromEmail,
+	}
+	return mailer.Config{
+		Provider:       provider,
+		From:           from,
+		ReplyTo:        mailer.Address{Name: from.Name, Email: getEnvOrDefault("MAIL_REPLY_TO", from.Email)},
+		SendGridAPIKey: os.Getenv("EMAIL_API_KEY"),
+		SMTPHost:       os.Getenv("SMTP_HOST"),
+		SMTPPort:       getEnvIntOrDefault("SMTP_PORT", 587),
+		SMTPUsername:   os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:   os.Getenv("SMTP_PASSWORD"),
+		Directory:      getEnvOrDefault("MAIL_DIRECTORY", "mail"),
+	}, nil
+}
+
+// LoadConfig loads environment variables a Config Struct containing relevant variables.
+// It fails if a variable is set to a value the server can't run with.
+func LoadConfig() (Config, error) {
 	if err := godotenv.Load(); err != nil {
 		log.Debug(context.Background(), "No .env file found, loading environment variables from the system")
 	}
@@ -60,17 +194,46 @@ func LoadConfig() Config {
 		)
 	}
 
-	return Config{
-		Host:              getEnvOrDefault("HOST", "localhost"),
-		Port:              getEnvOrDefault("PORT", "8080"),
-		Environment:       getEnvOrDefault("ENVIRONMENT", "development"),
-		DatabaseURL:       dbUrl,
-		GoogleKey:         os.Getenv("GOOGLE_KEY"),
-		GoogleSecret:      os.Getenv("GOOGLE_SECRET"),
-		DatadogClientType: getEnvOrDefault("DD_CLIENT_TYPE", "default"),
-		DatadogHost:       getEnvOrDefault("DD_HOST", "localhost"),
-		DatadogStatsdPort: getEnvOrDefault("DD_STATSD_PORT", "8125"),
+	hashParams, err := loadHashParams()
+	if err != nil {
+		return Config{}, err
+	}
+	mail, err := loadMailConfig()
+	if err != nil {
+		return Config{}, err
+	}
+	passwordPolicy := loadPasswordPolicy()
+	if hashParams.Algorithm == utils.HashAlgorithmBcrypt &&
+		(passwordPolicy.MaxLength <= 0 || passwordPolicy.MaxLength > utils.BcryptMaxPasswordBytes) {
+		return Config{}, fmt.Errorf("PASSWORD_MAX_LENGTH must be between 1 and %d with bcrypt", utils.BcryptMaxPasswordBytes)
 	}
+
+	return Config{
+		Host:                    getEnvOrDefault("HOST", "localhost"),
+		Port:                    getEnvOrDefault("PORT", "8080"),
+		Environment:             getEnvOrDefault("ENVIRONMENT", "development"),
+		PublicURL:               strings.TrimSuffix(getEnvOrDefault("PUBLIC_URL", "http://localhost:8080"), "/"),
+		DatabaseURL:             dbUrl,
+		GoogleKey:               os.Getenv("GOOGLE_KEY"),
+		GoogleSecret:            os.Getenv("GOOGLE_SECRET"),
+		DatadogClientType:       getEnvOrDefault("DD_CLIENT_TYPE", "default"),
+		DatadogHost:             getEnvOrDefault("DD_HOST", "localhost"),
+		DatadogStatsdPort:       getEnvOrDefault("DD_STATSD_PORT", "8125"),
+		PasswordPolicy:          passwordPolicy,
+		PasswordHashing:         hashParams,
+		AuditSigningKey:         os.Getenv("AUDIT_SIGNING_KEY"),
+		AuditCheckpointInterval: time.Duration(getEnvIntOrDefault("AUDIT_CHECKPOINT_INTERVAL_MINUTES", 60)) * time.Minute,
+		RulesSchedulerInterval:  time.Duration(getEnvIntOrDefault("RULES_SCHEDULER_INTERVAL_SECONDS", 60)) * time.Second,
+		RulesFourEyes:           getEnvBoolOrDefault("RULES_FOUR_EYES", false),
+		Mail:                    mail,
+		JobsWorkerInterval:      time.Duration(getEnvIntOrDefault("JOBS_WORKER_INTERVAL_SECONDS", 5)) * time.Second,
+		StreamHeartbeat:         time.Duration(getEnvIntOrDefault("STREAM_HEARTBEAT_SECONDS", 15)) * time.Second,
+	}, nil
+}
+
+// IsDevelopment reports whether the server runs in a development environment
+func (config *Config) IsDevelopment() bool {
+	return config.Environment == "development"
 }
 
 func (config *Config) CreateDatabase() (*sql.DB, error) {
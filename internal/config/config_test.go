@@ -3,12 +3,26 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/mailer"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// loadConfig loads the config with the variables every deployment has to set
+func loadConfig(t *testing.T) Config {
+	if os.Getenv("MAIL_FROM") == "" {
+		t.Setenv("MAIL_FROM", "no-reply@classconnect.test")
+	}
+	config, err := LoadConfig()
+	require.NoError(t, err)
+	return config
+}
+
 func TestLoadConfig(t *testing.T) {
-	config := LoadConfig()
+	config := loadConfig(t)
 	assert.Equal(t, "localhost", config.Host)
 	assert.Equal(t, "8080", config.Port)
 	assert.Equal(t, "development", config.Environment)
@@ -35,7 +49,7 @@ func TestLoadConfig2(t *testing.T) {
 	if err != nil {
 		return
 	}
-	config := LoadConfig()
+	config := loadConfig(t)
 	assert.Equal(t, config.DatabaseURL, "a")
 }
 
@@ -44,6 +58,134 @@ func TestLoadConfig3(t *testing.T) {
 	if err != nil {
 		return
 	}
-	config := LoadConfig()
+	config := loadConfig(t)
 	assert.Equal(t, config.Host, "a")
 }
+
+func TestLoadConfig_PasswordPolicy(t *testing.T) {
+	t.Setenv("PASSWORD_MIN_LENGTH", "12")
+	t.Setenv("PASSWORD_REQUIRE_SYMBOL", "true")
+	t.Setenv("PASSWORD_HISTORY_SIZE", "not a number")
+
+	config := loadConfig(t)
+	assert.Equal(t, 12, config.PasswordPolicy.MinLength)
+	assert.True(t, config.PasswordPolicy.RequireSymbol)
+	assert.True(t, config.PasswordPolicy.RequireUppercase)
+	assert.Equal(t, 5, config.PasswordPolicy.HistorySize)
+}
+
+func TestLoadConfig_PasswordHashing(t *testing.T) {
+	t.Setenv("PASSWORD_ARGON2_MEMORY", "65536")
+
+	config := loadConfig(t)
+	assert.Equal(t, utils.HashAlgorithmArgon2id, config.PasswordHashing.Algorithm)
+	assert.Equal(t, uint32(65536), config.PasswordHashing.Argon2Memory)
+	assert.Equal(t, uint32(2), config.PasswordHashing.Argon2Iterations)
+
+	t.Setenv("PASSWORD_HASH_ALGORITHM", "bcrypt")
+	t.Setenv("PASSWORD_BCRYPT_COST", "12")
+
+	config = loadConfig(t)
+	assert.Equal(t, utils.HashAlgorithmBcrypt, config.PasswordHashing.Algorithm)
+	assert.Equal(t, 12, config.PasswordHashing.BcryptCost)
+}
+
+func TestLoadConfig_InvalidPasswordHashing(t *testing.T) {
+	t.Setenv("MAIL_FROM", "no-reply@classconnect.test")
+
+	for key, value := range map[string]string{
+		"PASSWORD_HASH_ALGORITHM":     "md5",
+		"PASSWORD_BCRYPT_COST":        "40",
+		"PASSWORD_ARGON2_MEMORY":      "-1",
+		"PASSWORD_ARGON2_ITERATIONS":  "0",
+		"PASSWORD_ARGON2_PARALLELISM": "256",
+	} {
+		t.Run(key, func(t *testing.T) {
+			if key == "PASSWORD_BCRYPT_COST" {
+				t.Setenv("PASSWORD_HASH_ALGORITHM", utils.HashAlgorithmBcrypt)
+			}
+			t.Setenv(key, value)
+
+			_, err := LoadConfig()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadConfig_BcryptMaxLength(t *testing.T) {
+	t.Setenv("MAIL_FROM", "no-reply@classconnect.test")
+	t.Setenv("PASSWORD_MAX_LENGTH", "128")
+
+	// Argon2id hashes passwords of any length
+	config, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 128, config.PasswordPolicy.MaxLength)
+
+	t.Setenv("PASSWORD_HASH_ALGORITHM", utils.HashAlgorithmBcrypt)
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_RulesFourEyes(t *testing.T) {
+	assert.False(t, loadConfig(t).RulesFourEyes)
+
+	t.Setenv("RULES_FOUR_EYES", "true")
+	assert.True(t, loadConfig(t).RulesFourEyes)
+}
+
+func TestLoadConfig_Mail(t *testing.T) {
+	config := loadConfig(t)
+	assert.Equal(t, mailer.ProviderSendGrid, config.Mail.Provider)
+	assert.Equal(t, "no-reply@classconnect.test", config.Mail.From.Email)
+	assert.Equal(t, config.Mail.From.Email, config.Mail.ReplyTo.Email)
+
+	t.Setenv("MAIL_PROVIDER", "smtp")
+	t.Setenv("MAIL_REPLY_TO", "support@classconnect.test")
+	t.Setenv("SMTP_HOST", "mailhog")
+	t.Setenv("SMTP_PORT", "1025")
+
+	config = loadConfig(t)
+	assert.Equal(t, mailer.ProviderSMTP, config.Mail.Provider)
+	assert.Equal(t, "no-reply@classconnect.test", config.Mail.From.Email)
+	assert.Equal(t, "support@classconnect.test", config.Mail.ReplyTo.Email)
+	assert.Equal(t, "mailhog", config.Mail.SMTPHost)
+	assert.Equal(t, 1025, config.Mail.SMTPPort)
+}
+
+func TestLoadConfig_MailFromRequired(t *testing.T) {
+	t.Setenv("MAIL_FROM", "")
+
+	// The providers would reject an unverified sender, every email would fail
+	for _, provider := range []string{"", mailer.ProviderSendGrid, mailer.ProviderSMTP} {
+		t.Setenv("MAIL_PROVIDER", provider)
+		_, err := LoadConfig()
+		assert.Error(t, err, provider)
+	}
+
+	// Files can come from anyone
+	t.Setenv("MAIL_PROVIDER", mailer.ProviderFile)
+	config, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "no-reply@localhost", config.Mail.From.Email)
+}
+
+func TestLoadConfig_JobsWorkerInterval(t *testing.T) {
+	assert.Equal(t, 5*time.Second, loadConfig(t).JobsWorkerInterval)
+
+	t.Setenv("JOBS_WORKER_INTERVAL_SECONDS", "0")
+	assert.Zero(t, loadConfig(t).JobsWorkerInterval)
+}
+
+func TestLoadConfig_StreamHeartbeat(t *testing.T) {
+	assert.Equal(t, 15*time.Second, loadConfig(t).StreamHeartbeat)
+
+	t.Setenv("STREAM_HEARTBEAT_SECONDS", "30")
+	assert.Equal(t, 30*time.Second, loadConfig(t).StreamHeartbeat)
+}
+
+func TestLoadConfig_PublicURL(t *testing.T) {
+	t.Setenv("PUBLIC_URL", "https://api.example.com/")
+
+	config := loadConfig(t)
+	assert.Equal(t, "https://api.example.com", config.PublicURL)
+}
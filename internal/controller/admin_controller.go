@@ -0,0 +1,316 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/go-core/pkg/log"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// MaxImportSize is the largest import file accepted, in bytes
+	MaxImportSize = 10 << 20
+	// AsyncImportThreshold is the number of rows above which imports always run as a job
+	AsyncImportThreshold = 200
+)
+
+// AdminController groups the endpoints that manage the user base as a whole
+type AdminController struct {
+	userService   services.UserService
+	importService services.UserImportService
+	bulkService   services.UserBulkService
+	statsService  services.StatsService
+}
+
+func NewAdminController(userService services.UserService, importService services.UserImportService, bulkService services.UserBulkService, statsService services.StatsService) *AdminController {
+	return &AdminController{userService: userService, importService: importService, bulkService: bulkService, statsService: statsService}
+}
+
+// ImportUsers godoc
+// @Summary      Import users
+// @Description  Creates users from a CSV file (with an email, name, surname and optional role and password header) or from JSON lines.
+// @Description  Every row is validated like a registration. Users without password get a random one and can be invited to choose theirs.
+// @Description  Files with more than 200 rows, or with async=true, are imported in the background and the response has the id of the job to poll
+// @Tags         Admin
+// @Accept       text/csv
+// @Accept       application/x-ndjson
+// @Produce      json
+// @Param        dry_run  query  bool    false  "Only validate the rows"
+// @Param        mode     query  string  false  "transactional (default) creates all rows or none, best_effort creates every valid row"  Enums(transactional, best_effort)
+// @Param        invite   query  bool    false  "Email created users a link to choose their password"
+// @Param        async    query  bool    false  "Run the import in the background"
+// @Success      200  {object}  map[string]models.UserImportReport  "Import report with the outcome of every row"
+// @Success      202  {object}  map[string]int                      "Id of the job running the import"
+// @Failure      400  {object}  utils.HTTPError  "Invalid options or malformed file"
+// @Failure      413  {object}  utils.HTTPError  "File too large"
+// @Failure      415  {object}  utils.HTTPError  "Unsupported content type"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Header       202  {string}  Location  "URL of the import job"
+// @Router       /admin/users/import [post]
+// @Security Bearer
+func (c AdminController) ImportUsers(ctx *gin.Context) {
+	var opts models.UserImportOptions
+	if err := ctx.ShouldBindQuery(&opts); err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, MaxImportSize)
+	var (
+		rows []models.UserImportRow
+		err  error
+	)
+	switch ctx.ContentType() {
+	case "text/csv":
+		rows, err = models.ParseUserImportCSV(body)
+	case "application/x-ndjson", "application/jsonl", "application/json":
+		rows, err = models.ParseUserImportJSONL(body)
+	default:
+		utils.ErrorResponse(ctx, http.StatusUnsupportedMediaType, "Content-Type must be text/csv or application/x-ndjson")
+		return
+	}
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.ErrorResponse(ctx, http.StatusRequestEntityTooLarge, fmt.Sprintf("The file can't be larger than %d bytes", MaxImportSize))
+			return
+		}
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if len(rows) == 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "The file has no users")
+		return
+	}
+
+	if opts.Async || len(rows) > AsyncImportThreshold {
+		claims, err := models.GetClaimsFromGinContext(ctx)
+		if err != nil {
+			utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		adminId, err := strconv.Atoi(claims.Subject)
+		if err != nil {
+			utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		jobId, err := c.importService.StartImportJob(ctx.Request.Context(), adminId, rows, opts)
+		if err != nil {
+			utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		models.AuditFromContext(ctx).Describe("user.import", "import_job", jobId)
+		ctx.Header("Location", fmt.Sprintf("/admin/users/import/%d", jobId))
+		ctx.JSON(http.StatusAccepted, gin.H{"data": gin.H{"job_id": jobId}})
+		return
+	}
+
+	models.AuditFromContext(ctx).Name("user.import")
+	report, err := c.importService.Import(ctx.Request.Context(), rows, opts)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	models.AuditFromContext(ctx).Change(nil, map[string]any{
+		"dry_run": report.DryRun, "total": report.Total, "created": report.Created, "failed": report.Failed,
+	})
+	ctx.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// GetImportJob godoc
+// @Summary      Get an import job
+// @Description  Returns the status of a background import and its report once it is done
+// @Tags         Admin
+// @Produce      json
+// @Param        id   path      int  true  "Job ID"
+// @Success      200  {object}  map[string]models.UserImportJob  "Import job"
+// @Failure      400  {object}  utils.HTTPError  "Invalid job ID format"
+// @Failure      404  {object}  utils.HTTPError  "Job not found"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /admin/users/import/{id} [get]
+// @Security Bearer
+func (c AdminController) GetImportJob(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid job ID format")
+		return
+	}
+
+	job, err := c.importService.GetImportJob(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			utils.ErrorResponse(ctx, http.StatusNotFound, "Job not found")
+			return
+		}
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": job})
+}
+
+// ExportUsers godoc
+// @Summary      Export users
+// @Description  Streams the users matching the same filters as the user listing as CSV, XLSX or JSON lines.
+// @Description  The columns and their order can be chosen, the password is never exported
+// @Tags         Admin
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce      application/x-ndjson
+// @Param        format        query  string  false  "Format of the file, csv by default"  Enums(csv, xlsx, jsonl)
+// @Param        columns       query  string  false  "Comma separated columns: id, name, surname, email, location, role, verified, blocked, profile_photo, description, created_at, updated_at. All by default"
+// @Param        role          query  string  false  "Only users with this role"  Enums(student, teacher, admin)
+// @Param        verified      query  bool    false  "Only verified or unverified users"
+// @Param        blocked       query  bool    false  "Only blocked or unblocked users"
+// @Param        q             query  string  false  "Text to search in the name, surname and email"
+// @Param        created_from  query  string  false  "Only users created on or after this day (YYYY-MM-DD)"
+// @Param        created_to    query  string  false  "Only users created on or before this day (YYYY-MM-DD)"
+// @Success      200  {file}    file             "Exported users"
+// @Failure      400  {object}  utils.HTTPError  "Invalid format, column or filter"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /admin/users/export [get]
+// @Security Bearer
+func (c AdminController) ExportUsers(ctx *gin.Context) {
+	var request models.UserExportRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if request.Format == "" {
+		request.Format = models.ExportFormatCSV
+	}
+	columns, err := models.ParseExportColumns(request.Columns)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	filename := fmt.Sprintf("users-%s.%s", time.Now().UTC().Format("20060102"), request.Format)
+	streamTable(ctx, request.Format, filename, columns, func(writer utils.TableWriter) error {
+		return c.userService.StreamUsers(ctx.Request.Context(), request.UserFilter, func(user models.User) error {
+			return writer.WriteRow(user.ExportValues(columns))
+		})
+	})
+}
+
+// streamTable sends the rows written by fill as a file download. The table writers buffer their
+// output, so errors before the first rows can still be reported with a proper error response.
+func streamTable(ctx *gin.Context, format string, filename string, header []string, fill func(utils.TableWriter) error) {
+	ctx.Header("Content-Type", utils.TableContentType(format))
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	writer, err := utils.NewTableWriter(format, ctx.Writer, header)
+	if err == nil {
+		err = fill(writer)
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		if !ctx.Writer.Written() {
+			ctx.Writer.Header().Del("Content-Type")
+			ctx.Writer.Header().Del("Content-Disposition")
+			utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		// Part of the file was already sent, all that can be done is cutting it short
+		log.Error(ctx.Request.Context(), "Error streaming table", "file", filename, "error", err.Error())
+		ctx.Abort()
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+// BulkUsers godoc
+// @Summary      Run an action on many users
+// @Description  Blocks, unblocks, changes the role of, verifies or soft deletes the users with the given ids, or the ones matching
+// @Description  a filter like the one of the user listing (dates in RFC 3339). Everything runs in one transaction and is recorded
+// @Description  as a single audit entry. Up to 1000 users can be changed at once and the admin running the action is always skipped
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      models.BulkUserActionRequest  true  "Users and action"
+// @Success      200      {object}  map[string]models.BulkUserActionReport  "Result for every user"
+// @Failure      400      {object}  utils.HTTPError  "Invalid request or too many users"
+// @Failure      500      {object}  utils.HTTPError  "Internal server error"
+// @Router       /admin/users/bulk [post]
+// @Security Bearer
+func (c AdminController) BulkUsers(ctx *gin.Context) {
+	var request models.BulkUserActionRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	claims, err := models.GetClaimsFromGinContext(ctx)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	adminId, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	models.AuditFromContext(ctx).Name("user.bulk." + request.Action)
+	report, err := c.bulkService.RunBulkAction(ctx.Request.Context(), adminId, request)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrBulkTargets), errors.Is(err, models.ErrBulkEmptyFilter),
+			errors.Is(err, models.ErrBulkTooManyUsers), errors.Is(err, models.ErrBulkMissingRole),
+			errors.Is(err, models.ErrBulkMissingReason):
+			utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		default:
+			utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	models.AuditFromContext(ctx).Change(nil, report)
+	ctx.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// GetStats godoc
+// @Summary      Get dashboard statistics
+// @Description  Aggregates of the user base: users by role and verification state, signups per day or week, logins,
+// @Description  blocked users, notification tokens by provider and AI chat usage. Activity is counted within the
+// @Description  range, the last 30 days by default. Results are cached for a minute
+// @Tags         Admin
+// @Produce      json
+// @Param        from      query  string  false  "First day of the range (YYYY-MM-DD)"
+// @Param        to        query  string  false  "Last day of the range (YYYY-MM-DD), today by default"
+// @Param        interval  query  string  false  "Size of the signup buckets, day by default"  Enums(day, week)
+// @Success      200  {object}  map[string]models.AdminStats  "Statistics"
+// @Failure      400  {object}  utils.HTTPError  "Invalid range or interval"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /admin/stats [get]
+// @Security Bearer
+func (c AdminController) GetStats(ctx *gin.Context) {
+	var request models.StatsRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	stats, err := c.statsService.GetStats(ctx.Request.Context(), request)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidStatsRange) {
+			utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+			return
+		}
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(services.StatsCacheTTL.Seconds())))
+	ctx.JSON(http.StatusOK, gin.H{"data": stats})
+}
@@ -0,0 +1,352 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/controller"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	s "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAdminTest(t *testing.T) (*s.MockUserService, *s.MockUserImportService, *gin.Context, *httptest.ResponseRecorder, *controller.AdminController) {
+	mockService, mockImportService, _, c, recorder, adminController := setupAdminBulkTest(t)
+	return mockService, mockImportService, c, recorder, adminController
+}
+
+func setupAdminBulkTest(t *testing.T) (*s.MockUserService, *s.MockUserImportService, *s.MockUserBulkService, *gin.Context, *httptest.ResponseRecorder, *controller.AdminController) {
+	gin.SetMode(gin.TestMode)
+	mockService := s.NewMockUserService(t)
+	mockImportService := s.NewMockUserImportService(t)
+	mockBulkService := s.NewMockUserBulkService(t)
+	mockStatsService := s.NewMockStatsService(t)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Set("claims", &models.Claims{StandardClaims: jwt.StandardClaims{Subject: "1"}, Role: "admin"})
+	return mockService, mockImportService, mockBulkService, c, recorder, controller.NewAdminController(mockService, mockImportService, mockBulkService, mockStatsService)
+}
+
+func TestAdminController_ImportUsers_CSV(t *testing.T) {
+	_, mockImportService, c, recorder, adminController := setupAdminTest(t)
+
+	body := "email,name,surname\nana@example.com,Ana,Lopez\n"
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/users/import?dry_run=true&mode=best_effort", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "text/csv; charset=utf-8")
+
+	expectedRows := []models.UserImportRow{{Line: 2, Email: "ana@example.com", Name: "Ana", Surname: "Lopez"}}
+	expectedOpts := models.UserImportOptions{DryRun: true, Mode: models.ImportModeBestEffort}
+	report := &models.UserImportReport{DryRun: true, Mode: models.ImportModeBestEffort, Total: 1, Valid: 1}
+	mockImportService.EXPECT().Import(mock.Anything, expectedRows, expectedOpts).Return(report, nil)
+
+	adminController.ImportUsers(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response struct {
+		Data models.UserImportReport `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, *report, response.Data)
+}
+
+func TestAdminController_ImportUsers_Async(t *testing.T) {
+	_, mockImportService, c, recorder, adminController := setupAdminTest(t)
+
+	body := `{"email":"ana@example.com","name":"Ana","surname":"Lopez"}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/users/import?async=true&invite=true", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/x-ndjson")
+
+	mockImportService.EXPECT().
+		StartImportJob(mock.Anything, 1, mock.Anything, models.UserImportOptions{Async: true, SendInvitations: true}).
+		Return(7, nil)
+
+	adminController.ImportUsers(c)
+
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	assert.Equal(t, "/admin/users/import/7", recorder.Header().Get("Location"))
+	assert.JSONEq(t, `{"data":{"job_id":7}}`, recorder.Body.String())
+}
+
+func TestAdminController_ImportUsers_LargeFilesRunAsJob(t *testing.T) {
+	_, mockImportService, c, recorder, adminController := setupAdminTest(t)
+
+	var body strings.Builder
+	body.WriteString("email,name,surname\n")
+	for i := 0; i <= controller.AsyncImportThreshold; i++ {
+		fmt.Fprintf(&body, "user%d@example.com,Name,Surname\n", i)
+	}
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/users/import", strings.NewReader(body.String()))
+	c.Request.Header.Set("Content-Type", "text/csv")
+
+	mockImportService.EXPECT().
+		StartImportJob(mock.Anything, 1, mock.MatchedBy(func(rows []models.UserImportRow) bool { return len(rows) == controller.AsyncImportThreshold+1 }), models.UserImportOptions{}).
+		Return(8, nil)
+
+	adminController.ImportUsers(c)
+
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+}
+
+func TestAdminController_ImportUsers_BadRequests(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		contentType  string
+		body         string
+		expectedCode int
+	}{
+		{name: "invalid mode", url: "/admin/users/import?mode=all", contentType: "text/csv", body: "email,name,surname\n", expectedCode: http.StatusBadRequest},
+		{name: "unsupported content type", url: "/admin/users/import", contentType: "application/xml", body: "<users/>", expectedCode: http.StatusUnsupportedMediaType},
+		{name: "missing column", url: "/admin/users/import", contentType: "text/csv", body: "email,name\na@b.com,Ana\n", expectedCode: http.StatusBadRequest},
+		{name: "empty file", url: "/admin/users/import", contentType: "text/csv", body: "email,name,surname\n", expectedCode: http.StatusBadRequest},
+		{name: "too large", url: "/admin/users/import", contentType: "text/csv", body: strings.Repeat(" ", controller.MaxImportSize+1), expectedCode: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, c, recorder, adminController := setupAdminTest(t)
+			c.Request = httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", tt.contentType)
+
+			adminController.ImportUsers(c)
+
+			assert.Equal(t, tt.expectedCode, recorder.Code)
+		})
+	}
+}
+
+func TestAdminController_ImportUsers_ServiceError(t *testing.T) {
+	_, mockImportService, c, recorder, adminController := setupAdminTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/users/import", strings.NewReader("email,name,surname\nana@example.com,Ana,Lopez\n"))
+	c.Request.Header.Set("Content-Type", "text/csv")
+
+	mockImportService.EXPECT().Import(mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+	adminController.ImportUsers(c)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func TestAdminController_GetImportJob(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		job          *models.UserImportJob
+		err          error
+		expectedCode int
+	}{
+		{name: "found", id: "3", job: &models.UserImportJob{Id: 3, Status: models.ImportJobRunning}, expectedCode: http.StatusOK},
+		{name: "invalid id", id: "abc", expectedCode: http.StatusBadRequest},
+		{name: "not found", id: "3", err: repositories.ErrNotFound, expectedCode: http.StatusNotFound},
+		{name: "internal error", id: "3", err: errors.New("db error"), expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mockImportService, c, recorder, adminController := setupAdminTest(t)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/users/import/"+tt.id, nil)
+
+			if tt.job != nil || tt.err != nil {
+				mockImportService.EXPECT().GetImportJob(mock.Anything, 3).Return(tt.job, tt.err)
+			}
+
+			adminController.GetImportJob(c)
+
+			assert.Equal(t, tt.expectedCode, recorder.Code)
+		})
+	}
+}
+
+func TestAdminController_ExportUsers_CSV(t *testing.T) {
+	mockService, _, c, recorder, adminController := setupAdminTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/users/export?role=teacher&blocked=false&columns=id,email,role", nil)
+
+	blocked := false
+	mockService.EXPECT().
+		StreamUsers(mock.Anything, models.UserFilter{Role: "teacher", Blocked: &blocked}, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ models.UserFilter, fn func(models.User) error) error {
+			require.NoError(t, fn(models.User{Id: 1, Email: "ana@example.com", Role: "teacher", Password: "hash"}))
+			return fn(models.User{Id: 2, Email: "juan@example.com", Role: "teacher", Password: "hash"})
+		})
+
+	adminController.ExportUsers(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Header().Get("Content-Disposition"), `attachment; filename="users-`)
+	assert.Equal(t, "id,email,role\n1,ana@example.com,teacher\n2,juan@example.com,teacher\n", recorder.Body.String())
+}
+
+func TestAdminController_ExportUsers_JSONL(t *testing.T) {
+	mockService, _, c, recorder, adminController := setupAdminTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/users/export?format=jsonl&columns=email,verified", nil)
+
+	mockService.EXPECT().
+		StreamUsers(mock.Anything, models.UserFilter{}, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ models.UserFilter, fn func(models.User) error) error {
+			return fn(models.User{Id: 1, Email: "ana@example.com", Verified: true})
+		})
+
+	adminController.ExportUsers(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/x-ndjson", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, `{"email":"ana@example.com","verified":true}`+"\n", recorder.Body.String())
+}
+
+func TestAdminController_ExportUsers_BadRequests(t *testing.T) {
+	for _, query := range []string{"format=pdf", "columns=email,password", "role=guest", "created_from=yesterday"} {
+		t.Run(query, func(t *testing.T) {
+			_, _, c, recorder, adminController := setupAdminTest(t)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/users/export?"+query, nil)
+
+			adminController.ExportUsers(c)
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
+
+func TestAdminController_ExportUsers_Error(t *testing.T) {
+	mockService, _, c, recorder, adminController := setupAdminTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/users/export?format=xlsx", nil)
+
+	mockService.EXPECT().StreamUsers(mock.Anything, models.UserFilter{}, mock.Anything).Return(errors.New("db error"))
+
+	adminController.ExportUsers(c)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Empty(t, recorder.Header().Get("Content-Disposition"))
+}
+
+func TestAdminController_BulkUsers(t *testing.T) {
+	_, _, mockBulkService, c, recorder, adminController := setupAdminBulkTest(t)
+
+	body := `{"ids":[2,3],"action":"block","reason":"cheating","duration_minutes":60}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/users/bulk", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	request := models.BulkUserActionRequest{Ids: []int{2, 3}, Action: models.BulkActionBlock, Reason: "cheating", DurationMinutes: 60}
+	report := &models.BulkUserActionReport{AuditId: 9, Action: models.BulkActionBlock, Total: 2, Updated: 1, Results: []models.BulkUserResult{
+		{Id: 2, Status: models.BulkResultUpdated},
+		{Id: 3, Status: models.BulkResultNotFound},
+	}}
+	mockBulkService.EXPECT().RunBulkAction(mock.Anything, 1, request).Return(report, nil)
+
+	adminController.BulkUsers(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response struct {
+		Data models.BulkUserActionReport `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, *report, response.Data)
+}
+
+func TestAdminController_BulkUsers_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		serviceErr   error
+		expectedCode int
+	}{
+		{name: "invalid json", body: `{"ids":`, expectedCode: http.StatusBadRequest},
+		{name: "unknown action", body: `{"ids":[2],"action":"promote"}`, expectedCode: http.StatusBadRequest},
+		{name: "invalid role", body: `{"ids":[2],"action":"change_role","role":"owner"}`, expectedCode: http.StatusBadRequest},
+		{name: "invalid filter", body: `{"filter":{"role":"owner"},"action":"verify"}`, expectedCode: http.StatusBadRequest},
+		{name: "invalid request", body: `{"action":"verify"}`, serviceErr: models.ErrBulkTargets, expectedCode: http.StatusBadRequest},
+		{name: "too many users", body: `{"filter":{"role":"student"},"action":"verify"}`, serviceErr: models.ErrBulkTooManyUsers, expectedCode: http.StatusBadRequest},
+		{name: "internal error", body: `{"ids":[2],"action":"verify"}`, serviceErr: errors.New("db error"), expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, mockBulkService, c, recorder, adminController := setupAdminBulkTest(t)
+			c.Request = httptest.NewRequest(http.MethodPost, "/admin/users/bulk", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			if tt.serviceErr != nil {
+				mockBulkService.EXPECT().RunBulkAction(mock.Anything, 1, mock.Anything).Return(nil, tt.serviceErr)
+			}
+
+			adminController.BulkUsers(c)
+
+			assert.Equal(t, tt.expectedCode, recorder.Code)
+		})
+	}
+}
+
+func setupAdminStatsTest(t *testing.T) (*s.MockStatsService, *gin.Context, *httptest.ResponseRecorder, *controller.AdminController) {
+	gin.SetMode(gin.TestMode)
+	mockStatsService := s.NewMockStatsService(t)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	adminController := controller.NewAdminController(s.NewMockUserService(t), s.NewMockUserImportService(t), s.NewMockUserBulkService(t), mockStatsService)
+	return mockStatsService, c, recorder, adminController
+}
+
+func TestAdminController_GetStats(t *testing.T) {
+	mockStatsService, c, recorder, adminController := setupAdminStatsTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/stats?from=2025-03-01&to=2025-03-31&interval=week", nil)
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	stats := &models.AdminStats{From: from, To: to, Interval: models.StatsIntervalWeek, Users: models.UserStats{Total: 3}}
+	mockStatsService.EXPECT().
+		GetStats(mock.Anything, models.StatsRequest{From: &from, To: &to, Interval: models.StatsIntervalWeek}).
+		Return(stats, nil)
+
+	adminController.GetStats(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "private, max-age=60", recorder.Header().Get("Cache-Control"))
+	var response struct {
+		Data models.AdminStats `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, 3, response.Data.Users.Total)
+}
+
+func TestAdminController_GetStats_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		serviceErr   error
+		expectedCode int
+	}{
+		{name: "invalid interval", query: "interval=month", expectedCode: http.StatusBadRequest},
+		{name: "invalid date", query: "from=03/01/2025", expectedCode: http.StatusBadRequest},
+		{name: "invalid range", query: "from=2025-03-01&to=2025-02-01", serviceErr: models.ErrInvalidStatsRange, expectedCode: http.StatusBadRequest},
+		{name: "internal error", query: "", serviceErr: errors.New("db error"), expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStatsService, c, recorder, adminController := setupAdminStatsTest(t)
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/stats?"+tt.query, nil)
+
+			if tt.serviceErr != nil {
+				mockStatsService.EXPECT().GetStats(mock.Anything, mock.Anything).Return(nil, tt.serviceErr)
+			}
+
+			adminController.GetStats(c)
+
+			assert.Equal(t, tt.expectedCode, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditController struct {
+	auditService services.AuditService
+	chainService services.AuditChainService
+}
+
+func NewAuditController(auditService services.AuditService, chainService services.AuditChainService) *AuditController {
+	return &AuditController{auditService: auditService, chainService: chainService}
+}
+
+// GetAuditEvents godoc
+// @Summary      Get the audit log
+// @Description  Lists the privileged actions, newest first, a page at a time. With format=csv every matching event is exported, oldest first
+// @Tags         Admin
+// @Produce      json
+// @Produce      text/csv
+// @Param        actor_id     query  int     false  "Only actions of this admin"
+// @Param        action       query  string  false  "Only this action, like user.block"
+// @Param        target_type  query  string  false  "Only actions on this kind of resource, like user or rule"
+// @Param        target_id    query  string  false  "Only actions on this resource"
+// @Param        from         query  string  false  "Only actions on or after this day (YYYY-MM-DD)"
+// @Param        to           query  string  false  "Only actions on or before this day (YYYY-MM-DD)"
+// @Param        page         query  int     false  "Page number, starting at 1"
+// @Param        page_size    query  int     false  "Events per page, 50 by default and 500 at most"
+// @Param        format       query  string  false  "json by default"  Enums(json, csv)
+// @Success      200  {object}  map[string]interface{}  "Events in data and the page in pagination"
+// @Failure      400  {object}  utils.HTTPError  "Invalid filter"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /admin/audit [get]
+// @Security Bearer
+func (c AuditController) GetAuditEvents(ctx *gin.Context) {
+	var request models.AuditListRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if request.Format == "csv" {
+		c.exportAuditEvents(ctx, request.AuditFilter)
+		return
+	}
+
+	events, page, err := c.auditService.ListEvents(ctx.Request.Context(), request)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": events, "pagination": page})
+}
+
+func (c AuditController) exportAuditEvents(ctx *gin.Context, filter models.AuditFilter) {
+	filename := fmt.Sprintf("audit-%s.csv", time.Now().UTC().Format("20060102"))
+	streamTable(ctx, "csv", filename, models.AuditExportColumns, func(writer utils.TableWriter) error {
+		return c.auditService.StreamEvents(ctx.Request.Context(), filter, func(event models.AuditEvent) error {
+			return writer.WriteRow(event.ExportValues())
+		})
+	})
+}
+
+// GetAuditCheckpoints godoc
+// @Summary      Get the signed checkpoints of the rules audit
+// @Description  Lists the latest signed snapshots of the head of the rules audit hash chain, newest first, along with the key they are verified with. Keep copies outside the system: removing or editing audit entries breaks them
+// @Tags         Admin
+// @Produce      json
+// @Param        limit  query  int  false  "Checkpoints to return, 50 by default and 500 at most"
+// @Success      200  {object}  map[string]interface{}  "Checkpoints in data and the base64 Ed25519 key in public_key"
+// @Failure      400  {object}  utils.HTTPError  "Invalid limit"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /admin/audit/checkpoints [get]
+// @Security Bearer
+func (c AuditController) GetAuditCheckpoints(ctx *gin.Context) {
+	limit := models.AuditDefaultPageSize
+	if value := ctx.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > models.AuditMaxPageSize {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", models.AuditMaxPageSize))
+			return
+		}
+		limit = parsed
+	}
+
+	checkpoints, err := c.chainService.GetCheckpoints(ctx.Request.Context(), limit)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": checkpoints, "public_key": c.chainService.PublicKey()})
+}
@@ -0,0 +1,124 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/controller"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	s "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAuditTest(t *testing.T) (*s.MockAuditService, *gin.Context, *httptest.ResponseRecorder, *controller.AuditController) {
+	mockService, _, c, recorder, auditController := setupAuditChainTest(t)
+	return mockService, c, recorder, auditController
+}
+
+func setupAuditChainTest(t *testing.T) (*s.MockAuditService, *s.MockAuditChainService, *gin.Context, *httptest.ResponseRecorder, *controller.AuditController) {
+	gin.SetMode(gin.TestMode)
+	mockService := s.NewMockAuditService(t)
+	mockChainService := s.NewMockAuditChainService(t)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	return mockService, mockChainService, c, recorder, controller.NewAuditController(mockService, mockChainService)
+}
+
+func TestAuditController_GetAuditEvents(t *testing.T) {
+	mockService, c, recorder, auditController := setupAuditTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/audit?action=user.block&page=2&page_size=10", nil)
+
+	expectedRequest := models.AuditListRequest{AuditFilter: models.AuditFilter{Action: "user.block"}, Page: 2, PageSize: 10}
+	events := []models.AuditEvent{{Id: 11, Action: "user.block", TargetType: "user", TargetId: "3", StatusCode: 200}}
+	mockService.EXPECT().ListEvents(mock.Anything, expectedRequest).
+		Return(events, models.Pagination{Page: 2, PageSize: 10, Total: 11}, nil)
+
+	auditController.GetAuditEvents(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response struct {
+		Data       []models.AuditEvent `json:"data"`
+		Pagination models.Pagination   `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, events[0].Id, response.Data[0].Id)
+	assert.Equal(t, 11, response.Pagination.Total)
+}
+
+func TestAuditController_GetAuditEvents_CSV(t *testing.T) {
+	mockService, c, recorder, auditController := setupAuditTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/audit?format=csv&target_type=rule", nil)
+
+	createdAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	mockService.EXPECT().StreamEvents(mock.Anything, models.AuditFilter{TargetType: "rule"}, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ models.AuditFilter, fn func(models.AuditEvent) error) error {
+			return fn(models.AuditEvent{Id: 1, Action: "rule.delete", TargetType: "rule", TargetId: "4", StatusCode: 204, CreatedAt: createdAt})
+		})
+
+	auditController.GetAuditEvents(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("Content-Disposition"), "audit-")
+	lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[1], "rule.delete")
+}
+
+func TestAuditController_GetAuditEvents_InvalidFilter(t *testing.T) {
+	_, c, recorder, auditController := setupAuditTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/audit?page_size=1000", nil)
+
+	auditController.GetAuditEvents(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestAuditController_GetAuditEvents_Error(t *testing.T) {
+	mockService, c, recorder, auditController := setupAuditTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/audit", nil)
+
+	mockService.EXPECT().ListEvents(mock.Anything, models.AuditListRequest{}).
+		Return(nil, models.Pagination{}, errors.New("db down"))
+
+	auditController.GetAuditEvents(c)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func TestAuditController_GetAuditCheckpoints(t *testing.T) {
+	_, mockChainService, c, recorder, auditController := setupAuditChainTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/audit/checkpoints?limit=2", nil)
+
+	checkpoints := []models.AuditCheckpoint{{Id: 4, LastAuditId: 90, HeadHash: "ab", Entries: 90, PublicKey: "key", Signature: "sig"}}
+	mockChainService.EXPECT().GetCheckpoints(mock.Anything, 2).Return(checkpoints, nil)
+	mockChainService.EXPECT().PublicKey().Return("key")
+
+	auditController.GetAuditCheckpoints(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response struct {
+		Data      []models.AuditCheckpoint `json:"data"`
+		PublicKey string                   `json:"public_key"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, checkpoints, response.Data)
+	assert.Equal(t, "key", response.PublicKey)
+}
+
+func TestAuditController_GetAuditCheckpoints_InvalidLimit(t *testing.T) {
+	_, _, c, recorder, auditController := setupAuditChainTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/audit/checkpoints?limit=0", nil)
+
+	auditController.GetAuditCheckpoints(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Ingenieria-de-Software-2-Gupo-14/go-core/pkg/log"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
@@ -19,13 +20,15 @@ type AuthController struct {
 	userRepo             services.UserService
 	loginAttemptsService services.LoginAttemptService
 	verificationService  services.VerificationService
+	passwordService      services.PasswordService
 }
 
-func NewAuthController(userRepo services.UserService, loginAttemptsService services.LoginAttemptService, verificationService services.VerificationService) *AuthController {
+func NewAuthController(userRepo services.UserService, loginAttemptsService services.LoginAttemptService, verificationService services.VerificationService, passwordService services.PasswordService) *AuthController {
 	return &AuthController{
 		userRepo:             userRepo,
 		loginAttemptsService: loginAttemptsService,
 		verificationService:  verificationService,
+		passwordService:      passwordService,
 	}
 }
 
@@ -36,7 +39,7 @@ func NewAuthController(userRepo services.UserService, loginAttemptsService servi
 // @Produce      plain
 // @Param        request body models.CreateUserRequest true "User Registration Details"
 // @Success      201  {object}  map[string]int  "User created successfully"
-// @Failure      400  {object}  utils.HTTPError "Invalid request format"
+// @Failure      400  {object}  utils.HTTPError "Invalid request format or password rejected by the password policy"
 // @Failure      409  {object}  utils.HTTPError "Email already exists"
 // @Failure      500  {object}  utils.HTTPError "Internal server error"
 // @Router       /auth/users [post]
@@ -48,9 +51,14 @@ func (ac *AuthController) Register(c *gin.Context) {
 		return
 	}
 
+	if err := ac.passwordService.CheckPolicy(request.Password, []string{request.Name, request.Surname, request.Email}); err != nil {
+		utils.ErrorResponseWithErr(c, http.StatusBadRequest, err)
+		return
+	}
+
 	if user, err := ac.userRepo.GetUserByEmail(ctx, request.Email); err == nil {
 		if !user.Verified {
-			err := ac.userRepo.DeleteUser(ctx, user.Id)
+			err := ac.userRepo.DeleteUser(ctx, user.Id, "*")
 			if err != nil {
 				utils.ErrorResponseWithErr(c, http.StatusInternalServerError, err)
 				return
@@ -61,17 +69,12 @@ func (ac *AuthController) Register(c *gin.Context) {
 		}
 	}
 
-	id, err := ac.userRepo.CreateUser(ctx, request)
+	id, err := ac.verificationService.RegisterUser(ctx, request)
 	if err != nil {
 		utils.ErrorResponseWithErr(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	if err := ac.verificationService.SendVerificationEmail(ctx, id, request.Email); err != nil {
-		utils.ErrorResponseWithErr(c, http.StatusInternalServerError, err)
-		return
-	}
-
 	c.JSON(http.StatusCreated, gin.H{"id": id})
 }
 
@@ -82,7 +85,7 @@ func (ac *AuthController) Register(c *gin.Context) {
 // @Produce      plain
 // @Param        request body models.CreateUserRequest true "User Registration Details"
 // @Success      201  {object}  map[string]int  "User created successfully"
-// @Failure      400  {object}  utils.HTTPError "Invalid request format"
+// @Failure      400  {object}  utils.HTTPError "Invalid request format or password rejected by the password policy"
 // @Failure      409  {object}  utils.HTTPError "Email already exists"
 // @Failure      500  {object}  utils.HTTPError "Internal server error"
 // @Router       /auth/admins [post]
@@ -95,6 +98,11 @@ func (ac *AuthController) RegisterAdmin(c *gin.Context) {
 		return
 	}
 
+	if err := ac.passwordService.CheckPolicy(request.Password, []string{request.Name, request.Surname, request.Email}); err != nil {
+		utils.ErrorResponseWithErr(c, http.StatusBadRequest, err)
+		return
+	}
+
 	if _, err := ac.userRepo.GetUserByEmail(ctx, request.Email); err == nil {
 		utils.ErrorResponse(c, http.StatusConflict, "Email already exists")
 		return
@@ -108,6 +116,8 @@ func (ac *AuthController) RegisterAdmin(c *gin.Context) {
 		utils.ErrorResponseWithErr(c, http.StatusInternalServerError, err)
 		return
 	}
+	models.AuditFromContext(c).Describe("admin.create", "user", id)
+	models.AuditFromContext(c).Change(nil, map[string]any{"email": request.Email, "role": request.Role})
 
 	c.JSON(http.StatusCreated, gin.H{"id": id})
 }
@@ -150,7 +160,6 @@ func (ac *AuthController) VerifyRegistration(c *gin.Context) {
 		utils.ErrorResponseWithErr(c, http.StatusGone, err)
 		return
 	}
-	println(verification.VerificationPin)
 	if verification.VerificationPin != parts[1] {
 		utils.ErrorResponseWithErr(c, http.StatusBadRequest, err)
 		return
@@ -235,6 +244,11 @@ func (ac *AuthController) Login(c *gin.Context) {
 		return
 	}
 
+	// Upgrade hashes made with an older algorithm or parameters while we have the plain password
+	if err := ac.userRepo.RehashPassword(c.Request.Context(), *user, request.Password); err != nil {
+		log.Warn(c.Request.Context(), "Error upgrading password hash", "user_id", user.Id, "error", err.Error())
+	}
+
 	ac.finishAuth(c, *user)
 }
 
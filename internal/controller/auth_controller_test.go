@@ -12,10 +12,10 @@ import (
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/sendgrid/rest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -28,7 +28,9 @@ func TestNewAuthController(t *testing.T) {
 	mockLoginAttemptService := services.NewMockLoginAttemptService(t)
 	mockVerificationService := services.NewMockVerificationService(t)
 
-	controller := NewAuthController(mockUserService, mockLoginAttemptService, mockVerificationService)
+	mockPasswordService := services.NewMockPasswordService(t)
+
+	controller := NewAuthController(mockUserService, mockLoginAttemptService, mockVerificationService, mockPasswordService)
 	assert.NotNil(t, controller)
 }
 
@@ -37,30 +39,32 @@ func setupTestAuth(t *testing.T) (*services.MockUserService, *services.MockLogin
 	mockUserService := services.NewMockUserService(t)
 	mockLoginAttemptService := services.NewMockLoginAttemptService(t)
 	mockVerificationService := services.NewMockVerificationService(t)
+	mockPasswordService := services.NewMockPasswordService(t)
+	mockPasswordService.EXPECT().CheckPolicy(mock.Anything, mock.Anything).Return(nil).Maybe()
 
-	controller := NewAuthController(mockUserService, mockLoginAttemptService, mockVerificationService)
+	controller := NewAuthController(mockUserService, mockLoginAttemptService, mockVerificationService, mockPasswordService)
 	recorder := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(recorder)
 	return mockUserService, mockLoginAttemptService, mockVerificationService, c, recorder, controller
 }
 
-func setupIntegrationTestAuth(db *sql.DB, t *testing.T) (*gin.Context, *httptest.ResponseRecorder, *AuthController, *services.MockEmailSender) {
+func setupIntegrationTestAuth(db *sql.DB, t *testing.T) (*gin.Context, *httptest.ResponseRecorder, *AuthController) {
 	gin.SetMode(gin.TestMode)
-	email := services.NewMockEmailSender(t)
 	repoBlocked := repo.NewBlockedUserRepository(db)
-	userService := services.NewUserService(repo.CreateUserRepo(db), repoBlocked, email)
+	userService := services.NewUserService(repo.CreateUserRepo(db), repoBlocked, repo.NewJobRepository(db), repo.NewInboxRepository(db), "http://localhost:8080", utils.DefaultHashParams())
 	loginAttemptService := services.NewLoginAttemptService(repo.NewLoginAttemptRepository(db), repoBlocked)
-	verificationService := services.NewVerificationService(repo.CreateVerificationRepo(db), email)
+	verificationService := services.NewVerificationService(repo.CreateVerificationRepo(db), utils.DefaultHashParams())
+	passwordService := services.NewPasswordService(repo.CreateUserRepo(db), models.DefaultPasswordPolicy(), utils.DefaultHashParams())
 
-	controller := NewAuthController(userService, loginAttemptService, verificationService)
+	controller := NewAuthController(userService, loginAttemptService, verificationService, passwordService)
 	recorder := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(recorder)
-	return c, recorder, controller, email
+	return c, recorder, controller
 }
 
 func TestLogin_IntegrationTest(t *testing.T) {
 	db, mock, _ := sqlmock.New()
-	c, recorder, controller, _ := setupIntegrationTestAuth(db, t)
+	c, recorder, controller := setupIntegrationTestAuth(db, t)
 
 	password := "testsPassword"
 	hashedPassword, err := utils.HashPassword(password)
@@ -93,9 +97,9 @@ func TestLogin_IntegrationTest(t *testing.T) {
 		WithArgs(user.Email).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "name", "surname", "password", "email", "location", "role", "verified",
-			"profile_photo", "description", "created_at", "updated_at", "blocked"}).
+			"profile_photo", "description", "created_at", "updated_at", "version", "blocked"}).
 			AddRow(user.Id, user.Name, user.Name, user.Password, user.Email, user.Location, user.Role, user.Verified,
-				user.ProfilePhoto, user.Description, user.CreatedAt, user.UpdatedAt, user.Blocked))
+				user.ProfilePhoto, user.Description, user.CreatedAt, user.UpdatedAt, user.Version, user.Blocked))
 
 	mock.ExpectExec(`INSERT INTO login_attempts \(user_id, ip_address, user_agent, successful, created_at\) VALUES \(\$1, \$2, \$3, \$4, \$5\)`).
 		WithArgs(user.Id, "127.0.0.1", "Mozilla/5.0", true, sqlmock.AnyArg()).
@@ -113,7 +117,7 @@ func TestLogin_IntegrationTest(t *testing.T) {
 
 func TestResendPin_IntegrationTest(t *testing.T) {
 	db, mockDb, _ := sqlmock.New()
-	c, recorder, controller, mockEmail := setupIntegrationTestAuth(db, t)
+	c, recorder, controller := setupIntegrationTestAuth(db, t)
 
 	password := "testsPassword"
 	hashedPassword, err := utils.HashPassword(password)
@@ -136,9 +140,9 @@ func TestResendPin_IntegrationTest(t *testing.T) {
 		WithArgs(user.Email).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "name", "surname", "password", "email", "location", "role", "verified",
-			"profile_photo", "description", "created_at", "updated_at", "blocked"}).
+			"profile_photo", "description", "created_at", "updated_at", "version", "blocked"}).
 			AddRow(user.Id, user.Name, user.Name, user.Password, user.Email, user.Location, user.Role, user.Verified,
-				user.ProfilePhoto, user.Description, user.CreatedAt, user.UpdatedAt, user.Blocked))
+				user.ProfilePhoto, user.Description, user.CreatedAt, user.UpdatedAt, user.Version, user.Blocked))
 
 	now := time.Now()
 	expected := &models.UserVerification{
@@ -164,12 +168,14 @@ func TestResendPin_IntegrationTest(t *testing.T) {
 			expected.CreatedAt,
 		))
 
+	mockDb.ExpectBegin()
 	mockDb.ExpectExec(`UPDATE verification SET verification_pin = \$2, pin_expiration = \$3 WHERE id = \$1`).
 		WithArgs(expected.Id, sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(0, 1))
-
-	mockEmail.On("Send", mock.AnythingOfType("*mail.SGMailV3")).
-		Return(&rest.Response{StatusCode: 202}, nil)
+	mockDb.ExpectExec(`INSERT INTO jobs`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), `{t}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.ExpectCommit()
 
 	controller.ResendPin(c)
 
@@ -203,16 +209,10 @@ func TestRegister(t *testing.T) {
 		Return(nil, repo.ErrNotFound).
 		Once()
 
-	mockUserService.
-		EXPECT().
-		CreateUser(ctx, request).
-		Return(2, nil).
-		Once()
-
 	mockVerificationService.
 		EXPECT().
-		SendVerificationEmail(ctx, 2, request.Email).
-		Return(nil).
+		RegisterUser(ctx, request).
+		Return(2, nil).
 		Once()
 
 	controller.Register(c)
@@ -474,6 +474,12 @@ func TestLogin(t *testing.T) {
 		Return(user, nil).
 		Once()
 
+	mockUserService.
+		EXPECT().
+		RehashPassword(ctx, *user, password).
+		Return(nil).
+		Once()
+
 	mockLoginService.
 		EXPECT().
 		AddLoginAttempt(c, user.Id, "127.0.0.1", "Mozilla/5.0", true).
@@ -490,6 +496,40 @@ func TestLogin(t *testing.T) {
 	assert.NotEmpty(t, response["token"])
 }
 
+func TestLogin_RehashesLegacyPassword(t *testing.T) {
+	mockUserService, mockLoginService, _, c, recorder, controller := setupTestAuth(t)
+
+	password := "testsPassword"
+	legacyHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	require.NoError(t, err)
+
+	user := &models.User{
+		Id:       1,
+		Email:    "test@example.com",
+		Password: string(legacyHash),
+		Role:     "user",
+		Verified: true,
+	}
+
+	jsonBody, _ := json.Marshal(models.LoginRequest{Email: user.Email, Password: password})
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "127.0.0.1"
+	req.Header.Set("User-Agent", "Mozilla/5.0")
+	c.Request = req
+
+	ctx := req.Context()
+
+	mockUserService.EXPECT().GetUserByEmail(ctx, user.Email).Return(user, nil).Once()
+	mockUserService.EXPECT().RehashPassword(ctx, *user, password).Return(errors.New("db error")).Once()
+	mockLoginService.EXPECT().AddLoginAttempt(c, user.Id, "127.0.0.1", "Mozilla/5.0", true).Return(nil).Once()
+
+	controller.Login(c)
+
+	// A failed upgrade must not prevent the login
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
 func TestLogin_BadRequest(t *testing.T) {
 	_, _, _, c, recorder, controller := setupTestAuth(t)
 
@@ -762,3 +802,33 @@ func TestResendPin_UpdatePinError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "send error")
 }
+
+func TestRegister_PasswordPolicyRejected(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockUserService := services.NewMockUserService(t)
+	mockPasswordService := services.NewMockPasswordService(t)
+	controller := NewAuthController(mockUserService, services.NewMockLoginAttemptService(t), services.NewMockVerificationService(t), mockPasswordService)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+
+	request := models.CreateUserRequest{
+		Email:    "test@test.com",
+		Password: "password",
+		Name:     "test",
+		Surname:  "test",
+		Role:     "student",
+	}
+
+	jsonBody, _ := json.Marshal(request)
+	c.Request = httptest.NewRequest(http.MethodPost, "/users", bytes.NewBuffer(jsonBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	mockPasswordService.EXPECT().
+		CheckPolicy(request.Password, []string{request.Name, request.Surname, request.Email}).
+		Return(models.ValidationErrors{{Field: "password", Message: "is too common"}})
+
+	controller.Register(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "is too common")
+}
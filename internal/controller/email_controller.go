@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/mailer"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// EmailController lets admins see the emails the platform sends
+type EmailController struct{}
+
+func NewEmailController() *EmailController {
+	return &EmailController{}
+}
+
+// GetEmailTemplates godoc
+// @Summary      List the email templates
+// @Description  Lists the names of the emails the platform sends and the locales they are written in, the default one first
+// @Tags         Admin
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "Names in data.templates and locales in data.locales"
+// @Router       /admin/emails [get]
+// @Security Bearer
+func (c EmailController) GetEmailTemplates(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"data": gin.H{"templates": mailer.Templates(), "locales": mailer.Locales()}})
+}
+
+// PreviewEmail godoc
+// @Summary      Preview an email
+// @Description  Renders the email template with sample data. Without a locale it is rendered in the first one of Accept-Language there is a variant for, or in English
+// @Tags         Admin
+// @Produce      json
+// @Produce      html
+// @Produce      plain
+// @Param        name    path   string  true   "Template name"
+// @Param        locale  query  string  false  "Locale to render, like es or en"
+// @Param        format  query  string  false  "json with the subject, text and html by default"  Enums(json, html, text)
+// @Success      200  {object}  map[string]interface{}  "Rendered email in data"
+// @Failure      400  {object}  utils.HTTPError  "Invalid format"
+// @Failure      404  {object}  utils.HTTPError  "Unknown template"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /admin/emails/{name}/preview [get]
+// @Security Bearer
+func (c EmailController) PreviewEmail(ctx *gin.Context) {
+	name := ctx.Param("name")
+	data, err := mailer.SampleData(name)
+	if errors.Is(err, mailer.ErrUnknownTemplate) {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Email template not found")
+		return
+	}
+
+	locales := mailer.LocalesFromContext(ctx.Request.Context())
+	if locale := ctx.Query("locale"); locale != "" {
+		locales = []string{locale}
+	}
+	email, err := mailer.Render(name, locales, data)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Header("Content-Language", email.Locale)
+	switch ctx.DefaultQuery("format", "json") {
+	case "json":
+		ctx.JSON(http.StatusOK, gin.H{"data": email})
+	case "html":
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(email.HTML))
+	case "text":
+		ctx.String(http.StatusOK, "Subject: %s\n\n%s", email.Subject, email.Text)
+	default:
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "format must be json, html or text")
+	}
+}
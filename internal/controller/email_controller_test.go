@@ -0,0 +1,85 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/controller"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/mailer"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveEmailPreview(t *testing.T, target string, acceptLanguage string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	c.Request = c.Request.WithContext(mailer.WithLocales(c.Request.Context(), []string{acceptLanguage}))
+	c.Params = gin.Params{{Key: "name", Value: mailer.TemplatePasswordReset}}
+	controller.NewEmailController().PreviewEmail(c)
+	return recorder
+}
+
+func TestEmailController_GetEmailTemplates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/emails", nil)
+
+	controller.NewEmailController().GetEmailTemplates(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response struct {
+		Data struct {
+			Templates []string `json:"templates"`
+			Locales   []string `json:"locales"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Contains(t, response.Data.Templates, mailer.TemplatePasswordReset)
+	assert.Equal(t, []string{"en", "es"}, response.Data.Locales)
+}
+
+func TestEmailController_PreviewEmail(t *testing.T) {
+	recorder := serveEmailPreview(t, "/admin/emails/password_reset/preview", "en")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "en", recorder.Header().Get("Content-Language"))
+	var response struct {
+		Data mailer.Email `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, "Reset your password", response.Data.Subject)
+	assert.Contains(t, response.Data.HTML, "<!DOCTYPE html>")
+	assert.Contains(t, response.Data.Text, "Your reset token is 9-X7Y8Z9")
+}
+
+func TestEmailController_PreviewEmail_HTMLInLocale(t *testing.T) {
+	recorder := serveEmailPreview(t, "/admin/emails/password_reset/preview?locale=es&format=html", "en")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/html; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), `<html lang="es">`)
+}
+
+func TestEmailController_PreviewEmail_InvalidFormat(t *testing.T) {
+	recorder := serveEmailPreview(t, "/admin/emails/password_reset/preview?format=pdf", "")
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestEmailController_PreviewEmail_UnknownTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/emails/newsletter/preview", nil)
+	c.Params = gin.Params{{Key: "name", Value: "newsletter"}}
+
+	controller.NewEmailController().PreviewEmail(c)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// InboxController serves the notifications users were sent
+type InboxController struct {
+	inboxService services.InboxService
+}
+
+func NewInboxController(inboxService services.InboxService) *InboxController {
+	return &InboxController{inboxService: inboxService}
+}
+
+// GetInbox godoc
+// @Summary      Get the inbox of a user
+// @Description  Lists the notifications sent to the user, newest first, along with how many are unread
+// @Tags         Users
+// @Produce      json
+// @Param        id         path   int   true   "User ID"
+// @Param        page       query  int   false  "Page, starting at 1"
+// @Param        page_size  query  int   false  "Entries per page, 50 by default and 500 at most"
+// @Param        unread     query  bool  false  "Only list the unread entries"
+// @Success      200  {object}  models.Inbox  "Entries in data, with the pagination and the unread count"
+// @Failure      400  {object}  utils.HTTPError  "Invalid request"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /users/{id}/inbox [get]
+// @Security Bearer
+func (c InboxController) GetInbox(ctx *gin.Context) {
+	userId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+	var request models.InboxListRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	inbox, err := c.inboxService.GetInbox(ctx.Request.Context(), userId, request)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, inbox)
+}
+
+// GetInboxEntry godoc
+// @Summary      Get a notification of the inbox
+// @Description  Returns a notification sent to the user, push notifications and emails link to it
+// @Tags         Users
+// @Produce      json
+// @Param        id        path  int  true  "User ID"
+// @Param        entry_id  path  int  true  "Inbox entry ID"
+// @Success      200  {object}  map[string]interface{}  "Entry in data"
+// @Failure      400  {object}  utils.HTTPError  "Invalid ID"
+// @Failure      404  {object}  utils.HTTPError  "Entry not found"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /users/{id}/inbox/{entry_id} [get]
+// @Security Bearer
+func (c InboxController) GetInboxEntry(ctx *gin.Context) {
+	userId, id, ok := inboxEntryIds(ctx)
+	if !ok {
+		return
+	}
+	entry, err := c.inboxService.GetInboxEntry(ctx.Request.Context(), userId, id)
+	if err != nil {
+		writeInboxError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": entry})
+}
+
+// MarkInboxEntryRead godoc
+// @Summary      Mark a notification as read
+// @Description  Marks a notification of the inbox as read, keeping the time it was first read
+// @Tags         Users
+// @Produce      json
+// @Param        id        path  int  true  "User ID"
+// @Param        entry_id  path  int  true  "Inbox entry ID"
+// @Success      200  {object}  map[string]interface{}  "Entry in data"
+// @Failure      400  {object}  utils.HTTPError  "Invalid ID"
+// @Failure      404  {object}  utils.HTTPError  "Entry not found"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /users/{id}/inbox/{entry_id}/read [put]
+// @Security Bearer
+func (c InboxController) MarkInboxEntryRead(ctx *gin.Context) {
+	userId, id, ok := inboxEntryIds(ctx)
+	if !ok {
+		return
+	}
+	entry, err := c.inboxService.MarkRead(ctx.Request.Context(), userId, id)
+	if err != nil {
+		writeInboxError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": entry})
+}
+
+// MarkInboxRead godoc
+// @Summary      Mark every notification as read
+// @Description  Marks every unread notification of the inbox as read
+// @Tags         Users
+// @Produce      json
+// @Param        id  path  int  true  "User ID"
+// @Success      200  {object}  map[string]interface{}  "How many entries were marked in marked"
+// @Failure      400  {object}  utils.HTTPError  "Invalid user ID"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /users/{id}/inbox/read [put]
+// @Security Bearer
+func (c InboxController) MarkInboxRead(ctx *gin.Context) {
+	userId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+	marked, err := c.inboxService.MarkAllRead(ctx.Request.Context(), userId)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"marked": marked})
+}
+
+// DeleteInboxEntry godoc
+// @Summary      Delete a notification of the inbox
+// @Description  Removes a notification from the inbox of the user
+// @Tags         Users
+// @Param        id        path  int  true  "User ID"
+// @Param        entry_id  path  int  true  "Inbox entry ID"
+// @Success      204  {object}  nil  "Entry deleted"
+// @Failure      400  {object}  utils.HTTPError  "Invalid ID"
+// @Failure      404  {object}  utils.HTTPError  "Entry not found"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /users/{id}/inbox/{entry_id} [delete]
+// @Security Bearer
+func (c InboxController) DeleteInboxEntry(ctx *gin.Context) {
+	userId, id, ok := inboxEntryIds(ctx)
+	if !ok {
+		return
+	}
+	if err := c.inboxService.DeleteInboxEntry(ctx.Request.Context(), userId, id); err != nil {
+		writeInboxError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// inboxEntryIds parses the user and entry ids of the path, answering 400 if they are invalid
+func inboxEntryIds(ctx *gin.Context) (int, int, bool) {
+	userId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid user ID format")
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(ctx.Param("entry_id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid inbox entry ID")
+		return 0, 0, false
+	}
+	return userId, id, true
+}
+
+func writeInboxError(ctx *gin.Context, err error) {
+	if errors.Is(err, repositories.ErrNotFound) {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Inbox entry not found")
+		return
+	}
+	utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+}
@@ -0,0 +1,127 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/controller"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	s "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupInboxTest(t *testing.T) (*s.MockInboxService, *gin.Context, *httptest.ResponseRecorder, *controller.InboxController) {
+	gin.SetMode(gin.TestMode)
+	mockService := s.NewMockInboxService(t)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	return mockService, c, recorder, controller.NewInboxController(mockService)
+}
+
+func TestInboxController_GetInbox(t *testing.T) {
+	mockService, c, recorder, inboxController := setupInboxTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/3/inbox?page=2&page_size=10&unread=true", nil)
+	c.Params = gin.Params{{Key: "id", Value: "3"}}
+
+	inbox := models.Inbox{
+		Entries:    []models.InboxEntry{{Id: 12, UserId: 3, Title: "Exam"}},
+		Pagination: models.Pagination{Page: 2, PageSize: 10, Total: 11},
+		Unread:     11,
+	}
+	mockService.EXPECT().GetInbox(mock.Anything, 3, models.InboxListRequest{Page: 2, PageSize: 10, Unread: true}).Return(inbox, nil)
+
+	inboxController.GetInbox(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response models.Inbox
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, 12, response.Entries[0].Id)
+	assert.Equal(t, 11, response.Pagination.Total)
+	assert.Equal(t, 11, response.Unread)
+}
+
+func TestInboxController_GetInbox_InvalidPage(t *testing.T) {
+	_, c, recorder, inboxController := setupInboxTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/3/inbox?page_size=1000", nil)
+	c.Params = gin.Params{{Key: "id", Value: "3"}}
+
+	inboxController.GetInbox(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestInboxController_GetInboxEntry_NotFound(t *testing.T) {
+	mockService, c, recorder, inboxController := setupInboxTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/3/inbox/13", nil)
+	c.Params = gin.Params{{Key: "id", Value: "3"}, {Key: "entry_id", Value: "13"}}
+	mockService.EXPECT().GetInboxEntry(mock.Anything, 3, 13).Return(nil, repositories.ErrNotFound)
+
+	inboxController.GetInboxEntry(c)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestInboxController_MarkInboxEntryRead(t *testing.T) {
+	mockService, c, recorder, inboxController := setupInboxTest(t)
+	c.Request = httptest.NewRequest(http.MethodPut, "/users/3/inbox/12/read", nil)
+	c.Params = gin.Params{{Key: "id", Value: "3"}, {Key: "entry_id", Value: "12"}}
+	readAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockService.EXPECT().MarkRead(mock.Anything, 3, 12).Return(&models.InboxEntry{Id: 12, UserId: 3, ReadAt: &readAt}, nil)
+
+	inboxController.MarkInboxEntryRead(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"read_at":"2026-03-01T12:00:00Z"`)
+}
+
+func TestInboxController_MarkInboxEntryRead_InvalidId(t *testing.T) {
+	_, c, recorder, inboxController := setupInboxTest(t)
+	c.Request = httptest.NewRequest(http.MethodPut, "/users/3/inbox/abc/read", nil)
+	c.Params = gin.Params{{Key: "id", Value: "3"}, {Key: "entry_id", Value: "abc"}}
+
+	inboxController.MarkInboxEntryRead(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestInboxController_MarkInboxRead(t *testing.T) {
+	mockService, c, recorder, inboxController := setupInboxTest(t)
+	c.Request = httptest.NewRequest(http.MethodPut, "/users/3/inbox/read", nil)
+	c.Params = gin.Params{{Key: "id", Value: "3"}}
+	mockService.EXPECT().MarkAllRead(mock.Anything, 3).Return(4, nil)
+
+	inboxController.MarkInboxRead(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"marked":4}`, recorder.Body.String())
+}
+
+func TestInboxController_DeleteInboxEntry(t *testing.T) {
+	mockService, c, recorder, inboxController := setupInboxTest(t)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/users/3/inbox/12", nil)
+	c.Params = gin.Params{{Key: "id", Value: "3"}, {Key: "entry_id", Value: "12"}}
+	mockService.EXPECT().DeleteInboxEntry(mock.Anything, 3, 12).Return(nil)
+
+	inboxController.DeleteInboxEntry(c)
+
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+}
+
+func TestInboxController_DeleteInboxEntry_Error(t *testing.T) {
+	mockService, c, recorder, inboxController := setupInboxTest(t)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/users/3/inbox/12", nil)
+	c.Params = gin.Params{{Key: "id", Value: "3"}, {Key: "entry_id", Value: "12"}}
+	mockService.EXPECT().DeleteInboxEntry(mock.Anything, 3, 12).Return(errors.New("db down"))
+
+	inboxController.DeleteInboxEntry(c)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// JobController lets admins look at the background jobs that failed for good and run them again
+type JobController struct {
+	jobService services.JobService
+}
+
+func NewJobController(jobService services.JobService) *JobController {
+	return &JobController{jobService: jobService}
+}
+
+// GetDeadJobs godoc
+// @Summary      List the dead jobs
+// @Description  Lists the background jobs, like emails and push notifications, that failed too many times to be retried, newest first. Jobs with secrets, like reset tokens, are listed without their payload.
+// @Tags         Admin
+// @Produce      json
+// @Param        limit  query  int  false  "Jobs to return, 50 by default and 500 at most"
+// @Success      200  {object}  map[string]interface{}  "Dead jobs in data"
+// @Failure      400  {object}  utils.HTTPError  "Invalid limit"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /admin/jobs/dead [get]
+// @Security Bearer
+func (c JobController) GetDeadJobs(ctx *gin.Context) {
+	limit := models.AuditDefaultPageSize
+	if value := ctx.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > models.AuditMaxPageSize {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", models.AuditMaxPageSize))
+			return
+		}
+		limit = parsed
+	}
+
+	jobs, err := c.jobService.GetDeadJobs(ctx.Request.Context(), limit)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": jobs})
+}
+
+// RequeueDeadJob godoc
+// @Summary      Requeue a dead job
+// @Description  Moves a dead job back to the queue to run right away, with its attempts reset
+// @Tags         Admin
+// @Produce      json
+// @Param        id  path  int  true  "Job ID"
+// @Success      200  {object}  map[string]interface{}  "Requeued job in data"
+// @Failure      400  {object}  utils.HTTPError  "Invalid job ID"
+// @Failure      404  {object}  utils.HTTPError  "Dead job not found"
+// @Failure      409  {object}  utils.HTTPError  "The job had secrets and its payload was dropped"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /admin/jobs/dead/{id}/requeue [post]
+// @Security Bearer
+func (c JobController) RequeueDeadJob(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	models.AuditFromContext(ctx).Describe("job.requeue", "job", id)
+	job, err := c.jobService.RequeueDeadJob(ctx.Request.Context(), id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Dead job not found")
+		return
+	}
+	if errors.Is(err, repositories.ErrSecretJob) {
+		utils.ErrorResponseWithErr(ctx, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": job})
+}
@@ -0,0 +1,108 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/controller"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	s "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupJobTest(t *testing.T) (*s.MockJobService, *gin.Context, *httptest.ResponseRecorder, *controller.JobController) {
+	gin.SetMode(gin.TestMode)
+	mockService := s.NewMockJobService(t)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	return mockService, c, recorder, controller.NewJobController(mockService)
+}
+
+func TestJobController_GetDeadJobs(t *testing.T) {
+	mockService, c, recorder, jobController := setupJobTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/jobs/dead?limit=10", nil)
+
+	jobs := []models.DeadJob{{Job: models.Job{Id: 4, Type: models.JobEmail, Payload: json.RawMessage(`{}`), Attempts: 8, LastError: "sendgrid: 503"}}}
+	mockService.EXPECT().GetDeadJobs(mock.Anything, 10).Return(jobs, nil)
+
+	jobController.GetDeadJobs(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response struct {
+		Data []models.DeadJob `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, 4, response.Data[0].Id)
+	assert.Equal(t, "sendgrid: 503", response.Data[0].LastError)
+}
+
+func TestJobController_GetDeadJobs_InvalidLimit(t *testing.T) {
+	_, c, recorder, jobController := setupJobTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/jobs/dead?limit=0", nil)
+
+	jobController.GetDeadJobs(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestJobController_GetDeadJobs_Error(t *testing.T) {
+	mockService, c, recorder, jobController := setupJobTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/jobs/dead", nil)
+	mockService.EXPECT().GetDeadJobs(mock.Anything, models.AuditDefaultPageSize).Return(nil, errors.New("db down"))
+
+	jobController.GetDeadJobs(c)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func TestJobController_RequeueDeadJob(t *testing.T) {
+	mockService, c, recorder, jobController := setupJobTest(t)
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/jobs/dead/4/requeue", nil)
+	c.Params = gin.Params{{Key: "id", Value: "4"}}
+	mockService.EXPECT().RequeueDeadJob(mock.Anything, 4).Return(&models.Job{Id: 4, Type: models.JobPush}, nil)
+
+	jobController.RequeueDeadJob(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"type":"push"`)
+}
+
+func TestJobController_RequeueDeadJob_InvalidId(t *testing.T) {
+	_, c, recorder, jobController := setupJobTest(t)
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/jobs/dead/abc/requeue", nil)
+	c.Params = gin.Params{{Key: "id", Value: "abc"}}
+
+	jobController.RequeueDeadJob(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestJobController_RequeueDeadJob_NotFound(t *testing.T) {
+	mockService, c, recorder, jobController := setupJobTest(t)
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/jobs/dead/9/requeue", nil)
+	c.Params = gin.Params{{Key: "id", Value: "9"}}
+	mockService.EXPECT().RequeueDeadJob(mock.Anything, 9).Return(nil, repositories.ErrNotFound)
+
+	jobController.RequeueDeadJob(c)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestJobController_RequeueDeadJob_Secret(t *testing.T) {
+	mockService, c, recorder, jobController := setupJobTest(t)
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/jobs/dead/9/requeue", nil)
+	c.Params = gin.Params{{Key: "id", Value: "9"}}
+	mockService.EXPECT().RequeueDeadJob(mock.Anything, 9).Return(nil, repositories.ErrSecretJob)
+
+	jobController.RequeueDeadJob(c)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
@@ -0,0 +1,355 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RuleProposalController handles the four-eyes workflow for the rules. With RULES_FOUR_EYES on,
+// creating, modifying, deleting, rolling back, publishing and retiring rules go through it and only store a proposal.
+type RuleProposalController struct {
+	service services.RuleProposalService
+}
+
+func NewRuleProposalController(service services.RuleProposalService) *RuleProposalController {
+	return &RuleProposalController{service: service}
+}
+
+// ProposeRule is POST /rules in four-eyes mode, it stores the rule as a proposal
+// that is created when a different admin approves it
+func (c RuleProposalController) ProposeRule(ctx *gin.Context) {
+	userId, ok := claimsUserId(ctx)
+	if !ok {
+		return
+	}
+	var rule models.Rule
+	if err := ctx.ShouldBindJSON(&rule); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+	models.AuditFromContext(ctx).Name("rule.propose")
+	proposal, err := c.service.ProposeRule(ctx.Request.Context(), rule, userId)
+	if err != nil {
+		writeRuleWriteError(ctx, err)
+		return
+	}
+	c.writeProposed(ctx, proposal)
+}
+
+// ProposeModification is PUT /rules/:id in four-eyes mode, it stores the modification
+// as a proposal against the current version of the rule
+func (c RuleProposalController) ProposeModification(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid rule ID")
+		return
+	}
+	userId, ok := claimsUserId(ctx)
+	if !ok {
+		return
+	}
+	var modification models.RuleModify
+	if err := ctx.ShouldBindJSON(&modification); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+	models.AuditFromContext(ctx).Describe("rule.propose", "rule", id)
+	proposal, err := c.service.ProposeModification(ctx.Request.Context(), id, modification, userId, ctx.GetHeader("If-Match"))
+	if err != nil {
+		writeRuleWriteError(ctx, err)
+		return
+	}
+	c.writeProposed(ctx, proposal)
+}
+
+// ProposeDeletion is DELETE /rules/:id in four-eyes mode, it stores the deletion
+// as a proposal against the current version of the rule
+func (c RuleProposalController) ProposeDeletion(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid rule ID")
+		return
+	}
+	userId, ok := claimsUserId(ctx)
+	if !ok {
+		return
+	}
+	models.AuditFromContext(ctx).Describe("rule.propose", "rule", id)
+	proposal, err := c.service.ProposeDeletion(ctx.Request.Context(), id, userId, ctx.GetHeader("If-Match"))
+	if err != nil {
+		writeRuleWriteError(ctx, err)
+		return
+	}
+	c.writeProposed(ctx, proposal)
+}
+
+// ProposeRollback is POST /rules/:id/rollback/:v in four-eyes mode, it stores the rollback
+// as a proposal against the current version of the rule
+func (c RuleProposalController) ProposeRollback(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid rule ID")
+		return
+	}
+	version, err := strconv.Atoi(ctx.Param("v"))
+	if err != nil || version < 1 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid version")
+		return
+	}
+	userId, ok := claimsUserId(ctx)
+	if !ok {
+		return
+	}
+	models.AuditFromContext(ctx).Describe("rule.propose", "rule", id)
+	proposal, err := c.service.ProposeRollback(ctx.Request.Context(), id, version, userId, ctx.GetHeader("If-Match"))
+	if err != nil {
+		writeRuleWriteError(ctx, err)
+		return
+	}
+	c.writeProposed(ctx, proposal)
+}
+
+// ProposePublish is POST /rules/:id/publish in four-eyes mode
+func (c RuleProposalController) ProposePublish(ctx *gin.Context) {
+	c.proposeStatusChange(ctx, models.RuleProposalPublish)
+}
+
+// ProposeRetire is POST /rules/:id/retire in four-eyes mode
+func (c RuleProposalController) ProposeRetire(ctx *gin.Context) {
+	c.proposeStatusChange(ctx, models.RuleProposalRetire)
+}
+
+func (c RuleProposalController) proposeStatusChange(ctx *gin.Context, action models.RuleProposalAction) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid rule ID")
+		return
+	}
+	userId, ok := claimsUserId(ctx)
+	if !ok {
+		return
+	}
+	models.AuditFromContext(ctx).Describe("rule.propose", "rule", id)
+	proposal, err := c.service.ProposeStatusChange(ctx.Request.Context(), id, action, userId, ctx.GetHeader("If-Match"))
+	if err != nil {
+		writeRuleWriteError(ctx, err)
+		return
+	}
+	c.writeProposed(ctx, proposal)
+}
+
+func (c RuleProposalController) writeProposed(ctx *gin.Context, proposal *models.RuleProposal) {
+	models.AuditFromContext(ctx).Change(nil, proposal)
+	ctx.JSON(http.StatusAccepted, gin.H{"data": proposal})
+}
+
+// GetProposals godoc
+// @Summary      Get the rule proposals
+// @Description  Lists the proposed changes to the rules, newest first, without their comments
+// @Tags         Rules
+// @Produce      json
+// @Param        status  query  string  false  "Only proposals in this status"  Enums(pending, approved, rejected)
+// @Success      200  {object}  map[string][]models.RuleProposal  "Proposals"
+// @Failure      400  {object}  utils.HTTPError  "Invalid filter"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /rules/proposals [get]
+// @Security Bearer
+func (c RuleProposalController) GetProposals(ctx *gin.Context) {
+	var filter models.RuleProposalListRequest
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+	proposals, err := c.service.GetProposals(ctx.Request.Context(), filter)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": proposals})
+}
+
+// GetProposal godoc
+// @Summary      Get a rule proposal
+// @Description  Returns the proposal with its comments, oldest first
+// @Tags         Rules
+// @Produce      json
+// @Param        id   path  int  true  "Proposal ID"
+// @Success      200  {object}  map[string]models.RuleProposal  "Proposal"
+// @Failure      400  {object}  utils.HTTPError  "Invalid proposal ID"
+// @Failure      404  {object}  utils.HTTPError  "Proposal not found"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /rules/proposals/{id} [get]
+// @Security Bearer
+func (c RuleProposalController) GetProposal(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid proposal ID")
+		return
+	}
+	proposal, err := c.service.GetProposal(ctx.Request.Context(), id)
+	if err != nil {
+		writeProposalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": proposal})
+}
+
+// CommentProposal godoc
+// @Summary      Comment on a rule proposal
+// @Tags         Rules
+// @Accept       json
+// @Produce      json
+// @Param        id       path  int                                true  "Proposal ID"
+// @Param        request  body  models.RuleProposalCommentRequest  true  "Comment"
+// @Success      201  {object}  map[string]models.RuleProposalComment  "Comment added"
+// @Failure      400  {object}  utils.HTTPError  "Invalid proposal ID or request format"
+// @Failure      404  {object}  utils.HTTPError  "Proposal not found"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /rules/proposals/{id}/comments [post]
+// @Security Bearer
+func (c RuleProposalController) CommentProposal(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid proposal ID")
+		return
+	}
+	userId, ok := claimsUserId(ctx)
+	if !ok {
+		return
+	}
+	var request models.RuleProposalCommentRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+	comment, err := c.service.CommentProposal(ctx.Request.Context(), id, userId, request.Comment)
+	if err != nil {
+		writeProposalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": comment})
+}
+
+// ApproveProposal godoc
+// @Summary      Approve a rule proposal
+// @Description  Applies the proposed change. The rules audit records both the admin who proposed it and the one who approved it
+// @Tags         Rules
+// @Produce      json
+// @Param        id   path  int  true  "Proposal ID"
+// @Success      200  {object}  map[string]models.RuleProposal  "Proposal approved and applied"
+// @Failure      400  {object}  utils.HTTPError  "Invalid proposal ID"
+// @Failure      403  {object}  utils.HTTPError  "Admins can't approve their own proposals"
+// @Failure      404  {object}  utils.HTTPError  "Proposal not found"
+// @Failure      409  {object}  utils.HTTPError  "Proposal already reviewed, or the rule changed since it was proposed"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /rules/proposals/{id}/approve [post]
+// @Security Bearer
+func (c RuleProposalController) ApproveProposal(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid proposal ID")
+		return
+	}
+	userId, ok := claimsUserId(ctx)
+	if !ok {
+		return
+	}
+	models.AuditFromContext(ctx).Describe("rule.approve", "rule_proposal", id)
+	before := c.auditedProposal(ctx, id)
+	proposal, err := c.service.ApproveProposal(ctx.Request.Context(), id, userId)
+	if err != nil {
+		writeProposalError(ctx, err)
+		return
+	}
+	models.AuditFromContext(ctx).Change(before, proposal)
+	ctx.JSON(http.StatusOK, gin.H{"data": proposal})
+}
+
+// RejectProposal godoc
+// @Summary      Reject a rule proposal
+// @Description  Discards the proposed change. Admins can reject their own proposals to withdraw them
+// @Tags         Rules
+// @Accept       json
+// @Produce      json
+// @Param        id       path  int                               true  "Proposal ID"
+// @Param        request  body  models.RuleProposalRejectRequest  true  "Why the proposal is rejected"
+// @Success      200  {object}  map[string]models.RuleProposal  "Proposal rejected"
+// @Failure      400  {object}  utils.HTTPError  "Invalid proposal ID or request format"
+// @Failure      404  {object}  utils.HTTPError  "Proposal not found"
+// @Failure      409  {object}  utils.HTTPError  "Proposal already reviewed"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /rules/proposals/{id}/reject [post]
+// @Security Bearer
+func (c RuleProposalController) RejectProposal(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid proposal ID")
+		return
+	}
+	userId, ok := claimsUserId(ctx)
+	if !ok {
+		return
+	}
+	var request models.RuleProposalRejectRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+	models.AuditFromContext(ctx).Describe("rule.reject", "rule_proposal", id)
+	before := c.auditedProposal(ctx, id)
+	proposal, err := c.service.RejectProposal(ctx.Request.Context(), id, userId, request.Reason)
+	if err != nil {
+		writeProposalError(ctx, err)
+		return
+	}
+	models.AuditFromContext(ctx).Change(before, proposal)
+	ctx.JSON(http.StatusOK, gin.H{"data": proposal})
+}
+
+// auditedProposal returns the proposal as it is before being reviewed, to record it in the audit of the request
+func (c RuleProposalController) auditedProposal(ctx *gin.Context, id int) *models.RuleProposal {
+	if models.AuditFromContext(ctx) == nil {
+		return nil
+	}
+	proposal, err := c.service.GetProposal(ctx.Request.Context(), id)
+	if err != nil {
+		return nil
+	}
+	return proposal
+}
+
+// writeProposalError maps errors from reviewing proposals to their HTTP status
+func writeProposalError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Proposal not found")
+	case errors.Is(err, services.ErrSelfApproval):
+		utils.ErrorResponseWithErr(ctx, http.StatusForbidden, err)
+	case errors.Is(err, repositories.ErrProposalReviewed), errors.Is(err, services.ErrProposalOutdated):
+		utils.ErrorResponseWithErr(ctx, http.StatusConflict, err)
+	default:
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+	}
+}
+
+// claimsUserId returns the id of the logged in user, writing the error response when it can't
+func claimsUserId(ctx *gin.Context) (int, bool) {
+	claims, err := models.GetClaimsFromGinContext(ctx)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return 0, false
+	}
+	userId, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return 0, false
+	}
+	return userId, true
+}
@@ -0,0 +1,199 @@
+package controller_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/controller"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	s "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupRuleProposalTest(t *testing.T, method string, target string, body string) (*s.MockRuleProposalService, *gin.Context, *httptest.ResponseRecorder, *controller.RuleProposalController) {
+	gin.SetMode(gin.TestMode)
+	mockService := s.NewMockRuleProposalService(t)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(method, target, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("claims", &models.Claims{StandardClaims: jwt.StandardClaims{Subject: "8"}, Role: "admin"})
+	return mockService, c, recorder, controller.NewRuleProposalController(mockService)
+}
+
+func TestRuleProposalController_ProposeRule(t *testing.T) {
+	mockService, c, recorder, proposalController := setupRuleProposalTest(t, http.MethodPost, "/rules",
+		`{"Title":"T","Description":"D","ApplicationCondition":"true"}`)
+
+	mockService.EXPECT().ProposeRule(mock.Anything, mock.Anything, 8).
+		Return(&models.RuleProposal{Id: 1, Action: models.RuleProposalCreate, Status: models.RuleProposalPending}, nil)
+
+	proposalController.ProposeRule(c)
+
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"status":"pending"`)
+}
+
+func TestRuleProposalController_ProposeModification(t *testing.T) {
+	mockService, c, recorder, proposalController := setupRuleProposalTest(t, http.MethodPut, "/rules/4", `{}`)
+	c.Params = gin.Params{{Key: "id", Value: "4"}}
+	c.Request.Header.Set("If-Match", `"2"`)
+
+	mockService.EXPECT().ProposeModification(mock.Anything, 4, models.RuleModify{}, 8, `"2"`).Return(nil, s.ErrEmptyModification)
+
+	proposalController.ProposeModification(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestRuleProposalController_ProposeDeletion(t *testing.T) {
+	mockService, c, recorder, proposalController := setupRuleProposalTest(t, http.MethodDelete, "/rules/4", "")
+	c.Params = gin.Params{{Key: "id", Value: "4"}}
+	c.Request.Header.Set("If-Match", `"1"`)
+
+	mockService.EXPECT().ProposeDeletion(mock.Anything, 4, 8, `"1"`).Return(nil, repositories.ErrVersionMismatch)
+
+	proposalController.ProposeDeletion(c)
+
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+}
+
+func TestRuleProposalController_ProposeRollback(t *testing.T) {
+	mockService, c, recorder, proposalController := setupRuleProposalTest(t, http.MethodPost, "/rules/4/rollback/1", "")
+	c.Params = gin.Params{{Key: "id", Value: "4"}, {Key: "v", Value: "1"}}
+	c.Request.Header.Set("If-Match", `"2"`)
+
+	mockService.EXPECT().ProposeRollback(mock.Anything, 4, 1, 8, `"2"`).
+		Return(&models.RuleProposal{Id: 1, Action: models.RuleProposalRollback, Status: models.RuleProposalPending}, nil)
+
+	proposalController.ProposeRollback(c)
+
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"action":"rollback"`)
+}
+
+func TestRuleProposalController_ProposePublish(t *testing.T) {
+	mockService, c, recorder, proposalController := setupRuleProposalTest(t, http.MethodPost, "/rules/4/publish", "")
+	c.Params = gin.Params{{Key: "id", Value: "4"}}
+	c.Request.Header.Set("If-Match", `"2"`)
+
+	mockService.EXPECT().ProposeStatusChange(mock.Anything, 4, models.RuleProposalPublish, 8, `"2"`).
+		Return(&models.RuleProposal{Id: 1, Action: models.RuleProposalPublish, Status: models.RuleProposalPending}, nil)
+
+	proposalController.ProposePublish(c)
+
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+}
+
+func TestRuleProposalController_ProposeRetire(t *testing.T) {
+	mockService, c, recorder, proposalController := setupRuleProposalTest(t, http.MethodPost, "/rules/4/retire", "")
+	c.Params = gin.Params{{Key: "id", Value: "4"}}
+	c.Request.Header.Set("If-Match", `"2"`)
+
+	mockService.EXPECT().ProposeStatusChange(mock.Anything, 4, models.RuleProposalRetire, 8, `"2"`).
+		Return(nil, s.ErrInvalidRuleTransition)
+
+	proposalController.ProposeRetire(c)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func TestRuleProposalController_GetProposals(t *testing.T) {
+	mockService, c, recorder, proposalController := setupRuleProposalTest(t, http.MethodGet, "/rules/proposals?status=pending", "")
+
+	mockService.EXPECT().GetProposals(mock.Anything, models.RuleProposalListRequest{Status: models.RuleProposalPending}).
+		Return([]models.RuleProposal{{Id: 1}}, nil)
+
+	proposalController.GetProposals(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestRuleProposalController_GetProposals_InvalidFilter(t *testing.T) {
+	_, c, recorder, proposalController := setupRuleProposalTest(t, http.MethodGet, "/rules/proposals?status=withdrawn", "")
+
+	proposalController.GetProposals(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestRuleProposalController_GetProposal_NotFound(t *testing.T) {
+	mockService, c, recorder, proposalController := setupRuleProposalTest(t, http.MethodGet, "/rules/proposals/9", "")
+	c.Params = gin.Params{{Key: "id", Value: "9"}}
+
+	mockService.EXPECT().GetProposal(mock.Anything, 9).Return(nil, repositories.ErrNotFound)
+
+	proposalController.GetProposal(c)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestRuleProposalController_CommentProposal(t *testing.T) {
+	mockService, c, recorder, proposalController := setupRuleProposalTest(t, http.MethodPost, "/rules/proposals/1/comments", `{"comment":"Why?"}`)
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	mockService.EXPECT().CommentProposal(mock.Anything, 1, 8, "Why?").Return(&models.RuleProposalComment{Id: 2, Comment: "Why?"}, nil)
+
+	proposalController.CommentProposal(c)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+}
+
+func TestRuleProposalController_ApproveProposal(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"approved", nil, http.StatusOK},
+		{"own proposal", s.ErrSelfApproval, http.StatusForbidden},
+		{"already reviewed", repositories.ErrProposalReviewed, http.StatusConflict},
+		{"rule changed", s.ErrProposalOutdated, http.StatusConflict},
+		{"not found", repositories.ErrNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService, c, recorder, proposalController := setupRuleProposalTest(t, http.MethodPost, "/rules/proposals/1/approve", "")
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			var proposal *models.RuleProposal
+			if tt.err == nil {
+				proposal = &models.RuleProposal{Id: 1, Status: models.RuleProposalApproved, ProposedBy: 7, ReviewedBy: 8}
+			}
+			mockService.EXPECT().ApproveProposal(mock.Anything, 1, 8).Return(proposal, tt.err)
+
+			proposalController.ApproveProposal(c)
+
+			assert.Equal(t, tt.expected, recorder.Code)
+		})
+	}
+}
+
+func TestRuleProposalController_RejectProposal(t *testing.T) {
+	mockService, c, recorder, proposalController := setupRuleProposalTest(t, http.MethodPost, "/rules/proposals/1/reject", `{"reason":"Duplicated"}`)
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	mockService.EXPECT().RejectProposal(mock.Anything, 1, 8, "Duplicated").
+		Return(&models.RuleProposal{Id: 1, Status: models.RuleProposalRejected, ReviewReason: "Duplicated"}, nil)
+
+	proposalController.RejectProposal(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"review_reason":"Duplicated"`)
+}
+
+func TestRuleProposalController_RejectProposal_MissingReason(t *testing.T) {
+	_, c, recorder, proposalController := setupRuleProposalTest(t, http.MethodPost, "/rules/proposals/1/reject", `{}`)
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	proposalController.RejectProposal(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
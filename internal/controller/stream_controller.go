@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// StreamController streams the events of users to their connected clients, so they don't have to poll
+type StreamController struct {
+	streamService services.StreamService
+	heartbeat     time.Duration
+	// allowOrigin tells whether browsers on the origin can open WebSockets, like CORS does for requests
+	allowOrigin func(origin string) bool
+}
+
+func NewStreamController(streamService services.StreamService, heartbeat time.Duration, allowOrigin func(origin string) bool) *StreamController {
+	return &StreamController{streamService: streamService, heartbeat: heartbeat, allowOrigin: allowOrigin}
+}
+
+var errOriginNotAllowed = errors.New("origin not allowed")
+
+// Stream godoc
+// @Summary      Stream the events of a user
+// @Description  Streams new inbox entries, blocks and forced logouts with Server-Sent Events, or as JSON messages over WebSocket when the request asks to upgrade. Inbox events carry the entry id as event id: reconnecting with it in the Last-Event-ID header, or the last_event_id query for WebSocket, first sends the entries that were missed. The stream ends after a block or logout event, and a heartbeat is sent while it's idle
+// @Tags         Users
+// @Produce      text/event-stream
+// @Param        id             path    int  true   "User ID"
+// @Param        Last-Event-ID  header  int  false  "Id of the last inbox event received"
+// @Param        last_event_id  query   int  false  "Id of the last inbox event received, for clients that can't set headers"
+// @Success      200  {string}  string  "Stream of events"
+// @Failure      400  {object}  utils.HTTPError  "Invalid user or event ID"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /users/{id}/stream [get]
+// @Security Bearer
+func (c StreamController) Stream(ctx *gin.Context) {
+	userId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+	lastEventId, resume, err := parseLastEventId(ctx)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid last event ID")
+		return
+	}
+
+	// Subscribed before looking for missed entries, so none falls in between
+	events, cancel := c.streamService.Subscribe(userId)
+	defer cancel()
+	var missed []models.UserEvent
+	if resume {
+		missed, err = c.streamService.Missed(ctx.Request.Context(), userId, lastEventId)
+		if err != nil {
+			utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	if strings.EqualFold(ctx.GetHeader("Upgrade"), "websocket") {
+		websocket.Server{Handshake: c.checkOrigin, Handler: func(conn *websocket.Conn) {
+			// Clients don't send anything, reading only notices when they leave
+			streamCtx, leave := context.WithCancel(ctx.Request.Context())
+			defer leave()
+			go func() {
+				io.Copy(io.Discard, conn)
+				leave()
+			}()
+			c.pump(streamCtx, webSocketWriter{conn}, events, missed)
+		}}.ServeHTTP(ctx.Writer, ctx.Request)
+		return
+	}
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	// Keeps nginx from buffering the events
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+	c.pump(ctx.Request.Context(), sseWriter{ctx.Writer}, events, missed)
+}
+
+// checkOrigin keeps other sites from opening WebSockets with the credentials of the user.
+// Clients that aren't browsers, like the mobile app, send no Origin.
+func (c StreamController) checkOrigin(config *websocket.Config, req *http.Request) error {
+	origin := req.Header.Get("Origin")
+	if origin != "" && !c.allowOrigin(origin) {
+		return errOriginNotAllowed
+	}
+	return nil
+}
+
+// parseLastEventId returns the id of the last inbox event the client got, and whether it sent one
+func parseLastEventId(ctx *gin.Context) (int, bool, error) {
+	value := ctx.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = ctx.Query("last_event_id")
+	}
+	if value == "" {
+		return 0, false, nil
+	}
+	id, err := strconv.Atoi(value)
+	return id, true, err
+}
+
+// eventWriter sends events in the format of the stream
+type eventWriter interface {
+	write(event models.UserEvent) error
+	heartbeat() error
+}
+
+// pump sends the missed events and then the new ones, until the stream ends or the client leaves
+func (c StreamController) pump(ctx context.Context, writer eventWriter, events <-chan models.UserEvent, missed []models.UserEvent) {
+	sent := map[int]bool{}
+	for _, event := range missed {
+		if err := writer.write(event); err != nil {
+			return
+		}
+		sent[event.Id] = true
+	}
+
+	// No heartbeats if they are disabled, a nil channel never receives
+	var heartbeats <-chan time.Time
+	if c.heartbeat > 0 {
+		ticker := time.NewTicker(c.heartbeat)
+		defer ticker.Stop()
+		heartbeats = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				// Dropped by the hub, the client reconnects and resumes
+				return
+			}
+			// Entries added while catching up were sent with the missed ones already.
+			// Ids aren't compared, entries can be committed out of order.
+			if event.Type == models.UserEventInbox && sent[event.Id] {
+				delete(sent, event.Id)
+				continue
+			}
+			if err := writer.write(event); err != nil || event.Ends() {
+				return
+			}
+		case <-heartbeats:
+			if err := writer.heartbeat(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+type sseWriter struct {
+	w gin.ResponseWriter
+}
+
+func (s sseWriter) write(event models.UserEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if event.Type == models.UserEventInbox {
+		if _, err := fmt.Fprintf(s.w, "id: %d\n", event.Id); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+		return err
+	}
+	s.w.Flush()
+	return nil
+}
+
+func (s sseWriter) heartbeat() error {
+	// Lines starting with a colon are comments, clients ignore them
+	if _, err := io.WriteString(s.w, ": heartbeat\n\n"); err != nil {
+		return err
+	}
+	s.w.Flush()
+	return nil
+}
+
+type webSocketWriter struct {
+	conn *websocket.Conn
+}
+
+func (ws webSocketWriter) write(event models.UserEvent) error {
+	return websocket.JSON.Send(ws.conn, event)
+}
+
+func (ws webSocketWriter) heartbeat() error {
+	return websocket.JSON.Send(ws.conn, gin.H{"type": "heartbeat"})
+}
@@ -0,0 +1,188 @@
+package controller_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/controller"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	s "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func setupStreamTest(t *testing.T, heartbeat time.Duration) (*s.MockStreamService, *gin.Context, *httptest.ResponseRecorder, *controller.StreamController) {
+	gin.SetMode(gin.TestMode)
+	mockService := s.NewMockStreamService(t)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Params = gin.Params{{Key: "id", Value: "3"}}
+	return mockService, c, recorder, controller.NewStreamController(mockService, heartbeat, allowTestOrigin)
+}
+
+func allowTestOrigin(origin string) bool {
+	return origin == "https://app.example.com"
+}
+
+// serveStream serves the stream of user 3 over HTTP, for WebSocket clients
+func serveStream(t *testing.T, heartbeat time.Duration) (*s.MockStreamService, string) {
+	gin.SetMode(gin.TestMode)
+	mockService := s.NewMockStreamService(t)
+	router := gin.New()
+	router.GET("/users/:id/stream", controller.NewStreamController(mockService, heartbeat, allowTestOrigin).Stream)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return mockService, "ws" + strings.TrimPrefix(server.URL, "http") + "/users/3/stream"
+}
+
+func TestStreamController_Stream(t *testing.T) {
+	mockService, c, recorder, streamController := setupStreamTest(t, time.Hour)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/3/stream", nil)
+
+	events := make(chan models.UserEvent, 2)
+	events <- models.NewInboxEvent(models.InboxEntry{Id: 12, UserId: 3, Title: "Exam"})
+	events <- models.UserEvent{UserId: 3, Type: models.UserEventBlock}
+	cancelled := false
+	mockService.EXPECT().Subscribe(3).Return(events, func() { cancelled = true })
+
+	// Ends after the block, the user can't stay connected
+	streamController.Stream(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+	body := recorder.Body.String()
+	assert.Contains(t, body, "id: 12\nevent: inbox\ndata: {\"id\":12,\"user_id\":3,\"type\":\"inbox\",\"entry\":{")
+	// Only inbox events move the Last-Event-ID of the client
+	assert.Contains(t, body, "\n\nevent: block\ndata: {\"user_id\":3,\"type\":\"block\"}\n\n")
+	assert.True(t, cancelled)
+}
+
+func TestStreamController_Stream_Resume(t *testing.T) {
+	mockService, c, recorder, streamController := setupStreamTest(t, time.Hour)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/3/stream", nil)
+	c.Request.Header.Set("Last-Event-ID", "10")
+
+	events := make(chan models.UserEvent, 3)
+	// Added while catching up, it's among the missed entries too
+	events <- models.NewInboxEvent(models.InboxEntry{Id: 12, UserId: 3})
+	events <- models.NewInboxEvent(models.InboxEntry{Id: 13, UserId: 3})
+	// Committed after 12 and 13 with an earlier id, it wasn't missed
+	events <- models.NewInboxEvent(models.InboxEntry{Id: 9, UserId: 3})
+	close(events)
+	mockService.EXPECT().Subscribe(3).Return(events, func() {})
+	mockService.EXPECT().Missed(mock.Anything, 3, 10).Return([]models.UserEvent{
+		models.NewInboxEvent(models.InboxEntry{Id: 11, UserId: 3}),
+		models.NewInboxEvent(models.InboxEntry{Id: 12, UserId: 3}),
+	}, nil)
+
+	streamController.Stream(c)
+
+	body := recorder.Body.String()
+	assert.Contains(t, body, "id: 11\n")
+	assert.Contains(t, body, "id: 13\n")
+	assert.Contains(t, body, "id: 9\n")
+	assert.Equal(t, 1, strings.Count(body, "id: 12\n"))
+}
+
+func TestStreamController_Stream_Heartbeat(t *testing.T) {
+	mockService, c, recorder, streamController := setupStreamTest(t, time.Millisecond)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/3/stream", nil)
+
+	events := make(chan models.UserEvent)
+	mockService.EXPECT().Subscribe(3).Return(events, func() {})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(events)
+	}()
+
+	streamController.Stream(c)
+
+	assert.Contains(t, recorder.Body.String(), ": heartbeat\n\n")
+}
+
+func TestStreamController_Stream_InvalidLastEventId(t *testing.T) {
+	_, c, recorder, streamController := setupStreamTest(t, time.Hour)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/3/stream?last_event_id=abc", nil)
+
+	streamController.Stream(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestStreamController_Stream_MissedError(t *testing.T) {
+	mockService, c, recorder, streamController := setupStreamTest(t, time.Hour)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/3/stream?last_event_id=10", nil)
+
+	mockService.EXPECT().Subscribe(3).Return(make(chan models.UserEvent), func() {})
+	mockService.EXPECT().Missed(mock.Anything, 3, 10).Return(nil, errors.New("db down"))
+
+	streamController.Stream(c)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func TestStreamController_Stream_WebSocket(t *testing.T) {
+	mockService, url := serveStream(t, time.Hour)
+	events := make(chan models.UserEvent, 1)
+	events <- models.NewInboxEvent(models.InboxEntry{Id: 12, UserId: 3})
+	mockService.EXPECT().Subscribe(3).Return(events, func() {})
+
+	conn, err := websocket.Dial(url, "", "https://app.example.com")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var event models.UserEvent
+	require.NoError(t, websocket.JSON.Receive(conn, &event))
+	assert.Equal(t, 12, event.Id)
+}
+
+func TestStreamController_Stream_WebSocket_NoOrigin(t *testing.T) {
+	mockService, url := serveStream(t, time.Hour)
+	mockService.EXPECT().Subscribe(3).Return(make(chan models.UserEvent), func() {})
+
+	// Clients that aren't browsers, like the mobile app, send no Origin
+	req, err := http.NewRequest(http.MethodGet, "http"+strings.TrimPrefix(url, "ws"), nil)
+	require.NoError(t, err)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+}
+
+func TestStreamController_Stream_WebSocket_OtherOrigin(t *testing.T) {
+	mockService, url := serveStream(t, time.Hour)
+	mockService.EXPECT().Subscribe(3).Return(make(chan models.UserEvent), func() {})
+
+	// Another site can't open it with the cookies of the user
+	_, err := websocket.Dial(url, "", "https://evil.example.com")
+	assert.Error(t, err)
+}
+
+func TestStreamController_Stream_WebSocket_ClientLeaves(t *testing.T) {
+	// Without heartbeats, only reading notices the client left
+	mockService, url := serveStream(t, 0)
+	cancelled := make(chan struct{})
+	mockService.EXPECT().Subscribe(3).Return(make(chan models.UserEvent), func() { close(cancelled) })
+
+	conn, err := websocket.Dial(url, "", "https://app.example.com")
+	require.NoError(t, err)
+	conn.Close()
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the stream kept going after the client left")
+	}
+}
@@ -1,10 +1,17 @@
 package controller
 
 import (
-	"fmt"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/Ingenieria-de-Software-2-Gupo-14/go-core/pkg/log"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
@@ -16,27 +23,41 @@ import (
 
 // UserController struct that contains a database with users
 type UserController struct {
-	service     services.UserService
-	ruleService services.RulesService
+	service         services.UserService
+	ruleService     services.RulesService
+	passwordService services.PasswordService
 }
 
 // CreateController creates a controller
-func CreateController(service services.UserService, ruleService services.RulesService) *UserController {
-	return &UserController{service: service, ruleService: ruleService}
+func CreateController(service services.UserService, ruleService services.RulesService, passwordService services.PasswordService) *UserController {
+	return &UserController{service: service, ruleService: ruleService, passwordService: passwordService}
 }
 
 // UsersGet godoc
 // @Summary      Get all users
-// @Description  Returns a list of all users in the system
+// @Description  Returns a list of all users in the system, optionally filtered
 // @Tags         Users
 // @Accept       json
 // @Produce      json
+// @Param        role          query  string  false  "Only users with this role"  Enums(student, teacher, admin)
+// @Param        verified      query  bool    false  "Only verified or unverified users"
+// @Param        blocked       query  bool    false  "Only blocked or unblocked users"
+// @Param        q             query  string  false  "Text to search in the name, surname and email"
+// @Param        created_from  query  string  false  "Only users created on or after this day (YYYY-MM-DD)"
+// @Param        created_to    query  string  false  "Only users created on or before this day (YYYY-MM-DD)"
 // @Success      200  {object}  map[string][]models.User  "List of users"
+// @Failure      400  {object}  utils.HTTPError          "Invalid filter"
 // @Failure      500  {object}  utils.HTTPError          "Internal server error"
 // @Router       /users [get]
 // @Security Bearer
 func (c UserController) UsersGet(context *gin.Context) {
-	users, err := c.service.GetAllUsers(context.Request.Context())
+	var filter models.UserFilter
+	if err := context.ShouldBindQuery(&filter); err != nil {
+		utils.ErrorResponseWithErr(context, http.StatusBadRequest, err)
+		return
+	}
+
+	users, err := c.service.GetAllUsers(context.Request.Context(), filter)
 	if err != nil {
 		utils.ErrorResponseWithErr(context, http.StatusInternalServerError, err)
 		return
@@ -52,9 +73,12 @@ func (c UserController) UsersGet(context *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int  true  "User ID"
+// @Param        If-None-Match  header  string  false  "ETag from a previous response"
 // @Success      200  {object}  map[string]models.User  "User data"
+// @Success      304  {object}  nil                    "User not modified since the given ETag"
 // @Failure      400  {object}  utils.HTTPError        "Invalid user ID format"
 // @Failure      404  {object}  utils.HTTPError        "User not found"
+// @Header       200  {string}  ETag  "Current version of the user"
 // @Router       /users/{id} [get]
 // @Security Bearer
 func (c UserController) UserGetById(context *gin.Context) {
@@ -70,6 +94,12 @@ func (c UserController) UserGetById(context *gin.Context) {
 		return
 	}
 
+	context.Header("ETag", user.ETag())
+	if utils.MatchesETag(context.GetHeader("If-None-Match"), user.ETag()) {
+		context.AbortWithStatus(http.StatusNotModified)
+		return
+	}
+
 	context.JSON(http.StatusOK, gin.H{"data": user})
 }
 
@@ -80,8 +110,12 @@ func (c UserController) UserGetById(context *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int  true  "User ID"
+// @Param        If-Match  header  string  true  "ETag of the user being deleted"
 // @Success      204  {object}  nil  "User successfully deleted"
 // @Failure      400  {object}  utils.HTTPError  "Invalid user ID format"
+// @Failure      404  {object}  utils.HTTPError  "User not found"
+// @Failure      412  {object}  utils.HTTPError  "User was modified since the given ETag"
+// @Failure      428  {object}  utils.HTTPError  "Missing If-Match header"
 // @Failure      500  {object}  utils.HTTPError  "Internal server error"
 // @Router       /users/{id} [delete]
 // @Security Bearer
@@ -91,11 +125,21 @@ func (controller UserController) UserDeleteById(context *gin.Context) {
 		utils.ErrorResponse(context, http.StatusBadRequest, "Invalid user ID format")
 		return
 	}
+	models.AuditFromContext(context).Describe("user.delete", "user", id)
+	before := controller.auditedUser(context, id)
 
-	if err := controller.service.DeleteUser(context.Request.Context(), id); err != nil {
-		utils.ErrorResponseWithErr(context, http.StatusInternalServerError, err)
+	if err := controller.service.DeleteUser(context.Request.Context(), id, context.GetHeader("If-Match")); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrNotFound):
+			utils.ErrorResponse(context, http.StatusNotFound, "User not found")
+		case errors.Is(err, repositories.ErrVersionMismatch):
+			utils.ErrorResponseWithErr(context, http.StatusPreconditionFailed, err)
+		default:
+			utils.ErrorResponseWithErr(context, http.StatusInternalServerError, err)
+		}
 		return
 	}
+	models.AuditFromContext(context).Change(before, nil)
 	context.JSON(http.StatusNoContent, nil)
 }
 
@@ -107,9 +151,14 @@ func (controller UserController) UserDeleteById(context *gin.Context) {
 // @Produce      json
 // @Param        id    path      int         true  "User ID"
 // @Param        user  body      models.UserUpdateDto  true  "Updated user data"
-// @Success      200   {object}  map[string]models.UserUpdateDto  "Updated user data"
+// @Param        If-Match  header  string  true  "ETag of the user being modified"
+// @Success      200   {object}  map[string]models.User  "Updated user data"
 // @Failure      400   {object}  utils.HTTPError        "Invalid user ID format or request format"
+// @Failure      404   {object}  utils.HTTPError        "User not found"
+// @Failure      412   {object}  utils.HTTPError        "User was modified since the given ETag"
+// @Failure      428   {object}  utils.HTTPError        "Missing If-Match header"
 // @Failure      500   {object}  utils.HTTPError        "Internal server error"
+// @Header       200   {string}  ETag  "New version of the user"
 // @Router       /users/{id} [put]
 // @Security Bearer
 func (c UserController) ModifyUser(context *gin.Context) {
@@ -125,12 +174,94 @@ func (c UserController) ModifyUser(context *gin.Context) {
 		return
 	}
 
-	if err := c.service.ModifyUser(context.Request.Context(), id, user); err != nil {
-		utils.ErrorResponseWithErr(context, http.StatusInternalServerError, err)
+	models.AuditFromContext(context).Describe("user.update", "user", id)
+	before := c.auditedUser(context, id)
+	updated, err := c.service.ModifyUser(context.Request.Context(), id, user, context.GetHeader("If-Match"))
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrNotFound):
+			utils.ErrorResponse(context, http.StatusNotFound, "User not found")
+		case errors.Is(err, repositories.ErrVersionMismatch):
+			utils.ErrorResponseWithErr(context, http.StatusPreconditionFailed, err)
+		default:
+			utils.ErrorResponseWithErr(context, http.StatusInternalServerError, err)
+		}
 		return
 	}
 
-	context.JSON(http.StatusOK, gin.H{"data": user})
+	models.AuditFromContext(context).Change(before, updated)
+	context.Header("ETag", updated.ETag())
+	context.JSON(http.StatusOK, gin.H{"data": updated})
+}
+
+// PatchUser godoc
+// @Summary      Partially update user
+// @Description  Applies a JSON Merge Patch (RFC 7396) to the user profile. Omitted fields are kept and fields set to null are cleared.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int               true  "User ID"
+// @Param        patch  body      models.UserPatch  true  "Merge patch document"
+// @Param        If-Match  header  string  true  "ETag of the user being modified"
+// @Success      200    {object}  map[string]models.User  "Updated user data"
+// @Failure      400    {object}  utils.HTTPError        "Invalid user ID format, malformed patch or validation error"
+// @Failure      404    {object}  utils.HTTPError        "User not found"
+// @Failure      412    {object}  utils.HTTPError        "User was modified since the given ETag"
+// @Failure      415    {object}  utils.HTTPError        "Unsupported content type"
+// @Failure      428    {object}  utils.HTTPError        "Missing If-Match header"
+// @Failure      500    {object}  utils.HTTPError        "Internal server error"
+// @Header       200    {string}  ETag  "New version of the user"
+// @Router       /users/{id} [patch]
+// @Security Bearer
+func (c UserController) PatchUser(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	if contentType := ctx.ContentType(); contentType != "application/merge-patch+json" && contentType != "application/json" {
+		utils.ErrorResponse(ctx, http.StatusUnsupportedMediaType, "Content-Type must be application/merge-patch+json")
+		return
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil || !bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Patch document must be a JSON object")
+		return
+	}
+
+	var patch models.UserPatch
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patch); err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := patch.Validate(); err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	models.AuditFromContext(ctx).Describe("user.patch", "user", id)
+	before := c.auditedUser(ctx, id)
+	user, err := c.service.PatchUser(ctx.Request.Context(), id, patch, ctx.GetHeader("If-Match"))
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrNotFound):
+			utils.ErrorResponse(ctx, http.StatusNotFound, "User not found")
+		case errors.Is(err, repositories.ErrVersionMismatch):
+			utils.ErrorResponseWithErr(ctx, http.StatusPreconditionFailed, err)
+		default:
+			utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	models.AuditFromContext(ctx).Change(before, user)
+	ctx.Header("ETag", user.ETag())
+	ctx.JSON(http.StatusOK, gin.H{"data": user})
 }
 
 // BlockUserById godoc
@@ -152,11 +283,14 @@ func (c UserController) BlockUserById(context *gin.Context) {
 		return
 	}
 
+	models.AuditFromContext(context).Describe("user.block", "user", id)
+	before := c.auditedUser(context, id)
 	// TODO: Add reason and blockerId
 	if err := c.service.BlockUser(context.Request.Context(), id, "", nil, nil); err != nil {
 		utils.ErrorResponseWithErr(context, http.StatusInternalServerError, err)
 		return
 	}
+	models.AuditFromContext(context).Change(before, c.auditedUser(context, id))
 
 	context.String(http.StatusOK, "User blocked successfully")
 }
@@ -180,10 +314,13 @@ func (c UserController) MakeTeacher(context *gin.Context) {
 		return
 	}
 
+	models.AuditFromContext(context).Describe("user.make_teacher", "user", id)
+	before := c.auditedUser(context, id)
 	if err := c.service.MakeTeacher(context.Request.Context(), id); err != nil {
 		utils.ErrorResponseWithErr(context, http.StatusInternalServerError, err)
 		return
 	}
+	models.AuditFromContext(context).Change(before, c.auditedUser(context, id))
 	context.String(http.StatusOK, "User made teacher successfully")
 }
 
@@ -196,7 +333,8 @@ func (c UserController) MakeTeacher(context *gin.Context) {
 // @Param        id        path      int         true  "User ID"
 // @Param        password  body      models.PasswordModifyRequest  true  "User with updated password"
 // @Success      200       {object}  nil          "Pasword updated successfully"
-// @Failure      400       {object}  utils.HTTPError  "Invalid user ID format or request"
+// @Failure      400       {object}  utils.HTTPError  "Invalid request, invalid or expired token, or password rejected by the password policy"
+// @Failure      429       {object}  utils.HTTPError  "Too many wrong attempts for the token, a new one must be requested"
 // @Failure      500       {object}  utils.HTTPError  "Internal server error"
 // @Router       /users/password [put]
 func (c UserController) ModifyUserPasssword(ctx *gin.Context) {
@@ -207,31 +345,53 @@ func (c UserController) ModifyUserPasssword(ctx *gin.Context) {
 	}
 	data, err := c.service.ValidatePasswordResetToken(ctx.Request.Context(), user.Token)
 	if err != nil {
-		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		writeResetTokenError(ctx, err)
 		return
 	}
-	if c.service.ModifyPassword(ctx.Request.Context(), data.UserId, user.Password) != nil {
-		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+	// The token is only used up if the password changes, a rejected password can be retried with it
+	err = c.passwordService.ResetPassword(ctx.Request.Context(), data.Id, data.UserId, user.Password)
+	if errors.Is(err, services.ErrInvalidResetToken) {
+		writeResetTokenError(ctx, err)
 		return
 	}
-	err = c.service.SetPasswordTokenUsed(ctx.Request.Context(), user.Token)
 	if err != nil {
-		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		writeNewPasswordError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, nil)
 
 }
 
+func writeNewPasswordError(ctx *gin.Context, err error) {
+	var validationErrs models.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+	utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+}
+
+func writeResetTokenError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidResetToken):
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+	case errors.Is(err, services.ErrResetAttemptsExceeded):
+		utils.ErrorResponseWithErr(ctx, http.StatusTooManyRequests, err)
+	default:
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+	}
+}
+
 // NotifyUsers godoc
 // @Summary      Send a notification to users
-// @Description  Send a notification to users sent in body
+// @Description  Send a notification to users sent in body, by the channels each user has enabled for its type. It's stored in the inbox unless the user turned that channel off. Mandatory types can't be sent.
 // @Tags         Users
 // @Accept       json
 // @Produce      json
 // @Param        NotificationToken  body  models.NotifyRequest true  "NotificationToken payload"
 // @Success      204       {object}  nil          "Users notified successfully"
 // @Failure      400       {object}  utils.HTTPError  "Invalid request"
+// @Failure      403       {object}  utils.HTTPError  "Mandatory notification type"
 // @Failure      500       {object}  utils.HTTPError  "Internal server error"
 // @Router       /users/notify [post]
 func (c UserController) NotifyUsers(ctx *gin.Context) {
@@ -240,27 +400,21 @@ func (c UserController) NotifyUsers(ctx *gin.Context) {
 		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request format")
 		return
 	}
-	cont := ctx.Request.Context()
+	models.AuditFromContext(ctx).Name("notification.broadcast")
+	models.AuditFromContext(ctx).Change(nil, map[string]any{"users": notifyRequest.Users, "type": notifyRequest.NotificationType})
 	log.Debug(ctx, "notification", slog.Any("request", notifyRequest.Users))
-	for _, userID := range notifyRequest.Users {
-		preference, err := c.service.CheckPreference(cont, userID, notifyRequest.NotificationType)
-		if !preference || err != nil {
-			log.Debug(ctx, "user preference not set or error", slog.Int("userID", userID), slog.Any("error", err))
-			continue
-		}
-		errMobile := c.service.SendNotifByMobile(cont, userID, notifyRequest)
-		if errMobile != nil {
-			utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, errMobile)
-			continue
-		}
-
-		log.Debug(ctx, "notification sent to mobile", slog.Int("userID", userID))
-		errMail := c.service.SendNotifByEmail(cont, userID, notifyRequest)
-		if errMail != nil {
-			utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, errMail)
-			continue
-		}
-		log.Debug(ctx, "notification sent to email", slog.Int("userID", userID))
+	err := c.service.NotifyUsers(ctx.Request.Context(), notifyRequest.Users, notifyRequest)
+	if errors.Is(err, services.ErrUnknownNotificationType) {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if errors.Is(err, services.ErrMandatoryNotificationSend) {
+		utils.ErrorResponseWithErr(ctx, http.StatusForbidden, err)
+		return
+	}
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
 	}
 	ctx.JSON(http.StatusOK, nil)
 }
@@ -326,12 +480,14 @@ func (c UserController) GetUserNotifications(ctx *gin.Context) {
 
 // PasswordReset godoc
 // @Summary      Start the process to reset password
-// @Description  Start the process to reset password, sends and email with a link to make a new password
+// @Description  Start the process to reset password, sends and email with a link to make a new password.
+// @Description  The response is the same whether the email has an account or not
 // @Tags         Users
 // @Accept       json
 // @Produce      json
 // @Param        PasswordResetRequest  body  models.PasswordResetRequest true  "PasswordResetRequest payload"
-// @Success      200       {object}  nil          "Link sent successfully"
+// @Success      200       {object}  nil          "Link sent successfully if the email has an account"
+// @Failure      400       {object}  utils.HTTPError  "Invalid request format"
 // @Failure      500       {object}  utils.HTTPError  "Internal server error"
 // @Router       /users/reset/password [post]
 func (c UserController) PasswordReset(ctx *gin.Context) {
@@ -340,12 +496,7 @@ func (c UserController) PasswordReset(ctx *gin.Context) {
 		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request format")
 		return
 	}
-	user, err := c.service.GetUserByEmail(ctx.Request.Context(), passwordResetRequest.Email)
-	if err != nil {
-		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
-		return
-	}
-	err = c.service.StartPasswordReset(ctx.Request.Context(), user.Id, user.Email)
+	err := c.service.StartPasswordReset(ctx.Request.Context(), passwordResetRequest.Email)
 	if err != nil {
 		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
 		return
@@ -361,7 +512,8 @@ func (c UserController) PasswordReset(ctx *gin.Context) {
 // @Produce      plain
 // @Param        request body models.Rule true "Rule creation Details"
 // @Success      201       {object}  nil          "Rule created correctly"
-// @Failure      400  {object}  utils.HTTPError "Invalid request format"
+// @Success      202       {object}  map[string]models.RuleProposal  "In four-eyes mode, proposal waiting for another admin"
+// @Failure      400  {object}  utils.HTTPError "Invalid request format or application condition"
 // @Failure      500  {object}  utils.HTTPError "Internal server error"
 // @Router       /rules [post]
 // @Security Bearer
@@ -381,11 +533,17 @@ func (c UserController) AddRule(ctx *gin.Context) {
 		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request format")
 		return
 	}
+	models.AuditFromContext(ctx).Name("rule.create")
 	err = c.ruleService.CreateRule(ctx, rule, userId)
+	if errors.Is(err, utils.ErrInvalidExpression) {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
 	if err != nil {
 		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
 		return
 	}
+	models.AuditFromContext(ctx).Change(nil, rule)
 	ctx.JSON(http.StatusCreated, nil)
 }
 
@@ -396,8 +554,13 @@ func (c UserController) AddRule(ctx *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int  true  "Rule ID"
+// @Param        If-Match  header  string  true  "ETag of the rule, the rule version in quotes"
 // @Success      204  {object}  nil  "Rule successfully deleted"
+// @Success      202  {object}  map[string]models.RuleProposal  "In four-eyes mode, proposal waiting for another admin"
 // @Failure      400  {object}  utils.HTTPError  "Invalid user ID format"
+// @Failure      404  {object}  utils.HTTPError  "Rule not found"
+// @Failure      412  {object}  utils.HTTPError  "Rule was modified since the given ETag"
+// @Failure      428  {object}  utils.HTTPError  "Missing If-Match header"
 // @Failure      500  {object}  utils.HTTPError  "Internal server error"
 // @Router       /rules/{id} [delete]
 // @Security Bearer
@@ -418,50 +581,134 @@ func (c UserController) DeleteRule(ctx *gin.Context) {
 		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
 		return
 	}
-	err = c.ruleService.DeleteRule(ctx, id, userId)
+	models.AuditFromContext(ctx).Describe("rule.delete", "rule", id)
+	before := c.auditedRule(ctx, id)
+	err = c.ruleService.DeleteRule(ctx, id, userId, ctx.GetHeader("If-Match"))
 	if err != nil {
-		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		writeRuleWriteError(ctx, err)
 		return
 	}
+	models.AuditFromContext(ctx).Change(before, nil)
 	ctx.JSON(http.StatusOK, nil)
 }
 
 // GetRules godoc
 // @Summary      Get all rules
-// @Description  Returns a list of all rules in the system
+// @Description  Returns a list of all rules in the system, drafts included. With at, only the rules in force at the end of that day
 // @Tags         Rules
 // @Accept       json
 // @Produce      json
+// @Param        status         query   string  false  "Only rules in this stage of their lifecycle"  Enums(draft, scheduled, active, retired)
+// @Param        at             query   string  false  "Only rules in force at the end of this day (YYYY-MM-DD)"
+// @Param        category       query   string  false  "Only rules in this category"  Enums(general, academic, conduct, privacy, communication, evaluation)
+// @Param        tag            query   []string  false  "Only rules with all of these tags"  collectionFormat(multi)
+// @Param        Accept-Language  header  string  false  "Return the title and description in the first of these locales the rule is translated to, without the translations"
+// @Param        If-None-Match  header  string  false  "ETag from a previous response"
 // @Success      200  {object}  map[string][]models.Rule  "List of rules"
+// @Success      304  {object}  nil                      "Rules not modified since the given ETag"
+// @Failure      400  {object}  utils.HTTPError          "Invalid filter"
 // @Failure      500  {object}  utils.HTTPError          "Internal server error"
+// @Header       200  {string}  ETag  "Current version of the rule list"
+// @Header       200  {string}  Content-Language  "Locale of the rules, when Accept-Language was sent"
 // @Router       /rules [get]
 // @Security Bearer
 func (c UserController) GetRules(ctx *gin.Context) {
-	rules, err := c.ruleService.GetRules(ctx)
+	var filter models.RuleListRequest
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	rules, err := c.ruleService.GetRules(ctx, filter)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	writeRules(ctx, rules)
+}
+
+// GetActiveRules godoc
+// @Summary      Get the rules in force
+// @Description  Returns the active rules, the ones every user is bound by. No login is needed
+// @Tags         Rules
+// @Produce      json
+// @Param        Accept-Language  header  string  false  "Return the title and description in the first of these locales the rule is translated to, without the translations"
+// @Param        If-None-Match  header  string  false  "ETag from a previous response"
+// @Success      200  {object}  map[string][]models.Rule  "Active rules"
+// @Success      304  {object}  nil                      "Rules not modified since the given ETag"
+// @Failure      500  {object}  utils.HTTPError          "Internal server error"
+// @Header       200  {string}  ETag  "Current version of the rule list"
+// @Header       200  {string}  Content-Language  "Locale of the rules, when Accept-Language was sent"
+// @Router       /rules/active [get]
+func (c UserController) GetActiveRules(ctx *gin.Context) {
+	rules, err := c.ruleService.GetRules(ctx.Request.Context(), models.RuleListRequest{Status: models.RuleActive})
 	if err != nil {
 		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
 		return
 	}
+	writeRules(ctx, rules)
+}
+
+// writeRules responds with the rules and their ETag, or 304 if the client already has them.
+// With Accept-Language, the rules are localized to the locales the client accepts.
+func writeRules(ctx *gin.Context, rules []models.Rule) {
+	ctx.Header("Vary", "Accept-Language")
+	if header := ctx.GetHeader("Accept-Language"); header != "" {
+		locales := utils.AcceptedLocales(header)
+		languages := []string{}
+		for i, rule := range rules {
+			rules[i] = rule.Localize(locales)
+			if !slices.Contains(languages, rules[i].Locale) {
+				languages = append(languages, rules[i].Locale)
+			}
+		}
+		if len(languages) == 0 {
+			languages = append(languages, models.RuleDefaultLocale)
+		}
+		ctx.Header("Content-Language", strings.Join(languages, ", "))
+	}
+
+	etag := models.RulesETag(rules)
+	ctx.Header("ETag", etag)
+	if utils.MatchesETag(ctx.GetHeader("If-None-Match"), etag) {
+		ctx.AbortWithStatus(http.StatusNotModified)
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{"data": rules})
 }
 
 // GetAudits godoc
-// @Summary      Get all audits
-// @Description  Returns a list of all audits in the system
+// @Summary      Get the rules audit
+// @Description  Lists the changes made to rules, newest first, a page at a time, with the state of the rule before and after each one
 // @Tags         Rules
 // @Accept       json
 // @Produce      json
-// @Success      200  {object}  map[string][]models.AuditData  "List of audits"
+// @Param        rule_id    query  int     false  "Only changes to this rule"
+// @Param        user_id    query  int     false  "Only changes made by this user"
+// @Param        action     query  string  false  "Only this action"  Enums(rule.create, rule.update, rule.delete, rule.rollback, rule.publish, rule.activate, rule.retire)
+// @Param        from       query  string  false  "Only changes on or after this day (YYYY-MM-DD)"
+// @Param        to         query  string  false  "Only changes on or before this day (YYYY-MM-DD)"
+// @Param        page       query  int     false  "Page number, starting at 1"
+// @Param        page_size  query  int     false  "Entries per page, 50 by default and 500 at most"
+// @Success      200  {object}  map[string]interface{}  "Entries (models.AuditData) in data and the page in pagination"
+// @Failure      400  {object}  utils.HTTPError          "Invalid filter"
 // @Failure      500  {object}  utils.HTTPError          "Internal server error"
 // @Router       /rules/audit [get]
 // @Security Bearer
 func (c UserController) GetAudits(ctx *gin.Context) {
-	audits, err := c.ruleService.GetAudits(ctx)
+	var request models.RuleAuditListRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	audits, page, err := c.ruleService.GetAudits(ctx.Request.Context(), request)
 	if err != nil {
 		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{"data": audits})
+	ctx.JSON(http.StatusOK, gin.H{"data": audits, "pagination": page})
 }
 
 // ModifyRule godoc
@@ -471,8 +718,14 @@ func (c UserController) GetAudits(ctx *gin.Context) {
 // @Accept       json
 // @Param        id        path      int         true  "Rule ID"
 // @Param        modifications  body      models.RuleModify  true  "Elements to modify"
+// @Param        If-Match  header  string  true  "ETag of the rule, the rule version in quotes"
 // @Success      200       {object}  nil          "rule updated successfully"
+// @Success      202       {object}  map[string]models.RuleProposal  "In four-eyes mode, proposal waiting for another admin"
 // @Failure      400       {object}  utils.HTTPError  "Invalid user ID format or request"
+// @Failure      404       {object}  utils.HTTPError  "Rule not found"
+// @Failure      409       {object}  utils.HTTPError  "The effective date of a rule in force can't change"
+// @Failure      412       {object}  utils.HTTPError  "Rule was modified since the given ETag"
+// @Failure      428       {object}  utils.HTTPError  "Missing If-Match header"
 // @Failure      500       {object}  utils.HTTPError  "Internal server error"
 // @Router       /rules/{id} [put]
 // @Security Bearer
@@ -498,17 +751,438 @@ func (c UserController) ModifyRule(ctx *gin.Context) {
 		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request format")
 		return
 	}
-	err = c.ruleService.ModifyRule(ctx.Request.Context(), id, rule, userId)
+	models.AuditFromContext(ctx).Describe("rule.update", "rule", id)
+	before := c.auditedRule(ctx, id)
+	err = c.ruleService.ModifyRule(ctx.Request.Context(), id, rule, userId, ctx.GetHeader("If-Match"))
+	if err != nil {
+		writeRuleWriteError(ctx, err)
+		return
+	}
+	models.AuditFromContext(ctx).Change(before, c.auditedRule(ctx, id))
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// GetRuleVersions godoc
+// @Summary      Get the versions of a rule
+// @Description  Returns every version kept of the rule, newest first. Versions of deleted rules are kept too
+// @Tags         Rules
+// @Produce      json
+// @Param        id   path      int  true  "Rule ID"
+// @Success      200  {object}  map[string][]models.RuleVersion  "Versions of the rule"
+// @Failure      400  {object}  utils.HTTPError  "Invalid rule ID"
+// @Failure      404  {object}  utils.HTTPError  "Rule not found"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /rules/{id}/versions [get]
+// @Security Bearer
+func (c UserController) GetRuleVersions(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid rule ID")
+		return
+	}
+
+	versions, err := c.ruleService.GetRuleVersions(ctx.Request.Context(), id)
+	if err != nil {
+		writeRuleWriteError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": versions})
+}
+
+// GetRuleVersionDiff godoc
+// @Summary      Compare two versions of a rule
+// @Description  Lists the fields that changed between the versions, and a word by word diff of the description
+// @Tags         Rules
+// @Produce      json
+// @Param        id       path   int  true   "Rule ID"
+// @Param        v        path   int  true   "Version to compare"
+// @Param        against  query  int  false  "Version to compare it against, by default the one before it"
+// @Success      200  {object}  map[string]models.RuleDiff  "Changes from the against version to v"
+// @Failure      400  {object}  utils.HTTPError  "Invalid rule ID or version"
+// @Failure      404  {object}  utils.HTTPError  "Rule or version not found"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /rules/{id}/versions/{v}/diff [get]
+// @Security Bearer
+func (c UserController) GetRuleVersionDiff(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid rule ID")
+		return
+	}
+	version, err := strconv.Atoi(ctx.Param("v"))
+	if err != nil || version < 1 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid version")
+		return
+	}
+	against := 0
+	if value := ctx.Query("against"); value != "" {
+		against, err = strconv.Atoi(value)
+		if err != nil || against < 1 {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid against version")
+			return
+		}
+	}
+
+	diff, err := c.ruleService.DiffRuleVersions(ctx.Request.Context(), id, version, against)
+	if err != nil {
+		writeRuleWriteError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": diff})
+}
+
+// RollbackRule godoc
+// @Summary      Roll a rule back to an earlier version
+// @Description  Restores the content of the given version as a new version of the rule
+// @Tags         Rules
+// @Produce      json
+// @Param        id        path    int     true  "Rule ID"
+// @Param        v         path    int     true  "Version to restore"
+// @Param        If-Match  header  string  true  "ETag of the rule, the rule version in quotes"
+// @Success      200  {object}  nil  "Rule rolled back"
+// @Success      202  {object}  map[string]models.RuleProposal  "In four-eyes mode, proposal waiting for another admin"
+// @Failure      400  {object}  utils.HTTPError  "Invalid rule ID or version"
+// @Failure      404  {object}  utils.HTTPError  "Rule or version not found"
+// @Failure      409  {object}  utils.HTTPError  "Rule is already at that version, or is in force and the version has another effective date"
+// @Failure      412  {object}  utils.HTTPError  "Rule was modified since the given ETag"
+// @Failure      428  {object}  utils.HTTPError  "Missing If-Match header"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /rules/{id}/rollback/{v} [post]
+// @Security Bearer
+func (c UserController) RollbackRule(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid rule ID")
+		return
+	}
+	version, err := strconv.Atoi(ctx.Param("v"))
+	if err != nil || version < 1 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid version")
+		return
+	}
+
+	claims, err := models.GetClaimsFromGinContext(ctx)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	userId, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	models.AuditFromContext(ctx).Describe("rule.rollback", "rule", id)
+	before := c.auditedRule(ctx, id)
+	err = c.ruleService.RollbackRule(ctx.Request.Context(), id, version, userId, ctx.GetHeader("If-Match"))
+	if err != nil {
+		writeRuleWriteError(ctx, err)
+		return
+	}
+	models.AuditFromContext(ctx).Change(before, c.auditedRule(ctx, id))
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// PublishRule godoc
+// @Summary      Publish a draft rule
+// @Description  Schedules the draft, it comes into force on its effective date and every user is notified then
+// @Tags         Rules
+// @Produce      json
+// @Param        id        path    int     true  "Rule ID"
+// @Param        If-Match  header  string  true  "ETag of the rule, the rule version in quotes"
+// @Success      200  {object}  nil  "Rule scheduled"
+// @Success      202  {object}  map[string]models.RuleProposal  "In four-eyes mode, proposal waiting for another admin"
+// @Failure      400  {object}  utils.HTTPError  "Invalid rule ID"
+// @Failure      404  {object}  utils.HTTPError  "Rule not found"
+// @Failure      409  {object}  utils.HTTPError  "Rule is not a draft"
+// @Failure      412  {object}  utils.HTTPError  "Rule was modified since the given ETag"
+// @Failure      428  {object}  utils.HTTPError  "Missing If-Match header"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /rules/{id}/publish [post]
+// @Security Bearer
+func (c UserController) PublishRule(ctx *gin.Context) {
+	c.setRuleStatus(ctx, models.RuleScheduled, "rule.publish")
+}
+
+// RetireRule godoc
+// @Summary      Retire a rule
+// @Description  Takes a scheduled or active rule out of force from now on
+// @Tags         Rules
+// @Produce      json
+// @Param        id        path    int     true  "Rule ID"
+// @Param        If-Match  header  string  true  "ETag of the rule, the rule version in quotes"
+// @Success      200  {object}  nil  "Rule retired"
+// @Success      202  {object}  map[string]models.RuleProposal  "In four-eyes mode, proposal waiting for another admin"
+// @Failure      400  {object}  utils.HTTPError  "Invalid rule ID"
+// @Failure      404  {object}  utils.HTTPError  "Rule not found"
+// @Failure      409  {object}  utils.HTTPError  "Rule is a draft or already retired"
+// @Failure      412  {object}  utils.HTTPError  "Rule was modified since the given ETag"
+// @Failure      428  {object}  utils.HTTPError  "Missing If-Match header"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /rules/{id}/retire [post]
+// @Security Bearer
+func (c UserController) RetireRule(ctx *gin.Context) {
+	c.setRuleStatus(ctx, models.RuleRetired, "rule.retire")
+}
+
+func (c UserController) setRuleStatus(ctx *gin.Context, status models.RuleStatus, action string) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid rule ID")
+		return
+	}
+
+	claims, err := models.GetClaimsFromGinContext(ctx)
 	if err != nil {
 		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
 		return
 	}
+	userId, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	models.AuditFromContext(ctx).Describe(action, "rule", id)
+	before := c.auditedRule(ctx, id)
+	err = c.ruleService.SetRuleStatus(ctx.Request.Context(), id, status, userId, ctx.GetHeader("If-Match"))
+	if err != nil {
+		writeRuleWriteError(ctx, err)
+		return
+	}
+	models.AuditFromContext(ctx).Change(before, c.auditedRule(ctx, id))
 	ctx.JSON(http.StatusOK, nil)
 }
 
+// AcceptRule godoc
+// @Summary      Accept a rule
+// @Description  Records that the logged in user accepted the current version of an active rule, along with when and from which IP
+// @Tags         Rules
+// @Produce      json
+// @Param        id        path    int     true  "Rule ID"
+// @Param        If-Match  header  string  true  "ETag of the rule the user read, the rule version in quotes"
+// @Success      200  {object}  map[string]models.RuleAcceptance  "Acceptance recorded"
+// @Failure      400  {object}  utils.HTTPError  "Invalid rule ID"
+// @Failure      404  {object}  utils.HTTPError  "Rule not found"
+// @Failure      409  {object}  utils.HTTPError  "Rule is not active"
+// @Failure      412  {object}  utils.HTTPError  "Rule was modified since the given ETag"
+// @Failure      428  {object}  utils.HTTPError  "Missing If-Match header"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /rules/{id}/accept [post]
+// @Security Bearer
+func (c UserController) AcceptRule(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid rule ID")
+		return
+	}
+
+	claims, err := models.GetClaimsFromGinContext(ctx)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	userId, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	acceptance, err := c.ruleService.AcceptRule(ctx.Request.Context(), id, userId, ctx.GetHeader("If-Match"), ctx.ClientIP())
+	if err != nil {
+		writeRuleWriteError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": acceptance})
+}
+
+// GetPendingRules godoc
+// @Summary      Get the rules the user must accept
+// @Description  Returns the mandatory rules the logged in user hasn't accepted in their current version
+// @Tags         Rules
+// @Produce      json
+// @Success      200  {object}  map[string][]models.PendingRule  "Rules to accept"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /rules/pending [get]
+// @Security Bearer
+func (c UserController) GetPendingRules(ctx *gin.Context) {
+	claims, err := models.GetClaimsFromGinContext(ctx)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	userId, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	pending, err := c.ruleService.GetPendingRules(ctx.Request.Context(), userId)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": pending})
+}
+
+// GetRuleAcceptanceReport godoc
+// @Summary      Get the acceptance coverage of the rules
+// @Description  For every active rule, how many of the users bound by the rules accepted its current version, only an earlier one, or none
+// @Tags         Admin
+// @Produce      json
+// @Success      200  {object}  map[string]models.RuleAcceptanceReport  "Coverage of each rule"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /admin/rules/acceptance [get]
+// @Security Bearer
+func (c UserController) GetRuleAcceptanceReport(ctx *gin.Context) {
+	report, err := c.ruleService.GetAcceptanceReport(ctx.Request.Context())
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// GetApplicableRules godoc
+// @Summary      Get the rules that apply to a user
+// @Description  Evaluates the application condition of every active rule against the user. Rules whose condition can't be evaluated are listed in unevaluable.
+// @Tags         Rules
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  map[string]models.ApplicableRules  "Rules that apply to the user"
+// @Failure      400  {object}  utils.HTTPError  "Invalid user ID format"
+// @Failure      404  {object}  utils.HTTPError  "User not found"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /users/{id}/applicable-rules [get]
+// @Security Bearer
+func (c UserController) GetApplicableRules(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	user, err := c.service.GetUserById(ctx.Request.Context(), id)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "User not found")
+		return
+	}
+
+	applicable, err := c.ruleService.GetApplicableRules(ctx.Request.Context(), *user)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": applicable})
+}
+
+// EvaluateRuleCondition godoc
+// @Summary      Evaluate an application condition
+// @Description  Evaluates a condition against a user, or against the given attributes, without saving anything. Times are given as dates (2006-01-02) or RFC 3339 timestamps.
+// @Tags         Rules
+// @Accept       json
+// @Produce      json
+// @Param        request body models.RuleEvaluateRequest true "Condition and who to evaluate it for"
+// @Success      200  {object}  map[string]models.RuleEvaluateResponse  "Whether the condition holds"
+// @Failure      400  {object}  utils.HTTPError  "Invalid request format, condition or attributes"
+// @Failure      403  {object}  utils.HTTPError  "Evaluating for another user without being an admin"
+// @Failure      404  {object}  utils.HTTPError  "User not found"
+// @Failure      500  {object}  utils.HTTPError  "Internal server error"
+// @Router       /rules/evaluate [post]
+// @Security Bearer
+func (c UserController) EvaluateRuleCondition(ctx *gin.Context) {
+	var request models.RuleEvaluateRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+	if (request.UserId == nil) == (request.Attributes == nil) {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Either user_id or attributes must be given, but not both")
+		return
+	}
+
+	var vars map[string]any
+	if request.UserId != nil {
+		claims, err := models.GetClaimsFromGinContext(ctx)
+		if err != nil {
+			utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		// Attributes of other users, like their email, could be guessed one condition at a time
+		if claims.Role != "admin" && claims.Subject != strconv.Itoa(*request.UserId) {
+			utils.ErrorResponse(ctx, http.StatusForbidden, "Only admins can evaluate conditions for other users")
+			return
+		}
+		user, err := c.service.GetUserById(ctx.Request.Context(), *request.UserId)
+		if err != nil {
+			utils.ErrorResponse(ctx, http.StatusNotFound, "User not found")
+			return
+		}
+		vars = models.RuleConditionVars(*user)
+	} else {
+		var err error
+		vars, err = models.RuleConditionAttributes(request.Attributes)
+		if err != nil {
+			utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	applies, err := c.ruleService.EvaluateCondition(ctx.Request.Context(), request.Condition, vars)
+	if errors.Is(err, utils.ErrInvalidExpression) {
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": models.RuleEvaluateResponse{Condition: request.Condition, Applies: applies}})
+}
+
+// auditedUser returns the user as it is now, to record it in the audit of the request. Nothing is
+// loaded for requests that aren't audited, and errors are left to the action to report.
+func (c UserController) auditedUser(ctx *gin.Context, id int) *models.User {
+	if models.AuditFromContext(ctx) == nil {
+		return nil
+	}
+	user, err := c.service.GetUserById(ctx.Request.Context(), id)
+	if err != nil {
+		return nil
+	}
+	return user
+}
+
+// auditedRule is auditedUser for rules
+func (c UserController) auditedRule(ctx *gin.Context, id int) *models.Rule {
+	if models.AuditFromContext(ctx) == nil {
+		return nil
+	}
+	rule, err := c.ruleService.GetRule(ctx.Request.Context(), id)
+	if err != nil {
+		return nil
+	}
+	return rule
+}
+
+// writeRuleWriteError maps errors from conditional rule writes to their HTTP status
+func writeRuleWriteError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Rule not found")
+	case errors.Is(err, utils.ErrInvalidExpression), errors.Is(err, services.ErrEmptyModification):
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+	case errors.Is(err, repositories.ErrVersionMismatch):
+		utils.ErrorResponseWithErr(ctx, http.StatusPreconditionFailed, err)
+	case errors.Is(err, services.ErrRuleAlreadyAtVersion), errors.Is(err, services.ErrInvalidRuleTransition),
+		errors.Is(err, services.ErrRuleInForce), errors.Is(err, services.ErrRuleNotActive):
+		utils.ErrorResponseWithErr(ctx, http.StatusConflict, err)
+	default:
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+	}
+}
+
 // ModifyNotifPreference godoc
 // @Summary      Modify the preference of a notification type
-// @Description  Modify the preference of a notification type between exam_notification homework_notification or social_notification
+// @Description  Turn a notification type on or off by every channel. Use PUT /users/{id}/notifications/preferences to set each channel.
 // @Tags         Users
 // @Accept       json
 // @Produce      json
@@ -516,7 +1190,8 @@ func (c UserController) ModifyRule(ctx *gin.Context) {
 // @Param        NotificationPreferenceRequest  body  models.NotificationPreferenceRequest true  "NotificationPreferenceRequest payload"
 // @Success      200       {object}  nil          "preference changed successfully"
 // @Failure      500       {object}  utils.HTTPError  "Internal server error"
-// @Failure      400       {object}  utils.HTTPError  "Invalid request format"
+// @Failure      400       {object}  utils.HTTPError  "Invalid request format or unknown or mandatory notification type"
+// @Deprecated
 // @Router      /users/:id/notifications/preference [put]
 func (c UserController) ModifyNotifPreference(ctx *gin.Context) {
 	id, err := strconv.Atoi(ctx.Param("id"))
@@ -531,15 +1206,15 @@ func (c UserController) ModifyNotifPreference(ctx *gin.Context) {
 	}
 	err = c.service.SetNotificationPreference(ctx.Request.Context(), id, notifPreference)
 	if err != nil {
-		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		writeNotificationPreferencesError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, nil)
 }
 
 // GetNotifPreferences godoc
-// @Summary      Modify the preference of a notification type
-// @Description  Modify the preference of a notification type between exam_notification homework_notification or social_notification
+// @Summary      Get the preferences of the original notification types
+// @Description  Tells whether exam_notification, homework_notification and social_notification are sent by any channel. Use GET /users/{id}/notifications/preferences for every type by channel.
 // @Tags         Users
 // @Accept       json
 // @Produce      json
@@ -547,6 +1222,7 @@ func (c UserController) ModifyNotifPreference(ctx *gin.Context) {
 // @Success      200       {object}  models.NotificationPreference          "preferences"
 // @Failure      500       {object}  utils.HTTPError  "Internal server error"
 // @Failure      400       {object}  utils.HTTPError  "Invalid request format"
+// @Deprecated
 // @Router      /users/:id/notifications/preference [get]
 func (c UserController) GetNotifPreferences(ctx *gin.Context) {
 	id, err := strconv.Atoi(ctx.Param("id"))
@@ -562,6 +1238,135 @@ func (c UserController) GetNotifPreferences(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, preferences)
 }
 
+// GetNotificationSettings godoc
+// @Summary      Get the notification preferences matrix
+// @Description  Returns by which channels the user gets every notification type, along with the registered types and the channels. Mandatory types are on by every channel.
+// @Tags         Users
+// @Produce      json
+// @Param        id        path      int         true  "User ID"
+// @Success      200       {object}  models.NotificationSettings  "Preferences matrix"
+// @Failure      400       {object}  utils.HTTPError  "Invalid user ID"
+// @Failure      500       {object}  utils.HTTPError  "Internal server error"
+// @Router       /users/{id}/notifications/preferences [get]
+func (c UserController) GetNotificationSettings(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	settings, err := c.service.GetNotificationSettings(ctx.Request.Context(), id)
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": settings})
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary      Update the notification preferences matrix
+// @Description  Sets whether the user gets each notification type by each channel. Only the cells sent are changed, nothing is saved if any of them is invalid.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int         true  "User ID"
+// @Param        request   body      models.NotificationPreferencesRequest  true  "Preferences by type and channel"
+// @Success      200       {object}  models.NotificationSettings  "Whole preferences matrix"
+// @Failure      400       {object}  utils.HTTPError  "Invalid request, unknown type or channel, or mandatory type turned off"
+// @Failure      500       {object}  utils.HTTPError  "Internal server error"
+// @Router       /users/{id}/notifications/preferences [put]
+func (c UserController) UpdateNotificationPreferences(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	var request models.NotificationPreferencesRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+	settings, err := c.service.UpdateNotificationPreferences(ctx.Request.Context(), id, request.Preferences)
+	if err != nil {
+		writeNotificationPreferencesError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": settings})
+}
+
+func writeNotificationPreferencesError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnknownNotificationType), errors.Is(err, services.ErrUnknownNotificationChannel),
+		errors.Is(err, services.ErrMandatoryNotification):
+		utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+	default:
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+	}
+}
+
+// GetNotificationTypes godoc
+// @Summary      List the notification types
+// @Description  Returns the registry of notification types, users choose by which channels they get each one
+// @Tags         Users
+// @Produce      json
+// @Success      200       {array}   models.NotificationType  "Notification types"
+// @Failure      500       {object}  utils.HTTPError  "Internal server error"
+// @Router       /notifications/types [get]
+func (c UserController) GetNotificationTypes(ctx *gin.Context) {
+	types, err := c.service.GetNotificationTypes(ctx.Request.Context())
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": types})
+}
+
+// AddNotificationType godoc
+// @Summary      Register a notification type
+// @Description  Adds a notification type to the registry, users get it by every channel until they turn it off. Mandatory types can't be turned off.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        request   body      models.NotificationType  true  "Notification type"
+// @Success      201       {object}  models.NotificationType  "Registered type"
+// @Failure      400       {object}  utils.HTTPError  "Invalid request format"
+// @Failure      409       {object}  utils.HTTPError  "There's a type with the name"
+// @Failure      500       {object}  utils.HTTPError  "Internal server error"
+// @Router       /admin/notifications/types [post]
+func (c UserController) AddNotificationType(ctx *gin.Context) {
+	var notificationType models.NotificationType
+	if err := ctx.ShouldBindJSON(&notificationType); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+	models.AuditFromContext(ctx).Name("notification_type.create")
+	models.AuditFromContext(ctx).Change(nil, notificationType)
+	err := c.service.AddNotificationType(ctx.Request.Context(), &notificationType)
+	if errors.Is(err, repositories.ErrNotificationTypeExists) {
+		utils.ErrorResponseWithErr(ctx, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": notificationType})
+}
+
+var resetRedirectTemplate = template.Must(template.New("reset-redirect").Parse(`<!DOCTYPE html>
+<html>
+	<head>
+		<title>Redirecting...</title>
+		<meta http-equiv="refresh" content="0; url={{.}}" />
+		<script>
+			window.location.href = {{.}};
+		</script>
+	</head>
+	<body>
+		<p>If you're not redirected, <a href="{{.}}">click here</a>.</p>
+	</body>
+</html>
+`))
+
 func (c UserController) PasswordResetRedirect(ctx *gin.Context) {
 	token := ctx.Query("token")
 	if token == "" {
@@ -569,23 +1374,190 @@ func (c UserController) PasswordResetRedirect(ctx *gin.Context) {
 		return
 	}
 
-	deepLink := fmt.Sprintf("myapp://reset-password?token=%s", token)
+	// The token is query-escaped so the deep link can be trusted as a URL by the template,
+	// which escapes it for each context it appears in
+	deepLink := template.URL("myapp://reset-password?token=" + url.QueryEscape(token))
+
+	var page bytes.Buffer
+	if err := resetRedirectTemplate.Execute(&page, deepLink); err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+}
+
+// RequestEmailChange godoc
+// @Summary      Request an email change
+// @Description  Sends a confirmation pin to the new email and a cancel link to the current one. The email is only changed once the pin is confirmed
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                             true  "User ID"
+// @Param        request  body      models.EmailChangeStartRequest  true  "New email"
+// @Success      202      {object}  map[string]string  "Confirmation pin sent"
+// @Failure      400      {object}  utils.HTTPError  "Invalid request or same email as the current one"
+// @Failure      404      {object}  utils.HTTPError  "User not found"
+// @Failure      409      {object}  utils.HTTPError  "Email already exists"
+// @Failure      500      {object}  utils.HTTPError  "Internal server error"
+// @Router       /users/{id}/email [post]
+// @Security Bearer
+func (c UserController) RequestEmailChange(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	var request models.EmailChangeStartRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	err = c.service.RequestEmailChange(ctx.Request.Context(), id, request.Email)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSameEmail):
+			utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		case errors.Is(err, repositories.ErrNotFound):
+			utils.ErrorResponse(ctx, http.StatusNotFound, "User not found")
+		case errors.Is(err, repositories.ErrEmailTaken):
+			utils.ErrorResponse(ctx, http.StatusConflict, "Email already exists")
+		default:
+			utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "Confirmation pin sent to the new email"})
+}
+
+// ConfirmEmailChange godoc
+// @Summary      Confirm an email change
+// @Description  Applies a pending email change using the pin sent to the new email. Every session of the user is closed and pending password resets are discarded
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                               true  "User ID"
+// @Param        request  body      models.EmailChangeConfirmRequest  true  "Confirmation pin"
+// @Success      200      {object}  map[string]string  "Email changed"
+// @Failure      400      {object}  utils.HTTPError  "Invalid request or pin"
+// @Failure      409      {object}  utils.HTTPError  "Email already exists"
+// @Failure      410      {object}  utils.HTTPError  "Pin expired"
+// @Failure      429      {object}  utils.HTTPError  "Too many attempts"
+// @Failure      500      {object}  utils.HTTPError  "Internal server error"
+// @Router       /users/{id}/email/confirm [post]
+// @Security Bearer
+func (c UserController) ConfirmEmailChange(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	var request models.EmailChangeConfirmRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	err = c.service.ConfirmEmailChange(ctx.Request.Context(), id, request.VerificationPin)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidPin):
+			utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		case errors.Is(err, services.ErrPinExpired):
+			utils.ErrorResponseWithErr(ctx, http.StatusGone, err)
+		case errors.Is(err, services.ErrPinAttemptsExceeded):
+			utils.ErrorResponseWithErr(ctx, http.StatusTooManyRequests, err)
+		case errors.Is(err, repositories.ErrEmailTaken):
+			utils.ErrorResponse(ctx, http.StatusConflict, "Email already exists")
+		default:
+			utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	// The current session was revoked along with the others
+	ctx.SetCookie("Authorization", "", -1, "/", "", false, true)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Email changed, please log in again"})
+}
+
+// CancelEmailChange godoc
+// @Summary      Cancel an email change
+// @Description  Cancels a pending email change using the link sent to the current email
+// @Tags         Users
+// @Produce      plain
+// @Param        token  query     string  true  "Cancel token"
+// @Success      200    {string}  string  "Email change cancelled"
+// @Failure      400    {string}  string  "Missing token"
+// @Failure      404    {string}  string  "No pending email change for the token"
+// @Failure      500    {object}  utils.HTTPError  "Internal server error"
+// @Router       /users/email/cancel [get]
+func (c UserController) CancelEmailChange(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		ctx.String(http.StatusBadRequest, "Missing token")
+		return
+	}
+
+	err := c.service.CancelEmailChange(ctx.Request.Context(), token)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			ctx.String(http.StatusNotFound, "This email change was already confirmed, cancelled or never existed")
+			return
+		}
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		return
+	}
 
-	html := fmt.Sprintf(`
-		<!DOCTYPE html>
-		<html>
-			<head>
-				<title>Redirecting...</title>
-				<meta http-equiv="refresh" content="0; url=%s" />
-				<script>
-					window.location.href = "%s";
-				</script>
-			</head>
-			<body>
-				<p>If you're not redirected, <a href="%s">click here</a>.</p>
-			</body>
-		</html>
-	`, deepLink, deepLink, deepLink)
+	ctx.String(http.StatusOK, "Email change cancelled")
+}
 
-	ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(html))
+// ChangePassword godoc
+// @Summary      Change password
+// @Description  Changes the password of a user who knows the current one. The new password must follow the password policy
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                           true  "User ID"
+// @Param        request   body      models.PasswordChangeRequest  true  "Current and new password"
+// @Success      200       {object}  nil              "Password changed"
+// @Failure      400       {object}  utils.HTTPError  "Invalid request or password rejected by the password policy"
+// @Failure      403       {object}  utils.HTTPError  "Current password is incorrect"
+// @Failure      404       {object}  utils.HTTPError  "User not found"
+// @Failure      500       {object}  utils.HTTPError  "Internal server error"
+// @Router       /users/{id}/password [put]
+// @Security Bearer
+func (c UserController) ChangePassword(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	var request models.PasswordChangeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	models.AuditFromContext(ctx).Describe("user.change_password", "user", id)
+	err = c.passwordService.ChangePassword(ctx.Request.Context(), id, request.CurrentPassword, request.NewPassword)
+	if err != nil {
+		var validationErrs models.ValidationErrors
+		switch {
+		case errors.As(err, &validationErrs):
+			utils.ErrorResponseWithErr(ctx, http.StatusBadRequest, err)
+		case errors.Is(err, services.ErrWrongPassword):
+			utils.ErrorResponseWithErr(ctx, http.StatusForbidden, err)
+		case errors.Is(err, repositories.ErrNotFound):
+			utils.ErrorResponse(ctx, http.StatusNotFound, "User not found")
+		default:
+			utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
 }
@@ -6,9 +6,12 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -31,14 +34,25 @@ func setupTest(t *testing.T) (*s.MockUserService, *s.MockRulesService, *gin.Cont
 	mockRulesService := s.NewMockRulesService(t)
 	recorder := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(recorder)
-	userController := controller.CreateController(mockService, mockRulesService)
+	userController := controller.CreateController(mockService, mockRulesService, s.NewMockPasswordService(t))
 	return mockService, mockRulesService, c, recorder, userController
 }
 
+func setupPasswordTest(t *testing.T) (*s.MockUserService, *s.MockPasswordService, *gin.Context, *httptest.ResponseRecorder, *controller.UserController) {
+	gin.SetMode(gin.TestMode)
+	mockService := s.NewMockUserService(t)
+	mockPasswordService := s.NewMockPasswordService(t)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	userController := controller.CreateController(mockService, s.NewMockRulesService(t), mockPasswordService)
+	return mockService, mockPasswordService, c, recorder, userController
+}
+
 func TestCreateController(t *testing.T) {
 	mockService := s.NewMockUserService(t)
 	mockRulesService := s.NewMockRulesService(t)
-	result := controller.CreateController(mockService, mockRulesService)
+	mockPasswordService := s.NewMockPasswordService(t)
+	result := controller.CreateController(mockService, mockRulesService, mockPasswordService)
 	assert.NotNil(t, result)
 }
 
@@ -63,7 +77,7 @@ func TestUsersGet(t *testing.T) {
 		},
 	}
 
-	mockService.EXPECT().GetAllUsers(mock.Anything).Return(expectedUsers, nil)
+	mockService.EXPECT().GetAllUsers(mock.Anything, models.UserFilter{}).Return(expectedUsers, nil)
 
 	// Call the function
 	userController.UsersGet(c)
@@ -81,13 +95,39 @@ func TestUsersGet(t *testing.T) {
 	assert.Equal(t, expectedUsers[0].Name, response.Data[0].Name)
 }
 
+func TestUsersGet_Filtered(t *testing.T) {
+	mockService, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/users?role=student&verified=true&q=ana&created_from=2025-01-01", nil)
+
+	verified := true
+	createdFrom := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockService.EXPECT().
+		GetAllUsers(mock.Anything, models.UserFilter{Role: "student", Verified: &verified, Search: "ana", CreatedFrom: &createdFrom}).
+		Return([]models.User{{Id: 1, Name: "Ana"}}, nil)
+
+	userController.UsersGet(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestUsersGet_InvalidFilter(t *testing.T) {
+	_, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/users?verified=maybe", nil)
+
+	userController.UsersGet(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
 func TestUsersGet_Error(t *testing.T) {
 	mockService, _, c, recorder, userController := setupTest(t)
 
 	c.Request = httptest.NewRequest(http.MethodGet, "/users", nil)
 
 	mockService.EXPECT().
-		GetAllUsers(mock.Anything).
+		GetAllUsers(mock.Anything, models.UserFilter{}).
 		Return(nil, errors.New("database error"))
 
 	userController.UsersGet(c)
@@ -136,6 +176,23 @@ func TestUserGetById_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser.Id, response.Data.Id)
 	assert.Equal(t, expectedUser.Name, response.Data.Name)
+	assert.Equal(t, expectedUser.ETag(), recorder.Header().Get("ETag"))
+}
+
+func TestUserGetById_NotModified(t *testing.T) {
+	mockService, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	c.Request.Header.Set("If-None-Match", `"4"`)
+	c.AddParam("id", "1")
+
+	mockService.EXPECT().GetUserById(mock.Anything, 1).Return(&models.User{Id: 1, Version: 4}, nil)
+
+	userController.UserGetById(c)
+
+	assert.Equal(t, http.StatusNotModified, recorder.Code)
+	assert.Equal(t, `"4"`, recorder.Header().Get("ETag"))
+	assert.Empty(t, recorder.Body.String())
 }
 
 func TestUserGetById_NotFound(t *testing.T) {
@@ -174,13 +231,42 @@ func TestUserDeleteById_Success(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodDelete, "/users/"+strconv.Itoa(userId), nil)
 	c.AddParam("id", strconv.Itoa(userId))
 
-	mockService.EXPECT().DeleteUser(mock.Anything, userId).Return(nil)
+	c.Request.Header.Set("If-Match", `"3"`)
+
+	mockService.EXPECT().DeleteUser(mock.Anything, userId, `"3"`).Return(nil)
 
 	userController.UserDeleteById(c)
 
 	assert.Equal(t, http.StatusNoContent, recorder.Code)
 }
 
+func TestUserDeleteById_PreconditionFailed(t *testing.T) {
+	mockService, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodDelete, "/users/1", nil)
+	c.Request.Header.Set("If-Match", `"2"`)
+	c.AddParam("id", "1")
+
+	mockService.EXPECT().DeleteUser(mock.Anything, 1, `"2"`).Return(repositories.ErrVersionMismatch)
+
+	userController.UserDeleteById(c)
+
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+}
+
+func TestUserDeleteById_NotFound(t *testing.T) {
+	mockService, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodDelete, "/users/1", nil)
+	c.AddParam("id", "1")
+
+	mockService.EXPECT().DeleteUser(mock.Anything, 1, mock.Anything).Return(repositories.ErrNotFound)
+
+	userController.UserDeleteById(c)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
 func TestUserDeleteById_WrongPathParam(t *testing.T) {
 	_, _, c, recorder, userController := setupTest(t)
 
@@ -211,7 +297,7 @@ func TestUserDeleteById_ServiceError(t *testing.T) {
 	c.AddParam("id", strconv.Itoa(userId))
 
 	mockService.EXPECT().
-		DeleteUser(mock.Anything, userId).
+		DeleteUser(mock.Anything, userId, mock.Anything).
 		Return(errors.New("delete failed"))
 
 	userController.UserDeleteById(c)
@@ -242,19 +328,68 @@ func TestModifyUser_Success(t *testing.T) {
 	jsonValue, _ := json.Marshal(updatedUserDto)
 	c.Request = httptest.NewRequest(http.MethodPut, "/users/1", bytes.NewBuffer(jsonValue))
 	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("If-Match", `"1"`)
 	c.AddParam("id", "1")
 
 	mockService.EXPECT().ModifyUser(mock.Anything, 1, mock.MatchedBy(func(u models.UserUpdateDto) bool {
 		return u.Name == updatedUserDto.Name &&
 			u.Surname == updatedUserDto.Surname &&
 			u.Location == updatedUserDto.Location
-	})).Return(nil)
+	}), `"1"`).Return(&models.User{Id: 1, Name: "Updated", Surname: "User", Location: "New Location", Email: "test@example.com", Version: 2}, nil)
 
 	// Call the function
 	userController.ModifyUser(c)
 
 	// Check response
 	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data models.User `json:"data"`
+	}
+	err := json.Unmarshal(recorder.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "test@example.com", response.Data.Email)
+	assert.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+}
+
+func TestModifyUser_AuditsBeforeAndAfter(t *testing.T) {
+	mockService, _, c, recorder, userController := setupTest(t)
+
+	jsonValue, _ := json.Marshal(models.UserUpdateDto{Name: "Updated"})
+	c.Request = httptest.NewRequest(http.MethodPut, "/users/1", bytes.NewBuffer(jsonValue))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("If-Match", `"1"`)
+	c.AddParam("id", "1")
+	details := &models.AuditDetails{}
+	c.Set(models.AuditDetailsKey, details)
+
+	before := &models.User{Id: 1, Name: "Old", Version: 1}
+	after := &models.User{Id: 1, Name: "Updated", Version: 2}
+	mockService.EXPECT().GetUserById(mock.Anything, 1).Return(before, nil)
+	mockService.EXPECT().ModifyUser(mock.Anything, 1, mock.Anything, `"1"`).Return(after, nil)
+
+	userController.ModifyUser(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "user.update", details.Action)
+	assert.Equal(t, before, details.Before)
+	assert.Equal(t, after, details.After)
+}
+
+func TestModifyUser_PreconditionFailed(t *testing.T) {
+	mockService, _, c, recorder, userController := setupTest(t)
+
+	jsonValue, _ := json.Marshal(models.UserUpdateDto{Name: "Updated"})
+	c.Request = httptest.NewRequest(http.MethodPut, "/users/1", bytes.NewBuffer(jsonValue))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("If-Match", `"1"`)
+	c.AddParam("id", "1")
+
+	mockService.EXPECT().ModifyUser(mock.Anything, 1, mock.Anything, `"1"`).Return(nil, repositories.ErrVersionMismatch)
+
+	userController.ModifyUser(c)
+
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
 }
 
 func TestModifyUser_WrongPathParam(t *testing.T) {
@@ -316,8 +451,8 @@ func TestModifyUser_ServiceError(t *testing.T) {
 	c.AddParam("id", "1")
 
 	mockService.EXPECT().
-		ModifyUser(mock.Anything, 1, updatedUserDto).
-		Return(errors.New("update failed"))
+		ModifyUser(mock.Anything, 1, updatedUserDto, mock.Anything).
+		Return(nil, errors.New("update failed"))
 
 	userController.ModifyUser(c)
 
@@ -335,6 +470,142 @@ func TestModifyUser_ServiceError(t *testing.T) {
 	assert.Equal(t, "update failed", resp.Error)
 }
 
+func TestPatchUser_Success(t *testing.T) {
+	mockService, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodPatch, "/users/1", bytes.NewBufferString(`{"name": "Updated", "location": null}`))
+	c.Request.Header.Set("Content-Type", "application/merge-patch+json")
+	c.Request.Header.Set("If-Match", `"1"`)
+	c.AddParam("id", "1")
+
+	mockService.EXPECT().PatchUser(mock.Anything, 1, mock.MatchedBy(func(p models.UserPatch) bool {
+		return p.Name.Set && p.Name.Value == "Updated" &&
+			p.Location.Set && p.Location.Null &&
+			!p.Surname.Set && !p.Description.Set && !p.ProfilePhoto.Set
+	}), `"1"`).Return(&models.User{Id: 1, Name: "Updated", Surname: "User", Email: "test@example.com"}, nil)
+
+	userController.PatchUser(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data models.User `json:"data"`
+	}
+	err := json.Unmarshal(recorder.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "Updated", response.Data.Name)
+	assert.Equal(t, "test@example.com", response.Data.Email)
+	assert.Empty(t, response.Data.Location)
+}
+
+func TestPatchUser_WrongPathParam(t *testing.T) {
+	_, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodPatch, "/users/abc", nil)
+	c.AddParam("id", "abc")
+
+	userController.PatchUser(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestPatchUser_UnsupportedContentType(t *testing.T) {
+	_, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodPatch, "/users/1", bytes.NewBufferString(`{"name": "Updated"}`))
+	c.Request.Header.Set("Content-Type", "text/plain")
+	c.AddParam("id", "1")
+
+	userController.PatchUser(c)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+}
+
+func TestPatchUser_NotAnObject(t *testing.T) {
+	_, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodPatch, "/users/1", bytes.NewBufferString(`["name"]`))
+	c.Request.Header.Set("Content-Type", "application/merge-patch+json")
+	c.AddParam("id", "1")
+
+	userController.PatchUser(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestPatchUser_UnknownField(t *testing.T) {
+	_, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodPatch, "/users/1", bytes.NewBufferString(`{"email": "other@example.com"}`))
+	c.Request.Header.Set("Content-Type", "application/merge-patch+json")
+	c.AddParam("id", "1")
+
+	userController.PatchUser(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestPatchUser_ValidationError(t *testing.T) {
+	_, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodPatch, "/users/1", bytes.NewBufferString(`{"name": null, "profile_photo": "not a url"}`))
+	c.Request.Header.Set("Content-Type", "application/merge-patch+json")
+	c.AddParam("id", "1")
+
+	userController.PatchUser(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	var resp utils.HTTPError
+	err := json.Unmarshal(recorder.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Contains(t, resp.Error, "name: cannot be cleared")
+	assert.Contains(t, resp.Error, "profile_photo: must be an absolute http or https URL")
+}
+
+func TestPatchUser_NotFound(t *testing.T) {
+	mockService, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodPatch, "/users/1", bytes.NewBufferString(`{"description": null}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.AddParam("id", "1")
+
+	mockService.EXPECT().PatchUser(mock.Anything, 1, mock.Anything, mock.Anything).Return(nil, repositories.ErrNotFound)
+
+	userController.PatchUser(c)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestPatchUser_PreconditionFailed(t *testing.T) {
+	mockService, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodPatch, "/users/1", bytes.NewBufferString(`{"description": "new"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("If-Match", `"1-blocked"`)
+	c.AddParam("id", "1")
+
+	mockService.EXPECT().PatchUser(mock.Anything, 1, mock.Anything, `"1-blocked"`).Return(nil, repositories.ErrVersionMismatch)
+
+	userController.PatchUser(c)
+
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+}
+
+func TestPatchUser_ServiceError(t *testing.T) {
+	mockService, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodPatch, "/users/1", bytes.NewBufferString(`{"description": "new"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.AddParam("id", "1")
+
+	mockService.EXPECT().PatchUser(mock.Anything, 1, mock.Anything, mock.Anything).Return(nil, errors.New("update failed"))
+
+	userController.PatchUser(c)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
 func TestBlockUserById_Success(t *testing.T) {
 	mockService, _, c, recorder, userController := setupTest(t)
 
@@ -403,7 +674,7 @@ func TestBlockUserById_ServiceError(t *testing.T) {
 }
 
 func TestUserController_ModifyPassword(t *testing.T) {
-	mockService, _, c, recorder, controller := setupTest(t)
+	mockService, mockPasswordService, c, recorder, controller := setupPasswordTest(t)
 
 	newPassword := "TEST_PASSWORD"
 
@@ -414,6 +685,7 @@ func TestUserController_ModifyPassword(t *testing.T) {
 		Password: newPassword,
 	}
 	expectedPasswordResetData := models.PasswordResetData{
+		Id:     7,
 		Email:  "TEST_EMAIL",
 		UserId: 1,
 		Exp:    time.Now(),
@@ -427,8 +699,7 @@ func TestUserController_ModifyPassword(t *testing.T) {
 	c.Request = req
 
 	mockService.On("ValidatePasswordResetToken", c.Request.Context(), expectedRequest.Token).Return(&expectedPasswordResetData, nil)
-	mockService.On("ModifyPassword", c.Request.Context(), 1, newPassword).Return(nil)
-	mockService.On("SetPasswordTokenUsed", c.Request.Context(), expectedRequest.Token).Return(nil)
+	mockPasswordService.On("ResetPassword", c.Request.Context(), 7, 1, newPassword).Return(nil)
 
 	controller.ModifyUserPasssword(c)
 
@@ -478,21 +749,65 @@ func TestUserController_ModifyPassword_InvalidToken(t *testing.T) {
 	c.Request = req
 
 	mockService.On("ValidatePasswordResetToken", c.Request.Context(), request.Token).
-		Return(nil, errors.New("invalid or expired token"))
+		Return(nil, s.ErrInvalidResetToken)
 
 	controller.ModifyUserPasssword(c)
 
-	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
 
 	var result models.ErrorResponse
 	err := json.Unmarshal(recorder.Body.Bytes(), &result)
 	assert.NoError(t, err)
-	assert.Equal(t, "Internal Server Error", result.Title)
+	assert.Equal(t, "Bad Request", result.Title)
 }
 
-func TestUserController_ModifyPassword_ModifyFail(t *testing.T) {
+func TestUserController_ModifyPassword_TooManyAttempts(t *testing.T) {
 	mockService, _, c, recorder, controller := setupTest(t)
 
+	request := models.PasswordModifyRequest{
+		Token:    "1-333333",
+		Password: "newPassword123",
+	}
+	body, _ := json.Marshal(request)
+
+	req := httptest.NewRequest(http.MethodPut, "/users/password", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	mockService.On("ValidatePasswordResetToken", c.Request.Context(), request.Token).
+		Return(nil, s.ErrResetAttemptsExceeded)
+
+	controller.ModifyUserPasssword(c)
+
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+}
+
+func TestUserController_ModifyPassword_TokenAlreadyUsed(t *testing.T) {
+	mockService, mockPasswordService, c, recorder, controller := setupPasswordTest(t)
+
+	reqBody := models.PasswordModifyRequest{
+		Token:    "1-123456",
+		Password: "NewPassword1",
+	}
+	resetData := &models.PasswordResetData{Id: 1, UserId: 1}
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPut, "/users/password", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	mockService.On("ValidatePasswordResetToken", c.Request.Context(), reqBody.Token).Return(resetData, nil)
+	// Another request used the token first, the password isn't changed
+	mockPasswordService.On("ResetPassword", c.Request.Context(), 1, 1, reqBody.Password).Return(s.ErrInvalidResetToken)
+
+	controller.ModifyUserPasssword(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestUserController_ModifyPassword_ModifyFail(t *testing.T) {
+	mockService, mockPasswordService, c, recorder, controller := setupPasswordTest(t)
+
 	reqBody := models.PasswordModifyRequest{
 		Token:    "123456",
 		Password: "password",
@@ -509,8 +824,7 @@ func TestUserController_ModifyPassword_ModifyFail(t *testing.T) {
 
 	mockService.On("ValidatePasswordResetToken", c.Request.Context(), reqBody.Token).
 		Return(resetData, nil)
-
-	mockService.On("ModifyPassword", c.Request.Context(), resetData.UserId, reqBody.Password).
+	mockPasswordService.On("ResetPassword", c.Request.Context(), resetData.Id, resetData.UserId, reqBody.Password).
 		Return(errors.New("update failed"))
 
 	controller.ModifyUserPasssword(c)
@@ -523,12 +837,12 @@ func TestUserController_ModifyPassword_ModifyFail(t *testing.T) {
 	assert.Equal(t, "Internal Server Error", result.Title)
 }
 
-func TestUserController_ModifyPassword_SetTokenUsedFail(t *testing.T) {
-	mockService, _, c, recorder, controller := setupTest(t)
+func TestUserController_ModifyPassword_PolicyRejected(t *testing.T) {
+	mockService, mockPasswordService, c, recorder, controller := setupPasswordTest(t)
 
 	reqBody := models.PasswordModifyRequest{
 		Token:    "123456",
-		Password: "password",
+		Password: "short",
 	}
 	resetData := &models.PasswordResetData{
 		Email:  "test@example.com",
@@ -542,21 +856,94 @@ func TestUserController_ModifyPassword_SetTokenUsedFail(t *testing.T) {
 
 	mockService.On("ValidatePasswordResetToken", c.Request.Context(), reqBody.Token).
 		Return(resetData, nil)
+	// Rejected before the token is used up
+	mockPasswordService.On("ResetPassword", c.Request.Context(), resetData.Id, resetData.UserId, reqBody.Password).
+		Return(models.ValidationErrors{{Field: "password", Message: "must be at least 8 characters long"}})
 
-	mockService.On("ModifyPassword", c.Request.Context(), resetData.UserId, reqBody.Password).
-		Return(nil)
+	controller.ModifyUserPasssword(c)
 
-	mockService.On("SetPasswordTokenUsed", c.Request.Context(), reqBody.Token).
-		Return(errors.New("failed to mark token as used"))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
 
-	controller.ModifyUserPasssword(c)
+func TestUserController_ChangePassword(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		body         string
+		serviceErr   error
+		callService  bool
+		expectedCode int
+	}{
+		{
+			name:         "success",
+			id:           "1",
+			body:         `{"current_password":"OldPassword1","new_password":"NewPassword1"}`,
+			callService:  true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "invalid id",
+			id:           "abc",
+			body:         `{"current_password":"OldPassword1","new_password":"NewPassword1"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "missing fields",
+			id:           "1",
+			body:         `{"new_password":"NewPassword1"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "policy rejected",
+			id:           "1",
+			body:         `{"current_password":"OldPassword1","new_password":"NewPassword1"}`,
+			serviceErr:   models.ValidationErrors{{Field: "password", Message: "was used recently"}},
+			callService:  true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "wrong current password",
+			id:           "1",
+			body:         `{"current_password":"OldPassword1","new_password":"NewPassword1"}`,
+			serviceErr:   s.ErrWrongPassword,
+			callService:  true,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "user not found",
+			id:           "1",
+			body:         `{"current_password":"OldPassword1","new_password":"NewPassword1"}`,
+			serviceErr:   repositories.ErrNotFound,
+			callService:  true,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "internal error",
+			id:           "1",
+			body:         `{"current_password":"OldPassword1","new_password":"NewPassword1"}`,
+			serviceErr:   errors.New("db error"),
+			callService:  true,
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
 
-	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mockPasswordService, c, recorder, controller := setupPasswordTest(t)
 
-	var result models.ErrorResponse
-	err := json.Unmarshal(recorder.Body.Bytes(), &result)
-	assert.NoError(t, err)
-	assert.Equal(t, "Internal Server Error", result.Title)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Request = httptest.NewRequest(http.MethodPut, "/users/"+tt.id+"/password", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			if tt.callService {
+				mockPasswordService.EXPECT().ChangePassword(mock.Anything, 1, "OldPassword1", "NewPassword1").Return(tt.serviceErr)
+			}
+
+			controller.ChangePassword(c)
+
+			assert.Equal(t, tt.expectedCode, recorder.Code)
+		})
+	}
 }
 
 func TestUserController_NotifyUsers(t *testing.T) {
@@ -577,9 +964,7 @@ func TestUserController_NotifyUsers(t *testing.T) {
 
 	c.Request = req
 
-	mock.EXPECT().CheckPreference(c.Request.Context(), users[0], notifyRequest.NotificationType).Return(true, nil)
-	mock.EXPECT().SendNotifByMobile(c.Request.Context(), users[0], notifyRequest).Return(nil)
-	mock.EXPECT().SendNotifByEmail(c.Request.Context(), users[0], notifyRequest).Return(nil)
+	mock.EXPECT().NotifyUsers(c.Request.Context(), users, notifyRequest).Return(nil)
 
 	controller.NotifyUsers(c)
 	assert.Equal(t, http.StatusOK, recorder.Code)
@@ -598,14 +983,14 @@ func TestNotifyUsers_InvalidJSON(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "Invalid request format")
 }
 
-func TestNotifyUsers_CheckPreferenceFalse(t *testing.T) {
+func TestNotifyUsers_UnknownType(t *testing.T) {
 	mock, _, c, recorder, controller := setupTest(t)
 
 	notifyRequest := models.NotifyRequest{
-		Users:             []int{1},
+		Users:             []int{1, 2},
 		NotificationTitle: "title",
 		NotificationText:  "text",
-		NotificationType:  "exam_notification",
+		NotificationType:  "grades",
 	}
 
 	jsonBody, _ := json.Marshal(notifyRequest)
@@ -613,21 +998,20 @@ func TestNotifyUsers_CheckPreferenceFalse(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 	c.Request = req
 
-	mock.EXPECT().CheckPreference(c.Request.Context(), 1, "exam_notification").Return(false, nil)
+	mock.EXPECT().NotifyUsers(c.Request.Context(), []int{1, 2}, notifyRequest).Return(s.ErrUnknownNotificationType)
 
 	controller.NotifyUsers(c)
-	assert.Equal(t, http.StatusOK, recorder.Code)
-	assert.Equal(t, "null", recorder.Body.String())
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
 }
 
-func TestNotifyUsers_PreferenceFalse(t *testing.T) {
+func TestNotifyUsers_MandatoryType(t *testing.T) {
 	mock, _, c, recorder, controller := setupTest(t)
 
 	notifyRequest := models.NotifyRequest{
 		Users:             []int{1},
 		NotificationTitle: "title",
 		NotificationText:  "text",
-		NotificationType:  "exam_notification",
+		NotificationType:  "rule_notification",
 	}
 
 	jsonBody, _ := json.Marshal(notifyRequest)
@@ -635,14 +1019,13 @@ func TestNotifyUsers_PreferenceFalse(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 	c.Request = req
 
-	mock.EXPECT().CheckPreference(c.Request.Context(), 1, "exam_notification").Return(false, nil)
+	mock.EXPECT().NotifyUsers(c.Request.Context(), []int{1}, notifyRequest).Return(s.ErrMandatoryNotificationSend)
 
 	controller.NotifyUsers(c)
-	assert.Equal(t, http.StatusOK, recorder.Code)
-	assert.Equal(t, "null", recorder.Body.String())
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
 }
 
-func TestNotifyUsers_SendNotifByMobileError(t *testing.T) {
+func TestNotifyUsers_Error(t *testing.T) {
 	mock, _, c, recorder, controller := setupTest(t)
 
 	notifyRequest := models.NotifyRequest{
@@ -657,42 +1040,17 @@ func TestNotifyUsers_SendNotifByMobileError(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 	c.Request = req
 
-	mock.EXPECT().CheckPreference(c.Request.Context(), 1, "exam_notification").Return(true, nil)
-	mock.EXPECT().SendNotifByMobile(c.Request.Context(), 1, notifyRequest).Return(errors.New("mobile error"))
+	mock.EXPECT().NotifyUsers(c.Request.Context(), []int{1}, notifyRequest).Return(errors.New("inbox error"))
 
 	controller.NotifyUsers(c)
 	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
-	assert.Contains(t, recorder.Body.String(), "mobile error")
+	assert.Contains(t, recorder.Body.String(), "inbox error")
 }
 
-func TestNotifyUsers_SendNotifByEmailError(t *testing.T) {
+func TestUserController_SetUserNotifications(t *testing.T) {
 	mock, _, c, recorder, controller := setupTest(t)
 
-	notifyRequest := models.NotifyRequest{
-		Users:             []int{1},
-		NotificationTitle: "title",
-		NotificationText:  "text",
-		NotificationType:  "exam_notification",
-	}
-
-	jsonBody, _ := json.Marshal(notifyRequest)
-	req := httptest.NewRequest(http.MethodPost, "/users/notify", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
-	c.Request = req
-
-	mock.EXPECT().CheckPreference(c.Request.Context(), 1, "exam_notification").Return(true, nil)
-	mock.EXPECT().SendNotifByMobile(c.Request.Context(), 1, notifyRequest).Return(nil)
-	mock.EXPECT().SendNotifByEmail(c.Request.Context(), 1, notifyRequest).Return(errors.New("email error"))
-
-	controller.NotifyUsers(c)
-	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
-	assert.Contains(t, recorder.Body.String(), "email error")
-}
-
-func TestUserController_SetUserNotifications(t *testing.T) {
-	mock, _, c, recorder, controller := setupTest(t)
-
-	token := "notification token"
+	token := "notification token"
 
 	setupRequest := models.NotificationSetUpRequest{Token: token}
 
@@ -827,10 +1185,6 @@ func TestUserController_PasswordReset(t *testing.T) {
 	email := "test@email.com"
 
 	passwordResetRequest := models.PasswordResetRequest{Email: email}
-	user := models.User{
-		Id:    1,
-		Email: email,
-	}
 
 	jsonBody, _ := json.Marshal(passwordResetRequest)
 	req, _ := http.NewRequest(http.MethodPost, "/users/reset/password", bytes.NewBuffer(jsonBody))
@@ -838,8 +1192,7 @@ func TestUserController_PasswordReset(t *testing.T) {
 
 	c.Request = req
 
-	mock.EXPECT().GetUserByEmail(c.Request.Context(), email).Return(&user, nil)
-	mock.EXPECT().StartPasswordReset(c.Request.Context(), user.Id, user.Email).Return(nil)
+	mock.EXPECT().StartPasswordReset(c.Request.Context(), email).Return(nil)
 
 	controller.PasswordReset(c)
 
@@ -867,36 +1220,10 @@ func TestPasswordReset_InvalidRequestBody(t *testing.T) {
 	assert.Equal(t, "Invalid request format", resp.Error)
 }
 
-func TestPasswordReset_GetUserByEmailFails(t *testing.T) {
-	mock, _, c, recorder, controller := setupTest(t)
-
-	email := "test@test.com"
-	body := models.PasswordResetRequest{Email: email}
-	jsonBody, _ := json.Marshal(body)
-
-	req := httptest.NewRequest(http.MethodPost, "/users/reset/password", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
-	c.Request = req
-
-	mock.EXPECT().
-		GetUserByEmail(c.Request.Context(), email).
-		Return(nil, errors.New("user not found"))
-
-	controller.PasswordReset(c)
-
-	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
-
-	var resp utils.HTTPError
-	err := json.Unmarshal(recorder.Body.Bytes(), &resp)
-	assert.NoError(t, err)
-	assert.Equal(t, "user not found", resp.Error)
-}
-
 func TestPasswordReset_StartResetFails(t *testing.T) {
 	mock, _, c, recorder, controller := setupTest(t)
 
 	email := "test@email.com"
-	user := &models.User{Id: 1, Email: email}
 	reqBody := models.PasswordResetRequest{Email: email}
 	jsonBody, _ := json.Marshal(reqBody)
 
@@ -905,11 +1232,7 @@ func TestPasswordReset_StartResetFails(t *testing.T) {
 	c.Request = req
 
 	mock.EXPECT().
-		GetUserByEmail(c.Request.Context(), email).
-		Return(user, nil)
-
-	mock.EXPECT().
-		StartPasswordReset(c.Request.Context(), user.Id, user.Email).
+		StartPasswordReset(c.Request.Context(), email).
 		Return(errors.New("email service down"))
 
 	controller.PasswordReset(c)
@@ -1104,7 +1427,7 @@ func TestUserController_DeleteRule(t *testing.T) {
 		Role:  role,
 	})
 
-	mockRulesService.EXPECT().DeleteRule(c, ruleId, userId).Return(nil)
+	mockRulesService.EXPECT().DeleteRule(c, ruleId, userId, mock.Anything).Return(nil)
 
 	controller.DeleteRule(c)
 
@@ -1191,7 +1514,7 @@ func TestDeleteRule_ServiceFails(t *testing.T) {
 
 	mockRulesService.
 		EXPECT().
-		DeleteRule(c, ruleId, userId).
+		DeleteRule(c, ruleId, userId, mock.Anything).
 		Return(errors.New("deletion failed"))
 
 	controller.DeleteRule(c)
@@ -1219,7 +1542,7 @@ func TestUserController_GetRules(t *testing.T) {
 		ApplicationCondition: "condition",
 	}
 
-	mockRulesService.EXPECT().GetRules(c).Return([]models.Rule{rule}, nil)
+	mockRulesService.EXPECT().GetRules(c, models.RuleListRequest{}).Return([]models.Rule{rule}, nil)
 
 	controller.GetRules(c)
 
@@ -1237,10 +1560,87 @@ func TestUserController_GetRules(t *testing.T) {
 				"Description":          rule.Description,
 				"effectiveDate":        rule.EffectiveDate.Format(time.RFC3339Nano), // if formatted as string
 				"ApplicationCondition": rule.ApplicationCondition,
+				"version":              float64(rule.Version),
 			},
 		},
 	}
 	assert.Equal(t, expected, response)
+	assert.Equal(t, models.RulesETag([]models.Rule{rule}), recorder.Header().Get("ETag"))
+}
+
+func TestUserController_GetRules_NotModified(t *testing.T) {
+	_, mockRulesService, c, recorder, controller := setupTest(t)
+
+	rules := []models.Rule{{Id: 1, Version: 2}, {Id: 2, Version: 1}}
+
+	req, _ := http.NewRequest(http.MethodGet, "/rules", nil)
+	req.Header.Set("If-None-Match", models.RulesETag(rules))
+	c.Request = req
+
+	mockRulesService.EXPECT().GetRules(c, models.RuleListRequest{}).Return(rules, nil)
+
+	controller.GetRules(c)
+
+	assert.Equal(t, http.StatusNotModified, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+}
+
+func TestUserController_GetRules_Filtered(t *testing.T) {
+	_, mockRulesService, c, recorder, controller := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/rules?category=privacy&tag=data&tag=students", nil)
+
+	mockRulesService.EXPECT().GetRules(c, models.RuleListRequest{Category: models.RulePrivacy, Tags: []string{"data", "students"}}).
+		Return([]models.Rule{{Id: 1, Category: models.RulePrivacy, Tags: []string{"data", "students"}}}, nil)
+
+	controller.GetRules(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"tags":["data","students"]`)
+}
+
+func TestUserController_GetRules_InvalidCategory(t *testing.T) {
+	_, _, c, recorder, controller := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/rules?category=sports", nil)
+
+	controller.GetRules(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestUserController_GetActiveRules_Localized(t *testing.T) {
+	_, mockRulesService, c, recorder, controller := setupTest(t)
+
+	rules := func() []models.Rule {
+		return []models.Rule{
+			{Id: 1, Version: 1, Title: "Be nice", Description: "Always",
+				Translations: map[string]models.RuleTranslation{"es": {Title: "Sé amable", Description: "Siempre"}}},
+			{Id: 2, Version: 1, Title: "Attend", Description: "Every class"},
+		}
+	}
+	c.Request = httptest.NewRequest(http.MethodGet, "/rules/active", nil)
+	c.Request.Header.Set("Accept-Language", "es-AR,es;q=0.9")
+
+	mockRulesService.EXPECT().GetRules(mock.Anything, models.RuleListRequest{Status: models.RuleActive}).Return(rules(), nil)
+
+	controller.GetActiveRules(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response struct {
+		Data []models.Rule `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, "Sé amable", response.Data[0].Title)
+	assert.Equal(t, "es", response.Data[0].Locale)
+	assert.Nil(t, response.Data[0].Translations)
+	// Without a translation the rule falls back to the language it was written in
+	assert.Equal(t, "Attend", response.Data[1].Title)
+	assert.Equal(t, models.RuleDefaultLocale, response.Data[1].Locale)
+	assert.Equal(t, "es, en", recorder.Header().Get("Content-Language"))
+	assert.Equal(t, "Accept-Language", recorder.Header().Get("Vary"))
+	assert.NotEqual(t, models.RulesETag(rules()), recorder.Header().Get("ETag"))
 }
 
 func TestUserController_GetRules_Error(t *testing.T) {
@@ -1249,7 +1649,7 @@ func TestUserController_GetRules_Error(t *testing.T) {
 	req, _ := http.NewRequest(http.MethodGet, "/rules", nil)
 	c.Request = req
 
-	mockRulesService.EXPECT().GetRules(c).Return(nil, errors.New("database error"))
+	mockRulesService.EXPECT().GetRules(c, models.RuleListRequest{}).Return(nil, errors.New("database error"))
 
 	controller.GetRules(c)
 
@@ -1269,25 +1669,66 @@ func TestUserController_GetAudits(t *testing.T) {
 
 	c.Request = req
 
-	audit := models.Audit{
+	audit := models.AuditData{
 		Id:                   2,
-		RuleId:               sql.NullInt64{Int64: 1, Valid: true},
-		UserId:               sql.NullInt64{Int64: 1, Valid: true},
-		ModificationDate:     time.Time{},
-		NatureOfModification: "modification",
+		RuleId:               1,
+		UserId:               1,
+		Action:               models.RuleAuditCreate,
+		After:                json.RawMessage(`{"Title":"A"}`),
+		ModificationDate:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		NatureOfModification: "Created Rule 1: A",
+		Hash:                 "abc",
 	}
 
-	mockRulesService.EXPECT().GetAudits(c).Return([]models.Audit{audit}, nil)
+	mockRulesService.EXPECT().GetAudits(mock.Anything, models.RuleAuditListRequest{}).
+		Return([]models.AuditData{audit}, models.Pagination{Page: 1, PageSize: 50, Total: 1}, nil)
 
 	controller.GetAudits(c)
 	assert.Equal(t, http.StatusOK, recorder.Code)
 
-	var response map[string][]models.Audit
+	var response struct {
+		Data       []models.AuditData `json:"data"`
+		Pagination models.Pagination  `json:"pagination"`
+	}
 	err := json.Unmarshal(recorder.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	expected := []models.Audit{audit}
-	assert.Equal(t, expected, response["data"])
+	decoded := audit
+	decoded.Before = json.RawMessage("null")
+	assert.Equal(t, []models.AuditData{decoded}, response.Data)
+	assert.Equal(t, 1, response.Pagination.Total)
+	encoded, err := json.Marshal(audit)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":2,"rule_id":1,"user_id":1,"action":"rule.create","before":null,"after":{"Title":"A"},
+		"modification_date":"2025-03-01T00:00:00Z","nature_of_modification":"Created Rule 1: A","hash":"abc"}`,
+		string(encoded))
+}
+
+func TestUserController_GetAudits_Filtered(t *testing.T) {
+	_, mockRulesService, c, recorder, controller := setupTest(t)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/rules/audit?rule_id=3&user_id=1&action=rule.update&from=2025-03-01&page=2", nil)
+
+	ruleId, userId := 3, 1
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	expected := models.RuleAuditListRequest{
+		RuleAuditFilter: models.RuleAuditFilter{RuleId: &ruleId, UserId: &userId, Action: models.RuleAuditUpdate, From: &from},
+		Page:            2,
+	}
+	mockRulesService.EXPECT().GetAudits(mock.Anything, expected).
+		Return([]models.AuditData{}, models.Pagination{Page: 2, PageSize: 50, Total: 0}, nil)
+
+	controller.GetAudits(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestUserController_GetAudits_InvalidFilter(t *testing.T) {
+	_, _, c, recorder, controller := setupTest(t)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/rules/audit?action=rule.rename", nil)
+
+	controller.GetAudits(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
 }
 
 func TestUserController_GetAudits_Error(t *testing.T) {
@@ -1296,7 +1737,7 @@ func TestUserController_GetAudits_Error(t *testing.T) {
 	req, _ := http.NewRequest(http.MethodGet, "/audits", nil)
 	c.Request = req
 
-	mockRulesService.EXPECT().GetAudits(c).Return(nil, errors.New("database error"))
+	mockRulesService.EXPECT().GetAudits(mock.Anything, models.RuleAuditListRequest{}).Return(nil, models.Pagination{}, errors.New("database error"))
 
 	controller.GetAudits(c)
 
@@ -1345,7 +1786,9 @@ func TestUserController_ModifyRule(t *testing.T) {
 		Role:  role,
 	})
 
-	mockRulesService.EXPECT().ModifyRule(c.Request.Context(), ruleId, request, userId).Return(nil)
+	req.Header.Set("If-Match", `"1"`)
+
+	mockRulesService.EXPECT().ModifyRule(c.Request.Context(), ruleId, request, userId, `"1"`).Return(nil)
 
 	controller.ModifyRule(c)
 
@@ -1450,7 +1893,7 @@ func TestModifyRule_ServiceError(t *testing.T) {
 	})
 
 	mockRulesService.EXPECT().
-		ModifyRule(c.Request.Context(), ruleId, body, userId).
+		ModifyRule(c.Request.Context(), ruleId, body, userId, mock.Anything).
 		Return(errors.New("db failure"))
 
 	controller.ModifyRule(c)
@@ -1458,6 +1901,32 @@ func TestModifyRule_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 }
 
+func TestModifyRule_PreconditionFailed(t *testing.T) {
+	_, mockRulesService, c, recorder, controller := setupTest(t)
+
+	body := models.RuleModify{Title: "T"}
+	jsonBody, _ := json.Marshal(body)
+
+	req := httptest.NewRequest(http.MethodPut, "/rules/1", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"1"`)
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	c.Request = req
+	c.Set("claims", &models.Claims{
+		StandardClaims: jwt.StandardClaims{
+			Subject: "1",
+		},
+	})
+
+	mockRulesService.EXPECT().
+		ModifyRule(c.Request.Context(), 1, body, 1, `"1"`).
+		Return(repositories.ErrVersionMismatch)
+
+	controller.ModifyRule(c)
+
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+}
+
 func TestUserController_ModifyNotifPreference(t *testing.T) {
 	mock, _, c, recorder, controller := setupTest(t)
 
@@ -1531,6 +2000,27 @@ func TestModifyNotifPreference_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 }
 
+func TestModifyNotifPreference_MandatoryType(t *testing.T) {
+	mock, _, c, recorder, controller := setupTest(t)
+
+	request := models.NotificationPreferenceRequest{
+		NotificationType:       models.RuleNotification,
+		NotificationPreference: false,
+	}
+	jsonBody, _ := json.Marshal(request)
+
+	req := httptest.NewRequest(http.MethodPut, "/users/1/notifications/preference", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	c.Request = req
+
+	mock.EXPECT().SetNotificationPreference(c.Request.Context(), 1, request).Return(s.ErrMandatoryNotification)
+
+	controller.ModifyNotifPreference(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
 func TestUserController_GetNotifPreferences(t *testing.T) {
 	mock, _, c, recorder, controller := setupTest(t)
 
@@ -1586,6 +2076,129 @@ func TestGetNotifPreferences_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 }
 
+func TestUserController_GetNotificationSettings(t *testing.T) {
+	mock, _, c, recorder, controller := setupTest(t)
+
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/1/notifications/preferences", nil)
+
+	settings := &models.NotificationSettings{
+		Types:       []models.NotificationType{{Name: "exam_notification"}},
+		Channels:    models.NotificationChannels,
+		Preferences: models.NotificationPreferences{"exam_notification": {"inbox": true, "push": false, "email": true}},
+	}
+	mock.EXPECT().GetNotificationSettings(c.Request.Context(), 1).Return(settings, nil)
+
+	controller.GetNotificationSettings(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response struct {
+		Data models.NotificationSettings `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, settings.Preferences, response.Data.Preferences)
+	assert.Equal(t, models.NotificationChannels, response.Data.Channels)
+}
+
+func TestGetNotificationSettings_InvalidUserID(t *testing.T) {
+	_, _, c, recorder, controller := setupTest(t)
+
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "abc"}}
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/abc/notifications/preferences", nil)
+
+	controller.GetNotificationSettings(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestUserController_UpdateNotificationPreferences(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		serviceErr   error
+		expectCall   bool
+		expectedCode int
+	}{
+		{"updated", `{"preferences":{"exam_notification":{"push":false}}}`, nil, true, http.StatusOK},
+		{"missing preferences", `{}`, nil, false, http.StatusBadRequest},
+		{"invalid json", `{`, nil, false, http.StatusBadRequest},
+		{"unknown type", `{"preferences":{"exam_notification":{"push":false}}}`, s.ErrUnknownNotificationType, true, http.StatusBadRequest},
+		{"unknown channel", `{"preferences":{"exam_notification":{"push":false}}}`, s.ErrUnknownNotificationChannel, true, http.StatusBadRequest},
+		{"mandatory type", `{"preferences":{"exam_notification":{"push":false}}}`, s.ErrMandatoryNotification, true, http.StatusBadRequest},
+		{"service error", `{"preferences":{"exam_notification":{"push":false}}}`, errors.New("db error"), true, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, _, c, recorder, controller := setupTest(t)
+
+			req := httptest.NewRequest(http.MethodPut, "/users/1/notifications/preferences", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+			c.Request = req
+
+			if tt.expectCall {
+				var settings *models.NotificationSettings
+				if tt.serviceErr == nil {
+					settings = &models.NotificationSettings{Channels: models.NotificationChannels}
+				}
+				mock.EXPECT().UpdateNotificationPreferences(c.Request.Context(), 1,
+					models.NotificationPreferences{"exam_notification": {"push": false}}).Return(settings, tt.serviceErr)
+			}
+
+			controller.UpdateNotificationPreferences(c)
+
+			assert.Equal(t, tt.expectedCode, recorder.Code)
+		})
+	}
+}
+
+func TestUserController_GetNotificationTypes(t *testing.T) {
+	mock, _, c, recorder, controller := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/notifications/types", nil)
+	mock.EXPECT().GetNotificationTypes(c.Request.Context()).
+		Return([]models.NotificationType{{Name: models.RuleNotification, Mandatory: true}}, nil)
+
+	controller.GetNotificationTypes(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"name":"rule_notification"`)
+	assert.Contains(t, recorder.Body.String(), `"mandatory":true`)
+}
+
+func TestUserController_AddNotificationType(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		serviceErr   error
+		expectCall   bool
+		expectedCode int
+	}{
+		{"created", `{"name":"grades","description":"Grades"}`, nil, true, http.StatusCreated},
+		{"missing name", `{"description":"Grades"}`, nil, false, http.StatusBadRequest},
+		{"already exists", `{"name":"grades","description":"Grades"}`, repositories.ErrNotificationTypeExists, true, http.StatusConflict},
+		{"service error", `{"name":"grades","description":"Grades"}`, errors.New("db error"), true, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, _, c, recorder, controller := setupTest(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/notifications/types", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			c.Request = req
+
+			if tt.expectCall {
+				mock.EXPECT().AddNotificationType(c.Request.Context(), &models.NotificationType{Name: "grades", Description: "Grades"}).
+					Return(tt.serviceErr)
+			}
+
+			controller.AddNotificationType(c)
+
+			assert.Equal(t, tt.expectedCode, recorder.Code)
+		})
+	}
+}
+
 func TestUserController_PasswordResetRedirect(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	_, _, c, recorder, controller := setupTest(t)
@@ -1600,6 +2213,20 @@ func TestUserController_PasswordResetRedirect(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "<html>")
 }
 
+func TestUserController_PasswordResetRedirect_EscapesToken(t *testing.T) {
+	_, _, c, recorder, controller := setupTest(t)
+
+	token := `"><script>alert(1)</script>`
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/reset/password?token="+url.QueryEscape(token), nil)
+
+	controller.PasswordResetRedirect(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), "<script>alert(1)")
+	assert.NotContains(t, recorder.Body.String(), `"><`)
+	assert.Contains(t, recorder.Body.String(), "token=%22%3E%3Cscript%3Ealert%281%29%3C%2Fscript%3E")
+}
+
 func TestMakeTeacher(t *testing.T) {
 	mockService, _, c, recorder, userController := setupTest(t)
 
@@ -1645,22 +2272,139 @@ func TestMakeTeacher_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestRequestEmailChange_Success(t *testing.T) {
+	mockService, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodPost, "/users/1/email", bytes.NewBufferString(`{"email": "new@example.com"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.AddParam("id", "1")
+
+	mockService.EXPECT().RequestEmailChange(mock.Anything, 1, "new@example.com").Return(nil)
+
+	userController.RequestEmailChange(c)
+
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+}
+
+func TestRequestEmailChange_InvalidEmail(t *testing.T) {
+	_, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodPost, "/users/1/email", bytes.NewBufferString(`{"email": "not-an-email"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.AddParam("id", "1")
+
+	userController.RequestEmailChange(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestRequestEmailChange_EmailTaken(t *testing.T) {
+	mockService, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodPost, "/users/1/email", bytes.NewBufferString(`{"email": "taken@example.com"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.AddParam("id", "1")
+
+	mockService.EXPECT().RequestEmailChange(mock.Anything, 1, "taken@example.com").Return(repositories.ErrEmailTaken)
+
+	userController.RequestEmailChange(c)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func TestConfirmEmailChange_Success(t *testing.T) {
+	mockService, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodPost, "/users/1/email/confirm", bytes.NewBufferString(`{"pin": "7-AB12CD"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.AddParam("id", "1")
+
+	mockService.EXPECT().ConfirmEmailChange(mock.Anything, 1, "7-AB12CD").Return(nil)
+
+	userController.ConfirmEmailChange(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("Set-Cookie"), "Authorization=;")
+}
+
+func TestConfirmEmailChange_Errors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{s.ErrInvalidPin, http.StatusBadRequest},
+		{s.ErrPinExpired, http.StatusGone},
+		{s.ErrPinAttemptsExceeded, http.StatusTooManyRequests},
+		{repositories.ErrEmailTaken, http.StatusConflict},
+		{errors.New("db error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		mockService, _, c, recorder, userController := setupTest(t)
+
+		c.Request = httptest.NewRequest(http.MethodPost, "/users/1/email/confirm", bytes.NewBufferString(`{"pin": "7-AB12CD"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.AddParam("id", "1")
+
+		mockService.EXPECT().ConfirmEmailChange(mock.Anything, 1, "7-AB12CD").Return(tt.err)
+
+		userController.ConfirmEmailChange(c)
+
+		assert.Equal(t, tt.code, recorder.Code, tt.err.Error())
+	}
+}
+
+func TestCancelEmailChange(t *testing.T) {
+	mockService, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/email/cancel?token=abc", nil)
+
+	mockService.EXPECT().CancelEmailChange(mock.Anything, "abc").Return(nil)
+
+	userController.CancelEmailChange(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Email change cancelled", recorder.Body.String())
+}
+
+func TestCancelEmailChange_UnknownToken(t *testing.T) {
+	mockService, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/email/cancel?token=abc", nil)
+
+	mockService.EXPECT().CancelEmailChange(mock.Anything, "abc").Return(repositories.ErrNotFound)
+
+	userController.CancelEmailChange(c)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestCancelEmailChange_MissingToken(t *testing.T) {
+	_, _, c, recorder, userController := setupTest(t)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/email/cancel", nil)
+
+	userController.CancelEmailChange(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
 //integration tests
 
-func setupIntegrationTest(db *sql.DB, t *testing.T) (*s.MockEmailSender, *gin.Context, *httptest.ResponseRecorder, *controller.UserController) {
+func setupIntegrationTest(db *sql.DB, t *testing.T) (*gin.Context, *httptest.ResponseRecorder, *controller.UserController) {
 	gin.SetMode(gin.TestMode)
-	email := s.NewMockEmailSender(t)
-	userService := s.NewUserService(repositories.CreateUserRepo(db), repositories.NewBlockedUserRepository(db), email)
-	rulesService := s.NewRulesService(repositories.CreateRulesRepo(db))
+	userService := s.NewUserService(repositories.CreateUserRepo(db), repositories.NewBlockedUserRepository(db), repositories.NewJobRepository(db), repositories.NewInboxRepository(db), "http://localhost:8080", utils.DefaultHashParams())
+	rulesService := s.NewRulesService(repositories.CreateRulesRepo(db), repositories.NewRuleAcceptanceRepository(db))
+	passwordService := s.NewPasswordService(repositories.CreateUserRepo(db), models.DefaultPasswordPolicy(), utils.DefaultHashParams())
 	recorder := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(recorder)
-	userController := controller.CreateController(userService, rulesService)
-	return email, c, recorder, userController
+	userController := controller.CreateController(userService, rulesService, passwordService)
+	return c, recorder, userController
 }
 
 func TestUsersGet_IntegrationTest(t *testing.T) {
 	db, mock, _ := sqlmock.New()
-	_, c, recorder, userController := setupIntegrationTest(db, t)
+	c, recorder, userController := setupIntegrationTest(db, t)
 
 	c.Request = httptest.NewRequest(http.MethodGet, "/api/users", nil)
 
@@ -1699,7 +2443,7 @@ func TestUsersGet_IntegrationTest(t *testing.T) {
 
 func TestUserController_GetRules_IntegrationTest(t *testing.T) {
 	db, mock, _ := sqlmock.New()
-	_, c, recorder, userController := setupIntegrationTest(db, t)
+	c, recorder, userController := setupIntegrationTest(db, t)
 
 	req, _ := http.NewRequest(http.MethodGet, "/rules", nil)
 
@@ -1713,15 +2457,16 @@ func TestUserController_GetRules_IntegrationTest(t *testing.T) {
 			Description:          "First rule",
 			EffectiveDate:        now,
 			ApplicationCondition: "If condition A",
+			Version:              1,
 		},
 	}
 
 	rows := sqlmock.NewRows([]string{
-		"id", "title", "description", "effective_date", "application_condition",
+		"id", "title", "description", "effective_date", "application_condition", "version", "status", "retired_at", "mandatory", "category", "tags", "translations",
 	}).
-		AddRow(expectedRules[0].Id, expectedRules[0].Title, expectedRules[0].Description, expectedRules[0].EffectiveDate, expectedRules[0].ApplicationCondition)
+		AddRow(expectedRules[0].Id, expectedRules[0].Title, expectedRules[0].Description, expectedRules[0].EffectiveDate, expectedRules[0].ApplicationCondition, expectedRules[0].Version, models.RuleActive, nil, false, models.RuleGeneral, "{}", "{}")
 
-	mock.ExpectQuery(`SELECT id, title, description, effective_date, application_condition FROM rules`).
+	mock.ExpectQuery(`SELECT id, title, description, effective_date, application_condition, version, status, retired_at, mandatory, category, tags, translations FROM rules`).
 		WillReturnRows(rows)
 
 	userController.GetRules(c)
@@ -1740,8 +2485,341 @@ func TestUserController_GetRules_IntegrationTest(t *testing.T) {
 				"Description":          expectedRules[0].Description,
 				"effectiveDate":        expectedRules[0].EffectiveDate.Format(time.RFC3339Nano), // if formatted as string
 				"ApplicationCondition": expectedRules[0].ApplicationCondition,
+				"version":              float64(expectedRules[0].Version),
+				"status":               string(models.RuleActive),
+				"category":             string(models.RuleGeneral),
 			},
 		},
 	}
 	assert.Equal(t, expected, response)
 }
+
+func TestUserController_GetRuleVersions(t *testing.T) {
+	_, mockRulesService, c, recorder, controller := setupTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/rules/1/versions", nil)
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	versions := []models.RuleVersion{{Rule: models.Rule{Id: 1, Title: "B", Version: 2}, CreatedBy: 7}}
+	mockRulesService.EXPECT().GetRuleVersions(mock.Anything, 1).Return(versions, nil)
+
+	controller.GetRuleVersions(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response struct {
+		Data []models.RuleVersion `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, versions, response.Data)
+}
+
+func TestUserController_GetRuleVersionDiff(t *testing.T) {
+	_, mockRulesService, c, recorder, controller := setupTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/rules/1/versions/3/diff?against=1", nil)
+	c.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "v", Value: "3"}}
+
+	diff := &models.RuleDiff{RuleId: 1, From: 1, To: 3, Changes: []models.RuleFieldChange{{Field: "Title", From: "A", To: "C"}}}
+	mockRulesService.EXPECT().DiffRuleVersions(mock.Anything, 1, 3, 1).Return(diff, nil)
+
+	controller.GetRuleVersionDiff(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"field":"Title"`)
+}
+
+func TestUserController_GetRuleVersionDiff_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		version        string
+		query          string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "invalid version", version: "x", expectedStatus: http.StatusBadRequest},
+		{name: "invalid against", version: "3", query: "?against=0", expectedStatus: http.StatusBadRequest},
+		{name: "unknown version", version: "9", serviceErr: repositories.ErrNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mockRulesService, c, recorder, controller := setupTest(t)
+			c.Request = httptest.NewRequest(http.MethodGet, "/rules/1/versions/"+tt.version+"/diff"+tt.query, nil)
+			c.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "v", Value: tt.version}}
+			if tt.serviceErr != nil {
+				mockRulesService.EXPECT().DiffRuleVersions(mock.Anything, 1, 9, 0).Return(nil, tt.serviceErr)
+			}
+
+			controller.GetRuleVersionDiff(c)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestUserController_RollbackRule(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "rolled back", expectedStatus: http.StatusOK},
+		{name: "already at version", serviceErr: s.ErrRuleAlreadyAtVersion, expectedStatus: http.StatusConflict},
+		{name: "stale etag", serviceErr: repositories.ErrVersionMismatch, expectedStatus: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mockRulesService, c, recorder, controller := setupTest(t)
+			c.Request = httptest.NewRequest(http.MethodPost, "/rules/1/rollback/1", nil)
+			c.Request.Header.Set("If-Match", `"3"`)
+			c.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "v", Value: "1"}}
+			c.Set("claims", &models.Claims{StandardClaims: jwt.StandardClaims{Subject: "7"}, Role: "admin"})
+
+			mockRulesService.EXPECT().RollbackRule(mock.Anything, 1, 1, 7, `"3"`).Return(tt.serviceErr)
+
+			controller.RollbackRule(c)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestUserController_GetRules_InForceAt(t *testing.T) {
+	_, mockRulesService, c, recorder, controller := setupTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/rules?at=2026-03-01", nil)
+
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rules := []models.Rule{{Id: 1, Version: 2, Status: models.RuleActive}}
+	mockRulesService.EXPECT().GetRules(c, models.RuleListRequest{At: &at}).Return(rules, nil)
+
+	controller.GetRules(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"status":"active"`)
+}
+
+func TestUserController_GetRules_InvalidFilter(t *testing.T) {
+	for _, query := range []string{"?at=yesterday", "?status=pending"} {
+		t.Run(query, func(t *testing.T) {
+			_, _, c, recorder, controller := setupTest(t)
+			c.Request = httptest.NewRequest(http.MethodGet, "/rules"+query, nil)
+
+			controller.GetRules(c)
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
+
+func TestUserController_PublishAndRetireRule(t *testing.T) {
+	tests := []struct {
+		name           string
+		retire         bool
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "published", expectedStatus: http.StatusOK},
+		{name: "retired", retire: true, expectedStatus: http.StatusOK},
+		{name: "not a draft", serviceErr: fmt.Errorf("%w: an active rule can't become scheduled", s.ErrInvalidRuleTransition), expectedStatus: http.StatusConflict},
+		{name: "stale etag", retire: true, serviceErr: repositories.ErrVersionMismatch, expectedStatus: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mockRulesService, c, recorder, controller := setupTest(t)
+			c.Request = httptest.NewRequest(http.MethodPost, "/rules/1/publish", nil)
+			c.Request.Header.Set("If-Match", `"2"`)
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+			c.Set("claims", &models.Claims{StandardClaims: jwt.StandardClaims{Subject: "7"}, Role: "admin"})
+
+			status := models.RuleScheduled
+			if tt.retire {
+				status = models.RuleRetired
+			}
+			mockRulesService.EXPECT().SetRuleStatus(mock.Anything, 1, status, 7, `"2"`).Return(tt.serviceErr)
+
+			if tt.retire {
+				controller.RetireRule(c)
+			} else {
+				controller.PublishRule(c)
+			}
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestUserController_GetActiveRules(t *testing.T) {
+	_, mockRulesService, c, recorder, controller := setupTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/rules/active", nil)
+
+	rules := []models.Rule{{Id: 1, Title: "Terms of service", Version: 4, Status: models.RuleActive, Mandatory: true}}
+	mockRulesService.EXPECT().GetRules(mock.Anything, models.RuleListRequest{Status: models.RuleActive}).Return(rules, nil)
+
+	controller.GetActiveRules(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, models.RulesETag(rules), recorder.Header().Get("ETag"))
+	assert.Contains(t, recorder.Body.String(), `"mandatory":true`)
+}
+
+func TestUserController_AcceptRule(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "accepted", expectedStatus: http.StatusOK},
+		{name: "rule not active", serviceErr: s.ErrRuleNotActive, expectedStatus: http.StatusConflict},
+		{name: "read an older version", serviceErr: repositories.ErrVersionMismatch, expectedStatus: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mockRulesService, c, recorder, controller := setupTest(t)
+			c.Request = httptest.NewRequest(http.MethodPost, "/rules/1/accept", nil)
+			c.Request.Header.Set("If-Match", `"4"`)
+			c.Request.RemoteAddr = "10.0.0.1:1234"
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+			c.Set("claims", &models.Claims{StandardClaims: jwt.StandardClaims{Subject: "7"}, Role: "student"})
+
+			var acceptance *models.RuleAcceptance
+			if tt.serviceErr == nil {
+				acceptance = &models.RuleAcceptance{UserId: 7, RuleId: 1, RuleVersion: 4, IP: "10.0.0.1"}
+			}
+			mockRulesService.EXPECT().AcceptRule(mock.Anything, 1, 7, `"4"`, "10.0.0.1").Return(acceptance, tt.serviceErr)
+
+			controller.AcceptRule(c)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestUserController_GetPendingRules(t *testing.T) {
+	_, mockRulesService, c, recorder, controller := setupTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/rules/pending", nil)
+	c.Set("claims", &models.Claims{StandardClaims: jwt.StandardClaims{Subject: "7"}, Role: "student"})
+
+	pending := []models.PendingRule{{Rule: models.Rule{Id: 1, Version: 4}, AcceptedVersion: 3}}
+	mockRulesService.EXPECT().GetPendingRules(mock.Anything, 7).Return(pending, nil)
+
+	controller.GetPendingRules(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"accepted_version":3`)
+}
+
+func TestUserController_GetRuleAcceptanceReport(t *testing.T) {
+	_, mockRulesService, c, recorder, controller := setupTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/rules/acceptance", nil)
+
+	report := models.NewRuleAcceptanceReport(4, []models.RuleAcceptanceCoverage{{RuleId: 1, Version: 2, Accepted: 1}})
+	mockRulesService.EXPECT().GetAcceptanceReport(mock.Anything).Return(report, nil)
+
+	controller.GetRuleAcceptanceReport(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"coverage":0.25`)
+}
+
+func TestUserController_GetApplicableRules(t *testing.T) {
+	mockService, mockRulesService, c, recorder, controller := setupTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/7/applicable-rules", nil)
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+
+	user := &models.User{Id: 7, Role: "student"}
+	mockService.EXPECT().GetUserById(mock.Anything, 7).Return(user, nil)
+	mockRulesService.EXPECT().GetApplicableRules(mock.Anything, *user).Return(models.ApplicableRules{
+		UserId: 7, Rules: []models.Rule{{Id: 1}}, Unevaluable: []int{2},
+	}, nil)
+
+	controller.GetApplicableRules(c)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"unevaluable":[2]`)
+}
+
+func TestUserController_GetApplicableRules_UserNotFound(t *testing.T) {
+	mockService, _, c, recorder, controller := setupTest(t)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/7/applicable-rules", nil)
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+
+	mockService.EXPECT().GetUserById(mock.Anything, 7).Return(nil, repositories.ErrNotFound)
+
+	controller.GetApplicableRules(c)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestUserController_EvaluateRuleCondition(t *testing.T) {
+	evaluate := func(t *testing.T, body string, subject string, role string) (*s.MockUserService, *s.MockRulesService, func() *httptest.ResponseRecorder) {
+		mockService, mockRulesService, c, recorder, controller := setupTest(t)
+		c.Request = httptest.NewRequest(http.MethodPost, "/rules/evaluate", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("claims", &models.Claims{StandardClaims: jwt.StandardClaims{Subject: subject}, Role: role})
+		return mockService, mockRulesService, func() *httptest.ResponseRecorder {
+			controller.EvaluateRuleCondition(c)
+			return recorder
+		}
+	}
+
+	t.Run("for a user", func(t *testing.T) {
+		mockService, mockRulesService, run := evaluate(t, `{"condition":"role == \"student\"","user_id":7}`, "7", "student")
+		user := &models.User{Id: 7, Role: "student"}
+		mockService.EXPECT().GetUserById(mock.Anything, 7).Return(user, nil)
+		mockRulesService.EXPECT().EvaluateCondition(mock.Anything, `role == "student"`, models.RuleConditionVars(*user)).Return(true, nil)
+
+		recorder := run()
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"applies":true`)
+	})
+
+	t.Run("for attributes", func(t *testing.T) {
+		_, mockRulesService, run := evaluate(t, `{"condition":"created_at < \"2026-01-01\"","attributes":{"created_at":"2025-06-01","role":"student"}}`, "7", "student")
+		vars := map[string]any{"created_at": time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), "role": "student"}
+		mockRulesService.EXPECT().EvaluateCondition(mock.Anything, `created_at < "2026-01-01"`, vars).Return(true, nil)
+
+		recorder := run()
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("malformed condition", func(t *testing.T) {
+		_, mockRulesService, run := evaluate(t, `{"condition":"role ==","attributes":{}}`, "7", "student")
+		mockRulesService.EXPECT().EvaluateCondition(mock.Anything, "role ==", map[string]any{}).
+			Return(false, &utils.ExpressionError{Pos: 7, Message: "expected a value but found end of expression"})
+
+		recorder := run()
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "at position 7")
+	})
+
+	t.Run("unknown attribute", func(t *testing.T) {
+		_, _, run := evaluate(t, `{"condition":"true","attributes":{"age":20}}`, "7", "student")
+		assert.Equal(t, http.StatusBadRequest, run().Code)
+	})
+
+	t.Run("neither user nor attributes", func(t *testing.T) {
+		_, _, run := evaluate(t, `{"condition":"true"}`, "7", "student")
+		assert.Equal(t, http.StatusBadRequest, run().Code)
+	})
+
+	t.Run("another user", func(t *testing.T) {
+		_, _, run := evaluate(t, `{"condition":"true","user_id":8}`, "7", "student")
+		assert.Equal(t, http.StatusForbidden, run().Code)
+	})
+}
+
+func TestUserController_AddRule_InvalidCondition(t *testing.T) {
+	_, mockRulesService, c, recorder, controller := setupTest(t)
+	body := `{"Title":"T","Description":"D","ApplicationCondition":"only students"}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/rules", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("claims", &models.Claims{StandardClaims: jwt.StandardClaims{Subject: "7"}, Role: "admin"})
+
+	mockRulesService.EXPECT().CreateRule(mock.Anything, mock.Anything, 7).
+		Return(&utils.ExpressionError{Pos: 5, Message: `unexpected "students"`})
+
+	controller.AddRule(c)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// FileMailer writes every email to a .eml file in a directory instead of sending it,
+// so they can be opened with a mail client during development
+type FileMailer struct {
+	directory string
+	now       func() time.Time
+	sent      atomic.Int64
+}
+
+func NewFileMailer(directory string) *FileMailer {
+	return &FileMailer{directory: directory, now: time.Now}
+}
+
+func (m *FileMailer) Send(ctx context.Context, message Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	now := m.now()
+	document, err := render(message, now)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.directory, 0o755); err != nil {
+		return err
+	}
+	// Named so they sort in the order they were sent
+	name := fmt.Sprintf("%s-%d.eml", now.UTC().Format("20060102T150405.000000000"), m.sent.Add(1))
+	return os.WriteFile(filepath.Join(m.directory, name), document, 0o644)
+}
@@ -0,0 +1,109 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strconv"
+)
+
+// Providers a Mailer can be created for
+const (
+	ProviderSendGrid = "sendgrid"
+	ProviderSMTP     = "smtp"
+	// ProviderFile writes every message to a .eml file instead of sending it, for development
+	ProviderFile = "file"
+)
+
+var ErrNoRecipients = errors.New("the message has no recipients")
+
+// Address is a mailbox, the name is optional
+type Address struct {
+	Name  string
+	Email string
+}
+
+// String formats the address as in a message header, quoting the name when needed
+func (a Address) String() string {
+	return (&mail.Address{Name: a.Name, Address: a.Email}).String()
+}
+
+// Message is an email independent of the provider that sends it. From and ReplyTo are
+// filled with the configured ones when empty. At least one of Text and HTML must be set.
+type Message struct {
+	From    Address
+	ReplyTo Address
+	To      []Address
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer delivers emails
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// Config selects the provider emails are sent with and the address they come from
+type Config struct {
+	// One of ProviderSendGrid, ProviderSMTP and ProviderFile, SendGrid when empty
+	Provider string
+	From     Address
+	// Where replies go, From when empty
+	ReplyTo Address
+
+	SendGridAPIKey string
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+
+	// Directory the file provider writes the messages to
+	Directory string
+}
+
+// New creates the Mailer for the configured provider
+func New(config Config) (Mailer, error) {
+	var mailer Mailer
+	switch config.Provider {
+	case ProviderSendGrid, "":
+		mailer = NewSendGridMailer(config.SendGridAPIKey)
+	case ProviderSMTP:
+		if config.SMTPHost == "" {
+			return nil, errors.New("the smtp mail provider needs a host")
+		}
+		mailer = NewSMTPMailer(config.SMTPHost, strconv.Itoa(config.SMTPPort), config.SMTPUsername, config.SMTPPassword)
+	case ProviderFile:
+		mailer = NewFileMailer(config.Directory)
+	default:
+		return nil, fmt.Errorf("unknown mail provider %q", config.Provider)
+	}
+	return WithDefaults(mailer, config.From, config.ReplyTo), nil
+}
+
+type defaultsMailer struct {
+	mailer  Mailer
+	from    Address
+	replyTo Address
+}
+
+// WithDefaults returns a Mailer that fills the sender and reply-to address of
+// the messages that don't have one before sending them with mailer
+func WithDefaults(mailer Mailer, from Address, replyTo Address) Mailer {
+	return defaultsMailer{mailer: mailer, from: from, replyTo: replyTo}
+}
+
+func (m defaultsMailer) Send(ctx context.Context, message Message) error {
+	if message.From.Email == "" {
+		message.From = m.from
+	}
+	if message.ReplyTo.Email == "" {
+		message.ReplyTo = m.replyTo
+	}
+	if len(message.To) == 0 {
+		return ErrNoRecipients
+	}
+	return m.mailer.Send(ctx, message)
+}
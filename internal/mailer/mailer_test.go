@@ -0,0 +1,134 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testMessage = Message{
+	From:    Address{Name: "ClassConnect", Email: "no-reply@classconnect.test"},
+	To:      []Address{{Name: "Ana Pérez", Email: "ana@example.com"}},
+	Subject: "Código de verificación",
+	Text:    "Your code is 1-ABC123",
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		expected Mailer
+		err      bool
+	}{
+		{"sendgrid by default", Config{}, &SendGridMailer{}, false},
+		{"smtp", Config{Provider: ProviderSMTP, SMTPHost: "localhost", SMTPPort: 1025}, &SMTPMailer{}, false},
+		{"smtp without host", Config{Provider: ProviderSMTP}, nil, true},
+		{"file", Config{Provider: ProviderFile, Directory: t.TempDir()}, &FileMailer{}, false},
+		{"unknown", Config{Provider: "pigeon"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mailer, err := New(tt.config)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, tt.expected, mailer.(defaultsMailer).mailer)
+		})
+	}
+}
+
+func TestWithDefaults(t *testing.T) {
+	from := Address{Name: "ClassConnect", Email: "no-reply@classconnect.test"}
+	replyTo := Address{Email: "support@classconnect.test"}
+	ctx := context.Background()
+
+	t.Run("fills the sender", func(t *testing.T) {
+		inner := NewMockMailer(t)
+		inner.EXPECT().Send(ctx, mock.MatchedBy(func(message Message) bool {
+			return message.From == from && message.ReplyTo == replyTo
+		})).Return(nil)
+
+		err := WithDefaults(inner, from, replyTo).Send(ctx, Message{To: []Address{{Email: "ana@example.com"}}, Text: "Hi"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("keeps the message sender", func(t *testing.T) {
+		inner := NewMockMailer(t)
+		other := Address{Email: "rules@classconnect.test"}
+		inner.EXPECT().Send(ctx, mock.MatchedBy(func(message Message) bool {
+			return message.From == other && message.ReplyTo == other
+		})).Return(nil)
+
+		err := WithDefaults(inner, from, replyTo).Send(ctx, Message{From: other, ReplyTo: other, To: []Address{{Email: "ana@example.com"}}, Text: "Hi"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("without recipients", func(t *testing.T) {
+		err := WithDefaults(NewMockMailer(t), from, replyTo).Send(ctx, Message{Text: "Hi"})
+		assert.ErrorIs(t, err, ErrNoRecipients)
+	})
+}
+
+func TestRender(t *testing.T) {
+	date := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("text", func(t *testing.T) {
+		message := testMessage
+		message.ReplyTo = Address{Email: "support@classconnect.test"}
+
+		document, err := render(message, date)
+		require.NoError(t, err)
+		assert.Equal(t, "From: \"ClassConnect\" <no-reply@classconnect.test>\r\n"+
+			"Reply-To: <support@classconnect.test>\r\n"+
+			"To: =?utf-8?q?Ana_P=C3=A9rez?= <ana@example.com>\r\n"+
+			"Subject: =?utf-8?q?C=C3=B3digo_de_verificaci=C3=B3n?=\r\n"+
+			"Date: Sun, 01 Mar 2026 12:00:00 +0000\r\n"+
+			"MIME-Version: 1.0\r\n"+
+			"Content-Type: text/plain; charset=utf-8\r\n"+
+			"Content-Transfer-Encoding: quoted-printable\r\n"+
+			"\r\n"+
+			"Your code is 1-ABC123", string(document))
+	})
+
+	t.Run("text and html", func(t *testing.T) {
+		message := testMessage
+		message.HTML = "<p>Your code is <b>1-ABC123</b></p>"
+
+		document, err := render(message, date)
+		require.NoError(t, err)
+		assert.Contains(t, string(document), "Content-Type: multipart/alternative; boundary=")
+		assert.Less(t, strings.Index(string(document), "text/plain"), strings.Index(string(document), "text/html"))
+		assert.Contains(t, string(document), "<p>Your code is <b>1-ABC123</b></p>")
+	})
+
+	t.Run("without content", func(t *testing.T) {
+		_, err := render(Message{To: testMessage.To}, date)
+		assert.ErrorIs(t, err, ErrNoContent)
+	})
+}
+
+func TestFileMailer_Send(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "mail")
+	mailer := NewFileMailer(directory)
+
+	require.NoError(t, mailer.Send(context.Background(), testMessage))
+	require.NoError(t, mailer.Send(context.Background(), testMessage))
+
+	files, err := filepath.Glob(filepath.Join(directory, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	document, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(document), "To: =?utf-8?q?Ana_P=C3=A9rez?= <ana@example.com>")
+	assert.Contains(t, string(document), "Your code is 1-ABC123")
+}
@@ -0,0 +1,82 @@
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+var ErrNoContent = errors.New("the message has no text or html content")
+
+// render writes the message in the Internet Message Format (RFC 5322) used by SMTP and .eml files.
+// With both text and html the body is multipart/alternative, text first so clients prefer the html.
+func render(message Message, date time.Time) ([]byte, error) {
+	if message.Text == "" && message.HTML == "" {
+		return nil, ErrNoContent
+	}
+	var buffer bytes.Buffer
+	header := func(name string, value string) {
+		fmt.Fprintf(&buffer, "%s: %s\r\n", name, value)
+	}
+	header("From", message.From.String())
+	if message.ReplyTo.Email != "" {
+		header("Reply-To", message.ReplyTo.String())
+	}
+	to := make([]string, 0, len(message.To))
+	for _, address := range message.To {
+		to = append(to, address.String())
+	}
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", message.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+
+	if message.Text == "" || message.HTML == "" {
+		contentType, body := "text/plain", message.Text
+		if message.HTML != "" {
+			contentType, body = "text/html", message.HTML
+		}
+		header("Content-Type", contentType+"; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buffer.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buffer, body); err != nil {
+			return nil, err
+		}
+		return buffer.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&buffer)
+	header("Content-Type", "multipart/alternative; boundary="+parts.Boundary())
+	buffer.WriteString("\r\n")
+	for _, part := range []struct{ contentType, body string }{{"text/plain", message.Text}, {"text/html", message.HTML}} {
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + "; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(writer, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	writer := quotedprintable.NewWriter(w)
+	if _, err := writer.Write([]byte(body)); err != nil {
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,65 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sendgrid/rest"
+	"github.com/sendgrid/sendgrid-go"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+)
+
+type sendGridClient interface {
+	SendWithContext(ctx context.Context, email *mail.SGMailV3) (*rest.Response, error)
+}
+
+// SendGridMailer sends emails with the SendGrid API
+type SendGridMailer struct {
+	client sendGridClient
+}
+
+func NewSendGridMailer(apiKey string) *SendGridMailer {
+	return &SendGridMailer{client: sendgrid.NewSendClient(apiKey)}
+}
+
+func (m *SendGridMailer) Send(ctx context.Context, message Message) error {
+	email, err := sendGridMessage(message)
+	if err != nil {
+		return err
+	}
+	response, err := m.client.SendWithContext(ctx, email)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("sendgrid responded %d: %s", response.StatusCode, response.Body)
+	}
+	return nil
+}
+
+func sendGridMessage(message Message) (*mail.SGMailV3, error) {
+	if message.Text == "" && message.HTML == "" {
+		return nil, ErrNoContent
+	}
+	email := mail.NewV3Mail()
+	email.SetFrom(mail.NewEmail(message.From.Name, message.From.Email))
+	if message.ReplyTo.Email != "" {
+		email.SetReplyTo(mail.NewEmail(message.ReplyTo.Name, message.ReplyTo.Email))
+	}
+	email.Subject = message.Subject
+
+	personalization := mail.NewPersonalization()
+	for _, address := range message.To {
+		personalization.AddTos(mail.NewEmail(address.Name, address.Email))
+	}
+	email.AddPersonalizations(personalization)
+
+	// SendGrid requires text/plain before text/html
+	if message.Text != "" {
+		email.AddContent(mail.NewContent("text/plain", message.Text))
+	}
+	if message.HTML != "" {
+		email.AddContent(mail.NewContent("text/html", message.HTML))
+	}
+	return email, nil
+}
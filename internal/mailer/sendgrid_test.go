@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sendgrid/rest"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSendGridClient struct {
+	sent     *mail.SGMailV3
+	response *rest.Response
+}
+
+func (c *fakeSendGridClient) SendWithContext(_ context.Context, email *mail.SGMailV3) (*rest.Response, error) {
+	c.sent = email
+	return c.response, nil
+}
+
+func TestSendGridMailer_Send(t *testing.T) {
+	client := &fakeSendGridClient{response: &rest.Response{StatusCode: 202}}
+	mailer := &SendGridMailer{client: client}
+	message := testMessage
+	message.ReplyTo = Address{Email: "support@classconnect.test"}
+	message.HTML = "<p>Your code is 1-ABC123</p>"
+
+	require.NoError(t, mailer.Send(context.Background(), message))
+
+	assert.Equal(t, "no-reply@classconnect.test", client.sent.From.Address)
+	assert.Equal(t, "support@classconnect.test", client.sent.ReplyTo.Address)
+	assert.Equal(t, "ana@example.com", client.sent.Personalizations[0].To[0].Address)
+	require.Len(t, client.sent.Content, 2)
+	assert.Equal(t, "text/plain", client.sent.Content[0].Type)
+	assert.Equal(t, "text/html", client.sent.Content[1].Type)
+}
+
+func TestSendGridMailer_Send_Rejected(t *testing.T) {
+	client := &fakeSendGridClient{response: &rest.Response{StatusCode: 401, Body: "unauthorized"}}
+
+	err := (&SendGridMailer{client: client}).Send(context.Background(), testMessage)
+	assert.ErrorContains(t, err, "401")
+}
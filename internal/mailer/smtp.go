@@ -0,0 +1,111 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// SMTPTimeout bounds a whole delivery when the context doesn't end sooner, so a stalled server
+// can't hold the caller forever
+const SMTPTimeout = time.Minute
+
+// SMTPMailer sends emails through an SMTP server, upgrading to TLS when the server offers it.
+// Without a username it doesn't authenticate, as with local test servers like MailHog.
+type SMTPMailer struct {
+	host string
+	addr string
+	auth smtp.Auth
+	now  func() time.Time
+}
+
+func NewSMTPMailer(host string, port string, username string, password string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{host: host, addr: net.JoinHostPort(host, port), auth: auth, now: time.Now}
+}
+
+// Send does what smtp.SendMail does, but over a connection that gives up when the context is done
+// or SMTPTimeout passes
+func (m *SMTPMailer) Send(ctx context.Context, message Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	document, err := render(message, m.now())
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(SMTPTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	// Canceling the context interrupts whatever the client is waiting for
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if err := m.deliver(conn, message, document); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *SMTPMailer) deliver(conn net.Conn, message Message, document []byte) error {
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := client.Auth(m.auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(message.From.Email); err != nil {
+		return err
+	}
+	for _, address := range message.To {
+		if err := client.Rcpt(address.Email); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(document); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,145 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpMessage is what a client sent to the stand-in SMTP server
+type smtpMessage struct {
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+// serveSMTP accepts one connection on a local port, speaking just enough SMTP for net/smtp,
+// like MailHog does, and sends what it received on the returned channel
+func serveSMTP(t *testing.T) (string, <-chan smtpMessage) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan smtpMessage, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		var message smtpMessage
+		reply("220 localhost ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.TrimRight(line, "\r\n")
+			switch verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0]); verb {
+			case "EHLO":
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			case "AUTH":
+				message.auth = command
+				reply("235 Authenticated")
+			case "MAIL":
+				message.from = strings.TrimSuffix(strings.TrimPrefix(command, "MAIL FROM:<"), ">")
+				reply("250 OK")
+			case "RCPT":
+				message.to = append(message.to, strings.TrimSuffix(strings.TrimPrefix(command, "RCPT TO:<"), ">"))
+				reply("250 OK")
+			case "DATA":
+				reply("354 Go ahead")
+				var data strings.Builder
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				message.data = data.String()
+				reply("250 Queued")
+			case "QUIT":
+				reply("221 Bye")
+				received <- message
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	addr, received := serveSMTP(t)
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+
+	mailer := NewSMTPMailer(host, port, "user", "secret")
+	message := testMessage
+	message.To = append(message.To, Address{Email: "admin@example.com"})
+
+	require.NoError(t, mailer.Send(context.Background(), message))
+
+	sent := <-received
+	assert.Contains(t, sent.auth, "AUTH PLAIN")
+	assert.Equal(t, "no-reply@classconnect.test", sent.from)
+	assert.Equal(t, []string{"ana@example.com", "admin@example.com"}, sent.to)
+	assert.Contains(t, sent.data, "Subject: =?utf-8?q?C=C3=B3digo_de_verificaci=C3=B3n?=")
+	assert.Contains(t, sent.data, "Your code is 1-ABC123")
+}
+
+func TestSMTPMailer_Send_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := NewSMTPMailer("127.0.0.1", "1", "", "").Send(ctx, testMessage)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSMTPMailer_Send_StalledServer(t *testing.T) {
+	// Accepts the connection but never greets, like an overloaded server
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			accepted <- conn
+		}
+	}()
+	t.Cleanup(func() {
+		select {
+		case conn := <-accepted:
+			conn.Close()
+		default:
+		}
+	})
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- NewSMTPMailer(host, port, "", "").Send(ctx, testMessage) }()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Send didn't give up on a stalled server")
+	}
+}
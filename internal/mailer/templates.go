@@ -0,0 +1,215 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+)
+
+// DefaultLocale is the locale emails are written in when there is no variant for the ones the user accepts
+const DefaultLocale = utils.DefaultLocale
+
+// Names of the email templates
+const (
+	TemplateVerification      = "verification"
+	TemplatePasswordReset     = "password_reset"
+	TemplateInvitation        = "invitation"
+	TemplateEmailChange       = "email_change"
+	TemplateEmailChangeNotice = "email_change_notice"
+	TemplateNotification      = "notification"
+)
+
+// localesKey is the context key holding the locales the user accepts, most preferred first
+type localesKey struct{}
+
+var ErrUnknownTemplate = errors.New("unknown email template")
+
+// WithLocales returns a copy of ctx whose emails are rendered in the first of the locales there is a variant for
+func WithLocales(ctx context.Context, locales []string) context.Context {
+	return context.WithValue(ctx, localesKey{}, locales)
+}
+
+// LocalesFromContext returns the locales stored by WithLocales, none outside of a request
+func LocalesFromContext(ctx context.Context) []string {
+	locales, _ := ctx.Value(localesKey{}).([]string)
+	return locales
+}
+
+// CodeData fills the templates that send a code to confirm an email address
+type CodeData struct {
+	Code string
+	// Minutes until the code expires
+	Minutes int
+}
+
+// TokenData fills the templates that send a password reset token
+type TokenData struct {
+	Name  string
+	Token string
+	// Opens the app on the reset screen
+	Link string
+}
+
+type EmailChangeNoticeData struct {
+	NewEmail   string
+	CancelLink string
+}
+
+type NotificationData struct {
+	Title string
+	Text  string
+	// Link opens the notification in the inbox of the app
+	Link string
+}
+
+// templateSamples are the data each template is previewed and tested with
+var templateSamples = map[string]any{
+	TemplateVerification:      CodeData{Code: "12-A1B2C3", Minutes: 5},
+	TemplatePasswordReset:     TokenData{Token: "9-X7Y8Z9", Link: "https://classconnect.example/users/reset/password?token=9-X7Y8Z9"},
+	TemplateInvitation:        TokenData{Name: "Ana", Token: "3-Q1W2E3", Link: "https://classconnect.example/users/reset/password?token=3-Q1W2E3"},
+	TemplateEmailChange:       CodeData{Code: "7-K9L8M7", Minutes: 5},
+	TemplateEmailChangeNotice: EmailChangeNoticeData{NewEmail: "ana.nueva@example.com", CancelLink: "https://classconnect.example/users/email/cancel?token=abc123"},
+	TemplateNotification:      NotificationData{Title: "Nueva regla vigente: Asistencia", Text: "Hay que asistir al 75% de las clases.", Link: "https://classconnect.example/users/3/inbox/12"},
+}
+
+// SampleData returns the data the template is previewed with
+func SampleData(name string) (any, error) {
+	data, ok := templateSamples[name]
+	if !ok {
+		return nil, ErrUnknownTemplate
+	}
+	return data, nil
+}
+
+// Email is a template rendered for a locale, ready to be addressed and sent
+type Email struct {
+	Template string `json:"template"`
+	Locale   string `json:"locale"`
+	Subject  string `json:"subject"`
+	Text     string `json:"text"`
+	HTML     string `json:"html"`
+}
+
+// Message addresses the email to the recipients
+func (e Email) Message(to ...Address) Message {
+	return Message{To: to, Subject: e.Subject, Text: e.Text, HTML: e.HTML}
+}
+
+//go:embed templates
+var templateFiles embed.FS
+
+// templates are the parsed email templates, by locale and name
+var templates = mustParseTemplates(templateFiles)
+
+type emailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// layoutData is what the layouts are executed with, the template's own data is in Data
+type layoutData struct {
+	Locale  string
+	Subject string
+	Data    any
+}
+
+type buttonData struct {
+	Link  string
+	Label string
+}
+
+var templateFuncs = map[string]any{
+	"button": func(link string, label string) buttonData { return buttonData{Link: link, Label: label} },
+}
+
+// mustParseTemplates parses every templates/<locale>/<name>.txt with its .html alternate, the
+// locale's footer and the shared layouts. The templates are embedded, so failing to parse them is a bug.
+func mustParseTemplates(files fs.FS) map[string]map[string]emailTemplate {
+	parsed := map[string]map[string]emailTemplate{}
+	locales, err := fs.ReadDir(files, "templates")
+	if err != nil {
+		panic(err)
+	}
+	for _, locale := range locales {
+		if !locale.IsDir() {
+			continue
+		}
+		parsed[locale.Name()] = map[string]emailTemplate{}
+		for _, name := range Templates() {
+			dir := path.Join("templates", locale.Name())
+			text, err := texttemplate.New("layout").Funcs(templateFuncs).ParseFS(files,
+				"templates/layout.txt", path.Join(dir, "footer.txt"), path.Join(dir, name+".txt"))
+			if err != nil {
+				panic(err)
+			}
+			html, err := htmltemplate.New("layout").Funcs(templateFuncs).ParseFS(files,
+				"templates/layout.html", "templates/partials.html", path.Join(dir, "footer.html"), path.Join(dir, name+".html"))
+			if err != nil {
+				panic(err)
+			}
+			parsed[locale.Name()][name] = emailTemplate{text: text, html: html}
+		}
+	}
+	return parsed
+}
+
+// Templates returns the names of the email templates
+func Templates() []string {
+	names := make([]string, 0, len(templateSamples))
+	for name := range templateSamples {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Locales returns the locales the emails are written in, DefaultLocale first
+func Locales() []string {
+	locales := []string{DefaultLocale}
+	for locale := range templates {
+		if locale != DefaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	slices.Sort(locales[1:])
+	return locales
+}
+
+// Render fills the template with data in the first of the locales there is a variant for,
+// or in DefaultLocale. The text is the plain alternate of the html.
+func Render(name string, locales []string, data any) (Email, error) {
+	if _, ok := templateSamples[name]; !ok {
+		return Email{}, fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+	locale := DefaultLocale
+	for _, accepted := range locales {
+		if _, ok := templates[strings.ToLower(accepted)]; ok {
+			locale = strings.ToLower(accepted)
+			break
+		}
+	}
+	template := templates[locale][name]
+
+	var subject, text, html bytes.Buffer
+	if err := template.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Email{}, err
+	}
+	layout := layoutData{Locale: locale, Subject: strings.TrimSpace(subject.String()), Data: data}
+	if err := template.text.ExecuteTemplate(&text, "layout", layout); err != nil {
+		return Email{}, err
+	}
+	if err := template.html.ExecuteTemplate(&html, "layout", layout); err != nil {
+		return Email{}, err
+	}
+	return Email{Template: name, Locale: locale, Subject: layout.Subject, Text: text.String(), HTML: html.String()}, nil
+}
@@ -0,0 +1,77 @@
+package mailer
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the email templates")
+
+// TestRender_Golden renders every template with its sample data in every locale and compares it
+// with testdata/golden/<locale>/<name>. Run with -update after changing a template and review the diff.
+func TestRender_Golden(t *testing.T) {
+	for _, locale := range Locales() {
+		for _, name := range Templates() {
+			t.Run(locale+"/"+name, func(t *testing.T) {
+				data, err := SampleData(name)
+				require.NoError(t, err)
+				email, err := Render(name, []string{locale}, data)
+				require.NoError(t, err)
+				assert.Equal(t, locale, email.Locale)
+
+				text := "Subject: " + email.Subject + "\n\n" + email.Text
+				assertGolden(t, filepath.Join("testdata", "golden", locale, name+".txt"), text)
+				assertGolden(t, filepath.Join("testdata", "golden", locale, name+".html"), email.HTML)
+			})
+		}
+	}
+}
+
+func assertGolden(t *testing.T, path string, got string) {
+	t.Helper()
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+		return
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "run go test ./internal/mailer -update to create it")
+	assert.Equal(t, string(want), got)
+}
+
+func TestRender_Locale(t *testing.T) {
+	data := CodeData{Code: "1-ABC123", Minutes: 5}
+
+	email, err := Render(TemplateVerification, []string{"fr", "en-us", "en"}, data)
+	require.NoError(t, err)
+	assert.Equal(t, "en", email.Locale)
+
+	email, err = Render(TemplateVerification, []string{"fr"}, data)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultLocale, email.Locale)
+
+	_, err = Render("newsletter", nil, data)
+	assert.ErrorIs(t, err, ErrUnknownTemplate)
+}
+
+func TestRender_EscapesHTML(t *testing.T) {
+	email, err := Render(TemplateNotification, nil, NotificationData{Title: "<b>Hi</b>", Text: `<script>alert("x")</script>`})
+	require.NoError(t, err)
+
+	assert.Equal(t, "<b>Hi</b>", email.Subject)
+	assert.Contains(t, email.Text, `<script>alert("x")</script>`)
+	assert.NotContains(t, email.HTML, "<script>")
+	assert.Contains(t, email.HTML, "&lt;script&gt;")
+}
+
+func TestEmail_Message(t *testing.T) {
+	email := Email{Subject: "Asunto", Text: "texto", HTML: "<p>texto</p>"}
+	to := Address{Name: "Ana", Email: "ana@example.com"}
+
+	assert.Equal(t, Message{To: []Address{to}, Subject: "Asunto", Text: "texto", HTML: "<p>texto</p>"}, email.Message(to))
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/go-core/pkg/log"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDKey is the context key holding the id of the request
+	RequestIDKey = "request_id"
+)
+
+// RequestID gives every request an id, the one the client sent in X-Request-ID if it is
+// reasonable, and returns it in the same header so logs and audit events can be matched
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(RequestIDHeader)
+		if id == "" || len(id) > 100 || strings.ContainsFunc(id, func(r rune) bool { return r < 0x21 || r > 0x7e }) {
+			buf := make([]byte, 16)
+			_, _ = rand.Read(buf)
+			id = hex.EncodeToString(buf)
+		}
+		ctx.Set(RequestIDKey, id)
+		ctx.Header(RequestIDHeader, id)
+		ctx.Next()
+	}
+}
+
+// Audit records every request that changes something in the audit log, once it has been handled.
+// Handlers can describe the action with models.AuditFromContext. Requests made by users that
+// aren't admins, acting on their own account, and requests rejected before authenticating aren't recorded.
+func Audit(auditService services.AuditService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		method := ctx.Request.Method
+		if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+			ctx.Next()
+			return
+		}
+
+		details := &models.AuditDetails{}
+		ctx.Set(models.AuditDetailsKey, details)
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		event := &models.AuditEvent{
+			Action:     details.Action,
+			TargetType: details.TargetType,
+			TargetId:   details.TargetId,
+			StatusCode: status,
+			IP:         ctx.ClientIP(),
+			RequestId:  ctx.GetString(RequestIDKey),
+		}
+
+		claims, err := models.GetClaimsFromGinContext(ctx)
+		if err != nil {
+			if status == http.StatusUnauthorized || status == http.StatusForbidden {
+				return
+			}
+		} else {
+			if claims.Role != "admin" {
+				return
+			}
+			if id, err := strconv.Atoi(claims.Subject); err == nil {
+				event.ActorId = &id
+			}
+		}
+
+		if event.Action == "" {
+			event.Action = method + " " + ctx.FullPath()
+		}
+		if event.TargetType == "" && ctx.Param("id") != "" {
+			// The resource the route is about, /users/:id is a user
+			segment := strings.Split(strings.TrimPrefix(ctx.FullPath(), "/"), "/")[0]
+			event.TargetType = strings.TrimSuffix(segment, "s")
+			event.TargetId = ctx.Param("id")
+		}
+		event.Before = marshalAuditState(ctx, details.Before)
+		event.After = marshalAuditState(ctx, details.After)
+
+		// The response is already written, recording must not depend on the client waiting for it
+		if err := auditService.Record(context.WithoutCancel(ctx.Request.Context()), event); err != nil {
+			log.Error(ctx.Request.Context(), "Error recording audit event", "action", event.Action, "error", err.Error())
+		}
+	}
+}
+
+func marshalAuditState(ctx *gin.Context, state any) json.RawMessage {
+	if state == nil {
+		return nil
+	}
+	document, err := json.Marshal(state)
+	if err != nil {
+		log.Warn(ctx.Request.Context(), "Error encoding audit state", "error", err.Error())
+		return nil
+	}
+	// Handlers pass nil pointers for targets they couldn't load
+	if string(document) == "null" {
+		return nil
+	}
+	return document
+}
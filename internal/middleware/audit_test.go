@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setClaims stands in for the authentication middleware
+func setClaims(subject string, role string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set("claims", &models.Claims{StandardClaims: jwt.StandardClaims{Subject: subject}, Role: role})
+	}
+}
+
+func TestAudit_RecordsAdminAction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockAudit := services.NewMockAuditService(t)
+
+	var recorded *models.AuditEvent
+	mockAudit.EXPECT().Record(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, event *models.AuditEvent) { recorded = event }).
+		Return(nil)
+
+	r := gin.New()
+	r.Use(RequestID())
+	r.PUT("/users/:id/block", Audit(mockAudit), setClaims("7", "admin"), func(ctx *gin.Context) {
+		models.AuditFromContext(ctx).Describe("user.block", "user", 3)
+		models.AuditFromContext(ctx).Change(map[string]bool{"blocked": false}, map[string]bool{"blocked": true})
+		ctx.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/users/3/block", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "req-123", w.Header().Get(RequestIDHeader))
+	if assert.NotNil(t, recorded) {
+		assert.Equal(t, 7, *recorded.ActorId)
+		assert.Equal(t, "user.block", recorded.Action)
+		assert.Equal(t, "user", recorded.TargetType)
+		assert.Equal(t, "3", recorded.TargetId)
+		assert.JSONEq(t, `{"blocked":false}`, string(recorded.Before))
+		assert.JSONEq(t, `{"blocked":true}`, string(recorded.After))
+		assert.Equal(t, http.StatusOK, recorded.StatusCode)
+		assert.Equal(t, "req-123", recorded.RequestId)
+	}
+}
+
+func TestAudit_DefaultsFromRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockAudit := services.NewMockAuditService(t)
+
+	var recorded *models.AuditEvent
+	mockAudit.EXPECT().Record(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, event *models.AuditEvent) { recorded = event }).
+		Return(errors.New("db error"))
+
+	r := gin.New()
+	r.DELETE("/rules/:id", Audit(mockAudit), setClaims("7", "admin"), func(ctx *gin.Context) {
+		// The rule wasn't found to record it as it was
+		var before *models.Rule
+		models.AuditFromContext(ctx).Change(before, nil)
+		ctx.Status(http.StatusNotFound)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/rules/5", nil))
+
+	// A failure to record doesn't change the response
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	if assert.NotNil(t, recorded) {
+		assert.Equal(t, "DELETE /rules/:id", recorded.Action)
+		assert.Equal(t, "rule", recorded.TargetType)
+		assert.Equal(t, "5", recorded.TargetId)
+		assert.Equal(t, http.StatusNotFound, recorded.StatusCode)
+		assert.Nil(t, recorded.Before)
+	}
+}
+
+func TestAudit_SkipsUnprivilegedRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// The mock fails the test if anything is recorded
+	mockAudit := services.NewMockAuditService(t)
+
+	r := gin.New()
+	r.GET("/admin/stats", Audit(mockAudit), setClaims("7", "admin"), func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	r.PUT("/users/:id", Audit(mockAudit), setClaims("3", "student"), func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	r.POST("/admin/users/bulk", Audit(mockAudit), func(ctx *gin.Context) { ctx.AbortWithStatus(http.StatusUnauthorized) })
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/admin/stats", nil),
+		httptest.NewRequest(http.MethodPut, "/users/3", strings.NewReader("{}")),
+		httptest.NewRequest(http.MethodPost, "/admin/users/bulk", nil),
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+	}
+}
+
+func TestRequestID_GeneratesInvalidOrMissingIds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestID())
+	r.GET("/", func(ctx *gin.Context) {
+		assert.Equal(t, ctx.Writer.Header().Get(RequestIDHeader), ctx.GetString(RequestIDKey))
+		ctx.Status(http.StatusOK)
+	})
+
+	for _, sent := range []string{"", "has spaces", strings.Repeat("a", 101)} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, sent)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Len(t, w.Header().Get(RequestIDHeader), 32)
+	}
+}
@@ -41,6 +41,10 @@ func AuthMiddleware(userService services.UserService) gin.HandlerFunc {
 			return
 		}
 
+		if rejectRevokedSession(ctx, userService, uId, claims) {
+			return
+		}
+
 		// Refresh token if it is about to expire
 		if claims.ExpiresAt < time.Now().Add(time.Minute*5).Unix() {
 			newToken, err := models.GenerateToken(uId, claims.Email, claims.Name, claims.Role)
@@ -56,6 +60,26 @@ func AuthMiddleware(userService services.UserService) gin.HandlerFunc {
 	}
 }
 
+// rejectRevokedSession aborts the request if the token was issued before the user's sessions
+// were revoked, and reports whether it did
+func rejectRevokedSession(ctx *gin.Context, userService services.UserService, uId int, claims *models.Claims) bool {
+	revoked, err := userService.IsSessionRevoked(ctx.Request.Context(), uId, time.Unix(claims.IssuedAt, 0))
+	if err != nil {
+		utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+		ctx.Abort()
+		return true
+	}
+
+	if revoked {
+		utils.ErrorResponse(ctx, http.StatusUnauthorized, "Session expired, please log in again")
+		ctx.SetCookie("Authorization", "", -1, "/", "", false, true)
+		ctx.Abort()
+		return true
+	}
+
+	return false
+}
+
 // getAuthToken extracts the authentication token from either cookie or Authorization header
 func getAuthToken(c *gin.Context) string {
 	auth, _ := c.Cookie("Authorization")
@@ -88,6 +112,10 @@ func AdminOnlyMiddleware(userService services.UserService) gin.HandlerFunc {
 			return
 		}
 
+		if rejectRevokedSession(ctx, userService, uId, claims) {
+			return
+		}
+
 		// Refresh token if it is about to expire
 		if claims.ExpiresAt < time.Now().Add(time.Minute*5).Unix() {
 			newToken, err := models.GenerateToken(uId, claims.Email, claims.Name, claims.Role)
@@ -130,6 +158,10 @@ func UserOrAdminMiddleware(userService services.UserService) gin.HandlerFunc {
 			return
 		}
 
+		if rejectRevokedSession(ctx, userService, uId, claims) {
+			return
+		}
+
 		// Get the ID from path parameter
 		pathIdStr := ctx.Param("id")
 		if pathIdStr == "" {
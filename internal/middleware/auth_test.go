@@ -29,6 +29,7 @@ func TestAuthMiddleware_ValidToken(t *testing.T) {
 
 	mockService := services.NewMockUserService(t)
 	mockService.EXPECT().IsUserBlocked(mock.Anything, userID).Return(false, nil)
+	mockService.EXPECT().IsSessionRevoked(mock.Anything, userID, mock.Anything).Return(false, nil)
 
 	r := gin.New()
 	r.Use(AuthMiddleware(mockService))
@@ -143,6 +144,7 @@ func TestAuthMiddleware_TokenRefresh(t *testing.T) {
 
 	mockService := services.NewMockUserService(t)
 	mockService.EXPECT().IsUserBlocked(mock.Anything, userID).Return(false, nil)
+	mockService.EXPECT().IsSessionRevoked(mock.Anything, userID, mock.Anything).Return(false, nil)
 
 	r := gin.New()
 	r.Use(AuthMiddleware(mockService))
@@ -176,6 +178,7 @@ func TestAdminOnlyMiddleware_AdminAccess(t *testing.T) {
 	assert.NoError(t, err)
 
 	mockService := services.NewMockUserService(t)
+	mockService.EXPECT().IsSessionRevoked(mock.Anything, userID, mock.Anything).Return(false, nil)
 
 	r := gin.New()
 	r.Use(AdminOnlyMiddleware(mockService))
@@ -257,6 +260,7 @@ func TestAdminOnlyMiddleware_RefreshToken(t *testing.T) {
 	assert.NoError(t, err)
 
 	mockService := services.NewMockUserService(t)
+	mockService.EXPECT().IsSessionRevoked(mock.Anything, userID, mock.Anything).Return(false, nil)
 
 	r := gin.New()
 	r.Use(AdminOnlyMiddleware(mockService))
@@ -285,6 +289,7 @@ func TestUserOrAdminMiddleware_NormalUser(t *testing.T) {
 
 	mockService := services.NewMockUserService(t)
 	mockService.EXPECT().IsUserBlocked(mock.Anything, userID).Return(false, nil)
+	mockService.EXPECT().IsSessionRevoked(mock.Anything, userID, mock.Anything).Return(false, nil)
 
 	r := gin.New()
 	r.Use(UserOrAdminMiddleware(mockService))
@@ -327,6 +332,7 @@ func TestUserOrAdminMiddleware_ForbiddenAccess(t *testing.T) {
 
 	mockService := services.NewMockUserService(t)
 	mockService.EXPECT().IsUserBlocked(mock.Anything, userID).Return(false, nil)
+	mockService.EXPECT().IsSessionRevoked(mock.Anything, userID, mock.Anything).Return(false, nil)
 
 	r := gin.New()
 	r.Use(UserOrAdminMiddleware(mockService))
@@ -348,6 +354,7 @@ func TestUserOrAdminMiddleware_AdminAccess(t *testing.T) {
 
 	mockService := services.NewMockUserService(t)
 	mockService.EXPECT().IsUserBlocked(mock.Anything, adminID).Return(false, nil)
+	mockService.EXPECT().IsSessionRevoked(mock.Anything, adminID, mock.Anything).Return(false, nil)
 
 	r := gin.New()
 	r.Use(UserOrAdminMiddleware(mockService))
@@ -369,6 +376,7 @@ func TestUserOrAdminMiddleware_MissingPathParam(t *testing.T) {
 
 	mockService := services.NewMockUserService(t)
 	mockService.EXPECT().IsUserBlocked(mock.Anything, userID).Return(false, nil)
+	mockService.EXPECT().IsSessionRevoked(mock.Anything, userID, mock.Anything).Return(false, nil)
 
 	r := gin.New()
 	r.Use(UserOrAdminMiddleware(mockService))
@@ -391,6 +399,7 @@ func TestUserOrAdminMiddleware_InvalidPathParam(t *testing.T) {
 
 	mockService := services.NewMockUserService(t)
 	mockService.EXPECT().IsUserBlocked(mock.Anything, userID).Return(false, nil)
+	mockService.EXPECT().IsSessionRevoked(mock.Anything, userID, mock.Anything).Return(false, nil)
 
 	r := gin.New()
 	r.Use(UserOrAdminMiddleware(mockService))
@@ -428,6 +437,7 @@ func TestUserOrAdminMiddleware_RefreshToken(t *testing.T) {
 
 	mockService := services.NewMockUserService(t)
 	mockService.EXPECT().IsUserBlocked(mock.Anything, userID).Return(false, nil)
+	mockService.EXPECT().IsSessionRevoked(mock.Anything, userID, mock.Anything).Return(false, nil)
 
 	r := gin.New()
 	r.Use(UserOrAdminMiddleware(mockService))
@@ -448,3 +458,47 @@ func TestUserOrAdminMiddleware_RefreshToken(t *testing.T) {
 	}
 	assert.True(t, found, "Expected refreshed token cookie")
 }
+
+func TestAuthMiddleware_RevokedSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := 123
+
+	token, err := models.GenerateToken(userID, "test@example.com", "test", "student")
+	assert.NoError(t, err)
+
+	mockService := services.NewMockUserService(t)
+	mockService.EXPECT().IsUserBlocked(mock.Anything, userID).Return(false, nil)
+	mockService.EXPECT().IsSessionRevoked(mock.Anything, userID, mock.Anything).Return(true, nil)
+
+	r := gin.New()
+	r.Use(AuthMiddleware(mockService))
+	r.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := performRequestWithToken(r, token, "/")
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestUserOrAdminMiddleware_SessionCheckError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := 5
+
+	token, err := models.GenerateToken(userID, "user@test.com", "user", "student")
+	assert.NoError(t, err)
+
+	mockService := services.NewMockUserService(t)
+	mockService.EXPECT().IsUserBlocked(mock.Anything, userID).Return(false, nil)
+	mockService.EXPECT().IsSessionRevoked(mock.Anything, userID, mock.Anything).Return(false, sql.ErrConnDone)
+
+	r := gin.New()
+	r.Use(UserOrAdminMiddleware(mockService))
+	r.GET("/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := performRequestWithToken(r, token, "/users/5")
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
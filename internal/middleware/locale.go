@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/mailer"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Locale stores the locales of the Accept-Language header in the context, so the emails
+// sent while handling the request are written in the user's language
+func Locale() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		locales := utils.AcceptedLocales(ctx.GetHeader("Accept-Language"))
+		ctx.Request = ctx.Request.WithContext(mailer.WithLocales(ctx.Request.Context(), locales))
+		ctx.Next()
+	}
+}
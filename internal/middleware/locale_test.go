@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/mailer"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocale(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var fromRequest []string
+	r := gin.New()
+	r.GET("/", Locale(), func(c *gin.Context) {
+		fromRequest = mailer.LocalesFromContext(c.Request.Context())
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "en-US,es;q=0.5")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, []string{"en-us", "en", "es"}, fromRequest)
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireIfMatch rejects writes that don't carry an If-Match header, so clients
+// can't overwrite a resource without proving which version they are changing
+func RequireIfMatch() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("If-Match") == "" {
+			utils.ErrorResponse(ctx, http.StatusPreconditionRequired, "If-Match header is required")
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
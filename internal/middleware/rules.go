@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PendingRulesError is returned to users that must accept rules before going on
+type PendingRulesError struct {
+	utils.HTTPError
+	PendingRules []models.PendingRule `json:"pending_rules"`
+}
+
+// RequireAcceptedRules rejects requests of users that haven't accepted the current version of every
+// mandatory rule, listing them. Users that never accepted one of them get 451, users that only have to
+// accept new versions of rules they already accepted get 428. Admins aren't held to the rules.
+// It must run after AuthMiddleware.
+func RequireAcceptedRules(rulesService services.RulesService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		claims, err := models.GetClaimsFromGinContext(ctx)
+		if err != nil {
+			utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+			ctx.Abort()
+			return
+		}
+		if claims.Role == "admin" {
+			ctx.Next()
+			return
+		}
+		userId, err := strconv.Atoi(claims.Subject)
+		if err != nil {
+			utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+			ctx.Abort()
+			return
+		}
+
+		pending, err := rulesService.GetPendingRules(ctx.Request.Context(), userId)
+		if err != nil {
+			utils.ErrorResponseWithErr(ctx, http.StatusInternalServerError, err)
+			ctx.Abort()
+			return
+		}
+		if len(pending) == 0 {
+			ctx.Next()
+			return
+		}
+
+		code, message := http.StatusPreconditionRequired, "New versions of rules must be accepted"
+		for _, rule := range pending {
+			if rule.AcceptedVersion == 0 {
+				code, message = http.StatusUnavailableForLegalReasons, "Rules must be accepted"
+				break
+			}
+		}
+		ctx.AbortWithStatusJSON(code, PendingRulesError{
+			HTTPError:    utils.HTTPError{Code: code, Title: http.StatusText(code), Error: message},
+			PendingRules: pending,
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequireAcceptedRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		role           string
+		pending        []models.PendingRule
+		expectedStatus int
+	}{
+		{name: "everything accepted", role: "student", pending: []models.PendingRule{}, expectedStatus: http.StatusOK},
+		{name: "never accepted", role: "student", pending: []models.PendingRule{
+			{Rule: models.Rule{Id: 1, Version: 4}, AcceptedVersion: 3},
+			{Rule: models.Rule{Id: 2, Version: 1}},
+		}, expectedStatus: http.StatusUnavailableForLegalReasons},
+		{name: "new version to accept", role: "student", pending: []models.PendingRule{
+			{Rule: models.Rule{Id: 1, Version: 4}, AcceptedVersion: 3},
+		}, expectedStatus: http.StatusPreconditionRequired},
+		{name: "admins are not held to the rules", role: "admin", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rulesService := services.NewMockRulesService(t)
+			if tt.pending != nil {
+				rulesService.EXPECT().GetPendingRules(mock.Anything, 7).Return(tt.pending, nil)
+			}
+
+			r := gin.New()
+			r.Use(func(ctx *gin.Context) {
+				ctx.Set("claims", &models.Claims{StandardClaims: jwt.StandardClaims{Subject: "7"}, Role: tt.role})
+			})
+			r.Use(RequireAcceptedRules(rulesService))
+			r.GET("/", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"pending_rules":[`)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE rules ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- Every update bumps the row version so concurrent writers can detect each other
+CREATE OR REPLACE FUNCTION increment_version_column()
+RETURNS TRIGGER AS $$
+BEGIN
+   NEW.version = OLD.version + 1;
+   RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER trigger_users_version
+BEFORE UPDATE ON users
+FOR EACH ROW
+EXECUTE FUNCTION increment_version_column();
+
+CREATE TRIGGER trigger_rules_version
+BEFORE UPDATE ON rules
+FOR EACH ROW
+EXECUTE FUNCTION increment_version_column();
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS email_change_requests (
+    id SERIAL PRIMARY KEY,
+    user_id int NOT NULL,
+    old_email VARCHAR(255) NOT NULL,
+    new_email VARCHAR(255) NOT NULL,
+    verification_pin VARCHAR(6) NOT NULL,
+    cancel_token VARCHAR(64) NOT NULL UNIQUE,
+    pin_expiration TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Emails are compared case-insensitively everywhere, so enforce it in the schema too.
+-- Accounts whose emails differ only in case have to be merged by hand first, the index can't pick one.
+DO $$
+DECLARE
+    duplicated TEXT;
+BEGIN
+    SELECT string_agg(DISTINCT LOWER(email), ', ') INTO duplicated
+    FROM users
+    WHERE LOWER(email) IN (SELECT LOWER(email) FROM users GROUP BY LOWER(email) HAVING COUNT(*) > 1);
+    IF duplicated IS NOT NULL THEN
+        RAISE EXCEPTION 'users with emails that differ only in case must be merged before migrating: %', duplicated;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_idx ON users (LOWER(email));
+
+-- Tokens issued before this instant are rejected by the auth middleware
+ALTER TABLE users ADD COLUMN sessions_valid_after TIMESTAMPTZ;
+-- +goose StatementEnd
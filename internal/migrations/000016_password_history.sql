@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS password_history (
+    id SERIAL PRIMARY KEY,
+    user_id int NOT NULL,
+    password VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS password_history_user_idx ON password_history (user_id, created_at DESC);
+
+-- Current passwords are the first entry of every history
+INSERT INTO password_history (user_id, password)
+SELECT id, password FROM users WHERE password IS NOT NULL;
+
+-- Every password a user gets, however it is set, is recorded
+CREATE OR REPLACE FUNCTION record_password_history()
+RETURNS TRIGGER AS $$
+BEGIN
+   IF NEW.password IS NOT NULL AND (TG_OP = 'INSERT' OR NEW.password IS DISTINCT FROM OLD.password) THEN
+      INSERT INTO password_history (user_id, password) VALUES (NEW.id, NEW.password);
+   END IF;
+   RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER trigger_users_password_history
+AFTER INSERT OR UPDATE OF password ON users
+FOR EACH ROW
+EXECUTE FUNCTION record_password_history();
+-- +goose StatementEnd
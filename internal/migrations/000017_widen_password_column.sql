@@ -0,0 +1,6 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Argon2id hashes don't fit in the 60 characters of a bcrypt hash
+ALTER TABLE users
+    ALTER COLUMN password TYPE VARCHAR(255);
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tokens were stored in plain text, none of them can be trusted anymore
+DELETE FROM password_reset;
+
+ALTER TABLE password_reset
+    DROP COLUMN token,
+    ADD COLUMN token_hash VARCHAR(64) NOT NULL,
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS password_reset_user_idx ON password_reset (user_id) WHERE used = false;
+-- +goose StatementEnd
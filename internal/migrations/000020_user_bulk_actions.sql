@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- One row per bulk action, with every user it changed
+CREATE TABLE IF NOT EXISTS user_bulk_actions (
+    id SERIAL PRIMARY KEY,
+    actor_id int,
+    action VARCHAR(20) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}',
+    user_ids INT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL
+);
+-- +goose StatementEnd
@@ -0,0 +1,93 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Audit entries must keep the ids they were written with, even after the rule or user is deleted,
+-- otherwise their hashes would no longer match
+ALTER TABLE rules_audit DROP CONSTRAINT IF EXISTS rules_audit_rule_id_fkey;
+ALTER TABLE rules_audit DROP CONSTRAINT IF EXISTS rules_audit_user_id_fkey;
+UPDATE rules_audit SET modification_date = CURRENT_TIMESTAMP WHERE modification_date IS NULL;
+ALTER TABLE rules_audit ALTER COLUMN modification_date SET NOT NULL;
+
+ALTER TABLE rules_audit ADD COLUMN prev_hash TEXT;
+ALTER TABLE rules_audit ADD COLUMN hash TEXT;
+
+-- Must match models.Audit.ComputeHash
+CREATE OR REPLACE FUNCTION rules_audit_entry_hash(prev_hash TEXT, id INTEGER, rule_id INTEGER, user_id INTEGER, modification_date TIMESTAMP, nature TEXT)
+RETURNS TEXT AS $$
+   SELECT encode(sha256(convert_to(concat_ws(E'\n',
+       prev_hash,
+       id::text,
+       COALESCE(rule_id::text, ''),
+       COALESCE(user_id::text, ''),
+       to_char(modification_date, 'YYYY-MM-DD"T"HH24:MI:SS.US'),
+       nature
+   ), 'UTF8')), 'hex');
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Chain the entries written before this migration
+DO $$
+DECLARE
+   entry RECORD;
+   last_hash TEXT := repeat('0', 64);
+BEGIN
+   FOR entry IN SELECT * FROM rules_audit ORDER BY id LOOP
+      UPDATE rules_audit
+      SET prev_hash = last_hash,
+          hash = rules_audit_entry_hash(last_hash, entry.id, entry.rule_id, entry.user_id, entry.modification_date, entry.nature_of_modification)
+      WHERE id = entry.id
+      RETURNING hash INTO last_hash;
+   END LOOP;
+END $$;
+
+ALTER TABLE rules_audit ALTER COLUMN prev_hash SET NOT NULL;
+ALTER TABLE rules_audit ALTER COLUMN hash SET NOT NULL;
+
+-- Links every new entry to the last one. Writers take turns and the id is assigned while holding the lock,
+-- so ids follow the chain even when transactions commit out of order
+CREATE OR REPLACE FUNCTION chain_rules_audit()
+RETURNS TRIGGER AS $$
+DECLARE
+   last_hash TEXT;
+BEGIN
+   PERFORM pg_advisory_xact_lock(hashtext('rules_audit_chain'));
+   NEW.id = nextval(pg_get_serial_sequence('rules_audit', 'id'));
+   SELECT hash INTO last_hash FROM rules_audit ORDER BY id DESC LIMIT 1;
+   NEW.prev_hash = COALESCE(last_hash, repeat('0', 64));
+   NEW.hash = rules_audit_entry_hash(NEW.prev_hash, NEW.id, NEW.rule_id, NEW.user_id, NEW.modification_date, NEW.nature_of_modification);
+   RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER trigger_rules_audit_chain
+BEFORE INSERT ON rules_audit
+FOR EACH ROW
+EXECUTE FUNCTION chain_rules_audit();
+
+CREATE OR REPLACE FUNCTION reject_rules_audit_change()
+RETURNS TRIGGER AS $$
+BEGIN
+   RAISE EXCEPTION 'rules_audit is append-only';
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER trigger_rules_audit_append_only
+BEFORE UPDATE OR DELETE ON rules_audit
+FOR EACH ROW
+EXECUTE FUNCTION reject_rules_audit_change();
+
+CREATE TRIGGER trigger_rules_audit_no_truncate
+BEFORE TRUNCATE ON rules_audit
+FOR EACH STATEMENT
+EXECUTE FUNCTION reject_rules_audit_change();
+
+-- Signed snapshots of the head of the chain, so removing the latest entries can be detected too
+CREATE TABLE IF NOT EXISTS audit_checkpoints (
+    id SERIAL PRIMARY KEY,
+    last_audit_id INTEGER NOT NULL,
+    head_hash TEXT NOT NULL,
+    entries INTEGER NOT NULL,
+    public_key TEXT NOT NULL,
+    signature TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+-- +goose StatementEnd
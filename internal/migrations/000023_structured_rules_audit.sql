@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- What was done to the rule and its state before and after, instead of only a free-text description.
+-- Entries written before this migration have no action.
+ALTER TABLE rules_audit ADD COLUMN action VARCHAR(50) CHECK (action <> '');
+ALTER TABLE rules_audit ADD COLUMN before JSONB;
+ALTER TABLE rules_audit ADD COLUMN after JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_rules_audit_rule_id ON rules_audit(rule_id);
+CREATE INDEX IF NOT EXISTS idx_rules_audit_user_id ON rules_audit(user_id);
+CREATE INDEX IF NOT EXISTS idx_rules_audit_modification_date ON rules_audit(modification_date);
+
+-- Must match models.Audit.ComputeHash. Entries without an action hash the same as before this migration.
+CREATE OR REPLACE FUNCTION rules_audit_entry_hash(prev_hash TEXT, id INTEGER, rule_id INTEGER, user_id INTEGER, modification_date TIMESTAMP, nature TEXT, action TEXT, before JSONB, after JSONB)
+RETURNS TEXT AS $$
+   SELECT encode(sha256(convert_to(concat_ws(E'\n',
+       prev_hash,
+       id::text,
+       COALESCE(rule_id::text, ''),
+       COALESCE(user_id::text, ''),
+       to_char(modification_date, 'YYYY-MM-DD"T"HH24:MI:SS.US'),
+       nature,
+       action,
+       CASE WHEN action IS NOT NULL THEN COALESCE(before::text, '') END,
+       CASE WHEN action IS NOT NULL THEN COALESCE(after::text, '') END
+   ), 'UTF8')), 'hex');
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION chain_rules_audit()
+RETURNS TRIGGER AS $$
+DECLARE
+   last_hash TEXT;
+BEGIN
+   PERFORM pg_advisory_xact_lock(hashtext('rules_audit_chain'));
+   NEW.id = nextval(pg_get_serial_sequence('rules_audit', 'id'));
+   SELECT hash INTO last_hash FROM rules_audit ORDER BY id DESC LIMIT 1;
+   NEW.prev_hash = COALESCE(last_hash, repeat('0', 64));
+   NEW.hash = rules_audit_entry_hash(NEW.prev_hash, NEW.id, NEW.rule_id, NEW.user_id, NEW.modification_date, NEW.nature_of_modification,
+       NEW.action, NEW.before, NEW.after);
+   RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP FUNCTION IF EXISTS rules_audit_entry_hash(TEXT, INTEGER, INTEGER, INTEGER, TIMESTAMP, TEXT);
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Every version a rule went through. Kept after the rule is deleted, so past regulations can still be read.
+CREATE TABLE IF NOT EXISTS rule_versions (
+    rule_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    effective_date TIMESTAMP,
+    application_condition TEXT NOT NULL,
+    created_by INTEGER,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (rule_id, version)
+);
+
+-- Earlier versions weren't kept, history starts with the current one
+INSERT INTO rule_versions (rule_id, version, title, description, effective_date, application_condition)
+SELECT id, version, title, description, effective_date, application_condition FROM rules
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Rules are written as drafts, published to be scheduled for their effective date, activated on it and eventually retired.
+-- Rules created before this migration were all in force already.
+ALTER TABLE rules ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('draft', 'scheduled', 'active', 'retired'));
+ALTER TABLE rules ALTER COLUMN status SET DEFAULT 'draft';
+ALTER TABLE rules ADD COLUMN retired_at TIMESTAMP;
+
+-- The scheduler looks for scheduled rules whose effective date has come
+CREATE INDEX IF NOT EXISTS idx_rules_scheduled ON rules(effective_date) WHERE status = 'scheduled';
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Users must accept the current version of mandatory rules, like the terms of service, to keep using the platform
+ALTER TABLE rules ADD COLUMN mandatory BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Which version of each rule every user accepted, when and from where.
+-- Acceptances of earlier versions are kept, changing a rule asks users to accept it again.
+CREATE TABLE IF NOT EXISTS rule_acceptances (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rule_id INTEGER NOT NULL,
+    rule_version INTEGER NOT NULL,
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    accepted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, rule_id, rule_version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rule_acceptances_rule ON rule_acceptances(rule_id, rule_version);
+-- +goose StatementEnd
@@ -0,0 +1,71 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- In four-eyes mode changes to the rules are proposed by an admin and applied when a different admin approves them
+CREATE TABLE IF NOT EXISTS rule_proposals (
+    id SERIAL PRIMARY KEY,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('create', 'modify', 'delete')),
+    -- The rule being modified or deleted, and the version the proposal was made against. It outlives the rule.
+    rule_id INTEGER,
+    rule_version INTEGER,
+    -- The rule to create or the modification to apply
+    payload JSONB,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    proposed_by INTEGER NOT NULL REFERENCES users(id),
+    reviewed_by INTEGER REFERENCES users(id),
+    review_reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMPTZ,
+    -- Admins can withdraw their own proposals by rejecting them, but not approve them
+    CHECK (status <> 'approved' OR reviewed_by <> proposed_by)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rule_proposals_status ON rule_proposals(status, created_at);
+
+CREATE TABLE IF NOT EXISTS rule_proposal_comments (
+    id SERIAL PRIMARY KEY,
+    proposal_id INTEGER NOT NULL REFERENCES rule_proposals(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    comment TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rule_proposal_comments_proposal_id ON rule_proposal_comments(proposal_id);
+
+-- The admin who approved the change, the one who proposed it stays in user_id
+ALTER TABLE rules_audit ADD COLUMN approved_by INTEGER;
+
+-- Must match models.Audit.ComputeHash. Entries without an approver hash the same as before this migration.
+CREATE OR REPLACE FUNCTION rules_audit_entry_hash(prev_hash TEXT, id INTEGER, rule_id INTEGER, user_id INTEGER, modification_date TIMESTAMP, nature TEXT, action TEXT, before JSONB, after JSONB, approved_by INTEGER)
+RETURNS TEXT AS $$
+   SELECT encode(sha256(convert_to(concat_ws(E'\n',
+       prev_hash,
+       id::text,
+       COALESCE(rule_id::text, ''),
+       COALESCE(user_id::text, ''),
+       to_char(modification_date, 'YYYY-MM-DD"T"HH24:MI:SS.US'),
+       nature,
+       action,
+       CASE WHEN action IS NOT NULL THEN COALESCE(before::text, '') END,
+       CASE WHEN action IS NOT NULL THEN COALESCE(after::text, '') END,
+       approved_by::text
+   ), 'UTF8')), 'hex');
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION chain_rules_audit()
+RETURNS TRIGGER AS $$
+DECLARE
+   last_hash TEXT;
+BEGIN
+   PERFORM pg_advisory_xact_lock(hashtext('rules_audit_chain'));
+   NEW.id = nextval(pg_get_serial_sequence('rules_audit', 'id'));
+   SELECT hash INTO last_hash FROM rules_audit ORDER BY id DESC LIMIT 1;
+   NEW.prev_hash = COALESCE(last_hash, repeat('0', 64));
+   NEW.hash = rules_audit_entry_hash(NEW.prev_hash, NEW.id, NEW.rule_id, NEW.user_id, NEW.modification_date, NEW.nature_of_modification,
+       NEW.action, NEW.before, NEW.after, NEW.approved_by);
+   RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP FUNCTION IF EXISTS rules_audit_entry_hash(TEXT, INTEGER, INTEGER, INTEGER, TIMESTAMP, TEXT, TEXT, JSONB, JSONB);
+-- +goose StatementEnd
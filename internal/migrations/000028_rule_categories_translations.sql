@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Rules are grouped in a fixed set of categories and can have any tags
+ALTER TABLE rules ADD COLUMN category VARCHAR(30) NOT NULL DEFAULT 'general'
+    CHECK (category IN ('general', 'academic', 'conduct', 'privacy', 'communication', 'evaluation'));
+ALTER TABLE rules ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+-- Title and description in other languages, by locale: {"es": {"title": "...", "description": "..."}}
+ALTER TABLE rules ADD COLUMN translations JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_rules_category ON rules(category);
+CREATE INDEX IF NOT EXISTS idx_rules_tags ON rules USING GIN (tags);
+-- +goose StatementEnd
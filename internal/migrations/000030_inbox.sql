@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Every notification sent to a user, so the app can show its history. Push notifications and emails link to their entry.
+CREATE TABLE IF NOT EXISTS inbox (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    text TEXT NOT NULL,
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_inbox_user_id ON inbox(user_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_inbox_unread ON inbox(user_id) WHERE read_at IS NULL;
+-- +goose StatementEnd
@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Changes users are told about live are announced on the user_events channel, where every instance of the API
+-- listens and forwards them to the streams of the user. Notifications are sent when the transaction commits.
+CREATE OR REPLACE FUNCTION notify_user_event(user_id INTEGER, type TEXT, id INTEGER)
+RETURNS VOID AS $$
+BEGIN
+   PERFORM pg_notify('user_events', json_build_object('user_id', user_id, 'type', type, 'id', id)::text);
+END;
+$$ language 'plpgsql';
+
+CREATE OR REPLACE FUNCTION notify_inbox_entry()
+RETURNS TRIGGER AS $$
+BEGIN
+   PERFORM notify_user_event(NEW.user_id, 'inbox', NEW.id);
+   RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER trigger_inbox_notify
+AFTER INSERT ON inbox
+FOR EACH ROW
+EXECUTE FUNCTION notify_inbox_entry();
+
+CREATE OR REPLACE FUNCTION notify_user_blocked()
+RETURNS TRIGGER AS $$
+BEGIN
+   PERFORM notify_user_event(NEW.blocked_user_id, 'block', NULL);
+   RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER trigger_blocked_users_notify
+AFTER INSERT ON blocked_users
+FOR EACH ROW
+EXECUTE FUNCTION notify_user_blocked();
+
+-- Moving sessions_valid_after revokes the sessions of the user, whatever the reason
+CREATE OR REPLACE FUNCTION notify_sessions_revoked()
+RETURNS TRIGGER AS $$
+BEGIN
+   PERFORM notify_user_event(NEW.id, 'logout', NULL);
+   RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER trigger_users_sessions_notify
+AFTER UPDATE OF sessions_valid_after ON users
+FOR EACH ROW
+WHEN (NEW.sessions_valid_after IS DISTINCT FROM OLD.sessions_valid_after)
+EXECUTE FUNCTION notify_sessions_revoked();
+-- +goose StatementEnd
@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Registry of the kinds of notifications. Adding one is adding a row, users get it by every channel until they opt out.
+CREATE TABLE IF NOT EXISTS notification_types (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    -- Mandatory notifications are sent by every channel, whatever the preferences of the user
+    mandatory BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO notification_types (name, description, mandatory) VALUES
+    ('exam_notification', 'Exams', false),
+    ('homework_notification', 'Homework', false),
+    ('social_notification', 'Social', false),
+    ('rule_notification', 'Rules coming into force', true)
+ON CONFLICT (name) DO NOTHING;
+
+-- Whether a type of notification is sent to the user by a channel. Without a row it is.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id INTEGER NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    channel VARCHAR(10) NOT NULL CHECK (channel IN ('inbox', 'push', 'email')),
+    enabled BOOLEAN NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, type, channel),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (type) REFERENCES notification_types(name) ON DELETE CASCADE
+);
+
+-- The old preferences turned a type off altogether
+INSERT INTO notification_preferences (user_id, type, channel, enabled)
+SELECT users.id, old.type, channels.channel, false
+FROM users
+CROSS JOIN LATERAL (VALUES
+    ('exam_notification', users.exam_notification),
+    ('homework_notification', users.homework_notification),
+    ('social_notification', users.social_notification)
+) AS old(type, enabled)
+CROSS JOIN (VALUES ('inbox'), ('push'), ('email')) AS channels(channel)
+WHERE old.enabled = false;
+
+ALTER TABLE users
+    DROP COLUMN exam_notification,
+    DROP COLUMN homework_notification,
+    DROP COLUMN social_notification;
+-- +goose StatementEnd
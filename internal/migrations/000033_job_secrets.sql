@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Jobs that carry secrets, like reset tokens and verification pins. Their payload isn't kept once they fail for good.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS secret BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE dead_jobs ADD COLUMN IF NOT EXISTS secret BOOLEAN NOT NULL DEFAULT false;
+
+-- There's no telling which of the emails that already failed had secrets
+UPDATE dead_jobs SET payload = '{}', secret = true WHERE type = 'email';
+-- +goose StatementEnd
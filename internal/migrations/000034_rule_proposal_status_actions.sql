@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Rolling back, publishing and retiring rules are proposed too in four-eyes mode.
+-- A rollback proposal keeps the version to restore in payload.
+ALTER TABLE rule_proposals DROP CONSTRAINT IF EXISTS rule_proposals_action_check;
+ALTER TABLE rule_proposals ADD CONSTRAINT rule_proposals_action_check
+    CHECK (action IN ('create', 'modify', 'delete', 'rollback', 'publish', 'retire'));
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Versions keep the category, tags, translations and whether the rule is mandatory too, so rollbacks restore them.
+-- NULL means the version didn't record it.
+ALTER TABLE rule_versions ADD COLUMN mandatory BOOLEAN;
+ALTER TABLE rule_versions ADD COLUMN category VARCHAR(30);
+ALTER TABLE rule_versions ADD COLUMN tags TEXT[];
+ALTER TABLE rule_versions ADD COLUMN translations JSONB;
+
+-- They weren't kept until now, only the current version of each rule is known to have the ones the rule has
+UPDATE rule_versions
+SET mandatory = rules.mandatory, category = rules.category, tags = rules.tags, translations = rules.translations
+FROM rules
+WHERE rule_versions.rule_id = rules.id AND rule_versions.version = rules.version;
+-- +goose StatementEnd
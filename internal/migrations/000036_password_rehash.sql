@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Upgrading the hash of a password at login keeps the same password, so it isn't recorded in the history
+-- nor bumps the version of the user. The update sets app.password_rehash for its transaction.
+CREATE OR REPLACE FUNCTION record_password_history()
+RETURNS TRIGGER AS $$
+BEGIN
+   IF current_setting('app.password_rehash', true) = 'on' THEN
+      RETURN NEW;
+   END IF;
+   IF NEW.password IS NOT NULL AND (TG_OP = 'INSERT' OR NEW.password IS DISTINCT FROM OLD.password) THEN
+      INSERT INTO password_history (user_id, password) VALUES (NEW.id, NEW.password);
+   END IF;
+   RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE OR REPLACE FUNCTION increment_version_column()
+RETURNS TRIGGER AS $$
+BEGIN
+   IF TG_TABLE_NAME = 'users' AND current_setting('app.password_rehash', true) = 'on' THEN
+      RETURN NEW;
+   END IF;
+   NEW.version = OLD.version + 1;
+   RETURN NEW;
+END;
+$$ language 'plpgsql';
+-- +goose StatementEnd
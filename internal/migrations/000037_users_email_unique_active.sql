@@ -0,0 +1,8 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Deleted users are kept, but their email can be used again to register a new account
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_unique;
+DROP INDEX IF EXISTS users_email_lower_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_idx ON users (LOWER(email)) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
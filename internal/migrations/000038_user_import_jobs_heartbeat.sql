@@ -0,0 +1,6 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Running import jobs refresh heartbeat_at, so the ones whose instance went down can be told apart and failed
+ALTER TABLE user_import_jobs ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+-- +goose StatementEnd
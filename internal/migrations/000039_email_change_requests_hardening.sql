@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Cancel tokens were stored in plain text, none of the pending changes can be trusted anymore
+DELETE FROM email_change_requests;
+
+ALTER TABLE email_change_requests
+    DROP COLUMN cancel_token,
+    ADD COLUMN cancel_token_hash VARCHAR(64) NOT NULL UNIQUE,
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
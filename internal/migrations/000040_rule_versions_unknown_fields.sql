@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- 000035 used to give every earlier version the values the rule had when it ran. They are unknown instead,
+-- except for the version each remaining rule was at then.
+ALTER TABLE rule_versions
+    ALTER COLUMN mandatory DROP NOT NULL,
+    ALTER COLUMN mandatory DROP DEFAULT,
+    ALTER COLUMN category DROP NOT NULL,
+    ALTER COLUMN category DROP DEFAULT,
+    ALTER COLUMN tags DROP NOT NULL,
+    ALTER COLUMN tags DROP DEFAULT,
+    ALTER COLUMN translations DROP NOT NULL,
+    ALTER COLUMN translations DROP DEFAULT;
+
+WITH migrated AS (
+    SELECT MIN(tstamp) AS at FROM goose_db_version WHERE version_id = 35 AND is_applied
+), current_then AS (
+    SELECT rule_versions.rule_id, MAX(rule_versions.version) AS version
+    FROM rule_versions, migrated
+    WHERE rule_versions.created_at < migrated.at AND EXISTS (SELECT 1 FROM rules WHERE rules.id = rule_versions.rule_id)
+    GROUP BY rule_versions.rule_id
+)
+UPDATE rule_versions
+SET mandatory = NULL, category = NULL, tags = NULL, translations = NULL
+FROM migrated
+WHERE rule_versions.created_at < migrated.at
+    AND NOT EXISTS (
+        SELECT 1 FROM current_then
+        WHERE current_then.rule_id = rule_versions.rule_id AND current_then.version = rule_versions.version
+    );
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Acceptances go away with their rule, the ones deleted rules left behind first
+DELETE FROM rule_acceptances WHERE NOT EXISTS (SELECT 1 FROM rules WHERE rules.id = rule_acceptances.rule_id);
+
+ALTER TABLE rule_acceptances
+    ADD CONSTRAINT rule_acceptances_rule_id_fkey FOREIGN KEY (rule_id) REFERENCES rules(id) ON DELETE CASCADE;
+-- +goose StatementEnd
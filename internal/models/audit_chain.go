@@ -0,0 +1,139 @@
+package models
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditChainGenesis is the previous hash of the first rules audit entry
+const AuditChainGenesis = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// ComputeHash returns the hash the entry must have, given its contents and PrevHash.
+// It must match rules_audit_entry_hash in the migrations, which computes it on insert.
+func (a Audit) ComputeHash() string {
+	nullable := func(value sql.NullInt64) string {
+		if !value.Valid {
+			return ""
+		}
+		return strconv.FormatInt(value.Int64, 10)
+	}
+	fields := []string{
+		a.PrevHash,
+		strconv.Itoa(a.Id),
+		nullable(a.RuleId),
+		nullable(a.UserId),
+		a.ModificationDate.UTC().Format("2006-01-02T15:04:05.000000"),
+		a.NatureOfModification,
+	}
+	// Entries written before actions were recorded hash without them
+	if a.Action != "" {
+		fields = append(fields, string(a.Action), string(a.Before), string(a.After))
+	}
+	// Only approved entries hash the approver, the others hash as before it was recorded
+	if a.ApprovedBy.Valid {
+		fields = append(fields, nullable(a.ApprovedBy))
+	}
+	content := strings.Join(fields, "\n")
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditChainHead is the last entry of the rules audit chain
+type AuditChainHead struct {
+	LastAuditId int
+	Hash        string
+	Entries     int
+}
+
+// AuditCheckpoint is a signed snapshot of the head of the rules audit chain.
+// Entries removed from the end of the chain can't be detected from the chain alone, but they break the checkpoints.
+type AuditCheckpoint struct {
+	Id          int       `json:"id"`
+	LastAuditId int       `json:"last_audit_id"`
+	HeadHash    string    `json:"head_hash"`
+	Entries     int       `json:"entries"`
+	PublicKey   string    `json:"public_key"`
+	Signature   string    `json:"signature"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SignedMessage returns the bytes the signature of the checkpoint is computed over
+func (c AuditCheckpoint) SignedMessage() []byte {
+	return []byte(fmt.Sprintf("rules_audit\n%d\n%d\n%s\n%d", c.LastAuditId, c.Entries, c.HeadHash, c.CreatedAt.Unix()))
+}
+
+// Sign fills the public key and signature of the checkpoint
+func (c *AuditCheckpoint) Sign(key ed25519.PrivateKey) {
+	c.PublicKey = base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+	c.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, c.SignedMessage()))
+}
+
+// VerifySignature reports whether the checkpoint was signed with the private half of its public key
+func (c AuditCheckpoint) VerifySignature() bool {
+	publicKey, err := base64.StdEncoding.DecodeString(c.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return false
+	}
+	signature, err := base64.StdEncoding.DecodeString(c.Signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(publicKey, c.SignedMessage(), signature)
+}
+
+// AuditChainBreak is the first entry of the rules audit that can't be trusted
+type AuditChainBreak struct {
+	Id     int    `json:"id"`
+	Reason string `json:"reason"`
+}
+
+func (b AuditChainBreak) Error() string {
+	return fmt.Sprintf("audit entry %d: %s", b.Id, b.Reason)
+}
+
+// AuditChainReport is the outcome of walking the rules audit chain
+type AuditChainReport struct {
+	Entries     int              `json:"entries"`
+	Head        string           `json:"head"`
+	Checkpoints int              `json:"checkpoints"`
+	Break       *AuditChainBreak `json:"break,omitempty"`
+}
+
+// AuditChain checks rules audit entries one at a time, in id order
+type AuditChain struct {
+	lastId   int
+	lastHash string
+	entries  int
+}
+
+func NewAuditChain() *AuditChain {
+	return &AuditChain{lastHash: AuditChainGenesis}
+}
+
+// Append checks the entry follows the ones before it and returns where the chain breaks otherwise
+func (c *AuditChain) Append(entry Audit) *AuditChainBreak {
+	switch {
+	case entry.Id <= c.lastId:
+		return &AuditChainBreak{Id: entry.Id, Reason: fmt.Sprintf("out of order after entry %d", c.lastId)}
+	case entry.PrevHash != c.lastHash:
+		return &AuditChainBreak{Id: entry.Id, Reason: "previous hash doesn't match, an earlier entry was removed or modified"}
+	case entry.Hash != entry.ComputeHash():
+		return &AuditChainBreak{Id: entry.Id, Reason: "contents don't match its hash, the entry was modified"}
+	}
+	c.lastId = entry.Id
+	c.lastHash = entry.Hash
+	c.entries++
+	return nil
+}
+
+// Head returns the last entry appended so far
+func (c *AuditChain) Head() AuditChainHead {
+	return AuditChainHead{LastAuditId: c.lastId, Hash: c.lastHash, Entries: c.entries}
+}
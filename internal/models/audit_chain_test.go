@@ -0,0 +1,145 @@
+package models
+
+import (
+	"crypto/ed25519"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chainedAudits(natures ...string) []Audit {
+	var audits []Audit
+	prev := AuditChainGenesis
+	for i, nature := range natures {
+		audit := Audit{
+			Id:                   i + 1,
+			RuleId:               sql.NullInt64{Int64: 10, Valid: true},
+			UserId:               sql.NullInt64{Int64: 1, Valid: true},
+			ModificationDate:     time.Date(2025, 3, 1, 10, i, 0, 0, time.UTC),
+			NatureOfModification: nature,
+			PrevHash:             prev,
+		}
+		audit.Hash = audit.ComputeHash()
+		prev = audit.Hash
+		audits = append(audits, audit)
+	}
+	return audits
+}
+
+func TestAudit_ComputeHash(t *testing.T) {
+	// Same value rules_audit_entry_hash gives in the database
+	audit := Audit{
+		Id:                   1,
+		RuleId:               sql.NullInt64{Int64: 10, Valid: true},
+		ModificationDate:     time.Date(2025, 3, 1, 10, 0, 0, 123456000, time.UTC),
+		NatureOfModification: "Created Rule 10: Título",
+		PrevHash:             AuditChainGenesis,
+	}
+
+	assert.Equal(t, "6a39a205056d0dbc6665cbd8b8085f6be0262c57755fa0000038bdb3186a31f5", audit.ComputeHash())
+}
+
+func TestAudit_ComputeHash_WithAction(t *testing.T) {
+	audit := Audit{
+		Id:                   2,
+		RuleId:               sql.NullInt64{Int64: 10, Valid: true},
+		UserId:               sql.NullInt64{Int64: 1, Valid: true},
+		ModificationDate:     time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+		NatureOfModification: "Modified Rule 10: title",
+		Action:               RuleAuditUpdate,
+		Before:               json.RawMessage(`{"Title": "A"}`),
+		After:                json.RawMessage(`{"Title": "B"}`),
+		PrevHash:             strings.Repeat("a", 64),
+	}
+
+	assert.Equal(t, "fd3ff15d9aab9e3f861d2d0c3dd12e02e222a6293a115b654555d93fdb69ab92", audit.ComputeHash())
+}
+
+func TestAuditChain_Intact(t *testing.T) {
+	chain := NewAuditChain()
+	audits := chainedAudits("Created Rule 10: A", "Modified Rule 10: Changed title to: B", "Deleted Rule 10: B")
+
+	for _, audit := range audits {
+		require.Nil(t, chain.Append(audit))
+	}
+	assert.Equal(t, AuditChainHead{LastAuditId: 3, Hash: audits[2].Hash, Entries: 3}, chain.Head())
+}
+
+func TestAuditChain_Breaks(t *testing.T) {
+	tests := []struct {
+		name       string
+		tamper     func([]Audit) []Audit
+		expectedId int
+	}{
+		{
+			name: "modified entry",
+			tamper: func(audits []Audit) []Audit {
+				audits[1].NatureOfModification = "Modified Rule 10: nothing"
+				return audits
+			},
+			expectedId: 2,
+		},
+		{
+			name: "removed entry",
+			tamper: func(audits []Audit) []Audit {
+				return append(audits[:1], audits[2:]...)
+			},
+			expectedId: 3,
+		},
+		{
+			name: "rehashed entry",
+			tamper: func(audits []Audit) []Audit {
+				audits[0].NatureOfModification = "Created Rule 10: C"
+				audits[0].Hash = audits[0].ComputeHash()
+				return audits
+			},
+			expectedId: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := NewAuditChain()
+			var chainBreak *AuditChainBreak
+			for _, audit := range tt.tamper(chainedAudits("A", "B", "C")) {
+				if chainBreak = chain.Append(audit); chainBreak != nil {
+					break
+				}
+			}
+
+			require.NotNil(t, chainBreak)
+			assert.Equal(t, tt.expectedId, chainBreak.Id)
+		})
+	}
+}
+
+func TestAuditCheckpoint_Signature(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	checkpoint := AuditCheckpoint{LastAuditId: 3, HeadHash: "abc", Entries: 3, CreatedAt: time.Now()}
+
+	checkpoint.Sign(key)
+	assert.True(t, checkpoint.VerifySignature())
+
+	checkpoint.Entries = 2
+	assert.False(t, checkpoint.VerifySignature())
+}
+
+func TestAudit_ComputeHash_ApprovedBy(t *testing.T) {
+	audit := chainedAudits("Created Rule 10: A")[0]
+	audit.Action = RuleAuditCreate
+	unapproved := audit.ComputeHash()
+
+	// Approved entries also hash the approver, so it can't be changed afterwards
+	audit.ApprovedBy = sql.NullInt64{Int64: 2, Valid: true}
+	approved := audit.ComputeHash()
+	assert.NotEqual(t, unapproved, approved)
+
+	audit.ApprovedBy = sql.NullInt64{Int64: 3, Valid: true}
+	assert.NotEqual(t, approved, audit.ComputeHash())
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// AuditDetailsKey is the context key under which the audit middleware stores the details
+// handlers can add to the event it records
+const AuditDetailsKey = "audit_details"
+
+const (
+	AuditDefaultPageSize = 50
+	AuditMaxPageSize     = 500
+)
+
+// AuditEvent is one privileged action. Before and After hold JSON snapshots of the target,
+// when the handler that ran the action had them.
+type AuditEvent struct {
+	Id         int64           `json:"id"`
+	ActorId    *int            `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type,omitempty"`
+	TargetId   string          `json:"target_id,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	StatusCode int             `json:"status_code"`
+	IP         string          `json:"ip"`
+	RequestId  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditDetails is what a handler knows about the action it ran, beyond the request itself.
+// All its methods can be called on nil, for handlers running without the audit middleware.
+type AuditDetails struct {
+	Action     string
+	TargetType string
+	TargetId   string
+	Before     any
+	After      any
+}
+
+// AuditFromContext returns the details of the event being recorded for the request, or nil
+func AuditFromContext(ctx context.Context) *AuditDetails {
+	details, _ := ctx.Value(AuditDetailsKey).(*AuditDetails)
+	return details
+}
+
+// Name names an action without a single target
+func (d *AuditDetails) Name(action string) {
+	if d == nil {
+		return
+	}
+	d.Action = action
+}
+
+// Describe names the action and its target
+func (d *AuditDetails) Describe(action string, targetType string, targetId int) {
+	if d == nil {
+		return
+	}
+	d.Action = action
+	d.TargetType = targetType
+	d.TargetId = strconv.Itoa(targetId)
+}
+
+// Change records the state of the target before and after the action, either can be nil
+func (d *AuditDetails) Change(before any, after any) {
+	if d == nil {
+		return
+	}
+	d.Before = before
+	d.After = after
+}
+
+type AuditFilter struct {
+	ActorId    *int       `form:"actor_id"`
+	Action     string     `form:"action"`
+	TargetType string     `form:"target_type"`
+	TargetId   string     `form:"target_id"`
+	From       *time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To         *time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`
+}
+
+type AuditListRequest struct {
+	AuditFilter
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=500"`
+	Format   string `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+type Pagination struct {
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+	Total    int `json:"total"`
+}
+
+// NewPagination returns the requested page, falling back to the first page of AuditDefaultPageSize items
+func NewPagination(page int, pageSize int) Pagination {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > AuditMaxPageSize {
+		pageSize = AuditDefaultPageSize
+	}
+	return Pagination{Page: page, PageSize: pageSize}
+}
+
+// Offset returns how many items come before the page
+func (p Pagination) Offset() int {
+	return (p.Page - 1) * p.PageSize
+}
+
+// AuditExportColumns are the columns of the CSV export of the audit log
+var AuditExportColumns = []string{
+	"id", "created_at", "actor_id", "action", "target_type", "target_id", "status_code", "ip", "request_id", "before", "after",
+}
+
+// ExportValues returns the event in the order of AuditExportColumns
+func (e AuditEvent) ExportValues() []any {
+	var actor any
+	if e.ActorId != nil {
+		actor = *e.ActorId
+	}
+	return []any{
+		e.Id, e.CreatedAt, actor, e.Action, e.TargetType, e.TargetId, e.StatusCode, e.IP, e.RequestId,
+		string(e.Before), string(e.After),
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditDetails_NilIsIgnored(t *testing.T) {
+	var details *AuditDetails
+
+	assert.NotPanics(t, func() {
+		details.Name("user.import")
+		details.Describe("user.block", "user", 1)
+		details.Change(nil, true)
+	})
+}
+
+func TestAuditEvent_ExportValues(t *testing.T) {
+	createdAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	event := AuditEvent{Id: 5, Action: "user.block", TargetType: "user", TargetId: "3",
+		After: json.RawMessage(`{"blocked":true}`), StatusCode: 200, CreatedAt: createdAt}
+
+	values := event.ExportValues()
+
+	assert.Len(t, values, len(AuditExportColumns))
+	assert.Equal(t, []any{int64(5), createdAt, nil, "user.block", "user", "3", 200, "", "", "", `{"blocked":true}`}, values)
+}
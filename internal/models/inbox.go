@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// InboxEntry is a notification sent to a user, kept so it can be read in the app
+type InboxEntry struct {
+	Id     int    `json:"id"`
+	UserId int    `json:"user_id"`
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Text   string `json:"text"`
+	// Nil until the user reads the entry
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NewInboxEntry returns the entry of the notification in the inbox of the user
+func NewInboxEntry(userId int, notification NotifyRequest) InboxEntry {
+	return InboxEntry{
+		UserId: userId,
+		Type:   notification.NotificationType,
+		Title:  notification.NotificationTitle,
+		Text:   notification.NotificationText,
+	}
+}
+
+type InboxListRequest struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=500"`
+	// Only returns the entries that weren't read yet
+	Unread bool `form:"unread"`
+}
+
+// Inbox is a page of the inbox of a user, along with how many entries are unread in the whole inbox
+type Inbox struct {
+	Entries    []InboxEntry `json:"data"`
+	Pagination Pagination   `json:"pagination"`
+	Unread     int          `json:"unread"`
+}
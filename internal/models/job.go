@@ -0,0 +1,83 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/mailer"
+)
+
+// Kinds of background jobs, each one has its own payload
+const (
+	JobEmail = "email"
+	JobPush  = "push"
+	// JobBroadcast sends a notification to every user, its payload is the NotifyRequest
+	JobBroadcast = "broadcast"
+)
+
+// Job is work done in the background, outside of the request that caused it, and retried until it succeeds
+type Job struct {
+	Id      int             `json:"id"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+	// Secret jobs carry credentials, like reset tokens. Their payload is dropped when they fail for good.
+	Secret bool `json:"secret"`
+	// Times the job was claimed by a worker, including the current one
+	Attempts  int       `json:"attempts"`
+	RunAt     time.Time `json:"run_at"`
+	LastError string    `json:"last_error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DeadJob is a job that failed too many times and won't be retried unless an admin requeues it
+type DeadJob struct {
+	Job
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// PushJob delivers a notification to one device of a user
+type PushJob struct {
+	UserId int    `json:"user_id"`
+	Token  string `json:"token"`
+	Title  string `json:"title"`
+	Text   string `json:"text"`
+	// Entry of the notification in the inbox of the user, sent along so the app can open it
+	InboxId int `json:"inbox_id,omitempty"`
+}
+
+// Outbox builds the jobs for a new row once its id is known. They are written in the same
+// transaction as the row, so the jobs run if and only if the row is stored.
+type Outbox func(id int) ([]Job, error)
+
+// NewEmailJob returns the job that sends the message
+func NewEmailJob(message mailer.Message) (Job, error) {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return Job{}, err
+	}
+	return Job{Type: JobEmail, Payload: payload}, nil
+}
+
+// NewPushJob returns the job that sends the inbox entry to the device with the token
+func NewPushJob(token string, entry InboxEntry) (Job, error) {
+	payload, err := json.Marshal(PushJob{
+		UserId:  entry.UserId,
+		Token:   token,
+		Title:   entry.Title,
+		Text:    entry.Text,
+		InboxId: entry.Id,
+	})
+	if err != nil {
+		return Job{}, err
+	}
+	return Job{Type: JobPush, Payload: payload}, nil
+}
+
+// NewBroadcastJob returns the job that sends the notification to every user
+func NewBroadcastJob(notification NotifyRequest) (Job, error) {
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return Job{}, err
+	}
+	return Job{Type: JobBroadcast, Payload: payload}, nil
+}
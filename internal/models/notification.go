@@ -1,12 +1,16 @@
 package models
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 type NotifyRequest struct {
 	Users             []int  `json:"users"  validate:"required,dive,gt=0"`
 	NotificationTitle string `json:"notification_title" db:"notification_title" validate:"required,min=1,max=225"`
 	NotificationText  string `json:"notification_text" db:"notification_text" validate:"required,min=1,max=225"`
-	NotificationType  string `json:"notification_type"  validate:"required, oneof=exam_notification homework_notification social_notification"`
+	// NotificationType is one of the registered notification types
+	NotificationType string `json:"notification_type"  validate:"required,max=50"`
 }
 
 type NotificationToken struct {
@@ -22,13 +26,67 @@ type NotificationSetUpRequest struct {
 	Token string `json:"token"  validate:"required, max=225"`
 }
 
+// NotificationPreferenceRequest turns a type of notification on or off by every channel at once.
+// Deprecated: NotificationPreferencesRequest sets each channel.
 type NotificationPreferenceRequest struct {
-	NotificationType       string `json:"notification_type"  validate:"required, oneof=exam_notification homework_notification social_notification"`
+	NotificationType       string `json:"notification_type"  validate:"required,max=50"`
 	NotificationPreference bool   `json:"notification_preference"  validate:"required"`
 }
 
+// NotificationPreference tells whether the original three types of notification are sent by any channel.
+// Deprecated: NotificationSettings has every type by channel.
 type NotificationPreference struct {
 	ExamNotification     bool `json:"exam_notification"  validate:"required"`
 	HomeworkNotification bool `json:"homework_notification"  validate:"required"`
 	SocialNotification   bool `json:"social_notification"  validate:"required"`
 }
+
+// RuleNotification is sent to every user when a rule comes into force
+const RuleNotification = "rule_notification"
+
+// Channels notifications are sent by
+const (
+	NotificationChannelInbox = "inbox"
+	NotificationChannelPush  = "push"
+	NotificationChannelEmail = "email"
+)
+
+// NotificationChannels are every channel, in the order they are shown
+var NotificationChannels = []string{NotificationChannelInbox, NotificationChannelPush, NotificationChannelEmail}
+
+// IsNotificationChannel reports whether notifications are sent by the channel
+func IsNotificationChannel(channel string) bool {
+	return slices.Contains(NotificationChannels, channel)
+}
+
+// NotificationType is a kind of notification of the registry
+type NotificationType struct {
+	Name        string `json:"name" binding:"required,max=50"`
+	Description string `json:"description"`
+	// Mandatory notifications are sent by every channel, whatever the preferences
+	Mandatory bool      `json:"mandatory"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NotificationPreferences tells by which channels each type of notification is sent, by type and then channel
+type NotificationPreferences map[string]map[string]bool
+
+// Set records whether the type is sent by the channel
+func (p NotificationPreferences) Set(notificationType string, channel string, enabled bool) {
+	if p[notificationType] == nil {
+		p[notificationType] = map[string]bool{}
+	}
+	p[notificationType][channel] = enabled
+}
+
+// NotificationSettings is the whole preferences matrix of a user, along with the types and channels it's made of
+type NotificationSettings struct {
+	Types       []NotificationType      `json:"types"`
+	Channels    []string                `json:"channels"`
+	Preferences NotificationPreferences `json:"preferences"`
+}
+
+// NotificationPreferencesRequest changes the cells of the matrix it has, leaving the rest as they are
+type NotificationPreferencesRequest struct {
+	Preferences NotificationPreferences `json:"preferences" binding:"required"`
+}
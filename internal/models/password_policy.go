@@ -0,0 +1,117 @@
+package models
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+// commonPasswords holds the bundled list of widely used passwords, lowercased
+var commonPasswords = func() map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, line := range strings.Split(commonPasswordsFile, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			set[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return set
+}()
+
+// minPersonalInfoLength is the shortest piece of personal info checked against passwords,
+// shorter ones would reject too many legitimate passwords
+const minPersonalInfoLength = 3
+
+// PasswordPolicy describes the rules every new password must follow.
+type PasswordPolicy struct {
+	MinLength          int
+	MaxLength          int
+	RequireUppercase   bool
+	RequireLowercase   bool
+	RequireDigit       bool
+	RequireSymbol      bool
+	RejectPersonalInfo bool
+	RejectCommon       bool
+	// HistorySize is how many previous passwords of the user can't be reused, 0 disables the check
+	HistorySize int
+}
+
+// DefaultPasswordPolicy returns the policy used when nothing else is configured.
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:          8,
+		MaxLength:          utils.BcryptMaxPasswordBytes, // bcrypt can't hash longer ones, and PASSWORD_HASH_ALGORITHM=bcrypt still uses it
+		RequireUppercase:   true,
+		RequireLowercase:   true,
+		RequireDigit:       true,
+		RejectPersonalInfo: true,
+		RejectCommon:       true,
+		HistorySize:        5,
+	}
+}
+
+// Validate checks the password against every static rule of the policy. personalInfo holds
+// values such as the user's name or email that must not appear in the password.
+func (p PasswordPolicy) Validate(password string, personalInfo []string) ValidationErrors {
+	var errs ValidationErrors
+	add := func(msg string) {
+		errs = append(errs, FieldError{Field: "password", Message: msg})
+	}
+
+	if length := len([]rune(password)); length < p.MinLength {
+		add(fmt.Sprintf("must be at least %d characters long", p.MinLength))
+	} else if p.MaxLength > 0 && len(password) > p.MaxLength {
+		add(fmt.Sprintf("must be at most %d bytes long", p.MaxLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.RequireUppercase && !upper {
+		add("must contain an uppercase letter")
+	}
+	if p.RequireLowercase && !lower {
+		add("must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		add("must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		add("must contain a symbol")
+	}
+
+	lowered := strings.ToLower(password)
+	if p.RejectPersonalInfo {
+		for _, info := range personalInfo {
+			// Only the local part of an email is meaningful, the domain is shared by many users
+			info, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(info)), "@")
+			if len([]rune(info)) >= minPersonalInfoLength && strings.Contains(lowered, info) {
+				add("must not contain your name or email")
+				break
+			}
+		}
+	}
+
+	if p.RejectCommon {
+		if _, common := commonPasswords[lowered]; common {
+			add("is too common")
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	policy := DefaultPasswordPolicy()
+	personalInfo := []string{"Maria", "Gonzalez", "maria.g@example.com"}
+
+	tests := []struct {
+		name     string
+		password string
+		messages []string
+	}{
+		{name: "valid", password: "Correct4Horse"},
+		{name: "too short", password: "Ab1", messages: []string{"must be at least 8 characters long"}},
+		{name: "too long", password: "Aa1" + string(make([]byte, 70)), messages: []string{"must be at most 72 bytes long"}},
+		{name: "missing classes", password: "abcdefghij", messages: []string{"must contain an uppercase letter", "must contain a digit"}},
+		{name: "contains name", password: "GonzalezRocks1", messages: []string{"must not contain your name or email"}},
+		{name: "contains email local part", password: "Xmaria.g2024", messages: []string{"must not contain your name or email"}},
+		{name: "common", password: "Password1", messages: []string{"is too common"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := policy.Validate(tt.password, personalInfo)
+
+			var messages []string
+			for _, err := range errs {
+				assert.Equal(t, "password", err.Field)
+				messages = append(messages, err.Message)
+			}
+			assert.Equal(t, tt.messages, messages)
+		})
+	}
+}
+
+func TestPasswordPolicy_Validate_Disabled(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 4}
+
+	assert.Empty(t, policy.Validate("maria", []string{"maria"}))
+	assert.Empty(t, policy.Validate("password", nil))
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// PatchField holds a single member of a JSON Merge Patch (RFC 7396) document.
+// Set reports whether the member was present in the document and Null whether
+// it was explicitly set to null, which means the field must be cleared.
+type PatchField[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (p *PatchField[T]) UnmarshalJSON(data []byte) error {
+	p.Set = true
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		p.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &p.Value)
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// RuleAcceptance records that a user accepted a version of a rule
+type RuleAcceptance struct {
+	UserId      int       `json:"user_id"`
+	RuleId      int       `json:"rule_id"`
+	RuleVersion int       `json:"rule_version"`
+	IP          string    `json:"ip"`
+	AcceptedAt  time.Time `json:"accepted_at"`
+}
+
+// PendingRule is a mandatory rule the user hasn't accepted in its current version
+type PendingRule struct {
+	Rule
+	// Latest version of the rule the user accepted, 0 if they never did
+	AcceptedVersion int `json:"accepted_version"`
+}
+
+// RuleAcceptanceCoverage is how many users accepted an active rule
+type RuleAcceptanceCoverage struct {
+	RuleId    int    `json:"rule_id"`
+	Title     string `json:"title"`
+	Version   int    `json:"version"`
+	Mandatory bool   `json:"mandatory"`
+	// Users that accepted the current version
+	Accepted int `json:"accepted"`
+	// Users that only accepted an earlier version
+	AcceptedEarlierVersion int `json:"accepted_earlier_version"`
+	// Users that haven't accepted the current version
+	Pending int `json:"pending"`
+	// Share of the users that accepted the current version, between 0 and 1
+	Coverage float64 `json:"coverage"`
+}
+
+// RuleAcceptanceReport is the acceptance coverage of every active rule
+type RuleAcceptanceReport struct {
+	// Users bound by the rules: not deleted and not admins
+	Users int                      `json:"users"`
+	Rules []RuleAcceptanceCoverage `json:"rules"`
+}
+
+// NewRuleAcceptanceReport fills in what is pending and the coverage of each rule out of the given users
+func NewRuleAcceptanceReport(users int, rules []RuleAcceptanceCoverage) RuleAcceptanceReport {
+	for i := range rules {
+		rules[i].Pending = max(users-rules[i].Accepted, 0)
+		if users > 0 {
+			rules[i].Coverage = float64(rules[i].Accepted) / float64(users)
+		}
+	}
+	return RuleAcceptanceReport{Users: users, Rules: rules}
+}
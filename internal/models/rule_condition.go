@@ -0,0 +1,118 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+)
+
+// RuleConditionSchema lists the user attributes an application condition can use and their kinds
+var RuleConditionSchema = map[string]utils.ExprKind{
+	"id":         utils.ExprNumber,
+	"name":       utils.ExprString,
+	"surname":    utils.ExprString,
+	"email":      utils.ExprString,
+	"location":   utils.ExprString,
+	"role":       utils.ExprString,
+	"verified":   utils.ExprBool,
+	"blocked":    utils.ExprBool,
+	"created_at": utils.ExprTime,
+}
+
+// ParseRuleCondition parses an application condition and checks it only uses user attributes
+// the way their kinds allow, the error wraps utils.ErrInvalidExpression.
+func ParseRuleCondition(condition string) (*utils.Expression, error) {
+	expression, err := utils.ParseExpression(condition)
+	if err != nil {
+		return nil, err
+	}
+	if err := expression.Check(RuleConditionSchema); err != nil {
+		return nil, err
+	}
+	return expression, nil
+}
+
+// RuleConditionVars returns the attributes of the user conditions are evaluated against
+func RuleConditionVars(user User) map[string]any {
+	return map[string]any{
+		"id":         user.Id,
+		"name":       user.Name,
+		"surname":    user.Surname,
+		"email":      user.Email,
+		"location":   user.Location,
+		"role":       user.Role,
+		"verified":   user.Verified,
+		"blocked":    user.Blocked,
+		"created_at": user.CreatedAt,
+	}
+}
+
+// ErrInvalidConditionAttribute is returned for attributes that are not in RuleConditionSchema or have the wrong type
+var ErrInvalidConditionAttribute = errors.New("invalid condition attribute")
+
+// RuleConditionAttributes converts attributes decoded from JSON to the values conditions are
+// evaluated against. Times are given as dates (2006-01-02) or RFC 3339 timestamps.
+func RuleConditionAttributes(attributes map[string]any) (map[string]any, error) {
+	vars := make(map[string]any, len(attributes))
+	for name, value := range attributes {
+		kind, ok := RuleConditionSchema[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown attribute %q", ErrInvalidConditionAttribute, name)
+		}
+		converted, ok := conditionValue(kind, value)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q must be a %s", ErrInvalidConditionAttribute, name, kind)
+		}
+		vars[name] = converted
+	}
+	return vars, nil
+}
+
+func conditionValue(kind utils.ExprKind, value any) (any, bool) {
+	switch kind {
+	case utils.ExprString:
+		text, ok := value.(string)
+		return text, ok
+	case utils.ExprNumber:
+		number, ok := value.(float64)
+		return number, ok
+	case utils.ExprBool:
+		flag, ok := value.(bool)
+		return flag, ok
+	case utils.ExprTime:
+		text, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+		if parsed, err := time.Parse(time.DateOnly, text); err == nil {
+			return parsed, true
+		}
+		parsed, err := time.Parse(time.RFC3339, text)
+		return parsed, err == nil
+	}
+	return nil, false
+}
+
+// ApplicableRules are the active rules that apply to a user. Rules whose condition
+// predates the expression language can't be evaluated and are listed apart.
+type ApplicableRules struct {
+	UserId      int    `json:"user_id"`
+	Rules       []Rule `json:"rules"`
+	Unevaluable []int  `json:"unevaluable,omitempty"`
+}
+
+// RuleEvaluateRequest evaluates a condition against a user, or against the given attributes.
+// Attributes missing from the request are not set, so conditions using them fail.
+type RuleEvaluateRequest struct {
+	Condition  string         `json:"condition" binding:"required"`
+	UserId     *int           `json:"user_id,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// RuleEvaluateResponse is the result of evaluating a condition
+type RuleEvaluateResponse struct {
+	Condition string `json:"condition"`
+	Applies   bool   `json:"applies"`
+}
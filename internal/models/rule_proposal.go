@@ -0,0 +1,114 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// RuleProposalAction is the change to the rules a proposal makes
+type RuleProposalAction string
+
+const (
+	RuleProposalCreate RuleProposalAction = "create"
+	RuleProposalModify RuleProposalAction = "modify"
+	RuleProposalDelete RuleProposalAction = "delete"
+	// RuleProposalRollback restores the content of an earlier version
+	RuleProposalRollback RuleProposalAction = "rollback"
+	RuleProposalPublish  RuleProposalAction = "publish"
+	RuleProposalRetire   RuleProposalAction = "retire"
+)
+
+// RuleStatus is the status the action moves the rule to, empty if it doesn't change it
+func (a RuleProposalAction) RuleStatus() RuleStatus {
+	switch a {
+	case RuleProposalPublish:
+		return RuleScheduled
+	case RuleProposalRetire:
+		return RuleRetired
+	}
+	return ""
+}
+
+// RuleProposalStatus is whether a proposal was reviewed, and how
+type RuleProposalStatus string
+
+const (
+	RuleProposalPending  RuleProposalStatus = "pending"
+	RuleProposalApproved RuleProposalStatus = "approved"
+	RuleProposalRejected RuleProposalStatus = "rejected"
+)
+
+// RuleProposal is a change to the rules an admin proposed in four-eyes mode.
+// It is applied when a different admin approves it.
+type RuleProposal struct {
+	Id     int                `json:"id"`
+	Action RuleProposalAction `json:"action"`
+	// Rule being changed or deleted, and the version the proposal was made against
+	RuleId      int `json:"rule_id,omitempty"`
+	RuleVersion int `json:"rule_version,omitempty"`
+	// Rule to create, only for create proposals
+	Rule *Rule `json:"rule,omitempty"`
+	// Modification to apply, only for modify proposals
+	Modification *RuleModify `json:"modification,omitempty"`
+	// Version to restore, only for rollback proposals
+	Target     *RuleVersion       `json:"target,omitempty"`
+	Status     RuleProposalStatus `json:"status"`
+	ProposedBy int                `json:"proposed_by"`
+	ReviewedBy int                `json:"reviewed_by,omitempty"`
+	// Why the proposal was rejected
+	ReviewReason string                `json:"review_reason,omitempty"`
+	CreatedAt    time.Time             `json:"created_at"`
+	ReviewedAt   *time.Time            `json:"reviewed_at,omitempty"`
+	Comments     []RuleProposalComment `json:"comments,omitempty"`
+}
+
+// Payload returns the document stored with the proposal, the rule, the modification or the version to restore
+func (p RuleProposal) Payload() ([]byte, error) {
+	switch p.Action {
+	case RuleProposalCreate:
+		return json.Marshal(p.Rule)
+	case RuleProposalModify:
+		return json.Marshal(p.Modification)
+	case RuleProposalRollback:
+		return json.Marshal(p.Target)
+	}
+	return nil, nil
+}
+
+// SetPayload reads the document stored with the proposal
+func (p *RuleProposal) SetPayload(payload []byte) error {
+	switch p.Action {
+	case RuleProposalCreate:
+		p.Rule = &Rule{}
+		return json.Unmarshal(payload, p.Rule)
+	case RuleProposalModify:
+		p.Modification = &RuleModify{}
+		return json.Unmarshal(payload, p.Modification)
+	case RuleProposalRollback:
+		p.Target = &RuleVersion{}
+		return json.Unmarshal(payload, p.Target)
+	}
+	return nil
+}
+
+// RuleProposalComment is a comment an admin left on a proposal
+type RuleProposalComment struct {
+	Id         int       `json:"id"`
+	ProposalId int       `json:"proposal_id"`
+	UserId     int       `json:"user_id"`
+	Comment    string    `json:"comment"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type RuleProposalCommentRequest struct {
+	Comment string `json:"comment" binding:"required,max=2000"`
+}
+
+type RuleProposalRejectRequest struct {
+	Reason string `json:"reason" binding:"required,max=2000"`
+}
+
+// RuleProposalListRequest selects the proposals listed, every field is optional
+type RuleProposalListRequest struct {
+	Status RuleProposalStatus `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+)
+
+// RuleVersion is the content a rule had at one of its versions
+type RuleVersion struct {
+	Rule
+	// Zero for versions from before history was kept
+	CreatedBy int       `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// Fields the version didn't record, by their JSON key. Versions from before mandatory, category,
+	// tags and translations were kept don't know them.
+	UnknownFields []string `json:"unknown_fields,omitempty"`
+}
+
+// Knows reports whether the version recorded the field with the given JSON key
+func (version RuleVersion) Knows(field string) bool {
+	return !slices.Contains(version.UnknownFields, field)
+}
+
+// RuleFieldChange is a field that differs between two versions of a rule
+type RuleFieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// RuleDiff compares two versions of a rule
+type RuleDiff struct {
+	RuleId int `json:"rule_id"`
+	// Zero when comparing against a rule that didn't exist yet
+	From    int               `json:"from"`
+	To      int               `json:"to"`
+	Changes []RuleFieldChange `json:"changes"`
+	// Fields one of the versions didn't record, so they can't be compared
+	UnknownFields []string `json:"unknown_fields,omitempty"`
+	// Word by word changes of the description
+	DescriptionDiff []utils.DiffChunk `json:"description_diff"`
+}
+
+// DiffRules returns the changes that turn the from version into the to version
+func DiffRules(from RuleVersion, to RuleVersion) RuleDiff {
+	diff := RuleDiff{RuleId: to.Id, From: from.Version, To: to.Version, Changes: []RuleFieldChange{}}
+	add := func(field string, fromValue any, toValue any) {
+		diff.Changes = append(diff.Changes, RuleFieldChange{Field: field, From: fromValue, To: toValue})
+	}
+
+	if from.Title != to.Title {
+		add("Title", from.Title, to.Title)
+	}
+	if from.Description != to.Description {
+		add("Description", from.Description, to.Description)
+	}
+	if !from.EffectiveDate.Equal(to.EffectiveDate) {
+		add("effectiveDate", from.EffectiveDate, to.EffectiveDate)
+	}
+	if from.ApplicationCondition != to.ApplicationCondition {
+		add("ApplicationCondition", from.ApplicationCondition, to.ApplicationCondition)
+	}
+	// Only fields both versions recorded can be compared
+	known := func(field string) bool {
+		if from.Knows(field) && to.Knows(field) {
+			return true
+		}
+		diff.UnknownFields = append(diff.UnknownFields, field)
+		return false
+	}
+	if known("mandatory") && from.Mandatory != to.Mandatory {
+		add("mandatory", from.Mandatory, to.Mandatory)
+	}
+	if known("category") && from.Category != to.Category {
+		add("category", from.Category, to.Category)
+	}
+	if known("tags") && !slices.Equal(from.Tags, to.Tags) {
+		add("tags", from.Tags, to.Tags)
+	}
+	if known("translations") && !maps.Equal(from.Translations, to.Translations) {
+		add("translations", from.Translations, to.Translations)
+	}
+	diff.DescriptionDiff = utils.DiffWords(from.Description, to.Description)
+	return diff
+}
@@ -1,8 +1,15 @@
 package models
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
 )
 
 type Rule struct {
@@ -11,26 +18,255 @@ type Rule struct {
 	Description          string    `json:"Description" binding:"required"`
 	EffectiveDate        time.Time `json:"effectiveDate" `
 	ApplicationCondition string    `json:"ApplicationCondition" binding:"required"`
+	Version              int       `json:"version"`
+	// Set by the server, new rules are drafts
+	Status RuleStatus `json:"status,omitempty"`
+	// When the rule stopped being in force, only for retired rules
+	RetiredAt *time.Time `json:"retired_at,omitempty"`
+	// Users must accept the current version of mandatory rules, like the terms of service
+	Mandatory bool `json:"mandatory,omitempty"`
+	// RuleGeneral when not given
+	Category RuleCategory `json:"category,omitempty" binding:"omitempty,oneof=general academic conduct privacy communication evaluation"`
+	// Free labels, stored lowercase
+	Tags []string `json:"tags,omitempty" binding:"omitempty,max=20,dive,min=1,max=40"`
+	// Title and description in other languages, by locale
+	Translations map[string]RuleTranslation `json:"translations,omitempty" binding:"omitempty,dive,keys,bcp47_language_tag,endkeys"`
+	// Locale Title and Description are in, only set when the rule was localized for the client
+	Locale string `json:"locale,omitempty"`
+}
+
+// RuleCategory groups rules by what they are about
+type RuleCategory string
+
+const (
+	RuleGeneral       RuleCategory = "general"
+	RuleAcademic      RuleCategory = "academic"
+	RuleConduct       RuleCategory = "conduct"
+	RulePrivacy       RuleCategory = "privacy"
+	RuleCommunication RuleCategory = "communication"
+	RuleEvaluation    RuleCategory = "evaluation"
+)
+
+// RuleDefaultLocale is the language rules are written in, their title and description
+// are returned when there is no translation for the locales the client accepts
+const RuleDefaultLocale = utils.DefaultLocale
+
+// RuleTranslation is the title and description of a rule in another language
+type RuleTranslation struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description" binding:"required"`
+}
+
+// Normalize lowercases the tags and the locales of the translations and drops repeated tags
+func (r *Rule) Normalize() {
+	r.Tags = normalizeRuleTags(r.Tags)
+	r.Translations = normalizeRuleTranslations(r.Translations)
+}
+
+func normalizeRuleTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	normalized := make([]string, 0, len(tags))
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+func normalizeRuleTranslations(translations map[string]RuleTranslation) map[string]RuleTranslation {
+	if translations == nil {
+		return nil
+	}
+	normalized := make(map[string]RuleTranslation, len(translations))
+	for locale, translation := range translations {
+		normalized[strings.ToLower(locale)] = translation
+	}
+	return normalized
+}
+
+// Localize returns the rule with the title and description of the first of the locales it has
+// a translation for, or as written in RuleDefaultLocale when it has none. Translations are left out.
+func (r Rule) Localize(locales []string) Rule {
+	localized := r
+	localized.Translations = nil
+	localized.Locale = RuleDefaultLocale
+	for _, locale := range locales {
+		locale = strings.ToLower(locale)
+		if locale == RuleDefaultLocale {
+			return localized
+		}
+		if translation, ok := r.Translations[locale]; ok {
+			localized.Title = translation.Title
+			localized.Description = translation.Description
+			localized.Locale = locale
+			return localized
+		}
+	}
+	return localized
+}
+
+// RuleStatus is the stage of its lifecycle a rule is in
+type RuleStatus string
+
+const (
+	// RuleDraft rules are being written and are not in force
+	RuleDraft RuleStatus = "draft"
+	// RuleScheduled rules are published and come into force on their effective date
+	RuleScheduled RuleStatus = "scheduled"
+	// RuleActive rules are in force
+	RuleActive RuleStatus = "active"
+	// RuleRetired rules were in force until they were retired
+	RuleRetired RuleStatus = "retired"
+)
+
+// CanTransitionTo reports whether a rule can go from this status to next.
+// Drafts are published, scheduled rules activated, and scheduled or active rules retired.
+func (s RuleStatus) CanTransitionTo(next RuleStatus) bool {
+	switch next {
+	case RuleScheduled:
+		return s == RuleDraft
+	case RuleActive:
+		return s == RuleScheduled
+	case RuleRetired:
+		return s == RuleScheduled || s == RuleActive
+	}
+	return false
+}
+
+// RuleListRequest selects the rules listed, every field is optional
+type RuleListRequest struct {
+	Status RuleStatus `form:"status" binding:"omitempty,oneof=draft scheduled active retired"`
+	// Only the rules in force at the end of this day
+	At       *time.Time   `form:"at" time_format:"2006-01-02" time_utc:"1"`
+	Category RuleCategory `form:"category" binding:"omitempty,oneof=general academic conduct privacy communication evaluation"`
+	// Only the rules with all of these tags
+	Tags []string `form:"tag"`
+}
+
+// ETag returns the entity tag clients must send in If-Match to modify or delete the rule.
+func (r Rule) ETag() string {
+	return fmt.Sprintf(`"%d"`, r.Version)
+}
+
+// RulesETag returns an entity tag for a list of rules that changes whenever
+// a rule is added, removed or modified, or returned in another locale.
+func RulesETag(rules []Rule) string {
+	hash := sha256.New()
+	for _, rule := range rules {
+		fmt.Fprintf(hash, "%d:%d;", rule.Id, rule.Version)
+		if rule.Locale != "" {
+			fmt.Fprintf(hash, "%s;", rule.Locale)
+		}
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil))[:16] + `"`
 }
 
 type RuleModify struct {
 	Title                string `json:"Title"`
 	Description          string `json:"Description" `
 	ApplicationCondition string `json:"ApplicationCondition" `
+	// Only drafts and scheduled rules can change their effective date
+	EffectiveDate *time.Time   `json:"effectiveDate"`
+	Mandatory     *bool        `json:"mandatory"`
+	Category      RuleCategory `json:"category" binding:"omitempty,oneof=general academic conduct privacy communication evaluation"`
+	// Replace all the tags or translations of the rule, an empty list or object removes them
+	Tags         []string                   `json:"tags" binding:"omitempty,max=20,dive,min=1,max=40"`
+	Translations map[string]RuleTranslation `json:"translations" binding:"omitempty,dive,keys,bcp47_language_tag,endkeys"`
 }
 
+// Normalize lowercases the tags and the locales of the translations and drops repeated tags
+func (m *RuleModify) Normalize() {
+	m.Tags = normalizeRuleTags(m.Tags)
+	m.Translations = normalizeRuleTranslations(m.Translations)
+}
+
+// IsEmpty reports whether the modification changes nothing
+func (m RuleModify) IsEmpty() bool {
+	return m.Title == "" && m.Description == "" && m.ApplicationCondition == "" && m.EffectiveDate == nil &&
+		m.Mandatory == nil && m.Category == "" && m.Tags == nil && m.Translations == nil
+}
+
+// RuleAuditAction is what was done to a rule in a rules audit entry
+type RuleAuditAction string
+
+const (
+	RuleAuditCreate   RuleAuditAction = "rule.create"
+	RuleAuditUpdate   RuleAuditAction = "rule.update"
+	RuleAuditDelete   RuleAuditAction = "rule.delete"
+	RuleAuditRollback RuleAuditAction = "rule.rollback"
+	RuleAuditPublish  RuleAuditAction = "rule.publish"
+	RuleAuditActivate RuleAuditAction = "rule.activate"
+	RuleAuditRetire   RuleAuditAction = "rule.retire"
+)
+
+// Audit is a rules audit entry as stored
 type Audit struct {
-	Id                   int
-	RuleId               sql.NullInt64
-	UserId               sql.NullInt64
+	Id     int
+	RuleId sql.NullInt64
+	UserId sql.NullInt64
+	// Admin who approved the proposal the action comes from, null unless four-eyes mode was on
+	ApprovedBy           sql.NullInt64
 	ModificationDate     time.Time
 	NatureOfModification string
+	// Empty for entries written before actions were recorded
+	Action RuleAuditAction
+	// State of the rule before and after the action, nil when it didn't exist
+	Before json.RawMessage
+	After  json.RawMessage
+	// Hash of the entry before this one, or AuditChainGenesis for the first
+	PrevHash string
+	// Hash of this entry's contents and PrevHash, see ComputeHash
+	Hash string
 }
 
+// AuditData is a rules audit entry as returned to clients
 type AuditData struct {
-	Id                   int
-	RuleId               int
-	UserId               int
-	ModificationDate     time.Time
-	NatureOfModification string
+	Id                   int             `json:"id"`
+	RuleId               int             `json:"rule_id,omitempty"`
+	UserId               int             `json:"user_id,omitempty"`
+	ApprovedBy           int             `json:"approved_by,omitempty"`
+	Action               RuleAuditAction `json:"action,omitempty"`
+	Before               json.RawMessage `json:"before" swaggertype:"object"`
+	After                json.RawMessage `json:"after" swaggertype:"object"`
+	ModificationDate     time.Time       `json:"modification_date"`
+	NatureOfModification string          `json:"nature_of_modification"`
+	Hash                 string          `json:"hash"`
+}
+
+// Data returns the entry as returned to clients
+func (a Audit) Data() AuditData {
+	return AuditData{
+		Id:                   a.Id,
+		RuleId:               int(a.RuleId.Int64),
+		UserId:               int(a.UserId.Int64),
+		ApprovedBy:           int(a.ApprovedBy.Int64),
+		Action:               a.Action,
+		Before:               a.Before,
+		After:                a.After,
+		ModificationDate:     a.ModificationDate,
+		NatureOfModification: a.NatureOfModification,
+		Hash:                 a.Hash,
+	}
+}
+
+// RuleAuditFilter selects rules audit entries, every field is optional
+type RuleAuditFilter struct {
+	RuleId *int            `form:"rule_id"`
+	UserId *int            `form:"user_id"`
+	Action RuleAuditAction `form:"action" binding:"omitempty,oneof=rule.create rule.update rule.delete rule.rollback rule.publish rule.activate rule.retire"`
+	From   *time.Time      `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To     *time.Time      `form:"to" time_format:"2006-01-02" time_utc:"1"`
+}
+
+type RuleAuditListRequest struct {
+	RuleAuditFilter
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=500"`
 }
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleStatus_CanTransitionTo(t *testing.T) {
+	allowed := map[RuleStatus][]RuleStatus{
+		RuleDraft:     {RuleScheduled},
+		RuleScheduled: {RuleActive, RuleRetired},
+		RuleActive:    {RuleRetired},
+		RuleRetired:   {},
+	}
+	statuses := []RuleStatus{RuleDraft, RuleScheduled, RuleActive, RuleRetired}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			assert.Equal(t, slices.Contains(allowed[from], to), from.CanTransitionTo(to), "%s to %s", from, to)
+		}
+	}
+}
+
+func TestRuleConditionAttributes(t *testing.T) {
+	vars, err := RuleConditionAttributes(map[string]any{
+		"role": "student", "id": float64(7), "verified": true, "created_at": "2025-06-01",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), vars["created_at"])
+
+	invalid := []map[string]any{
+		{"age": float64(20)},
+		{"role": float64(1)},
+		{"verified": "yes"},
+		{"created_at": "yesterday"},
+		{"id": "7"},
+	}
+	for _, attributes := range invalid {
+		_, err := RuleConditionAttributes(attributes)
+		assert.ErrorIs(t, err, ErrInvalidConditionAttribute, attributes)
+	}
+}
+
+func TestParseRuleCondition(t *testing.T) {
+	expression, err := ParseRuleCondition(`role == "student" && created_at < "2026-01-01"`)
+	assert.NoError(t, err)
+
+	applies, err := expression.Evaluate(RuleConditionVars(User{Role: "student", CreatedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)}))
+	assert.NoError(t, err)
+	assert.True(t, applies)
+
+	_, err = ParseRuleCondition(`grade > 4`)
+	assert.ErrorContains(t, err, `unknown attribute "grade"`)
+}
+
+func TestRule_Localize(t *testing.T) {
+	rule := Rule{Id: 1, Title: "Be nice", Description: "Always", Translations: map[string]RuleTranslation{
+		"es":    {Title: "Sé amable", Description: "Siempre"},
+		"pt-br": {Title: "Seja gentil", Description: "Sempre"},
+	}}
+
+	tests := []struct {
+		name     string
+		locales  []string
+		expected string
+		locale   string
+	}{
+		{"translated", []string{"es"}, "Sé amable", "es"},
+		{"regional", []string{"pt-BR", "pt"}, "Seja gentil", "pt-br"},
+		{"base language", []string{"es-ar", "es"}, "Sé amable", "es"},
+		{"default language first", []string{"en", "es"}, "Be nice", RuleDefaultLocale},
+		{"not translated", []string{"fr"}, "Be nice", RuleDefaultLocale},
+		{"no locales", nil, "Be nice", RuleDefaultLocale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			localized := rule.Localize(tt.locales)
+			assert.Equal(t, tt.expected, localized.Title)
+			assert.Equal(t, tt.locale, localized.Locale)
+			assert.Nil(t, localized.Translations)
+		})
+	}
+	assert.Len(t, rule.Translations, 2)
+}
+
+func TestRule_Normalize(t *testing.T) {
+	rule := Rule{
+		Tags:         []string{" Exams", "exams", "", "Data"},
+		Translations: map[string]RuleTranslation{"pt-BR": {Title: "T", Description: "D"}},
+	}
+
+	rule.Normalize()
+
+	assert.Equal(t, []string{"exams", "data"}, rule.Tags)
+	assert.Contains(t, rule.Translations, "pt-br")
+
+	modification := RuleModify{Tags: []string{}}
+	modification.Normalize()
+	assert.NotNil(t, modification.Tags)
+	assert.False(t, modification.IsEmpty())
+	assert.True(t, RuleModify{}.IsEmpty())
+}
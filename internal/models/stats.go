@@ -0,0 +1,67 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	StatsIntervalDay  = "day"
+	StatsIntervalWeek = "week"
+
+	// StatsDefaultDays is how many days the statistics cover when no range is given
+	StatsDefaultDays = 30
+	// StatsMaxDays is the longest range the statistics can cover
+	StatsMaxDays = 366
+)
+
+var ErrInvalidStatsRange = errors.New("from must not be after to and the range can't be longer than 366 days")
+
+// StatsRequest selects the days covered by the statistics, both ends included
+type StatsRequest struct {
+	From     *time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To       *time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`
+	Interval string     `form:"interval" binding:"omitempty,oneof=day week"`
+}
+
+// AdminStats are aggregates of the whole user base. Users, blocked users and notification tokens
+// are counted as they are now, signups, logins and chat usage within the range.
+type AdminStats struct {
+	From               time.Time      `json:"from"`
+	To                 time.Time      `json:"to"`
+	Interval           string         `json:"interval"`
+	Users              UserStats      `json:"users"`
+	Signups            []StatsBucket  `json:"signups"`
+	Logins             LoginStats     `json:"logins"`
+	NotificationTokens map[string]int `json:"notification_tokens"`
+	Chat               ChatStats      `json:"chat"`
+	GeneratedAt        time.Time      `json:"generated_at"`
+}
+
+type UserStats struct {
+	Total      int            `json:"total"`
+	ByRole     map[string]int `json:"by_role"`
+	Verified   int            `json:"verified"`
+	Unverified int            `json:"unverified"`
+	Blocked    int            `json:"blocked"`
+}
+
+type StatsBucket struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+type LoginStats struct {
+	Attempts    int     `json:"attempts"`
+	Failed      int     `json:"failed"`
+	FailedRate  float64 `json:"failed_rate"`
+	ActiveUsers int     `json:"active_users"`
+}
+
+type ChatStats struct {
+	Messages      int      `json:"messages"`
+	UserMessages  int      `json:"user_messages"`
+	ActiveUsers   int      `json:"active_users"`
+	RatedMessages int      `json:"rated_messages"`
+	AverageRating *float64 `json:"average_rating"`
+}
@@ -1,6 +1,19 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"net/url"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	NameMinLength         = 3
+	NameMaxLength         = 60
+	LocationMaxLength     = 50
+	DescriptionMaxLength  = 240
+	ProfilePhotoMaxLength = 255
+)
 
 type User struct {
 	Id           int       `json:"id" db:"id"`
@@ -15,9 +28,19 @@ type User struct {
 	Description  string    `json:"description,omitempty" db:"description"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	Version      int       `json:"-" db:"version"`
 	Blocked      bool      `json:"blocked"` // No direct db tag, calculated with JOIN
 }
 
+// ETag returns the entity tag of the user representation. The blocked flag lives in
+// another table so it is part of the tag instead of bumping the row version.
+func (u User) ETag() string {
+	if u.Blocked {
+		return fmt.Sprintf(`"%d-blocked"`, u.Version)
+	}
+	return fmt.Sprintf(`"%d"`, u.Version)
+}
+
 type UserUpdateDto struct {
 	Name         string  `json:"name" db:"name"`
 	Surname      string  `json:"surname" db:"surname"`
@@ -44,6 +67,81 @@ func (u *User) Update(user UserUpdateDto) {
 	}
 }
 
+// UserPatch is a JSON Merge Patch (RFC 7396) document for a user profile.
+// Members left out of the document are kept, members set to null are cleared.
+type UserPatch struct {
+	Name         PatchField[string] `json:"name" swaggertype:"string"`
+	Surname      PatchField[string] `json:"surname" swaggertype:"string"`
+	Location     PatchField[string] `json:"location" swaggertype:"string"`
+	ProfilePhoto PatchField[string] `json:"profile_photo" swaggertype:"string"`
+	Description  PatchField[string] `json:"description" swaggertype:"string"`
+}
+
+// Validate checks every member present in the patch and reports all failures together.
+func (p UserPatch) Validate() error {
+	var errs ValidationErrors
+	checkLength := func(field string, value PatchField[string], min, max int) {
+		if !value.Set || value.Null {
+			return
+		}
+		if length := utf8.RuneCountInString(value.Value); length < min || length > max {
+			errs = append(errs, FieldError{field, fmt.Sprintf("must be between %d and %d characters", min, max)})
+		}
+	}
+
+	if p.Name.Null {
+		errs = append(errs, FieldError{"name", "cannot be cleared"})
+	}
+	checkLength("name", p.Name, NameMinLength, NameMaxLength)
+	if p.Surname.Null {
+		errs = append(errs, FieldError{"surname", "cannot be cleared"})
+	}
+	checkLength("surname", p.Surname, NameMinLength, NameMaxLength)
+	checkLength("location", p.Location, 0, LocationMaxLength)
+	checkLength("description", p.Description, 0, DescriptionMaxLength)
+	checkLength("profile_photo", p.ProfilePhoto, 1, ProfilePhotoMaxLength)
+	if p.ProfilePhoto.Set && !p.ProfilePhoto.Null && !isHTTPURL(p.ProfilePhoto.Value) {
+		errs = append(errs, FieldError{"profile_photo", "must be an absolute http or https URL"})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.ParseRequestURI(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// ApplyPatch merges the patch into the user following RFC 7396 semantics.
+func (u *User) ApplyPatch(patch UserPatch) {
+	if patch.Name.Set && !patch.Name.Null {
+		u.Name = patch.Name.Value
+	}
+	if patch.Surname.Set && !patch.Surname.Null {
+		u.Surname = patch.Surname.Value
+	}
+	if patch.Location.Set {
+		u.Location = patch.Location.Value
+	}
+	if patch.Description.Set {
+		u.Description = patch.Description.Value
+	}
+	if patch.ProfilePhoto.Set {
+		if patch.ProfilePhoto.Null {
+			u.ProfilePhoto = nil
+		} else {
+			photo := patch.ProfilePhoto.Value
+			u.ProfilePhoto = &photo
+		}
+	}
+}
+
 type BlockedUser struct {
 	Id            int        `json:"id" db:"id"`
 	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
@@ -68,7 +166,7 @@ type EmailVerifiaction struct {
 
 type CreateUserRequest struct {
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=8"`
+	Password string `json:"password" binding:"required"`
 	Name     string `json:"name" binding:"required,min=3,max=60"`
 	Surname  string `json:"surname" binding:"required,min=3,max=60"`
 	Role     string `json:"role" binding:"required,oneof=student teacher admin"`
@@ -77,12 +175,17 @@ type CreateUserRequest struct {
 
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=8"`
+	Password string `json:"password" binding:"required"`
 }
 
 type PasswordModifyRequest struct {
-	Token    string `json:"token" binding:"required,max=6"`
-	Password string `json:"password" binding:"required,min=8,max=60"`
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type PasswordChangeRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
 }
 
 type LocationModifyRequest struct {
@@ -94,8 +197,31 @@ type PasswordResetRequest struct {
 }
 
 type PasswordResetData struct {
-	Email  string
-	UserId int
-	Exp    time.Time
-	Used   bool
+	Id        int
+	Email     string
+	UserId    int
+	TokenHash string
+	Exp       time.Time
+	Used      bool
+	Attempts  int
+}
+
+type EmailChangeRequest struct {
+	Id              int       `json:"id" db:"id"`
+	UserId          int       `json:"user_id" db:"user_id"`
+	OldEmail        string    `json:"old_email" db:"old_email"`
+	NewEmail        string    `json:"new_email" db:"new_email"`
+	VerificationPin string    `json:"-" db:"verification_pin"`
+	CancelTokenHash string    `json:"-" db:"cancel_token_hash"`
+	Attempts        int       `json:"-" db:"attempts"`
+	PinExpiration   time.Time `json:"pin_expiration" db:"pin_expiration"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+type EmailChangeStartRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type EmailChangeConfirmRequest struct {
+	VerificationPin string `json:"pin" binding:"required"`
 }
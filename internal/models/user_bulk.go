@@ -0,0 +1,84 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	BulkActionBlock      = "block"
+	BulkActionUnblock    = "unblock"
+	BulkActionChangeRole = "change_role"
+	BulkActionVerify     = "verify"
+	BulkActionSoftDelete = "soft_delete"
+
+	BulkResultUpdated = "updated"
+	// BulkResultUnchanged marks users already in the state the action leaves them in
+	BulkResultUnchanged = "unchanged"
+	BulkResultNotFound  = "not_found"
+	// BulkResultSkipped marks the admin running the action, who can't act on their own account
+	BulkResultSkipped = "skipped"
+
+	// MaxBulkUsers is the most users a single bulk action can change
+	MaxBulkUsers = 1000
+)
+
+var (
+	ErrBulkTargets       = errors.New("either ids or filter must be given, but not both")
+	ErrBulkEmptyFilter   = errors.New("filter must have at least one condition")
+	ErrBulkTooManyUsers  = errors.New("too many users for a single bulk action")
+	ErrBulkMissingRole   = errors.New("role is required to change the role")
+	ErrBulkMissingReason = errors.New("reason is required to block users")
+)
+
+type BulkUserActionRequest struct {
+	Ids    []int       `json:"ids" binding:"omitempty,max=1000,dive,min=1"`
+	Filter *UserFilter `json:"filter"`
+	Action string      `json:"action" binding:"required,oneof=block unblock change_role verify soft_delete"`
+	// Reason and DurationMinutes are for block, a block without duration is permanent
+	Reason          string `json:"reason" binding:"max=255"`
+	DurationMinutes int    `json:"duration_minutes" binding:"min=0"`
+	// Role is for change_role
+	Role string `json:"role" binding:"omitempty,oneof=student teacher admin"`
+}
+
+// Validate checks the rules binding can't express, which fields are needed depends on the action
+func (r BulkUserActionRequest) Validate() error {
+	if (len(r.Ids) == 0) == (r.Filter == nil) {
+		return ErrBulkTargets
+	}
+	if r.Filter != nil && r.Filter.IsEmpty() {
+		return ErrBulkEmptyFilter
+	}
+	if r.Action == BulkActionChangeRole && r.Role == "" {
+		return ErrBulkMissingRole
+	}
+	if r.Action == BulkActionBlock && r.Reason == "" {
+		return ErrBulkMissingReason
+	}
+	return nil
+}
+
+// BulkUserAction is a validated bulk action ready to run
+type BulkUserAction struct {
+	ActorId      int
+	Action       string
+	Ids          []int
+	Filter       *UserFilter
+	Reason       string
+	BlockedUntil *time.Time
+	Role         string
+}
+
+type BulkUserResult struct {
+	Id     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+type BulkUserActionReport struct {
+	AuditId int              `json:"audit_id"`
+	Action  string           `json:"action"`
+	Total   int              `json:"total"`
+	Updated int              `json:"updated"`
+	Results []BulkUserResult `json:"results"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkUserActionRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request BulkUserActionRequest
+		err     error
+	}{
+		{name: "ids", request: BulkUserActionRequest{Ids: []int{1}, Action: BulkActionVerify}},
+		{name: "filter", request: BulkUserActionRequest{Filter: &UserFilter{Role: "student"}, Action: BulkActionSoftDelete}},
+		{name: "no targets", request: BulkUserActionRequest{Action: BulkActionVerify}, err: ErrBulkTargets},
+		{name: "both targets", request: BulkUserActionRequest{Ids: []int{1}, Filter: &UserFilter{Role: "student"}, Action: BulkActionVerify}, err: ErrBulkTargets},
+		{name: "empty filter", request: BulkUserActionRequest{Filter: &UserFilter{Search: " "}, Action: BulkActionVerify}, err: ErrBulkEmptyFilter},
+		{name: "role missing", request: BulkUserActionRequest{Ids: []int{1}, Action: BulkActionChangeRole}, err: ErrBulkMissingRole},
+		{name: "reason missing", request: BulkUserActionRequest{Ids: []int{1}, Action: BulkActionBlock}, err: ErrBulkMissingReason},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.request.Validate(), tt.err)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+// Kinds of user events, streamed to the connected clients of the user
+const (
+	// UserEventInbox is a new entry in the inbox of the user
+	UserEventInbox = "inbox"
+	// UserEventBlock means the user was blocked, the stream ends after it
+	UserEventBlock = "block"
+	// UserEventLogout means the sessions of the user were revoked, the stream ends after it
+	UserEventLogout = "logout"
+)
+
+// UserEventsChannel is the Postgres channel the database announces the user events on
+const UserEventsChannel = "user_events"
+
+// UserEvent is something that happened to a user that their connected clients are told about right away
+type UserEvent struct {
+	// Id of the inbox entry for inbox events, streams are resumed from it
+	Id     int    `json:"id,omitempty"`
+	UserId int    `json:"user_id"`
+	Type   string `json:"type"`
+	// Entry is loaded for inbox events before they are streamed
+	Entry *InboxEntry `json:"entry,omitempty"`
+}
+
+// Ends reports whether the stream ends after the event, because the user has to log in again
+func (e UserEvent) Ends() bool {
+	return e.Type == UserEventBlock || e.Type == UserEventLogout
+}
+
+// NewInboxEvent returns the event of the entry being added to the inbox
+func NewInboxEvent(entry InboxEntry) UserEvent {
+	return UserEvent{Id: entry.Id, UserId: entry.UserId, Type: UserEventInbox, Entry: &entry}
+}
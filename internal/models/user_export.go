@@ -0,0 +1,108 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatXLSX  = "xlsx"
+	ExportFormatJSONL = "jsonl"
+)
+
+var ErrUnknownExportColumn = errors.New("unknown column")
+
+// UserExportColumns are the columns an export can have, in their default order.
+// The password hash is never exported.
+var UserExportColumns = []string{
+	"id", "name", "surname", "email", "location", "role", "verified", "blocked",
+	"profile_photo", "description", "created_at", "updated_at",
+}
+
+// UserFilter narrows a user listing. Every field is optional, dates are inclusive days in UTC.
+type UserFilter struct {
+	Role        string     `json:"role" form:"role" binding:"omitempty,oneof=student teacher admin"`
+	Verified    *bool      `json:"verified" form:"verified"`
+	Blocked     *bool      `json:"blocked" form:"blocked"`
+	Search      string     `json:"q" form:"q"`
+	CreatedFrom *time.Time `json:"created_from" form:"created_from" time_format:"2006-01-02" time_utc:"1"`
+	CreatedTo   *time.Time `json:"created_to" form:"created_to" time_format:"2006-01-02" time_utc:"1"`
+}
+
+// IsEmpty reports whether the filter matches every user
+func (f UserFilter) IsEmpty() bool {
+	return f.Role == "" && f.Verified == nil && f.Blocked == nil && strings.TrimSpace(f.Search) == "" &&
+		f.CreatedFrom == nil && f.CreatedTo == nil
+}
+
+type UserExportRequest struct {
+	UserFilter
+	Format string `form:"format" binding:"omitempty,oneof=csv xlsx jsonl"`
+	// Columns is a comma separated subset of UserExportColumns
+	Columns string `form:"columns"`
+}
+
+// ParseExportColumns validates a comma separated list of columns, an empty list selects all of them
+func ParseExportColumns(columns string) ([]string, error) {
+	if strings.TrimSpace(columns) == "" {
+		return UserExportColumns, nil
+	}
+
+	known := make(map[string]bool, len(UserExportColumns))
+	for _, column := range UserExportColumns {
+		known[column] = true
+	}
+
+	var selected []string
+	seen := make(map[string]bool)
+	for _, column := range strings.Split(columns, ",") {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !known[column] {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownExportColumn, column)
+		}
+		if !seen[column] {
+			seen[column] = true
+			selected = append(selected, column)
+		}
+	}
+	return selected, nil
+}
+
+// ExportValues returns the value of each column for the user. Missing optional values are nil.
+func (u User) ExportValues(columns []string) []any {
+	values := make([]any, len(columns))
+	for i, column := range columns {
+		switch column {
+		case "id":
+			values[i] = u.Id
+		case "name":
+			values[i] = u.Name
+		case "surname":
+			values[i] = u.Surname
+		case "email":
+			values[i] = u.Email
+		case "location":
+			values[i] = u.Location
+		case "role":
+			values[i] = u.Role
+		case "verified":
+			values[i] = u.Verified
+		case "blocked":
+			values[i] = u.Blocked
+		case "profile_photo":
+			if u.ProfilePhoto != nil {
+				values[i] = *u.ProfilePhoto
+			}
+		case "description":
+			values[i] = u.Description
+		case "created_at":
+			values[i] = u.CreatedAt
+		case "updated_at":
+			values[i] = u.UpdatedAt
+		}
+	}
+	return values
+}
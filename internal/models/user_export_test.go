@@ -0,0 +1,30 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExportColumns(t *testing.T) {
+	columns, err := ParseExportColumns("")
+	assert.NoError(t, err)
+	assert.Equal(t, UserExportColumns, columns)
+
+	columns, err = ParseExportColumns(" Email, name,email ")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"email", "name"}, columns)
+
+	_, err = ParseExportColumns("email,password")
+	assert.ErrorIs(t, err, ErrUnknownExportColumn)
+}
+
+func TestUser_ExportValues(t *testing.T) {
+	createdAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	user := User{Id: 4, Email: "ana@example.com", Password: "hash", Verified: true, CreatedAt: createdAt}
+
+	values := user.ExportValues([]string{"id", "email", "verified", "profile_photo", "created_at"})
+
+	assert.Equal(t, []any{4, "ana@example.com", true, nil, createdAt}, values)
+}
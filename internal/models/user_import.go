@@ -0,0 +1,158 @@
+package models
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	ImportModeTransactional = "transactional"
+	ImportModeBestEffort    = "best_effort"
+
+	ImportRowValid   = "valid"
+	ImportRowCreated = "created"
+	ImportRowFailed  = "failed"
+	// ImportRowSkipped marks valid rows not created because another row failed in transactional mode
+	ImportRowSkipped = "skipped"
+
+	ImportJobRunning = "running"
+	ImportJobDone    = "done"
+	ImportJobFailed  = "failed"
+)
+
+var ErrMissingImportColumn = errors.New("missing required column")
+
+// UserImportRow is one user of an import file. Password is optional, users imported
+// without one get a random password and should be invited to set their own.
+type UserImportRow struct {
+	Line     int    `json:"-"`
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Surname  string `json:"surname"`
+	Role     string `json:"role"`
+	Password string `json:"password"`
+	// ParseError is set when the line couldn't be read at all
+	ParseError string `json:"-"`
+}
+
+type UserImportOptions struct {
+	DryRun          bool   `form:"dry_run"`
+	Mode            string `form:"mode" binding:"omitempty,oneof=transactional best_effort"`
+	SendInvitations bool   `form:"invite"`
+	Async           bool   `form:"async"`
+}
+
+type UserImportRowResult struct {
+	Line   int      `json:"line"`
+	Email  string   `json:"email"`
+	Id     int      `json:"id,omitempty"`
+	Status string   `json:"status"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+type UserImportReport struct {
+	DryRun  bool                  `json:"dry_run"`
+	Mode    string                `json:"mode"`
+	Total   int                   `json:"total"`
+	Valid   int                   `json:"valid"`
+	Created int                   `json:"created"`
+	Failed  int                   `json:"failed"`
+	Rows    []UserImportRowResult `json:"rows"`
+}
+
+type UserImportJob struct {
+	Id         int               `json:"id"`
+	Status     string            `json:"status"`
+	CreatedBy  int               `json:"created_by"`
+	Report     *UserImportReport `json:"report,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+}
+
+// ParseUserImportCSV reads a CSV file with a header row. The email, name and surname columns
+// are required, role and password are optional and unknown columns are ignored.
+func ParseUserImportCSV(r io.Reader) ([]UserImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"email", "name", "surname"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingImportColumn, required)
+		}
+	}
+
+	var rows []UserImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			rows = append(rows, UserImportRow{Line: parseErr.StartLine, ParseError: parseErr.Err.Error()})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		rows = append(rows, UserImportRow{
+			Line:     line,
+			Email:    get("email"),
+			Name:     get("name"),
+			Surname:  get("surname"),
+			Role:     get("role"),
+			Password: get("password"),
+		})
+	}
+	return rows, nil
+}
+
+// ParseUserImportJSONL reads one JSON object per line, blank lines are skipped.
+func ParseUserImportJSONL(r io.Reader) ([]UserImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var rows []UserImportRow
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		row := UserImportRow{}
+		if err := json.Unmarshal([]byte(text), &row); err != nil {
+			row = UserImportRow{ParseError: "invalid JSON"}
+		}
+		row.Line = line
+		row.Email = strings.TrimSpace(row.Email)
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
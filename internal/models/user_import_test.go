@@ -0,0 +1,46 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUserImportCSV(t *testing.T) {
+	file := "\ufeffEmail,Name,Surname,Role,Extra\n" +
+		"ana@example.com, Ana ,Lopez,teacher,x\n" +
+		"juan@example.com,Juan,Perez\n" +
+		"\"broken@example.com,Broken,Row\n"
+
+	rows, err := ParseUserImportCSV(strings.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, UserImportRow{Line: 2, Email: "ana@example.com", Name: "Ana", Surname: "Lopez", Role: "teacher"}, rows[0])
+	assert.Equal(t, UserImportRow{Line: 3, Email: "juan@example.com", Name: "Juan", Surname: "Perez"}, rows[1])
+	assert.NotEmpty(t, rows[2].ParseError)
+}
+
+func TestParseUserImportCSV_MissingColumn(t *testing.T) {
+	_, err := ParseUserImportCSV(strings.NewReader("email,name\nana@example.com,Ana\n"))
+	assert.ErrorIs(t, err, ErrMissingImportColumn)
+	assert.ErrorContains(t, err, "surname")
+}
+
+func TestParseUserImportJSONL(t *testing.T) {
+	file := `{"email":" ana@example.com","name":"Ana","surname":"Lopez","password":"Secret123"}
+
+not json
+{"email":"juan@example.com","name":"Juan","surname":"Perez","role":"student"}
+`
+	rows, err := ParseUserImportJSONL(strings.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, UserImportRow{Line: 1, Email: "ana@example.com", Name: "Ana", Surname: "Lopez", Password: "Secret123"}, rows[0])
+	assert.Equal(t, UserImportRow{Line: 3, ParseError: "invalid JSON"}, rows[1])
+	assert.Equal(t, 4, rows[2].Line)
+	assert.Equal(t, "student", rows[2].Role)
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -27,3 +28,51 @@ func TestUser_Update(t *testing.T) {
 	assert.Equal(t, description, user.Description)
 	assert.Equal(t, profilePhoto, *user.ProfilePhoto)
 }
+
+func TestUserPatch_UnmarshalDistinguishesNullAndMissing(t *testing.T) {
+	var patch UserPatch
+	err := json.Unmarshal([]byte(`{"location": null, "description": "new"}`), &patch)
+	assert.NoError(t, err)
+
+	assert.False(t, patch.Name.Set)
+	assert.True(t, patch.Location.Set)
+	assert.True(t, patch.Location.Null)
+	assert.True(t, patch.Description.Set)
+	assert.False(t, patch.Description.Null)
+	assert.Equal(t, "new", patch.Description.Value)
+}
+
+func TestUser_ApplyPatch(t *testing.T) {
+	photo := "https://example.com/a.png"
+	user := User{Name: "John", Surname: "Doe", Location: "BA", Description: "desc", ProfilePhoto: &photo}
+
+	var patch UserPatch
+	err := json.Unmarshal([]byte(`{"surname": "Smith", "location": null, "profile_photo": null}`), &patch)
+	assert.NoError(t, err)
+	user.ApplyPatch(patch)
+
+	assert.Equal(t, "John", user.Name)
+	assert.Equal(t, "Smith", user.Surname)
+	assert.Equal(t, "", user.Location)
+	assert.Equal(t, "desc", user.Description)
+	assert.Nil(t, user.ProfilePhoto)
+}
+
+func TestUserPatch_Validate(t *testing.T) {
+	var valid UserPatch
+	err := json.Unmarshal([]byte(`{"name": "Johnny", "profile_photo": "https://example.com/a.png", "description": null}`), &valid)
+	assert.NoError(t, err)
+	assert.NoError(t, valid.Validate())
+
+	var invalid UserPatch
+	err = json.Unmarshal([]byte(`{"name": "Jo", "surname": null, "profile_photo": "ftp://example.com/a.png"}`), &invalid)
+	assert.NoError(t, err)
+
+	err = invalid.Validate()
+	var validationErrors ValidationErrors
+	assert.ErrorAs(t, err, &validationErrors)
+	assert.Len(t, validationErrors, 3)
+	assert.Equal(t, "name", validationErrors[0].Field)
+	assert.Equal(t, "surname", validationErrors[1].Field)
+	assert.Equal(t, "profile_photo", validationErrors[2].Field)
+}
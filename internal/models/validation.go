@@ -0,0 +1,21 @@
+package models
+
+import "strings"
+
+// FieldError describes a single request field that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors collects every field that failed validation so clients can
+// fix them all at once instead of one request at a time.
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(v))
+	for _, fieldErr := range v {
+		msgs = append(msgs, fieldErr.Field+": "+fieldErr.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+)
+
+// AuditChainRepository reads the hash chain of the rules audit and stores its signed checkpoints.
+// The hashes themselves are computed by the database when entries are inserted.
+type AuditChainRepository interface {
+	GetChainHead(ctx context.Context) (*models.AuditChainHead, error)
+	StreamChain(ctx context.Context, fn func(models.Audit) error) error
+	AddCheckpoint(ctx context.Context, checkpoint *models.AuditCheckpoint) error
+	GetCheckpoints(ctx context.Context, limit int) ([]models.AuditCheckpoint, error)
+	StreamCheckpoints(ctx context.Context, fn func(models.AuditCheckpoint) error) error
+}
+
+type auditChainRepository struct {
+	DB *sql.DB
+}
+
+func NewAuditChainRepository(db *sql.DB) *auditChainRepository {
+	return &auditChainRepository{DB: db}
+}
+
+const auditCheckpointColumns = "id, last_audit_id, head_hash, entries, public_key, signature, created_at"
+
+// GetChainHead returns the last entry of the chain, or the genesis hash when it is empty
+func (db auditChainRepository) GetChainHead(ctx context.Context) (*models.AuditChainHead, error) {
+	head := models.AuditChainHead{Hash: models.AuditChainGenesis}
+	err := db.DB.QueryRowContext(ctx, `
+		SELECT id, hash, (SELECT COUNT(*) FROM rules_audit)
+		FROM rules_audit ORDER BY id DESC LIMIT 1`,
+	).Scan(&head.LastAuditId, &head.Hash, &head.Entries)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return &head, nil
+}
+
+// StreamChain calls fn with every rules audit entry, in chain order
+func (db auditChainRepository) StreamChain(ctx context.Context, fn func(models.Audit) error) error {
+	rows, err := db.DB.QueryContext(ctx, "SELECT "+ruleAuditColumns+" FROM rules_audit ORDER BY id")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		audit, err := scanRuleAudit(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(audit); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// AddCheckpoint saves a signed checkpoint and fills its id
+func (db auditChainRepository) AddCheckpoint(ctx context.Context, checkpoint *models.AuditCheckpoint) error {
+	return db.DB.QueryRowContext(ctx, `
+		INSERT INTO audit_checkpoints (last_audit_id, head_hash, entries, public_key, signature, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		checkpoint.LastAuditId, checkpoint.HeadHash, checkpoint.Entries, checkpoint.PublicKey, checkpoint.Signature, checkpoint.CreatedAt,
+	).Scan(&checkpoint.Id)
+}
+
+// GetCheckpoints returns the latest checkpoints, newest first
+func (db auditChainRepository) GetCheckpoints(ctx context.Context, limit int) ([]models.AuditCheckpoint, error) {
+	rows, err := db.DB.QueryContext(ctx, "SELECT "+auditCheckpointColumns+" FROM audit_checkpoints ORDER BY id DESC LIMIT $1", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checkpoints := []models.AuditCheckpoint{}
+	for rows.Next() {
+		checkpoint, err := scanAuditCheckpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, rows.Err()
+}
+
+// StreamCheckpoints calls fn with every checkpoint, oldest first
+func (db auditChainRepository) StreamCheckpoints(ctx context.Context, fn func(models.AuditCheckpoint) error) error {
+	rows, err := db.DB.QueryContext(ctx, "SELECT "+auditCheckpointColumns+" FROM audit_checkpoints ORDER BY id")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		checkpoint, err := scanAuditCheckpoint(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(checkpoint); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func scanAuditCheckpoint(rows *sql.Rows) (models.AuditCheckpoint, error) {
+	var checkpoint models.AuditCheckpoint
+	err := rows.Scan(&checkpoint.Id, &checkpoint.LastAuditId, &checkpoint.HeadHash, &checkpoint.Entries,
+		&checkpoint.PublicKey, &checkpoint.Signature, &checkpoint.CreatedAt)
+	return checkpoint, err
+}
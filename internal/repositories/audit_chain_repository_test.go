@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditChainRepository_GetChainHead(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewAuditChainRepository(db)
+
+	mock.ExpectQuery(`SELECT id, hash, \(SELECT COUNT\(\*\) FROM rules_audit\) FROM rules_audit ORDER BY id DESC LIMIT 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hash", "count"}).AddRow(12, "abc", 11))
+	mock.ExpectQuery(`FROM rules_audit ORDER BY id DESC LIMIT 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hash", "count"}))
+
+	head, err := repo.GetChainHead(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &models.AuditChainHead{LastAuditId: 12, Hash: "abc", Entries: 11}, head)
+
+	head, err = repo.GetChainHead(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &models.AuditChainHead{Hash: models.AuditChainGenesis}, head)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditChainRepository_AddCheckpoint(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	checkpoint := &models.AuditCheckpoint{LastAuditId: 12, HeadHash: "abc", Entries: 11, PublicKey: "key", Signature: "sig", CreatedAt: time.Now()}
+	mock.ExpectQuery(`INSERT INTO audit_checkpoints \(last_audit_id, head_hash, entries, public_key, signature, created_at\)`).
+		WithArgs(12, "abc", 11, "key", "sig", checkpoint.CreatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	err = NewAuditChainRepository(db).AddCheckpoint(context.Background(), checkpoint)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, checkpoint.Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditChainRepository_StreamChain(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT id, rule_id, user_id, approved_by, modification_date, nature_of_modification, COALESCE\(action, ''\), before, after, prev_hash, hash FROM rules_audit ORDER BY id`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "rule_id", "user_id", "approved_by", "modification_date", "nature_of_modification", "action", "before", "after", "prev_hash", "hash"}).
+			AddRow(1, 10, 1, nil, now, "Created Rule 10: A", "", nil, nil, models.AuditChainGenesis, "a1").
+			AddRow(2, 10, 1, 2, now, "Deleted Rule 10: A", "rule.delete", []byte(`{"Title": "A"}`), nil, "a1", "b2"))
+
+	var hashes []string
+	err = NewAuditChainRepository(db).StreamChain(context.Background(), func(audit models.Audit) error {
+		hashes = append(hashes, audit.PrevHash+">"+audit.Hash)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{models.AuditChainGenesis + ">a1", "a1>b2"}, hashes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
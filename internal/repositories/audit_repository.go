@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+)
+
+type AuditRepository interface {
+	AddAuditEvent(ctx context.Context, event *models.AuditEvent) error
+	GetAuditEvents(ctx context.Context, filter models.AuditFilter, limit int, offset int) ([]models.AuditEvent, int, error)
+	StreamAuditEvents(ctx context.Context, filter models.AuditFilter, fn func(models.AuditEvent) error) error
+}
+
+type auditRepository struct {
+	DB *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *auditRepository {
+	return &auditRepository{DB: db}
+}
+
+const auditEventColumns = "id, actor_id, action, target_type, target_id, before, after, status_code, ip, request_id, created_at"
+
+// AddAuditEvent saves the event and fills its id and creation time
+func (db auditRepository) AddAuditEvent(ctx context.Context, event *models.AuditEvent) error {
+	return db.DB.QueryRowContext(ctx, `
+		INSERT INTO audit_events (actor_id, action, target_type, target_id, before, after, status_code, ip, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at`,
+		event.ActorId, event.Action, event.TargetType, event.TargetId, nullableJSON(event.Before), nullableJSON(event.After),
+		event.StatusCode, event.IP, event.RequestId,
+	).Scan(&event.Id, &event.CreatedAt)
+}
+
+// GetAuditEvents returns a page of the events matching the filter, newest first, and how many match in total
+func (db auditRepository) GetAuditEvents(ctx context.Context, filter models.AuditFilter, limit int, offset int) ([]models.AuditEvent, int, error) {
+	where, args := auditFilterClause(filter)
+
+	var total int
+	if err := db.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_events"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM audit_events%s ORDER BY id DESC LIMIT $%d OFFSET $%d", auditEventColumns, where, len(args)+1, len(args)+2)
+	rows, err := db.DB.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	events := []models.AuditEvent{}
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		events = append(events, event)
+	}
+	return events, total, rows.Err()
+}
+
+// StreamAuditEvents calls fn with every event matching the filter, oldest first
+func (db auditRepository) StreamAuditEvents(ctx context.Context, filter models.AuditFilter, fn func(models.AuditEvent) error) error {
+	where, args := auditFilterClause(filter)
+	rows, err := db.DB.QueryContext(ctx, "SELECT "+auditEventColumns+" FROM audit_events"+where+" ORDER BY id", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func scanAuditEvent(rows *sql.Rows) (models.AuditEvent, error) {
+	var event models.AuditEvent
+	var before, after []byte
+	err := rows.Scan(&event.Id, &event.ActorId, &event.Action, &event.TargetType, &event.TargetId, &before, &after,
+		&event.StatusCode, &event.IP, &event.RequestId, &event.CreatedAt)
+	if before != nil {
+		event.Before = before
+	}
+	if after != nil {
+		event.After = after
+	}
+	return event, err
+}
+
+func auditFilterClause(filter models.AuditFilter) (string, []any) {
+	var conditions []string
+	var args []any
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.ActorId != nil {
+		add("actor_id = $%d", *filter.ActorId)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.TargetType != "" {
+		add("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetId != "" {
+		add("target_id = $%d", filter.TargetId)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		// The day given is included
+		add("created_at < $%d", filter.To.AddDate(0, 0, 1))
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// nullableJSON stores empty JSON documents as NULL
+func nullableJSON(document []byte) any {
+	if len(document) == 0 {
+		return nil
+	}
+	return string(document)
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var auditEventRowColumns = []string{"id", "actor_id", "action", "target_type", "target_id", "before", "after", "status_code", "ip", "request_id", "created_at"}
+
+func TestAuditRepository_AddAuditEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	actor := 7
+	now := time.Now()
+	event := &models.AuditEvent{ActorId: &actor, Action: "user.block", TargetType: "user", TargetId: "3",
+		After: json.RawMessage(`{"blocked":true}`), StatusCode: 200, IP: "10.0.0.1", RequestId: "abc"}
+
+	mock.ExpectQuery(`INSERT INTO audit_events \(actor_id, action, target_type, target_id, before, after, status_code, ip, request_id\)`).
+		WithArgs(&actor, "user.block", "user", "3", nil, `{"blocked":true}`, 200, "10.0.0.1", "abc").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(42), now))
+
+	err = NewAuditRepository(db).AddAuditEvent(context.Background(), event)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), event.Id)
+	assert.Equal(t, now, event.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditRepository_GetAuditEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	actor := 7
+	to := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	filter := models.AuditFilter{ActorId: &actor, TargetType: "user", To: &to}
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM audit_events WHERE actor_id = \$1 AND target_type = \$2 AND created_at < \$3`).
+		WithArgs(7, "user", to.AddDate(0, 0, 1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(31))
+	mock.ExpectQuery(`SELECT id, actor_id, .+ FROM audit_events WHERE actor_id = \$1 AND target_type = \$2 AND created_at < \$3 ORDER BY id DESC LIMIT \$4 OFFSET \$5`).
+		WithArgs(7, "user", to.AddDate(0, 0, 1), 10, 20).
+		WillReturnRows(sqlmock.NewRows(auditEventRowColumns).
+			AddRow(int64(3), 7, "user.block", "user", "3", nil, []byte(`{"blocked":true}`), 200, "10.0.0.1", "abc", now))
+
+	events, total, err := NewAuditRepository(db).GetAuditEvents(context.Background(), filter, 10, 20)
+
+	require.NoError(t, err)
+	assert.Equal(t, 31, total)
+	require.Len(t, events, 1)
+	assert.Equal(t, 7, *events[0].ActorId)
+	assert.Nil(t, events[0].Before)
+	assert.JSONEq(t, `{"blocked":true}`, string(events[0].After))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditRepository_StreamAuditEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM audit_events WHERE action = \$1 ORDER BY id$`).
+		WithArgs("user.delete").
+		WillReturnRows(sqlmock.NewRows(auditEventRowColumns).
+			AddRow(int64(1), nil, "user.delete", "user", "3", nil, nil, 204, "", "", now).
+			AddRow(int64(2), 7, "user.delete", "user", "4", nil, nil, 204, "", "", now))
+
+	var ids []int64
+	err = NewAuditRepository(db).StreamAuditEvents(context.Background(), models.AuditFilter{Action: "user.delete"}, func(event models.AuditEvent) error {
+		ids = append(ids, event.Id)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
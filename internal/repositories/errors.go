@@ -1,5 +1,33 @@
 package repositories
 
-import "errors"
+import (
+	"database/sql"
+	"errors"
+)
 
-var ErrNotFound = errors.New("not found")
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrVersionMismatch = errors.New("resource was modified by another request")
+	ErrEmailTaken      = errors.New("email already in use")
+	// ErrProposalReviewed is returned when reviewing a rule proposal that was already approved or rejected
+	ErrProposalReviewed = errors.New("proposal was already reviewed")
+	// ErrNotificationTypeExists is returned when registering a type of notification with the name of another one
+	ErrNotificationTypeExists = errors.New("notification type already exists")
+	// ErrSecretJob is returned when requeuing a dead job whose payload was dropped because it had secrets
+	ErrSecretJob = errors.New("job had secrets and can't be run again")
+)
+
+// uniqueViolation is the postgres error code for a unique constraint violation
+const uniqueViolation = "23505"
+
+// checkVersionedWrite turns a conditional write that matched no rows into ErrVersionMismatch
+func checkVersionedWrite(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrVersionMismatch
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+)
+
+// InboxRepository keeps the notifications sent to each user. Every method but AddInboxEntry is
+// scoped to the user, so entries of other users are reported as ErrNotFound.
+type InboxRepository interface {
+	// AddInboxEntry saves the entry, filling its id and creation time, along with the jobs the outbox builds for it
+	AddInboxEntry(ctx context.Context, entry *models.InboxEntry, outbox models.Outbox) error
+	GetInboxEntry(ctx context.Context, userId int, id int) (*models.InboxEntry, error)
+	// GetInbox returns a page of the entries of the user, newest first, how many there are and how many are unread
+	GetInbox(ctx context.Context, userId int, unreadOnly bool, limit int, offset int) ([]models.InboxEntry, int, int, error)
+	// GetInboxEntriesAfter returns up to limit entries of the user added after the one with the id, oldest first
+	GetInboxEntriesAfter(ctx context.Context, userId int, afterId int, limit int) ([]models.InboxEntry, error)
+	// MarkInboxEntryRead marks the entry as read, keeping the time it was first read
+	MarkInboxEntryRead(ctx context.Context, userId int, id int) (*models.InboxEntry, error)
+	// MarkInboxRead marks every unread entry of the user as read, returning how many there were
+	MarkInboxRead(ctx context.Context, userId int) (int, error)
+	DeleteInboxEntry(ctx context.Context, userId int, id int) error
+}
+
+type inboxRepository struct {
+	DB *sql.DB
+}
+
+func NewInboxRepository(db *sql.DB) *inboxRepository {
+	return &inboxRepository{DB: db}
+}
+
+const inboxColumns = "id, user_id, type, title, text, read_at, created_at"
+
+func scanInboxEntry(row rowScanner) (models.InboxEntry, error) {
+	var entry models.InboxEntry
+	var readAt sql.NullTime
+	err := row.Scan(&entry.Id, &entry.UserId, &entry.Type, &entry.Title, &entry.Text, &readAt, &entry.CreatedAt)
+	if readAt.Valid {
+		entry.ReadAt = &readAt.Time
+	}
+	return entry, err
+}
+
+func (db inboxRepository) AddInboxEntry(ctx context.Context, entry *models.InboxEntry, outbox models.Outbox) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO inbox (user_id, type, title, text)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`,
+		entry.UserId, entry.Type, entry.Title, entry.Text,
+	).Scan(&entry.Id, &entry.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := enqueueOutbox(ctx, tx, outbox, entry.Id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (db inboxRepository) GetInboxEntry(ctx context.Context, userId int, id int) (*models.InboxEntry, error) {
+	row := db.DB.QueryRowContext(ctx, "SELECT "+inboxColumns+" FROM inbox WHERE id = $1 AND user_id = $2", id, userId)
+	entry, err := scanInboxEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (db inboxRepository) GetInbox(ctx context.Context, userId int, unreadOnly bool, limit int, offset int) ([]models.InboxEntry, int, int, error) {
+	var total, unread int
+	err := db.DB.QueryRowContext(ctx,
+		"SELECT COUNT(*), COUNT(*) FILTER (WHERE read_at IS NULL) FROM inbox WHERE user_id = $1", userId,
+	).Scan(&total, &unread)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if unreadOnly {
+		total = unread
+	}
+
+	rows, err := db.DB.QueryContext(ctx, `
+		SELECT `+inboxColumns+` FROM inbox
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY id DESC LIMIT $3 OFFSET $4`,
+		userId, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer rows.Close()
+
+	entries := []models.InboxEntry{}
+	for rows.Next() {
+		entry, err := scanInboxEntry(rows)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, total, unread, rows.Err()
+}
+
+func (db inboxRepository) GetInboxEntriesAfter(ctx context.Context, userId int, afterId int, limit int) ([]models.InboxEntry, error) {
+	rows, err := db.DB.QueryContext(ctx,
+		"SELECT "+inboxColumns+" FROM inbox WHERE user_id = $1 AND id > $2 ORDER BY id LIMIT $3", userId, afterId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.InboxEntry{}
+	for rows.Next() {
+		entry, err := scanInboxEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (db inboxRepository) MarkInboxEntryRead(ctx context.Context, userId int, id int) (*models.InboxEntry, error) {
+	row := db.DB.QueryRowContext(ctx, `
+		UPDATE inbox SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2
+		RETURNING `+inboxColumns, id, userId)
+	entry, err := scanInboxEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (db inboxRepository) MarkInboxRead(ctx context.Context, userId int) (int, error) {
+	result, err := db.DB.ExecContext(ctx, "UPDATE inbox SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL", userId)
+	if err != nil {
+		return 0, err
+	}
+	marked, err := result.RowsAffected()
+	return int(marked), err
+}
+
+func (db inboxRepository) DeleteInboxEntry(ctx context.Context, userId int, id int) error {
+	result, err := db.DB.ExecContext(ctx, "DELETE FROM inbox WHERE id = $1 AND user_id = $2", id, userId)
+	if err != nil {
+		return err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
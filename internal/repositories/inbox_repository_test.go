@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var inboxRowColumns = []string{"id", "user_id", "type", "title", "text", "read_at", "created_at"}
+
+func TestInboxRepository_AddInboxEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO inbox \(user_id, type, title, text\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id, created_at`).
+		WithArgs(3, "exam_notification", "Exam", "Tomorrow").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(12, now))
+	mock.ExpectExec(`INSERT INTO jobs`).
+		WithArgs(`{"push"}`, `{"{\"inbox_id\":12}"}`, `{f}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	entry := models.InboxEntry{UserId: 3, Type: "exam_notification", Title: "Exam", Text: "Tomorrow"}
+	err = NewInboxRepository(db).AddInboxEntry(context.Background(), &entry, func(id int) ([]models.Job, error) {
+		return []models.Job{{Type: models.JobPush, Payload: json.RawMessage(`{"inbox_id":12}`)}}, nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 12, entry.Id)
+	assert.Equal(t, now, entry.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInboxRepository_GetInbox(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT COUNT\(\*\), COUNT\(\*\) FILTER \(WHERE read_at IS NULL\) FROM inbox WHERE user_id = \$1`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"total", "unread"}).AddRow(31, 2))
+	mock.ExpectQuery(`SELECT id, user_id, .+ FROM inbox WHERE user_id = \$1 AND \(NOT \$2 OR read_at IS NULL\) ORDER BY id DESC LIMIT \$3 OFFSET \$4`).
+		WithArgs(3, false, 10, 20).
+		WillReturnRows(sqlmock.NewRows(inboxRowColumns).
+			AddRow(12, 3, "exam_notification", "Exam", "Tomorrow", nil, now).
+			AddRow(11, 3, "social_notification", "Hi", "Hello", now, now))
+
+	entries, total, unread, err := NewInboxRepository(db).GetInbox(context.Background(), 3, false, 10, 20)
+
+	require.NoError(t, err)
+	assert.Equal(t, 31, total)
+	assert.Equal(t, 2, unread)
+	require.Len(t, entries, 2)
+	assert.Nil(t, entries[0].ReadAt)
+	require.NotNil(t, entries[1].ReadAt)
+	assert.Equal(t, now, *entries[1].ReadAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInboxRepository_GetInbox_UnreadOnly(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\)`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"total", "unread"}).AddRow(31, 2))
+	mock.ExpectQuery(`FROM inbox WHERE`).
+		WithArgs(3, true, 50, 0).
+		WillReturnRows(sqlmock.NewRows(inboxRowColumns))
+
+	entries, total, unread, err := NewInboxRepository(db).GetInbox(context.Background(), 3, true, 50, 0)
+
+	require.NoError(t, err)
+	// The total is of the entries listed, only the unread ones
+	assert.Equal(t, 2, total)
+	assert.Equal(t, 2, unread)
+	assert.Empty(t, entries)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInboxRepository_GetInboxEntriesAfter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT id, user_id, .+ FROM inbox WHERE user_id = \$1 AND id > \$2 ORDER BY id LIMIT \$3`).
+		WithArgs(3, 10, 100).
+		WillReturnRows(sqlmock.NewRows(inboxRowColumns).
+			AddRow(11, 3, "exam_notification", "Exam", "Tomorrow", nil, now).
+			AddRow(12, 3, "social_notification", "Hi", "Hello", nil, now))
+
+	entries, err := NewInboxRepository(db).GetInboxEntriesAfter(context.Background(), 3, 10, 100)
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, 11, entries[0].Id)
+	assert.Equal(t, 12, entries[1].Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInboxRepository_MarkInboxEntryRead(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`UPDATE inbox SET read_at = COALESCE\(read_at, NOW\(\)\) WHERE id = \$1 AND user_id = \$2 RETURNING id, user_id`).
+		WithArgs(12, 3).
+		WillReturnRows(sqlmock.NewRows(inboxRowColumns).AddRow(12, 3, "exam_notification", "Exam", "Tomorrow", now, now))
+	mock.ExpectQuery(`UPDATE inbox SET read_at`).
+		WithArgs(13, 3).
+		WillReturnRows(sqlmock.NewRows(inboxRowColumns))
+
+	repo := NewInboxRepository(db)
+	entry, err := repo.MarkInboxEntryRead(context.Background(), 3, 12)
+	require.NoError(t, err)
+	assert.Equal(t, now, *entry.ReadAt)
+
+	// Entries of other users are not found
+	_, err = repo.MarkInboxEntryRead(context.Background(), 3, 13)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInboxRepository_MarkInboxRead(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE inbox SET read_at = NOW\(\) WHERE user_id = \$1 AND read_at IS NULL`).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	marked, err := NewInboxRepository(db).MarkInboxRead(context.Background(), 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, marked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInboxRepository_DeleteInboxEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`DELETE FROM inbox WHERE id = \$1 AND user_id = \$2`).
+		WithArgs(12, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM inbox`).
+		WithArgs(13, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := NewInboxRepository(db)
+	assert.NoError(t, repo.DeleteInboxEntry(context.Background(), 3, 12))
+	assert.ErrorIs(t, repo.DeleteInboxEntry(context.Background(), 3, 13), ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/lib/pq"
+)
+
+// JobRepository is the outbox of the background jobs. Jobs that fail too many times
+// are moved to a dead-letter table, where admins can look at them and requeue them.
+type JobRepository interface {
+	Enqueue(ctx context.Context, jobs ...models.Job) error
+	// ClaimJobs takes up to limit due jobs, oldest first, counting an attempt and hiding them from other
+	// workers for the lease. Jobs locked by another worker are skipped. If the worker doesn't complete,
+	// retry or bury a job before the lease ends, because it stopped, the job is claimed again.
+	ClaimJobs(ctx context.Context, limit int, lease time.Duration) ([]models.Job, error)
+	// RenewJobLease hides a claimed job from other workers for another lease. It returns false if the
+	// lease already ran out and another worker claimed the job, which counted another attempt.
+	RenewJobLease(ctx context.Context, id int, attempts int, lease time.Duration) (bool, error)
+	CompleteJob(ctx context.Context, id int) error
+	// RetryJob releases a claimed job to run again at runAt
+	RetryJob(ctx context.Context, id int, runAt time.Time, lastError string) error
+	// BuryJob moves a claimed job to the dead-letter table, without the payload if it's secret
+	BuryJob(ctx context.Context, id int, lastError string) error
+	// GetDeadJobs returns the last limit jobs moved to the dead-letter table, newest first
+	GetDeadJobs(ctx context.Context, limit int) ([]models.DeadJob, error)
+	// RequeueDeadJob moves the job back from the dead-letter table to run now, with no attempts.
+	// Secret jobs can't be requeued, their payload is gone.
+	RequeueDeadJob(ctx context.Context, id int) (*models.Job, error)
+}
+
+type jobRepository struct {
+	DB *sql.DB
+}
+
+func NewJobRepository(db *sql.DB) *jobRepository {
+	return &jobRepository{DB: db}
+}
+
+const jobColumns = "id, type, payload, secret, attempts, run_at, last_error, created_at"
+
+func scanJob(row rowScanner, extra ...any) (models.Job, error) {
+	var job models.Job
+	dest := []any{&job.Id, &job.Type, &job.Payload, &job.Secret, &job.Attempts, &job.RunAt, &job.LastError, &job.CreatedAt}
+	err := row.Scan(append(dest, extra...)...)
+	return job, err
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// enqueueJobs writes the jobs with db, which is a transaction when they go along with a change
+func enqueueJobs(ctx context.Context, db execer, jobs []models.Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+	types := make([]string, len(jobs))
+	payloads := make([]string, len(jobs))
+	secrets := make([]bool, len(jobs))
+	for i, job := range jobs {
+		types[i] = job.Type
+		payloads[i] = string(job.Payload)
+		secrets[i] = job.Secret
+	}
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO jobs (type, payload, secret)
+		SELECT * FROM unnest($1::text[], $2::jsonb[], $3::bool[])`, pq.Array(types), pq.Array(payloads), pq.Array(secrets))
+	return err
+}
+
+// enqueueOutbox writes the jobs the outbox builds for the new row with the id, if there is an outbox
+func enqueueOutbox(ctx context.Context, tx *sql.Tx, outbox models.Outbox, id int) error {
+	if outbox == nil {
+		return nil
+	}
+	jobs, err := outbox(id)
+	if err != nil {
+		return err
+	}
+	return enqueueJobs(ctx, tx, jobs)
+}
+
+func (db jobRepository) Enqueue(ctx context.Context, jobs ...models.Job) error {
+	return enqueueJobs(ctx, db.DB, jobs)
+}
+
+func (db jobRepository) ClaimJobs(ctx context.Context, limit int, lease time.Duration) ([]models.Job, error) {
+	rows, err := db.DB.QueryContext(ctx, `
+		UPDATE jobs SET attempts = attempts + 1, run_at = NOW() + $2 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE run_at <= NOW()
+			ORDER BY run_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING `+jobColumns, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []models.Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func (db jobRepository) RenewJobLease(ctx context.Context, id int, attempts int, lease time.Duration) (bool, error) {
+	result, err := db.DB.ExecContext(ctx,
+		"UPDATE jobs SET run_at = NOW() + $3 * INTERVAL '1 millisecond' WHERE id = $1 AND attempts = $2",
+		id, attempts, lease.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	renewed, err := result.RowsAffected()
+	return renewed > 0, err
+}
+
+func (db jobRepository) CompleteJob(ctx context.Context, id int) error {
+	_, err := db.DB.ExecContext(ctx, "DELETE FROM jobs WHERE id = $1", id)
+	return err
+}
+
+func (db jobRepository) RetryJob(ctx context.Context, id int, runAt time.Time, lastError string) error {
+	_, err := db.DB.ExecContext(ctx, "UPDATE jobs SET run_at = $2, last_error = $3 WHERE id = $1", id, runAt, lastError)
+	return err
+}
+
+func (db jobRepository) BuryJob(ctx context.Context, id int, lastError string) error {
+	_, err := db.DB.ExecContext(ctx, `
+		WITH dead AS (DELETE FROM jobs WHERE id = $1 RETURNING id, type, payload, secret, attempts, created_at)
+		INSERT INTO dead_jobs (id, type, payload, secret, attempts, last_error, created_at)
+		SELECT id, type, CASE WHEN secret THEN '{}' ELSE payload END, secret, attempts, $2, created_at FROM dead`, id, lastError)
+	return err
+}
+
+func (db jobRepository) GetDeadJobs(ctx context.Context, limit int) ([]models.DeadJob, error) {
+	rows, err := db.DB.QueryContext(ctx, `
+		SELECT id, type, payload, secret, attempts, failed_at, last_error, created_at, failed_at
+		FROM dead_jobs
+		ORDER BY failed_at DESC, id DESC
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []models.DeadJob{}
+	for rows.Next() {
+		var dead models.DeadJob
+		dead.Job, err = scanJob(rows, &dead.FailedAt)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, dead)
+	}
+	return jobs, rows.Err()
+}
+
+func (db jobRepository) RequeueDeadJob(ctx context.Context, id int) (*models.Job, error) {
+	job, err := scanJob(db.DB.QueryRowContext(ctx, `
+		WITH dead AS (DELETE FROM dead_jobs WHERE id = $1 AND NOT secret RETURNING id, type, payload, last_error, created_at)
+		INSERT INTO jobs (id, type, payload, last_error, created_at)
+		SELECT id, type, payload, last_error, created_at FROM dead
+		RETURNING `+jobColumns, id))
+	if err == sql.ErrNoRows {
+		var secret bool
+		err := db.DB.QueryRowContext(ctx, "SELECT secret FROM dead_jobs WHERE id = $1", id).Scan(&secret)
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nil, ErrSecretJob
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var jobRowColumns = []string{"id", "type", "payload", "secret", "attempts", "run_at", "last_error", "created_at"}
+
+func TestJobRepository_Enqueue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewJobRepository(db)
+	mock.ExpectExec(`INSERT INTO jobs \(type, payload, secret\) SELECT \* FROM unnest\(\$1::text\[\], \$2::jsonb\[\], \$3::bool\[\]\)`).
+		WithArgs(`{"email","push"}`, `{"{\"subject\":\"Hola\"}","{\"token\":\"abc\"}"}`, `{t,f}`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = repo.Enqueue(context.Background(),
+		models.Job{Type: models.JobEmail, Payload: json.RawMessage(`{"subject":"Hola"}`), Secret: true},
+		models.Job{Type: models.JobPush, Payload: json.RawMessage(`{"token":"abc"}`)})
+	assert.NoError(t, err)
+
+	// Nothing to write without jobs
+	assert.NoError(t, repo.Enqueue(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJobRepository_ClaimJobs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewJobRepository(db)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`UPDATE jobs SET attempts = attempts \+ 1, run_at = NOW\(\) \+ \$2 \* INTERVAL '1 millisecond' WHERE id IN \( SELECT id FROM jobs WHERE run_at <= NOW\(\) ORDER BY run_at, id LIMIT \$1 FOR UPDATE SKIP LOCKED\) RETURNING id, type`).
+		WithArgs(10, int64(60000)).
+		WillReturnRows(sqlmock.NewRows(jobRowColumns).
+			AddRow(1, models.JobEmail, []byte(`{}`), false, 1, now.Add(time.Minute), "", now).
+			AddRow(2, models.JobPush, []byte(`{}`), false, 3, now.Add(time.Minute), "timeout", now))
+
+	jobs, err := repo.ClaimJobs(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, models.JobEmail, jobs[0].Type)
+	assert.Equal(t, 3, jobs[1].Attempts)
+	assert.Equal(t, "timeout", jobs[1].LastError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJobRepository_RetryJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewJobRepository(db)
+	runAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`UPDATE jobs SET run_at = \$2, last_error = \$3 WHERE id = \$1`).
+		WithArgs(4, runAt, "smtp down").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.RetryJob(context.Background(), 4, runAt, "smtp down"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJobRepository_RenewJobLease(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewJobRepository(db)
+	mock.ExpectExec(`UPDATE jobs SET run_at = NOW\(\) \+ \$3 \* INTERVAL '1 millisecond' WHERE id = \$1 AND attempts = \$2`).
+		WithArgs(4, 2, int64(300000)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Claimed again by another worker, which counted one more attempt
+	mock.ExpectExec(`UPDATE jobs SET run_at`).
+		WithArgs(4, 2, int64(300000)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	renewed, err := repo.RenewJobLease(context.Background(), 4, 2, 5*time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, renewed)
+	renewed, err = repo.RenewJobLease(context.Background(), 4, 2, 5*time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, renewed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJobRepository_CompleteJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewJobRepository(db)
+	mock.ExpectExec(`DELETE FROM jobs WHERE id = \$1`).WithArgs(4).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.CompleteJob(context.Background(), 4))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJobRepository_BuryJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewJobRepository(db)
+	// Secret payloads aren't kept
+	mock.ExpectExec(`WITH dead AS \(DELETE FROM jobs WHERE id = \$1 RETURNING .*\) INSERT INTO dead_jobs .* CASE WHEN secret THEN '\{\}' ELSE payload END`).
+		WithArgs(4, "smtp down").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.BuryJob(context.Background(), 4, "smtp down"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJobRepository_GetDeadJobs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewJobRepository(db)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT .* FROM dead_jobs ORDER BY failed_at DESC, id DESC LIMIT \$1`).
+		WithArgs(50).
+		WillReturnRows(sqlmock.NewRows(append(jobRowColumns, "failed_at")).
+			AddRow(4, models.JobEmail, []byte(`{}`), true, 8, now, "smtp down", now.Add(-time.Hour), now))
+
+	jobs, err := repo.GetDeadJobs(context.Background(), 50)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, 4, jobs[0].Id)
+	assert.Equal(t, "smtp down", jobs[0].LastError)
+	assert.Equal(t, now, jobs[0].FailedAt)
+	assert.True(t, jobs[0].Secret)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJobRepository_RequeueDeadJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewJobRepository(db)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`WITH dead AS \(DELETE FROM dead_jobs WHERE id = \$1 AND NOT secret RETURNING .*\) INSERT INTO jobs \(id, type, payload, last_error, created_at\)`).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows(jobRowColumns).AddRow(4, models.JobEmail, []byte(`{}`), false, 0, now, "smtp down", now))
+	mock.ExpectQuery(`WITH dead AS`).WithArgs(5).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`SELECT secret FROM dead_jobs WHERE id = \$1`).WithArgs(5).WillReturnError(sql.ErrNoRows)
+	// A secret job is still there, without its payload
+	mock.ExpectQuery(`WITH dead AS`).WithArgs(6).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`SELECT secret FROM dead_jobs WHERE id = \$1`).WithArgs(6).
+		WillReturnRows(sqlmock.NewRows([]string{"secret"}).AddRow(true))
+
+	job, err := repo.RequeueDeadJob(context.Background(), 4)
+	require.NoError(t, err)
+	assert.Equal(t, 0, job.Attempts)
+
+	_, err = repo.RequeueDeadJob(context.Background(), 5)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = repo.RequeueDeadJob(context.Background(), 6)
+	assert.ErrorIs(t, err, ErrSecretJob)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -6,19 +6,20 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
 	mock "github.com/stretchr/testify/mock"
 )
 
-// NewMockBlockedUserRepository creates a new instance of MockBlockedUserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// NewMockAuditChainRepository creates a new instance of MockAuditChainRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockBlockedUserRepository(t interface {
+func NewMockAuditChainRepository(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockBlockedUserRepository {
-	mock := &MockBlockedUserRepository{}
+}) *MockAuditChainRepository {
+	mock := &MockAuditChainRepository{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -26,178 +27,277 @@ func NewMockBlockedUserRepository(t interface {
 	return mock
 }
 
-// MockBlockedUserRepository is an autogenerated mock type for the BlockedUserRepository type
-type MockBlockedUserRepository struct {
+// MockAuditChainRepository is an autogenerated mock type for the AuditChainRepository type
+type MockAuditChainRepository struct {
 	mock.Mock
 }
 
-type MockBlockedUserRepository_Expecter struct {
+type MockAuditChainRepository_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *MockBlockedUserRepository) EXPECT() *MockBlockedUserRepository_Expecter {
-	return &MockBlockedUserRepository_Expecter{mock: &_m.Mock}
+func (_m *MockAuditChainRepository) EXPECT() *MockAuditChainRepository_Expecter {
+	return &MockAuditChainRepository_Expecter{mock: &_m.Mock}
 }
 
-// BlockUser provides a mock function for the type MockBlockedUserRepository
-func (_mock *MockBlockedUserRepository) BlockUser(ctx context.Context, blockedUserID int, reason string, blockerID *int, blockedUntil *time.Time) error {
-	ret := _mock.Called(ctx, blockedUserID, reason, blockerID, blockedUntil)
+// AddCheckpoint provides a mock function for the type MockAuditChainRepository
+func (_mock *MockAuditChainRepository) AddCheckpoint(ctx context.Context, checkpoint *models.AuditCheckpoint) error {
+	ret := _mock.Called(ctx, checkpoint)
 
 	if len(ret) == 0 {
-		panic("no return value specified for BlockUser")
+		panic("no return value specified for AddCheckpoint")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, *int, *time.Time) error); ok {
-		r0 = returnFunc(ctx, blockedUserID, reason, blockerID, blockedUntil)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.AuditCheckpoint) error); ok {
+		r0 = returnFunc(ctx, checkpoint)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockBlockedUserRepository_BlockUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BlockUser'
-type MockBlockedUserRepository_BlockUser_Call struct {
+// MockAuditChainRepository_AddCheckpoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddCheckpoint'
+type MockAuditChainRepository_AddCheckpoint_Call struct {
 	*mock.Call
 }
 
-// BlockUser is a helper method to define mock.On call
+// AddCheckpoint is a helper method to define mock.On call
 //   - ctx
-//   - blockedUserID
-//   - reason
-//   - blockerID
-//   - blockedUntil
-func (_e *MockBlockedUserRepository_Expecter) BlockUser(ctx interface{}, blockedUserID interface{}, reason interface{}, blockerID interface{}, blockedUntil interface{}) *MockBlockedUserRepository_BlockUser_Call {
-	return &MockBlockedUserRepository_BlockUser_Call{Call: _e.mock.On("BlockUser", ctx, blockedUserID, reason, blockerID, blockedUntil)}
+//   - checkpoint
+func (_e *MockAuditChainRepository_Expecter) AddCheckpoint(ctx interface{}, checkpoint interface{}) *MockAuditChainRepository_AddCheckpoint_Call {
+	return &MockAuditChainRepository_AddCheckpoint_Call{Call: _e.mock.On("AddCheckpoint", ctx, checkpoint)}
 }
 
-func (_c *MockBlockedUserRepository_BlockUser_Call) Run(run func(ctx context.Context, blockedUserID int, reason string, blockerID *int, blockedUntil *time.Time)) *MockBlockedUserRepository_BlockUser_Call {
+func (_c *MockAuditChainRepository_AddCheckpoint_Call) Run(run func(ctx context.Context, checkpoint *models.AuditCheckpoint)) *MockAuditChainRepository_AddCheckpoint_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(*int), args[4].(*time.Time))
+		run(args[0].(context.Context), args[1].(*models.AuditCheckpoint))
 	})
 	return _c
 }
 
-func (_c *MockBlockedUserRepository_BlockUser_Call) Return(err error) *MockBlockedUserRepository_BlockUser_Call {
+func (_c *MockAuditChainRepository_AddCheckpoint_Call) Return(err error) *MockAuditChainRepository_AddCheckpoint_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockBlockedUserRepository_BlockUser_Call) RunAndReturn(run func(ctx context.Context, blockedUserID int, reason string, blockerID *int, blockedUntil *time.Time) error) *MockBlockedUserRepository_BlockUser_Call {
+func (_c *MockAuditChainRepository_AddCheckpoint_Call) RunAndReturn(run func(ctx context.Context, checkpoint *models.AuditCheckpoint) error) *MockAuditChainRepository_AddCheckpoint_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetBlocksByUserId provides a mock function for the type MockBlockedUserRepository
-func (_mock *MockBlockedUserRepository) GetBlocksByUserId(ctx context.Context, userID int) ([]models.BlockedUser, error) {
-	ret := _mock.Called(ctx, userID)
+// GetChainHead provides a mock function for the type MockAuditChainRepository
+func (_mock *MockAuditChainRepository) GetChainHead(ctx context.Context) (*models.AuditChainHead, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetBlocksByUserId")
+		panic("no return value specified for GetChainHead")
 	}
 
-	var r0 []models.BlockedUser
+	var r0 *models.AuditChainHead
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]models.BlockedUser, error)); ok {
-		return returnFunc(ctx, userID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*models.AuditChainHead, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []models.BlockedUser); ok {
-		r0 = returnFunc(ctx, userID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *models.AuditChainHead); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.BlockedUser)
+			r0 = ret.Get(0).(*models.AuditChainHead)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAuditChainRepository_GetChainHead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetChainHead'
+type MockAuditChainRepository_GetChainHead_Call struct {
+	*mock.Call
+}
+
+// GetChainHead is a helper method to define mock.On call
+//   - ctx
+func (_e *MockAuditChainRepository_Expecter) GetChainHead(ctx interface{}) *MockAuditChainRepository_GetChainHead_Call {
+	return &MockAuditChainRepository_GetChainHead_Call{Call: _e.mock.On("GetChainHead", ctx)}
+}
+
+func (_c *MockAuditChainRepository_GetChainHead_Call) Run(run func(ctx context.Context)) *MockAuditChainRepository_GetChainHead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockAuditChainRepository_GetChainHead_Call) Return(auditChainHead *models.AuditChainHead, err error) *MockAuditChainRepository_GetChainHead_Call {
+	_c.Call.Return(auditChainHead, err)
+	return _c
+}
+
+func (_c *MockAuditChainRepository_GetChainHead_Call) RunAndReturn(run func(ctx context.Context) (*models.AuditChainHead, error)) *MockAuditChainRepository_GetChainHead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCheckpoints provides a mock function for the type MockAuditChainRepository
+func (_mock *MockAuditChainRepository) GetCheckpoints(ctx context.Context, limit int) ([]models.AuditCheckpoint, error) {
+	ret := _mock.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCheckpoints")
+	}
+
+	var r0 []models.AuditCheckpoint
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]models.AuditCheckpoint, error)); ok {
+		return returnFunc(ctx, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []models.AuditCheckpoint); ok {
+		r0 = returnFunc(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AuditCheckpoint)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = returnFunc(ctx, userID)
+		r1 = returnFunc(ctx, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockBlockedUserRepository_GetBlocksByUserId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBlocksByUserId'
-type MockBlockedUserRepository_GetBlocksByUserId_Call struct {
+// MockAuditChainRepository_GetCheckpoints_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCheckpoints'
+type MockAuditChainRepository_GetCheckpoints_Call struct {
 	*mock.Call
 }
 
-// GetBlocksByUserId is a helper method to define mock.On call
+// GetCheckpoints is a helper method to define mock.On call
 //   - ctx
-//   - userID
-func (_e *MockBlockedUserRepository_Expecter) GetBlocksByUserId(ctx interface{}, userID interface{}) *MockBlockedUserRepository_GetBlocksByUserId_Call {
-	return &MockBlockedUserRepository_GetBlocksByUserId_Call{Call: _e.mock.On("GetBlocksByUserId", ctx, userID)}
+//   - limit
+func (_e *MockAuditChainRepository_Expecter) GetCheckpoints(ctx interface{}, limit interface{}) *MockAuditChainRepository_GetCheckpoints_Call {
+	return &MockAuditChainRepository_GetCheckpoints_Call{Call: _e.mock.On("GetCheckpoints", ctx, limit)}
 }
 
-func (_c *MockBlockedUserRepository_GetBlocksByUserId_Call) Run(run func(ctx context.Context, userID int)) *MockBlockedUserRepository_GetBlocksByUserId_Call {
+func (_c *MockAuditChainRepository_GetCheckpoints_Call) Run(run func(ctx context.Context, limit int)) *MockAuditChainRepository_GetCheckpoints_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(int))
 	})
 	return _c
 }
 
-func (_c *MockBlockedUserRepository_GetBlocksByUserId_Call) Return(blockedUsers []models.BlockedUser, err error) *MockBlockedUserRepository_GetBlocksByUserId_Call {
-	_c.Call.Return(blockedUsers, err)
+func (_c *MockAuditChainRepository_GetCheckpoints_Call) Return(auditCheckpoints []models.AuditCheckpoint, err error) *MockAuditChainRepository_GetCheckpoints_Call {
+	_c.Call.Return(auditCheckpoints, err)
 	return _c
 }
 
-func (_c *MockBlockedUserRepository_GetBlocksByUserId_Call) RunAndReturn(run func(ctx context.Context, userID int) ([]models.BlockedUser, error)) *MockBlockedUserRepository_GetBlocksByUserId_Call {
+func (_c *MockAuditChainRepository_GetCheckpoints_Call) RunAndReturn(run func(ctx context.Context, limit int) ([]models.AuditCheckpoint, error)) *MockAuditChainRepository_GetCheckpoints_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UnblockUser provides a mock function for the type MockBlockedUserRepository
-func (_mock *MockBlockedUserRepository) UnblockUser(ctx context.Context, blockedUserID int) error {
-	ret := _mock.Called(ctx, blockedUserID)
+// StreamChain provides a mock function for the type MockAuditChainRepository
+func (_mock *MockAuditChainRepository) StreamChain(ctx context.Context, fn func(models.Audit) error) error {
+	ret := _mock.Called(ctx, fn)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UnblockUser")
+		panic("no return value specified for StreamChain")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
-		r0 = returnFunc(ctx, blockedUserID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, func(models.Audit) error) error); ok {
+		r0 = returnFunc(ctx, fn)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockBlockedUserRepository_UnblockUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnblockUser'
-type MockBlockedUserRepository_UnblockUser_Call struct {
+// MockAuditChainRepository_StreamChain_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StreamChain'
+type MockAuditChainRepository_StreamChain_Call struct {
 	*mock.Call
 }
 
-// UnblockUser is a helper method to define mock.On call
+// StreamChain is a helper method to define mock.On call
 //   - ctx
-//   - blockedUserID
-func (_e *MockBlockedUserRepository_Expecter) UnblockUser(ctx interface{}, blockedUserID interface{}) *MockBlockedUserRepository_UnblockUser_Call {
-	return &MockBlockedUserRepository_UnblockUser_Call{Call: _e.mock.On("UnblockUser", ctx, blockedUserID)}
+//   - fn
+func (_e *MockAuditChainRepository_Expecter) StreamChain(ctx interface{}, fn interface{}) *MockAuditChainRepository_StreamChain_Call {
+	return &MockAuditChainRepository_StreamChain_Call{Call: _e.mock.On("StreamChain", ctx, fn)}
 }
 
-func (_c *MockBlockedUserRepository_UnblockUser_Call) Run(run func(ctx context.Context, blockedUserID int)) *MockBlockedUserRepository_UnblockUser_Call {
+func (_c *MockAuditChainRepository_StreamChain_Call) Run(run func(ctx context.Context, fn func(models.Audit) error)) *MockAuditChainRepository_StreamChain_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context), args[1].(func(models.Audit) error))
 	})
 	return _c
 }
 
-func (_c *MockBlockedUserRepository_UnblockUser_Call) Return(err error) *MockBlockedUserRepository_UnblockUser_Call {
+func (_c *MockAuditChainRepository_StreamChain_Call) Return(err error) *MockAuditChainRepository_StreamChain_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockBlockedUserRepository_UnblockUser_Call) RunAndReturn(run func(ctx context.Context, blockedUserID int) error) *MockBlockedUserRepository_UnblockUser_Call {
+func (_c *MockAuditChainRepository_StreamChain_Call) RunAndReturn(run func(ctx context.Context, fn func(models.Audit) error) error) *MockAuditChainRepository_StreamChain_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockChatRepository creates a new instance of MockChatRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// StreamCheckpoints provides a mock function for the type MockAuditChainRepository
+func (_mock *MockAuditChainRepository) StreamCheckpoints(ctx context.Context, fn func(models.AuditCheckpoint) error) error {
+	ret := _mock.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamCheckpoints")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, func(models.AuditCheckpoint) error) error); ok {
+		r0 = returnFunc(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAuditChainRepository_StreamCheckpoints_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StreamCheckpoints'
+type MockAuditChainRepository_StreamCheckpoints_Call struct {
+	*mock.Call
+}
+
+// StreamCheckpoints is a helper method to define mock.On call
+//   - ctx
+//   - fn
+func (_e *MockAuditChainRepository_Expecter) StreamCheckpoints(ctx interface{}, fn interface{}) *MockAuditChainRepository_StreamCheckpoints_Call {
+	return &MockAuditChainRepository_StreamCheckpoints_Call{Call: _e.mock.On("StreamCheckpoints", ctx, fn)}
+}
+
+func (_c *MockAuditChainRepository_StreamCheckpoints_Call) Run(run func(ctx context.Context, fn func(models.AuditCheckpoint) error)) *MockAuditChainRepository_StreamCheckpoints_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(func(models.AuditCheckpoint) error))
+	})
+	return _c
+}
+
+func (_c *MockAuditChainRepository_StreamCheckpoints_Call) Return(err error) *MockAuditChainRepository_StreamCheckpoints_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAuditChainRepository_StreamCheckpoints_Call) RunAndReturn(run func(ctx context.Context, fn func(models.AuditCheckpoint) error) error) *MockAuditChainRepository_StreamCheckpoints_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAuditRepository creates a new instance of MockAuditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockChatRepository(t interface {
+func NewMockAuditRepository(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockChatRepository {
-	mock := &MockChatRepository{}
+}) *MockAuditRepository {
+	mock := &MockAuditRepository{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -205,285 +305,3788 @@ func NewMockChatRepository(t interface {
 	return mock
 }
 
-// MockChatRepository is an autogenerated mock type for the ChatRepository type
-type MockChatRepository struct {
+// MockAuditRepository is an autogenerated mock type for the AuditRepository type
+type MockAuditRepository struct {
 	mock.Mock
 }
 
-type MockChatRepository_Expecter struct {
+type MockAuditRepository_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *MockChatRepository) EXPECT() *MockChatRepository_Expecter {
-	return &MockChatRepository_Expecter{mock: &_m.Mock}
+func (_m *MockAuditRepository) EXPECT() *MockAuditRepository_Expecter {
+	return &MockAuditRepository_Expecter{mock: &_m.Mock}
 }
 
-// GetLatestMessage provides a mock function for the type MockChatRepository
-func (_mock *MockChatRepository) GetLatestMessage(context1 context.Context, userId int) (*models.ChatMessage, error) {
-	ret := _mock.Called(context1, userId)
+// AddAuditEvent provides a mock function for the type MockAuditRepository
+func (_mock *MockAuditRepository) AddAuditEvent(ctx context.Context, event *models.AuditEvent) error {
+	ret := _mock.Called(ctx, event)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetLatestMessage")
+		panic("no return value specified for AddAuditEvent")
 	}
 
-	var r0 *models.ChatMessage
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.ChatMessage, error)); ok {
-		return returnFunc(context1, userId)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.AuditEvent) error); ok {
+		r0 = returnFunc(ctx, event)
+	} else {
+		r0 = ret.Error(0)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.ChatMessage); ok {
-		r0 = returnFunc(context1, userId)
+	return r0
+}
+
+// MockAuditRepository_AddAuditEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddAuditEvent'
+type MockAuditRepository_AddAuditEvent_Call struct {
+	*mock.Call
+}
+
+// AddAuditEvent is a helper method to define mock.On call
+//   - ctx
+//   - event
+func (_e *MockAuditRepository_Expecter) AddAuditEvent(ctx interface{}, event interface{}) *MockAuditRepository_AddAuditEvent_Call {
+	return &MockAuditRepository_AddAuditEvent_Call{Call: _e.mock.On("AddAuditEvent", ctx, event)}
+}
+
+func (_c *MockAuditRepository_AddAuditEvent_Call) Run(run func(ctx context.Context, event *models.AuditEvent)) *MockAuditRepository_AddAuditEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.AuditEvent))
+	})
+	return _c
+}
+
+func (_c *MockAuditRepository_AddAuditEvent_Call) Return(err error) *MockAuditRepository_AddAuditEvent_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAuditRepository_AddAuditEvent_Call) RunAndReturn(run func(ctx context.Context, event *models.AuditEvent) error) *MockAuditRepository_AddAuditEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAuditEvents provides a mock function for the type MockAuditRepository
+func (_mock *MockAuditRepository) GetAuditEvents(ctx context.Context, filter models.AuditFilter, limit int, offset int) ([]models.AuditEvent, int, error) {
+	ret := _mock.Called(ctx, filter, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuditEvents")
+	}
+
+	var r0 []models.AuditEvent
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.AuditFilter, int, int) ([]models.AuditEvent, int, error)); ok {
+		return returnFunc(ctx, filter, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.AuditFilter, int, int) []models.AuditEvent); ok {
+		r0 = returnFunc(ctx, filter, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.ChatMessage)
+			r0 = ret.Get(0).([]models.AuditEvent)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = returnFunc(context1, userId)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.AuditFilter, int, int) int); ok {
+		r1 = returnFunc(ctx, filter, limit, offset)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(int)
 	}
-	return r0, r1
+	if returnFunc, ok := ret.Get(2).(func(context.Context, models.AuditFilter, int, int) error); ok {
+		r2 = returnFunc(ctx, filter, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
 }
 
-// MockChatRepository_GetLatestMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLatestMessage'
-type MockChatRepository_GetLatestMessage_Call struct {
+// MockAuditRepository_GetAuditEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAuditEvents'
+type MockAuditRepository_GetAuditEvents_Call struct {
 	*mock.Call
 }
 
-// GetLatestMessage is a helper method to define mock.On call
-//   - context1
-//   - userId
-func (_e *MockChatRepository_Expecter) GetLatestMessage(context1 interface{}, userId interface{}) *MockChatRepository_GetLatestMessage_Call {
-	return &MockChatRepository_GetLatestMessage_Call{Call: _e.mock.On("GetLatestMessage", context1, userId)}
+// GetAuditEvents is a helper method to define mock.On call
+//   - ctx
+//   - filter
+//   - limit
+//   - offset
+func (_e *MockAuditRepository_Expecter) GetAuditEvents(ctx interface{}, filter interface{}, limit interface{}, offset interface{}) *MockAuditRepository_GetAuditEvents_Call {
+	return &MockAuditRepository_GetAuditEvents_Call{Call: _e.mock.On("GetAuditEvents", ctx, filter, limit, offset)}
 }
 
-func (_c *MockChatRepository_GetLatestMessage_Call) Run(run func(context1 context.Context, userId int)) *MockChatRepository_GetLatestMessage_Call {
+func (_c *MockAuditRepository_GetAuditEvents_Call) Run(run func(ctx context.Context, filter models.AuditFilter, limit int, offset int)) *MockAuditRepository_GetAuditEvents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context), args[1].(models.AuditFilter), args[2].(int), args[3].(int))
 	})
 	return _c
 }
 
-func (_c *MockChatRepository_GetLatestMessage_Call) Return(chatMessage *models.ChatMessage, err error) *MockChatRepository_GetLatestMessage_Call {
-	_c.Call.Return(chatMessage, err)
+func (_c *MockAuditRepository_GetAuditEvents_Call) Return(auditEvents []models.AuditEvent, n int, err error) *MockAuditRepository_GetAuditEvents_Call {
+	_c.Call.Return(auditEvents, n, err)
 	return _c
 }
 
-func (_c *MockChatRepository_GetLatestMessage_Call) RunAndReturn(run func(context1 context.Context, userId int) (*models.ChatMessage, error)) *MockChatRepository_GetLatestMessage_Call {
+func (_c *MockAuditRepository_GetAuditEvents_Call) RunAndReturn(run func(ctx context.Context, filter models.AuditFilter, limit int, offset int) ([]models.AuditEvent, int, error)) *MockAuditRepository_GetAuditEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StreamAuditEvents provides a mock function for the type MockAuditRepository
+func (_mock *MockAuditRepository) StreamAuditEvents(ctx context.Context, filter models.AuditFilter, fn func(models.AuditEvent) error) error {
+	ret := _mock.Called(ctx, filter, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamAuditEvents")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.AuditFilter, func(models.AuditEvent) error) error); ok {
+		r0 = returnFunc(ctx, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAuditRepository_StreamAuditEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StreamAuditEvents'
+type MockAuditRepository_StreamAuditEvents_Call struct {
+	*mock.Call
+}
+
+// StreamAuditEvents is a helper method to define mock.On call
+//   - ctx
+//   - filter
+//   - fn
+func (_e *MockAuditRepository_Expecter) StreamAuditEvents(ctx interface{}, filter interface{}, fn interface{}) *MockAuditRepository_StreamAuditEvents_Call {
+	return &MockAuditRepository_StreamAuditEvents_Call{Call: _e.mock.On("StreamAuditEvents", ctx, filter, fn)}
+}
+
+func (_c *MockAuditRepository_StreamAuditEvents_Call) Run(run func(ctx context.Context, filter models.AuditFilter, fn func(models.AuditEvent) error)) *MockAuditRepository_StreamAuditEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.AuditFilter), args[2].(func(models.AuditEvent) error))
+	})
+	return _c
+}
+
+func (_c *MockAuditRepository_StreamAuditEvents_Call) Return(err error) *MockAuditRepository_StreamAuditEvents_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAuditRepository_StreamAuditEvents_Call) RunAndReturn(run func(ctx context.Context, filter models.AuditFilter, fn func(models.AuditEvent) error) error) *MockAuditRepository_StreamAuditEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockBlockedUserRepository creates a new instance of MockBlockedUserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockBlockedUserRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockBlockedUserRepository {
+	mock := &MockBlockedUserRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockBlockedUserRepository is an autogenerated mock type for the BlockedUserRepository type
+type MockBlockedUserRepository struct {
+	mock.Mock
+}
+
+type MockBlockedUserRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockBlockedUserRepository) EXPECT() *MockBlockedUserRepository_Expecter {
+	return &MockBlockedUserRepository_Expecter{mock: &_m.Mock}
+}
+
+// BlockUser provides a mock function for the type MockBlockedUserRepository
+func (_mock *MockBlockedUserRepository) BlockUser(ctx context.Context, blockedUserID int, reason string, blockerID *int, blockedUntil *time.Time) error {
+	ret := _mock.Called(ctx, blockedUserID, reason, blockerID, blockedUntil)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BlockUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, *int, *time.Time) error); ok {
+		r0 = returnFunc(ctx, blockedUserID, reason, blockerID, blockedUntil)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockBlockedUserRepository_BlockUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BlockUser'
+type MockBlockedUserRepository_BlockUser_Call struct {
+	*mock.Call
+}
+
+// BlockUser is a helper method to define mock.On call
+//   - ctx
+//   - blockedUserID
+//   - reason
+//   - blockerID
+//   - blockedUntil
+func (_e *MockBlockedUserRepository_Expecter) BlockUser(ctx interface{}, blockedUserID interface{}, reason interface{}, blockerID interface{}, blockedUntil interface{}) *MockBlockedUserRepository_BlockUser_Call {
+	return &MockBlockedUserRepository_BlockUser_Call{Call: _e.mock.On("BlockUser", ctx, blockedUserID, reason, blockerID, blockedUntil)}
+}
+
+func (_c *MockBlockedUserRepository_BlockUser_Call) Run(run func(ctx context.Context, blockedUserID int, reason string, blockerID *int, blockedUntil *time.Time)) *MockBlockedUserRepository_BlockUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(*int), args[4].(*time.Time))
+	})
+	return _c
+}
+
+func (_c *MockBlockedUserRepository_BlockUser_Call) Return(err error) *MockBlockedUserRepository_BlockUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockBlockedUserRepository_BlockUser_Call) RunAndReturn(run func(ctx context.Context, blockedUserID int, reason string, blockerID *int, blockedUntil *time.Time) error) *MockBlockedUserRepository_BlockUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBlocksByUserId provides a mock function for the type MockBlockedUserRepository
+func (_mock *MockBlockedUserRepository) GetBlocksByUserId(ctx context.Context, userID int) ([]models.BlockedUser, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlocksByUserId")
+	}
+
+	var r0 []models.BlockedUser
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]models.BlockedUser, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []models.BlockedUser); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.BlockedUser)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockBlockedUserRepository_GetBlocksByUserId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBlocksByUserId'
+type MockBlockedUserRepository_GetBlocksByUserId_Call struct {
+	*mock.Call
+}
+
+// GetBlocksByUserId is a helper method to define mock.On call
+//   - ctx
+//   - userID
+func (_e *MockBlockedUserRepository_Expecter) GetBlocksByUserId(ctx interface{}, userID interface{}) *MockBlockedUserRepository_GetBlocksByUserId_Call {
+	return &MockBlockedUserRepository_GetBlocksByUserId_Call{Call: _e.mock.On("GetBlocksByUserId", ctx, userID)}
+}
+
+func (_c *MockBlockedUserRepository_GetBlocksByUserId_Call) Run(run func(ctx context.Context, userID int)) *MockBlockedUserRepository_GetBlocksByUserId_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockBlockedUserRepository_GetBlocksByUserId_Call) Return(blockedUsers []models.BlockedUser, err error) *MockBlockedUserRepository_GetBlocksByUserId_Call {
+	_c.Call.Return(blockedUsers, err)
+	return _c
+}
+
+func (_c *MockBlockedUserRepository_GetBlocksByUserId_Call) RunAndReturn(run func(ctx context.Context, userID int) ([]models.BlockedUser, error)) *MockBlockedUserRepository_GetBlocksByUserId_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnblockUser provides a mock function for the type MockBlockedUserRepository
+func (_mock *MockBlockedUserRepository) UnblockUser(ctx context.Context, blockedUserID int) error {
+	ret := _mock.Called(ctx, blockedUserID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnblockUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = returnFunc(ctx, blockedUserID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockBlockedUserRepository_UnblockUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnblockUser'
+type MockBlockedUserRepository_UnblockUser_Call struct {
+	*mock.Call
+}
+
+// UnblockUser is a helper method to define mock.On call
+//   - ctx
+//   - blockedUserID
+func (_e *MockBlockedUserRepository_Expecter) UnblockUser(ctx interface{}, blockedUserID interface{}) *MockBlockedUserRepository_UnblockUser_Call {
+	return &MockBlockedUserRepository_UnblockUser_Call{Call: _e.mock.On("UnblockUser", ctx, blockedUserID)}
+}
+
+func (_c *MockBlockedUserRepository_UnblockUser_Call) Run(run func(ctx context.Context, blockedUserID int)) *MockBlockedUserRepository_UnblockUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockBlockedUserRepository_UnblockUser_Call) Return(err error) *MockBlockedUserRepository_UnblockUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockBlockedUserRepository_UnblockUser_Call) RunAndReturn(run func(ctx context.Context, blockedUserID int) error) *MockBlockedUserRepository_UnblockUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockChatRepository creates a new instance of MockChatRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockChatRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockChatRepository {
+	mock := &MockChatRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockChatRepository is an autogenerated mock type for the ChatRepository type
+type MockChatRepository struct {
+	mock.Mock
+}
+
+type MockChatRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockChatRepository) EXPECT() *MockChatRepository_Expecter {
+	return &MockChatRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetLatestMessage provides a mock function for the type MockChatRepository
+func (_mock *MockChatRepository) GetLatestMessage(context1 context.Context, userId int) (*models.ChatMessage, error) {
+	ret := _mock.Called(context1, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestMessage")
+	}
+
+	var r0 *models.ChatMessage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.ChatMessage, error)); ok {
+		return returnFunc(context1, userId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.ChatMessage); ok {
+		r0 = returnFunc(context1, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ChatMessage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(context1, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockChatRepository_GetLatestMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLatestMessage'
+type MockChatRepository_GetLatestMessage_Call struct {
+	*mock.Call
+}
+
+// GetLatestMessage is a helper method to define mock.On call
+//   - context1
+//   - userId
+func (_e *MockChatRepository_Expecter) GetLatestMessage(context1 interface{}, userId interface{}) *MockChatRepository_GetLatestMessage_Call {
+	return &MockChatRepository_GetLatestMessage_Call{Call: _e.mock.On("GetLatestMessage", context1, userId)}
+}
+
+func (_c *MockChatRepository_GetLatestMessage_Call) Run(run func(context1 context.Context, userId int)) *MockChatRepository_GetLatestMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockChatRepository_GetLatestMessage_Call) Return(chatMessage *models.ChatMessage, err error) *MockChatRepository_GetLatestMessage_Call {
+	_c.Call.Return(chatMessage, err)
+	return _c
+}
+
+func (_c *MockChatRepository_GetLatestMessage_Call) RunAndReturn(run func(context1 context.Context, userId int) (*models.ChatMessage, error)) *MockChatRepository_GetLatestMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMessages provides a mock function for the type MockChatRepository
+func (_mock *MockChatRepository) GetMessages(ctx context.Context, id int, date string) ([]models.ChatMessage, error) {
+	ret := _mock.Called(ctx, id, date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessages")
+	}
+
+	var r0 []models.ChatMessage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) ([]models.ChatMessage, error)); ok {
+		return returnFunc(ctx, id, date)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) []models.ChatMessage); ok {
+		r0 = returnFunc(ctx, id, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ChatMessage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = returnFunc(ctx, id, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockChatRepository_GetMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessages'
+type MockChatRepository_GetMessages_Call struct {
+	*mock.Call
+}
+
+// GetMessages is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - date
+func (_e *MockChatRepository_Expecter) GetMessages(ctx interface{}, id interface{}, date interface{}) *MockChatRepository_GetMessages_Call {
+	return &MockChatRepository_GetMessages_Call{Call: _e.mock.On("GetMessages", ctx, id, date)}
+}
+
+func (_c *MockChatRepository_GetMessages_Call) Run(run func(ctx context.Context, id int, date string)) *MockChatRepository_GetMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockChatRepository_GetMessages_Call) Return(chatMessages []models.ChatMessage, err error) *MockChatRepository_GetMessages_Call {
+	_c.Call.Return(chatMessages, err)
+	return _c
+}
+
+func (_c *MockChatRepository_GetMessages_Call) RunAndReturn(run func(ctx context.Context, id int, date string) ([]models.ChatMessage, error)) *MockChatRepository_GetMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMessage provides a mock function for the type MockChatRepository
+func (_mock *MockChatRepository) NewMessage(ctx context.Context, id int, message string, sender string) error {
+	ret := _mock.Called(ctx, id, message, sender)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewMessage")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, string) error); ok {
+		r0 = returnFunc(ctx, id, message, sender)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockChatRepository_NewMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NewMessage'
+type MockChatRepository_NewMessage_Call struct {
+	*mock.Call
+}
+
+// NewMessage is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - message
+//   - sender
+func (_e *MockChatRepository_Expecter) NewMessage(ctx interface{}, id interface{}, message interface{}, sender interface{}) *MockChatRepository_NewMessage_Call {
+	return &MockChatRepository_NewMessage_Call{Call: _e.mock.On("NewMessage", ctx, id, message, sender)}
+}
+
+func (_c *MockChatRepository_NewMessage_Call) Run(run func(ctx context.Context, id int, message string, sender string)) *MockChatRepository_NewMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockChatRepository_NewMessage_Call) Return(err error) *MockChatRepository_NewMessage_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockChatRepository_NewMessage_Call) RunAndReturn(run func(ctx context.Context, id int, message string, sender string) error) *MockChatRepository_NewMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateMessageFeedback provides a mock function for the type MockChatRepository
+func (_mock *MockChatRepository) UpdateMessageFeedback(context1 context.Context, userId int, messageId int, feedback string) error {
+	ret := _mock.Called(context1, userId, messageId, feedback)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMessageFeedback")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) error); ok {
+		r0 = returnFunc(context1, userId, messageId, feedback)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockChatRepository_UpdateMessageFeedback_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMessageFeedback'
+type MockChatRepository_UpdateMessageFeedback_Call struct {
+	*mock.Call
+}
+
+// UpdateMessageFeedback is a helper method to define mock.On call
+//   - context1
+//   - userId
+//   - messageId
+//   - feedback
+func (_e *MockChatRepository_Expecter) UpdateMessageFeedback(context1 interface{}, userId interface{}, messageId interface{}, feedback interface{}) *MockChatRepository_UpdateMessageFeedback_Call {
+	return &MockChatRepository_UpdateMessageFeedback_Call{Call: _e.mock.On("UpdateMessageFeedback", context1, userId, messageId, feedback)}
+}
+
+func (_c *MockChatRepository_UpdateMessageFeedback_Call) Run(run func(context1 context.Context, userId int, messageId int, feedback string)) *MockChatRepository_UpdateMessageFeedback_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockChatRepository_UpdateMessageFeedback_Call) Return(err error) *MockChatRepository_UpdateMessageFeedback_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockChatRepository_UpdateMessageFeedback_Call) RunAndReturn(run func(context1 context.Context, userId int, messageId int, feedback string) error) *MockChatRepository_UpdateMessageFeedback_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateMessageRating provides a mock function for the type MockChatRepository
+func (_mock *MockChatRepository) UpdateMessageRating(context1 context.Context, userId int, messageId int, rating int) error {
+	ret := _mock.Called(context1, userId, messageId, rating)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMessageRating")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) error); ok {
+		r0 = returnFunc(context1, userId, messageId, rating)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockChatRepository_UpdateMessageRating_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMessageRating'
+type MockChatRepository_UpdateMessageRating_Call struct {
+	*mock.Call
+}
+
+// UpdateMessageRating is a helper method to define mock.On call
+//   - context1
+//   - userId
+//   - messageId
+//   - rating
+func (_e *MockChatRepository_Expecter) UpdateMessageRating(context1 interface{}, userId interface{}, messageId interface{}, rating interface{}) *MockChatRepository_UpdateMessageRating_Call {
+	return &MockChatRepository_UpdateMessageRating_Call{Call: _e.mock.On("UpdateMessageRating", context1, userId, messageId, rating)}
+}
+
+func (_c *MockChatRepository_UpdateMessageRating_Call) Run(run func(context1 context.Context, userId int, messageId int, rating int)) *MockChatRepository_UpdateMessageRating_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockChatRepository_UpdateMessageRating_Call) Return(err error) *MockChatRepository_UpdateMessageRating_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockChatRepository_UpdateMessageRating_Call) RunAndReturn(run func(context1 context.Context, userId int, messageId int, rating int) error) *MockChatRepository_UpdateMessageRating_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockInboxRepository creates a new instance of MockInboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockInboxRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockInboxRepository {
+	mock := &MockInboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockInboxRepository is an autogenerated mock type for the InboxRepository type
+type MockInboxRepository struct {
+	mock.Mock
+}
+
+type MockInboxRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockInboxRepository) EXPECT() *MockInboxRepository_Expecter {
+	return &MockInboxRepository_Expecter{mock: &_m.Mock}
+}
+
+// AddInboxEntry provides a mock function for the type MockInboxRepository
+func (_mock *MockInboxRepository) AddInboxEntry(ctx context.Context, entry *models.InboxEntry, outbox models.Outbox) error {
+	ret := _mock.Called(ctx, entry, outbox)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddInboxEntry")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.InboxEntry, models.Outbox) error); ok {
+		r0 = returnFunc(ctx, entry, outbox)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInboxRepository_AddInboxEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddInboxEntry'
+type MockInboxRepository_AddInboxEntry_Call struct {
+	*mock.Call
+}
+
+// AddInboxEntry is a helper method to define mock.On call
+//   - ctx
+//   - entry
+//   - outbox
+func (_e *MockInboxRepository_Expecter) AddInboxEntry(ctx interface{}, entry interface{}, outbox interface{}) *MockInboxRepository_AddInboxEntry_Call {
+	return &MockInboxRepository_AddInboxEntry_Call{Call: _e.mock.On("AddInboxEntry", ctx, entry, outbox)}
+}
+
+func (_c *MockInboxRepository_AddInboxEntry_Call) Run(run func(ctx context.Context, entry *models.InboxEntry, outbox models.Outbox)) *MockInboxRepository_AddInboxEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.InboxEntry), args[2].(models.Outbox))
+	})
+	return _c
+}
+
+func (_c *MockInboxRepository_AddInboxEntry_Call) Return(err error) *MockInboxRepository_AddInboxEntry_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInboxRepository_AddInboxEntry_Call) RunAndReturn(run func(ctx context.Context, entry *models.InboxEntry, outbox models.Outbox) error) *MockInboxRepository_AddInboxEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteInboxEntry provides a mock function for the type MockInboxRepository
+func (_mock *MockInboxRepository) DeleteInboxEntry(ctx context.Context, userId int, id int) error {
+	ret := _mock.Called(ctx, userId, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteInboxEntry")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = returnFunc(ctx, userId, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInboxRepository_DeleteInboxEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteInboxEntry'
+type MockInboxRepository_DeleteInboxEntry_Call struct {
+	*mock.Call
+}
+
+// DeleteInboxEntry is a helper method to define mock.On call
+//   - ctx
+//   - userId
+//   - id
+func (_e *MockInboxRepository_Expecter) DeleteInboxEntry(ctx interface{}, userId interface{}, id interface{}) *MockInboxRepository_DeleteInboxEntry_Call {
+	return &MockInboxRepository_DeleteInboxEntry_Call{Call: _e.mock.On("DeleteInboxEntry", ctx, userId, id)}
+}
+
+func (_c *MockInboxRepository_DeleteInboxEntry_Call) Run(run func(ctx context.Context, userId int, id int)) *MockInboxRepository_DeleteInboxEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockInboxRepository_DeleteInboxEntry_Call) Return(err error) *MockInboxRepository_DeleteInboxEntry_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInboxRepository_DeleteInboxEntry_Call) RunAndReturn(run func(ctx context.Context, userId int, id int) error) *MockInboxRepository_DeleteInboxEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetInbox provides a mock function for the type MockInboxRepository
+func (_mock *MockInboxRepository) GetInbox(ctx context.Context, userId int, unreadOnly bool, limit int, offset int) ([]models.InboxEntry, int, int, error) {
+	ret := _mock.Called(ctx, userId, unreadOnly, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInbox")
+	}
+
+	var r0 []models.InboxEntry
+	var r1 int
+	var r2 int
+	var r3 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, bool, int, int) ([]models.InboxEntry, int, int, error)); ok {
+		return returnFunc(ctx, userId, unreadOnly, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, bool, int, int) []models.InboxEntry); ok {
+		r0 = returnFunc(ctx, userId, unreadOnly, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.InboxEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, bool, int, int) int); ok {
+		r1 = returnFunc(ctx, userId, unreadOnly, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, int, bool, int, int) int); ok {
+		r2 = returnFunc(ctx, userId, unreadOnly, limit, offset)
+	} else {
+		r2 = ret.Get(2).(int)
+	}
+	if returnFunc, ok := ret.Get(3).(func(context.Context, int, bool, int, int) error); ok {
+		r3 = returnFunc(ctx, userId, unreadOnly, limit, offset)
+	} else {
+		r3 = ret.Error(3)
+	}
+	return r0, r1, r2, r3
+}
+
+// MockInboxRepository_GetInbox_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetInbox'
+type MockInboxRepository_GetInbox_Call struct {
+	*mock.Call
+}
+
+// GetInbox is a helper method to define mock.On call
+//   - ctx
+//   - userId
+//   - unreadOnly
+//   - limit
+//   - offset
+func (_e *MockInboxRepository_Expecter) GetInbox(ctx interface{}, userId interface{}, unreadOnly interface{}, limit interface{}, offset interface{}) *MockInboxRepository_GetInbox_Call {
+	return &MockInboxRepository_GetInbox_Call{Call: _e.mock.On("GetInbox", ctx, userId, unreadOnly, limit, offset)}
+}
+
+func (_c *MockInboxRepository_GetInbox_Call) Run(run func(ctx context.Context, userId int, unreadOnly bool, limit int, offset int)) *MockInboxRepository_GetInbox_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(bool), args[3].(int), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *MockInboxRepository_GetInbox_Call) Return(inboxEntrys []models.InboxEntry, n int, n1 int, err error) *MockInboxRepository_GetInbox_Call {
+	_c.Call.Return(inboxEntrys, n, n1, err)
+	return _c
+}
+
+func (_c *MockInboxRepository_GetInbox_Call) RunAndReturn(run func(ctx context.Context, userId int, unreadOnly bool, limit int, offset int) ([]models.InboxEntry, int, int, error)) *MockInboxRepository_GetInbox_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetInboxEntriesAfter provides a mock function for the type MockInboxRepository
+func (_mock *MockInboxRepository) GetInboxEntriesAfter(ctx context.Context, userId int, afterId int, limit int) ([]models.InboxEntry, error) {
+	ret := _mock.Called(ctx, userId, afterId, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInboxEntriesAfter")
+	}
+
+	var r0 []models.InboxEntry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) ([]models.InboxEntry, error)); ok {
+		return returnFunc(ctx, userId, afterId, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) []models.InboxEntry); ok {
+		r0 = returnFunc(ctx, userId, afterId, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.InboxEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = returnFunc(ctx, userId, afterId, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInboxRepository_GetInboxEntriesAfter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetInboxEntriesAfter'
+type MockInboxRepository_GetInboxEntriesAfter_Call struct {
+	*mock.Call
+}
+
+// GetInboxEntriesAfter is a helper method to define mock.On call
+//   - ctx
+//   - userId
+//   - afterId
+//   - limit
+func (_e *MockInboxRepository_Expecter) GetInboxEntriesAfter(ctx interface{}, userId interface{}, afterId interface{}, limit interface{}) *MockInboxRepository_GetInboxEntriesAfter_Call {
+	return &MockInboxRepository_GetInboxEntriesAfter_Call{Call: _e.mock.On("GetInboxEntriesAfter", ctx, userId, afterId, limit)}
+}
+
+func (_c *MockInboxRepository_GetInboxEntriesAfter_Call) Run(run func(ctx context.Context, userId int, afterId int, limit int)) *MockInboxRepository_GetInboxEntriesAfter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockInboxRepository_GetInboxEntriesAfter_Call) Return(inboxEntrys []models.InboxEntry, err error) *MockInboxRepository_GetInboxEntriesAfter_Call {
+	_c.Call.Return(inboxEntrys, err)
+	return _c
+}
+
+func (_c *MockInboxRepository_GetInboxEntriesAfter_Call) RunAndReturn(run func(ctx context.Context, userId int, afterId int, limit int) ([]models.InboxEntry, error)) *MockInboxRepository_GetInboxEntriesAfter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetInboxEntry provides a mock function for the type MockInboxRepository
+func (_mock *MockInboxRepository) GetInboxEntry(ctx context.Context, userId int, id int) (*models.InboxEntry, error) {
+	ret := _mock.Called(ctx, userId, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInboxEntry")
+	}
+
+	var r0 *models.InboxEntry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) (*models.InboxEntry, error)); ok {
+		return returnFunc(ctx, userId, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) *models.InboxEntry); ok {
+		r0 = returnFunc(ctx, userId, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.InboxEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = returnFunc(ctx, userId, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInboxRepository_GetInboxEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetInboxEntry'
+type MockInboxRepository_GetInboxEntry_Call struct {
+	*mock.Call
+}
+
+// GetInboxEntry is a helper method to define mock.On call
+//   - ctx
+//   - userId
+//   - id
+func (_e *MockInboxRepository_Expecter) GetInboxEntry(ctx interface{}, userId interface{}, id interface{}) *MockInboxRepository_GetInboxEntry_Call {
+	return &MockInboxRepository_GetInboxEntry_Call{Call: _e.mock.On("GetInboxEntry", ctx, userId, id)}
+}
+
+func (_c *MockInboxRepository_GetInboxEntry_Call) Run(run func(ctx context.Context, userId int, id int)) *MockInboxRepository_GetInboxEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockInboxRepository_GetInboxEntry_Call) Return(inboxEntry *models.InboxEntry, err error) *MockInboxRepository_GetInboxEntry_Call {
+	_c.Call.Return(inboxEntry, err)
+	return _c
+}
+
+func (_c *MockInboxRepository_GetInboxEntry_Call) RunAndReturn(run func(ctx context.Context, userId int, id int) (*models.InboxEntry, error)) *MockInboxRepository_GetInboxEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkInboxEntryRead provides a mock function for the type MockInboxRepository
+func (_mock *MockInboxRepository) MarkInboxEntryRead(ctx context.Context, userId int, id int) (*models.InboxEntry, error) {
+	ret := _mock.Called(ctx, userId, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkInboxEntryRead")
+	}
+
+	var r0 *models.InboxEntry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) (*models.InboxEntry, error)); ok {
+		return returnFunc(ctx, userId, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) *models.InboxEntry); ok {
+		r0 = returnFunc(ctx, userId, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.InboxEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = returnFunc(ctx, userId, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInboxRepository_MarkInboxEntryRead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkInboxEntryRead'
+type MockInboxRepository_MarkInboxEntryRead_Call struct {
+	*mock.Call
+}
+
+// MarkInboxEntryRead is a helper method to define mock.On call
+//   - ctx
+//   - userId
+//   - id
+func (_e *MockInboxRepository_Expecter) MarkInboxEntryRead(ctx interface{}, userId interface{}, id interface{}) *MockInboxRepository_MarkInboxEntryRead_Call {
+	return &MockInboxRepository_MarkInboxEntryRead_Call{Call: _e.mock.On("MarkInboxEntryRead", ctx, userId, id)}
+}
+
+func (_c *MockInboxRepository_MarkInboxEntryRead_Call) Run(run func(ctx context.Context, userId int, id int)) *MockInboxRepository_MarkInboxEntryRead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockInboxRepository_MarkInboxEntryRead_Call) Return(inboxEntry *models.InboxEntry, err error) *MockInboxRepository_MarkInboxEntryRead_Call {
+	_c.Call.Return(inboxEntry, err)
+	return _c
+}
+
+func (_c *MockInboxRepository_MarkInboxEntryRead_Call) RunAndReturn(run func(ctx context.Context, userId int, id int) (*models.InboxEntry, error)) *MockInboxRepository_MarkInboxEntryRead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkInboxRead provides a mock function for the type MockInboxRepository
+func (_mock *MockInboxRepository) MarkInboxRead(ctx context.Context, userId int) (int, error) {
+	ret := _mock.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkInboxRead")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return returnFunc(ctx, userId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = returnFunc(ctx, userId)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInboxRepository_MarkInboxRead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkInboxRead'
+type MockInboxRepository_MarkInboxRead_Call struct {
+	*mock.Call
+}
+
+// MarkInboxRead is a helper method to define mock.On call
+//   - ctx
+//   - userId
+func (_e *MockInboxRepository_Expecter) MarkInboxRead(ctx interface{}, userId interface{}) *MockInboxRepository_MarkInboxRead_Call {
+	return &MockInboxRepository_MarkInboxRead_Call{Call: _e.mock.On("MarkInboxRead", ctx, userId)}
+}
+
+func (_c *MockInboxRepository_MarkInboxRead_Call) Run(run func(ctx context.Context, userId int)) *MockInboxRepository_MarkInboxRead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockInboxRepository_MarkInboxRead_Call) Return(n int, err error) *MockInboxRepository_MarkInboxRead_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockInboxRepository_MarkInboxRead_Call) RunAndReturn(run func(ctx context.Context, userId int) (int, error)) *MockInboxRepository_MarkInboxRead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockJobRepository creates a new instance of MockJobRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockJobRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockJobRepository {
+	mock := &MockJobRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockJobRepository is an autogenerated mock type for the JobRepository type
+type MockJobRepository struct {
+	mock.Mock
+}
+
+type MockJobRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockJobRepository) EXPECT() *MockJobRepository_Expecter {
+	return &MockJobRepository_Expecter{mock: &_m.Mock}
+}
+
+// BuryJob provides a mock function for the type MockJobRepository
+func (_mock *MockJobRepository) BuryJob(ctx context.Context, id int, lastError string) error {
+	ret := _mock.Called(ctx, id, lastError)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BuryJob")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, id, lastError)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockJobRepository_BuryJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BuryJob'
+type MockJobRepository_BuryJob_Call struct {
+	*mock.Call
+}
+
+// BuryJob is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - lastError
+func (_e *MockJobRepository_Expecter) BuryJob(ctx interface{}, id interface{}, lastError interface{}) *MockJobRepository_BuryJob_Call {
+	return &MockJobRepository_BuryJob_Call{Call: _e.mock.On("BuryJob", ctx, id, lastError)}
+}
+
+func (_c *MockJobRepository_BuryJob_Call) Run(run func(ctx context.Context, id int, lastError string)) *MockJobRepository_BuryJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockJobRepository_BuryJob_Call) Return(err error) *MockJobRepository_BuryJob_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockJobRepository_BuryJob_Call) RunAndReturn(run func(ctx context.Context, id int, lastError string) error) *MockJobRepository_BuryJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ClaimJobs provides a mock function for the type MockJobRepository
+func (_mock *MockJobRepository) ClaimJobs(ctx context.Context, limit int, lease time.Duration) ([]models.Job, error) {
+	ret := _mock.Called(ctx, limit, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimJobs")
+	}
+
+	var r0 []models.Job
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Duration) ([]models.Job, error)); ok {
+		return returnFunc(ctx, limit, lease)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Duration) []models.Job); ok {
+		r0 = returnFunc(ctx, limit, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Job)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, time.Duration) error); ok {
+		r1 = returnFunc(ctx, limit, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockJobRepository_ClaimJobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimJobs'
+type MockJobRepository_ClaimJobs_Call struct {
+	*mock.Call
+}
+
+// ClaimJobs is a helper method to define mock.On call
+//   - ctx
+//   - limit
+//   - lease
+func (_e *MockJobRepository_Expecter) ClaimJobs(ctx interface{}, limit interface{}, lease interface{}) *MockJobRepository_ClaimJobs_Call {
+	return &MockJobRepository_ClaimJobs_Call{Call: _e.mock.On("ClaimJobs", ctx, limit, lease)}
+}
+
+func (_c *MockJobRepository_ClaimJobs_Call) Run(run func(ctx context.Context, limit int, lease time.Duration)) *MockJobRepository_ClaimJobs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockJobRepository_ClaimJobs_Call) Return(jobs []models.Job, err error) *MockJobRepository_ClaimJobs_Call {
+	_c.Call.Return(jobs, err)
+	return _c
+}
+
+func (_c *MockJobRepository_ClaimJobs_Call) RunAndReturn(run func(ctx context.Context, limit int, lease time.Duration) ([]models.Job, error)) *MockJobRepository_ClaimJobs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CompleteJob provides a mock function for the type MockJobRepository
+func (_mock *MockJobRepository) CompleteJob(ctx context.Context, id int) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteJob")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockJobRepository_CompleteJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompleteJob'
+type MockJobRepository_CompleteJob_Call struct {
+	*mock.Call
+}
+
+// CompleteJob is a helper method to define mock.On call
+//   - ctx
+//   - id
+func (_e *MockJobRepository_Expecter) CompleteJob(ctx interface{}, id interface{}) *MockJobRepository_CompleteJob_Call {
+	return &MockJobRepository_CompleteJob_Call{Call: _e.mock.On("CompleteJob", ctx, id)}
+}
+
+func (_c *MockJobRepository_CompleteJob_Call) Run(run func(ctx context.Context, id int)) *MockJobRepository_CompleteJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockJobRepository_CompleteJob_Call) Return(err error) *MockJobRepository_CompleteJob_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockJobRepository_CompleteJob_Call) RunAndReturn(run func(ctx context.Context, id int) error) *MockJobRepository_CompleteJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Enqueue provides a mock function for the type MockJobRepository
+func (_mock *MockJobRepository) Enqueue(ctx context.Context, jobs ...models.Job) error {
+	var tmpRet mock.Arguments
+	if len(jobs) > 0 {
+		tmpRet = _mock.Called(ctx, jobs)
+	} else {
+		tmpRet = _mock.Called(ctx)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enqueue")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, ...models.Job) error); ok {
+		r0 = returnFunc(ctx, jobs...)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockJobRepository_Enqueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Enqueue'
+type MockJobRepository_Enqueue_Call struct {
+	*mock.Call
+}
+
+// Enqueue is a helper method to define mock.On call
+//   - ctx
+//   - jobs
+func (_e *MockJobRepository_Expecter) Enqueue(ctx interface{}, jobs ...interface{}) *MockJobRepository_Enqueue_Call {
+	return &MockJobRepository_Enqueue_Call{Call: _e.mock.On("Enqueue",
+		append([]interface{}{ctx}, jobs...)...)}
+}
+
+func (_c *MockJobRepository_Enqueue_Call) Run(run func(ctx context.Context, jobs ...models.Job)) *MockJobRepository_Enqueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]models.Job, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(models.Job)
+			}
+		}
+		run(args[0].(context.Context), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockJobRepository_Enqueue_Call) Return(err error) *MockJobRepository_Enqueue_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockJobRepository_Enqueue_Call) RunAndReturn(run func(ctx context.Context, jobs ...models.Job) error) *MockJobRepository_Enqueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDeadJobs provides a mock function for the type MockJobRepository
+func (_mock *MockJobRepository) GetDeadJobs(ctx context.Context, limit int) ([]models.DeadJob, error) {
+	ret := _mock.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadJobs")
+	}
+
+	var r0 []models.DeadJob
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]models.DeadJob, error)); ok {
+		return returnFunc(ctx, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []models.DeadJob); ok {
+		r0 = returnFunc(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.DeadJob)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockJobRepository_GetDeadJobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeadJobs'
+type MockJobRepository_GetDeadJobs_Call struct {
+	*mock.Call
+}
+
+// GetDeadJobs is a helper method to define mock.On call
+//   - ctx
+//   - limit
+func (_e *MockJobRepository_Expecter) GetDeadJobs(ctx interface{}, limit interface{}) *MockJobRepository_GetDeadJobs_Call {
+	return &MockJobRepository_GetDeadJobs_Call{Call: _e.mock.On("GetDeadJobs", ctx, limit)}
+}
+
+func (_c *MockJobRepository_GetDeadJobs_Call) Run(run func(ctx context.Context, limit int)) *MockJobRepository_GetDeadJobs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockJobRepository_GetDeadJobs_Call) Return(deadJobs []models.DeadJob, err error) *MockJobRepository_GetDeadJobs_Call {
+	_c.Call.Return(deadJobs, err)
+	return _c
+}
+
+func (_c *MockJobRepository_GetDeadJobs_Call) RunAndReturn(run func(ctx context.Context, limit int) ([]models.DeadJob, error)) *MockJobRepository_GetDeadJobs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RenewJobLease provides a mock function for the type MockJobRepository
+func (_mock *MockJobRepository) RenewJobLease(ctx context.Context, id int, attempts int, lease time.Duration) (bool, error) {
+	ret := _mock.Called(ctx, id, attempts, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenewJobLease")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, time.Duration) (bool, error)); ok {
+		return returnFunc(ctx, id, attempts, lease)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, time.Duration) bool); ok {
+		r0 = returnFunc(ctx, id, attempts, lease)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, time.Duration) error); ok {
+		r1 = returnFunc(ctx, id, attempts, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockJobRepository_RenewJobLease_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RenewJobLease'
+type MockJobRepository_RenewJobLease_Call struct {
+	*mock.Call
+}
+
+// RenewJobLease is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - attempts
+//   - lease
+func (_e *MockJobRepository_Expecter) RenewJobLease(ctx interface{}, id interface{}, attempts interface{}, lease interface{}) *MockJobRepository_RenewJobLease_Call {
+	return &MockJobRepository_RenewJobLease_Call{Call: _e.mock.On("RenewJobLease", ctx, id, attempts, lease)}
+}
+
+func (_c *MockJobRepository_RenewJobLease_Call) Run(run func(ctx context.Context, id int, attempts int, lease time.Duration)) *MockJobRepository_RenewJobLease_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockJobRepository_RenewJobLease_Call) Return(b bool, err error) *MockJobRepository_RenewJobLease_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockJobRepository_RenewJobLease_Call) RunAndReturn(run func(ctx context.Context, id int, attempts int, lease time.Duration) (bool, error)) *MockJobRepository_RenewJobLease_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RequeueDeadJob provides a mock function for the type MockJobRepository
+func (_mock *MockJobRepository) RequeueDeadJob(ctx context.Context, id int) (*models.Job, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequeueDeadJob")
+	}
+
+	var r0 *models.Job
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.Job, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.Job); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Job)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockJobRepository_RequeueDeadJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequeueDeadJob'
+type MockJobRepository_RequeueDeadJob_Call struct {
+	*mock.Call
+}
+
+// RequeueDeadJob is a helper method to define mock.On call
+//   - ctx
+//   - id
+func (_e *MockJobRepository_Expecter) RequeueDeadJob(ctx interface{}, id interface{}) *MockJobRepository_RequeueDeadJob_Call {
+	return &MockJobRepository_RequeueDeadJob_Call{Call: _e.mock.On("RequeueDeadJob", ctx, id)}
+}
+
+func (_c *MockJobRepository_RequeueDeadJob_Call) Run(run func(ctx context.Context, id int)) *MockJobRepository_RequeueDeadJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockJobRepository_RequeueDeadJob_Call) Return(job *models.Job, err error) *MockJobRepository_RequeueDeadJob_Call {
+	_c.Call.Return(job, err)
+	return _c
+}
+
+func (_c *MockJobRepository_RequeueDeadJob_Call) RunAndReturn(run func(ctx context.Context, id int) (*models.Job, error)) *MockJobRepository_RequeueDeadJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RetryJob provides a mock function for the type MockJobRepository
+func (_mock *MockJobRepository) RetryJob(ctx context.Context, id int, runAt time.Time, lastError string) error {
+	ret := _mock.Called(ctx, id, runAt, lastError)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RetryJob")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Time, string) error); ok {
+		r0 = returnFunc(ctx, id, runAt, lastError)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockJobRepository_RetryJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RetryJob'
+type MockJobRepository_RetryJob_Call struct {
+	*mock.Call
+}
+
+// RetryJob is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - runAt
+//   - lastError
+func (_e *MockJobRepository_Expecter) RetryJob(ctx interface{}, id interface{}, runAt interface{}, lastError interface{}) *MockJobRepository_RetryJob_Call {
+	return &MockJobRepository_RetryJob_Call{Call: _e.mock.On("RetryJob", ctx, id, runAt, lastError)}
+}
+
+func (_c *MockJobRepository_RetryJob_Call) Run(run func(ctx context.Context, id int, runAt time.Time, lastError string)) *MockJobRepository_RetryJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockJobRepository_RetryJob_Call) Return(err error) *MockJobRepository_RetryJob_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockJobRepository_RetryJob_Call) RunAndReturn(run func(ctx context.Context, id int, runAt time.Time, lastError string) error) *MockJobRepository_RetryJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Newmockexecer creates a new instance of mockexecer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func Newmockexecer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockexecer {
+	mock := &mockexecer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockexecer is an autogenerated mock type for the execer type
+type mockexecer struct {
+	mock.Mock
+}
+
+type mockexecer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockexecer) EXPECT() *mockexecer_Expecter {
+	return &mockexecer_Expecter{mock: &_m.Mock}
+}
+
+// ExecContext provides a mock function for the type mockexecer
+func (_mock *mockexecer) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	var tmpRet mock.Arguments
+	if len(args) > 0 {
+		tmpRet = _mock.Called(ctx, query, args)
+	} else {
+		tmpRet = _mock.Called(ctx, query)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExecContext")
+	}
+
+	var r0 sql.Result
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []any) (sql.Result, error)); ok {
+		return returnFunc(ctx, query, args)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...any) sql.Result); ok {
+		r0 = returnFunc(ctx, query, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(sql.Result)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, ...any) error); ok {
+		r1 = returnFunc(ctx, query, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockexecer_ExecContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExecContext'
+type mockexecer_ExecContext_Call struct {
+	*mock.Call
+}
+
+// ExecContext is a helper method to define mock.On call
+//   - ctx
+//   - query
+//   - args
+func (_e *mockexecer_Expecter) ExecContext(ctx interface{}, query interface{}, args ...interface{}) *mockexecer_ExecContext_Call {
+	return &mockexecer_ExecContext_Call{Call: _e.mock.On("ExecContext",
+		append([]interface{}{ctx, query}, args...)...)}
+}
+
+func (_c *mockexecer_ExecContext_Call) Run(run func(ctx context.Context, query string, args ...any)) *mockexecer_ExecContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]any, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(any)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *mockexecer_ExecContext_Call) Return(result sql.Result, err error) *mockexecer_ExecContext_Call {
+	_c.Call.Return(result, err)
+	return _c
+}
+
+func (_c *mockexecer_ExecContext_Call) RunAndReturn(run func(ctx context.Context, query string, args ...any) (sql.Result, error)) *mockexecer_ExecContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockLoginAttemptRepository creates a new instance of MockLoginAttemptRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLoginAttemptRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLoginAttemptRepository {
+	mock := &MockLoginAttemptRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockLoginAttemptRepository is an autogenerated mock type for the LoginAttemptRepository type
+type MockLoginAttemptRepository struct {
+	mock.Mock
+}
+
+type MockLoginAttemptRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockLoginAttemptRepository) EXPECT() *MockLoginAttemptRepository_Expecter {
+	return &MockLoginAttemptRepository_Expecter{mock: &_m.Mock}
+}
+
+// AddLoginAttempt provides a mock function for the type MockLoginAttemptRepository
+func (_mock *MockLoginAttemptRepository) AddLoginAttempt(ctx context.Context, userID int, ipAddress string, userAgent string, successful bool) error {
+	ret := _mock.Called(ctx, userID, ipAddress, userAgent, successful)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddLoginAttempt")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, string, bool) error); ok {
+		r0 = returnFunc(ctx, userID, ipAddress, userAgent, successful)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLoginAttemptRepository_AddLoginAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddLoginAttempt'
+type MockLoginAttemptRepository_AddLoginAttempt_Call struct {
+	*mock.Call
+}
+
+// AddLoginAttempt is a helper method to define mock.On call
+//   - ctx
+//   - userID
+//   - ipAddress
+//   - userAgent
+//   - successful
+func (_e *MockLoginAttemptRepository_Expecter) AddLoginAttempt(ctx interface{}, userID interface{}, ipAddress interface{}, userAgent interface{}, successful interface{}) *MockLoginAttemptRepository_AddLoginAttempt_Call {
+	return &MockLoginAttemptRepository_AddLoginAttempt_Call{Call: _e.mock.On("AddLoginAttempt", ctx, userID, ipAddress, userAgent, successful)}
+}
+
+func (_c *MockLoginAttemptRepository_AddLoginAttempt_Call) Run(run func(ctx context.Context, userID int, ipAddress string, userAgent string, successful bool)) *MockLoginAttemptRepository_AddLoginAttempt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string), args[4].(bool))
+	})
+	return _c
+}
+
+func (_c *MockLoginAttemptRepository_AddLoginAttempt_Call) Return(err error) *MockLoginAttemptRepository_AddLoginAttempt_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLoginAttemptRepository_AddLoginAttempt_Call) RunAndReturn(run func(ctx context.Context, userID int, ipAddress string, userAgent string, successful bool) error) *MockLoginAttemptRepository_AddLoginAttempt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// BadLoginAttemptsInLast10Minutes provides a mock function for the type MockLoginAttemptRepository
+func (_mock *MockLoginAttemptRepository) BadLoginAttemptsInLast10Minutes(ctx context.Context, userID int) (int, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BadLoginAttemptsInLast10Minutes")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BadLoginAttemptsInLast10Minutes'
+type MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call struct {
+	*mock.Call
+}
+
+// BadLoginAttemptsInLast10Minutes is a helper method to define mock.On call
+//   - ctx
+//   - userID
+func (_e *MockLoginAttemptRepository_Expecter) BadLoginAttemptsInLast10Minutes(ctx interface{}, userID interface{}) *MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call {
+	return &MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call{Call: _e.mock.On("BadLoginAttemptsInLast10Minutes", ctx, userID)}
+}
+
+func (_c *MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call) Run(run func(ctx context.Context, userID int)) *MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call) Return(n int, err error) *MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call) RunAndReturn(run func(ctx context.Context, userID int) (int, error)) *MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLoginsByUserId provides a mock function for the type MockLoginAttemptRepository
+func (_mock *MockLoginAttemptRepository) GetLoginsByUserId(ctx context.Context, userID int, limit int, offset int) ([]*models.LoginAttempt, error) {
+	ret := _mock.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoginsByUserId")
+	}
+
+	var r0 []*models.LoginAttempt
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) ([]*models.LoginAttempt, error)); ok {
+		return returnFunc(ctx, userID, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) []*models.LoginAttempt); ok {
+		r0 = returnFunc(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.LoginAttempt)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = returnFunc(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLoginAttemptRepository_GetLoginsByUserId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLoginsByUserId'
+type MockLoginAttemptRepository_GetLoginsByUserId_Call struct {
+	*mock.Call
+}
+
+// GetLoginsByUserId is a helper method to define mock.On call
+//   - ctx
+//   - userID
+//   - limit
+//   - offset
+func (_e *MockLoginAttemptRepository_Expecter) GetLoginsByUserId(ctx interface{}, userID interface{}, limit interface{}, offset interface{}) *MockLoginAttemptRepository_GetLoginsByUserId_Call {
+	return &MockLoginAttemptRepository_GetLoginsByUserId_Call{Call: _e.mock.On("GetLoginsByUserId", ctx, userID, limit, offset)}
+}
+
+func (_c *MockLoginAttemptRepository_GetLoginsByUserId_Call) Run(run func(ctx context.Context, userID int, limit int, offset int)) *MockLoginAttemptRepository_GetLoginsByUserId_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockLoginAttemptRepository_GetLoginsByUserId_Call) Return(loginAttempts []*models.LoginAttempt, err error) *MockLoginAttemptRepository_GetLoginsByUserId_Call {
+	_c.Call.Return(loginAttempts, err)
+	return _c
+}
+
+func (_c *MockLoginAttemptRepository_GetLoginsByUserId_Call) RunAndReturn(run func(ctx context.Context, userID int, limit int, offset int) ([]*models.LoginAttempt, error)) *MockLoginAttemptRepository_GetLoginsByUserId_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRuleAcceptanceRepository creates a new instance of MockRuleAcceptanceRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRuleAcceptanceRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRuleAcceptanceRepository {
+	mock := &MockRuleAcceptanceRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockRuleAcceptanceRepository is an autogenerated mock type for the RuleAcceptanceRepository type
+type MockRuleAcceptanceRepository struct {
+	mock.Mock
+}
+
+type MockRuleAcceptanceRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRuleAcceptanceRepository) EXPECT() *MockRuleAcceptanceRepository_Expecter {
+	return &MockRuleAcceptanceRepository_Expecter{mock: &_m.Mock}
+}
+
+// AddAcceptance provides a mock function for the type MockRuleAcceptanceRepository
+func (_mock *MockRuleAcceptanceRepository) AddAcceptance(ctx context.Context, acceptance models.RuleAcceptance) error {
+	ret := _mock.Called(ctx, acceptance)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddAcceptance")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleAcceptance) error); ok {
+		r0 = returnFunc(ctx, acceptance)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRuleAcceptanceRepository_AddAcceptance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddAcceptance'
+type MockRuleAcceptanceRepository_AddAcceptance_Call struct {
+	*mock.Call
+}
+
+// AddAcceptance is a helper method to define mock.On call
+//   - ctx
+//   - acceptance
+func (_e *MockRuleAcceptanceRepository_Expecter) AddAcceptance(ctx interface{}, acceptance interface{}) *MockRuleAcceptanceRepository_AddAcceptance_Call {
+	return &MockRuleAcceptanceRepository_AddAcceptance_Call{Call: _e.mock.On("AddAcceptance", ctx, acceptance)}
+}
+
+func (_c *MockRuleAcceptanceRepository_AddAcceptance_Call) Run(run func(ctx context.Context, acceptance models.RuleAcceptance)) *MockRuleAcceptanceRepository_AddAcceptance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.RuleAcceptance))
+	})
+	return _c
+}
+
+func (_c *MockRuleAcceptanceRepository_AddAcceptance_Call) Return(err error) *MockRuleAcceptanceRepository_AddAcceptance_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRuleAcceptanceRepository_AddAcceptance_Call) RunAndReturn(run func(ctx context.Context, acceptance models.RuleAcceptance) error) *MockRuleAcceptanceRepository_AddAcceptance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAcceptanceCoverage provides a mock function for the type MockRuleAcceptanceRepository
+func (_mock *MockRuleAcceptanceRepository) GetAcceptanceCoverage(ctx context.Context) (int, []models.RuleAcceptanceCoverage, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAcceptanceCoverage")
+	}
+
+	var r0 int
+	var r1 []models.RuleAcceptanceCoverage
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (int, []models.RuleAcceptanceCoverage, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) []models.RuleAcceptanceCoverage); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]models.RuleAcceptanceCoverage)
+		}
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = returnFunc(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockRuleAcceptanceRepository_GetAcceptanceCoverage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAcceptanceCoverage'
+type MockRuleAcceptanceRepository_GetAcceptanceCoverage_Call struct {
+	*mock.Call
+}
+
+// GetAcceptanceCoverage is a helper method to define mock.On call
+//   - ctx
+func (_e *MockRuleAcceptanceRepository_Expecter) GetAcceptanceCoverage(ctx interface{}) *MockRuleAcceptanceRepository_GetAcceptanceCoverage_Call {
+	return &MockRuleAcceptanceRepository_GetAcceptanceCoverage_Call{Call: _e.mock.On("GetAcceptanceCoverage", ctx)}
+}
+
+func (_c *MockRuleAcceptanceRepository_GetAcceptanceCoverage_Call) Run(run func(ctx context.Context)) *MockRuleAcceptanceRepository_GetAcceptanceCoverage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockRuleAcceptanceRepository_GetAcceptanceCoverage_Call) Return(n int, ruleAcceptanceCoverages []models.RuleAcceptanceCoverage, err error) *MockRuleAcceptanceRepository_GetAcceptanceCoverage_Call {
+	_c.Call.Return(n, ruleAcceptanceCoverages, err)
+	return _c
+}
+
+func (_c *MockRuleAcceptanceRepository_GetAcceptanceCoverage_Call) RunAndReturn(run func(ctx context.Context) (int, []models.RuleAcceptanceCoverage, error)) *MockRuleAcceptanceRepository_GetAcceptanceCoverage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPendingRules provides a mock function for the type MockRuleAcceptanceRepository
+func (_mock *MockRuleAcceptanceRepository) GetPendingRules(ctx context.Context, userId int) ([]models.PendingRule, error) {
+	ret := _mock.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingRules")
+	}
+
+	var r0 []models.PendingRule
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]models.PendingRule, error)); ok {
+		return returnFunc(ctx, userId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []models.PendingRule); ok {
+		r0 = returnFunc(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PendingRule)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleAcceptanceRepository_GetPendingRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPendingRules'
+type MockRuleAcceptanceRepository_GetPendingRules_Call struct {
+	*mock.Call
+}
+
+// GetPendingRules is a helper method to define mock.On call
+//   - ctx
+//   - userId
+func (_e *MockRuleAcceptanceRepository_Expecter) GetPendingRules(ctx interface{}, userId interface{}) *MockRuleAcceptanceRepository_GetPendingRules_Call {
+	return &MockRuleAcceptanceRepository_GetPendingRules_Call{Call: _e.mock.On("GetPendingRules", ctx, userId)}
+}
+
+func (_c *MockRuleAcceptanceRepository_GetPendingRules_Call) Run(run func(ctx context.Context, userId int)) *MockRuleAcceptanceRepository_GetPendingRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockRuleAcceptanceRepository_GetPendingRules_Call) Return(pendingRules []models.PendingRule, err error) *MockRuleAcceptanceRepository_GetPendingRules_Call {
+	_c.Call.Return(pendingRules, err)
+	return _c
+}
+
+func (_c *MockRuleAcceptanceRepository_GetPendingRules_Call) RunAndReturn(run func(ctx context.Context, userId int) ([]models.PendingRule, error)) *MockRuleAcceptanceRepository_GetPendingRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRuleProposalRepository creates a new instance of MockRuleProposalRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRuleProposalRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRuleProposalRepository {
+	mock := &MockRuleProposalRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockRuleProposalRepository is an autogenerated mock type for the RuleProposalRepository type
+type MockRuleProposalRepository struct {
+	mock.Mock
+}
+
+type MockRuleProposalRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRuleProposalRepository) EXPECT() *MockRuleProposalRepository_Expecter {
+	return &MockRuleProposalRepository_Expecter{mock: &_m.Mock}
+}
+
+// AddComment provides a mock function for the type MockRuleProposalRepository
+func (_mock *MockRuleProposalRepository) AddComment(ctx context.Context, comment models.RuleProposalComment) (*models.RuleProposalComment, error) {
+	ret := _mock.Called(ctx, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddComment")
+	}
+
+	var r0 *models.RuleProposalComment
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleProposalComment) (*models.RuleProposalComment, error)); ok {
+		return returnFunc(ctx, comment)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleProposalComment) *models.RuleProposalComment); ok {
+		r0 = returnFunc(ctx, comment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleProposalComment)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.RuleProposalComment) error); ok {
+		r1 = returnFunc(ctx, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleProposalRepository_AddComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddComment'
+type MockRuleProposalRepository_AddComment_Call struct {
+	*mock.Call
+}
+
+// AddComment is a helper method to define mock.On call
+//   - ctx
+//   - comment
+func (_e *MockRuleProposalRepository_Expecter) AddComment(ctx interface{}, comment interface{}) *MockRuleProposalRepository_AddComment_Call {
+	return &MockRuleProposalRepository_AddComment_Call{Call: _e.mock.On("AddComment", ctx, comment)}
+}
+
+func (_c *MockRuleProposalRepository_AddComment_Call) Run(run func(ctx context.Context, comment models.RuleProposalComment)) *MockRuleProposalRepository_AddComment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.RuleProposalComment))
+	})
+	return _c
+}
+
+func (_c *MockRuleProposalRepository_AddComment_Call) Return(ruleProposalComment *models.RuleProposalComment, err error) *MockRuleProposalRepository_AddComment_Call {
+	_c.Call.Return(ruleProposalComment, err)
+	return _c
+}
+
+func (_c *MockRuleProposalRepository_AddComment_Call) RunAndReturn(run func(ctx context.Context, comment models.RuleProposalComment) (*models.RuleProposalComment, error)) *MockRuleProposalRepository_AddComment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddProposal provides a mock function for the type MockRuleProposalRepository
+func (_mock *MockRuleProposalRepository) AddProposal(ctx context.Context, proposal models.RuleProposal) (*models.RuleProposal, error) {
+	ret := _mock.Called(ctx, proposal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddProposal")
+	}
+
+	var r0 *models.RuleProposal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleProposal) (*models.RuleProposal, error)); ok {
+		return returnFunc(ctx, proposal)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleProposal) *models.RuleProposal); ok {
+		r0 = returnFunc(ctx, proposal)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleProposal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.RuleProposal) error); ok {
+		r1 = returnFunc(ctx, proposal)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleProposalRepository_AddProposal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddProposal'
+type MockRuleProposalRepository_AddProposal_Call struct {
+	*mock.Call
+}
+
+// AddProposal is a helper method to define mock.On call
+//   - ctx
+//   - proposal
+func (_e *MockRuleProposalRepository_Expecter) AddProposal(ctx interface{}, proposal interface{}) *MockRuleProposalRepository_AddProposal_Call {
+	return &MockRuleProposalRepository_AddProposal_Call{Call: _e.mock.On("AddProposal", ctx, proposal)}
+}
+
+func (_c *MockRuleProposalRepository_AddProposal_Call) Run(run func(ctx context.Context, proposal models.RuleProposal)) *MockRuleProposalRepository_AddProposal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.RuleProposal))
+	})
+	return _c
+}
+
+func (_c *MockRuleProposalRepository_AddProposal_Call) Return(ruleProposal *models.RuleProposal, err error) *MockRuleProposalRepository_AddProposal_Call {
+	_c.Call.Return(ruleProposal, err)
+	return _c
+}
+
+func (_c *MockRuleProposalRepository_AddProposal_Call) RunAndReturn(run func(ctx context.Context, proposal models.RuleProposal) (*models.RuleProposal, error)) *MockRuleProposalRepository_AddProposal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ApproveProposal provides a mock function for the type MockRuleProposalRepository
+func (_mock *MockRuleProposalRepository) ApproveProposal(ctx context.Context, proposalId int, approverId int) (*models.RuleProposal, error) {
+	ret := _mock.Called(ctx, proposalId, approverId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApproveProposal")
+	}
+
+	var r0 *models.RuleProposal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) (*models.RuleProposal, error)); ok {
+		return returnFunc(ctx, proposalId, approverId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) *models.RuleProposal); ok {
+		r0 = returnFunc(ctx, proposalId, approverId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleProposal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = returnFunc(ctx, proposalId, approverId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleProposalRepository_ApproveProposal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApproveProposal'
+type MockRuleProposalRepository_ApproveProposal_Call struct {
+	*mock.Call
+}
+
+// ApproveProposal is a helper method to define mock.On call
+//   - ctx
+//   - proposalId
+//   - approverId
+func (_e *MockRuleProposalRepository_Expecter) ApproveProposal(ctx interface{}, proposalId interface{}, approverId interface{}) *MockRuleProposalRepository_ApproveProposal_Call {
+	return &MockRuleProposalRepository_ApproveProposal_Call{Call: _e.mock.On("ApproveProposal", ctx, proposalId, approverId)}
+}
+
+func (_c *MockRuleProposalRepository_ApproveProposal_Call) Run(run func(ctx context.Context, proposalId int, approverId int)) *MockRuleProposalRepository_ApproveProposal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockRuleProposalRepository_ApproveProposal_Call) Return(ruleProposal *models.RuleProposal, err error) *MockRuleProposalRepository_ApproveProposal_Call {
+	_c.Call.Return(ruleProposal, err)
+	return _c
+}
+
+func (_c *MockRuleProposalRepository_ApproveProposal_Call) RunAndReturn(run func(ctx context.Context, proposalId int, approverId int) (*models.RuleProposal, error)) *MockRuleProposalRepository_ApproveProposal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetProposal provides a mock function for the type MockRuleProposalRepository
+func (_mock *MockRuleProposalRepository) GetProposal(ctx context.Context, proposalId int) (*models.RuleProposal, error) {
+	ret := _mock.Called(ctx, proposalId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProposal")
+	}
+
+	var r0 *models.RuleProposal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.RuleProposal, error)); ok {
+		return returnFunc(ctx, proposalId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.RuleProposal); ok {
+		r0 = returnFunc(ctx, proposalId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleProposal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, proposalId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleProposalRepository_GetProposal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProposal'
+type MockRuleProposalRepository_GetProposal_Call struct {
+	*mock.Call
+}
+
+// GetProposal is a helper method to define mock.On call
+//   - ctx
+//   - proposalId
+func (_e *MockRuleProposalRepository_Expecter) GetProposal(ctx interface{}, proposalId interface{}) *MockRuleProposalRepository_GetProposal_Call {
+	return &MockRuleProposalRepository_GetProposal_Call{Call: _e.mock.On("GetProposal", ctx, proposalId)}
+}
+
+func (_c *MockRuleProposalRepository_GetProposal_Call) Run(run func(ctx context.Context, proposalId int)) *MockRuleProposalRepository_GetProposal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockRuleProposalRepository_GetProposal_Call) Return(ruleProposal *models.RuleProposal, err error) *MockRuleProposalRepository_GetProposal_Call {
+	_c.Call.Return(ruleProposal, err)
+	return _c
+}
+
+func (_c *MockRuleProposalRepository_GetProposal_Call) RunAndReturn(run func(ctx context.Context, proposalId int) (*models.RuleProposal, error)) *MockRuleProposalRepository_GetProposal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetProposals provides a mock function for the type MockRuleProposalRepository
+func (_mock *MockRuleProposalRepository) GetProposals(ctx context.Context, filter models.RuleProposalListRequest) ([]models.RuleProposal, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProposals")
+	}
+
+	var r0 []models.RuleProposal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleProposalListRequest) ([]models.RuleProposal, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleProposalListRequest) []models.RuleProposal); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.RuleProposal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.RuleProposalListRequest) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleProposalRepository_GetProposals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProposals'
+type MockRuleProposalRepository_GetProposals_Call struct {
+	*mock.Call
+}
+
+// GetProposals is a helper method to define mock.On call
+//   - ctx
+//   - filter
+func (_e *MockRuleProposalRepository_Expecter) GetProposals(ctx interface{}, filter interface{}) *MockRuleProposalRepository_GetProposals_Call {
+	return &MockRuleProposalRepository_GetProposals_Call{Call: _e.mock.On("GetProposals", ctx, filter)}
+}
+
+func (_c *MockRuleProposalRepository_GetProposals_Call) Run(run func(ctx context.Context, filter models.RuleProposalListRequest)) *MockRuleProposalRepository_GetProposals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.RuleProposalListRequest))
+	})
+	return _c
+}
+
+func (_c *MockRuleProposalRepository_GetProposals_Call) Return(ruleProposals []models.RuleProposal, err error) *MockRuleProposalRepository_GetProposals_Call {
+	_c.Call.Return(ruleProposals, err)
+	return _c
+}
+
+func (_c *MockRuleProposalRepository_GetProposals_Call) RunAndReturn(run func(ctx context.Context, filter models.RuleProposalListRequest) ([]models.RuleProposal, error)) *MockRuleProposalRepository_GetProposals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RejectProposal provides a mock function for the type MockRuleProposalRepository
+func (_mock *MockRuleProposalRepository) RejectProposal(ctx context.Context, proposalId int, reviewerId int, reason string) (*models.RuleProposal, error) {
+	ret := _mock.Called(ctx, proposalId, reviewerId, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectProposal")
+	}
+
+	var r0 *models.RuleProposal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) (*models.RuleProposal, error)); ok {
+		return returnFunc(ctx, proposalId, reviewerId, reason)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) *models.RuleProposal); ok {
+		r0 = returnFunc(ctx, proposalId, reviewerId, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleProposal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, string) error); ok {
+		r1 = returnFunc(ctx, proposalId, reviewerId, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleProposalRepository_RejectProposal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RejectProposal'
+type MockRuleProposalRepository_RejectProposal_Call struct {
+	*mock.Call
+}
+
+// RejectProposal is a helper method to define mock.On call
+//   - ctx
+//   - proposalId
+//   - reviewerId
+//   - reason
+func (_e *MockRuleProposalRepository_Expecter) RejectProposal(ctx interface{}, proposalId interface{}, reviewerId interface{}, reason interface{}) *MockRuleProposalRepository_RejectProposal_Call {
+	return &MockRuleProposalRepository_RejectProposal_Call{Call: _e.mock.On("RejectProposal", ctx, proposalId, reviewerId, reason)}
+}
+
+func (_c *MockRuleProposalRepository_RejectProposal_Call) Run(run func(ctx context.Context, proposalId int, reviewerId int, reason string)) *MockRuleProposalRepository_RejectProposal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockRuleProposalRepository_RejectProposal_Call) Return(ruleProposal *models.RuleProposal, err error) *MockRuleProposalRepository_RejectProposal_Call {
+	_c.Call.Return(ruleProposal, err)
+	return _c
+}
+
+func (_c *MockRuleProposalRepository_RejectProposal_Call) RunAndReturn(run func(ctx context.Context, proposalId int, reviewerId int, reason string) (*models.RuleProposal, error)) *MockRuleProposalRepository_RejectProposal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRulesRepository creates a new instance of MockRulesRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRulesRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRulesRepository {
+	mock := &MockRulesRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockRulesRepository is an autogenerated mock type for the RulesRepository type
+type MockRulesRepository struct {
+	mock.Mock
+}
+
+type MockRulesRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRulesRepository) EXPECT() *MockRulesRepository_Expecter {
+	return &MockRulesRepository_Expecter{mock: &_m.Mock}
+}
+
+// AddRule provides a mock function for the type MockRulesRepository
+func (_mock *MockRulesRepository) AddRule(ctx context.Context, rule models.Rule, userId int) error {
+	ret := _mock.Called(ctx, rule, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddRule")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Rule, int) error); ok {
+		r0 = returnFunc(ctx, rule, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRulesRepository_AddRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddRule'
+type MockRulesRepository_AddRule_Call struct {
+	*mock.Call
+}
+
+// AddRule is a helper method to define mock.On call
+//   - ctx
+//   - rule
+//   - userId
+func (_e *MockRulesRepository_Expecter) AddRule(ctx interface{}, rule interface{}, userId interface{}) *MockRulesRepository_AddRule_Call {
+	return &MockRulesRepository_AddRule_Call{Call: _e.mock.On("AddRule", ctx, rule, userId)}
+}
+
+func (_c *MockRulesRepository_AddRule_Call) Run(run func(ctx context.Context, rule models.Rule, userId int)) *MockRulesRepository_AddRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Rule), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockRulesRepository_AddRule_Call) Return(err error) *MockRulesRepository_AddRule_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRulesRepository_AddRule_Call) RunAndReturn(run func(ctx context.Context, rule models.Rule, userId int) error) *MockRulesRepository_AddRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteRule provides a mock function for the type MockRulesRepository
+func (_mock *MockRulesRepository) DeleteRule(ctx context.Context, ruleId int, userId int, version int) error {
+	ret := _mock.Called(ctx, ruleId, userId, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRule")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) error); ok {
+		r0 = returnFunc(ctx, ruleId, userId, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRulesRepository_DeleteRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRule'
+type MockRulesRepository_DeleteRule_Call struct {
+	*mock.Call
+}
+
+// DeleteRule is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+//   - userId
+//   - version
+func (_e *MockRulesRepository_Expecter) DeleteRule(ctx interface{}, ruleId interface{}, userId interface{}, version interface{}) *MockRulesRepository_DeleteRule_Call {
+	return &MockRulesRepository_DeleteRule_Call{Call: _e.mock.On("DeleteRule", ctx, ruleId, userId, version)}
+}
+
+func (_c *MockRulesRepository_DeleteRule_Call) Run(run func(ctx context.Context, ruleId int, userId int, version int)) *MockRulesRepository_DeleteRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockRulesRepository_DeleteRule_Call) Return(err error) *MockRulesRepository_DeleteRule_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRulesRepository_DeleteRule_Call) RunAndReturn(run func(ctx context.Context, ruleId int, userId int, version int) error) *MockRulesRepository_DeleteRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAudit provides a mock function for the type MockRulesRepository
+func (_mock *MockRulesRepository) GetAudit(ctx context.Context, filter models.RuleAuditFilter, limit int, offset int) ([]models.Audit, int, error) {
+	ret := _mock.Called(ctx, filter, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAudit")
+	}
+
+	var r0 []models.Audit
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleAuditFilter, int, int) ([]models.Audit, int, error)); ok {
+		return returnFunc(ctx, filter, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleAuditFilter, int, int) []models.Audit); ok {
+		r0 = returnFunc(ctx, filter, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Audit)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.RuleAuditFilter, int, int) int); ok {
+		r1 = returnFunc(ctx, filter, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, models.RuleAuditFilter, int, int) error); ok {
+		r2 = returnFunc(ctx, filter, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockRulesRepository_GetAudit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAudit'
+type MockRulesRepository_GetAudit_Call struct {
+	*mock.Call
+}
+
+// GetAudit is a helper method to define mock.On call
+//   - ctx
+//   - filter
+//   - limit
+//   - offset
+func (_e *MockRulesRepository_Expecter) GetAudit(ctx interface{}, filter interface{}, limit interface{}, offset interface{}) *MockRulesRepository_GetAudit_Call {
+	return &MockRulesRepository_GetAudit_Call{Call: _e.mock.On("GetAudit", ctx, filter, limit, offset)}
+}
+
+func (_c *MockRulesRepository_GetAudit_Call) Run(run func(ctx context.Context, filter models.RuleAuditFilter, limit int, offset int)) *MockRulesRepository_GetAudit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.RuleAuditFilter), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockRulesRepository_GetAudit_Call) Return(audits []models.Audit, n int, err error) *MockRulesRepository_GetAudit_Call {
+	_c.Call.Return(audits, n, err)
+	return _c
+}
+
+func (_c *MockRulesRepository_GetAudit_Call) RunAndReturn(run func(ctx context.Context, filter models.RuleAuditFilter, limit int, offset int) ([]models.Audit, int, error)) *MockRulesRepository_GetAudit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDueRules provides a mock function for the type MockRulesRepository
+func (_mock *MockRulesRepository) GetDueRules(ctx context.Context, now time.Time) ([]models.Rule, error) {
+	ret := _mock.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDueRules")
+	}
+
+	var r0 []models.Rule
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) ([]models.Rule, error)); ok {
+		return returnFunc(ctx, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) []models.Rule); ok {
+		r0 = returnFunc(ctx, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Rule)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = returnFunc(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRulesRepository_GetDueRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDueRules'
+type MockRulesRepository_GetDueRules_Call struct {
+	*mock.Call
+}
+
+// GetDueRules is a helper method to define mock.On call
+//   - ctx
+//   - now
+func (_e *MockRulesRepository_Expecter) GetDueRules(ctx interface{}, now interface{}) *MockRulesRepository_GetDueRules_Call {
+	return &MockRulesRepository_GetDueRules_Call{Call: _e.mock.On("GetDueRules", ctx, now)}
+}
+
+func (_c *MockRulesRepository_GetDueRules_Call) Run(run func(ctx context.Context, now time.Time)) *MockRulesRepository_GetDueRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockRulesRepository_GetDueRules_Call) Return(rules []models.Rule, err error) *MockRulesRepository_GetDueRules_Call {
+	_c.Call.Return(rules, err)
+	return _c
+}
+
+func (_c *MockRulesRepository_GetDueRules_Call) RunAndReturn(run func(ctx context.Context, now time.Time) ([]models.Rule, error)) *MockRulesRepository_GetDueRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRule provides a mock function for the type MockRulesRepository
+func (_mock *MockRulesRepository) GetRule(ctx context.Context, ruleId int) (*models.Rule, error) {
+	ret := _mock.Called(ctx, ruleId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRule")
+	}
+
+	var r0 *models.Rule
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.Rule, error)); ok {
+		return returnFunc(ctx, ruleId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.Rule); ok {
+		r0 = returnFunc(ctx, ruleId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Rule)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, ruleId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRulesRepository_GetRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRule'
+type MockRulesRepository_GetRule_Call struct {
+	*mock.Call
+}
+
+// GetRule is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+func (_e *MockRulesRepository_Expecter) GetRule(ctx interface{}, ruleId interface{}) *MockRulesRepository_GetRule_Call {
+	return &MockRulesRepository_GetRule_Call{Call: _e.mock.On("GetRule", ctx, ruleId)}
+}
+
+func (_c *MockRulesRepository_GetRule_Call) Run(run func(ctx context.Context, ruleId int)) *MockRulesRepository_GetRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockRulesRepository_GetRule_Call) Return(rule *models.Rule, err error) *MockRulesRepository_GetRule_Call {
+	_c.Call.Return(rule, err)
+	return _c
+}
+
+func (_c *MockRulesRepository_GetRule_Call) RunAndReturn(run func(ctx context.Context, ruleId int) (*models.Rule, error)) *MockRulesRepository_GetRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRuleVersion provides a mock function for the type MockRulesRepository
+func (_mock *MockRulesRepository) GetRuleVersion(ctx context.Context, ruleId int, version int) (*models.RuleVersion, error) {
+	ret := _mock.Called(ctx, ruleId, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRuleVersion")
+	}
+
+	var r0 *models.RuleVersion
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) (*models.RuleVersion, error)); ok {
+		return returnFunc(ctx, ruleId, version)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) *models.RuleVersion); ok {
+		r0 = returnFunc(ctx, ruleId, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleVersion)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = returnFunc(ctx, ruleId, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRulesRepository_GetRuleVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRuleVersion'
+type MockRulesRepository_GetRuleVersion_Call struct {
+	*mock.Call
+}
+
+// GetRuleVersion is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+//   - version
+func (_e *MockRulesRepository_Expecter) GetRuleVersion(ctx interface{}, ruleId interface{}, version interface{}) *MockRulesRepository_GetRuleVersion_Call {
+	return &MockRulesRepository_GetRuleVersion_Call{Call: _e.mock.On("GetRuleVersion", ctx, ruleId, version)}
+}
+
+func (_c *MockRulesRepository_GetRuleVersion_Call) Run(run func(ctx context.Context, ruleId int, version int)) *MockRulesRepository_GetRuleVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockRulesRepository_GetRuleVersion_Call) Return(ruleVersion *models.RuleVersion, err error) *MockRulesRepository_GetRuleVersion_Call {
+	_c.Call.Return(ruleVersion, err)
+	return _c
+}
+
+func (_c *MockRulesRepository_GetRuleVersion_Call) RunAndReturn(run func(ctx context.Context, ruleId int, version int) (*models.RuleVersion, error)) *MockRulesRepository_GetRuleVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRuleVersions provides a mock function for the type MockRulesRepository
+func (_mock *MockRulesRepository) GetRuleVersions(ctx context.Context, ruleId int) ([]models.RuleVersion, error) {
+	ret := _mock.Called(ctx, ruleId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRuleVersions")
+	}
+
+	var r0 []models.RuleVersion
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]models.RuleVersion, error)); ok {
+		return returnFunc(ctx, ruleId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []models.RuleVersion); ok {
+		r0 = returnFunc(ctx, ruleId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.RuleVersion)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, ruleId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRulesRepository_GetRuleVersions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRuleVersions'
+type MockRulesRepository_GetRuleVersions_Call struct {
+	*mock.Call
+}
+
+// GetRuleVersions is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+func (_e *MockRulesRepository_Expecter) GetRuleVersions(ctx interface{}, ruleId interface{}) *MockRulesRepository_GetRuleVersions_Call {
+	return &MockRulesRepository_GetRuleVersions_Call{Call: _e.mock.On("GetRuleVersions", ctx, ruleId)}
+}
+
+func (_c *MockRulesRepository_GetRuleVersions_Call) Run(run func(ctx context.Context, ruleId int)) *MockRulesRepository_GetRuleVersions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockRulesRepository_GetRuleVersions_Call) Return(ruleVersions []models.RuleVersion, err error) *MockRulesRepository_GetRuleVersions_Call {
+	_c.Call.Return(ruleVersions, err)
+	return _c
+}
+
+func (_c *MockRulesRepository_GetRuleVersions_Call) RunAndReturn(run func(ctx context.Context, ruleId int) ([]models.RuleVersion, error)) *MockRulesRepository_GetRuleVersions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRules provides a mock function for the type MockRulesRepository
+func (_mock *MockRulesRepository) GetRules(ctx context.Context, filter models.RuleListRequest) ([]models.Rule, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRules")
+	}
+
+	var r0 []models.Rule
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleListRequest) ([]models.Rule, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleListRequest) []models.Rule); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Rule)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.RuleListRequest) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRulesRepository_GetRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRules'
+type MockRulesRepository_GetRules_Call struct {
+	*mock.Call
+}
+
+// GetRules is a helper method to define mock.On call
+//   - ctx
+//   - filter
+func (_e *MockRulesRepository_Expecter) GetRules(ctx interface{}, filter interface{}) *MockRulesRepository_GetRules_Call {
+	return &MockRulesRepository_GetRules_Call{Call: _e.mock.On("GetRules", ctx, filter)}
+}
+
+func (_c *MockRulesRepository_GetRules_Call) Run(run func(ctx context.Context, filter models.RuleListRequest)) *MockRulesRepository_GetRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.RuleListRequest))
+	})
+	return _c
+}
+
+func (_c *MockRulesRepository_GetRules_Call) Return(rules []models.Rule, err error) *MockRulesRepository_GetRules_Call {
+	_c.Call.Return(rules, err)
+	return _c
+}
+
+func (_c *MockRulesRepository_GetRules_Call) RunAndReturn(run func(ctx context.Context, filter models.RuleListRequest) ([]models.Rule, error)) *MockRulesRepository_GetRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ModifyRule provides a mock function for the type MockRulesRepository
+func (_mock *MockRulesRepository) ModifyRule(ctx context.Context, ruleId int, modification models.RuleModify, userId int, version int) error {
+	ret := _mock.Called(ctx, ruleId, modification, userId, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ModifyRule")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.RuleModify, int, int) error); ok {
+		r0 = returnFunc(ctx, ruleId, modification, userId, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRulesRepository_ModifyRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ModifyRule'
+type MockRulesRepository_ModifyRule_Call struct {
+	*mock.Call
+}
+
+// ModifyRule is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+//   - modification
+//   - userId
+//   - version
+func (_e *MockRulesRepository_Expecter) ModifyRule(ctx interface{}, ruleId interface{}, modification interface{}, userId interface{}, version interface{}) *MockRulesRepository_ModifyRule_Call {
+	return &MockRulesRepository_ModifyRule_Call{Call: _e.mock.On("ModifyRule", ctx, ruleId, modification, userId, version)}
+}
+
+func (_c *MockRulesRepository_ModifyRule_Call) Run(run func(ctx context.Context, ruleId int, modification models.RuleModify, userId int, version int)) *MockRulesRepository_ModifyRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.RuleModify), args[3].(int), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *MockRulesRepository_ModifyRule_Call) Return(err error) *MockRulesRepository_ModifyRule_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRulesRepository_ModifyRule_Call) RunAndReturn(run func(ctx context.Context, ruleId int, modification models.RuleModify, userId int, version int) error) *MockRulesRepository_ModifyRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RollbackRule provides a mock function for the type MockRulesRepository
+func (_mock *MockRulesRepository) RollbackRule(ctx context.Context, ruleId int, target models.RuleVersion, userId int, version int) error {
+	ret := _mock.Called(ctx, ruleId, target, userId, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RollbackRule")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.RuleVersion, int, int) error); ok {
+		r0 = returnFunc(ctx, ruleId, target, userId, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRulesRepository_RollbackRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RollbackRule'
+type MockRulesRepository_RollbackRule_Call struct {
+	*mock.Call
+}
+
+// RollbackRule is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+//   - target
+//   - userId
+//   - version
+func (_e *MockRulesRepository_Expecter) RollbackRule(ctx interface{}, ruleId interface{}, target interface{}, userId interface{}, version interface{}) *MockRulesRepository_RollbackRule_Call {
+	return &MockRulesRepository_RollbackRule_Call{Call: _e.mock.On("RollbackRule", ctx, ruleId, target, userId, version)}
+}
+
+func (_c *MockRulesRepository_RollbackRule_Call) Run(run func(ctx context.Context, ruleId int, target models.RuleVersion, userId int, version int)) *MockRulesRepository_RollbackRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.RuleVersion), args[3].(int), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *MockRulesRepository_RollbackRule_Call) Return(err error) *MockRulesRepository_RollbackRule_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRulesRepository_RollbackRule_Call) RunAndReturn(run func(ctx context.Context, ruleId int, target models.RuleVersion, userId int, version int) error) *MockRulesRepository_RollbackRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetRuleStatus provides a mock function for the type MockRulesRepository
+func (_mock *MockRulesRepository) SetRuleStatus(ctx context.Context, ruleId int, status models.RuleStatus, userId int, version int, outbox models.Outbox) error {
+	ret := _mock.Called(ctx, ruleId, status, userId, version, outbox)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRuleStatus")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.RuleStatus, int, int, models.Outbox) error); ok {
+		r0 = returnFunc(ctx, ruleId, status, userId, version, outbox)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRulesRepository_SetRuleStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetRuleStatus'
+type MockRulesRepository_SetRuleStatus_Call struct {
+	*mock.Call
+}
+
+// SetRuleStatus is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+//   - status
+//   - userId
+//   - version
+//   - outbox
+func (_e *MockRulesRepository_Expecter) SetRuleStatus(ctx interface{}, ruleId interface{}, status interface{}, userId interface{}, version interface{}, outbox interface{}) *MockRulesRepository_SetRuleStatus_Call {
+	return &MockRulesRepository_SetRuleStatus_Call{Call: _e.mock.On("SetRuleStatus", ctx, ruleId, status, userId, version, outbox)}
+}
+
+func (_c *MockRulesRepository_SetRuleStatus_Call) Run(run func(ctx context.Context, ruleId int, status models.RuleStatus, userId int, version int, outbox models.Outbox)) *MockRulesRepository_SetRuleStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.RuleStatus), args[3].(int), args[4].(int), args[5].(models.Outbox))
+	})
+	return _c
+}
+
+func (_c *MockRulesRepository_SetRuleStatus_Call) Return(err error) *MockRulesRepository_SetRuleStatus_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRulesRepository_SetRuleStatus_Call) RunAndReturn(run func(ctx context.Context, ruleId int, status models.RuleStatus, userId int, version int, outbox models.Outbox) error) *MockRulesRepository_SetRuleStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewmockrowScanner creates a new instance of mockrowScanner. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewmockrowScanner(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockrowScanner {
+	mock := &mockrowScanner{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockrowScanner is an autogenerated mock type for the rowScanner type
+type mockrowScanner struct {
+	mock.Mock
+}
+
+type mockrowScanner_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockrowScanner) EXPECT() *mockrowScanner_Expecter {
+	return &mockrowScanner_Expecter{mock: &_m.Mock}
+}
+
+// Scan provides a mock function for the type mockrowScanner
+func (_mock *mockrowScanner) Scan(dest ...any) error {
+	var tmpRet mock.Arguments
+	if len(dest) > 0 {
+		tmpRet = _mock.Called(dest)
+	} else {
+		tmpRet = _mock.Called()
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for Scan")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(...any) error); ok {
+		r0 = returnFunc(dest...)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockrowScanner_Scan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Scan'
+type mockrowScanner_Scan_Call struct {
+	*mock.Call
+}
+
+// Scan is a helper method to define mock.On call
+//   - dest
+func (_e *mockrowScanner_Expecter) Scan(dest ...interface{}) *mockrowScanner_Scan_Call {
+	return &mockrowScanner_Scan_Call{Call: _e.mock.On("Scan",
+		append([]interface{}{}, dest...)...)}
+}
+
+func (_c *mockrowScanner_Scan_Call) Run(run func(dest ...any)) *mockrowScanner_Scan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]any, len(args)-0)
+		for i, a := range args[0:] {
+			if a != nil {
+				variadicArgs[i] = a.(any)
+			}
+		}
+		run(variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *mockrowScanner_Scan_Call) Return(err error) *mockrowScanner_Scan_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockrowScanner_Scan_Call) RunAndReturn(run func(dest ...any) error) *mockrowScanner_Scan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockStatsRepository creates a new instance of MockStatsRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockStatsRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockStatsRepository {
+	mock := &MockStatsRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockStatsRepository is an autogenerated mock type for the StatsRepository type
+type MockStatsRepository struct {
+	mock.Mock
+}
+
+type MockStatsRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockStatsRepository) EXPECT() *MockStatsRepository_Expecter {
+	return &MockStatsRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetStats provides a mock function for the type MockStatsRepository
+func (_mock *MockStatsRepository) GetStats(ctx context.Context, from time.Time, until time.Time, interval string) (*models.AdminStats, error) {
+	ret := _mock.Called(ctx, from, until, interval)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStats")
+	}
+
+	var r0 *models.AdminStats
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, string) (*models.AdminStats, error)); ok {
+		return returnFunc(ctx, from, until, interval)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, string) *models.AdminStats); ok {
+		r0 = returnFunc(ctx, from, until, interval)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AdminStats)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, string) error); ok {
+		r1 = returnFunc(ctx, from, until, interval)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStatsRepository_GetStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStats'
+type MockStatsRepository_GetStats_Call struct {
+	*mock.Call
+}
+
+// GetStats is a helper method to define mock.On call
+//   - ctx
+//   - from
+//   - until
+//   - interval
+func (_e *MockStatsRepository_Expecter) GetStats(ctx interface{}, from interface{}, until interface{}, interval interface{}) *MockStatsRepository_GetStats_Call {
+	return &MockStatsRepository_GetStats_Call{Call: _e.mock.On("GetStats", ctx, from, until, interval)}
+}
+
+func (_c *MockStatsRepository_GetStats_Call) Run(run func(ctx context.Context, from time.Time, until time.Time, interval string)) *MockStatsRepository_GetStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockStatsRepository_GetStats_Call) Return(adminStats *models.AdminStats, err error) *MockStatsRepository_GetStats_Call {
+	_c.Call.Return(adminStats, err)
+	return _c
+}
+
+func (_c *MockStatsRepository_GetStats_Call) RunAndReturn(run func(ctx context.Context, from time.Time, until time.Time, interval string) (*models.AdminStats, error)) *MockStatsRepository_GetStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockUserBulkRepository creates a new instance of MockUserBulkRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserBulkRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserBulkRepository {
+	mock := &MockUserBulkRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockUserBulkRepository is an autogenerated mock type for the UserBulkRepository type
+type MockUserBulkRepository struct {
+	mock.Mock
+}
+
+type MockUserBulkRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUserBulkRepository) EXPECT() *MockUserBulkRepository_Expecter {
+	return &MockUserBulkRepository_Expecter{mock: &_m.Mock}
+}
+
+// RunBulkAction provides a mock function for the type MockUserBulkRepository
+func (_mock *MockUserBulkRepository) RunBulkAction(ctx context.Context, action models.BulkUserAction) (*models.BulkUserActionReport, error) {
+	ret := _mock.Called(ctx, action)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunBulkAction")
+	}
+
+	var r0 *models.BulkUserActionReport
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.BulkUserAction) (*models.BulkUserActionReport, error)); ok {
+		return returnFunc(ctx, action)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.BulkUserAction) *models.BulkUserActionReport); ok {
+		r0 = returnFunc(ctx, action)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.BulkUserActionReport)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.BulkUserAction) error); ok {
+		r1 = returnFunc(ctx, action)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserBulkRepository_RunBulkAction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunBulkAction'
+type MockUserBulkRepository_RunBulkAction_Call struct {
+	*mock.Call
+}
+
+// RunBulkAction is a helper method to define mock.On call
+//   - ctx
+//   - action
+func (_e *MockUserBulkRepository_Expecter) RunBulkAction(ctx interface{}, action interface{}) *MockUserBulkRepository_RunBulkAction_Call {
+	return &MockUserBulkRepository_RunBulkAction_Call{Call: _e.mock.On("RunBulkAction", ctx, action)}
+}
+
+func (_c *MockUserBulkRepository_RunBulkAction_Call) Run(run func(ctx context.Context, action models.BulkUserAction)) *MockUserBulkRepository_RunBulkAction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.BulkUserAction))
+	})
+	return _c
+}
+
+func (_c *MockUserBulkRepository_RunBulkAction_Call) Return(bulkUserActionReport *models.BulkUserActionReport, err error) *MockUserBulkRepository_RunBulkAction_Call {
+	_c.Call.Return(bulkUserActionReport, err)
+	return _c
+}
+
+func (_c *MockUserBulkRepository_RunBulkAction_Call) RunAndReturn(run func(ctx context.Context, action models.BulkUserAction) (*models.BulkUserActionReport, error)) *MockUserBulkRepository_RunBulkAction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockUserEventListener creates a new instance of MockUserEventListener. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserEventListener(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserEventListener {
+	mock := &MockUserEventListener{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockUserEventListener is an autogenerated mock type for the UserEventListener type
+type MockUserEventListener struct {
+	mock.Mock
+}
+
+type MockUserEventListener_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUserEventListener) EXPECT() *MockUserEventListener_Expecter {
+	return &MockUserEventListener_Expecter{mock: &_m.Mock}
+}
+
+// Listen provides a mock function for the type MockUserEventListener
+func (_mock *MockUserEventListener) Listen(ctx context.Context, fn func(models.UserEvent), reconnected func()) error {
+	ret := _mock.Called(ctx, fn, reconnected)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Listen")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, func(models.UserEvent), func()) error); ok {
+		r0 = returnFunc(ctx, fn, reconnected)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserEventListener_Listen_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Listen'
+type MockUserEventListener_Listen_Call struct {
+	*mock.Call
+}
+
+// Listen is a helper method to define mock.On call
+//   - ctx
+//   - fn
+//   - reconnected
+func (_e *MockUserEventListener_Expecter) Listen(ctx interface{}, fn interface{}, reconnected interface{}) *MockUserEventListener_Listen_Call {
+	return &MockUserEventListener_Listen_Call{Call: _e.mock.On("Listen", ctx, fn, reconnected)}
+}
+
+func (_c *MockUserEventListener_Listen_Call) Run(run func(ctx context.Context, fn func(models.UserEvent), reconnected func())) *MockUserEventListener_Listen_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(func(models.UserEvent)), args[2].(func()))
+	})
+	return _c
+}
+
+func (_c *MockUserEventListener_Listen_Call) Return(err error) *MockUserEventListener_Listen_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserEventListener_Listen_Call) RunAndReturn(run func(ctx context.Context, fn func(models.UserEvent), reconnected func()) error) *MockUserEventListener_Listen_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockUserImportRepository creates a new instance of MockUserImportRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserImportRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserImportRepository {
+	mock := &MockUserImportRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockUserImportRepository is an autogenerated mock type for the UserImportRepository type
+type MockUserImportRepository struct {
+	mock.Mock
+}
+
+type MockUserImportRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUserImportRepository) EXPECT() *MockUserImportRepository_Expecter {
+	return &MockUserImportRepository_Expecter{mock: &_m.Mock}
+}
+
+// AddImportedUser provides a mock function for the type MockUserImportRepository
+func (_mock *MockUserImportRepository) AddImportedUser(ctx context.Context, user *models.User) (int, error) {
+	ret := _mock.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddImportedUser")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.User) (int, error)); ok {
+		return returnFunc(ctx, user)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.User) int); ok {
+		r0 = returnFunc(ctx, user)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *models.User) error); ok {
+		r1 = returnFunc(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserImportRepository_AddImportedUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddImportedUser'
+type MockUserImportRepository_AddImportedUser_Call struct {
+	*mock.Call
+}
+
+// AddImportedUser is a helper method to define mock.On call
+//   - ctx
+//   - user
+func (_e *MockUserImportRepository_Expecter) AddImportedUser(ctx interface{}, user interface{}) *MockUserImportRepository_AddImportedUser_Call {
+	return &MockUserImportRepository_AddImportedUser_Call{Call: _e.mock.On("AddImportedUser", ctx, user)}
+}
+
+func (_c *MockUserImportRepository_AddImportedUser_Call) Run(run func(ctx context.Context, user *models.User)) *MockUserImportRepository_AddImportedUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.User))
+	})
+	return _c
+}
+
+func (_c *MockUserImportRepository_AddImportedUser_Call) Return(n int, err error) *MockUserImportRepository_AddImportedUser_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockUserImportRepository_AddImportedUser_Call) RunAndReturn(run func(ctx context.Context, user *models.User) (int, error)) *MockUserImportRepository_AddImportedUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddImportedUsers provides a mock function for the type MockUserImportRepository
+func (_mock *MockUserImportRepository) AddImportedUsers(ctx context.Context, users []*models.User) ([]int, error) {
+	ret := _mock.Called(ctx, users)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddImportedUsers")
+	}
+
+	var r0 []int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*models.User) ([]int, error)); ok {
+		return returnFunc(ctx, users)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*models.User) []int); ok {
+		r0 = returnFunc(ctx, users)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []*models.User) error); ok {
+		r1 = returnFunc(ctx, users)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserImportRepository_AddImportedUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddImportedUsers'
+type MockUserImportRepository_AddImportedUsers_Call struct {
+	*mock.Call
+}
+
+// AddImportedUsers is a helper method to define mock.On call
+//   - ctx
+//   - users
+func (_e *MockUserImportRepository_Expecter) AddImportedUsers(ctx interface{}, users interface{}) *MockUserImportRepository_AddImportedUsers_Call {
+	return &MockUserImportRepository_AddImportedUsers_Call{Call: _e.mock.On("AddImportedUsers", ctx, users)}
+}
+
+func (_c *MockUserImportRepository_AddImportedUsers_Call) Run(run func(ctx context.Context, users []*models.User)) *MockUserImportRepository_AddImportedUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*models.User))
+	})
+	return _c
+}
+
+func (_c *MockUserImportRepository_AddImportedUsers_Call) Return(ints []int, err error) *MockUserImportRepository_AddImportedUsers_Call {
+	_c.Call.Return(ints, err)
+	return _c
+}
+
+func (_c *MockUserImportRepository_AddImportedUsers_Call) RunAndReturn(run func(ctx context.Context, users []*models.User) ([]int, error)) *MockUserImportRepository_AddImportedUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateImportJob provides a mock function for the type MockUserImportRepository
+func (_mock *MockUserImportRepository) CreateImportJob(ctx context.Context, createdBy int) (int, error) {
+	ret := _mock.Called(ctx, createdBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateImportJob")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return returnFunc(ctx, createdBy)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = returnFunc(ctx, createdBy)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, createdBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserImportRepository_CreateImportJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateImportJob'
+type MockUserImportRepository_CreateImportJob_Call struct {
+	*mock.Call
+}
+
+// CreateImportJob is a helper method to define mock.On call
+//   - ctx
+//   - createdBy
+func (_e *MockUserImportRepository_Expecter) CreateImportJob(ctx interface{}, createdBy interface{}) *MockUserImportRepository_CreateImportJob_Call {
+	return &MockUserImportRepository_CreateImportJob_Call{Call: _e.mock.On("CreateImportJob", ctx, createdBy)}
+}
+
+func (_c *MockUserImportRepository_CreateImportJob_Call) Run(run func(ctx context.Context, createdBy int)) *MockUserImportRepository_CreateImportJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserImportRepository_CreateImportJob_Call) Return(n int, err error) *MockUserImportRepository_CreateImportJob_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockUserImportRepository_CreateImportJob_Call) RunAndReturn(run func(ctx context.Context, createdBy int) (int, error)) *MockUserImportRepository_CreateImportJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExistingEmails provides a mock function for the type MockUserImportRepository
+func (_mock *MockUserImportRepository) ExistingEmails(ctx context.Context, emails []string) (map[string]bool, error) {
+	ret := _mock.Called(ctx, emails)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExistingEmails")
+	}
+
+	var r0 map[string]bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) (map[string]bool, error)); ok {
+		return returnFunc(ctx, emails)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) map[string]bool); ok {
+		r0 = returnFunc(ctx, emails)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]bool)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = returnFunc(ctx, emails)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserImportRepository_ExistingEmails_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExistingEmails'
+type MockUserImportRepository_ExistingEmails_Call struct {
+	*mock.Call
+}
+
+// ExistingEmails is a helper method to define mock.On call
+//   - ctx
+//   - emails
+func (_e *MockUserImportRepository_Expecter) ExistingEmails(ctx interface{}, emails interface{}) *MockUserImportRepository_ExistingEmails_Call {
+	return &MockUserImportRepository_ExistingEmails_Call{Call: _e.mock.On("ExistingEmails", ctx, emails)}
+}
+
+func (_c *MockUserImportRepository_ExistingEmails_Call) Run(run func(ctx context.Context, emails []string)) *MockUserImportRepository_ExistingEmails_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *MockUserImportRepository_ExistingEmails_Call) Return(stringToBool map[string]bool, err error) *MockUserImportRepository_ExistingEmails_Call {
+	_c.Call.Return(stringToBool, err)
+	return _c
+}
+
+func (_c *MockUserImportRepository_ExistingEmails_Call) RunAndReturn(run func(ctx context.Context, emails []string) (map[string]bool, error)) *MockUserImportRepository_ExistingEmails_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetMessages provides a mock function for the type MockChatRepository
-func (_mock *MockChatRepository) GetMessages(ctx context.Context, id int, date string) ([]models.ChatMessage, error) {
-	ret := _mock.Called(ctx, id, date)
+// FailStaleImportJobs provides a mock function for the type MockUserImportRepository
+func (_mock *MockUserImportRepository) FailStaleImportJobs(ctx context.Context, staleAfter time.Duration, reason string) (int64, error) {
+	ret := _mock.Called(ctx, staleAfter, reason)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetMessages")
+		panic("no return value specified for FailStaleImportJobs")
 	}
 
-	var r0 []models.ChatMessage
+	var r0 int64
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) ([]models.ChatMessage, error)); ok {
-		return returnFunc(ctx, id, date)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Duration, string) (int64, error)); ok {
+		return returnFunc(ctx, staleAfter, reason)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) []models.ChatMessage); ok {
-		r0 = returnFunc(ctx, id, date)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Duration, string) int64); ok {
+		r0 = returnFunc(ctx, staleAfter, reason)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.ChatMessage)
-		}
+		r0 = ret.Get(0).(int64)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
-		r1 = returnFunc(ctx, id, date)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Duration, string) error); ok {
+		r1 = returnFunc(ctx, staleAfter, reason)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockChatRepository_GetMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessages'
-type MockChatRepository_GetMessages_Call struct {
+// MockUserImportRepository_FailStaleImportJobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FailStaleImportJobs'
+type MockUserImportRepository_FailStaleImportJobs_Call struct {
 	*mock.Call
 }
 
-// GetMessages is a helper method to define mock.On call
+// FailStaleImportJobs is a helper method to define mock.On call
 //   - ctx
-//   - id
-//   - date
-func (_e *MockChatRepository_Expecter) GetMessages(ctx interface{}, id interface{}, date interface{}) *MockChatRepository_GetMessages_Call {
-	return &MockChatRepository_GetMessages_Call{Call: _e.mock.On("GetMessages", ctx, id, date)}
+//   - staleAfter
+//   - reason
+func (_e *MockUserImportRepository_Expecter) FailStaleImportJobs(ctx interface{}, staleAfter interface{}, reason interface{}) *MockUserImportRepository_FailStaleImportJobs_Call {
+	return &MockUserImportRepository_FailStaleImportJobs_Call{Call: _e.mock.On("FailStaleImportJobs", ctx, staleAfter, reason)}
 }
 
-func (_c *MockChatRepository_GetMessages_Call) Run(run func(ctx context.Context, id int, date string)) *MockChatRepository_GetMessages_Call {
+func (_c *MockUserImportRepository_FailStaleImportJobs_Call) Run(run func(ctx context.Context, staleAfter time.Duration, reason string)) *MockUserImportRepository_FailStaleImportJobs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(string))
+		run(args[0].(context.Context), args[1].(time.Duration), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockChatRepository_GetMessages_Call) Return(chatMessages []models.ChatMessage, err error) *MockChatRepository_GetMessages_Call {
-	_c.Call.Return(chatMessages, err)
+func (_c *MockUserImportRepository_FailStaleImportJobs_Call) Return(n int64, err error) *MockUserImportRepository_FailStaleImportJobs_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *MockChatRepository_GetMessages_Call) RunAndReturn(run func(ctx context.Context, id int, date string) ([]models.ChatMessage, error)) *MockChatRepository_GetMessages_Call {
+func (_c *MockUserImportRepository_FailStaleImportJobs_Call) RunAndReturn(run func(ctx context.Context, staleAfter time.Duration, reason string) (int64, error)) *MockUserImportRepository_FailStaleImportJobs_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMessage provides a mock function for the type MockChatRepository
-func (_mock *MockChatRepository) NewMessage(ctx context.Context, id int, message string, sender string) error {
-	ret := _mock.Called(ctx, id, message, sender)
+// FinishImportJob provides a mock function for the type MockUserImportRepository
+func (_mock *MockUserImportRepository) FinishImportJob(ctx context.Context, id int, report *models.UserImportReport, failure error) error {
+	ret := _mock.Called(ctx, id, report, failure)
 
 	if len(ret) == 0 {
-		panic("no return value specified for NewMessage")
+		panic("no return value specified for FinishImportJob")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, string) error); ok {
-		r0 = returnFunc(ctx, id, message, sender)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, *models.UserImportReport, error) error); ok {
+		r0 = returnFunc(ctx, id, report, failure)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockChatRepository_NewMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NewMessage'
-type MockChatRepository_NewMessage_Call struct {
+// MockUserImportRepository_FinishImportJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FinishImportJob'
+type MockUserImportRepository_FinishImportJob_Call struct {
 	*mock.Call
 }
 
-// NewMessage is a helper method to define mock.On call
+// FinishImportJob is a helper method to define mock.On call
 //   - ctx
 //   - id
-//   - message
-//   - sender
-func (_e *MockChatRepository_Expecter) NewMessage(ctx interface{}, id interface{}, message interface{}, sender interface{}) *MockChatRepository_NewMessage_Call {
-	return &MockChatRepository_NewMessage_Call{Call: _e.mock.On("NewMessage", ctx, id, message, sender)}
+//   - report
+//   - failure
+func (_e *MockUserImportRepository_Expecter) FinishImportJob(ctx interface{}, id interface{}, report interface{}, failure interface{}) *MockUserImportRepository_FinishImportJob_Call {
+	return &MockUserImportRepository_FinishImportJob_Call{Call: _e.mock.On("FinishImportJob", ctx, id, report, failure)}
 }
 
-func (_c *MockChatRepository_NewMessage_Call) Run(run func(ctx context.Context, id int, message string, sender string)) *MockChatRepository_NewMessage_Call {
+func (_c *MockUserImportRepository_FinishImportJob_Call) Run(run func(ctx context.Context, id int, report *models.UserImportReport, failure error)) *MockUserImportRepository_FinishImportJob_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string))
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.UserImportReport), args[3].(error))
 	})
 	return _c
 }
 
-func (_c *MockChatRepository_NewMessage_Call) Return(err error) *MockChatRepository_NewMessage_Call {
+func (_c *MockUserImportRepository_FinishImportJob_Call) Return(err error) *MockUserImportRepository_FinishImportJob_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockChatRepository_NewMessage_Call) RunAndReturn(run func(ctx context.Context, id int, message string, sender string) error) *MockChatRepository_NewMessage_Call {
+func (_c *MockUserImportRepository_FinishImportJob_Call) RunAndReturn(run func(ctx context.Context, id int, report *models.UserImportReport, failure error) error) *MockUserImportRepository_FinishImportJob_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateMessageFeedback provides a mock function for the type MockChatRepository
-func (_mock *MockChatRepository) UpdateMessageFeedback(context1 context.Context, userId int, messageId int, feedback string) error {
-	ret := _mock.Called(context1, userId, messageId, feedback)
+// GetImportJob provides a mock function for the type MockUserImportRepository
+func (_mock *MockUserImportRepository) GetImportJob(ctx context.Context, id int) (*models.UserImportJob, error) {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateMessageFeedback")
+		panic("no return value specified for GetImportJob")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) error); ok {
-		r0 = returnFunc(context1, userId, messageId, feedback)
+	var r0 *models.UserImportJob
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.UserImportJob, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.UserImportJob); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserImportJob)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockChatRepository_UpdateMessageFeedback_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMessageFeedback'
-type MockChatRepository_UpdateMessageFeedback_Call struct {
+// MockUserImportRepository_GetImportJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetImportJob'
+type MockUserImportRepository_GetImportJob_Call struct {
 	*mock.Call
 }
 
-// UpdateMessageFeedback is a helper method to define mock.On call
-//   - context1
-//   - userId
-//   - messageId
-//   - feedback
-func (_e *MockChatRepository_Expecter) UpdateMessageFeedback(context1 interface{}, userId interface{}, messageId interface{}, feedback interface{}) *MockChatRepository_UpdateMessageFeedback_Call {
-	return &MockChatRepository_UpdateMessageFeedback_Call{Call: _e.mock.On("UpdateMessageFeedback", context1, userId, messageId, feedback)}
+// GetImportJob is a helper method to define mock.On call
+//   - ctx
+//   - id
+func (_e *MockUserImportRepository_Expecter) GetImportJob(ctx interface{}, id interface{}) *MockUserImportRepository_GetImportJob_Call {
+	return &MockUserImportRepository_GetImportJob_Call{Call: _e.mock.On("GetImportJob", ctx, id)}
 }
 
-func (_c *MockChatRepository_UpdateMessageFeedback_Call) Run(run func(context1 context.Context, userId int, messageId int, feedback string)) *MockChatRepository_UpdateMessageFeedback_Call {
+func (_c *MockUserImportRepository_GetImportJob_Call) Run(run func(ctx context.Context, id int)) *MockUserImportRepository_GetImportJob_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
+		run(args[0].(context.Context), args[1].(int))
 	})
 	return _c
 }
 
-func (_c *MockChatRepository_UpdateMessageFeedback_Call) Return(err error) *MockChatRepository_UpdateMessageFeedback_Call {
-	_c.Call.Return(err)
+func (_c *MockUserImportRepository_GetImportJob_Call) Return(userImportJob *models.UserImportJob, err error) *MockUserImportRepository_GetImportJob_Call {
+	_c.Call.Return(userImportJob, err)
 	return _c
 }
 
-func (_c *MockChatRepository_UpdateMessageFeedback_Call) RunAndReturn(run func(context1 context.Context, userId int, messageId int, feedback string) error) *MockChatRepository_UpdateMessageFeedback_Call {
+func (_c *MockUserImportRepository_GetImportJob_Call) RunAndReturn(run func(ctx context.Context, id int) (*models.UserImportJob, error)) *MockUserImportRepository_GetImportJob_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateMessageRating provides a mock function for the type MockChatRepository
-func (_mock *MockChatRepository) UpdateMessageRating(context1 context.Context, userId int, messageId int, rating int) error {
-	ret := _mock.Called(context1, userId, messageId, rating)
+// TouchImportJob provides a mock function for the type MockUserImportRepository
+func (_mock *MockUserImportRepository) TouchImportJob(ctx context.Context, id int) error {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateMessageRating")
+		panic("no return value specified for TouchImportJob")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) error); ok {
-		r0 = returnFunc(context1, userId, messageId, rating)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockChatRepository_UpdateMessageRating_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMessageRating'
-type MockChatRepository_UpdateMessageRating_Call struct {
+// MockUserImportRepository_TouchImportJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TouchImportJob'
+type MockUserImportRepository_TouchImportJob_Call struct {
 	*mock.Call
 }
 
-// UpdateMessageRating is a helper method to define mock.On call
-//   - context1
-//   - userId
-//   - messageId
-//   - rating
-func (_e *MockChatRepository_Expecter) UpdateMessageRating(context1 interface{}, userId interface{}, messageId interface{}, rating interface{}) *MockChatRepository_UpdateMessageRating_Call {
-	return &MockChatRepository_UpdateMessageRating_Call{Call: _e.mock.On("UpdateMessageRating", context1, userId, messageId, rating)}
+// TouchImportJob is a helper method to define mock.On call
+//   - ctx
+//   - id
+func (_e *MockUserImportRepository_Expecter) TouchImportJob(ctx interface{}, id interface{}) *MockUserImportRepository_TouchImportJob_Call {
+	return &MockUserImportRepository_TouchImportJob_Call{Call: _e.mock.On("TouchImportJob", ctx, id)}
 }
 
-func (_c *MockChatRepository_UpdateMessageRating_Call) Run(run func(context1 context.Context, userId int, messageId int, rating int)) *MockChatRepository_UpdateMessageRating_Call {
+func (_c *MockUserImportRepository_TouchImportJob_Call) Run(run func(ctx context.Context, id int)) *MockUserImportRepository_TouchImportJob_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+		run(args[0].(context.Context), args[1].(int))
 	})
 	return _c
 }
 
-func (_c *MockChatRepository_UpdateMessageRating_Call) Return(err error) *MockChatRepository_UpdateMessageRating_Call {
+func (_c *MockUserImportRepository_TouchImportJob_Call) Return(err error) *MockUserImportRepository_TouchImportJob_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockChatRepository_UpdateMessageRating_Call) RunAndReturn(run func(context1 context.Context, userId int, messageId int, rating int) error) *MockChatRepository_UpdateMessageRating_Call {
+func (_c *MockUserImportRepository_TouchImportJob_Call) RunAndReturn(run func(ctx context.Context, id int) error) *MockUserImportRepository_TouchImportJob_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockLoginAttemptRepository creates a new instance of MockLoginAttemptRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// NewmockqueryRower creates a new instance of mockqueryRower. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockLoginAttemptRepository(t interface {
+func NewmockqueryRower(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockLoginAttemptRepository {
-	mock := &MockLoginAttemptRepository{}
+}) *mockqueryRower {
+	mock := &mockqueryRower{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -491,658 +4094,755 @@ func NewMockLoginAttemptRepository(t interface {
 	return mock
 }
 
-// MockLoginAttemptRepository is an autogenerated mock type for the LoginAttemptRepository type
-type MockLoginAttemptRepository struct {
+// mockqueryRower is an autogenerated mock type for the queryRower type
+type mockqueryRower struct {
 	mock.Mock
 }
 
-type MockLoginAttemptRepository_Expecter struct {
+type mockqueryRower_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *MockLoginAttemptRepository) EXPECT() *MockLoginAttemptRepository_Expecter {
-	return &MockLoginAttemptRepository_Expecter{mock: &_m.Mock}
+func (_m *mockqueryRower) EXPECT() *mockqueryRower_Expecter {
+	return &mockqueryRower_Expecter{mock: &_m.Mock}
 }
 
-// AddLoginAttempt provides a mock function for the type MockLoginAttemptRepository
-func (_mock *MockLoginAttemptRepository) AddLoginAttempt(ctx context.Context, userID int, ipAddress string, userAgent string, successful bool) error {
-	ret := _mock.Called(ctx, userID, ipAddress, userAgent, successful)
+// QueryRowContext provides a mock function for the type mockqueryRower
+func (_mock *mockqueryRower) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	var tmpRet mock.Arguments
+	if len(args) > 0 {
+		tmpRet = _mock.Called(ctx, query, args)
+	} else {
+		tmpRet = _mock.Called(ctx, query)
+	}
+	ret := tmpRet
 
 	if len(ret) == 0 {
-		panic("no return value specified for AddLoginAttempt")
+		panic("no return value specified for QueryRowContext")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, string, bool) error); ok {
-		r0 = returnFunc(ctx, userID, ipAddress, userAgent, successful)
+	var r0 *sql.Row
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...any) *sql.Row); ok {
+		r0 = returnFunc(ctx, query, args...)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sql.Row)
+		}
 	}
 	return r0
 }
 
-// MockLoginAttemptRepository_AddLoginAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddLoginAttempt'
-type MockLoginAttemptRepository_AddLoginAttempt_Call struct {
+// mockqueryRower_QueryRowContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryRowContext'
+type mockqueryRower_QueryRowContext_Call struct {
 	*mock.Call
 }
 
-// AddLoginAttempt is a helper method to define mock.On call
+// QueryRowContext is a helper method to define mock.On call
 //   - ctx
-//   - userID
-//   - ipAddress
-//   - userAgent
-//   - successful
-func (_e *MockLoginAttemptRepository_Expecter) AddLoginAttempt(ctx interface{}, userID interface{}, ipAddress interface{}, userAgent interface{}, successful interface{}) *MockLoginAttemptRepository_AddLoginAttempt_Call {
-	return &MockLoginAttemptRepository_AddLoginAttempt_Call{Call: _e.mock.On("AddLoginAttempt", ctx, userID, ipAddress, userAgent, successful)}
+//   - query
+//   - args
+func (_e *mockqueryRower_Expecter) QueryRowContext(ctx interface{}, query interface{}, args ...interface{}) *mockqueryRower_QueryRowContext_Call {
+	return &mockqueryRower_QueryRowContext_Call{Call: _e.mock.On("QueryRowContext",
+		append([]interface{}{ctx, query}, args...)...)}
 }
 
-func (_c *MockLoginAttemptRepository_AddLoginAttempt_Call) Run(run func(ctx context.Context, userID int, ipAddress string, userAgent string, successful bool)) *MockLoginAttemptRepository_AddLoginAttempt_Call {
+func (_c *mockqueryRower_QueryRowContext_Call) Run(run func(ctx context.Context, query string, args ...any)) *mockqueryRower_QueryRowContext_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string), args[4].(bool))
+		variadicArgs := make([]any, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(any)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), variadicArgs...)
 	})
 	return _c
 }
 
-func (_c *MockLoginAttemptRepository_AddLoginAttempt_Call) Return(err error) *MockLoginAttemptRepository_AddLoginAttempt_Call {
-	_c.Call.Return(err)
+func (_c *mockqueryRower_QueryRowContext_Call) Return(row *sql.Row) *mockqueryRower_QueryRowContext_Call {
+	_c.Call.Return(row)
 	return _c
 }
 
-func (_c *MockLoginAttemptRepository_AddLoginAttempt_Call) RunAndReturn(run func(ctx context.Context, userID int, ipAddress string, userAgent string, successful bool) error) *MockLoginAttemptRepository_AddLoginAttempt_Call {
+func (_c *mockqueryRower_QueryRowContext_Call) RunAndReturn(run func(ctx context.Context, query string, args ...any) *sql.Row) *mockqueryRower_QueryRowContext_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// BadLoginAttemptsInLast10Minutes provides a mock function for the type MockLoginAttemptRepository
-func (_mock *MockLoginAttemptRepository) BadLoginAttemptsInLast10Minutes(ctx context.Context, userID int) (int, error) {
-	ret := _mock.Called(ctx, userID)
+// NewMockUserRepository creates a new instance of MockUserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserRepository {
+	mock := &MockUserRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockUserRepository is an autogenerated mock type for the UserRepository type
+type MockUserRepository struct {
+	mock.Mock
+}
+
+type MockUserRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUserRepository) EXPECT() *MockUserRepository_Expecter {
+	return &MockUserRepository_Expecter{mock: &_m.Mock}
+}
+
+// AddEmailChangeAttempt provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) AddEmailChangeAttempt(ctx context.Context, id int) (*models.EmailChangeRequest, error) {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for BadLoginAttemptsInLast10Minutes")
+		panic("no return value specified for AddEmailChangeAttempt")
 	}
 
-	var r0 int
+	var r0 *models.EmailChangeRequest
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
-		return returnFunc(ctx, userID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.EmailChangeRequest, error)); ok {
+		return returnFunc(ctx, id)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) int); ok {
-		r0 = returnFunc(ctx, userID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.EmailChangeRequest); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
-		r0 = ret.Get(0).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmailChangeRequest)
+		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = returnFunc(ctx, userID)
+		r1 = returnFunc(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BadLoginAttemptsInLast10Minutes'
-type MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call struct {
+// MockUserRepository_AddEmailChangeAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddEmailChangeAttempt'
+type MockUserRepository_AddEmailChangeAttempt_Call struct {
 	*mock.Call
 }
 
-// BadLoginAttemptsInLast10Minutes is a helper method to define mock.On call
+// AddEmailChangeAttempt is a helper method to define mock.On call
 //   - ctx
-//   - userID
-func (_e *MockLoginAttemptRepository_Expecter) BadLoginAttemptsInLast10Minutes(ctx interface{}, userID interface{}) *MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call {
-	return &MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call{Call: _e.mock.On("BadLoginAttemptsInLast10Minutes", ctx, userID)}
+//   - id
+func (_e *MockUserRepository_Expecter) AddEmailChangeAttempt(ctx interface{}, id interface{}) *MockUserRepository_AddEmailChangeAttempt_Call {
+	return &MockUserRepository_AddEmailChangeAttempt_Call{Call: _e.mock.On("AddEmailChangeAttempt", ctx, id)}
 }
 
-func (_c *MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call) Run(run func(ctx context.Context, userID int)) *MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call {
+func (_c *MockUserRepository_AddEmailChangeAttempt_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_AddEmailChangeAttempt_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(int))
 	})
 	return _c
 }
 
-func (_c *MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call) Return(n int, err error) *MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call {
-	_c.Call.Return(n, err)
+func (_c *MockUserRepository_AddEmailChangeAttempt_Call) Return(emailChangeRequest *models.EmailChangeRequest, err error) *MockUserRepository_AddEmailChangeAttempt_Call {
+	_c.Call.Return(emailChangeRequest, err)
 	return _c
 }
 
-func (_c *MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call) RunAndReturn(run func(ctx context.Context, userID int) (int, error)) *MockLoginAttemptRepository_BadLoginAttemptsInLast10Minutes_Call {
+func (_c *MockUserRepository_AddEmailChangeAttempt_Call) RunAndReturn(run func(ctx context.Context, id int) (*models.EmailChangeRequest, error)) *MockUserRepository_AddEmailChangeAttempt_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetLoginsByUserId provides a mock function for the type MockLoginAttemptRepository
-func (_mock *MockLoginAttemptRepository) GetLoginsByUserId(ctx context.Context, userID int, limit int, offset int) ([]*models.LoginAttempt, error) {
-	ret := _mock.Called(ctx, userID, limit, offset)
+// AddEmailChangeRequest provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) AddEmailChangeRequest(ctx context.Context, request *models.EmailChangeRequest, outbox models.Outbox) (int, error) {
+	ret := _mock.Called(ctx, request, outbox)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetLoginsByUserId")
+		panic("no return value specified for AddEmailChangeRequest")
 	}
 
-	var r0 []*models.LoginAttempt
+	var r0 int
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) ([]*models.LoginAttempt, error)); ok {
-		return returnFunc(ctx, userID, limit, offset)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.EmailChangeRequest, models.Outbox) (int, error)); ok {
+		return returnFunc(ctx, request, outbox)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) []*models.LoginAttempt); ok {
-		r0 = returnFunc(ctx, userID, limit, offset)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.EmailChangeRequest, models.Outbox) int); ok {
+		r0 = returnFunc(ctx, request, outbox)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.LoginAttempt)
-		}
+		r0 = ret.Get(0).(int)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
-		r1 = returnFunc(ctx, userID, limit, offset)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *models.EmailChangeRequest, models.Outbox) error); ok {
+		r1 = returnFunc(ctx, request, outbox)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockLoginAttemptRepository_GetLoginsByUserId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLoginsByUserId'
-type MockLoginAttemptRepository_GetLoginsByUserId_Call struct {
+// MockUserRepository_AddEmailChangeRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddEmailChangeRequest'
+type MockUserRepository_AddEmailChangeRequest_Call struct {
 	*mock.Call
 }
 
-// GetLoginsByUserId is a helper method to define mock.On call
+// AddEmailChangeRequest is a helper method to define mock.On call
 //   - ctx
-//   - userID
-//   - limit
-//   - offset
-func (_e *MockLoginAttemptRepository_Expecter) GetLoginsByUserId(ctx interface{}, userID interface{}, limit interface{}, offset interface{}) *MockLoginAttemptRepository_GetLoginsByUserId_Call {
-	return &MockLoginAttemptRepository_GetLoginsByUserId_Call{Call: _e.mock.On("GetLoginsByUserId", ctx, userID, limit, offset)}
+//   - request
+//   - outbox
+func (_e *MockUserRepository_Expecter) AddEmailChangeRequest(ctx interface{}, request interface{}, outbox interface{}) *MockUserRepository_AddEmailChangeRequest_Call {
+	return &MockUserRepository_AddEmailChangeRequest_Call{Call: _e.mock.On("AddEmailChangeRequest", ctx, request, outbox)}
 }
 
-func (_c *MockLoginAttemptRepository_GetLoginsByUserId_Call) Run(run func(ctx context.Context, userID int, limit int, offset int)) *MockLoginAttemptRepository_GetLoginsByUserId_Call {
+func (_c *MockUserRepository_AddEmailChangeRequest_Call) Run(run func(ctx context.Context, request *models.EmailChangeRequest, outbox models.Outbox)) *MockUserRepository_AddEmailChangeRequest_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+		run(args[0].(context.Context), args[1].(*models.EmailChangeRequest), args[2].(models.Outbox))
 	})
 	return _c
 }
 
-func (_c *MockLoginAttemptRepository_GetLoginsByUserId_Call) Return(loginAttempts []*models.LoginAttempt, err error) *MockLoginAttemptRepository_GetLoginsByUserId_Call {
-	_c.Call.Return(loginAttempts, err)
+func (_c *MockUserRepository_AddEmailChangeRequest_Call) Return(n int, err error) *MockUserRepository_AddEmailChangeRequest_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *MockLoginAttemptRepository_GetLoginsByUserId_Call) RunAndReturn(run func(ctx context.Context, userID int, limit int, offset int) ([]*models.LoginAttempt, error)) *MockLoginAttemptRepository_GetLoginsByUserId_Call {
+func (_c *MockUserRepository_AddEmailChangeRequest_Call) RunAndReturn(run func(ctx context.Context, request *models.EmailChangeRequest, outbox models.Outbox) (int, error)) *MockUserRepository_AddEmailChangeRequest_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockRulesRepository creates a new instance of MockRulesRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func NewMockRulesRepository(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *MockRulesRepository {
-	mock := &MockRulesRepository{}
-	mock.Mock.Test(t)
+// AddNotificationToken provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) AddNotificationToken(ctx context.Context, id int, text string) error {
+	ret := _mock.Called(ctx, id, text)
 
-	t.Cleanup(func() { mock.AssertExpectations(t) })
+	if len(ret) == 0 {
+		panic("no return value specified for AddNotificationToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, id, text)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_AddNotificationToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddNotificationToken'
+type MockUserRepository_AddNotificationToken_Call struct {
+	*mock.Call
+}
 
-	return mock
+// AddNotificationToken is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - text
+func (_e *MockUserRepository_Expecter) AddNotificationToken(ctx interface{}, id interface{}, text interface{}) *MockUserRepository_AddNotificationToken_Call {
+	return &MockUserRepository_AddNotificationToken_Call{Call: _e.mock.On("AddNotificationToken", ctx, id, text)}
 }
 
-// MockRulesRepository is an autogenerated mock type for the RulesRepository type
-type MockRulesRepository struct {
-	mock.Mock
+func (_c *MockUserRepository_AddNotificationToken_Call) Run(run func(ctx context.Context, id int, text string)) *MockUserRepository_AddNotificationToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
 }
 
-type MockRulesRepository_Expecter struct {
-	mock *mock.Mock
+func (_c *MockUserRepository_AddNotificationToken_Call) Return(err error) *MockUserRepository_AddNotificationToken_Call {
+	_c.Call.Return(err)
+	return _c
 }
 
-func (_m *MockRulesRepository) EXPECT() *MockRulesRepository_Expecter {
-	return &MockRulesRepository_Expecter{mock: &_m.Mock}
+func (_c *MockUserRepository_AddNotificationToken_Call) RunAndReturn(run func(ctx context.Context, id int, text string) error) *MockUserRepository_AddNotificationToken_Call {
+	_c.Call.Return(run)
+	return _c
 }
 
-// AddRule provides a mock function for the type MockRulesRepository
-func (_mock *MockRulesRepository) AddRule(ctx context.Context, rule models.Rule, userId int) error {
-	ret := _mock.Called(ctx, rule, userId)
+// AddNotificationType provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) AddNotificationType(ctx context.Context, notificationType *models.NotificationType) error {
+	ret := _mock.Called(ctx, notificationType)
 
 	if len(ret) == 0 {
-		panic("no return value specified for AddRule")
+		panic("no return value specified for AddNotificationType")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Rule, int) error); ok {
-		r0 = returnFunc(ctx, rule, userId)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.NotificationType) error); ok {
+		r0 = returnFunc(ctx, notificationType)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRulesRepository_AddRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddRule'
-type MockRulesRepository_AddRule_Call struct {
+// MockUserRepository_AddNotificationType_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddNotificationType'
+type MockUserRepository_AddNotificationType_Call struct {
 	*mock.Call
 }
 
-// AddRule is a helper method to define mock.On call
+// AddNotificationType is a helper method to define mock.On call
 //   - ctx
-//   - rule
-//   - userId
-func (_e *MockRulesRepository_Expecter) AddRule(ctx interface{}, rule interface{}, userId interface{}) *MockRulesRepository_AddRule_Call {
-	return &MockRulesRepository_AddRule_Call{Call: _e.mock.On("AddRule", ctx, rule, userId)}
+//   - notificationType
+func (_e *MockUserRepository_Expecter) AddNotificationType(ctx interface{}, notificationType interface{}) *MockUserRepository_AddNotificationType_Call {
+	return &MockUserRepository_AddNotificationType_Call{Call: _e.mock.On("AddNotificationType", ctx, notificationType)}
 }
 
-func (_c *MockRulesRepository_AddRule_Call) Run(run func(ctx context.Context, rule models.Rule, userId int)) *MockRulesRepository_AddRule_Call {
+func (_c *MockUserRepository_AddNotificationType_Call) Run(run func(ctx context.Context, notificationType *models.NotificationType)) *MockUserRepository_AddNotificationType_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(models.Rule), args[2].(int))
+		run(args[0].(context.Context), args[1].(*models.NotificationType))
 	})
 	return _c
 }
 
-func (_c *MockRulesRepository_AddRule_Call) Return(err error) *MockRulesRepository_AddRule_Call {
+func (_c *MockUserRepository_AddNotificationType_Call) Return(err error) *MockUserRepository_AddNotificationType_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRulesRepository_AddRule_Call) RunAndReturn(run func(ctx context.Context, rule models.Rule, userId int) error) *MockRulesRepository_AddRule_Call {
+func (_c *MockUserRepository_AddNotificationType_Call) RunAndReturn(run func(ctx context.Context, notificationType *models.NotificationType) error) *MockUserRepository_AddNotificationType_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteRule provides a mock function for the type MockRulesRepository
-func (_mock *MockRulesRepository) DeleteRule(ctx context.Context, ruleId int, userId int) error {
-	ret := _mock.Called(ctx, ruleId, userId)
+// AddPasswordResetAttempt provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) AddPasswordResetAttempt(ctx context.Context, id int) (*models.PasswordResetData, error) {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteRule")
+		panic("no return value specified for AddPasswordResetAttempt")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
-		r0 = returnFunc(ctx, ruleId, userId)
+	var r0 *models.PasswordResetData
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.PasswordResetData, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.PasswordResetData); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PasswordResetData)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockRulesRepository_DeleteRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRule'
-type MockRulesRepository_DeleteRule_Call struct {
+// MockUserRepository_AddPasswordResetAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddPasswordResetAttempt'
+type MockUserRepository_AddPasswordResetAttempt_Call struct {
 	*mock.Call
 }
 
-// DeleteRule is a helper method to define mock.On call
+// AddPasswordResetAttempt is a helper method to define mock.On call
 //   - ctx
-//   - ruleId
-//   - userId
-func (_e *MockRulesRepository_Expecter) DeleteRule(ctx interface{}, ruleId interface{}, userId interface{}) *MockRulesRepository_DeleteRule_Call {
-	return &MockRulesRepository_DeleteRule_Call{Call: _e.mock.On("DeleteRule", ctx, ruleId, userId)}
+//   - id
+func (_e *MockUserRepository_Expecter) AddPasswordResetAttempt(ctx interface{}, id interface{}) *MockUserRepository_AddPasswordResetAttempt_Call {
+	return &MockUserRepository_AddPasswordResetAttempt_Call{Call: _e.mock.On("AddPasswordResetAttempt", ctx, id)}
 }
 
-func (_c *MockRulesRepository_DeleteRule_Call) Run(run func(ctx context.Context, ruleId int, userId int)) *MockRulesRepository_DeleteRule_Call {
+func (_c *MockUserRepository_AddPasswordResetAttempt_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_AddPasswordResetAttempt_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(int))
+		run(args[0].(context.Context), args[1].(int))
 	})
 	return _c
 }
 
-func (_c *MockRulesRepository_DeleteRule_Call) Return(err error) *MockRulesRepository_DeleteRule_Call {
-	_c.Call.Return(err)
+func (_c *MockUserRepository_AddPasswordResetAttempt_Call) Return(passwordResetData *models.PasswordResetData, err error) *MockUserRepository_AddPasswordResetAttempt_Call {
+	_c.Call.Return(passwordResetData, err)
 	return _c
 }
 
-func (_c *MockRulesRepository_DeleteRule_Call) RunAndReturn(run func(ctx context.Context, ruleId int, userId int) error) *MockRulesRepository_DeleteRule_Call {
+func (_c *MockUserRepository_AddPasswordResetAttempt_Call) RunAndReturn(run func(ctx context.Context, id int) (*models.PasswordResetData, error)) *MockUserRepository_AddPasswordResetAttempt_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetAudit provides a mock function for the type MockRulesRepository
-func (_mock *MockRulesRepository) GetAudit(ctx context.Context) ([]models.Audit, error) {
-	ret := _mock.Called(ctx)
+// AddPasswordResetToken provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) AddPasswordResetToken(ctx context.Context, id int, email string, tokenHash string, tokenExpiration time.Time, outbox models.Outbox) (int, error) {
+	ret := _mock.Called(ctx, id, email, tokenHash, tokenExpiration, outbox)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAudit")
+		panic("no return value specified for AddPasswordResetToken")
 	}
 
-	var r0 []models.Audit
+	var r0 int
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.Audit, error)); ok {
-		return returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, string, time.Time, models.Outbox) (int, error)); ok {
+		return returnFunc(ctx, id, email, tokenHash, tokenExpiration, outbox)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.Audit); ok {
-		r0 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, string, time.Time, models.Outbox) int); ok {
+		r0 = returnFunc(ctx, id, email, tokenHash, tokenExpiration, outbox)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Audit)
-		}
+		r0 = ret.Get(0).(int)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, string, string, time.Time, models.Outbox) error); ok {
+		r1 = returnFunc(ctx, id, email, tokenHash, tokenExpiration, outbox)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRulesRepository_GetAudit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAudit'
-type MockRulesRepository_GetAudit_Call struct {
+// MockUserRepository_AddPasswordResetToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddPasswordResetToken'
+type MockUserRepository_AddPasswordResetToken_Call struct {
 	*mock.Call
 }
 
-// GetAudit is a helper method to define mock.On call
+// AddPasswordResetToken is a helper method to define mock.On call
 //   - ctx
-func (_e *MockRulesRepository_Expecter) GetAudit(ctx interface{}) *MockRulesRepository_GetAudit_Call {
-	return &MockRulesRepository_GetAudit_Call{Call: _e.mock.On("GetAudit", ctx)}
+//   - id
+//   - email
+//   - tokenHash
+//   - tokenExpiration
+//   - outbox
+func (_e *MockUserRepository_Expecter) AddPasswordResetToken(ctx interface{}, id interface{}, email interface{}, tokenHash interface{}, tokenExpiration interface{}, outbox interface{}) *MockUserRepository_AddPasswordResetToken_Call {
+	return &MockUserRepository_AddPasswordResetToken_Call{Call: _e.mock.On("AddPasswordResetToken", ctx, id, email, tokenHash, tokenExpiration, outbox)}
 }
 
-func (_c *MockRulesRepository_GetAudit_Call) Run(run func(ctx context.Context)) *MockRulesRepository_GetAudit_Call {
+func (_c *MockUserRepository_AddPasswordResetToken_Call) Run(run func(ctx context.Context, id int, email string, tokenHash string, tokenExpiration time.Time, outbox models.Outbox)) *MockUserRepository_AddPasswordResetToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string), args[4].(time.Time), args[5].(models.Outbox))
 	})
 	return _c
 }
 
-func (_c *MockRulesRepository_GetAudit_Call) Return(audits []models.Audit, err error) *MockRulesRepository_GetAudit_Call {
-	_c.Call.Return(audits, err)
+func (_c *MockUserRepository_AddPasswordResetToken_Call) Return(n int, err error) *MockUserRepository_AddPasswordResetToken_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *MockRulesRepository_GetAudit_Call) RunAndReturn(run func(ctx context.Context) ([]models.Audit, error)) *MockRulesRepository_GetAudit_Call {
+func (_c *MockUserRepository_AddPasswordResetToken_Call) RunAndReturn(run func(ctx context.Context, id int, email string, tokenHash string, tokenExpiration time.Time, outbox models.Outbox) (int, error)) *MockUserRepository_AddPasswordResetToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetRules provides a mock function for the type MockRulesRepository
-func (_mock *MockRulesRepository) GetRules(ctx context.Context) ([]models.Rule, error) {
-	ret := _mock.Called(ctx)
+// AddUser provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) AddUser(ctx context.Context, user *models.User) (int, error) {
+	ret := _mock.Called(ctx, user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetRules")
+		panic("no return value specified for AddUser")
 	}
 
-	var r0 []models.Rule
+	var r0 int
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.Rule, error)); ok {
-		return returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.User) (int, error)); ok {
+		return returnFunc(ctx, user)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.Rule); ok {
-		r0 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.User) int); ok {
+		r0 = returnFunc(ctx, user)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Rule)
-		}
+		r0 = ret.Get(0).(int)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *models.User) error); ok {
+		r1 = returnFunc(ctx, user)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRulesRepository_GetRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRules'
-type MockRulesRepository_GetRules_Call struct {
+// MockUserRepository_AddUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddUser'
+type MockUserRepository_AddUser_Call struct {
 	*mock.Call
 }
 
-// GetRules is a helper method to define mock.On call
+// AddUser is a helper method to define mock.On call
 //   - ctx
-func (_e *MockRulesRepository_Expecter) GetRules(ctx interface{}) *MockRulesRepository_GetRules_Call {
-	return &MockRulesRepository_GetRules_Call{Call: _e.mock.On("GetRules", ctx)}
+//   - user
+func (_e *MockUserRepository_Expecter) AddUser(ctx interface{}, user interface{}) *MockUserRepository_AddUser_Call {
+	return &MockUserRepository_AddUser_Call{Call: _e.mock.On("AddUser", ctx, user)}
 }
 
-func (_c *MockRulesRepository_GetRules_Call) Run(run func(ctx context.Context)) *MockRulesRepository_GetRules_Call {
+func (_c *MockUserRepository_AddUser_Call) Run(run func(ctx context.Context, user *models.User)) *MockUserRepository_AddUser_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(*models.User))
 	})
 	return _c
 }
 
-func (_c *MockRulesRepository_GetRules_Call) Return(rules []models.Rule, err error) *MockRulesRepository_GetRules_Call {
-	_c.Call.Return(rules, err)
+func (_c *MockUserRepository_AddUser_Call) Return(n int, err error) *MockUserRepository_AddUser_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *MockRulesRepository_GetRules_Call) RunAndReturn(run func(ctx context.Context) ([]models.Rule, error)) *MockRulesRepository_GetRules_Call {
+func (_c *MockUserRepository_AddUser_Call) RunAndReturn(run func(ctx context.Context, user *models.User) (int, error)) *MockUserRepository_AddUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ModifyRule provides a mock function for the type MockRulesRepository
-func (_mock *MockRulesRepository) ModifyRule(ctx context.Context, ruleId int, modification models.RuleModify, userId int) error {
-	ret := _mock.Called(ctx, ruleId, modification, userId)
+// ApplyEmailChange provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) ApplyEmailChange(ctx context.Context, request *models.EmailChangeRequest) error {
+	ret := _mock.Called(ctx, request)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ModifyRule")
+		panic("no return value specified for ApplyEmailChange")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.RuleModify, int) error); ok {
-		r0 = returnFunc(ctx, ruleId, modification, userId)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.EmailChangeRequest) error); ok {
+		r0 = returnFunc(ctx, request)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRulesRepository_ModifyRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ModifyRule'
-type MockRulesRepository_ModifyRule_Call struct {
+// MockUserRepository_ApplyEmailChange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyEmailChange'
+type MockUserRepository_ApplyEmailChange_Call struct {
 	*mock.Call
 }
 
-// ModifyRule is a helper method to define mock.On call
+// ApplyEmailChange is a helper method to define mock.On call
 //   - ctx
-//   - ruleId
-//   - modification
-//   - userId
-func (_e *MockRulesRepository_Expecter) ModifyRule(ctx interface{}, ruleId interface{}, modification interface{}, userId interface{}) *MockRulesRepository_ModifyRule_Call {
-	return &MockRulesRepository_ModifyRule_Call{Call: _e.mock.On("ModifyRule", ctx, ruleId, modification, userId)}
+//   - request
+func (_e *MockUserRepository_Expecter) ApplyEmailChange(ctx interface{}, request interface{}) *MockUserRepository_ApplyEmailChange_Call {
+	return &MockUserRepository_ApplyEmailChange_Call{Call: _e.mock.On("ApplyEmailChange", ctx, request)}
 }
 
-func (_c *MockRulesRepository_ModifyRule_Call) Run(run func(ctx context.Context, ruleId int, modification models.RuleModify, userId int)) *MockRulesRepository_ModifyRule_Call {
+func (_c *MockUserRepository_ApplyEmailChange_Call) Run(run func(ctx context.Context, request *models.EmailChangeRequest)) *MockUserRepository_ApplyEmailChange_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(models.RuleModify), args[3].(int))
+		run(args[0].(context.Context), args[1].(*models.EmailChangeRequest))
 	})
 	return _c
 }
 
-func (_c *MockRulesRepository_ModifyRule_Call) Return(err error) *MockRulesRepository_ModifyRule_Call {
+func (_c *MockUserRepository_ApplyEmailChange_Call) Return(err error) *MockUserRepository_ApplyEmailChange_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRulesRepository_ModifyRule_Call) RunAndReturn(run func(ctx context.Context, ruleId int, modification models.RuleModify, userId int) error) *MockRulesRepository_ModifyRule_Call {
+func (_c *MockUserRepository_ApplyEmailChange_Call) RunAndReturn(run func(ctx context.Context, request *models.EmailChangeRequest) error) *MockUserRepository_ApplyEmailChange_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockUserRepository creates a new instance of MockUserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func NewMockUserRepository(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *MockUserRepository {
-	mock := &MockUserRepository{}
-	mock.Mock.Test(t)
+// DeleteEmailChangeRequestByCancelToken provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) DeleteEmailChangeRequestByCancelToken(ctx context.Context, tokenHash string) error {
+	ret := _mock.Called(ctx, tokenHash)
 
-	t.Cleanup(func() { mock.AssertExpectations(t) })
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteEmailChangeRequestByCancelToken")
+	}
 
-	return mock
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, tokenHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
 }
 
-// MockUserRepository is an autogenerated mock type for the UserRepository type
-type MockUserRepository struct {
-	mock.Mock
+// MockUserRepository_DeleteEmailChangeRequestByCancelToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteEmailChangeRequestByCancelToken'
+type MockUserRepository_DeleteEmailChangeRequestByCancelToken_Call struct {
+	*mock.Call
 }
 
-type MockUserRepository_Expecter struct {
-	mock *mock.Mock
+// DeleteEmailChangeRequestByCancelToken is a helper method to define mock.On call
+//   - ctx
+//   - tokenHash
+func (_e *MockUserRepository_Expecter) DeleteEmailChangeRequestByCancelToken(ctx interface{}, tokenHash interface{}) *MockUserRepository_DeleteEmailChangeRequestByCancelToken_Call {
+	return &MockUserRepository_DeleteEmailChangeRequestByCancelToken_Call{Call: _e.mock.On("DeleteEmailChangeRequestByCancelToken", ctx, tokenHash)}
 }
 
-func (_m *MockUserRepository) EXPECT() *MockUserRepository_Expecter {
-	return &MockUserRepository_Expecter{mock: &_m.Mock}
+func (_c *MockUserRepository_DeleteEmailChangeRequestByCancelToken_Call) Run(run func(ctx context.Context, tokenHash string)) *MockUserRepository_DeleteEmailChangeRequestByCancelToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
 }
 
-// AddNotificationToken provides a mock function for the type MockUserRepository
-func (_mock *MockUserRepository) AddNotificationToken(ctx context.Context, id int, text string) error {
-	ret := _mock.Called(ctx, id, text)
+func (_c *MockUserRepository_DeleteEmailChangeRequestByCancelToken_Call) Return(err error) *MockUserRepository_DeleteEmailChangeRequestByCancelToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_DeleteEmailChangeRequestByCancelToken_Call) RunAndReturn(run func(ctx context.Context, tokenHash string) error) *MockUserRepository_DeleteEmailChangeRequestByCancelToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteUser provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) DeleteUser(ctx context.Context, id int, version int) error {
+	ret := _mock.Called(ctx, id, version)
 
 	if len(ret) == 0 {
-		panic("no return value specified for AddNotificationToken")
+		panic("no return value specified for DeleteUser")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
-		r0 = returnFunc(ctx, id, text)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = returnFunc(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockUserRepository_AddNotificationToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddNotificationToken'
-type MockUserRepository_AddNotificationToken_Call struct {
+// MockUserRepository_DeleteUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUser'
+type MockUserRepository_DeleteUser_Call struct {
 	*mock.Call
 }
 
-// AddNotificationToken is a helper method to define mock.On call
+// DeleteUser is a helper method to define mock.On call
 //   - ctx
 //   - id
-//   - text
-func (_e *MockUserRepository_Expecter) AddNotificationToken(ctx interface{}, id interface{}, text interface{}) *MockUserRepository_AddNotificationToken_Call {
-	return &MockUserRepository_AddNotificationToken_Call{Call: _e.mock.On("AddNotificationToken", ctx, id, text)}
+//   - version
+func (_e *MockUserRepository_Expecter) DeleteUser(ctx interface{}, id interface{}, version interface{}) *MockUserRepository_DeleteUser_Call {
+	return &MockUserRepository_DeleteUser_Call{Call: _e.mock.On("DeleteUser", ctx, id, version)}
 }
 
-func (_c *MockUserRepository_AddNotificationToken_Call) Run(run func(ctx context.Context, id int, text string)) *MockUserRepository_AddNotificationToken_Call {
+func (_c *MockUserRepository_DeleteUser_Call) Run(run func(ctx context.Context, id int, version int)) *MockUserRepository_DeleteUser_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(string))
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
 	})
 	return _c
 }
 
-func (_c *MockUserRepository_AddNotificationToken_Call) Return(err error) *MockUserRepository_AddNotificationToken_Call {
+func (_c *MockUserRepository_DeleteUser_Call) Return(err error) *MockUserRepository_DeleteUser_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserRepository_AddNotificationToken_Call) RunAndReturn(run func(ctx context.Context, id int, text string) error) *MockUserRepository_AddNotificationToken_Call {
+func (_c *MockUserRepository_DeleteUser_Call) RunAndReturn(run func(ctx context.Context, id int, version int) error) *MockUserRepository_DeleteUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// AddPasswordResetToken provides a mock function for the type MockUserRepository
-func (_mock *MockUserRepository) AddPasswordResetToken(ctx context.Context, id int, email string, token string, tokenExpiration time.Time) error {
-	ret := _mock.Called(ctx, id, email, token, tokenExpiration)
+// EmailExists provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	ret := _mock.Called(ctx, email)
 
 	if len(ret) == 0 {
-		panic("no return value specified for AddPasswordResetToken")
+		panic("no return value specified for EmailExists")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, string, time.Time) error); ok {
-		r0 = returnFunc(ctx, id, email, token, tokenExpiration)
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return returnFunc(ctx, email)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = returnFunc(ctx, email)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(bool)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockUserRepository_AddPasswordResetToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddPasswordResetToken'
-type MockUserRepository_AddPasswordResetToken_Call struct {
+// MockUserRepository_EmailExists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EmailExists'
+type MockUserRepository_EmailExists_Call struct {
 	*mock.Call
 }
 
-// AddPasswordResetToken is a helper method to define mock.On call
+// EmailExists is a helper method to define mock.On call
 //   - ctx
-//   - id
 //   - email
-//   - token
-//   - tokenExpiration
-func (_e *MockUserRepository_Expecter) AddPasswordResetToken(ctx interface{}, id interface{}, email interface{}, token interface{}, tokenExpiration interface{}) *MockUserRepository_AddPasswordResetToken_Call {
-	return &MockUserRepository_AddPasswordResetToken_Call{Call: _e.mock.On("AddPasswordResetToken", ctx, id, email, token, tokenExpiration)}
+func (_e *MockUserRepository_Expecter) EmailExists(ctx interface{}, email interface{}) *MockUserRepository_EmailExists_Call {
+	return &MockUserRepository_EmailExists_Call{Call: _e.mock.On("EmailExists", ctx, email)}
 }
 
-func (_c *MockUserRepository_AddPasswordResetToken_Call) Run(run func(ctx context.Context, id int, email string, token string, tokenExpiration time.Time)) *MockUserRepository_AddPasswordResetToken_Call {
+func (_c *MockUserRepository_EmailExists_Call) Run(run func(ctx context.Context, email string)) *MockUserRepository_EmailExists_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string), args[4].(time.Time))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockUserRepository_AddPasswordResetToken_Call) Return(err error) *MockUserRepository_AddPasswordResetToken_Call {
-	_c.Call.Return(err)
+func (_c *MockUserRepository_EmailExists_Call) Return(b bool, err error) *MockUserRepository_EmailExists_Call {
+	_c.Call.Return(b, err)
 	return _c
 }
 
-func (_c *MockUserRepository_AddPasswordResetToken_Call) RunAndReturn(run func(ctx context.Context, id int, email string, token string, tokenExpiration time.Time) error) *MockUserRepository_AddPasswordResetToken_Call {
+func (_c *MockUserRepository_EmailExists_Call) RunAndReturn(run func(ctx context.Context, email string) (bool, error)) *MockUserRepository_EmailExists_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// AddUser provides a mock function for the type MockUserRepository
-func (_mock *MockUserRepository) AddUser(ctx context.Context, user *models.User) (int, error) {
-	ret := _mock.Called(ctx, user)
+// GetAllUsers provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) GetAllUsers(ctx context.Context, filter models.UserFilter) ([]models.User, error) {
+	ret := _mock.Called(ctx, filter)
 
 	if len(ret) == 0 {
-		panic("no return value specified for AddUser")
+		panic("no return value specified for GetAllUsers")
 	}
 
-	var r0 int
+	var r0 []models.User
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.User) (int, error)); ok {
-		return returnFunc(ctx, user)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.UserFilter) ([]models.User, error)); ok {
+		return returnFunc(ctx, filter)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.User) int); ok {
-		r0 = returnFunc(ctx, user)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.UserFilter) []models.User); ok {
+		r0 = returnFunc(ctx, filter)
 	} else {
-		r0 = ret.Get(0).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.User)
+		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *models.User) error); ok {
-		r1 = returnFunc(ctx, user)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.UserFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockUserRepository_AddUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddUser'
-type MockUserRepository_AddUser_Call struct {
+// MockUserRepository_GetAllUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllUsers'
+type MockUserRepository_GetAllUsers_Call struct {
 	*mock.Call
 }
 
-// AddUser is a helper method to define mock.On call
+// GetAllUsers is a helper method to define mock.On call
 //   - ctx
-//   - user
-func (_e *MockUserRepository_Expecter) AddUser(ctx interface{}, user interface{}) *MockUserRepository_AddUser_Call {
-	return &MockUserRepository_AddUser_Call{Call: _e.mock.On("AddUser", ctx, user)}
+//   - filter
+func (_e *MockUserRepository_Expecter) GetAllUsers(ctx interface{}, filter interface{}) *MockUserRepository_GetAllUsers_Call {
+	return &MockUserRepository_GetAllUsers_Call{Call: _e.mock.On("GetAllUsers", ctx, filter)}
 }
 
-func (_c *MockUserRepository_AddUser_Call) Run(run func(ctx context.Context, user *models.User)) *MockUserRepository_AddUser_Call {
+func (_c *MockUserRepository_GetAllUsers_Call) Run(run func(ctx context.Context, filter models.UserFilter)) *MockUserRepository_GetAllUsers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*models.User))
+		run(args[0].(context.Context), args[1].(models.UserFilter))
 	})
 	return _c
 }
 
-func (_c *MockUserRepository_AddUser_Call) Return(n int, err error) *MockUserRepository_AddUser_Call {
-	_c.Call.Return(n, err)
+func (_c *MockUserRepository_GetAllUsers_Call) Return(users []models.User, err error) *MockUserRepository_GetAllUsers_Call {
+	_c.Call.Return(users, err)
 	return _c
 }
 
-func (_c *MockUserRepository_AddUser_Call) RunAndReturn(run func(ctx context.Context, user *models.User) (int, error)) *MockUserRepository_AddUser_Call {
+func (_c *MockUserRepository_GetAllUsers_Call) RunAndReturn(run func(ctx context.Context, filter models.UserFilter) ([]models.User, error)) *MockUserRepository_GetAllUsers_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CheckPreference provides a mock function for the type MockUserRepository
-func (_mock *MockUserRepository) CheckPreference(ctx context.Context, id int, notificationType string) (bool, error) {
+// GetNotificationChannels provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) GetNotificationChannels(ctx context.Context, id int, notificationType string) (map[string]bool, error) {
 	ret := _mock.Called(ctx, id, notificationType)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CheckPreference")
+		panic("no return value specified for GetNotificationChannels")
 	}
 
-	var r0 bool
+	var r0 map[string]bool
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) (bool, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) (map[string]bool, error)); ok {
 		return returnFunc(ctx, id, notificationType)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) bool); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) map[string]bool); ok {
 		r0 = returnFunc(ctx, id, notificationType)
 	} else {
-		r0 = ret.Get(0).(bool)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]bool)
+		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
 		r1 = returnFunc(ctx, id, notificationType)
@@ -1152,100 +4852,111 @@ func (_mock *MockUserRepository) CheckPreference(ctx context.Context, id int, no
 	return r0, r1
 }
 
-// MockUserRepository_CheckPreference_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckPreference'
-type MockUserRepository_CheckPreference_Call struct {
+// MockUserRepository_GetNotificationChannels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNotificationChannels'
+type MockUserRepository_GetNotificationChannels_Call struct {
 	*mock.Call
 }
 
-// CheckPreference is a helper method to define mock.On call
+// GetNotificationChannels is a helper method to define mock.On call
 //   - ctx
 //   - id
 //   - notificationType
-func (_e *MockUserRepository_Expecter) CheckPreference(ctx interface{}, id interface{}, notificationType interface{}) *MockUserRepository_CheckPreference_Call {
-	return &MockUserRepository_CheckPreference_Call{Call: _e.mock.On("CheckPreference", ctx, id, notificationType)}
+func (_e *MockUserRepository_Expecter) GetNotificationChannels(ctx interface{}, id interface{}, notificationType interface{}) *MockUserRepository_GetNotificationChannels_Call {
+	return &MockUserRepository_GetNotificationChannels_Call{Call: _e.mock.On("GetNotificationChannels", ctx, id, notificationType)}
 }
 
-func (_c *MockUserRepository_CheckPreference_Call) Run(run func(ctx context.Context, id int, notificationType string)) *MockUserRepository_CheckPreference_Call {
+func (_c *MockUserRepository_GetNotificationChannels_Call) Run(run func(ctx context.Context, id int, notificationType string)) *MockUserRepository_GetNotificationChannels_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(int), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockUserRepository_CheckPreference_Call) Return(b bool, err error) *MockUserRepository_CheckPreference_Call {
-	_c.Call.Return(b, err)
+func (_c *MockUserRepository_GetNotificationChannels_Call) Return(stringToBool map[string]bool, err error) *MockUserRepository_GetNotificationChannels_Call {
+	_c.Call.Return(stringToBool, err)
 	return _c
 }
 
-func (_c *MockUserRepository_CheckPreference_Call) RunAndReturn(run func(ctx context.Context, id int, notificationType string) (bool, error)) *MockUserRepository_CheckPreference_Call {
+func (_c *MockUserRepository_GetNotificationChannels_Call) RunAndReturn(run func(ctx context.Context, id int, notificationType string) (map[string]bool, error)) *MockUserRepository_GetNotificationChannels_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteUser provides a mock function for the type MockUserRepository
-func (_mock *MockUserRepository) DeleteUser(ctx context.Context, id int) error {
+// GetNotificationPreferences provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) GetNotificationPreferences(ctx context.Context, id int) (models.NotificationPreferences, error) {
 	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteUser")
+		panic("no return value specified for GetNotificationPreferences")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+	var r0 models.NotificationPreferences
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (models.NotificationPreferences, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) models.NotificationPreferences); ok {
 		r0 = returnFunc(ctx, id)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(models.NotificationPreferences)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockUserRepository_DeleteUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUser'
-type MockUserRepository_DeleteUser_Call struct {
+// MockUserRepository_GetNotificationPreferences_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNotificationPreferences'
+type MockUserRepository_GetNotificationPreferences_Call struct {
 	*mock.Call
 }
 
-// DeleteUser is a helper method to define mock.On call
+// GetNotificationPreferences is a helper method to define mock.On call
 //   - ctx
 //   - id
-func (_e *MockUserRepository_Expecter) DeleteUser(ctx interface{}, id interface{}) *MockUserRepository_DeleteUser_Call {
-	return &MockUserRepository_DeleteUser_Call{Call: _e.mock.On("DeleteUser", ctx, id)}
+func (_e *MockUserRepository_Expecter) GetNotificationPreferences(ctx interface{}, id interface{}) *MockUserRepository_GetNotificationPreferences_Call {
+	return &MockUserRepository_GetNotificationPreferences_Call{Call: _e.mock.On("GetNotificationPreferences", ctx, id)}
 }
 
-func (_c *MockUserRepository_DeleteUser_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_DeleteUser_Call {
+func (_c *MockUserRepository_GetNotificationPreferences_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_GetNotificationPreferences_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(int))
 	})
 	return _c
 }
 
-func (_c *MockUserRepository_DeleteUser_Call) Return(err error) *MockUserRepository_DeleteUser_Call {
-	_c.Call.Return(err)
+func (_c *MockUserRepository_GetNotificationPreferences_Call) Return(notificationPreferences models.NotificationPreferences, err error) *MockUserRepository_GetNotificationPreferences_Call {
+	_c.Call.Return(notificationPreferences, err)
 	return _c
 }
 
-func (_c *MockUserRepository_DeleteUser_Call) RunAndReturn(run func(ctx context.Context, id int) error) *MockUserRepository_DeleteUser_Call {
+func (_c *MockUserRepository_GetNotificationPreferences_Call) RunAndReturn(run func(ctx context.Context, id int) (models.NotificationPreferences, error)) *MockUserRepository_GetNotificationPreferences_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetAllUsers provides a mock function for the type MockUserRepository
-func (_mock *MockUserRepository) GetAllUsers(ctx context.Context) ([]models.User, error) {
+// GetNotificationTypes provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) GetNotificationTypes(ctx context.Context) ([]models.NotificationType, error) {
 	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAllUsers")
+		panic("no return value specified for GetNotificationTypes")
 	}
 
-	var r0 []models.User
+	var r0 []models.NotificationType
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.User, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.NotificationType, error)); ok {
 		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.User); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.NotificationType); ok {
 		r0 = returnFunc(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.User)
+			r0 = ret.Get(0).([]models.NotificationType)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
@@ -1256,144 +4967,145 @@ func (_mock *MockUserRepository) GetAllUsers(ctx context.Context) ([]models.User
 	return r0, r1
 }
 
-// MockUserRepository_GetAllUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllUsers'
-type MockUserRepository_GetAllUsers_Call struct {
+// MockUserRepository_GetNotificationTypes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNotificationTypes'
+type MockUserRepository_GetNotificationTypes_Call struct {
 	*mock.Call
 }
 
-// GetAllUsers is a helper method to define mock.On call
+// GetNotificationTypes is a helper method to define mock.On call
 //   - ctx
-func (_e *MockUserRepository_Expecter) GetAllUsers(ctx interface{}) *MockUserRepository_GetAllUsers_Call {
-	return &MockUserRepository_GetAllUsers_Call{Call: _e.mock.On("GetAllUsers", ctx)}
+func (_e *MockUserRepository_Expecter) GetNotificationTypes(ctx interface{}) *MockUserRepository_GetNotificationTypes_Call {
+	return &MockUserRepository_GetNotificationTypes_Call{Call: _e.mock.On("GetNotificationTypes", ctx)}
 }
 
-func (_c *MockUserRepository_GetAllUsers_Call) Run(run func(ctx context.Context)) *MockUserRepository_GetAllUsers_Call {
+func (_c *MockUserRepository_GetNotificationTypes_Call) Run(run func(ctx context.Context)) *MockUserRepository_GetNotificationTypes_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context))
 	})
 	return _c
 }
 
-func (_c *MockUserRepository_GetAllUsers_Call) Return(users []models.User, err error) *MockUserRepository_GetAllUsers_Call {
-	_c.Call.Return(users, err)
+func (_c *MockUserRepository_GetNotificationTypes_Call) Return(notificationTypes []models.NotificationType, err error) *MockUserRepository_GetNotificationTypes_Call {
+	_c.Call.Return(notificationTypes, err)
 	return _c
 }
 
-func (_c *MockUserRepository_GetAllUsers_Call) RunAndReturn(run func(ctx context.Context) ([]models.User, error)) *MockUserRepository_GetAllUsers_Call {
+func (_c *MockUserRepository_GetNotificationTypes_Call) RunAndReturn(run func(ctx context.Context) ([]models.NotificationType, error)) *MockUserRepository_GetNotificationTypes_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetNotificationPreference provides a mock function for the type MockUserRepository
-func (_mock *MockUserRepository) GetNotificationPreference(ctx context.Context, id int) (*models.NotificationPreference, error) {
-	ret := _mock.Called(ctx, id)
+// GetPasswordHistory provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) GetPasswordHistory(ctx context.Context, id int, limit int) ([]string, error) {
+	ret := _mock.Called(ctx, id, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetNotificationPreference")
+		panic("no return value specified for GetPasswordHistory")
 	}
 
-	var r0 *models.NotificationPreference
+	var r0 []string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.NotificationPreference, error)); ok {
-		return returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]string, error)); ok {
+		return returnFunc(ctx, id, limit)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.NotificationPreference); ok {
-		r0 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []string); ok {
+		r0 = returnFunc(ctx, id, limit)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.NotificationPreference)
+			r0 = ret.Get(0).([]string)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = returnFunc(ctx, id, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockUserRepository_GetNotificationPreference_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNotificationPreference'
-type MockUserRepository_GetNotificationPreference_Call struct {
+// MockUserRepository_GetPasswordHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPasswordHistory'
+type MockUserRepository_GetPasswordHistory_Call struct {
 	*mock.Call
 }
 
-// GetNotificationPreference is a helper method to define mock.On call
+// GetPasswordHistory is a helper method to define mock.On call
 //   - ctx
 //   - id
-func (_e *MockUserRepository_Expecter) GetNotificationPreference(ctx interface{}, id interface{}) *MockUserRepository_GetNotificationPreference_Call {
-	return &MockUserRepository_GetNotificationPreference_Call{Call: _e.mock.On("GetNotificationPreference", ctx, id)}
+//   - limit
+func (_e *MockUserRepository_Expecter) GetPasswordHistory(ctx interface{}, id interface{}, limit interface{}) *MockUserRepository_GetPasswordHistory_Call {
+	return &MockUserRepository_GetPasswordHistory_Call{Call: _e.mock.On("GetPasswordHistory", ctx, id, limit)}
 }
 
-func (_c *MockUserRepository_GetNotificationPreference_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_GetNotificationPreference_Call {
+func (_c *MockUserRepository_GetPasswordHistory_Call) Run(run func(ctx context.Context, id int, limit int)) *MockUserRepository_GetPasswordHistory_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
 	})
 	return _c
 }
 
-func (_c *MockUserRepository_GetNotificationPreference_Call) Return(notificationPreference *models.NotificationPreference, err error) *MockUserRepository_GetNotificationPreference_Call {
-	_c.Call.Return(notificationPreference, err)
+func (_c *MockUserRepository_GetPasswordHistory_Call) Return(strings []string, err error) *MockUserRepository_GetPasswordHistory_Call {
+	_c.Call.Return(strings, err)
 	return _c
 }
 
-func (_c *MockUserRepository_GetNotificationPreference_Call) RunAndReturn(run func(ctx context.Context, id int) (*models.NotificationPreference, error)) *MockUserRepository_GetNotificationPreference_Call {
+func (_c *MockUserRepository_GetPasswordHistory_Call) RunAndReturn(run func(ctx context.Context, id int, limit int) ([]string, error)) *MockUserRepository_GetPasswordHistory_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetPasswordResetTokenInfo provides a mock function for the type MockUserRepository
-func (_mock *MockUserRepository) GetPasswordResetTokenInfo(ctx context.Context, token string) (*models.PasswordResetData, error) {
-	ret := _mock.Called(ctx, token)
+// GetSessionsValidAfter provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) GetSessionsValidAfter(ctx context.Context, id int) (*time.Time, error) {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPasswordResetTokenInfo")
+		panic("no return value specified for GetSessionsValidAfter")
 	}
 
-	var r0 *models.PasswordResetData
+	var r0 *time.Time
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.PasswordResetData, error)); ok {
-		return returnFunc(ctx, token)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*time.Time, error)); ok {
+		return returnFunc(ctx, id)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.PasswordResetData); ok {
-		r0 = returnFunc(ctx, token)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *time.Time); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.PasswordResetData)
+			r0 = ret.Get(0).(*time.Time)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, token)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockUserRepository_GetPasswordResetTokenInfo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPasswordResetTokenInfo'
-type MockUserRepository_GetPasswordResetTokenInfo_Call struct {
+// MockUserRepository_GetSessionsValidAfter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSessionsValidAfter'
+type MockUserRepository_GetSessionsValidAfter_Call struct {
 	*mock.Call
 }
 
-// GetPasswordResetTokenInfo is a helper method to define mock.On call
+// GetSessionsValidAfter is a helper method to define mock.On call
 //   - ctx
-//   - token
-func (_e *MockUserRepository_Expecter) GetPasswordResetTokenInfo(ctx interface{}, token interface{}) *MockUserRepository_GetPasswordResetTokenInfo_Call {
-	return &MockUserRepository_GetPasswordResetTokenInfo_Call{Call: _e.mock.On("GetPasswordResetTokenInfo", ctx, token)}
+//   - id
+func (_e *MockUserRepository_Expecter) GetSessionsValidAfter(ctx interface{}, id interface{}) *MockUserRepository_GetSessionsValidAfter_Call {
+	return &MockUserRepository_GetSessionsValidAfter_Call{Call: _e.mock.On("GetSessionsValidAfter", ctx, id)}
 }
 
-func (_c *MockUserRepository_GetPasswordResetTokenInfo_Call) Run(run func(ctx context.Context, token string)) *MockUserRepository_GetPasswordResetTokenInfo_Call {
+func (_c *MockUserRepository_GetSessionsValidAfter_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_GetSessionsValidAfter_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(int))
 	})
 	return _c
 }
 
-func (_c *MockUserRepository_GetPasswordResetTokenInfo_Call) Return(passwordResetData *models.PasswordResetData, err error) *MockUserRepository_GetPasswordResetTokenInfo_Call {
-	_c.Call.Return(passwordResetData, err)
+func (_c *MockUserRepository_GetSessionsValidAfter_Call) Return(time1 *time.Time, err error) *MockUserRepository_GetSessionsValidAfter_Call {
+	_c.Call.Return(time1, err)
 	return _c
 }
 
-func (_c *MockUserRepository_GetPasswordResetTokenInfo_Call) RunAndReturn(run func(ctx context.Context, token string) (*models.PasswordResetData, error)) *MockUserRepository_GetPasswordResetTokenInfo_Call {
+func (_c *MockUserRepository_GetSessionsValidAfter_Call) RunAndReturn(run func(ctx context.Context, id int) (*time.Time, error)) *MockUserRepository_GetSessionsValidAfter_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1706,95 +5418,237 @@ func (_c *MockUserRepository_ModifyUser_Call) RunAndReturn(run func(ctx context.
 	return _c
 }
 
-// SetNotificationPreference provides a mock function for the type MockUserRepository
-func (_mock *MockUserRepository) SetNotificationPreference(ctx context.Context, id int, preference models.NotificationPreferenceRequest) error {
-	ret := _mock.Called(ctx, id, preference)
+// RehashPassword provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) RehashPassword(ctx context.Context, id int, currentHash string, newHash string) error {
+	ret := _mock.Called(ctx, id, currentHash, newHash)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SetNotificationPreference")
+		panic("no return value specified for RehashPassword")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.NotificationPreferenceRequest) error); ok {
-		r0 = returnFunc(ctx, id, preference)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, string) error); ok {
+		r0 = returnFunc(ctx, id, currentHash, newHash)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockUserRepository_SetNotificationPreference_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetNotificationPreference'
-type MockUserRepository_SetNotificationPreference_Call struct {
+// MockUserRepository_RehashPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RehashPassword'
+type MockUserRepository_RehashPassword_Call struct {
 	*mock.Call
 }
 
-// SetNotificationPreference is a helper method to define mock.On call
+// RehashPassword is a helper method to define mock.On call
 //   - ctx
 //   - id
-//   - preference
-func (_e *MockUserRepository_Expecter) SetNotificationPreference(ctx interface{}, id interface{}, preference interface{}) *MockUserRepository_SetNotificationPreference_Call {
-	return &MockUserRepository_SetNotificationPreference_Call{Call: _e.mock.On("SetNotificationPreference", ctx, id, preference)}
+//   - currentHash
+//   - newHash
+func (_e *MockUserRepository_Expecter) RehashPassword(ctx interface{}, id interface{}, currentHash interface{}, newHash interface{}) *MockUserRepository_RehashPassword_Call {
+	return &MockUserRepository_RehashPassword_Call{Call: _e.mock.On("RehashPassword", ctx, id, currentHash, newHash)}
 }
 
-func (_c *MockUserRepository_SetNotificationPreference_Call) Run(run func(ctx context.Context, id int, preference models.NotificationPreferenceRequest)) *MockUserRepository_SetNotificationPreference_Call {
+func (_c *MockUserRepository_RehashPassword_Call) Run(run func(ctx context.Context, id int, currentHash string, newHash string)) *MockUserRepository_RehashPassword_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(models.NotificationPreferenceRequest))
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string))
 	})
 	return _c
 }
 
-func (_c *MockUserRepository_SetNotificationPreference_Call) Return(err error) *MockUserRepository_SetNotificationPreference_Call {
+func (_c *MockUserRepository_RehashPassword_Call) Return(err error) *MockUserRepository_RehashPassword_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserRepository_SetNotificationPreference_Call) RunAndReturn(run func(ctx context.Context, id int, preference models.NotificationPreferenceRequest) error) *MockUserRepository_SetNotificationPreference_Call {
+func (_c *MockUserRepository_RehashPassword_Call) RunAndReturn(run func(ctx context.Context, id int, currentHash string, newHash string) error) *MockUserRepository_RehashPassword_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SetPasswordTokenUsed provides a mock function for the type MockUserRepository
-func (_mock *MockUserRepository) SetPasswordTokenUsed(ctx context.Context, token string) error {
-	ret := _mock.Called(ctx, token)
+// ReleaseEmailChangeAttempt provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) ReleaseEmailChangeAttempt(ctx context.Context, id int) error {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SetPasswordTokenUsed")
+		panic("no return value specified for ReleaseEmailChangeAttempt")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = returnFunc(ctx, token)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockUserRepository_SetPasswordTokenUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetPasswordTokenUsed'
-type MockUserRepository_SetPasswordTokenUsed_Call struct {
+// MockUserRepository_ReleaseEmailChangeAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseEmailChangeAttempt'
+type MockUserRepository_ReleaseEmailChangeAttempt_Call struct {
 	*mock.Call
 }
 
-// SetPasswordTokenUsed is a helper method to define mock.On call
+// ReleaseEmailChangeAttempt is a helper method to define mock.On call
 //   - ctx
-//   - token
-func (_e *MockUserRepository_Expecter) SetPasswordTokenUsed(ctx interface{}, token interface{}) *MockUserRepository_SetPasswordTokenUsed_Call {
-	return &MockUserRepository_SetPasswordTokenUsed_Call{Call: _e.mock.On("SetPasswordTokenUsed", ctx, token)}
+//   - id
+func (_e *MockUserRepository_Expecter) ReleaseEmailChangeAttempt(ctx interface{}, id interface{}) *MockUserRepository_ReleaseEmailChangeAttempt_Call {
+	return &MockUserRepository_ReleaseEmailChangeAttempt_Call{Call: _e.mock.On("ReleaseEmailChangeAttempt", ctx, id)}
 }
 
-func (_c *MockUserRepository_SetPasswordTokenUsed_Call) Run(run func(ctx context.Context, token string)) *MockUserRepository_SetPasswordTokenUsed_Call {
+func (_c *MockUserRepository_ReleaseEmailChangeAttempt_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_ReleaseEmailChangeAttempt_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ReleaseEmailChangeAttempt_Call) Return(err error) *MockUserRepository_ReleaseEmailChangeAttempt_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_ReleaseEmailChangeAttempt_Call) RunAndReturn(run func(ctx context.Context, id int) error) *MockUserRepository_ReleaseEmailChangeAttempt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleasePasswordResetAttempt provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) ReleasePasswordResetAttempt(ctx context.Context, id int) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleasePasswordResetAttempt")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_ReleasePasswordResetAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleasePasswordResetAttempt'
+type MockUserRepository_ReleasePasswordResetAttempt_Call struct {
+	*mock.Call
+}
+
+// ReleasePasswordResetAttempt is a helper method to define mock.On call
+//   - ctx
+//   - id
+func (_e *MockUserRepository_Expecter) ReleasePasswordResetAttempt(ctx interface{}, id interface{}) *MockUserRepository_ReleasePasswordResetAttempt_Call {
+	return &MockUserRepository_ReleasePasswordResetAttempt_Call{Call: _e.mock.On("ReleasePasswordResetAttempt", ctx, id)}
+}
+
+func (_c *MockUserRepository_ReleasePasswordResetAttempt_Call) Run(run func(ctx context.Context, id int)) *MockUserRepository_ReleasePasswordResetAttempt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ReleasePasswordResetAttempt_Call) Return(err error) *MockUserRepository_ReleasePasswordResetAttempt_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_ReleasePasswordResetAttempt_Call) RunAndReturn(run func(ctx context.Context, id int) error) *MockUserRepository_ReleasePasswordResetAttempt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetPassword provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) ResetPassword(ctx context.Context, tokenId int, userId int, password string) error {
+	ret := _mock.Called(ctx, tokenId, userId, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) error); ok {
+		r0 = returnFunc(ctx, tokenId, userId, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
+type MockUserRepository_ResetPassword_Call struct {
+	*mock.Call
+}
+
+// ResetPassword is a helper method to define mock.On call
+//   - ctx
+//   - tokenId
+//   - userId
+//   - password
+func (_e *MockUserRepository_Expecter) ResetPassword(ctx interface{}, tokenId interface{}, userId interface{}, password interface{}) *MockUserRepository_ResetPassword_Call {
+	return &MockUserRepository_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, tokenId, userId, password)}
+}
+
+func (_c *MockUserRepository_ResetPassword_Call) Run(run func(ctx context.Context, tokenId int, userId int, password string)) *MockUserRepository_ResetPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ResetPassword_Call) Return(err error) *MockUserRepository_ResetPassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_ResetPassword_Call) RunAndReturn(run func(ctx context.Context, tokenId int, userId int, password string) error) *MockUserRepository_ResetPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetNotificationPreferences provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) SetNotificationPreferences(ctx context.Context, id int, preferences models.NotificationPreferences) error {
+	ret := _mock.Called(ctx, id, preferences)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetNotificationPreferences")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.NotificationPreferences) error); ok {
+		r0 = returnFunc(ctx, id, preferences)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_SetNotificationPreferences_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetNotificationPreferences'
+type MockUserRepository_SetNotificationPreferences_Call struct {
+	*mock.Call
+}
+
+// SetNotificationPreferences is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - preferences
+func (_e *MockUserRepository_Expecter) SetNotificationPreferences(ctx interface{}, id interface{}, preferences interface{}) *MockUserRepository_SetNotificationPreferences_Call {
+	return &MockUserRepository_SetNotificationPreferences_Call{Call: _e.mock.On("SetNotificationPreferences", ctx, id, preferences)}
+}
+
+func (_c *MockUserRepository_SetNotificationPreferences_Call) Run(run func(ctx context.Context, id int, preferences models.NotificationPreferences)) *MockUserRepository_SetNotificationPreferences_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.NotificationPreferences))
 	})
 	return _c
 }
 
-func (_c *MockUserRepository_SetPasswordTokenUsed_Call) Return(err error) *MockUserRepository_SetPasswordTokenUsed_Call {
+func (_c *MockUserRepository_SetNotificationPreferences_Call) Return(err error) *MockUserRepository_SetNotificationPreferences_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserRepository_SetPasswordTokenUsed_Call) RunAndReturn(run func(ctx context.Context, token string) error) *MockUserRepository_SetPasswordTokenUsed_Call {
+func (_c *MockUserRepository_SetNotificationPreferences_Call) RunAndReturn(run func(ctx context.Context, id int, preferences models.NotificationPreferences) error) *MockUserRepository_SetNotificationPreferences_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1845,6 +5699,53 @@ func (_c *MockUserRepository_SetVerifiedTrue_Call) RunAndReturn(run func(ctx con
 	return _c
 }
 
+// StreamUsers provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) StreamUsers(ctx context.Context, filter models.UserFilter, fn func(models.User) error) error {
+	ret := _mock.Called(ctx, filter, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamUsers")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.UserFilter, func(models.User) error) error); ok {
+		r0 = returnFunc(ctx, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_StreamUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StreamUsers'
+type MockUserRepository_StreamUsers_Call struct {
+	*mock.Call
+}
+
+// StreamUsers is a helper method to define mock.On call
+//   - ctx
+//   - filter
+//   - fn
+func (_e *MockUserRepository_Expecter) StreamUsers(ctx interface{}, filter interface{}, fn interface{}) *MockUserRepository_StreamUsers_Call {
+	return &MockUserRepository_StreamUsers_Call{Call: _e.mock.On("StreamUsers", ctx, filter, fn)}
+}
+
+func (_c *MockUserRepository_StreamUsers_Call) Run(run func(ctx context.Context, filter models.UserFilter, fn func(models.User) error)) *MockUserRepository_StreamUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.UserFilter), args[2].(func(models.User) error))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_StreamUsers_Call) Return(err error) *MockUserRepository_StreamUsers_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_StreamUsers_Call) RunAndReturn(run func(ctx context.Context, filter models.UserFilter, fn func(models.User) error) error) *MockUserRepository_StreamUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockVerificationRepository creates a new instance of MockVerificationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockVerificationRepository(t interface {
@@ -1873,8 +5774,8 @@ func (_m *MockVerificationRepository) EXPECT() *MockVerificationRepository_Expec
 }
 
 // AddPendingVerification provides a mock function for the type MockVerificationRepository
-func (_mock *MockVerificationRepository) AddPendingVerification(ctx context.Context, verification *models.UserVerification) (int, error) {
-	ret := _mock.Called(ctx, verification)
+func (_mock *MockVerificationRepository) AddPendingVerification(ctx context.Context, verification *models.UserVerification, outbox models.Outbox) (int, error) {
+	ret := _mock.Called(ctx, verification, outbox)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddPendingVerification")
@@ -1882,16 +5783,16 @@ func (_mock *MockVerificationRepository) AddPendingVerification(ctx context.Cont
 
 	var r0 int
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.UserVerification) (int, error)); ok {
-		return returnFunc(ctx, verification)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.UserVerification, models.Outbox) (int, error)); ok {
+		return returnFunc(ctx, verification, outbox)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.UserVerification) int); ok {
-		r0 = returnFunc(ctx, verification)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.UserVerification, models.Outbox) int); ok {
+		r0 = returnFunc(ctx, verification, outbox)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *models.UserVerification) error); ok {
-		r1 = returnFunc(ctx, verification)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *models.UserVerification, models.Outbox) error); ok {
+		r1 = returnFunc(ctx, verification, outbox)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1906,13 +5807,14 @@ type MockVerificationRepository_AddPendingVerification_Call struct {
 // AddPendingVerification is a helper method to define mock.On call
 //   - ctx
 //   - verification
-func (_e *MockVerificationRepository_Expecter) AddPendingVerification(ctx interface{}, verification interface{}) *MockVerificationRepository_AddPendingVerification_Call {
-	return &MockVerificationRepository_AddPendingVerification_Call{Call: _e.mock.On("AddPendingVerification", ctx, verification)}
+//   - outbox
+func (_e *MockVerificationRepository_Expecter) AddPendingVerification(ctx interface{}, verification interface{}, outbox interface{}) *MockVerificationRepository_AddPendingVerification_Call {
+	return &MockVerificationRepository_AddPendingVerification_Call{Call: _e.mock.On("AddPendingVerification", ctx, verification, outbox)}
 }
 
-func (_c *MockVerificationRepository_AddPendingVerification_Call) Run(run func(ctx context.Context, verification *models.UserVerification)) *MockVerificationRepository_AddPendingVerification_Call {
+func (_c *MockVerificationRepository_AddPendingVerification_Call) Run(run func(ctx context.Context, verification *models.UserVerification, outbox models.Outbox)) *MockVerificationRepository_AddPendingVerification_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*models.UserVerification))
+		run(args[0].(context.Context), args[1].(*models.UserVerification), args[2].(models.Outbox))
 	})
 	return _c
 }
@@ -1922,7 +5824,64 @@ func (_c *MockVerificationRepository_AddPendingVerification_Call) Return(n int,
 	return _c
 }
 
-func (_c *MockVerificationRepository_AddPendingVerification_Call) RunAndReturn(run func(ctx context.Context, verification *models.UserVerification) (int, error)) *MockVerificationRepository_AddPendingVerification_Call {
+func (_c *MockVerificationRepository_AddPendingVerification_Call) RunAndReturn(run func(ctx context.Context, verification *models.UserVerification, outbox models.Outbox) (int, error)) *MockVerificationRepository_AddPendingVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddUserPendingVerification provides a mock function for the type MockVerificationRepository
+func (_mock *MockVerificationRepository) AddUserPendingVerification(ctx context.Context, user *models.User, verification *models.UserVerification, outbox models.Outbox) (int, error) {
+	ret := _mock.Called(ctx, user, verification, outbox)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddUserPendingVerification")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.User, *models.UserVerification, models.Outbox) (int, error)); ok {
+		return returnFunc(ctx, user, verification, outbox)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.User, *models.UserVerification, models.Outbox) int); ok {
+		r0 = returnFunc(ctx, user, verification, outbox)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *models.User, *models.UserVerification, models.Outbox) error); ok {
+		r1 = returnFunc(ctx, user, verification, outbox)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockVerificationRepository_AddUserPendingVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddUserPendingVerification'
+type MockVerificationRepository_AddUserPendingVerification_Call struct {
+	*mock.Call
+}
+
+// AddUserPendingVerification is a helper method to define mock.On call
+//   - ctx
+//   - user
+//   - verification
+//   - outbox
+func (_e *MockVerificationRepository_Expecter) AddUserPendingVerification(ctx interface{}, user interface{}, verification interface{}, outbox interface{}) *MockVerificationRepository_AddUserPendingVerification_Call {
+	return &MockVerificationRepository_AddUserPendingVerification_Call{Call: _e.mock.On("AddUserPendingVerification", ctx, user, verification, outbox)}
+}
+
+func (_c *MockVerificationRepository_AddUserPendingVerification_Call) Run(run func(ctx context.Context, user *models.User, verification *models.UserVerification, outbox models.Outbox)) *MockVerificationRepository_AddUserPendingVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.User), args[2].(*models.UserVerification), args[3].(models.Outbox))
+	})
+	return _c
+}
+
+func (_c *MockVerificationRepository_AddUserPendingVerification_Call) Return(n int, err error) *MockVerificationRepository_AddUserPendingVerification_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockVerificationRepository_AddUserPendingVerification_Call) RunAndReturn(run func(ctx context.Context, user *models.User, verification *models.UserVerification, outbox models.Outbox) (int, error)) *MockVerificationRepository_AddUserPendingVerification_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -2088,16 +6047,16 @@ func (_c *MockVerificationRepository_GetVerificationById_Call) RunAndReturn(run
 }
 
 // UpdatePin provides a mock function for the type MockVerificationRepository
-func (_mock *MockVerificationRepository) UpdatePin(ctx context.Context, userId int, pin string) error {
-	ret := _mock.Called(ctx, userId, pin)
+func (_mock *MockVerificationRepository) UpdatePin(ctx context.Context, id int, pin string, outbox models.Outbox) error {
+	ret := _mock.Called(ctx, id, pin, outbox)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdatePin")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
-		r0 = returnFunc(ctx, userId, pin)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, models.Outbox) error); ok {
+		r0 = returnFunc(ctx, id, pin, outbox)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -2111,15 +6070,16 @@ type MockVerificationRepository_UpdatePin_Call struct {
 
 // UpdatePin is a helper method to define mock.On call
 //   - ctx
-//   - userId
+//   - id
 //   - pin
-func (_e *MockVerificationRepository_Expecter) UpdatePin(ctx interface{}, userId interface{}, pin interface{}) *MockVerificationRepository_UpdatePin_Call {
-	return &MockVerificationRepository_UpdatePin_Call{Call: _e.mock.On("UpdatePin", ctx, userId, pin)}
+//   - outbox
+func (_e *MockVerificationRepository_Expecter) UpdatePin(ctx interface{}, id interface{}, pin interface{}, outbox interface{}) *MockVerificationRepository_UpdatePin_Call {
+	return &MockVerificationRepository_UpdatePin_Call{Call: _e.mock.On("UpdatePin", ctx, id, pin, outbox)}
 }
 
-func (_c *MockVerificationRepository_UpdatePin_Call) Run(run func(ctx context.Context, userId int, pin string)) *MockVerificationRepository_UpdatePin_Call {
+func (_c *MockVerificationRepository_UpdatePin_Call) Run(run func(ctx context.Context, id int, pin string, outbox models.Outbox)) *MockVerificationRepository_UpdatePin_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(string))
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(models.Outbox))
 	})
 	return _c
 }
@@ -2129,7 +6089,7 @@ func (_c *MockVerificationRepository_UpdatePin_Call) Return(err error) *MockVeri
 	return _c
 }
 
-func (_c *MockVerificationRepository_UpdatePin_Call) RunAndReturn(run func(ctx context.Context, userId int, pin string) error) *MockVerificationRepository_UpdatePin_Call {
+func (_c *MockVerificationRepository_UpdatePin_Call) RunAndReturn(run func(ctx context.Context, id int, pin string, outbox models.Outbox) error) *MockVerificationRepository_UpdatePin_Call {
 	_c.Call.Return(run)
 	return _c
 }
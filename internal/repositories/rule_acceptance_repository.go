@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+)
+
+// RuleAcceptanceRepository keeps which versions of the rules each user accepted
+type RuleAcceptanceRepository interface {
+	// AddAcceptance records the acceptance, accepting the same version again does nothing
+	AddAcceptance(ctx context.Context, acceptance models.RuleAcceptance) error
+	// GetPendingRules returns the active mandatory rules the user hasn't accepted in their current version
+	GetPendingRules(ctx context.Context, userId int) ([]models.PendingRule, error)
+	// GetAcceptanceCoverage returns how many users are bound by the rules and how many accepted each active rule
+	GetAcceptanceCoverage(ctx context.Context) (int, []models.RuleAcceptanceCoverage, error)
+}
+
+type ruleAcceptanceRepository struct {
+	DB *sql.DB
+}
+
+func NewRuleAcceptanceRepository(db *sql.DB) *ruleAcceptanceRepository {
+	return &ruleAcceptanceRepository{DB: db}
+}
+
+// boundUsers are the users the rules apply to, admins manage them and aren't asked to accept them
+const boundUsers = "deleted_at IS NULL AND role <> 'admin'"
+
+func (db ruleAcceptanceRepository) AddAcceptance(ctx context.Context, acceptance models.RuleAcceptance) error {
+	_, err := db.DB.ExecContext(ctx, `
+		INSERT INTO rule_acceptances (user_id, rule_id, rule_version, ip, accepted_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, rule_id, rule_version) DO NOTHING`,
+		acceptance.UserId, acceptance.RuleId, acceptance.RuleVersion, acceptance.IP, acceptance.AcceptedAt)
+	return err
+}
+
+func (db ruleAcceptanceRepository) GetPendingRules(ctx context.Context, userId int) ([]models.PendingRule, error) {
+	rows, err := db.DB.QueryContext(ctx, `
+		SELECT `+ruleColumns+`,
+			COALESCE((SELECT MAX(a.rule_version) FROM rule_acceptances a WHERE a.user_id = $1 AND a.rule_id = rules.id), 0)
+		FROM rules
+		WHERE status = $2 AND mandatory AND NOT EXISTS (
+			SELECT 1 FROM rule_acceptances a WHERE a.user_id = $1 AND a.rule_id = rules.id AND a.rule_version = rules.version)
+		ORDER BY id`, userId, models.RuleActive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pending := []models.PendingRule{}
+	for rows.Next() {
+		var rule models.PendingRule
+		var err error
+		rule.Rule, err = scanRule(rows, &rule.AcceptedVersion)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, rule)
+	}
+	return pending, rows.Err()
+}
+
+func (db ruleAcceptanceRepository) GetAcceptanceCoverage(ctx context.Context) (int, []models.RuleAcceptanceCoverage, error) {
+	var users int
+	if err := db.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM users WHERE "+boundUsers).Scan(&users); err != nil {
+		return 0, nil, err
+	}
+
+	// Each user is counted once per rule, by the latest version they accepted
+	rows, err := db.DB.QueryContext(ctx, `
+		SELECT r.id, r.title, r.version, r.mandatory,
+			COUNT(latest.user_id) FILTER (WHERE latest.rule_version = r.version),
+			COUNT(latest.user_id) FILTER (WHERE latest.rule_version < r.version)
+		FROM rules r
+		LEFT JOIN (
+			SELECT a.rule_id, a.user_id, MAX(a.rule_version) AS rule_version
+			FROM rule_acceptances a JOIN users u ON u.id = a.user_id
+			WHERE u.`+boundUsers+`
+			GROUP BY a.rule_id, a.user_id
+		) latest ON latest.rule_id = r.id
+		WHERE r.status = $1
+		GROUP BY r.id
+		ORDER BY r.id`, models.RuleActive)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	coverage := []models.RuleAcceptanceCoverage{}
+	for rows.Next() {
+		var rule models.RuleAcceptanceCoverage
+		if err := rows.Scan(&rule.RuleId, &rule.Title, &rule.Version, &rule.Mandatory, &rule.Accepted, &rule.AcceptedEarlierVersion); err != nil {
+			return 0, nil, err
+		}
+		coverage = append(coverage, rule)
+	}
+	return users, coverage, rows.Err()
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleAcceptanceRepository_AddAcceptance(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRuleAcceptanceRepository(db)
+	acceptance := models.RuleAcceptance{UserId: 7, RuleId: 1, RuleVersion: 3, IP: "10.0.0.1", AcceptedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+
+	mock.ExpectExec(`INSERT INTO rule_acceptances \(user_id, rule_id, rule_version, ip, accepted_at\) VALUES \(\$1, \$2, \$3, \$4, \$5\) ON CONFLICT \(user_id, rule_id, rule_version\) DO NOTHING`).
+		WithArgs(7, 1, 3, "10.0.0.1", acceptance.AcceptedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.AddAcceptance(context.Background(), acceptance))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRuleAcceptanceRepository_GetPendingRules(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRuleAcceptanceRepository(db)
+	effectiveDate := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`FROM rules WHERE status = \$2 AND mandatory AND NOT EXISTS`).
+		WithArgs(7, models.RuleActive).
+		WillReturnRows(sqlmock.NewRows(append(ruleRowColumns, "accepted_version")).
+			AddRow(1, "Terms of service", "Be nice", effectiveDate, "always", 4, models.RuleActive, nil, true, models.RuleGeneral, "{}", "{}", 2).
+			AddRow(2, "Attendance", "Attend classes", effectiveDate, "always", 1, models.RuleActive, nil, true, models.RuleGeneral, "{}", "{}", 0))
+
+	pending, err := repo.GetPendingRules(context.Background(), 7)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, 4, pending[0].Version)
+	assert.Equal(t, 2, pending[0].AcceptedVersion)
+	assert.True(t, pending[0].Mandatory)
+	assert.Equal(t, 0, pending[1].AcceptedVersion)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRuleAcceptanceRepository_GetAcceptanceCoverage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRuleAcceptanceRepository(db)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users WHERE deleted_at IS NULL AND role <> 'admin'`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(10))
+	mock.ExpectQuery(`FROM rules r LEFT JOIN .* WHERE r.status = \$1 GROUP BY r.id ORDER BY r.id`).
+		WithArgs(models.RuleActive).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "version", "mandatory", "accepted", "accepted_earlier_version"}).
+			AddRow(1, "Terms of service", 4, true, 6, 3))
+
+	users, coverage, err := repo.GetAcceptanceCoverage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 10, users)
+	assert.Equal(t, []models.RuleAcceptanceCoverage{{RuleId: 1, Title: "Terms of service", Version: 4, Mandatory: true, Accepted: 6, AcceptedEarlierVersion: 3}}, coverage)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,238 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+)
+
+// RuleProposalRepository keeps the changes to the rules waiting for a second admin in four-eyes mode
+type RuleProposalRepository interface {
+	AddProposal(ctx context.Context, proposal models.RuleProposal) (*models.RuleProposal, error)
+	// GetProposal returns the proposal with its comments, oldest first
+	GetProposal(ctx context.Context, proposalId int) (*models.RuleProposal, error)
+	// GetProposals returns the proposals matching the filter without their comments, newest first
+	GetProposals(ctx context.Context, filter models.RuleProposalListRequest) ([]models.RuleProposal, error)
+	AddComment(ctx context.Context, comment models.RuleProposalComment) (*models.RuleProposalComment, error)
+	// ApproveProposal applies the pending proposal and marks it approved in a single transaction.
+	// The rules audit records who proposed the change and who approved it.
+	ApproveProposal(ctx context.Context, proposalId int, approverId int) (*models.RuleProposal, error)
+	RejectProposal(ctx context.Context, proposalId int, reviewerId int, reason string) (*models.RuleProposal, error)
+}
+
+type ruleProposalRepository struct {
+	DB *sql.DB
+}
+
+func NewRuleProposalRepository(db *sql.DB) *ruleProposalRepository {
+	return &ruleProposalRepository{DB: db}
+}
+
+const ruleProposalColumns = "id, action, COALESCE(rule_id, 0), COALESCE(rule_version, 0), payload, status, proposed_by, " +
+	"COALESCE(reviewed_by, 0), COALESCE(review_reason, ''), created_at, reviewed_at"
+
+func scanRuleProposal(row rowScanner) (models.RuleProposal, error) {
+	var proposal models.RuleProposal
+	var payload []byte
+	var reviewedAt sql.NullTime
+	err := row.Scan(&proposal.Id, &proposal.Action, &proposal.RuleId, &proposal.RuleVersion, &payload, &proposal.Status,
+		&proposal.ProposedBy, &proposal.ReviewedBy, &proposal.ReviewReason, &proposal.CreatedAt, &reviewedAt)
+	if err != nil {
+		return proposal, err
+	}
+	if reviewedAt.Valid {
+		proposal.ReviewedAt = &reviewedAt.Time
+	}
+	if payload != nil {
+		err = proposal.SetPayload(payload)
+	}
+	return proposal, err
+}
+
+// nullableId stores 0 as NULL
+func nullableId(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
+
+func (db ruleProposalRepository) AddProposal(ctx context.Context, proposal models.RuleProposal) (*models.RuleProposal, error) {
+	payload, err := proposal.Payload()
+	if err != nil {
+		return nil, err
+	}
+	added, err := scanRuleProposal(db.DB.QueryRowContext(ctx, `
+		INSERT INTO rule_proposals (action, rule_id, rule_version, payload, proposed_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+ruleProposalColumns,
+		proposal.Action, nullableId(proposal.RuleId), nullableId(proposal.RuleVersion), payload, proposal.ProposedBy))
+	if err != nil {
+		return nil, err
+	}
+	return &added, nil
+}
+
+func (db ruleProposalRepository) GetProposal(ctx context.Context, proposalId int) (*models.RuleProposal, error) {
+	proposal, err := scanRuleProposal(db.DB.QueryRowContext(ctx, "SELECT "+ruleProposalColumns+" FROM rule_proposals WHERE id = $1", proposalId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := db.DB.QueryContext(ctx, `
+		SELECT id, proposal_id, user_id, comment, created_at
+		FROM rule_proposal_comments WHERE proposal_id = $1 ORDER BY id`, proposalId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var comment models.RuleProposalComment
+		if err := rows.Scan(&comment.Id, &comment.ProposalId, &comment.UserId, &comment.Comment, &comment.CreatedAt); err != nil {
+			return nil, err
+		}
+		proposal.Comments = append(proposal.Comments, comment)
+	}
+	return &proposal, rows.Err()
+}
+
+func (db ruleProposalRepository) GetProposals(ctx context.Context, filter models.RuleProposalListRequest) ([]models.RuleProposal, error) {
+	where := ""
+	var args []any
+	if filter.Status != "" {
+		where = " WHERE status = $1"
+		args = append(args, filter.Status)
+	}
+	rows, err := db.DB.QueryContext(ctx, "SELECT "+ruleProposalColumns+" FROM rule_proposals"+where+" ORDER BY id DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	proposals := []models.RuleProposal{}
+	for rows.Next() {
+		proposal, err := scanRuleProposal(rows)
+		if err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, proposal)
+	}
+	return proposals, rows.Err()
+}
+
+func (db ruleProposalRepository) AddComment(ctx context.Context, comment models.RuleProposalComment) (*models.RuleProposalComment, error) {
+	err := db.DB.QueryRowContext(ctx, `
+		INSERT INTO rule_proposal_comments (proposal_id, user_id, comment)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at`, comment.ProposalId, comment.UserId, comment.Comment).Scan(&comment.Id, &comment.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (db ruleProposalRepository) ApproveProposal(ctx context.Context, proposalId int, approverId int) (*models.RuleProposal, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	proposal, err := lockPendingProposal(ctx, tx, proposalId)
+	if err != nil {
+		return nil, err
+	}
+
+	switch proposal.Action {
+	case models.RuleProposalCreate:
+		rule, err := insertRule(ctx, tx, *proposal.Rule, proposal.ProposedBy, approverId)
+		if err != nil {
+			return nil, err
+		}
+		proposal.RuleId = rule.Id
+	case models.RuleProposalModify:
+		columns, values, description := ruleModification(proposal.RuleId, *proposal.Modification)
+		if len(columns) == 0 {
+			return nil, fmt.Errorf("proposal %d doesn't modify anything", proposalId)
+		}
+		_, err = updateRuleTx(ctx, tx, proposal.RuleId, proposal.RuleVersion, proposal.ProposedBy, approverId,
+			models.RuleAuditUpdate, description, columns, values)
+	case models.RuleProposalDelete:
+		err = deleteRule(ctx, tx, proposal.RuleId, proposal.ProposedBy, approverId, proposal.RuleVersion)
+	case models.RuleProposalRollback:
+		columns, values, description := ruleRollback(proposal.RuleId, *proposal.Target)
+		_, err = updateRuleTx(ctx, tx, proposal.RuleId, proposal.RuleVersion, proposal.ProposedBy, approverId,
+			models.RuleAuditRollback, description, columns, values)
+	case models.RuleProposalPublish, models.RuleProposalRetire:
+		action, description, columns, values, err := ruleStatusChange(proposal.RuleId, proposal.Action.RuleStatus())
+		if err != nil {
+			return nil, err
+		}
+		_, err = updateRuleTx(ctx, tx, proposal.RuleId, proposal.RuleVersion, proposal.ProposedBy, approverId,
+			action, description, columns, values)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		err = fmt.Errorf("unknown proposal action %q", proposal.Action)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := markProposalReviewed(ctx, tx, proposal, models.RuleProposalApproved, approverId, ""); err != nil {
+		return nil, err
+	}
+	return proposal, tx.Commit()
+}
+
+func (db ruleProposalRepository) RejectProposal(ctx context.Context, proposalId int, reviewerId int, reason string) (*models.RuleProposal, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	proposal, err := lockPendingProposal(ctx, tx, proposalId)
+	if err != nil {
+		return nil, err
+	}
+	if err := markProposalReviewed(ctx, tx, proposal, models.RuleProposalRejected, reviewerId, reason); err != nil {
+		return nil, err
+	}
+	return proposal, tx.Commit()
+}
+
+// lockPendingProposal locks the proposal so only one admin reviews it
+func lockPendingProposal(ctx context.Context, tx *sql.Tx, proposalId int) (*models.RuleProposal, error) {
+	proposal, err := scanRuleProposal(tx.QueryRowContext(ctx, "SELECT "+ruleProposalColumns+" FROM rule_proposals WHERE id = $1 FOR UPDATE", proposalId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if proposal.Status != models.RuleProposalPending {
+		return nil, ErrProposalReviewed
+	}
+	return &proposal, nil
+}
+
+func markProposalReviewed(ctx context.Context, tx *sql.Tx, proposal *models.RuleProposal, status models.RuleProposalStatus, reviewerId int, reason string) error {
+	var reviewedAt sql.NullTime
+	err := tx.QueryRowContext(ctx, `
+		UPDATE rule_proposals
+		SET status = $1, reviewed_by = $2, review_reason = NULLIF($3, ''), rule_id = $4, reviewed_at = NOW()
+		WHERE id = $5
+		RETURNING reviewed_at`, status, reviewerId, reason, nullableId(proposal.RuleId), proposal.Id).Scan(&reviewedAt)
+	if err != nil {
+		return err
+	}
+	proposal.Status = status
+	proposal.ReviewedBy = reviewerId
+	proposal.ReviewReason = reason
+	proposal.ReviewedAt = &reviewedAt.Time
+	return nil
+}
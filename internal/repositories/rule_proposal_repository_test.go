@@ -0,0 +1,279 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var ruleProposalRowColumns = []string{"id", "action", "rule_id", "rule_version", "payload", "status", "proposed_by", "reviewed_by", "review_reason", "created_at", "reviewed_at"}
+
+func TestRuleProposalRepository_AddProposal(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRuleProposalRepository(db)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	title := "New title"
+	modification := models.RuleModify{Title: title}
+	payload, err := json.Marshal(modification)
+	require.NoError(t, err)
+
+	mock.ExpectQuery(`INSERT INTO rule_proposals \(action, rule_id, rule_version, payload, proposed_by\) VALUES \(\$1, \$2, \$3, \$4, \$5\) RETURNING id, action`).
+		WithArgs(models.RuleProposalModify, 4, 2, payload, 7).
+		WillReturnRows(sqlmock.NewRows(ruleProposalRowColumns).
+			AddRow(1, "modify", 4, 2, payload, "pending", 7, 0, "", now, nil))
+
+	proposal, err := repo.AddProposal(context.Background(), models.RuleProposal{
+		Action: models.RuleProposalModify, RuleId: 4, RuleVersion: 2, Modification: &modification, ProposedBy: 7,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, proposal.Id)
+	assert.Equal(t, models.RuleProposalPending, proposal.Status)
+	assert.Equal(t, title, proposal.Modification.Title)
+	assert.Nil(t, proposal.ReviewedAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRuleProposalRepository_GetProposal(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRuleProposalRepository(db)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`FROM rule_proposals WHERE id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(ruleProposalRowColumns).
+			AddRow(1, "delete", 4, 2, nil, "rejected", 7, 8, "Still needed", now, now))
+	mock.ExpectQuery(`SELECT id, proposal_id, user_id, comment, created_at FROM rule_proposal_comments WHERE proposal_id = \$1 ORDER BY id`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "proposal_id", "user_id", "comment", "created_at"}).
+			AddRow(1, 1, 8, "Other rules depend on it", now))
+
+	proposal, err := repo.GetProposal(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.RuleProposalDelete, proposal.Action)
+	assert.Equal(t, "Still needed", proposal.ReviewReason)
+	assert.Equal(t, &now, proposal.ReviewedAt)
+	require.Len(t, proposal.Comments, 1)
+	assert.Equal(t, "Other rules depend on it", proposal.Comments[0].Comment)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRuleProposalRepository_GetProposal_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM rule_proposals WHERE id = \$1`).WithArgs(1).WillReturnRows(sqlmock.NewRows(ruleProposalRowColumns))
+
+	_, err = NewRuleProposalRepository(db).GetProposal(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestRuleProposalRepository_GetProposals(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM rule_proposals WHERE status = \$1 ORDER BY id DESC`).
+		WithArgs(models.RuleProposalPending).
+		WillReturnRows(sqlmock.NewRows(ruleProposalRowColumns).
+			AddRow(2, "create", 0, 0, []byte(`{"Title":"A"}`), "pending", 7, 0, "", now, nil))
+
+	proposals, err := NewRuleProposalRepository(db).GetProposals(context.Background(), models.RuleProposalListRequest{Status: models.RuleProposalPending})
+	require.NoError(t, err)
+	require.Len(t, proposals, 1)
+	assert.Equal(t, "A", proposals[0].Rule.Title)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRuleProposalRepository_ApproveProposal(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("create", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		rule := models.Rule{Title: "A", Description: "D", EffectiveDate: now, ApplicationCondition: "true"}
+		payload, err := json.Marshal(rule)
+		require.NoError(t, err)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM rule_proposals WHERE id = \$1 FOR UPDATE`).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows(ruleProposalRowColumns).AddRow(3, "create", 0, 0, payload, "pending", 7, 0, "", now, nil))
+		mock.ExpectQuery(`INSERT INTO rules`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(10, 1))
+		mock.ExpectExec(`INSERT INTO rule_versions`).
+			WithArgs(10, 1, "A", "D", now, "true", false, models.RuleGeneral, sqlmock.AnyArg(), "{}", 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		// The proposer is the author and the approver is recorded next to them
+		mock.ExpectExec(`INSERT INTO rules_audit \(rule_id, user_id, approved_by,`).
+			WithArgs(10, 7, 8, "Created Rule 10: A", models.RuleAuditCreate, nil, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`UPDATE rule_proposals SET status = \$1, reviewed_by = \$2, review_reason = NULLIF\(\$3, ''\), rule_id = \$4, reviewed_at = NOW\(\) WHERE id = \$5`).
+			WithArgs(models.RuleProposalApproved, 8, "", 10, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"reviewed_at"}).AddRow(now))
+		mock.ExpectCommit()
+
+		proposal, err := NewRuleProposalRepository(db).ApproveProposal(context.Background(), 3, 8)
+		require.NoError(t, err)
+		assert.Equal(t, models.RuleProposalApproved, proposal.Status)
+		assert.Equal(t, 10, proposal.RuleId)
+		assert.Equal(t, 8, proposal.ReviewedBy)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("publish", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM rule_proposals WHERE id = \$1 FOR UPDATE`).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows(ruleProposalRowColumns).AddRow(3, "publish", 4, 2, nil, "pending", 7, 0, "", now, nil))
+		mock.ExpectQuery(`FROM rules WHERE id = \$1 AND version = \$2 FOR UPDATE`).
+			WithArgs(4, 2).
+			WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+				AddRow(4, "A", "D", now, "true", 2, models.RuleDraft, nil, false, models.RuleGeneral, "{}", "{}"))
+		mock.ExpectQuery(`UPDATE rules SET status = \$1 WHERE id = \$2 AND version = \$3 RETURNING`).
+			WithArgs(models.RuleScheduled, 4, 2).
+			WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+				AddRow(4, "A", "D", now, "true", 3, models.RuleScheduled, nil, false, models.RuleGeneral, "{}", "{}"))
+		mock.ExpectExec(`INSERT INTO rule_versions`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO rules_audit \(rule_id, user_id, approved_by,`).
+			WithArgs(4, 7, 8, "Published Rule 4", models.RuleAuditPublish, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`UPDATE rule_proposals SET status = \$1`).
+			WithArgs(models.RuleProposalApproved, 8, "", 4, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"reviewed_at"}).AddRow(now))
+		mock.ExpectCommit()
+
+		proposal, err := NewRuleProposalRepository(db).ApproveProposal(context.Background(), 3, 8)
+		require.NoError(t, err)
+		assert.Equal(t, models.RuleProposalApproved, proposal.Status)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		target := models.RuleVersion{Rule: models.Rule{Id: 4, Title: "Old", Description: "D", EffectiveDate: now, ApplicationCondition: "true", Version: 1}}
+		payload, err := json.Marshal(target)
+		require.NoError(t, err)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM rule_proposals WHERE id = \$1 FOR UPDATE`).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows(ruleProposalRowColumns).AddRow(3, "rollback", 4, 2, payload, "pending", 7, 0, "", now, nil))
+		mock.ExpectQuery(`FROM rules WHERE id = \$1 AND version = \$2 FOR UPDATE`).
+			WithArgs(4, 2).
+			WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+				AddRow(4, "New", "D", now, "true", 2, models.RuleDraft, nil, false, models.RuleGeneral, "{}", "{}"))
+		mock.ExpectQuery(`UPDATE rules SET title = \$1, description = \$2, effective_date = \$3, application_condition = \$4`).
+			WithArgs("Old", "D", now, "true", false, "", sqlmock.AnyArg(), "{}", 4, 2).
+			WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+				AddRow(4, "Old", "D", now, "true", 3, models.RuleDraft, nil, false, models.RuleGeneral, "{}", "{}"))
+		mock.ExpectExec(`INSERT INTO rule_versions`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO rules_audit \(rule_id, user_id, approved_by,`).
+			WithArgs(4, 7, 8, "Rolled back Rule 4 to version 1", models.RuleAuditRollback, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`UPDATE rule_proposals SET status = \$1`).
+			WithArgs(models.RuleProposalApproved, 8, "", 4, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"reviewed_at"}).AddRow(now))
+		mock.ExpectCommit()
+
+		proposal, err := NewRuleProposalRepository(db).ApproveProposal(context.Background(), 3, 8)
+		require.NoError(t, err)
+		assert.Equal(t, 1, proposal.Target.Version)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rule changed since the proposal", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM rule_proposals WHERE id = \$1 FOR UPDATE`).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows(ruleProposalRowColumns).AddRow(3, "delete", 4, 2, nil, "pending", 7, 0, "", now, nil))
+		mock.ExpectQuery(`DELETE FROM rules WHERE id = \$1 AND version = \$2`).
+			WithArgs(4, 2).
+			WillReturnRows(sqlmock.NewRows(ruleRowColumns))
+		mock.ExpectRollback()
+
+		_, err = NewRuleProposalRepository(db).ApproveProposal(context.Background(), 3, 8)
+		assert.ErrorIs(t, err, ErrVersionMismatch)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already reviewed", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM rule_proposals WHERE id = \$1 FOR UPDATE`).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows(ruleProposalRowColumns).AddRow(3, "delete", 4, 2, nil, "rejected", 7, 8, "No", now, now))
+		mock.ExpectRollback()
+
+		_, err = NewRuleProposalRepository(db).ApproveProposal(context.Background(), 3, 9)
+		assert.ErrorIs(t, err, ErrProposalReviewed)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRuleProposalRepository_RejectProposal(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM rule_proposals WHERE id = \$1 FOR UPDATE`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(ruleProposalRowColumns).AddRow(3, "delete", 4, 2, nil, "pending", 7, 0, "", now, nil))
+	mock.ExpectQuery(`UPDATE rule_proposals SET status = \$1`).
+		WithArgs(models.RuleProposalRejected, 7, "Withdrawn", 4, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"reviewed_at"}).AddRow(now))
+	mock.ExpectCommit()
+
+	proposal, err := NewRuleProposalRepository(db).RejectProposal(context.Background(), 3, 7, "Withdrawn")
+	require.NoError(t, err)
+	assert.Equal(t, models.RuleProposalRejected, proposal.Status)
+	assert.Equal(t, "Withdrawn", proposal.ReviewReason)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRuleProposalRepository_AddComment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`INSERT INTO rule_proposal_comments \(proposal_id, user_id, comment\) VALUES \(\$1, \$2, \$3\) RETURNING id, created_at`).
+		WithArgs(3, 8, "Looks good").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, now))
+
+	comment, err := NewRuleProposalRepository(db).AddComment(context.Background(), models.RuleProposalComment{ProposalId: 3, UserId: 8, Comment: "Looks good"})
+	require.NoError(t, err)
+	assert.Equal(t, 5, comment.Id)
+	assert.Equal(t, now, comment.CreatedAt)
+}
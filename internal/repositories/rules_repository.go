@@ -3,20 +3,31 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type RulesRepository interface {
 	AddRule(ctx context.Context, rule models.Rule, userId int) error
-	DeleteRule(ctx context.Context, ruleId int, userId int) error
-	GetRules(ctx context.Context) ([]models.Rule, error)
-	ModifyRule(ctx context.Context, ruleId int, modification models.RuleModify, userId int) error
-	GetAudit(ctx context.Context) ([]models.Audit, error)
+	DeleteRule(ctx context.Context, ruleId int, userId int, version int) error
+	GetRule(ctx context.Context, ruleId int) (*models.Rule, error)
+	GetRules(ctx context.Context, filter models.RuleListRequest) ([]models.Rule, error)
+	ModifyRule(ctx context.Context, ruleId int, modification models.RuleModify, userId int, version int) error
+	GetAudit(ctx context.Context, filter models.RuleAuditFilter, limit int, offset int) ([]models.Audit, int, error)
+	GetRuleVersions(ctx context.Context, ruleId int) ([]models.RuleVersion, error)
+	GetRuleVersion(ctx context.Context, ruleId int, version int) (*models.RuleVersion, error)
+	RollbackRule(ctx context.Context, ruleId int, target models.RuleVersion, userId int, version int) error
+	// SetRuleStatus moves the rule to the status, userId is 0 when the system does it.
+	// The jobs of the outbox, if any, are queued along with the change.
+	SetRuleStatus(ctx context.Context, ruleId int, status models.RuleStatus, userId int, version int, outbox models.Outbox) error
+	// GetDueRules returns the scheduled rules whose effective date is not after now
+	GetDueRules(ctx context.Context, now time.Time) ([]models.Rule, error)
 }
 
 type rulesRepository struct {
@@ -28,6 +39,112 @@ func CreateRulesRepo(db *sql.DB) *rulesRepository {
 	return &rulesRepository{DB: db}
 }
 
+const ruleColumns = "id, title, description, effective_date, application_condition, version, status, retired_at, mandatory, category, tags, translations"
+
+// ruleAuditColumns are the columns scanRuleAudit reads. Entries written before actions were recorded have none.
+const ruleAuditColumns = "id, rule_id, user_id, approved_by, modification_date, nature_of_modification, COALESCE(action, ''), before, after, prev_hash, hash"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanRule reads the ruleColumns of the row, and then any extra columns into extra
+func scanRule(row rowScanner, extra ...any) (models.Rule, error) {
+	var rule models.Rule
+	var retiredAt sql.NullTime
+	var translations []byte
+	dest := []any{&rule.Id, &rule.Title, &rule.Description, &rule.EffectiveDate, &rule.ApplicationCondition, &rule.Version,
+		&rule.Status, &retiredAt, &rule.Mandatory, &rule.Category, pq.Array(&rule.Tags), &translations}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return rule, err
+	}
+	if retiredAt.Valid {
+		rule.RetiredAt = &retiredAt.Time
+	}
+	return rule, setRuleTagsAndTranslations(&rule, translations)
+}
+
+// setRuleTagsAndTranslations reads the translations column into the rule, leaving no tags or translations nil
+func setRuleTagsAndTranslations(rule *models.Rule, translations []byte) error {
+	if len(rule.Tags) == 0 {
+		rule.Tags = nil
+	}
+	if len(translations) > 0 {
+		if err := json.Unmarshal(translations, &rule.Translations); err != nil {
+			return err
+		}
+		if len(rule.Translations) == 0 {
+			rule.Translations = nil
+		}
+	}
+	return nil
+}
+
+// ruleTranslationsJSON is the value stored in the translations column
+func ruleTranslationsJSON(translations map[string]models.RuleTranslation) (string, error) {
+	if translations == nil {
+		return "{}", nil
+	}
+	document, err := json.Marshal(translations)
+	return string(document), err
+}
+
+// ruleTags is the value stored in the tags column
+func ruleTags(tags []string) any {
+	if tags == nil {
+		tags = []string{}
+	}
+	return pq.Array(tags)
+}
+
+// actorId is the user id stored for an action, NULL when the system did it
+func actorId(userId int) any {
+	if userId == 0 {
+		return nil
+	}
+	return userId
+}
+
+func scanRuleAudit(row rowScanner) (models.Audit, error) {
+	var audit models.Audit
+	var before, after []byte
+	err := row.Scan(&audit.Id, &audit.RuleId, &audit.UserId, &audit.ApprovedBy, &audit.ModificationDate, &audit.NatureOfModification,
+		&audit.Action, &before, &after, &audit.PrevHash, &audit.Hash)
+	if before != nil {
+		audit.Before = before
+	}
+	if after != nil {
+		audit.After = after
+	}
+	return audit, err
+}
+
+// addRuleAudit appends an entry to the rules audit, the database links it to the hash chain.
+// before and after are the states of the rule around the action, nil when it didn't exist.
+// approvedBy is the admin who approved the proposal the action comes from, 0 when it wasn't proposed.
+func addRuleAudit(ctx context.Context, tx *sql.Tx, action models.RuleAuditAction, ruleId int, userId int, approvedBy int, description string, before *models.Rule, after *models.Rule) error {
+	snapshot := func(rule *models.Rule) (any, error) {
+		if rule == nil {
+			return nil, nil
+		}
+		document, err := json.Marshal(rule)
+		return string(document), err
+	}
+	beforeJSON, err := snapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := snapshot(after)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO rules_audit (rule_id, user_id, approved_by, nature_of_modification, action, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`, ruleId, actorId(userId), actorId(approvedBy), description, action, beforeJSON, afterJSON)
+	return err
+}
+
 func (db rulesRepository) AddRule(ctx context.Context, rule models.Rule, userId int) error {
 	tx, err := db.DB.Begin()
 	if err != nil {
@@ -41,30 +158,48 @@ func (db rulesRepository) AddRule(ctx context.Context, rule models.Rule, userId
 		}
 	}()
 
+	if _, err := insertRule(ctx, tx, rule, userId, 0); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertRule adds the rule as a draft, with its first version and audit entry
+func insertRule(ctx context.Context, tx *sql.Tx, rule models.Rule, userId int, approvedBy int) (*models.Rule, error) {
+	rule.Status = models.RuleDraft
+	rule.RetiredAt = nil
+	rule.Locale = ""
+	if rule.Category == "" {
+		rule.Category = models.RuleGeneral
+	}
+	translations, err := ruleTranslationsJSON(rule.Translations)
+	if err != nil {
+		return nil, err
+	}
 	query := `
-		INSERT INTO rules (title, description,effective_date, application_condition)
-		VALUES ($1, $2, $3,$4)
-		RETURNING id`
-	var id int
+		INSERT INTO rules (title, description,effective_date, application_condition, status, mandatory, category, tags, translations)
+		VALUES ($1, $2, $3,$4, $5, $6, $7, $8, $9)
+		RETURNING id, version`
 	err = tx.QueryRowContext(ctx, query,
-		&rule.Title, &rule.Description, &rule.EffectiveDate, &rule.ApplicationCondition,
-	).Scan(&id)
-
+		&rule.Title, &rule.Description, &rule.EffectiveDate, &rule.ApplicationCondition, rule.Status, rule.Mandatory,
+		rule.Category, ruleTags(rule.Tags), translations,
+	).Scan(&rule.Id, &rule.Version)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err := addRuleVersion(ctx, tx, rule, userId); err != nil {
+		return nil, err
 	}
-	_, err = tx.QueryContext(ctx, `
-	 		INSERT INTO rules_audit (rule_id, user_id, nature_of_modification)
-	 		VALUES ($1, $2, $3)`, id, userId, fmt.Sprint("Created Rule ", id, ": ", rule.Title))
+	err = addRuleAudit(ctx, tx, models.RuleAuditCreate, rule.Id, userId, approvedBy, fmt.Sprint("Created Rule ", rule.Id, ": ", rule.Title), nil, &rule)
 	if err != nil {
-		tx.Rollback()
-		return err
+		return nil, err
 	}
-
-	return tx.Commit()
+	return &rule, nil
 }
 
-func (db rulesRepository) DeleteRule(ctx context.Context, ruleId int, userId int) error {
+// DeleteRule deletes the rule only if it is still at the given version
+func (db rulesRepository) DeleteRule(ctx context.Context, ruleId int, userId int, version int) error {
 	tx, err := db.DB.Begin()
 	if err != nil {
 		return err
@@ -77,37 +212,77 @@ func (db rulesRepository) DeleteRule(ctx context.Context, ruleId int, userId int
 		}
 	}()
 
-	var deletedRule models.Rule
-	err = tx.QueryRow(`
+	if err := deleteRule(ctx, tx, ruleId, userId, 0, version); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func deleteRule(ctx context.Context, tx *sql.Tx, ruleId int, userId int, approvedBy int, version int) error {
+	deletedRule, err := scanRule(tx.QueryRowContext(ctx, `
 			DELETE FROM rules
-			WHERE id = $1
-			RETURNING title, description, application_condition
-		`, ruleId).Scan(&deletedRule.Title, &deletedRule.Description, &deletedRule.ApplicationCondition)
+			WHERE id = $1 AND version = $2
+			RETURNING `+ruleColumns, ruleId, version))
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrVersionMismatch
+		}
 		return err
 	}
-	_, err = tx.QueryContext(ctx, `
-	 		INSERT INTO rules_audit (rule_id, user_id, nature_of_modification)
-	 		VALUES ($1, $2, $3)`, nil, userId, fmt.Sprint("Deleted Rule ", ruleId, ": ", deletedRule.Title))
+	return addRuleAudit(ctx, tx, models.RuleAuditDelete, ruleId, userId, approvedBy, fmt.Sprint("Deleted Rule ", ruleId, ": ", deletedRule.Title), &deletedRule, nil)
+}
+
+func (db rulesRepository) GetRule(ctx context.Context, ruleId int) (*models.Rule, error) {
+	rule, err := scanRule(db.DB.QueryRowContext(ctx, `
+		SELECT `+ruleColumns+`
+		FROM rules WHERE id = $1`, ruleId))
 	if err != nil {
-		tx.Rollback()
-		return err
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
 	}
-	return tx.Commit()
+	return &rule, nil
 }
 
-func (db rulesRepository) GetRules(ctx context.Context) ([]models.Rule, error) {
+// GetRules returns the rules matching the filter
+func (db rulesRepository) GetRules(ctx context.Context, filter models.RuleListRequest) ([]models.Rule, error) {
+	var conditions []string
+	var args []any
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.At != nil {
+		// In force at the end of the day: published, effective by then and not retired yet
+		args = append(args, filter.At.AddDate(0, 0, 1))
+		conditions = append(conditions, fmt.Sprintf(
+			"status <> 'draft' AND effective_date < $%[1]d AND (retired_at IS NULL OR retired_at >= $%[1]d)", len(args)))
+	}
+	if filter.Category != "" {
+		args = append(args, filter.Category)
+		conditions = append(conditions, fmt.Sprintf("category = $%d", len(args)))
+	}
+	if len(filter.Tags) > 0 {
+		args = append(args, pq.Array(filter.Tags))
+		conditions = append(conditions, fmt.Sprintf("tags @> $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
 	rows, err := db.DB.QueryContext(ctx, `
-		SELECT id, title, description, effective_date, application_condition
-		FROM rules`)
+		SELECT `+ruleColumns+`
+		FROM rules`+where, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var rules []models.Rule
 	for rows.Next() {
-		var rule models.Rule
-		err := rows.Scan(&rule.Id, &rule.Title, &rule.Description, &rule.EffectiveDate, &rule.ApplicationCondition)
+		rule, err := scanRule(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -116,35 +291,146 @@ func (db rulesRepository) GetRules(ctx context.Context) ([]models.Rule, error) {
 	return rules, nil
 }
 
-func (db rulesRepository) ModifyRule(ctx context.Context, ruleId int, modification models.RuleModify, userId int) error {
-	modification_nature := fmt.Sprint("Modified Rule ", ruleId, ":")
-	counter := 1
-	query := "UPDATE rules SET"
-	params := []interface{}{}
-	setClauses := []string{}
+// GetDueRules returns the scheduled rules whose effective date is not after now, oldest first
+func (db rulesRepository) GetDueRules(ctx context.Context, now time.Time) ([]models.Rule, error) {
+	rows, err := db.DB.QueryContext(ctx, "SELECT "+ruleColumns+" FROM rules WHERE status = $1 AND effective_date <= $2 ORDER BY effective_date, id",
+		models.RuleScheduled, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.Rule{}
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// SetRuleStatus moves the rule to the status only if it is still at the given version.
+// Whether the rule can go to that status is checked by the caller.
+func (db rulesRepository) SetRuleStatus(ctx context.Context, ruleId int, status models.RuleStatus, userId int, version int, outbox models.Outbox) error {
+	action, description, columns, values, err := ruleStatusChange(ruleId, status)
+	if err != nil {
+		return err
+	}
+	return db.updateRule(ctx, ruleId, version, userId, action, description, columns, values, outbox)
+}
+
+// ruleStatusChange returns how moving the rule to the status is audited, and the columns it sets with their values
+func ruleStatusChange(ruleId int, status models.RuleStatus) (models.RuleAuditAction, string, []string, []any, error) {
+	columns := []string{"status"}
+	values := []any{status}
+	var action models.RuleAuditAction
+	var description string
+	switch status {
+	case models.RuleScheduled:
+		action, description = models.RuleAuditPublish, fmt.Sprint("Published Rule ", ruleId)
+	case models.RuleActive:
+		action, description = models.RuleAuditActivate, fmt.Sprint("Activated Rule ", ruleId)
+	case models.RuleRetired:
+		action, description = models.RuleAuditRetire, fmt.Sprint("Retired Rule ", ruleId)
+		columns = append(columns, "retired_at")
+		values = append(values, time.Now().UTC())
+	default:
+		return "", "", nil, nil, fmt.Errorf("can't set rule status to %q", status)
+	}
+	return action, description, columns, values, nil
+}
+
+// ModifyRule applies the modification only if the rule is still at the given version
+func (db rulesRepository) ModifyRule(ctx context.Context, ruleId int, modification models.RuleModify, userId int, version int) error {
+	columns, values, description := ruleModification(ruleId, modification)
+	if len(columns) == 0 {
+		return nil
+	}
+	return db.updateRule(ctx, ruleId, version, userId, models.RuleAuditUpdate, description, columns, values, nil)
+}
+
+// ruleModification returns the columns the modification sets, their values and a description of the change
+func ruleModification(ruleId int, modification models.RuleModify) ([]string, []any, string) {
+	var columns, changed []string
+	var values []any
 	if modification.Title != "" {
-		setClauses = append(setClauses, fmt.Sprint("title = $", counter))
-		counter += 1
-		params = append(params, modification.Title)
-		modification_nature += " Changed title to: " + modification.Title
+		columns = append(columns, "title")
+		values = append(values, modification.Title)
+		changed = append(changed, "title")
 	}
 	if modification.Description != "" {
-		setClauses = append(setClauses, fmt.Sprint("description = $", counter))
-		counter += 1
-		params = append(params, modification.Description)
-		modification_nature += " Changed description to: " + modification.Description
+		columns = append(columns, "description")
+		values = append(values, modification.Description)
+		changed = append(changed, "description")
 	}
 	if modification.ApplicationCondition != "" {
-		setClauses = append(setClauses, fmt.Sprint("application_condition = $", counter))
-		counter += 1
-		params = append(params, modification.ApplicationCondition)
-		modification_nature += " Changed application condition to: " + modification.ApplicationCondition
+		columns = append(columns, "application_condition")
+		values = append(values, modification.ApplicationCondition)
+		changed = append(changed, "application condition")
 	}
-	if len(setClauses) == 0 {
-		return nil
+	if modification.EffectiveDate != nil {
+		columns = append(columns, "effective_date")
+		values = append(values, *modification.EffectiveDate)
+		changed = append(changed, "effective date")
+	}
+	if modification.Mandatory != nil {
+		columns = append(columns, "mandatory")
+		values = append(values, *modification.Mandatory)
+		changed = append(changed, "mandatory")
+	}
+	if modification.Category != "" {
+		columns = append(columns, "category")
+		values = append(values, modification.Category)
+		changed = append(changed, "category")
+	}
+	if modification.Tags != nil {
+		columns = append(columns, "tags")
+		values = append(values, ruleTags(modification.Tags))
+		changed = append(changed, "tags")
+	}
+	if modification.Translations != nil {
+		// Marshaling a map of strings can't fail
+		translations, _ := ruleTranslationsJSON(modification.Translations)
+		columns = append(columns, "translations")
+		values = append(values, translations)
+		changed = append(changed, "translations")
+	}
+	return columns, values, fmt.Sprint("Modified Rule ", ruleId, ": ", strings.Join(changed, ", "))
+}
+
+// RollbackRule restores the content of an earlier version of the rule, as a new version,
+// only if the rule is still at the given version
+func (db rulesRepository) RollbackRule(ctx context.Context, ruleId int, target models.RuleVersion, userId int, version int) error {
+	columns, values, description := ruleRollback(ruleId, target)
+	return db.updateRule(ctx, ruleId, version, userId, models.RuleAuditRollback, description, columns, values, nil)
+}
+
+// ruleRollback returns the columns restoring the target version sets, their values and a description of the change.
+// Fields the target version didn't record keep their current value.
+func ruleRollback(ruleId int, target models.RuleVersion) ([]string, []any, string) {
+	columns := []string{"title", "description", "effective_date", "application_condition"}
+	values := []any{target.Title, target.Description, target.EffectiveDate, target.ApplicationCondition}
+	if target.Knows("mandatory") {
+		columns, values = append(columns, "mandatory"), append(values, target.Mandatory)
+	}
+	if target.Knows("category") {
+		columns, values = append(columns, "category"), append(values, target.Category)
+	}
+	if target.Knows("tags") {
+		columns, values = append(columns, "tags"), append(values, ruleTags(target.Tags))
+	}
+	if target.Knows("translations") {
+		// Marshaling a map of strings can't fail
+		translations, _ := ruleTranslationsJSON(target.Translations)
+		columns, values = append(columns, "translations"), append(values, translations)
 	}
-	query += " " + strings.Join(setClauses, ", ") + fmt.Sprint(" WHERE id = $", counter)
-	params = append(params, ruleId)
+	return columns, values, fmt.Sprint("Rolled back Rule ", ruleId, " to version ", target.Version)
+}
+
+// updateRule sets the columns of the rule if it is still at the given version, and records the new version and the change
+func (db rulesRepository) updateRule(ctx context.Context, ruleId int, version int, userId int, action models.RuleAuditAction, description string, columns []string, values []any, outbox models.Outbox) error {
 	tx, err := db.DB.Begin()
 	if err != nil {
 		return err
@@ -156,36 +442,186 @@ func (db rulesRepository) ModifyRule(ctx context.Context, ruleId int, modificati
 			panic(r)
 		}
 	}()
-	_, err = tx.ExecContext(ctx, query, params...)
-	if err != nil {
+
+	if _, err := updateRuleTx(ctx, tx, ruleId, version, userId, 0, action, description, columns, values); err != nil {
+		tx.Rollback()
 		return err
 	}
-	_, err = tx.QueryContext(ctx, `
-	 		INSERT INTO rules_audit (rule_id, user_id, nature_of_modification)
-	 		VALUES ($1, $2, $3)`, ruleId, userId, modification_nature)
-	if err != nil {
+	if err := enqueueOutbox(ctx, tx, outbox, ruleId); err != nil {
 		tx.Rollback()
 		return err
 	}
 	return tx.Commit()
 }
 
-func (db rulesRepository) GetAudit(ctx context.Context) ([]models.Audit, error) {
-	rows, err := db.DB.QueryContext(ctx, `
-		SELECT id, rule_id, user_id, modification_date, nature_of_modification
-		FROM rules_audit`)
+func updateRuleTx(ctx context.Context, tx *sql.Tx, ruleId int, version int, userId int, approvedBy int, action models.RuleAuditAction, description string, columns []string, values []any) (*models.Rule, error) {
+	setClauses := make([]string, len(columns))
+	for i, column := range columns {
+		setClauses[i] = fmt.Sprint(column, " = $", i+1)
+	}
+	query := "UPDATE rules SET " + strings.Join(setClauses, ", ") +
+		fmt.Sprint(" WHERE id = $", len(columns)+1, " AND version = $", len(columns)+2) + " RETURNING " + ruleColumns
+	params := append(values, ruleId, version)
+
+	// Lock the rule so the state recorded as before is the one being replaced
+	before, err := scanRule(tx.QueryRowContext(ctx, "SELECT "+ruleColumns+" FROM rules WHERE id = $1 AND version = $2 FOR UPDATE", ruleId, version))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrVersionMismatch
+		}
+		return nil, err
+	}
+	after, err := scanRule(tx.QueryRowContext(ctx, query, params...))
+	if err != nil {
+		return nil, err
+	}
+	if err := addRuleVersion(ctx, tx, after, userId); err != nil {
+		return nil, err
+	}
+	if err := addRuleAudit(ctx, tx, action, ruleId, userId, approvedBy, description, &before, &after); err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// addRuleVersion keeps the content of the rule at its current version
+func addRuleVersion(ctx context.Context, tx *sql.Tx, rule models.Rule, userId int) error {
+	translations, err := ruleTranslationsJSON(rule.Translations)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO rule_versions (rule_id, version, title, description, effective_date, application_condition, mandatory, category, tags, translations, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		rule.Id, rule.Version, rule.Title, rule.Description, rule.EffectiveDate, rule.ApplicationCondition,
+		rule.Mandatory, rule.Category, ruleTags(rule.Tags), translations, actorId(userId))
+	return err
+}
+
+const ruleVersionColumns = "rule_id, version, title, description, effective_date, application_condition, mandatory, category, tags, translations, " +
+	"COALESCE(created_by, 0), created_at"
+
+// scanRuleVersion reads a version, NULL columns are fields the version didn't record
+func scanRuleVersion(row rowScanner) (models.RuleVersion, error) {
+	var version models.RuleVersion
+	var mandatory sql.NullBool
+	var category sql.NullString
+	var tags, translations []byte
+	err := row.Scan(&version.Id, &version.Version, &version.Title, &version.Description, &version.EffectiveDate,
+		&version.ApplicationCondition, &mandatory, &category, &tags, &translations,
+		&version.CreatedBy, &version.CreatedAt)
+	if err != nil {
+		return version, err
+	}
+
+	if mandatory.Valid {
+		version.Mandatory = mandatory.Bool
+	} else {
+		version.UnknownFields = append(version.UnknownFields, "mandatory")
+	}
+	if category.Valid {
+		version.Category = models.RuleCategory(category.String)
+	} else {
+		version.UnknownFields = append(version.UnknownFields, "category")
+	}
+	if tags != nil {
+		if err := pq.Array(&version.Tags).Scan(tags); err != nil {
+			return version, err
+		}
+	} else {
+		version.UnknownFields = append(version.UnknownFields, "tags")
+	}
+	if translations == nil {
+		version.UnknownFields = append(version.UnknownFields, "translations")
+	}
+	return version, setRuleTagsAndTranslations(&version.Rule, translations)
+}
+
+// GetRuleVersions returns every version kept of the rule, newest first. Versions of deleted rules are kept too.
+func (db rulesRepository) GetRuleVersions(ctx context.Context, ruleId int) ([]models.RuleVersion, error) {
+	rows, err := db.DB.QueryContext(ctx, "SELECT "+ruleVersionColumns+" FROM rule_versions WHERE rule_id = $1 ORDER BY version DESC", ruleId)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var audits []models.Audit
+
+	versions := []models.RuleVersion{}
 	for rows.Next() {
-		var audit models.Audit
-		err := rows.Scan(&audit.Id, &audit.RuleId, &audit.UserId, &audit.ModificationDate, &audit.NatureOfModification)
+		version, err := scanRuleVersion(rows)
 		if err != nil {
 			return nil, err
 		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+func (db rulesRepository) GetRuleVersion(ctx context.Context, ruleId int, version int) (*models.RuleVersion, error) {
+	ruleVersion, err := scanRuleVersion(db.DB.QueryRowContext(ctx,
+		"SELECT "+ruleVersionColumns+" FROM rule_versions WHERE rule_id = $1 AND version = $2", ruleId, version))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &ruleVersion, nil
+}
+
+// GetAudit returns a page of the rules audit entries matching the filter, newest first, and how many match in total
+func (db rulesRepository) GetAudit(ctx context.Context, filter models.RuleAuditFilter, limit int, offset int) ([]models.Audit, int, error) {
+	where, args := ruleAuditFilterClause(filter)
+
+	var total int
+	if err := db.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM rules_audit"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM rules_audit%s ORDER BY id DESC LIMIT $%d OFFSET $%d", ruleAuditColumns, where, len(args)+1, len(args)+2)
+	rows, err := db.DB.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	audits := []models.Audit{}
+	for rows.Next() {
+		audit, err := scanRuleAudit(rows)
+		if err != nil {
+			return nil, 0, err
+		}
 		audits = append(audits, audit)
 	}
-	return audits, nil
+	return audits, total, rows.Err()
+}
+
+func ruleAuditFilterClause(filter models.RuleAuditFilter) (string, []any) {
+	var conditions []string
+	var args []any
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.RuleId != nil {
+		add("rule_id = $%d", *filter.RuleId)
+	}
+	if filter.UserId != nil {
+		add("user_id = $%d", *filter.UserId)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.From != nil {
+		add("modification_date >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		// The day given is included
+		add("modification_date < $%d", filter.To.AddDate(0, 0, 1))
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
 }
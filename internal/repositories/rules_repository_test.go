@@ -3,15 +3,26 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
 	"time"
 )
 
+var ruleRowColumns = []string{"id", "title", "description", "effective_date", "application_condition", "version", "status", "retired_at", "mandatory", "category", "tags", "translations"}
+
+// ruleSnapshot returns the rule as recorded in the rules audit
+func ruleSnapshot(t *testing.T, rule models.Rule) string {
+	document, err := json.Marshal(rule)
+	require.NoError(t, err)
+	return string(document)
+}
+
 func TestCreateRulesRepo(t *testing.T) {
 	db, _, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -34,18 +45,26 @@ func TestRulesRepository_AddRule(t *testing.T) {
 		Description:          "A rule for testing",
 		EffectiveDate:        time.Now(),
 		ApplicationCondition: "Condition A",
+		Tags:                 []string{"privacy"},
+		Translations:         map[string]models.RuleTranslation{"es": {Title: "Regla", Description: "Una regla"}},
 	}
 	userId := 1
 
 	mock.ExpectBegin()
 
-	mock.ExpectQuery(`INSERT INTO rules \(title, description,effective_date, application_condition\) VALUES \(\$1, \$2, \$3,\$4\) RETURNING id`).
-		WithArgs(rule.Title, rule.Description, rule.EffectiveDate, rule.ApplicationCondition).
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(123))
+	mock.ExpectQuery(`INSERT INTO rules \(title, description,effective_date, application_condition, status, mandatory, category, tags, translations\) VALUES \(\$1, \$2, \$3,\$4, \$5, \$6, \$7, \$8, \$9\) RETURNING id, version`).
+		WithArgs(rule.Title, rule.Description, rule.EffectiveDate, rule.ApplicationCondition, models.RuleDraft, false, models.RuleGeneral, pq.Array([]string{"privacy"}), `{"es":{"title":"Regla","description":"Una regla"}}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(123, 1))
 
-	mock.ExpectQuery(`INSERT INTO rules_audit \(rule_id, user_id, nature_of_modification\) VALUES \(\$1, \$2, \$3\)`).
-		WithArgs(123, userId, fmt.Sprint("Created Rule ", 123, ": ", rule.Title)).
-		WillReturnRows(sqlmock.NewRows([]string{"dummy"}))
+	created := rule
+	created.Id, created.Version, created.Status, created.Category = 123, 1, models.RuleDraft, models.RuleGeneral
+	mock.ExpectExec(`INSERT INTO rule_versions \(rule_id, version, title, description, effective_date, application_condition, mandatory, category, tags, translations, created_by\)`).
+		WithArgs(123, 1, rule.Title, rule.Description, rule.EffectiveDate, rule.ApplicationCondition,
+			false, models.RuleGeneral, pq.Array([]string{"privacy"}), `{"es":{"title":"Regla","description":"Una regla"}}`, userId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO rules_audit \(rule_id, user_id, approved_by, nature_of_modification, action, before, after\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7\)`).
+		WithArgs(123, userId, nil, fmt.Sprint("Created Rule ", 123, ": ", rule.Title), models.RuleAuditCreate, nil, ruleSnapshot(t, created)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	mock.ExpectCommit()
 
@@ -65,25 +84,95 @@ func TestRulesRepository_DeleteRule(t *testing.T) {
 	ctx := context.Background()
 	ruleID := 1
 	userID := 1
+	version := 2
 	deletedTitle := "Old Rule"
 	deletedDescription := "No longer needed"
 	deletedCondition := "Condition X"
 
 	mock.ExpectBegin()
 
-	mock.ExpectQuery(`DELETE FROM rules WHERE id = \$1 RETURNING title, description, application_condition`).
-		WithArgs(ruleID).
-		WillReturnRows(sqlmock.NewRows([]string{"title", "description", "application_condition"}).
-			AddRow(deletedTitle, deletedDescription, deletedCondition))
+	effectiveDate := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`DELETE FROM rules WHERE id = \$1 AND version = \$2 RETURNING id, title, description, effective_date, application_condition, version, status, retired_at, mandatory`).
+		WithArgs(ruleID, version).
+		WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+			AddRow(ruleID, deletedTitle, deletedDescription, effectiveDate, deletedCondition, version, models.RuleActive, nil, false, models.RuleGeneral, "{}", "{}"))
 
-	mock.ExpectQuery(`INSERT INTO rules_audit \(rule_id, user_id, nature_of_modification\) VALUES \(\$1, \$2, \$3\)`).
-		WithArgs(nil, userID, fmt.Sprint("Deleted Rule ", ruleID, ": ", deletedTitle)).
-		WillReturnRows(sqlmock.NewRows([]string{"dummy"}))
+	deleted := models.Rule{Id: ruleID, Title: deletedTitle, Description: deletedDescription, EffectiveDate: effectiveDate,
+		ApplicationCondition: deletedCondition, Version: version, Status: models.RuleActive, Category: models.RuleGeneral}
+	mock.ExpectExec(`INSERT INTO rules_audit \(rule_id, user_id, approved_by, nature_of_modification, action, before, after\)`).
+		WithArgs(ruleID, userID, nil, fmt.Sprint("Deleted Rule ", ruleID, ": ", deletedTitle), models.RuleAuditDelete, ruleSnapshot(t, deleted), nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	mock.ExpectCommit()
 
-	err = repo.DeleteRule(ctx, ruleID, userID)
+	err = repo.DeleteRule(ctx, ruleID, userID, version)
+	require.NoError(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRulesRepository_DeleteRule_VersionMismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateRulesRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`DELETE FROM rules WHERE id = \$1 AND version = \$2`).
+		WithArgs(1, 3).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	err = repo.DeleteRule(context.Background(), 1, 1, 3)
+	require.ErrorIs(t, err, ErrVersionMismatch)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRulesRepository_GetRule(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateRulesRepo(db)
+
+	now := time.Now()
+	expected := &models.Rule{
+		Id:                   1,
+		Title:                "Rule A",
+		Description:          "First rule",
+		EffectiveDate:        now,
+		ApplicationCondition: "If condition A",
+		Version:              4,
+		Category:             models.RuleGeneral,
+	}
+
+	mock.ExpectQuery(`SELECT id, title, description, effective_date, application_condition, version, status, retired_at, mandatory, category, tags, translations FROM rules WHERE id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "effective_date", "application_condition", "version", "status", "retired_at", "mandatory", "category", "tags", "translations"}).
+			AddRow(expected.Id, expected.Title, expected.Description, expected.EffectiveDate, expected.ApplicationCondition, expected.Version, expected.Status, nil, false, models.RuleGeneral, "{}", "{}"))
+
+	rule, err := repo.GetRule(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, expected, rule)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRulesRepository_GetRule_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateRulesRepo(db)
+
+	mock.ExpectQuery(`SELECT id, title, description, effective_date, application_condition, version, status, retired_at, mandatory, category, tags, translations FROM rules WHERE id = \$1`).
+		WithArgs(1).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetRule(context.Background(), 1)
+	require.ErrorIs(t, err, ErrNotFound)
 
 	require.NoError(t, mock.ExpectationsWereMet())
 }
@@ -100,30 +189,51 @@ func TestRulesRepository_GetAudit(t *testing.T) {
 	now := time.Now()
 	expectedAudits := []models.Audit{
 		{
-			Id:                   1,
+			Id:                   2,
 			RuleId:               sql.NullInt64{Int64: 10, Valid: true},
 			UserId:               sql.NullInt64{Int64: 1, Valid: true},
 			ModificationDate:     now,
-			NatureOfModification: "Created Rule 10: Test Rule",
+			NatureOfModification: "Modified Rule 10: title",
+			Action:               models.RuleAuditUpdate,
+			Before:               json.RawMessage(`{"Title": "Test Rule"}`),
+			After:                json.RawMessage(`{"Title": "New Rule"}`),
+			PrevHash:             "a1",
+			Hash:                 "b2",
 		},
 		{
-			Id:                   2,
-			RuleId:               sql.NullInt64{Int64: 11, Valid: true},
+			Id:                   1,
+			RuleId:               sql.NullInt64{Int64: 10, Valid: true},
 			UserId:               sql.NullInt64{Int64: 1, Valid: true},
 			ModificationDate:     now,
-			NatureOfModification: "Deleted Rule 11: Old Rule",
+			NatureOfModification: "Created Rule 10: Test Rule",
+			PrevHash:             models.AuditChainGenesis,
+			Hash:                 "a1",
 		},
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "rule_id", "user_id", "modification_date", "nature_of_modification"}).
-		AddRow(expectedAudits[0].Id, expectedAudits[0].RuleId, expectedAudits[0].UserId, expectedAudits[0].ModificationDate, expectedAudits[0].NatureOfModification).
-		AddRow(expectedAudits[1].Id, expectedAudits[1].RuleId, expectedAudits[1].UserId, expectedAudits[1].ModificationDate, expectedAudits[1].NatureOfModification)
+	rows := sqlmock.NewRows([]string{"id", "rule_id", "user_id", "approved_by", "modification_date", "nature_of_modification", "action", "before", "after", "prev_hash", "hash"})
+	for _, audit := range expectedAudits {
+		var before, after []byte
+		if audit.Before != nil {
+			before, after = audit.Before, audit.After
+		}
+		rows.AddRow(audit.Id, audit.RuleId, audit.UserId, audit.ApprovedBy, audit.ModificationDate, audit.NatureOfModification, audit.Action, before, after, audit.PrevHash, audit.Hash)
+	}
 
-	mock.ExpectQuery(`SELECT id, rule_id, user_id, modification_date, nature_of_modification FROM rules_audit`).
+	ruleId, userId := 10, 1
+	to := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	filter := models.RuleAuditFilter{RuleId: &ruleId, UserId: &userId, To: &to}
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM rules_audit WHERE rule_id = \$1 AND user_id = \$2 AND modification_date < \$3`).
+		WithArgs(10, 1, to.AddDate(0, 0, 1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(`SELECT id, rule_id, user_id, approved_by, modification_date, nature_of_modification, COALESCE\(action, ''\), before, after, prev_hash, hash FROM rules_audit WHERE rule_id = \$1 AND user_id = \$2 AND modification_date < \$3 ORDER BY id DESC LIMIT \$4 OFFSET \$5`).
+		WithArgs(10, 1, to.AddDate(0, 0, 1), 50, 0).
 		WillReturnRows(rows)
 
-	audits, err := repo.GetAudit(ctx)
+	audits, total, err := repo.GetAudit(ctx, filter, 50, 0)
 	require.NoError(t, err)
+	require.Equal(t, 2, total)
 	require.Equal(t, expectedAudits, audits)
 
 	require.NoError(t, mock.ExpectationsWereMet())
@@ -146,18 +256,20 @@ func TestRulesRepository_GetRules(t *testing.T) {
 			Description:          "First rule",
 			EffectiveDate:        now,
 			ApplicationCondition: "If condition A",
+			Version:              1,
+			Category:             models.RuleGeneral,
 		},
 	}
 
 	rows := sqlmock.NewRows([]string{
-		"id", "title", "description", "effective_date", "application_condition",
+		"id", "title", "description", "effective_date", "application_condition", "version", "status", "retired_at", "mandatory", "category", "tags", "translations",
 	}).
-		AddRow(expectedRules[0].Id, expectedRules[0].Title, expectedRules[0].Description, expectedRules[0].EffectiveDate, expectedRules[0].ApplicationCondition)
+		AddRow(expectedRules[0].Id, expectedRules[0].Title, expectedRules[0].Description, expectedRules[0].EffectiveDate, expectedRules[0].ApplicationCondition, expectedRules[0].Version, expectedRules[0].Status, nil, false, models.RuleGeneral, "{}", "{}")
 
-	mock.ExpectQuery(`SELECT id, title, description, effective_date, application_condition FROM rules`).
+	mock.ExpectQuery(`SELECT id, title, description, effective_date, application_condition, version, status, retired_at, mandatory, category, tags, translations FROM rules`).
 		WillReturnRows(rows)
 
-	rules, err := repo.GetRules(ctx)
+	rules, err := repo.GetRules(ctx, models.RuleListRequest{})
 	require.NoError(t, err)
 	require.Equal(t, expectedRules, rules)
 
@@ -174,6 +286,7 @@ func TestRulesRepository_ModifyRule(t *testing.T) {
 	ctx := context.Background()
 	ruleID := 1
 	userID := 1
+	version := 2
 
 	modification := models.RuleModify{
 		Title:                "Updated Title",
@@ -181,29 +294,335 @@ func TestRulesRepository_ModifyRule(t *testing.T) {
 		ApplicationCondition: "Updated Condition",
 	}
 
-	modificationNature := fmt.Sprint("Modified Rule ", ruleID, ":")
-	modificationNature += " Changed title to: " + modification.Title
-	modificationNature += " Changed description to: " + modification.Description
-	modificationNature += " Changed application condition to: " + modification.ApplicationCondition
+	effectiveDate := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	before := models.Rule{Id: ruleID, Title: "Title", Description: "Description", EffectiveDate: effectiveDate, ApplicationCondition: "Condition", Version: version, Category: models.RuleGeneral}
+	after := models.Rule{Id: ruleID, Title: modification.Title, Description: modification.Description, EffectiveDate: effectiveDate,
+		ApplicationCondition: modification.ApplicationCondition, Version: version + 1, Category: models.RuleGeneral}
 
 	// Expect transaction begin
 	mock.ExpectBegin()
 
+	// Expect the rule to be locked to record its previous state
+	mock.ExpectQuery(`SELECT id, title, description, effective_date, application_condition, version, status, retired_at, mandatory, category, tags, translations FROM rules WHERE id = \$1 AND version = \$2 FOR UPDATE`).
+		WithArgs(ruleID, version).
+		WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+			AddRow(before.Id, before.Title, before.Description, before.EffectiveDate, before.ApplicationCondition, before.Version, before.Status, nil, false, models.RuleGeneral, "{}", "{}"))
+
 	// Expect dynamic UPDATE
-	mock.ExpectExec(`UPDATE rules SET title = \$1, description = \$2, application_condition = \$3 WHERE id = \$4`).
-		WithArgs(modification.Title, modification.Description, modification.ApplicationCondition, ruleID).
-		WillReturnResult(sqlmock.NewResult(0, 1)) // dummy result
+	mock.ExpectQuery(`UPDATE rules SET title = \$1, description = \$2, application_condition = \$3 WHERE id = \$4 AND version = \$5 RETURNING id, title, description, effective_date, application_condition, version, status, retired_at, mandatory`).
+		WithArgs(modification.Title, modification.Description, modification.ApplicationCondition, ruleID, version).
+		WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+			AddRow(after.Id, after.Title, after.Description, after.EffectiveDate, after.ApplicationCondition, after.Version, after.Status, nil, false, models.RuleGeneral, "{}", "{}"))
+
+	// Expect the new version to be kept
+	mock.ExpectExec(`INSERT INTO rule_versions`).
+		WithArgs(ruleID, version+1, after.Title, after.Description, after.EffectiveDate, after.ApplicationCondition,
+			false, models.RuleGeneral, pq.Array([]string{}), "{}", userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	// Expect audit insert
-	mock.ExpectQuery(`INSERT INTO rules_audit \(rule_id, user_id, nature_of_modification\) VALUES \(\$1, \$2, \$3\)`).
-		WithArgs(ruleID, userID, modificationNature).
-		WillReturnRows(sqlmock.NewRows([]string{"dummy"}))
+	mock.ExpectExec(`INSERT INTO rules_audit \(rule_id, user_id, approved_by, nature_of_modification, action, before, after\)`).
+		WithArgs(ruleID, userID, nil, "Modified Rule 1: title, description, application condition", models.RuleAuditUpdate,
+			ruleSnapshot(t, before), ruleSnapshot(t, after)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	// Expect commit
 	mock.ExpectCommit()
 
-	err = repo.ModifyRule(ctx, ruleID, modification, userID)
+	err = repo.ModifyRule(ctx, ruleID, modification, userID, version)
+	require.NoError(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRulesRepository_ModifyRule_VersionMismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateRulesRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM rules WHERE id = \$1 AND version = \$2 FOR UPDATE`).
+		WithArgs(1, 2).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	err = repo.ModifyRule(context.Background(), 1, models.RuleModify{Title: "Updated Title"}, 1, 2)
+	require.ErrorIs(t, err, ErrVersionMismatch)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRulesRepository_RollbackRule(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateRulesRepo(db)
+
+	effectiveDate := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	before := models.Rule{Id: 1, Title: "New", Description: "New description", EffectiveDate: effectiveDate, ApplicationCondition: "New condition", Version: 3, Category: models.RuleGeneral}
+	target := models.RuleVersion{Rule: models.Rule{Id: 1, Title: "Old", Description: "Old description", EffectiveDate: effectiveDate, ApplicationCondition: "Old condition", Version: 1,
+		Mandatory: true, Category: models.RuleAcademic, Tags: []string{"exams"}, Translations: map[string]models.RuleTranslation{"es": {Title: "Vieja", Description: "Descripción vieja"}}}}
+	after := target.Rule
+	after.Version = 4
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM rules WHERE id = \$1 AND version = \$2 FOR UPDATE`).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+			AddRow(before.Id, before.Title, before.Description, before.EffectiveDate, before.ApplicationCondition, before.Version, before.Status, nil, false, models.RuleGeneral, "{}", "{}"))
+	// The category, tags, translations and whether it's mandatory are restored too
+	translations := `{"es":{"title":"Vieja","description":"Descripción vieja"}}`
+	mock.ExpectQuery(`UPDATE rules SET title = \$1, description = \$2, effective_date = \$3, application_condition = \$4, mandatory = \$5, category = \$6, tags = \$7, translations = \$8 WHERE id = \$9 AND version = \$10 RETURNING`).
+		WithArgs(target.Title, target.Description, target.EffectiveDate, target.ApplicationCondition, true, models.RuleAcademic, pq.Array([]string{"exams"}), translations, 1, 3).
+		WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+			AddRow(after.Id, after.Title, after.Description, after.EffectiveDate, after.ApplicationCondition, after.Version, after.Status, nil, true, models.RuleAcademic, "{exams}", translations))
+	mock.ExpectExec(`INSERT INTO rule_versions`).
+		WithArgs(1, 4, after.Title, after.Description, after.EffectiveDate, after.ApplicationCondition, true, models.RuleAcademic, pq.Array([]string{"exams"}), translations, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO rules_audit`).
+		WithArgs(1, 7, nil, "Rolled back Rule 1 to version 1", models.RuleAuditRollback, ruleSnapshot(t, before), ruleSnapshot(t, after)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.RollbackRule(context.Background(), 1, target, 7, 3)
+	require.NoError(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRulesRepository_RollbackRule_UnknownFields(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateRulesRepo(db)
+
+	effectiveDate := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	before := models.Rule{Id: 1, Title: "New", Description: "New description", EffectiveDate: effectiveDate, ApplicationCondition: "New condition", Version: 3,
+		Mandatory: true, Category: models.RuleAcademic}
+	target := models.RuleVersion{Rule: models.Rule{Id: 1, Title: "Old", Description: "Old description", EffectiveDate: effectiveDate, ApplicationCondition: "Old condition", Version: 1},
+		UnknownFields: []string{"mandatory", "category", "tags", "translations"}}
+	after := before
+	after.Title, after.Description, after.ApplicationCondition, after.Version = target.Title, target.Description, target.ApplicationCondition, 4
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM rules WHERE id = \$1 AND version = \$2 FOR UPDATE`).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+			AddRow(before.Id, before.Title, before.Description, before.EffectiveDate, before.ApplicationCondition, before.Version, before.Status, nil, true, models.RuleAcademic, "{}", "{}"))
+	// Fields the target version didn't record keep their current value
+	mock.ExpectQuery(`UPDATE rules SET title = \$1, description = \$2, effective_date = \$3, application_condition = \$4 WHERE id = \$5 AND version = \$6 RETURNING`).
+		WithArgs(target.Title, target.Description, target.EffectiveDate, target.ApplicationCondition, 1, 3).
+		WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+			AddRow(after.Id, after.Title, after.Description, after.EffectiveDate, after.ApplicationCondition, after.Version, after.Status, nil, true, models.RuleAcademic, "{}", "{}"))
+	mock.ExpectExec(`INSERT INTO rule_versions`).
+		WithArgs(1, 4, after.Title, after.Description, after.EffectiveDate, after.ApplicationCondition, true, models.RuleAcademic, pq.Array([]string{}), "{}", 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO rules_audit`).
+		WithArgs(1, 7, nil, "Rolled back Rule 1 to version 1", models.RuleAuditRollback, ruleSnapshot(t, before), ruleSnapshot(t, after)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.RollbackRule(context.Background(), 1, target, 7, 3)
+	require.NoError(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRulesRepository_GetRuleVersions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateRulesRepo(db)
+
+	now := time.Now()
+	columns := []string{"rule_id", "version", "title", "description", "effective_date", "application_condition", "mandatory", "category", "tags", "translations", "created_by", "created_at"}
+	mock.ExpectQuery(`SELECT rule_id, version, title, description, effective_date, application_condition, mandatory, category, tags, translations, COALESCE\(created_by, 0\), created_at FROM rule_versions WHERE rule_id = \$1 ORDER BY version DESC`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, 2, "B", "Second", now, "Condition", true, models.RuleAcademic, "{exams}", `{"es":{"title":"B","description":"Segunda"}}`, 7, now).
+			AddRow(1, 1, "A", "First", now, "Condition", nil, nil, nil, nil, 0, now))
+	mock.ExpectQuery(`FROM rule_versions WHERE rule_id = \$1 AND version = \$2`).
+		WithArgs(1, 5).
+		WillReturnError(sql.ErrNoRows)
+
+	versions, err := repo.GetRuleVersions(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, models.RuleVersion{
+		Rule: models.Rule{Id: 1, Title: "B", Description: "Second", EffectiveDate: now, ApplicationCondition: "Condition", Version: 2,
+			Mandatory: true, Category: models.RuleAcademic, Tags: []string{"exams"}, Translations: map[string]models.RuleTranslation{"es": {Title: "B", Description: "Segunda"}}},
+		CreatedBy: 7,
+		CreatedAt: now,
+	}, versions[0])
+	// NULL columns are fields the version didn't record
+	assert.Equal(t, models.RuleVersion{
+		Rule:          models.Rule{Id: 1, Title: "A", Description: "First", EffectiveDate: now, ApplicationCondition: "Condition", Version: 1},
+		CreatedAt:     now,
+		UnknownFields: []string{"mandatory", "category", "tags", "translations"},
+	}, versions[1])
+
+	_, err = repo.GetRuleVersion(context.Background(), 1, 5)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRulesRepository_GetRules_InForceAt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateRulesRepo(db)
+
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM rules WHERE status = \$1 AND status <> 'draft' AND effective_date < \$2 AND \(retired_at IS NULL OR retired_at >= \$2\)`).
+		WithArgs(models.RuleRetired, at.AddDate(0, 0, 1)).
+		WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+			AddRow(1, "Title", "Description", at, "Condition", 3, models.RuleRetired, at.Add(time.Hour), false, models.RuleGeneral, "{}", "{}"))
+
+	rules, err := repo.GetRules(context.Background(), models.RuleListRequest{Status: models.RuleRetired, At: &at})
 	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.NotNil(t, rules[0].RetiredAt)
+	assert.Equal(t, at.Add(time.Hour), *rules[0].RetiredAt)
 
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestRulesRepository_GetRules_CategoryAndTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateRulesRepo(db)
+
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM rules WHERE category = \$1 AND tags @> \$2`).
+		WithArgs(models.RulePrivacy, pq.Array([]string{"data", "students"})).
+		WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+			AddRow(1, "Title", "Description", at, "true", 1, models.RuleActive, nil, false, models.RulePrivacy, `{data,students,exams}`,
+				`{"es":{"title":"Título","description":"Descripción"}}`))
+
+	rules, err := repo.GetRules(context.Background(), models.RuleListRequest{Category: models.RulePrivacy, Tags: []string{"data", "students"}})
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, models.RulePrivacy, rules[0].Category)
+	assert.Equal(t, []string{"data", "students", "exams"}, rules[0].Tags)
+	assert.Equal(t, map[string]models.RuleTranslation{"es": {Title: "Título", Description: "Descripción"}}, rules[0].Translations)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRuleModification(t *testing.T) {
+	columns, values, description := ruleModification(1, models.RuleModify{
+		Category:     models.RuleConduct,
+		Tags:         []string{},
+		Translations: map[string]models.RuleTranslation{"es": {Title: "Regla", Description: "Una regla"}},
+	})
+
+	assert.Equal(t, []string{"category", "tags", "translations"}, columns)
+	assert.Equal(t, []any{models.RuleConduct, pq.Array([]string{}), `{"es":{"title":"Regla","description":"Una regla"}}`}, values)
+	assert.Equal(t, "Modified Rule 1: category, tags, translations", description)
+}
+
+func TestRulesRepository_GetDueRules(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateRulesRepo(db)
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM rules WHERE status = \$1 AND effective_date <= \$2 ORDER BY effective_date, id`).
+		WithArgs(models.RuleScheduled, now).
+		WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+			AddRow(1, "Title", "Description", now.Add(-time.Hour), "Condition", 2, models.RuleScheduled, nil, false, models.RuleGeneral, "{}", "{}"))
+
+	rules, err := repo.GetDueRules(context.Background(), now)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, models.RuleScheduled, rules[0].Status)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRulesRepository_SetRuleStatus(t *testing.T) {
+	effectiveDate := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("activated by the system", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := CreateRulesRepo(db)
+		before := models.Rule{Id: 1, Title: "Title", Description: "Description", EffectiveDate: effectiveDate, ApplicationCondition: "Condition", Version: 2, Status: models.RuleScheduled, Category: models.RuleGeneral}
+		after := before
+		after.Version, after.Status = 3, models.RuleActive
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM rules WHERE id = \$1 AND version = \$2 FOR UPDATE`).
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+				AddRow(before.Id, before.Title, before.Description, before.EffectiveDate, before.ApplicationCondition, before.Version, before.Status, nil, false, models.RuleGeneral, "{}", "{}"))
+		mock.ExpectQuery(`UPDATE rules SET status = \$1 WHERE id = \$2 AND version = \$3 RETURNING`).
+			WithArgs(models.RuleActive, 1, 2).
+			WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+				AddRow(after.Id, after.Title, after.Description, after.EffectiveDate, after.ApplicationCondition, after.Version, after.Status, nil, false, models.RuleGeneral, "{}", "{}"))
+		mock.ExpectExec(`INSERT INTO rule_versions`).
+			WithArgs(1, 3, after.Title, after.Description, after.EffectiveDate, after.ApplicationCondition, false, models.RuleGeneral, pq.Array([]string{}), "{}", nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO rules_audit`).
+			WithArgs(1, nil, nil, "Activated Rule 1", models.RuleAuditActivate, ruleSnapshot(t, before), ruleSnapshot(t, after)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO jobs`).
+			WithArgs(`{"broadcast"}`, `{"{\"notification_title\":\"Rule 1\"}"}`, `{f}`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err = repo.SetRuleStatus(context.Background(), 1, models.RuleActive, 0, 2, func(id int) ([]models.Job, error) {
+			return []models.Job{{Type: models.JobBroadcast, Payload: json.RawMessage(fmt.Sprintf(`{"notification_title":"Rule %d"}`, id))}}, nil
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("retired by an admin", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := CreateRulesRepo(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM rules WHERE id = \$1 AND version = \$2 FOR UPDATE`).
+			WithArgs(1, 3).
+			WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+				AddRow(1, "Title", "Description", effectiveDate, "Condition", 3, models.RuleActive, nil, false, models.RuleGeneral, "{}", "{}"))
+		mock.ExpectQuery(`UPDATE rules SET status = \$1, retired_at = \$2 WHERE id = \$3 AND version = \$4 RETURNING`).
+			WithArgs(models.RuleRetired, sqlmock.AnyArg(), 1, 3).
+			WillReturnRows(sqlmock.NewRows(ruleRowColumns).
+				AddRow(1, "Title", "Description", effectiveDate, "Condition", 4, models.RuleRetired, time.Now(), false, models.RuleGeneral, "{}", "{}"))
+		mock.ExpectExec(`INSERT INTO rule_versions`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO rules_audit`).
+			WithArgs(1, 7, nil, "Retired Rule 1", models.RuleAuditRetire, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		require.NoError(t, repo.SetRuleStatus(context.Background(), 1, models.RuleRetired, 7, 3, nil))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		db, _, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		require.Error(t, CreateRulesRepo(db).SetRuleStatus(context.Background(), 1, models.RuleDraft, 7, 3, nil))
+	})
+}
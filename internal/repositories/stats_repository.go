@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+)
+
+type StatsRepository interface {
+	GetStats(ctx context.Context, from time.Time, until time.Time, interval string) (*models.AdminStats, error)
+}
+
+type statsRepository struct {
+	DB *sql.DB
+}
+
+func NewStatsRepository(db *sql.DB) *statsRepository {
+	return &statsRepository{DB: db}
+}
+
+// GetStats computes the dashboard statistics for activity between from, included, and until,
+// excluded. All the queries see the same snapshot of the database.
+func (db statsRepository) GetStats(ctx context.Context, from time.Time, until time.Time, interval string) (*models.AdminStats, error) {
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	stats := &models.AdminStats{
+		Interval:           interval,
+		NotificationTokens: map[string]int{"expo": 0, "fcm": 0},
+	}
+	stats.Users.ByRole = map[string]int{"student": 0, "teacher": 0, "admin": 0}
+
+	if err := countUsers(ctx, tx, &stats.Users); err != nil {
+		return nil, err
+	}
+	if stats.Signups, err = countSignups(ctx, tx, from, until, interval); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE NOT successful), COUNT(DISTINCT user_id) FILTER (WHERE successful)
+		FROM login_attempts
+		WHERE created_at >= $1 AND created_at < $2`, from, until,
+	).Scan(&stats.Logins.Attempts, &stats.Logins.Failed, &stats.Logins.ActiveUsers)
+	if err != nil {
+		return nil, err
+	}
+	if stats.Logins.Attempts > 0 {
+		stats.Logins.FailedRate = float64(stats.Logins.Failed) / float64(stats.Logins.Attempts)
+	}
+
+	// Same rule the notification sender uses to pick the provider
+	if err := countGrouped(ctx, tx, stats.NotificationTokens, `
+		SELECT CASE WHEN token LIKE '%ExponentPushToken[%' THEN 'expo' ELSE 'fcm' END, COUNT(*)
+		FROM notifications
+		GROUP BY 1`); err != nil {
+		return nil, err
+	}
+
+	// Messages are stored with rating 0 until the user rates them
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE sender = 'user'), COUNT(DISTINCT user_id),
+		       COUNT(*) FILTER (WHERE rating > 0), AVG(rating) FILTER (WHERE rating > 0)
+		FROM ai_chat
+		WHERE time_sent >= $1 AND time_sent < $2`, from, until,
+	).Scan(&stats.Chat.Messages, &stats.Chat.UserMessages, &stats.Chat.ActiveUsers,
+		&stats.Chat.RatedMessages, &stats.Chat.AverageRating)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func countUsers(ctx context.Context, tx *sql.Tx, users *models.UserStats) error {
+	if err := countGrouped(ctx, tx, users.ByRole, "SELECT role, COUNT(*) FROM users WHERE deleted_at IS NULL GROUP BY role"); err != nil {
+		return err
+	}
+	err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE verified), COUNT(*) FILTER (WHERE `+activeBlockExists+`)
+		FROM users
+		WHERE deleted_at IS NULL`,
+	).Scan(&users.Total, &users.Verified, &users.Blocked)
+	users.Unverified = users.Total - users.Verified
+	return err
+}
+
+// countSignups returns a bucket for every day or week of the range, including the ones without signups
+func countSignups(ctx context.Context, tx *sql.Tx, from time.Time, until time.Time, interval string) ([]models.StatsBucket, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT date_trunc($3, created_at) AS bucket, COUNT(*)
+		FROM users
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY bucket`, from, until, interval)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[time.Time]int)
+	for rows.Next() {
+		var bucket time.Time
+		var count int
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, err
+		}
+		counts[bucket.UTC()] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var buckets []models.StatsBucket
+	for start := truncateToInterval(from, interval); start.Before(until); {
+		buckets = append(buckets, models.StatsBucket{Start: start, Count: counts[start]})
+		if interval == models.StatsIntervalWeek {
+			start = start.AddDate(0, 0, 7)
+		} else {
+			start = start.AddDate(0, 0, 1)
+		}
+	}
+	return buckets, nil
+}
+
+// truncateToInterval does what date_trunc does for days and weeks, which start on Monday
+func truncateToInterval(t time.Time, interval string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if interval != models.StatsIntervalWeek {
+		return day
+	}
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+func countGrouped(ctx context.Context, tx *sql.Tx, counts map[string]int, query string) error {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return err
+		}
+		counts[key] = count
+	}
+	return rows.Err()
+}
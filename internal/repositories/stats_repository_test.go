@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsRepository_GetStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT role, COUNT\(\*\) FROM users WHERE deleted_at IS NULL GROUP BY role`).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "count"}).AddRow("student", 8).AddRow("admin", 1))
+	mock.ExpectQuery(`SELECT COUNT\(\*\), COUNT\(\*\) FILTER \(WHERE verified\), COUNT\(\*\) FILTER \(WHERE EXISTS`).
+		WillReturnRows(sqlmock.NewRows([]string{"total", "verified", "blocked"}).AddRow(9, 7, 2))
+	mock.ExpectQuery(`SELECT date_trunc\(\$3, created_at\) AS bucket, COUNT\(\*\) FROM users`).
+		WithArgs(from, until, models.StatsIntervalDay).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "count"}).AddRow(from.AddDate(0, 0, 1), 4))
+	mock.ExpectQuery(`FROM login_attempts WHERE created_at >= \$1 AND created_at < \$2`).
+		WithArgs(from, until).
+		WillReturnRows(sqlmock.NewRows([]string{"attempts", "failed", "active"}).AddRow(20, 5, 6))
+	mock.ExpectQuery(`FROM notifications GROUP BY 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"provider", "count"}).AddRow("expo", 3))
+	mock.ExpectQuery(`FROM ai_chat WHERE time_sent >= \$1 AND time_sent < \$2`).
+		WithArgs(from, until).
+		WillReturnRows(sqlmock.NewRows([]string{"messages", "user_messages", "active", "rated", "avg"}).AddRow(10, 5, 2, 2, 4.5))
+	mock.ExpectRollback()
+
+	stats, err := NewStatsRepository(db).GetStats(context.Background(), from, until, models.StatsIntervalDay)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.UserStats{
+		Total:      9,
+		ByRole:     map[string]int{"student": 8, "teacher": 0, "admin": 1},
+		Verified:   7,
+		Unverified: 2,
+		Blocked:    2,
+	}, stats.Users)
+	assert.Equal(t, []models.StatsBucket{
+		{Start: from, Count: 0},
+		{Start: from.AddDate(0, 0, 1), Count: 4},
+		{Start: from.AddDate(0, 0, 2), Count: 0},
+	}, stats.Signups)
+	assert.Equal(t, models.LoginStats{Attempts: 20, Failed: 5, FailedRate: 0.25, ActiveUsers: 6}, stats.Logins)
+	assert.Equal(t, map[string]int{"expo": 3, "fcm": 0}, stats.NotificationTokens)
+	require.NotNil(t, stats.Chat.AverageRating)
+	assert.Equal(t, 4.5, *stats.Chat.AverageRating)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTruncateToInterval(t *testing.T) {
+	// 2025-03-06 is a Thursday
+	thursday := time.Date(2025, 3, 6, 15, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC), truncateToInterval(thursday, models.StatsIntervalDay))
+	assert.Equal(t, time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), truncateToInterval(thursday, models.StatsIntervalWeek))
+
+	sunday := time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), truncateToInterval(sunday, models.StatsIntervalWeek))
+}
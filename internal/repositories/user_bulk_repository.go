@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/lib/pq"
+)
+
+type UserBulkRepository interface {
+	RunBulkAction(ctx context.Context, action models.BulkUserAction) (*models.BulkUserActionReport, error)
+}
+
+type userBulkRepository struct {
+	DB *sql.DB
+}
+
+func NewUserBulkRepository(db *sql.DB) *userBulkRepository {
+	return &userBulkRepository{DB: db}
+}
+
+// bulkActionParams is what gets recorded of the action besides the affected users
+type bulkActionParams struct {
+	Reason       string             `json:"reason,omitempty"`
+	BlockedUntil *time.Time         `json:"blocked_until,omitempty"`
+	Role         string             `json:"role,omitempty"`
+	Filter       *models.UserFilter `json:"filter,omitempty"`
+}
+
+// RunBulkAction applies the action to the users in a single transaction and records it in
+// user_bulk_actions. The actor is never changed by their own action. Users that can't be
+// found or are already in the target state are reported but don't make the action fail.
+func (db userBulkRepository) RunBulkAction(ctx context.Context, action models.BulkUserAction) (*models.BulkUserActionReport, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	requested, found, err := lockBulkTargets(ctx, tx, action)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []int
+	for _, id := range requested {
+		if found[id] && id != action.ActorId {
+			targets = append(targets, id)
+		}
+	}
+
+	updated := make(map[int]bool)
+	if len(targets) > 0 {
+		if updated, err = applyBulkAction(ctx, tx, action, targets); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &models.BulkUserActionReport{
+		Action:  action.Action,
+		Total:   len(requested),
+		Updated: len(updated),
+		Results: make([]models.BulkUserResult, len(requested)),
+	}
+	for i, id := range requested {
+		status := models.BulkResultUnchanged
+		switch {
+		case !found[id]:
+			status = models.BulkResultNotFound
+		case id == action.ActorId:
+			status = models.BulkResultSkipped
+		case updated[id]:
+			status = models.BulkResultUpdated
+		}
+		report.Results[i] = models.BulkUserResult{Id: id, Status: status}
+	}
+
+	affected := make([]int, 0, len(updated))
+	for id := range updated {
+		affected = append(affected, id)
+	}
+	sort.Ints(affected)
+	params, err := json.Marshal(bulkActionParams{
+		Reason:       action.Reason,
+		BlockedUntil: action.BlockedUntil,
+		Role:         action.Role,
+		Filter:       action.Filter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO user_bulk_actions (actor_id, action, params, user_ids)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`,
+		action.ActorId, action.Action, params, pq.Array(affected),
+	).Scan(&report.AuditId)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, tx.Commit()
+}
+
+// lockBulkTargets returns the ids the action was asked to change, without duplicates, and which
+// of them exist. Their rows stay locked until the transaction ends.
+func lockBulkTargets(ctx context.Context, tx *sql.Tx, action models.BulkUserAction) ([]int, map[int]bool, error) {
+	var requested []int
+	seen := make(map[int]bool, len(action.Ids))
+	for _, id := range action.Ids {
+		if !seen[id] {
+			seen[id] = true
+			requested = append(requested, id)
+		}
+	}
+
+	var rows *sql.Rows
+	var err error
+	if action.Filter != nil {
+		where, args := userFilterClause(*action.Filter, 1)
+		rows, err = tx.QueryContext(ctx, fmt.Sprintf("SELECT id FROM users%s ORDER BY id LIMIT %d FOR UPDATE", where, models.MaxBulkUsers+1), args...)
+	} else {
+		rows, err = tx.QueryContext(ctx, "SELECT id FROM users WHERE id = ANY($1) AND deleted_at IS NULL ORDER BY id FOR UPDATE", pq.Array(requested))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	found := make(map[int]bool)
+	var matched []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, nil, err
+		}
+		found[id] = true
+		matched = append(matched, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if action.Filter != nil {
+		if len(matched) > models.MaxBulkUsers {
+			return nil, nil, models.ErrBulkTooManyUsers
+		}
+		return matched, found, nil
+	}
+	return requested, found, nil
+}
+
+// applyBulkAction changes the users and returns the ids of the ones that actually changed
+func applyBulkAction(ctx context.Context, tx *sql.Tx, action models.BulkUserAction, ids []int) (map[int]bool, error) {
+	var query string
+	args := []any{pq.Array(ids)}
+	switch action.Action {
+	case models.BulkActionBlock:
+		query = `
+			INSERT INTO blocked_users (blocked_user_id, reason, blocker_id, blocked_until, created_at)
+			SELECT target.id, $2, $3, $4, NOW() FROM unnest($1::int[]) AS target(id)
+			WHERE NOT EXISTS(
+				SELECT 1 FROM blocked_users
+				WHERE blocked_user_id = target.id
+				AND (blocked_until IS NULL OR blocked_until > NOW())
+			)
+			RETURNING blocked_user_id`
+		args = append(args, action.Reason, action.ActorId, action.BlockedUntil)
+	case models.BulkActionUnblock:
+		query = `
+			UPDATE blocked_users
+			SET blocked_until = NOW(), reason = reason || ' [UNBLOCK]'
+			WHERE blocked_user_id = ANY($1) AND (blocked_until IS NULL OR blocked_until > NOW())
+			RETURNING blocked_user_id`
+	case models.BulkActionChangeRole:
+		// Tokens carry the role, so the ones issued with the old role are revoked
+		query = `
+			UPDATE users SET role = $2, sessions_valid_after = NOW()
+			WHERE id = ANY($1) AND role <> $2
+			RETURNING id`
+		args = append(args, action.Role)
+	case models.BulkActionVerify:
+		query = "UPDATE users SET verified = true WHERE id = ANY($1) AND NOT verified RETURNING id"
+	case models.BulkActionSoftDelete:
+		query = `
+			UPDATE users SET deleted_at = NOW(), sessions_valid_after = NOW()
+			WHERE id = ANY($1) AND deleted_at IS NULL
+			RETURNING id`
+	default:
+		return nil, fmt.Errorf("unknown bulk action %q", action.Action)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	updated := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		updated[id] = true
+	}
+	return updated, rows.Err()
+}
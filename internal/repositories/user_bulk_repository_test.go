@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserBulkRepository_RunBulkAction_Ids(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM users WHERE id = ANY\(\$1\) AND deleted_at IS NULL ORDER BY id FOR UPDATE`).
+		WithArgs(pq.Array([]int{4, 2, 3, 1})).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3))
+	mock.ExpectQuery(`UPDATE users SET verified = true WHERE id = ANY\(\$1\) AND NOT verified RETURNING id`).
+		WithArgs(pq.Array([]int{2, 3})).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(`INSERT INTO user_bulk_actions \(actor_id, action, params, user_ids\)`).
+		WithArgs(1, models.BulkActionVerify, []byte(`{}`), pq.Array([]int{3})).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	mock.ExpectCommit()
+
+	report, err := NewUserBulkRepository(db).RunBulkAction(context.Background(), models.BulkUserAction{
+		ActorId: 1,
+		Action:  models.BulkActionVerify,
+		Ids:     []int{4, 2, 3, 1, 2},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, &models.BulkUserActionReport{
+		AuditId: 11,
+		Action:  models.BulkActionVerify,
+		Total:   4,
+		Updated: 1,
+		Results: []models.BulkUserResult{
+			{Id: 4, Status: models.BulkResultNotFound},
+			{Id: 2, Status: models.BulkResultUnchanged},
+			{Id: 3, Status: models.BulkResultUpdated},
+			{Id: 1, Status: models.BulkResultSkipped},
+		},
+	}, report)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserBulkRepository_RunBulkAction_Filter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM users WHERE deleted_at IS NULL AND role = \$1 ORDER BY id LIMIT 1001 FOR UPDATE`).
+		WithArgs("teacher").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5).AddRow(6))
+	mock.ExpectQuery(`INSERT INTO blocked_users .+ FROM unnest\(\$1::int\[\]\) AS target\(id\)`).
+		WithArgs(pq.Array([]int{5, 6}), "cheating", 1, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"blocked_user_id"}).AddRow(5).AddRow(6))
+	mock.ExpectQuery(`INSERT INTO user_bulk_actions`).
+		WithArgs(1, models.BulkActionBlock, []byte(`{"reason":"cheating","filter":{"role":"teacher","verified":null,"blocked":null,"q":"","created_from":null,"created_to":null}}`), pq.Array([]int{5, 6})).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+	mock.ExpectCommit()
+
+	report, err := NewUserBulkRepository(db).RunBulkAction(context.Background(), models.BulkUserAction{
+		ActorId: 1,
+		Action:  models.BulkActionBlock,
+		Filter:  &models.UserFilter{Role: "teacher"},
+		Reason:  "cheating",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserBulkRepository_RunBulkAction_TooManyUsers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id"})
+	for id := 1; id <= models.MaxBulkUsers+1; id++ {
+		rows.AddRow(id)
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM users WHERE deleted_at IS NULL AND role = \$1`).WillReturnRows(rows)
+	mock.ExpectRollback()
+
+	_, err = NewUserBulkRepository(db).RunBulkAction(context.Background(), models.BulkUserAction{
+		ActorId: 1,
+		Action:  models.BulkActionSoftDelete,
+		Filter:  &models.UserFilter{Role: "student"},
+	})
+
+	assert.ErrorIs(t, err, models.ErrBulkTooManyUsers)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserBulkRepository_RunBulkAction_RollsBackOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM users WHERE id = ANY`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(`UPDATE users SET deleted_at = NOW\(\), sessions_valid_after = NOW\(\)`).
+		WillReturnError(errors.New("db error"))
+	mock.ExpectRollback()
+
+	_, err = NewUserBulkRepository(db).RunBulkAction(context.Background(), models.BulkUserAction{
+		ActorId: 1,
+		Action:  models.BulkActionSoftDelete,
+		Ids:     []int{2},
+	})
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
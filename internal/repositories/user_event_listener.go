@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/lib/pq"
+)
+
+// UserEventListener receives the user events the database announces, whichever instance of the API caused them
+type UserEventListener interface {
+	// Listen calls fn with every user event until the context is done. The connection is reestablished
+	// when it drops, and reconnected is called then, since the events sent in between were lost.
+	Listen(ctx context.Context, fn func(models.UserEvent), reconnected func()) error
+}
+
+// Bounds of the wait between attempts to reconnect, and how often an idle connection is checked
+const (
+	listenerMinReconnect = 10 * time.Second
+	listenerMaxReconnect = time.Minute
+	listenerPing         = 90 * time.Second
+)
+
+type userEventListener struct {
+	databaseURL string
+}
+
+// NewUserEventListener returns a listener with its own connection to the database, LISTEN needs one
+func NewUserEventListener(databaseURL string) *userEventListener {
+	return &userEventListener{databaseURL: databaseURL}
+}
+
+func (l userEventListener) Listen(ctx context.Context, fn func(models.UserEvent), reconnected func()) error {
+	listener := pq.NewListener(l.databaseURL, listenerMinReconnect, listenerMaxReconnect, nil)
+	defer listener.Close()
+	if err := listener.Listen(models.UserEventsChannel); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-listener.Notify:
+			// A nil notification means the connection was reestablished
+			if notification == nil {
+				reconnected()
+				continue
+			}
+			event, err := parseUserEvent(notification.Extra)
+			if err != nil {
+				continue
+			}
+			fn(event)
+		case <-time.After(listenerPing):
+			go listener.Ping()
+		}
+	}
+}
+
+func parseUserEvent(payload string) (models.UserEvent, error) {
+	var event models.UserEvent
+	err := json.Unmarshal([]byte(payload), &event)
+	return event, err
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUserEvent(t *testing.T) {
+	event, err := parseUserEvent(`{"user_id" : 3, "type" : "inbox", "id" : 12}`)
+	require.NoError(t, err)
+	assert.Equal(t, models.UserEvent{Id: 12, UserId: 3, Type: models.UserEventInbox}, event)
+
+	// Only inbox events have an id
+	event, err = parseUserEvent(`{"user_id" : 3, "type" : "logout", "id" : null}`)
+	require.NoError(t, err)
+	assert.Equal(t, models.UserEvent{UserId: 3, Type: models.UserEventLogout}, event)
+
+	_, err = parseUserEvent(`not json`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+)
+
+const activeBlockExists = `EXISTS(
+		SELECT 1 FROM blocked_users
+		WHERE blocked_user_id = users.id
+		AND (blocked_until IS NULL OR blocked_until > NOW())
+	)`
+
+// userFilterClause builds the WHERE clause of a query on the users table and its arguments,
+// numbered from the given position. Soft deleted users never match.
+func userFilterClause(filter models.UserFilter, firstArg int) (string, []any) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []any
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", firstArg+len(args)-1)
+	}
+
+	if filter.Role != "" {
+		conditions = append(conditions, "role = "+arg(filter.Role))
+	}
+	if filter.Verified != nil {
+		conditions = append(conditions, "verified = "+arg(*filter.Verified))
+	}
+	if filter.Blocked != nil {
+		if *filter.Blocked {
+			conditions = append(conditions, activeBlockExists)
+		} else {
+			conditions = append(conditions, "NOT "+activeBlockExists)
+		}
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		pattern := arg("%" + escapeLike(search) + "%")
+		conditions = append(conditions, fmt.Sprintf("(name ILIKE %[1]s OR surname ILIKE %[1]s OR email ILIKE %[1]s)", pattern))
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= "+arg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		// The day given is included
+		conditions = append(conditions, "created_at < "+arg(filter.CreatedTo.AddDate(0, 0, 1)))
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// escapeLike escapes the LIKE wildcards so the text is matched literally
+func escapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(text)
+}
@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/lib/pq"
+)
+
+type UserImportRepository interface {
+	ExistingEmails(ctx context.Context, emails []string) (map[string]bool, error)
+	AddImportedUser(ctx context.Context, user *models.User) (int, error)
+	AddImportedUsers(ctx context.Context, users []*models.User) ([]int, error)
+	CreateImportJob(ctx context.Context, createdBy int) (int, error)
+	FinishImportJob(ctx context.Context, id int, report *models.UserImportReport, failure error) error
+	GetImportJob(ctx context.Context, id int) (*models.UserImportJob, error)
+	// TouchImportJob records that a running job is still alive
+	TouchImportJob(ctx context.Context, id int) error
+	// FailStaleImportJobs marks as failed the running jobs without a heartbeat for longer than staleAfter
+	// and returns how many there were
+	FailStaleImportJobs(ctx context.Context, staleAfter time.Duration, reason string) (int64, error)
+}
+
+// ImportRowError reports which user of a batch made the whole batch fail
+type ImportRowError struct {
+	Index int
+	Err   error
+}
+
+func (e *ImportRowError) Error() string {
+	return fmt.Sprintf("user %d: %s", e.Index, e.Err)
+}
+
+func (e *ImportRowError) Unwrap() error {
+	return e.Err
+}
+
+type userImportRepository struct {
+	DB *sql.DB
+}
+
+func NewUserImportRepository(db *sql.DB) *userImportRepository {
+	return &userImportRepository{DB: db}
+}
+
+// ExistingEmails returns which of the given emails, lowercased, already belong to a user
+func (db userImportRepository) ExistingEmails(ctx context.Context, emails []string) (map[string]bool, error) {
+	lowered := make([]string, len(emails))
+	for i, email := range emails {
+		lowered[i] = strings.ToLower(email)
+	}
+
+	rows, err := db.DB.QueryContext(ctx, "SELECT LOWER(email) FROM users WHERE LOWER(email) = ANY($1) AND deleted_at IS NULL", pq.Array(lowered))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		existing[email] = true
+	}
+	return existing, rows.Err()
+}
+
+func (db userImportRepository) AddImportedUser(ctx context.Context, user *models.User) (int, error) {
+	return insertImportedUser(ctx, db.DB, user)
+}
+
+// AddImportedUsers creates every user or none of them. When one fails the error is an *ImportRowError.
+func (db userImportRepository) AddImportedUsers(ctx context.Context, users []*models.User) ([]int, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(users))
+	for i, user := range users {
+		ids[i], err = insertImportedUser(ctx, tx, user)
+		if err != nil {
+			tx.Rollback()
+			return nil, &ImportRowError{Index: i, Err: err}
+		}
+	}
+
+	return ids, tx.Commit()
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func insertImportedUser(ctx context.Context, db queryRower, user *models.User) (int, error) {
+	query := `
+		INSERT INTO users (name, surname, password, email, role, verified)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+	var id int
+	err := db.QueryRowContext(ctx, query, user.Name, user.Surname, user.Password, user.Email, user.Role, user.Verified).Scan(&id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return 0, ErrEmailTaken
+	}
+	return id, err
+}
+
+func (db userImportRepository) CreateImportJob(ctx context.Context, createdBy int) (int, error) {
+	var id int
+	err := db.DB.QueryRowContext(ctx,
+		"INSERT INTO user_import_jobs (status, created_by) VALUES ($1, $2) RETURNING id",
+		models.ImportJobRunning, createdBy,
+	).Scan(&id)
+	return id, err
+}
+
+// FinishImportJob stores the outcome of a job, it failed if failure isn't nil
+func (db userImportRepository) FinishImportJob(ctx context.Context, id int, report *models.UserImportReport, failure error) error {
+	status := models.ImportJobDone
+	var errMsg sql.NullString
+	if failure != nil {
+		status = models.ImportJobFailed
+		errMsg = sql.NullString{String: failure.Error(), Valid: true}
+	}
+
+	var reportJSON []byte
+	if report != nil {
+		var err error
+		if reportJSON, err = json.Marshal(report); err != nil {
+			return err
+		}
+	}
+
+	_, err := db.DB.ExecContext(ctx,
+		"UPDATE user_import_jobs SET status = $2, report = $3, error = $4, finished_at = NOW() WHERE id = $1",
+		id, status, reportJSON, errMsg)
+	return err
+}
+
+func (db userImportRepository) TouchImportJob(ctx context.Context, id int) error {
+	_, err := db.DB.ExecContext(ctx,
+		"UPDATE user_import_jobs SET heartbeat_at = NOW() WHERE id = $1 AND status = $2",
+		id, models.ImportJobRunning)
+	return err
+}
+
+func (db userImportRepository) FailStaleImportJobs(ctx context.Context, staleAfter time.Duration, reason string) (int64, error) {
+	result, err := db.DB.ExecContext(ctx,
+		"UPDATE user_import_jobs SET status = $1, error = $2, finished_at = NOW() WHERE status = $3 AND heartbeat_at < NOW() - $4 * INTERVAL '1 millisecond'",
+		models.ImportJobFailed, reason, models.ImportJobRunning, staleAfter.Milliseconds())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (db userImportRepository) GetImportJob(ctx context.Context, id int) (*models.UserImportJob, error) {
+	var (
+		job        models.UserImportJob
+		createdBy  sql.NullInt64
+		reportJSON []byte
+		errMsg     sql.NullString
+		finishedAt sql.NullTime
+	)
+	err := db.DB.QueryRowContext(ctx,
+		"SELECT id, status, created_by, report, error, created_at, finished_at FROM user_import_jobs WHERE id = $1", id,
+	).Scan(&job.Id, &job.Status, &createdBy, &reportJSON, &errMsg, &job.CreatedAt, &finishedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job.CreatedBy = int(createdBy.Int64)
+	job.Error = errMsg.String
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	if len(reportJSON) > 0 {
+		job.Report = &models.UserImportReport{}
+		if err := json.Unmarshal(reportJSON, job.Report); err != nil {
+			return nil, err
+		}
+	}
+	return &job, nil
+}
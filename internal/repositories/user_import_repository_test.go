@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserImportRepository_ExistingEmails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserImportRepository(db)
+
+	mock.ExpectQuery(`SELECT LOWER\(email\) FROM users WHERE LOWER\(email\) = ANY\(\$1\) AND deleted_at IS NULL`).
+		WithArgs(pq.Array([]string{"ana@example.com", "juan@example.com"})).
+		WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow("ana@example.com"))
+
+	existing, err := repo.ExistingEmails(context.Background(), []string{"Ana@example.com", "juan@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"ana@example.com": true}, existing)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserImportRepository_AddImportedUser_EmailTaken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserImportRepository(db)
+	user := &models.User{Email: "ana@example.com", Name: "Ana", Surname: "Lopez", Password: "hash", Role: "student", Verified: true}
+
+	mock.ExpectQuery(`INSERT INTO users`).
+		WithArgs(user.Name, user.Surname, user.Password, user.Email, user.Role, user.Verified).
+		WillReturnError(&pq.Error{Code: uniqueViolation})
+
+	_, err = repo.AddImportedUser(context.Background(), user)
+	assert.ErrorIs(t, err, ErrEmailTaken)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserImportRepository_AddImportedUsers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserImportRepository(db)
+	users := []*models.User{
+		{Email: "ana@example.com", Name: "Ana", Surname: "Lopez", Password: "hash", Role: "student"},
+		{Email: "juan@example.com", Name: "Juan", Surname: "Perez", Password: "hash", Role: "teacher"},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO users`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	mock.ExpectQuery(`INSERT INTO users`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	mock.ExpectCommit()
+
+	ids, err := repo.AddImportedUsers(context.Background(), users)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{10, 11}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserImportRepository_AddImportedUsers_RollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserImportRepository(db)
+	users := []*models.User{{Email: "ana@example.com"}, {Email: "juan@example.com"}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO users`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	mock.ExpectQuery(`INSERT INTO users`).WillReturnError(&pq.Error{Code: uniqueViolation})
+	mock.ExpectRollback()
+
+	ids, err := repo.AddImportedUsers(context.Background(), users)
+	assert.Nil(t, ids)
+
+	var rowErr *ImportRowError
+	require.ErrorAs(t, err, &rowErr)
+	assert.Equal(t, 1, rowErr.Index)
+	assert.ErrorIs(t, err, ErrEmailTaken)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserImportRepository_CreateImportJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserImportRepository(db)
+
+	mock.ExpectQuery(`INSERT INTO user_import_jobs \(status, created_by\) VALUES \(\$1, \$2\) RETURNING id`).
+		WithArgs(models.ImportJobRunning, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	id, err := repo.CreateImportJob(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserImportRepository_FinishImportJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserImportRepository(db)
+
+	mock.ExpectExec(`UPDATE user_import_jobs SET status = \$2, report = \$3, error = \$4, finished_at = NOW\(\) WHERE id = \$1`).
+		WithArgs(3, models.ImportJobDone, []byte(`{"dry_run":false,"mode":"transactional","total":1,"valid":1,"created":1,"failed":0,"rows":null}`), sql.NullString{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE user_import_jobs`).
+		WithArgs(3, models.ImportJobFailed, []byte(nil), sql.NullString{String: "db down", Valid: true}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	report := &models.UserImportReport{Mode: models.ImportModeTransactional, Total: 1, Valid: 1, Created: 1}
+	assert.NoError(t, repo.FinishImportJob(context.Background(), 3, report, nil))
+	assert.NoError(t, repo.FinishImportJob(context.Background(), 3, nil, errors.New("db down")))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserImportRepository_FailStaleImportJobs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserImportRepository(db)
+
+	mock.ExpectExec(`UPDATE user_import_jobs SET status = \$1, error = \$2, finished_at = NOW\(\) WHERE status = \$3 AND heartbeat_at < NOW\(\) - \$4 \* INTERVAL '1 millisecond'`).
+		WithArgs(models.ImportJobFailed, "interrupted", models.ImportJobRunning, int64(90000)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	failed, err := repo.FailStaleImportJobs(context.Background(), 90*time.Second, "interrupted")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), failed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserImportRepository_GetImportJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserImportRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT id, status, created_by, report, error, created_at, finished_at FROM user_import_jobs WHERE id = \$1`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_by", "report", "error", "created_at", "finished_at"}).
+			AddRow(3, models.ImportJobDone, 1, []byte(`{"total":2,"created":2}`), nil, now, now))
+	mock.ExpectQuery(`SELECT id, status, created_by, report, error, created_at, finished_at FROM user_import_jobs WHERE id = \$1`).
+		WithArgs(4).
+		WillReturnError(sql.ErrNoRows)
+
+	job, err := repo.GetImportJob(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportJobDone, job.Status)
+	assert.Equal(t, 1, job.CreatedBy)
+	assert.Equal(t, 2, job.Report.Created)
+	assert.Equal(t, now, *job.FinishedAt)
+
+	_, err = repo.GetImportJob(context.Background(), 4)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
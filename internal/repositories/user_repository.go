@@ -3,32 +3,57 @@ package repositories
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"errors"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type UserRepository interface {
 	GetUser(ctx context.Context, id int) (*models.User, error)
-	GetAllUsers(ctx context.Context) ([]models.User, error)
-	DeleteUser(ctx context.Context, id int) error
+	GetAllUsers(ctx context.Context, filter models.UserFilter) ([]models.User, error)
+	StreamUsers(ctx context.Context, filter models.UserFilter, fn func(models.User) error) error
+	DeleteUser(ctx context.Context, id int, version int) error
 	AddUser(ctx context.Context, user *models.User) (int, error)
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	ModifyUser(ctx context.Context, user *models.User) error
 	ModifyPassword(ctx context.Context, id int, password string) error
+	// RehashPassword replaces the hash of the same password, if it is still the current one, without recording a new password
+	RehashPassword(ctx context.Context, id int, currentHash string, newHash string) error
 	AddNotificationToken(ctx context.Context, id int, text string) error
 	GetUserNotificationsToken(ctx context.Context, id int) (models.NotificationTokens, error)
 	SetVerifiedTrue(ctx context.Context, id int) error
-	AddPasswordResetToken(ctx context.Context, id int, email string, token string, tokenExpiration time.Time) error
-	GetPasswordResetTokenInfo(ctx context.Context, token string) (*models.PasswordResetData, error)
-	SetPasswordTokenUsed(ctx context.Context, token string) error
-	SetNotificationPreference(ctx context.Context, id int, preference models.NotificationPreferenceRequest) error
-	CheckPreference(ctx context.Context, id int, notificationType string) (bool, error)
-	GetNotificationPreference(ctx context.Context, id int) (*models.NotificationPreference, error)
+	AddPasswordResetToken(ctx context.Context, id int, email string, tokenHash string, tokenExpiration time.Time, outbox models.Outbox) (int, error)
+	// AddPasswordResetAttempt counts an attempt to use the token, returning it with the attempts made so far
+	AddPasswordResetAttempt(ctx context.Context, id int) (*models.PasswordResetData, error)
+	// ReleasePasswordResetAttempt takes back an attempt that turned out to have the right secret
+	ReleasePasswordResetAttempt(ctx context.Context, id int) error
+	// ResetPassword uses up the reset token and changes the password of its user, both or neither
+	ResetPassword(ctx context.Context, tokenId int, userId int, password string) error
+	GetNotificationTypes(ctx context.Context) ([]models.NotificationType, error)
+	// AddNotificationType registers a new type of notification, returning ErrNotificationTypeExists if there's one with the name
+	AddNotificationType(ctx context.Context, notificationType *models.NotificationType) error
+	// GetNotificationPreferences returns by which channels the user gets every registered type of notification
+	GetNotificationPreferences(ctx context.Context, id int) (models.NotificationPreferences, error)
+	// GetNotificationChannels returns by which channels the user gets the type of notification, ErrNotFound if it isn't registered
+	GetNotificationChannels(ctx context.Context, id int, notificationType string) (map[string]bool, error)
+	// SetNotificationPreferences saves the preferences given, leaving the rest of the matrix as it is
+	SetNotificationPreferences(ctx context.Context, id int, preferences models.NotificationPreferences) error
 	MakeTeacher(ctx context.Context, id int) error
+	EmailExists(ctx context.Context, email string) (bool, error)
+	AddEmailChangeRequest(ctx context.Context, request *models.EmailChangeRequest, outbox models.Outbox) (int, error)
+	// AddEmailChangeAttempt counts an attempt to confirm the change, returning it with the attempts made so far
+	AddEmailChangeAttempt(ctx context.Context, id int) (*models.EmailChangeRequest, error)
+	// ReleaseEmailChangeAttempt takes back an attempt that turned out to have the right pin
+	ReleaseEmailChangeAttempt(ctx context.Context, id int) error
+	DeleteEmailChangeRequestByCancelToken(ctx context.Context, tokenHash string) error
+	ApplyEmailChange(ctx context.Context, request *models.EmailChangeRequest) error
+	GetSessionsValidAfter(ctx context.Context, id int) (*time.Time, error)
+	GetPasswordHistory(ctx context.Context, id int, limit int) ([]string, error)
 }
 
 type userRepository struct {
@@ -49,14 +74,14 @@ func (db userRepository) GetUser(ctx context.Context, id int) (*models.User, err
 	query := `
 		SELECT
 			u.id, u.name, u.surname, u.password, u.email, u.location, u.role, u.verified,
-			u.profile_photo, u.description, u.created_at, u.updated_at,
+			u.profile_photo, u.description, u.created_at, u.updated_at, u.version,
 			EXISTS(
 				SELECT 1 FROM blocked_users
 				WHERE blocked_user_id = u.id
 				AND (blocked_until IS NULL OR blocked_until > NOW())
 			) AS blocked
 		FROM users u
-		WHERE u.id = $1
+		WHERE u.id = $1 AND u.deleted_at IS NULL
 		LIMIT 1`
 
 	row := db.DB.QueryRowContext(ctx, query, id)
@@ -65,7 +90,7 @@ func (db userRepository) GetUser(ctx context.Context, id int) (*models.User, err
 	err := row.Scan(
 		&user.Id, &user.Name, &user.Surname, &user.Password, &user.Email, &user.Location,
 		&user.Role, &user.Verified, &user.ProfilePhoto, &user.Description, &user.CreatedAt,
-		&user.UpdatedAt, &user.Blocked,
+		&user.UpdatedAt, &user.Version, &user.Blocked,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -77,13 +102,14 @@ func (db userRepository) GetUser(ctx context.Context, id int) (*models.User, err
 	return &user, nil
 }
 
-func (db userRepository) GetAllUsers(ctx context.Context) ([]models.User, error) {
+func (db userRepository) GetAllUsers(ctx context.Context, filter models.UserFilter) ([]models.User, error) {
 	// Simplificado: No calcular BadLoginAttempts ni Blocked aquí por rendimiento.
 	// Estos campos tendrán su valor cero (0 y false).
+	where, args := userFilterClause(filter, 1)
 	rows, err := db.DB.QueryContext(ctx, `
 		SELECT id, name, surname, password, email, location, role, profile_photo,
 		       description, created_at, updated_at
-		FROM users`)
+		FROM users`+where, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -106,22 +132,59 @@ func (db userRepository) GetAllUsers(ctx context.Context) ([]models.User, error)
 	return users, nil
 }
 
-func (db userRepository) DeleteUser(ctx context.Context, id int) error {
-	_, err := db.DB.ExecContext(ctx, "DELETE FROM users WHERE id = $1", id)
+// StreamUsers calls fn with every user matching the filter, ordered by id, without loading them
+// all in memory. The password is left empty. Iteration stops at the first error fn returns.
+func (db userRepository) StreamUsers(ctx context.Context, filter models.UserFilter, fn func(models.User) error) error {
+	where, args := userFilterClause(filter, 1)
+	rows, err := db.DB.QueryContext(ctx, `
+		SELECT id, name, surname, email, location, role, verified, profile_photo,
+		       description, created_at, updated_at, `+activeBlockExists+` AS blocked
+		FROM users`+where+`
+		ORDER BY id`, args...)
 	if err != nil {
 		return err
 	}
+	defer rows.Close()
 
-	return nil
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.Id, &user.Name, &user.Surname, &user.Email, &user.Location, &user.Role,
+			&user.Verified, &user.ProfilePhoto, &user.Description, &user.CreatedAt,
+			&user.UpdatedAt, &user.Blocked,
+		)
+		if err != nil {
+			return err
+		}
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// DeleteUser deletes the user only if it is still at the given version
+func (db userRepository) DeleteUser(ctx context.Context, id int, version int) error {
+	result, err := db.DB.ExecContext(ctx, "DELETE FROM users WHERE id = $1 AND version = $2", id, version)
+	if err != nil {
+		return err
+	}
+
+	return checkVersionedWrite(result)
 }
 
 func (db userRepository) AddUser(ctx context.Context, user *models.User) (int, error) {
+	return insertUser(ctx, db.DB, user)
+}
+
+// insertUser writes the user with db, which is a transaction when other rows go along with it
+func insertUser(ctx context.Context, db queryRower, user *models.User) (int, error) {
 	query := `
 		INSERT INTO users (name, surname, password, email, location, role, verified, profile_photo, description)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id`
 	var id int
-	err := db.DB.QueryRowContext(ctx, query,
+	err := db.QueryRowContext(ctx, query,
 		&user.Name, &user.Surname, &user.Password, &user.Email, &user.Location, &user.Role,
 		&user.Verified, &user.ProfilePhoto, &user.Description,
 	).Scan(&id)
@@ -137,14 +200,14 @@ func (db userRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 	query := `
 		SELECT
 			u.id, u.name, u.surname, u.password, u.email, u.location, u.role, u.verified,
-			u.profile_photo, u.description, u.created_at, u.updated_at,
+			u.profile_photo, u.description, u.created_at, u.updated_at, u.version,
 			EXISTS(
 				SELECT 1 FROM blocked_users
 				WHERE blocked_user_id = u.id
 				AND (blocked_until IS NULL OR blocked_until > NOW())
 			) AS blocked
 		FROM users u
-		WHERE u.email ILIKE $1
+		WHERE u.email ILIKE $1 AND u.deleted_at IS NULL
 		LIMIT 1`
 
 	row := db.DB.QueryRowContext(ctx, query, email)
@@ -153,7 +216,7 @@ func (db userRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 	err := row.Scan(
 		&user.Id, &user.Name, &user.Surname, &user.Password, &user.Email, &user.Location,
 		&user.Role, &user.Verified, &user.ProfilePhoto, &user.Description, &user.CreatedAt,
-		&user.UpdatedAt, &user.Blocked,
+		&user.UpdatedAt, &user.Version, &user.Blocked,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -165,15 +228,20 @@ func (db userRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 	return &user, nil
 }
 
+// ModifyUser saves the user only if the stored row is still at user.Version
 func (db userRepository) ModifyUser(ctx context.Context, user *models.User) error {
 	query := `
 		UPDATE users SET name = $1, surname = $2, location = $3, profile_photo = $4, description = $5, verified = $6
-		WHERE id = $7`
-	_, err := db.DB.ExecContext(ctx, query,
+		WHERE id = $7 AND version = $8`
+	result, err := db.DB.ExecContext(ctx, query,
 		&user.Name, &user.Surname, &user.Location, &user.ProfilePhoto, &user.Description, &user.Verified, &user.Id,
+		&user.Version,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return checkVersionedWrite(result)
 }
 
 func (db userRepository) ModifyPassword(ctx context.Context, id int, password string) error {
@@ -181,6 +249,26 @@ func (db userRepository) ModifyPassword(ctx context.Context, id int, password st
 	return err
 }
 
+// RehashPassword only updates the hash column. app.password_rehash tells the triggers of the users table
+// not to add it to the password history nor bump the version of the user, it's the same password.
+func (db userRepository) RehashPassword(ctx context.Context, id int, currentHash string, newHash string) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "SET LOCAL app.password_rehash = 'on'"); err != nil {
+		tx.Rollback()
+		return err
+	}
+	// A password changed meanwhile is left as it is
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET password = $1 WHERE id = $2 AND password = $3", newHash, id, currentHash); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
 func (db userRepository) AddNotificationToken(ctx context.Context, id int, text string) error {
 	query := `
 		INSERT INTO notifications (user_id, token)
@@ -221,53 +309,345 @@ func (db userRepository) SetVerifiedTrue(ctx context.Context, id int) error {
 	return err
 }
 
-func (db userRepository) AddPasswordResetToken(ctx context.Context, id int, email string, token string, tokenExpiration time.Time) error {
-	_, err := db.DB.ExecContext(ctx, "INSERT INTO password_reset (user_id, email, token, token_expiration) VALUES ($1, $2, $3, $4)", id, email, token, tokenExpiration)
-	return err
+// AddPasswordResetToken stores the hash of a new reset token for the user, invalidating
+// the ones issued before, along with the jobs the outbox builds for it. It returns the id of the new token.
+func (db userRepository) AddPasswordResetToken(ctx context.Context, id int, email string, tokenHash string, tokenExpiration time.Time, outbox models.Outbox) (int, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE password_reset SET used = true WHERE user_id = $1 AND used = false", id); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	var resetId int
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO password_reset (user_id, email, token_hash, token_expiration) VALUES ($1, $2, $3, $4) RETURNING id",
+		id, email, tokenHash, tokenExpiration,
+	).Scan(&resetId)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := enqueueOutbox(ctx, tx, outbox, resetId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	return resetId, tx.Commit()
 }
 
-func (db userRepository) GetPasswordResetTokenInfo(ctx context.Context, token string) (*models.PasswordResetData, error) {
-	row := db.DB.QueryRowContext(ctx, "SELECT email, user_id, token_expiration, used FROM password_reset WHERE token = $1", token)
+// AddPasswordResetAttempt counts the attempt and reads the token in a single statement, so concurrent
+// attempts each see a different count. It returns ErrNotFound if there's no token with the id.
+func (db userRepository) AddPasswordResetAttempt(ctx context.Context, id int) (*models.PasswordResetData, error) {
+	row := db.DB.QueryRowContext(ctx, `
+		UPDATE password_reset SET attempts = attempts + 1 WHERE id = $1
+		RETURNING id, email, user_id, token_hash, token_expiration, used, attempts`, id)
 	var data models.PasswordResetData
-	err := row.Scan(&data.Email, &data.UserId, &data.Exp, &data.Used)
+	err := row.Scan(&data.Id, &data.Email, &data.UserId, &data.TokenHash, &data.Exp, &data.Used, &data.Attempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &data, nil
 }
 
-func (db userRepository) SetPasswordTokenUsed(ctx context.Context, token string) error {
-	_, err := db.DB.ExecContext(ctx, "UPDATE password_reset SET used = true where token = $1", token)
+func (db userRepository) ReleasePasswordResetAttempt(ctx context.Context, id int) error {
+	_, err := db.DB.ExecContext(ctx, "UPDATE password_reset SET attempts = attempts - 1 WHERE id = $1 AND attempts > 0", id)
 	return err
 }
 
-func (db userRepository) SetNotificationPreference(ctx context.Context, id int, preference models.NotificationPreferenceRequest) error {
-	query := fmt.Sprintf("UPDATE users SET %s = $2 WHERE id = $1", preference.NotificationType)
-	_, err := db.DB.ExecContext(ctx, query, id, preference.NotificationPreference)
+// ResetPassword marks the unused token as used and changes the password in one transaction, returning
+// ErrNotFound if the token was already used, so that only one request can consume it.
+func (db userRepository) ResetPassword(ctx context.Context, tokenId int, userId int, password string) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, "UPDATE password_reset SET used = true WHERE id = $1 AND user_id = $2 AND used = false", tokenId, userId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected == 0 {
+		tx.Rollback()
+		return ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET password = $1 WHERE id = $2", password, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (db userRepository) GetNotificationTypes(ctx context.Context) ([]models.NotificationType, error) {
+	rows, err := db.DB.QueryContext(ctx, "SELECT name, description, mandatory, created_at FROM notification_types ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := []models.NotificationType{}
+	for rows.Next() {
+		var notificationType models.NotificationType
+		err := rows.Scan(&notificationType.Name, &notificationType.Description, &notificationType.Mandatory, &notificationType.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, notificationType)
+	}
+	return types, rows.Err()
+}
+
+func (db userRepository) AddNotificationType(ctx context.Context, notificationType *models.NotificationType) error {
+	err := db.DB.QueryRowContext(ctx,
+		"INSERT INTO notification_types (name, description, mandatory) VALUES ($1, $2, $3) RETURNING created_at",
+		notificationType.Name, notificationType.Description, notificationType.Mandatory,
+	).Scan(&notificationType.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return ErrNotificationTypeExists
+	}
 	return err
 }
 
-func (db userRepository) CheckPreference(ctx context.Context, id int, notificationType string) (bool, error) {
-	query := fmt.Sprintf("SELECT %s FROM users WHERE id = $1", notificationType)
-	row := db.DB.QueryRowContext(ctx, query, id)
-	var preference bool
-	if err := row.Scan(&preference); err != nil {
-		return false, err
+// notificationMatrixQuery has a row for each registered type and channel. Without a preference the
+// notification is sent, and mandatory types are sent whatever the preference says.
+const notificationMatrixQuery = `
+	SELECT t.name, c.channel, t.mandatory OR COALESCE(p.enabled, true)
+	FROM notification_types t
+	CROSS JOIN unnest($2::text[]) AS c(channel)
+	LEFT JOIN notification_preferences p ON p.user_id = $1 AND p.type = t.name AND p.channel = c.channel`
+
+func (db userRepository) GetNotificationPreferences(ctx context.Context, id int) (models.NotificationPreferences, error) {
+	rows, err := db.DB.QueryContext(ctx, notificationMatrixQuery, id, pq.Array(models.NotificationChannels))
+	if err != nil {
+		return nil, err
 	}
-	return preference, nil
+	defer rows.Close()
+
+	preferences := models.NotificationPreferences{}
+	for rows.Next() {
+		var notificationType, channel string
+		var enabled bool
+		if err := rows.Scan(&notificationType, &channel, &enabled); err != nil {
+			return nil, err
+		}
+		preferences.Set(notificationType, channel, enabled)
+	}
+	return preferences, rows.Err()
 }
 
-func (db userRepository) GetNotificationPreference(ctx context.Context, id int) (*models.NotificationPreference, error) {
-	row := db.DB.QueryRowContext(ctx, "SELECT exam_notification, homework_notification, social_notification FROM users WHERE id = $1", id)
-	var preferences models.NotificationPreference
-	err := row.Scan(&preferences.ExamNotification, &preferences.HomeworkNotification, &preferences.SocialNotification)
+func (db userRepository) GetNotificationChannels(ctx context.Context, id int, notificationType string) (map[string]bool, error) {
+	rows, err := db.DB.QueryContext(ctx, notificationMatrixQuery+" WHERE t.name = $3", id, pq.Array(models.NotificationChannels), notificationType)
 	if err != nil {
 		return nil, err
 	}
-	return &preferences, nil
+	defer rows.Close()
+
+	channels := map[string]bool{}
+	for rows.Next() {
+		var name, channel string
+		var enabled bool
+		if err := rows.Scan(&name, &channel, &enabled); err != nil {
+			return nil, err
+		}
+		channels[channel] = enabled
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(channels) == 0 {
+		return nil, ErrNotFound
+	}
+	return channels, nil
+}
+
+func (db userRepository) SetNotificationPreferences(ctx context.Context, id int, preferences models.NotificationPreferences) error {
+	// Flattened into columns so the whole matrix is saved by a single statement
+	var types, channels []string
+	var enabled []bool
+	for _, notificationType := range slices.Sorted(maps.Keys(preferences)) {
+		for _, channel := range slices.Sorted(maps.Keys(preferences[notificationType])) {
+			types = append(types, notificationType)
+			channels = append(channels, channel)
+			enabled = append(enabled, preferences[notificationType][channel])
+		}
+	}
+	if len(types) == 0 {
+		return nil
+	}
+	_, err := db.DB.ExecContext(ctx, `
+		INSERT INTO notification_preferences (user_id, type, channel, enabled)
+		SELECT $1, p.type, p.channel, p.enabled
+		FROM unnest($2::text[], $3::text[], $4::bool[]) AS p(type, channel, enabled)
+		ON CONFLICT (user_id, type, channel) DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = NOW()`,
+		id, pq.Array(types), pq.Array(channels), pq.Array(enabled))
+	return err
 }
 
 //id, username, name, surname,  password,email, location, admin, blocked_user, profile_photo,description
 // Eliminar Implementación de métodos IncrementBadLoginAttempts y ResetBadLoginAttempts
 // func (db userRepository) IncrementBadLoginAttempts(...) { ... }
 // func (db userRepository) ResetBadLoginAttempts(...) { ... }
+
+// EmailExists reports whether any user already owns the email, ignoring case
+func (db userRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	var exists bool
+	err := db.DB.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE LOWER(email) = LOWER($1) AND deleted_at IS NULL)", email).Scan(&exists)
+	return exists, err
+}
+
+// AddEmailChangeRequest stores a pending email change, replacing any previous one of the same user,
+// along with the jobs the outbox builds for it
+func (db userRepository) AddEmailChangeRequest(ctx context.Context, request *models.EmailChangeRequest, outbox models.Outbox) (int, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM email_change_requests WHERE user_id = $1", request.UserId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	query := `
+		INSERT INTO email_change_requests (user_id, old_email, new_email, verification_pin, cancel_token_hash, pin_expiration)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+	var id int
+	err = tx.QueryRowContext(ctx, query,
+		request.UserId, request.OldEmail, request.NewEmail, request.VerificationPin, request.CancelTokenHash, request.PinExpiration,
+	).Scan(&id)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := enqueueOutbox(ctx, tx, outbox, id); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// AddEmailChangeAttempt counts the attempt and reads the request in a single statement, so concurrent
+// guesses can't get past the limit
+func (db userRepository) AddEmailChangeAttempt(ctx context.Context, id int) (*models.EmailChangeRequest, error) {
+	query := `
+		UPDATE email_change_requests SET attempts = attempts + 1 WHERE id = $1
+		RETURNING id, user_id, old_email, new_email, verification_pin, cancel_token_hash, pin_expiration, created_at, attempts`
+	var request models.EmailChangeRequest
+	err := db.DB.QueryRowContext(ctx, query, id).Scan(
+		&request.Id, &request.UserId, &request.OldEmail, &request.NewEmail, &request.VerificationPin,
+		&request.CancelTokenHash, &request.PinExpiration, &request.CreatedAt, &request.Attempts,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (db userRepository) ReleaseEmailChangeAttempt(ctx context.Context, id int) error {
+	_, err := db.DB.ExecContext(ctx, "UPDATE email_change_requests SET attempts = attempts - 1 WHERE id = $1 AND attempts > 0", id)
+	return err
+}
+
+func (db userRepository) DeleteEmailChangeRequestByCancelToken(ctx context.Context, tokenHash string) error {
+	result, err := db.DB.ExecContext(ctx, "DELETE FROM email_change_requests WHERE cancel_token_hash = $1", tokenHash)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ApplyEmailChange moves the user to the new email, revokes every session issued so far,
+// discards pending password resets and removes the change request, all in one transaction
+func (db userRepository) ApplyEmailChange(ctx context.Context, request *models.EmailChangeRequest) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE users SET email = $1, sessions_valid_after = NOW() WHERE id = $2",
+		request.NewEmail, request.UserId)
+	if err != nil {
+		tx.Rollback()
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return ErrEmailTaken
+		}
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE password_reset SET used = true WHERE user_id = $1 AND used = false", request.UserId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM email_change_requests WHERE user_id = $1", request.UserId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetSessionsValidAfter returns the instant before which the user's tokens are no longer accepted,
+// or nil if none of them were ever revoked
+func (db userRepository) GetSessionsValidAfter(ctx context.Context, id int) (*time.Time, error) {
+	var validAfter *time.Time
+	err := db.DB.QueryRowContext(ctx, "SELECT sessions_valid_after FROM users WHERE id = $1", id).Scan(&validAfter)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return validAfter, nil
+}
+
+// GetPasswordHistory returns the hashes of the user's last passwords, newest first.
+// The current password is always the first one.
+func (db userRepository) GetPasswordHistory(ctx context.Context, id int, limit int) ([]string, error) {
+	rows, err := db.DB.QueryContext(ctx, `
+		SELECT password FROM password_history
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2`, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, rows.Err()
+}
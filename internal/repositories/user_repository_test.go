@@ -3,7 +3,7 @@ package repositories
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -11,6 +11,7 @@ import (
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 )
 
 func TestCreateDatabase(t *testing.T) {
@@ -81,16 +82,17 @@ func TestDatabase_GetUser(t *testing.T) {
 	description := "Test description"
 	createdAt := time.Now()
 	updatedAt := time.Now()
+	version := 3
 	blocked := false
 
 	// Use the actual query pattern from the repository
-	mock.ExpectQuery(`SELECT\s+u\.id,\s*u\.name,\s*u\.surname,\s*u\.password,\s*u\.email,\s*u\.location,\s*u\.role,\s*u\.verified,\s*u\.profile_photo,\s*u\.description,\s*u\.created_at,\s*u\.updated_at,\s*EXISTS`).
+	mock.ExpectQuery(`SELECT\s+u\.id,\s*u\.name,\s*u\.surname,\s*u\.password,\s*u\.email,\s*u\.location,\s*u\.role,\s*u\.verified,\s*u\.profile_photo,\s*u\.description,\s*u\.created_at,\s*u\.updated_at,\s*u\.version,\s*EXISTS`).
 		WithArgs(id).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "name", "surname", "password", "email", "location", "role", "verified",
-			"profile_photo", "description", "created_at", "updated_at", "blocked"}).
+			"profile_photo", "description", "created_at", "updated_at", "version", "blocked"}).
 			AddRow(id, name, surname, password, email, location, role, verified,
-				&profilePicture, description, createdAt, updatedAt, blocked))
+				&profilePicture, description, createdAt, updatedAt, version, blocked))
 
 	ctx := context.Background()
 	database := CreateUserRepo(db)
@@ -108,6 +110,7 @@ func TestDatabase_GetUser(t *testing.T) {
 		Description:  description,
 		CreatedAt:    createdAt,
 		UpdatedAt:    updatedAt,
+		Version:      version,
 		Blocked:      blocked,
 	}
 
@@ -178,7 +181,7 @@ func TestDatabase_GetAllUsers(t *testing.T) {
 	var expectedUsers []models.User
 	expectedUsers = append(expectedUsers, expectedUser)
 
-	users, err := database.GetAllUsers(ctx)
+	users, err := database.GetAllUsers(ctx, models.UserFilter{})
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUsers, users)
 }
@@ -200,6 +203,7 @@ func TestDatabase_GetUserByEmail(t *testing.T) {
 	description := "Test description"
 	createdAt := time.Now()
 	updatedAt := time.Now()
+	version := 3
 	blocked := false
 
 	// Use the actual query pattern
@@ -207,9 +211,9 @@ func TestDatabase_GetUserByEmail(t *testing.T) {
 		WithArgs(email).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "name", "surname", "password", "email", "location", "role", "verified",
-			"profile_photo", "description", "created_at", "updated_at", "blocked"}).
+			"profile_photo", "description", "created_at", "updated_at", "version", "blocked"}).
 			AddRow(id, name, surname, password, email, location, role, verified,
-				&profilePicture, description, createdAt, updatedAt, blocked))
+				&profilePicture, description, createdAt, updatedAt, version, blocked))
 
 	ctx := context.Background()
 	database := CreateUserRepo(db)
@@ -227,6 +231,7 @@ func TestDatabase_GetUserByEmail(t *testing.T) {
 		Description:  description,
 		CreatedAt:    createdAt,
 		UpdatedAt:    updatedAt,
+		Version:      version,
 		Blocked:      blocked,
 	}
 
@@ -259,17 +264,33 @@ func TestDatabase_DeleteUser(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectExec(`DELETE FROM users WHERE id = \$1`).
-		WithArgs(1).
+	mock.ExpectExec(`DELETE FROM users WHERE id = \$1 AND version = \$2`).
+		WithArgs(1, 2).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	ctx := context.Background()
 	database := CreateUserRepo(db)
 
-	err = database.DeleteUser(ctx, 1)
+	err = database.DeleteUser(ctx, 1, 2)
 	assert.NoError(t, err)
 }
 
+func TestDatabase_DeleteUser_VersionMismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`DELETE FROM users WHERE id = \$1 AND version = \$2`).
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctx := context.Background()
+	database := CreateUserRepo(db)
+
+	err = database.DeleteUser(ctx, 1, 2)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+}
+
 func TestDatabase_ModifyUser(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -285,10 +306,11 @@ func TestDatabase_ModifyUser(t *testing.T) {
 	verified := false
 	profilePicture := "test_profile.jpg"
 	description := "Test description"
+	version := 5
 	blocked := false
 
-	mock.ExpectExec(`UPDATE users SET name = \$1, surname = \$2, location = \$3, profile_photo = \$4, description = \$5, verified = \$6 WHERE id = \$7`).
-		WithArgs(name, surname, location, &profilePicture, description, verified, id).
+	mock.ExpectExec(`UPDATE users SET name = \$1, surname = \$2, location = \$3, profile_photo = \$4, description = \$5, verified = \$6 WHERE id = \$7 AND version = \$8`).
+		WithArgs(name, surname, location, &profilePicture, description, verified, id, version).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	ctx := context.Background()
@@ -305,6 +327,7 @@ func TestDatabase_ModifyUser(t *testing.T) {
 		Verified:     verified,
 		ProfilePhoto: &profilePicture,
 		Description:  description,
+		Version:      version,
 		Blocked:      blocked,
 	}
 
@@ -312,6 +335,21 @@ func TestDatabase_ModifyUser(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestDatabase_ModifyUser_VersionMismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE users SET .* WHERE id = \$7 AND version = \$8`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctx := context.Background()
+	database := CreateUserRepo(db)
+
+	err = database.ModifyUser(ctx, &models.User{Id: 1, Name: "Test", Version: 1})
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+}
+
 func TestDatabase_ModifyPassword(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -327,6 +365,24 @@ func TestDatabase_ModifyPassword(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestDatabase_RehashPassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	// The triggers don't record the same password again nor bump the version
+	mock.ExpectBegin()
+	mock.ExpectExec(`SET LOCAL app.password_rehash = 'on'`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE users SET password = \$1 WHERE id = \$2 AND password = \$3`).
+		WithArgs("new hash", 1, "old hash").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = CreateUserRepo(db).RehashPassword(context.Background(), 1, "old hash", "new hash")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestDatabase_MakeTeacher(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -433,19 +489,42 @@ func TestUserRepository_AddPasswordResetToken(t *testing.T) {
 
 	userID := 1
 	email := "user@example.com"
-	token := "123456"
+	tokenHash := "hash"
 	expiration := time.Now().Add(1 * time.Hour)
 
-	mock.ExpectExec(`INSERT INTO password_reset \(user_id, email, token, token_expiration\) VALUES \(\$1, \$2, \$3, \$4\)`).
-		WithArgs(userID, email, token, expiration).
-		WillReturnResult(sqlmock.NewResult(1, 1)) // simulate 1 row inserted
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE password_reset SET used = true WHERE user_id = \$1 AND used = false`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO password_reset \(user_id, email, token_hash, token_expiration\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id`).
+		WithArgs(userID, email, tokenHash, expiration).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+	mock.ExpectCommit()
 
-	err = repo.AddPasswordResetToken(ctx, userID, email, token, expiration)
+	id, err := repo.AddPasswordResetToken(ctx, userID, email, tokenHash, expiration, nil)
 	assert.NoError(t, err)
+	assert.Equal(t, 9, id)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUserRepository_GetPasswordResetTokenInfo(t *testing.T) {
+func TestUserRepository_AddPasswordResetToken_InsertFails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateUserRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE password_reset SET used = true`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO password_reset`).WillReturnError(errors.New("db error"))
+	mock.ExpectRollback()
+
+	_, err = repo.AddPasswordResetToken(context.Background(), 1, "user@example.com", "hash", time.Now(), nil)
+	assert.EqualError(t, err, "db error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_AddPasswordResetAttempt(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
@@ -453,44 +532,59 @@ func TestUserRepository_GetPasswordResetTokenInfo(t *testing.T) {
 	repo := CreateUserRepo(db)
 	ctx := context.Background()
 
-	token := "reset-token"
 	expTime := time.Now()
 	mail := "user@example.com"
 	userId := 1
 
-	mock.ExpectQuery(`SELECT email, user_id, token_expiration, used FROM password_reset WHERE token = \$1`).
-		WithArgs(token).
-		WillReturnRows(sqlmock.NewRows([]string{"email", "user_id", "token_expiration", "used"}).
-			AddRow("user@example.com", userId, expTime, false))
+	mock.ExpectQuery(`UPDATE password_reset SET attempts = attempts \+ 1 WHERE id = \$1 RETURNING id, email, user_id, token_hash, token_expiration, used, attempts`).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "user_id", "token_hash", "token_expiration", "used", "attempts"}).
+			AddRow(4, mail, userId, "hash", expTime, false, 2))
 
-	result, err := repo.GetPasswordResetTokenInfo(ctx, token)
+	result, err := repo.AddPasswordResetAttempt(ctx, 4)
 	assert.NoError(t, err)
+	assert.Equal(t, 4, result.Id)
 	assert.Equal(t, mail, result.Email)
 	assert.Equal(t, userId, result.UserId)
+	assert.Equal(t, "hash", result.TokenHash)
 	assert.Equal(t, expTime, result.Exp)
 	assert.False(t, result.Used)
+	assert.Equal(t, 2, result.Attempts)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUserRepository_SetPasswordTokenUsed(t *testing.T) {
+func TestUserRepository_AddPasswordResetAttempt_NotFound(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := CreateUserRepo(db)
-	ctx := context.Background()
-	token := "123456"
 
-	mock.ExpectExec(`UPDATE password_reset SET used = true where token = \$1`).
-		WithArgs(token).
-		WillReturnResult(sqlmock.NewResult(0, 1)) // simulate success
+	mock.ExpectQuery(`UPDATE password_reset SET attempts = attempts \+ 1`).
+		WithArgs(4).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.AddPasswordResetAttempt(context.Background(), 4)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	err = repo.SetPasswordTokenUsed(ctx, token)
+func TestUserRepository_ReleasePasswordResetAttempt(t *testing.T) {
+	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateUserRepo(db)
+
+	mock.ExpectExec(`UPDATE password_reset SET attempts = attempts - 1 WHERE id = \$1 AND attempts > 0`).
+		WithArgs(4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.ReleasePasswordResetAttempt(context.Background(), 4))
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUserRepository_SetNotificationPreference(t *testing.T) {
+func TestUserRepository_ResetPassword(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
@@ -498,66 +592,472 @@ func TestUserRepository_SetNotificationPreference(t *testing.T) {
 	repo := CreateUserRepo(db)
 	ctx := context.Background()
 
-	preference := models.NotificationPreferenceRequest{
-		NotificationType:       "email_notifications",
-		NotificationPreference: true,
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE password_reset SET used = true WHERE id = \$1 AND user_id = \$2 AND used = false`).
+		WithArgs(4, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE users SET password = \$1 WHERE id = \$2`).
+		WithArgs("hash", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.ResetPassword(ctx, 4, 1, "hash"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_ResetPassword_TokenAlreadyUsed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateUserRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE password_reset SET used = true`).
+		WithArgs(4, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = repo.ResetPassword(context.Background(), 4, 1, "hash")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_ResetPassword_PasswordNotChanged(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateUserRepo(db)
+
+	// The token isn't used up if the password couldn't be changed
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE password_reset SET used = true`).
+		WithArgs(4, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE users SET password`).
+		WithArgs("hash", 1).
+		WillReturnError(errors.New("db error"))
+	mock.ExpectRollback()
+
+	err = repo.ResetPassword(context.Background(), 4, 1, "hash")
+	assert.EqualError(t, err, "db error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_GetNotificationTypes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateUserRepo(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT name, description, mandatory, created_at FROM notification_types ORDER BY name`).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "mandatory", "created_at"}).
+			AddRow("exam_notification", "Exams", false, now).
+			AddRow(models.RuleNotification, "Rules", true, now))
+
+	types, err := repo.GetNotificationTypes(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.NotificationType{
+		{Name: "exam_notification", Description: "Exams", CreatedAt: now},
+		{Name: models.RuleNotification, Description: "Rules", Mandatory: true, CreatedAt: now},
+	}, types)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_AddNotificationType(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateUserRepo(db)
+	now := time.Now()
+	notificationType := models.NotificationType{Name: "grades", Description: "Grades"}
+
+	mock.ExpectQuery(`INSERT INTO notification_types \(name, description, mandatory\) VALUES \(\$1, \$2, \$3\) RETURNING created_at`).
+		WithArgs("grades", "Grades", false).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+	mock.ExpectQuery(`INSERT INTO notification_types`).
+		WithArgs("grades", "Grades", false).
+		WillReturnError(&pq.Error{Code: uniqueViolation})
+
+	err = repo.AddNotificationType(context.Background(), &notificationType)
+	assert.NoError(t, err)
+	assert.Equal(t, now, notificationType.CreatedAt)
+
+	err = repo.AddNotificationType(context.Background(), &notificationType)
+	assert.ErrorIs(t, err, ErrNotificationTypeExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_GetNotificationPreferences(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateUserRepo(db)
+
+	mock.ExpectQuery(`SELECT t.name, c.channel, t.mandatory OR COALESCE\(p.enabled, true\) FROM notification_types t`).
+		WithArgs(1, pq.Array(models.NotificationChannels)).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "channel", "enabled"}).
+			AddRow("exam_notification", "inbox", true).
+			AddRow("exam_notification", "push", false).
+			AddRow("social_notification", "email", true))
+
+	preferences, err := repo.GetNotificationPreferences(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, models.NotificationPreferences{
+		"exam_notification":   {"inbox": true, "push": false},
+		"social_notification": {"email": true},
+	}, preferences)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_GetNotificationChannels(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateUserRepo(db)
+
+	mock.ExpectQuery(`FROM notification_types t .* WHERE t.name = \$3`).
+		WithArgs(1, pq.Array(models.NotificationChannels), "exam_notification").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "channel", "enabled"}).
+			AddRow("exam_notification", "inbox", true).
+			AddRow("exam_notification", "push", false).
+			AddRow("exam_notification", "email", true))
+	mock.ExpectQuery(`FROM notification_types t .* WHERE t.name = \$3`).
+		WithArgs(1, pq.Array(models.NotificationChannels), "unknown").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "channel", "enabled"}))
+
+	channels, err := repo.GetNotificationChannels(context.Background(), 1, "exam_notification")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"inbox": true, "push": false, "email": true}, channels)
+
+	_, err = repo.GetNotificationChannels(context.Background(), 1, "unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_SetNotificationPreferences(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateUserRepo(db)
+
+	mock.ExpectExec(`INSERT INTO notification_preferences \(user_id, type, channel, enabled\) SELECT .* ON CONFLICT \(user_id, type, channel\) DO UPDATE`).
+		WithArgs(1,
+			pq.Array([]string{"exam_notification", "exam_notification", "social_notification"}),
+			pq.Array([]string{"email", "push", "inbox"}),
+			pq.Array([]bool{true, false, false})).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err = repo.SetNotificationPreferences(context.Background(), 1, models.NotificationPreferences{
+		"social_notification": {"inbox": false},
+		"exam_notification":   {"push": false, "email": true},
+	})
+	assert.NoError(t, err)
+
+	// Nothing to save
+	err = repo.SetNotificationPreferences(context.Background(), 1, models.NotificationPreferences{})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_EmailExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateUserRepo(db)
+
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM users WHERE LOWER\(email\) = LOWER\(\$1\) AND deleted_at IS NULL\)`).
+		WithArgs("Test@Example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	exists, err := repo.EmailExists(context.Background(), "Test@Example.com")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_AddEmailChangeRequest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateUserRepo(db)
+	request := &models.EmailChangeRequest{
+		UserId:          1,
+		OldEmail:        "old@example.com",
+		NewEmail:        "new@example.com",
+		VerificationPin: "AB12CD",
+		CancelTokenHash: "hash",
+		PinExpiration:   time.Now(),
 	}
-	userID := 1
 
-	expectedQuery := fmt.Sprintf("UPDATE users SET %s = \\$2 WHERE id = \\$1", preference.NotificationType)
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM email_change_requests WHERE user_id = \$1`).
+		WithArgs(request.UserId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO email_change_requests`).
+		WithArgs(request.UserId, request.OldEmail, request.NewEmail, request.VerificationPin, request.CancelTokenHash, request.PinExpiration).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectExec(`INSERT INTO jobs`).
+		WithArgs(`{"email","email"}`, sqlmock.AnyArg(), `{t,t}`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	var outboxId int
+	id, err := repo.AddEmailChangeRequest(context.Background(), request, func(id int) ([]models.Job, error) {
+		outboxId = id
+		return []models.Job{{Type: models.JobEmail, Payload: []byte("{}"), Secret: true}, {Type: models.JobEmail, Payload: []byte("{}"), Secret: true}}, nil
+	})
+	assert.Equal(t, 3, outboxId)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_AddEmailChangeAttempt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
 
-	mock.ExpectExec(expectedQuery).
-		WithArgs(userID, preference.NotificationPreference).
+	repo := CreateUserRepo(db)
+	expTime := time.Now()
+	createdAt := expTime.Add(-time.Minute)
+
+	mock.ExpectQuery(`UPDATE email_change_requests SET attempts = attempts \+ 1 WHERE id = \$1\s+RETURNING id, user_id, old_email, new_email, verification_pin, cancel_token_hash, pin_expiration, created_at, attempts`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "old_email", "new_email", "verification_pin", "cancel_token_hash", "pin_expiration", "created_at", "attempts"}).
+			AddRow(3, 1, "old@example.com", "new@example.com", "AB12CD", "hash", expTime, createdAt, 2))
+
+	request, err := repo.AddEmailChangeAttempt(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.EmailChangeRequest{
+		Id:              3,
+		UserId:          1,
+		OldEmail:        "old@example.com",
+		NewEmail:        "new@example.com",
+		VerificationPin: "AB12CD",
+		CancelTokenHash: "hash",
+		PinExpiration:   expTime,
+		CreatedAt:       createdAt,
+		Attempts:        2,
+	}, request)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_AddEmailChangeAttempt_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateUserRepo(db)
+
+	mock.ExpectQuery(`UPDATE email_change_requests SET attempts = attempts \+ 1`).
+		WithArgs(3).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.AddEmailChangeAttempt(context.Background(), 3)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_ReleaseEmailChangeAttempt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateUserRepo(db)
+
+	mock.ExpectExec(`UPDATE email_change_requests SET attempts = attempts - 1 WHERE id = \$1 AND attempts > 0`).
+		WithArgs(3).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	err = repo.SetNotificationPreference(ctx, userID, preference)
+	assert.NoError(t, repo.ReleaseEmailChangeAttempt(context.Background(), 3))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_DeleteEmailChangeRequestByCancelToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateUserRepo(db)
+
+	mock.ExpectExec(`DELETE FROM email_change_requests WHERE cancel_token_hash = \$1`).
+		WithArgs("token").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM email_change_requests WHERE cancel_token_hash = \$1`).
+		WithArgs("unknown").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.DeleteEmailChangeRequestByCancelToken(context.Background(), "token"))
+	assert.ErrorIs(t, repo.DeleteEmailChangeRequestByCancelToken(context.Background(), "unknown"), ErrNotFound)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
-func TestUserRepository_CheckPreference(t *testing.T) {
+
+func TestUserRepository_ApplyEmailChange(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := CreateUserRepo(db)
-	ctx := context.Background()
-	userID := 1
-	notificationType := "exam_notifications"
+	request := &models.EmailChangeRequest{Id: 3, UserId: 1, NewEmail: "new@example.com"}
 
-	query := fmt.Sprintf("SELECT %s FROM users WHERE id = \\$1", notificationType)
-	mock.ExpectQuery(query).
-		WithArgs(userID).
-		WillReturnRows(sqlmock.NewRows([]string{notificationType}).AddRow(true))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE users SET email = \$1, sessions_valid_after = NOW\(\) WHERE id = \$2`).
+		WithArgs(request.NewEmail, request.UserId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE password_reset SET used = true WHERE user_id = \$1 AND used = false`).
+		WithArgs(request.UserId).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`DELETE FROM email_change_requests WHERE user_id = \$1`).
+		WithArgs(request.UserId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
-	result, err := repo.CheckPreference(ctx, userID, notificationType)
+	err = repo.ApplyEmailChange(context.Background(), request)
 	assert.NoError(t, err)
-	assert.True(t, result)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUserRepository_GetNotificationPreference(t *testing.T) {
+func TestUserRepository_ApplyEmailChange_EmailTaken(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := CreateUserRepo(db)
-	ctx := context.Background()
-	userID := 1
-	examNotification := true
-	homeworkNotification := false
-	socialNotification := true
+	request := &models.EmailChangeRequest{Id: 3, UserId: 1, NewEmail: "new@example.com"}
 
-	mock.ExpectQuery(`SELECT exam_notification, homework_notification, social_notification FROM users WHERE id = \$1`).
-		WithArgs(userID).
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE users SET email = \$1`).
+		WithArgs(request.NewEmail, request.UserId).
+		WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
+
+	err = repo.ApplyEmailChange(context.Background(), request)
+	assert.ErrorIs(t, err, ErrEmailTaken)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_GetSessionsValidAfter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateUserRepo(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT sessions_valid_after FROM users WHERE id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"sessions_valid_after"}).AddRow(now))
+	mock.ExpectQuery(`SELECT sessions_valid_after FROM users WHERE id = \$1`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"sessions_valid_after"}).AddRow(nil))
+
+	validAfter, err := repo.GetSessionsValidAfter(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, now, *validAfter)
+
+	validAfter, err = repo.GetSessionsValidAfter(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Nil(t, validAfter)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_GetPasswordHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateUserRepo(db)
+
+	mock.ExpectQuery(`SELECT password FROM password_history`).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"password"}).AddRow("hash2").AddRow("hash1"))
+
+	history, err := repo.GetPasswordHistory(context.Background(), 1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"hash2", "hash1"}, history)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDatabase_GetAllUsers_Filtered(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	verified := true
+	blocked := false
+	createdTo := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	filter := models.UserFilter{Role: "teacher", Verified: &verified, Blocked: &blocked, Search: "50%_off", CreatedTo: &createdTo}
+
+	mock.ExpectQuery(`FROM users WHERE deleted_at IS NULL AND role = \$1 AND verified = \$2 AND NOT EXISTS\(.+\) AND \(name ILIKE \$3 OR surname ILIKE \$3 OR email ILIKE \$3\) AND created_at < \$4`).
+		WithArgs("teacher", true, `%50\%\_off%`, createdTo.AddDate(0, 0, 1)).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"exam_notification", "homework_notification", "social_notification",
-		}).AddRow(examNotification, homeworkNotification, socialNotification))
+			"id", "name", "surname", "password", "email", "location", "role",
+			"profile_photo", "description", "created_at", "updated_at"}))
 
-	result, err := repo.GetNotificationPreference(ctx, userID)
+	users, err := CreateUserRepo(db).GetAllUsers(context.Background(), filter)
 	assert.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Equal(t, examNotification, result.ExamNotification)
-	assert.Equal(t, homeworkNotification, result.HomeworkNotification)
-	assert.Equal(t, socialNotification, result.SocialNotification)
+	assert.Empty(t, users)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDatabase_StreamUsers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT id, name, surname, email, location, role, verified, profile_photo, description, created_at, updated_at, EXISTS\(.+\) AS blocked FROM users WHERE deleted_at IS NULL AND role = \$1 ORDER BY id`).
+		WithArgs("student").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "name", "surname", "email", "location", "role", "verified",
+			"profile_photo", "description", "created_at", "updated_at", "blocked"}).
+			AddRow(1, "Ana", "Lopez", "ana@example.com", "", "student", true, nil, "", now, now, false).
+			AddRow(2, "Juan", "Perez", "juan@example.com", "", "student", false, nil, "", now, now, true))
+
+	var streamed []models.User
+	err = CreateUserRepo(db).StreamUsers(context.Background(), models.UserFilter{Role: "student"}, func(user models.User) error {
+		streamed = append(streamed, user)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, streamed, 2)
+	assert.Equal(t, "juan@example.com", streamed[1].Email)
+	assert.True(t, streamed[1].Blocked)
+	assert.Empty(t, streamed[1].Password)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestDatabase_StreamUsers_StopsOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM users WHERE deleted_at IS NULL ORDER BY id`).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "name", "surname", "email", "location", "role", "verified",
+			"profile_photo", "description", "created_at", "updated_at", "blocked"}).
+			AddRow(1, "Ana", "Lopez", "ana@example.com", "", "student", true, nil, "", now, now, false).
+			AddRow(2, "Juan", "Perez", "juan@example.com", "", "student", false, nil, "", now, now, true))
+
+	calls := 0
+	writeErr := errors.New("client went away")
+	err = CreateUserRepo(db).StreamUsers(context.Background(), models.UserFilter{}, func(models.User) error {
+		calls++
+		return writeErr
+	})
+
+	assert.ErrorIs(t, err, writeErr)
+	assert.Equal(t, 1, calls)
+}
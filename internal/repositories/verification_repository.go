@@ -13,11 +13,16 @@ import (
 const PinLifeTime = 5
 
 type VerificationRepository interface {
-	AddPendingVerification(ctx context.Context, verification *models.UserVerification) (int, error)
+	// AddPendingVerification stores the verification along with the jobs the outbox builds for it
+	AddPendingVerification(ctx context.Context, verification *models.UserVerification, outbox models.Outbox) (int, error)
+	// AddUserPendingVerification stores a new user along with its verification and the jobs the outbox builds for it,
+	// returning the id of the user
+	AddUserPendingVerification(ctx context.Context, user *models.User, verification *models.UserVerification, outbox models.Outbox) (int, error)
 	GetVerificationById(ctx context.Context, id int) (*models.UserVerification, error)
 	GetVerificationByEmail(ctx context.Context, email string) (*models.UserVerification, error)
 	DeleteByUserId(ctx context.Context, userId int) error
-	UpdatePin(ctx context.Context, userId int, pin string) error
+	// UpdatePin replaces the pin of the verification, restarting its expiration, along with the jobs the outbox builds for it
+	UpdatePin(ctx context.Context, id int, pin string, outbox models.Outbox) error
 }
 
 type verificationRepository struct {
@@ -28,21 +33,58 @@ func CreateVerificationRepo(db *sql.DB) *verificationRepository {
 	return &verificationRepository{DB: db}
 }
 
-func (db verificationRepository) AddPendingVerification(ctx context.Context, verification *models.UserVerification) (int, error) {
+func (db verificationRepository) AddPendingVerification(ctx context.Context, verification *models.UserVerification, outbox models.Outbox) (int, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	id, err := insertVerification(ctx, tx, verification)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := enqueueOutbox(ctx, tx, outbox, id); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+func (db verificationRepository) AddUserPendingVerification(ctx context.Context, user *models.User, verification *models.UserVerification, outbox models.Outbox) (int, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	userId, err := insertUser(ctx, tx, user)
+	if err != nil {
+		return 0, err
+	}
+
+	verification.UserId = userId
+	id, err := insertVerification(ctx, tx, verification)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := enqueueOutbox(ctx, tx, outbox, id); err != nil {
+		return 0, err
+	}
+	return userId, tx.Commit()
+}
+
+func insertVerification(ctx context.Context, db queryRower, verification *models.UserVerification) (int, error) {
 	query := `
 		INSERT INTO verification (user_id, user_email, verification_pin, pin_expiration)
 		VALUES ($1, $2, $3, $4)
 		RETURNING id`
 	var id int
-	err := db.DB.QueryRowContext(ctx, query,
+	err := db.QueryRowContext(ctx, query,
 		verification.UserId, verification.UserEmail, verification.VerificationPin, verification.PinExpiration,
 	).Scan(&id)
-
-	if err != nil {
-		return 0, err
-	}
-
-	return id, nil
+	return id, err
 }
 
 func (db verificationRepository) GetVerificationById(ctx context.Context, id int) (*models.UserVerification, error) {
@@ -86,9 +128,15 @@ func (db verificationRepository) DeleteByUserId(ctx context.Context, userId int)
 	return err
 }
 
-func (db verificationRepository) UpdatePin(ctx context.Context, Id int, pin string) error {
-	rows, err := db.DB.ExecContext(ctx, "UPDATE verification SET verification_pin = $2, pin_expiration = $3 WHERE id = $1",
-		Id, pin, time.Now().Add(PinLifeTime*time.Minute))
+func (db verificationRepository) UpdatePin(ctx context.Context, id int, pin string, outbox models.Outbox) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.ExecContext(ctx, "UPDATE verification SET verification_pin = $2, pin_expiration = $3 WHERE id = $1",
+		id, pin, time.Now().Add(PinLifeTime*time.Minute))
 	if err != nil {
 		return err
 	}
@@ -99,5 +147,9 @@ func (db verificationRepository) UpdatePin(ctx context.Context, Id int, pin stri
 	if affected < 1 {
 		return ErrNotFound
 	}
-	return err
+
+	if err := enqueueOutbox(ctx, tx, outbox, id); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
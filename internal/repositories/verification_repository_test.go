@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
 	"github.com/stretchr/testify/assert"
@@ -36,6 +37,7 @@ func TestVerificationRepository_AddPendingVerification(t *testing.T) {
 		PinExpiration:   time.Now().Add(10 * time.Minute),
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectQuery(`INSERT INTO verification \(user_id, user_email, verification_pin, pin_expiration\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id`).
 		WithArgs(
 			verification.UserId,
@@ -44,14 +46,78 @@ func TestVerificationRepository_AddPendingVerification(t *testing.T) {
 			verification.PinExpiration,
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
-
-	id, err := repo.AddPendingVerification(ctx, verification)
+	// The email goes out in the same transaction, built with the id of the verification
+	mock.ExpectExec(`INSERT INTO jobs \(type, payload, secret\) SELECT \* FROM unnest`).
+		WithArgs(`{"email"}`, `{"{\"code\":\"1-123456\"}"}`, `{t}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	id, err := repo.AddPendingVerification(ctx, verification, func(id int) ([]models.Job, error) {
+		return []models.Job{{Type: models.JobEmail, Payload: []byte(fmt.Sprintf(`{"code":"%d-%s"}`, id, verification.VerificationPin)), Secret: true}}, nil
+	})
 	assert.NoError(t, err)
 	assert.Equal(t, expectedID, id)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestVerificationRepository_AddUserPendingVerification(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateVerificationRepo(db)
+
+	user := &models.User{Name: "Ana", Surname: "Diaz", Email: "ana@example.com", Password: "hash", Role: "student"}
+	verification := &models.UserVerification{
+		UserEmail:       user.Email,
+		VerificationPin: "123456",
+		PinExpiration:   time.Now().Add(10 * time.Minute),
+	}
+
+	// The user, its verification and the email are written together
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO users`).
+		WithArgs(user.Name, user.Surname, user.Password, user.Email, user.Location, user.Role, false, user.ProfilePhoto, user.Description).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery(`INSERT INTO verification \(user_id, user_email, verification_pin, pin_expiration\)`).
+		WithArgs(7, user.Email, verification.VerificationPin, verification.PinExpiration).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectExec(`INSERT INTO jobs`).
+		WithArgs(`{"email"}`, `{"{\"code\":\"3-123456\"}"}`, `{t}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	id, err := repo.AddUserPendingVerification(context.Background(), user, verification, func(id int) ([]models.Job, error) {
+		return []models.Job{{Type: models.JobEmail, Payload: []byte(fmt.Sprintf(`{"code":"%d-%s"}`, id, verification.VerificationPin)), Secret: true}}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, id)
+	assert.Equal(t, 7, verification.UserId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVerificationRepository_AddUserPendingVerification_RollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := CreateVerificationRepo(db)
+
+	// Without the email the user isn't created either
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO users`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery(`INSERT INTO verification`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectExec(`INSERT INTO jobs`).WillReturnError(fmt.Errorf("db down"))
+	mock.ExpectRollback()
+
+	_, err = repo.AddUserPendingVerification(context.Background(), &models.User{}, &models.UserVerification{}, func(id int) ([]models.Job, error) {
+		return []models.Job{{Type: models.JobEmail, Payload: []byte(`{}`)}}, nil
+	})
+	assert.EqualError(t, err, "db down")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestVerificationRepository_DeleteByUserId(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -200,11 +266,13 @@ func TestVerificationRepository_UpdatePin(t *testing.T) {
 	id := 1
 	pin := "654321"
 
+	mock.ExpectBegin()
 	mock.ExpectExec(`UPDATE verification SET verification_pin = \$2, pin_expiration = \$3 WHERE id = \$1`).
 		WithArgs(id, pin, sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(0, 1)) // 1 row affected
+	mock.ExpectCommit()
 
-	err = repo.UpdatePin(ctx, id, pin)
+	err = repo.UpdatePin(ctx, id, pin, nil)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -219,11 +287,16 @@ func TestVerificationRepository_UpdatePin_NoRowAffected(t *testing.T) {
 	id := 1
 	pin := "654321"
 
+	mock.ExpectBegin()
 	mock.ExpectExec(`UPDATE verification SET verification_pin = \$2, pin_expiration = \$3 WHERE id = \$1`).
 		WithArgs(id, pin, sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(0, 0)) // 0 rows affected
+	mock.ExpectRollback()
 
-	err = repo.UpdatePin(ctx, id, pin)
+	err = repo.UpdatePin(ctx, id, pin, func(int) ([]models.Job, error) {
+		t.Fatal("no email for a missing verification")
+		return nil, nil
+	})
 	assert.ErrorIs(t, err, ErrNotFound)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -1,16 +1,22 @@
 package router
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"database/sql"
+	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
 	"os"
 
+	"github.com/Ingenieria-de-Software-2-Gupo-14/go-core/pkg/log"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/go-core/pkg/telemetry"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/config"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/controller"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/mailer"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
-	"github.com/sendgrid/sendgrid-go"
 )
 
 type Dependencies struct {
@@ -22,14 +28,25 @@ type Dependencies struct {
 }
 
 type Controllers struct {
-	AuthController *controller.AuthController
-	UserController *controller.UserController
-	ChatController *controller.ChatController
+	AuthController  *controller.AuthController
+	UserController  *controller.UserController
+	ChatController  *controller.ChatController
+	AdminController *controller.AdminController
+	AuditController *controller.AuditController
+	EmailController *controller.EmailController
+	JobController   *controller.JobController
+	InboxController *controller.InboxController
+	// Streams are served by this instance, but get the events caused in any of them
+	StreamController *controller.StreamController
+	// Proposals are only made in four-eyes mode, but can be listed and reviewed at any time
+	RuleProposalController *controller.RuleProposalController
 }
 
 type Services struct {
 	UserService  services.UserService
 	LoginService services.LoginAttemptService
+	AuditService services.AuditService
+	RulesService services.RulesService
 }
 
 type Repositories struct {
@@ -53,6 +70,9 @@ func NewDependencies(cfg *config.Config) (*Dependencies, error) {
 			return nil, err
 		}
 	}
+	if err := cfg.PasswordHashing.Validate(); err != nil {
+		return nil, err
+	}
 
 	// Repositories
 	userRepo := repositories.CreateUserRepo(db)
@@ -60,18 +80,65 @@ func NewDependencies(cfg *config.Config) (*Dependencies, error) {
 	blockRepo := repositories.NewBlockedUserRepository(db)
 	verificationRepo := repositories.CreateVerificationRepo(db)
 	rulesRepo := repositories.CreateRulesRepo(db)
+	acceptanceRepo := repositories.NewRuleAcceptanceRepository(db)
+	proposalRepo := repositories.NewRuleProposalRepository(db)
 	chatRepo := repositories.CreateChatsRepo(db)
+	importRepo := repositories.NewUserImportRepository(db)
+	bulkRepo := repositories.NewUserBulkRepository(db)
+	statsRepo := repositories.NewStatsRepository(db)
+	auditRepo := repositories.NewAuditRepository(db)
+	auditChainRepo := repositories.NewAuditChainRepository(db)
+	jobRepo := repositories.NewJobRepository(db)
+	inboxRepo := repositories.NewInboxRepository(db)
+	// Email
+	emailClient, err := mailer.New(cfg.Mail)
+	if err != nil {
+		return nil, err
+	}
+
 	// Services
-	userService := services.NewUserService(userRepo, blockRepo, sendgrid.NewSendClient(os.Getenv("EMAIL_API_KEY")))
+	userService := services.NewUserService(userRepo, blockRepo, jobRepo, inboxRepo, cfg.PublicURL, cfg.PasswordHashing)
 	loginService := services.NewLoginAttemptService(loginRepo, blockRepo)
-	verificationService := services.NewVerificationService(verificationRepo, sendgrid.NewSendClient(os.Getenv("EMAIL_API_KEY")))
-	rulesService := services.NewRulesService(rulesRepo)
+	verificationService := services.NewVerificationService(verificationRepo, cfg.PasswordHashing)
+	rulesService := services.NewRulesService(rulesRepo, acceptanceRepo)
+	proposalService := services.NewRuleProposalService(proposalRepo, rulesRepo)
 	chatService := services.NewChatsService(chatRepo)
+	passwordService := services.NewPasswordService(userRepo, cfg.PasswordPolicy, cfg.PasswordHashing)
+	importService := services.NewUserImportService(importRepo, userService, passwordService)
+	bulkService := services.NewUserBulkService(bulkRepo)
+	statsService := services.NewStatsService(statsRepo, services.StatsCacheTTL)
+	auditService := services.NewAuditService(auditRepo)
+	signingKey, err := loadAuditSigningKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	auditChainService := services.NewAuditChainService(auditChainRepo, signingKey)
+	// Emails and push notifications are queued in the jobs outbox and delivered by the worker
+	jobService := services.NewJobService(jobRepo, map[string]services.JobHandler{
+		models.JobEmail:     services.EmailJobHandler(emailClient),
+		models.JobPush:      services.PushJobHandler,
+		models.JobBroadcast: services.BroadcastJobHandler(userService),
+	})
+	streamService := services.NewStreamService(repositories.NewUserEventListener(cfg.DatabaseURL), inboxRepo)
+	if os.Getenv("TESTING") != "true" {
+		go streamService.Run(context.Background())
+		go jobService.RunWorker(context.Background(), cfg.JobsWorkerInterval)
+		go auditChainService.RunCheckpoints(context.Background(), cfg.AuditCheckpointInterval)
+		go rulesService.RunScheduler(context.Background(), cfg.RulesSchedulerInterval)
+		go importService.RunRecovery(context.Background())
+	}
 
 	// Controllers
-	authController := controller.NewAuthController(userService, loginService, verificationService)
-	userController := controller.CreateController(userService, rulesService)
+	authController := controller.NewAuthController(userService, loginService, verificationService, passwordService)
+	userController := controller.CreateController(userService, rulesService, passwordService)
 	chatController := controller.NewChatsController(chatService)
+	adminController := controller.NewAdminController(userService, importService, bulkService, statsService)
+	auditController := controller.NewAuditController(auditService, auditChainService)
+	proposalController := controller.NewRuleProposalController(proposalService)
+	emailController := controller.NewEmailController()
+	jobController := controller.NewJobController(jobService)
+	inboxController := controller.NewInboxController(services.NewInboxService(inboxRepo))
+	streamController := controller.NewStreamController(streamService, cfg.StreamHeartbeat, allowedOrigin)
 
 	// Clients
 	telemetryClient, err := cfg.CreateDatadogClient()
@@ -82,13 +149,22 @@ func NewDependencies(cfg *config.Config) (*Dependencies, error) {
 	return &Dependencies{
 		DB: db,
 		Controllers: Controllers{
-			AuthController: authController,
-			UserController: userController,
-			ChatController: chatController,
+			AuthController:         authController,
+			UserController:         userController,
+			ChatController:         chatController,
+			AdminController:        adminController,
+			AuditController:        auditController,
+			EmailController:        emailController,
+			JobController:          jobController,
+			InboxController:        inboxController,
+			StreamController:       streamController,
+			RuleProposalController: proposalController,
 		},
 		Services: Services{
 			UserService:  userService,
 			LoginService: loginService,
+			AuditService: auditService,
+			RulesService: rulesService,
 		},
 		Repositories: Repositories{
 			UserRepository:  userRepo,
@@ -101,3 +177,20 @@ func NewDependencies(cfg *config.Config) (*Dependencies, error) {
 	}, nil
 
 }
+
+// loadAuditSigningKey parses the configured key. In development it creates one that only lasts until the
+// next restart, anywhere else checkpoints signed with a key nobody keeps wouldn't prove anything.
+func loadAuditSigningKey(cfg *config.Config) (ed25519.PrivateKey, error) {
+	if cfg.AuditSigningKey != "" {
+		return services.ParseAuditSigningKey(cfg.AuditSigningKey)
+	}
+	if !cfg.IsDevelopment() {
+		return nil, errors.New("AUDIT_SIGNING_KEY is required outside development")
+	}
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	log.Warn(context.Background(), "AUDIT_SIGNING_KEY is not set, audit checkpoints are signed with a temporary key")
+	return key, nil
+}
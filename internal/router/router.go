@@ -34,22 +34,28 @@ func Health(deps *Dependencies) gin.HandlerFunc {
 	}
 }
 
+// allowedOrigin tells whether the web app on the origin can call the API, by CORS or WebSocket
+func allowedOrigin(origin string) bool {
+	return strings.HasSuffix(origin, ".vercel.app") || origin == "http://localhost:8081"
+}
+
 // CreateRouter creates and return a Router with its corresponding end points
 func CreateRouter(config config.Config) (*gin.Engine, error) {
 	r := gin.Default()
 
 	r.Use(cors.New(cors.Config{
-		AllowOriginFunc: func(origin string) bool {
-			return strings.HasSuffix(origin, ".vercel.app") || origin == "http://localhost:8081"
-		},
-		AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowOriginFunc: allowedOrigin,
+		AllowMethods:    []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders: []string{"Origin",
 			"Content-Type",
 			"Accept",
 			"Authorization",
 			"X-Requested-With",
-			"X-CSRF-Token"},
-		ExposeHeaders:    []string{"Content-Length"},
+			"X-CSRF-Token",
+			"If-Match",
+			"If-None-Match",
+			middleware.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", "ETag", middleware.RequestIDHeader},
 		AllowCredentials: true,
 	}))
 
@@ -59,6 +65,13 @@ func CreateRouter(config config.Config) (*gin.Engine, error) {
 	}
 
 	r.Use(telemetry.MetricsMiddleware(deps.Clients.TelemetryClient))
+	r.Use(middleware.RequestID())
+	r.Use(middleware.Locale())
+
+	// Privileged routes go through audit, which records the changes made by admins
+	audit := middleware.Audit(deps.Services.AuditService)
+	// Routes for using the platform go through acceptedRules, which asks users to accept the mandatory rules first
+	acceptedRules := middleware.RequireAcceptedRules(deps.Services.RulesService)
 
 	r.GET("/health", Health(deps))
 
@@ -73,39 +86,96 @@ func CreateRouter(config config.Config) (*gin.Engine, error) {
 	auth.POST("/users", deps.Controllers.AuthController.Register)
 	auth.POST("/users/verify", deps.Controllers.AuthController.VerifyRegistration)
 	auth.PUT("/users/verify/resend", deps.Controllers.AuthController.ResendPin)
-	auth.POST("/admins", middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.AuthController.RegisterAdmin)
+	auth.POST("/admins", audit, middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.AuthController.RegisterAdmin)
 	auth.POST("/login", deps.Controllers.AuthController.Login)
 	auth.GET("/logout", deps.Controllers.AuthController.Logout)
 	auth.GET("/verify", middleware.AuthMiddleware(deps.Services.UserService), deps.Controllers.AuthController.VerifyToken)
 
 	// User routes
-	r.GET("/users", middleware.AuthMiddleware(deps.Services.UserService), deps.Controllers.UserController.UsersGet)
-	r.PUT("/users/:id", middleware.UserOrAdminMiddleware(deps.Services.UserService), deps.Controllers.UserController.ModifyUser)
-	r.GET("/users/:id", middleware.AuthMiddleware(deps.Services.UserService), deps.Controllers.UserController.UserGetById)
+	r.GET("/users", middleware.AuthMiddleware(deps.Services.UserService), acceptedRules, deps.Controllers.UserController.UsersGet)
+	r.GET("/users/:id", middleware.AuthMiddleware(deps.Services.UserService), acceptedRules, deps.Controllers.UserController.UserGetById)
 	r.GET("/users/:id/notifications", deps.Controllers.UserController.GetUserNotifications)
 	r.POST("/users/:id/notifications", deps.Controllers.UserController.SetUserNotifications)
-	r.DELETE("/users/:id", middleware.UserOrAdminMiddleware(deps.Services.UserService), deps.Controllers.UserController.UserDeleteById)
-	r.PUT("/users/:id/block", middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.UserController.BlockUserById)
-	r.PUT("/users/:id/teacher", middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.UserController.MakeTeacher)
+	r.DELETE("/users/:id", audit, middleware.UserOrAdminMiddleware(deps.Services.UserService), middleware.RequireIfMatch(), deps.Controllers.UserController.UserDeleteById)
+	r.PUT("/users/:id/block", audit, middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.UserController.BlockUserById)
+	r.PUT("/users/:id/teacher", audit, middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.UserController.MakeTeacher)
 	r.PUT("/users/password", deps.Controllers.UserController.ModifyUserPasssword)
-	r.POST("/users/notify", deps.Controllers.UserController.NotifyUsers)
+	r.PUT("/users/:id/password", audit, middleware.UserOrAdminMiddleware(deps.Services.UserService), deps.Controllers.UserController.ChangePassword)
+	r.POST("/users/notify", audit, deps.Controllers.UserController.NotifyUsers)
 	r.PUT("/users/:id/notifications/preference", deps.Controllers.UserController.ModifyNotifPreference)
 	r.GET("/users/:id/notifications/preference", deps.Controllers.UserController.GetNotifPreferences)
+	r.GET("/users/:id/notifications/preferences", middleware.UserOrAdminMiddleware(deps.Services.UserService), deps.Controllers.UserController.GetNotificationSettings)
+	r.PUT("/users/:id/notifications/preferences", middleware.UserOrAdminMiddleware(deps.Services.UserService), deps.Controllers.UserController.UpdateNotificationPreferences)
+	r.GET("/notifications/types", middleware.AuthMiddleware(deps.Services.UserService), deps.Controllers.UserController.GetNotificationTypes)
 	r.POST("/users/reset/password", deps.Controllers.UserController.PasswordReset)
 	r.GET("/users/reset/password", deps.Controllers.UserController.PasswordResetRedirect)
+	r.POST("/users/:id/email", audit, middleware.UserOrAdminMiddleware(deps.Services.UserService), deps.Controllers.UserController.RequestEmailChange)
+	r.POST("/users/:id/email/confirm", audit, middleware.UserOrAdminMiddleware(deps.Services.UserService), deps.Controllers.UserController.ConfirmEmailChange)
+	r.GET("/users/email/cancel", deps.Controllers.UserController.CancelEmailChange)
+
+	// A user's own routes for using the platform, once authenticated they need the mandatory rules accepted.
+	// Deleting the account, the password, the email and the notification settings stay reachable without them.
+	userPlatform := r.Group("/users/:id", audit, middleware.UserOrAdminMiddleware(deps.Services.UserService), acceptedRules)
+	userPlatform.PUT("", middleware.RequireIfMatch(), deps.Controllers.UserController.ModifyUser)
+	userPlatform.PATCH("", middleware.RequireIfMatch(), deps.Controllers.UserController.PatchUser)
+	userPlatform.GET("/applicable-rules", deps.Controllers.UserController.GetApplicableRules)
+	userPlatform.GET("/inbox", deps.Controllers.InboxController.GetInbox)
+	userPlatform.PUT("/inbox/read", deps.Controllers.InboxController.MarkInboxRead)
+	userPlatform.GET("/inbox/:entry_id", deps.Controllers.InboxController.GetInboxEntry)
+	userPlatform.PUT("/inbox/:entry_id/read", deps.Controllers.InboxController.MarkInboxEntryRead)
+	userPlatform.DELETE("/inbox/:entry_id", deps.Controllers.InboxController.DeleteInboxEntry)
+	userPlatform.GET("/stream", deps.Controllers.StreamController.Stream)
 
 	// Rules routes
-	r.POST("/rules", middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.UserController.AddRule)
-	r.DELETE("/rules/:id", middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.UserController.DeleteRule)
+	addRule, modifyRule, deleteRule := deps.Controllers.UserController.AddRule, deps.Controllers.UserController.ModifyRule, deps.Controllers.UserController.DeleteRule
+	rollbackRule, publishRule, retireRule := deps.Controllers.UserController.RollbackRule, deps.Controllers.UserController.PublishRule, deps.Controllers.UserController.RetireRule
+	if config.RulesFourEyes {
+		// Changes are only proposed, a second admin approves them in /rules/proposals
+		proposals := deps.Controllers.RuleProposalController
+		addRule, modifyRule, deleteRule = proposals.ProposeRule, proposals.ProposeModification, proposals.ProposeDeletion
+		rollbackRule, publishRule, retireRule = proposals.ProposeRollback, proposals.ProposePublish, proposals.ProposeRetire
+	}
+	r.POST("/rules", audit, middleware.AdminOnlyMiddleware(deps.Services.UserService), addRule)
+	r.DELETE("/rules/:id", audit, middleware.AdminOnlyMiddleware(deps.Services.UserService), middleware.RequireIfMatch(), deleteRule)
 	r.GET("/rules", middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.UserController.GetRules)
-	r.PUT("/rules/:id", middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.UserController.ModifyRule)
+	r.PUT("/rules/:id", audit, middleware.AdminOnlyMiddleware(deps.Services.UserService), middleware.RequireIfMatch(), modifyRule)
+	r.GET("/rules/proposals", middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.RuleProposalController.GetProposals)
+	r.GET("/rules/proposals/:id", middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.RuleProposalController.GetProposal)
+	r.POST("/rules/proposals/:id/comments", middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.RuleProposalController.CommentProposal)
+	r.POST("/rules/proposals/:id/approve", audit, middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.RuleProposalController.ApproveProposal)
+	r.POST("/rules/proposals/:id/reject", audit, middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.RuleProposalController.RejectProposal)
+	r.GET("/rules/active", deps.Controllers.UserController.GetActiveRules)
+	r.POST("/rules/evaluate", middleware.AuthMiddleware(deps.Services.UserService), deps.Controllers.UserController.EvaluateRuleCondition)
+	r.GET("/rules/pending", middleware.AuthMiddleware(deps.Services.UserService), deps.Controllers.UserController.GetPendingRules)
+	r.POST("/rules/:id/accept", middleware.AuthMiddleware(deps.Services.UserService), middleware.RequireIfMatch(), deps.Controllers.UserController.AcceptRule)
 	r.GET("/rules/audit", middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.UserController.GetAudits)
+	r.GET("/rules/:id/versions", middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.UserController.GetRuleVersions)
+	r.GET("/rules/:id/versions/:v/diff", middleware.AdminOnlyMiddleware(deps.Services.UserService), deps.Controllers.UserController.GetRuleVersionDiff)
+	r.POST("/rules/:id/rollback/:v", audit, middleware.AdminOnlyMiddleware(deps.Services.UserService), middleware.RequireIfMatch(), rollbackRule)
+	r.POST("/rules/:id/publish", audit, middleware.AdminOnlyMiddleware(deps.Services.UserService), middleware.RequireIfMatch(), publishRule)
+	r.POST("/rules/:id/retire", audit, middleware.AdminOnlyMiddleware(deps.Services.UserService), middleware.RequireIfMatch(), retireRule)
+
+	// Admin routes
+	admin := r.Group("/admin", audit, middleware.AdminOnlyMiddleware(deps.Services.UserService))
+	admin.POST("/users/import", deps.Controllers.AdminController.ImportUsers)
+	admin.GET("/users/import/:id", deps.Controllers.AdminController.GetImportJob)
+	admin.GET("/users/export", deps.Controllers.AdminController.ExportUsers)
+	admin.POST("/users/bulk", deps.Controllers.AdminController.BulkUsers)
+	admin.GET("/stats", deps.Controllers.AdminController.GetStats)
+	admin.GET("/audit", deps.Controllers.AuditController.GetAuditEvents)
+	admin.GET("/audit/checkpoints", deps.Controllers.AuditController.GetAuditCheckpoints)
+	admin.GET("/rules/acceptance", deps.Controllers.UserController.GetRuleAcceptanceReport)
+	admin.GET("/emails", deps.Controllers.EmailController.GetEmailTemplates)
+	admin.GET("/emails/:name/preview", deps.Controllers.EmailController.PreviewEmail)
+	admin.GET("/jobs/dead", deps.Controllers.JobController.GetDeadJobs)
+	admin.POST("/jobs/dead/:id/requeue", deps.Controllers.JobController.RequeueDeadJob)
+	admin.POST("/notifications/types", deps.Controllers.UserController.AddNotificationType)
 
 	//Ai Chat routes
-	r.POST("/chat", middleware.AuthMiddleware(deps.Services.UserService), deps.Controllers.ChatController.SendMessage)
-	r.GET("/chat", middleware.AuthMiddleware(deps.Services.UserService), deps.Controllers.ChatController.GetMessages)
-	r.PUT("/chat/:message_id/rate", middleware.AuthMiddleware(deps.Services.UserService), deps.Controllers.ChatController.RateMessage)
-	r.PUT("/chat/:message_id/feedback", middleware.AuthMiddleware(deps.Services.UserService), deps.Controllers.ChatController.FeedbackMessage)
+	r.POST("/chat", middleware.AuthMiddleware(deps.Services.UserService), acceptedRules, deps.Controllers.ChatController.SendMessage)
+	r.GET("/chat", middleware.AuthMiddleware(deps.Services.UserService), acceptedRules, deps.Controllers.ChatController.GetMessages)
+	r.PUT("/chat/:message_id/rate", middleware.AuthMiddleware(deps.Services.UserService), acceptedRules, deps.Controllers.ChatController.RateMessage)
+	r.PUT("/chat/:message_id/feedback", middleware.AuthMiddleware(deps.Services.UserService), acceptedRules, deps.Controllers.ChatController.FeedbackMessage)
 	return r, nil
 }
 
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/config"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -24,8 +25,45 @@ func TestCreateRouter(t *testing.T) {
 	os.Setenv("TESTING", "true")
 	gin.SetMode(gin.TestMode)
 
-	router, err := CreateRouter(config.Config{})
+	router, err := CreateRouter(config.Config{Environment: "development", PasswordHashing: utils.DefaultHashParams()})
 	assert.NoError(t, err)
 	assert.NotNil(t, router)
 	os.Setenv("TESTING", "")
+
+	// The grouped routes of a user keep their paths
+	handlers := map[string]string{}
+	for _, route := range router.Routes() {
+		handlers[route.Method+" "+route.Path] = route.Handler
+	}
+	assert.Contains(t, handlers["PUT /users/:id"], "ModifyUser")
+	assert.Contains(t, handlers["PATCH /users/:id"], "PatchUser")
+	assert.Contains(t, handlers["GET /users/:id/inbox"], "GetInbox")
+	assert.Contains(t, handlers["GET /users/:id/stream"], "Stream")
+}
+
+func TestCreateRouter_RulesFourEyes(t *testing.T) {
+	os.Setenv("TESTING", "true")
+	defer os.Setenv("TESTING", "")
+	gin.SetMode(gin.TestMode)
+
+	router, err := CreateRouter(config.Config{Environment: "development", PasswordHashing: utils.DefaultHashParams(), RulesFourEyes: true})
+	assert.NoError(t, err)
+
+	handlers := map[string]string{}
+	for _, route := range router.Routes() {
+		handlers[route.Method+" "+route.Path] = route.Handler
+	}
+	assert.Contains(t, handlers["POST /rules"], "ProposeRule")
+	assert.Contains(t, handlers["PUT /rules/:id"], "ProposeModification")
+	assert.Contains(t, handlers["DELETE /rules/:id"], "ProposeDeletion")
+	assert.Contains(t, handlers["POST /rules/proposals/:id/approve"], "ApproveProposal")
+}
+
+func TestLoadAuditSigningKey_RequiredOutsideDevelopment(t *testing.T) {
+	_, err := loadAuditSigningKey(&config.Config{Environment: "production"})
+	assert.Error(t, err)
+
+	key, err := loadAuditSigningKey(&config.Config{Environment: "development", PasswordHashing: utils.DefaultHashParams()})
+	assert.NoError(t, err)
+	assert.NotNil(t, key)
 }
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/go-core/pkg/log"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	repo "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+)
+
+var ErrNoSigningKey = errors.New("no key to sign audit checkpoints with")
+
+type AuditChainService interface {
+	// Verify walks the whole rules audit chain and its checkpoints and reports the first broken link.
+	// If trustedKey is given, checkpoints signed with any other key are rejected.
+	Verify(ctx context.Context, trustedKey ed25519.PublicKey) (*models.AuditChainReport, error)
+	// CreateCheckpoint signs the current head of the chain. It returns nil if nothing was added since the last one.
+	CreateCheckpoint(ctx context.Context) (*models.AuditCheckpoint, error)
+	GetCheckpoints(ctx context.Context, limit int) ([]models.AuditCheckpoint, error)
+	// PublicKey returns the key checkpoints are verified with, base64 encoded
+	PublicKey() string
+	// RunCheckpoints creates a checkpoint every interval until the context is done
+	RunCheckpoints(ctx context.Context, interval time.Duration)
+}
+
+type auditChainService struct {
+	chainRepo  repo.AuditChainRepository
+	signingKey ed25519.PrivateKey
+	now        func() time.Time
+}
+
+// NewAuditChainService creates the service. Without a signing key it can still verify, but not create checkpoints.
+func NewAuditChainService(chainRepo repo.AuditChainRepository, signingKey ed25519.PrivateKey) *auditChainService {
+	return &auditChainService{chainRepo: chainRepo, signingKey: signingKey, now: time.Now}
+}
+
+// ParseAuditSigningKey decodes a base64 Ed25519 private key or 32 byte seed
+func ParseAuditSigningKey(encoded string) (ed25519.PrivateKey, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit signing key: %w", err)
+	}
+	switch len(key) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(key), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(key), nil
+	}
+	return nil, fmt.Errorf("invalid audit signing key: expected %d or %d bytes, got %d", ed25519.SeedSize, ed25519.PrivateKeySize, len(key))
+}
+
+// ParseAuditPublicKey decodes a base64 Ed25519 public key
+func ParseAuditPublicKey(encoded string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit public key: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid audit public key: expected %d bytes, got %d", ed25519.PublicKeySize, len(key))
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+func (s *auditChainService) Verify(ctx context.Context, trustedKey ed25519.PublicKey) (*models.AuditChainReport, error) {
+	var checkpoints []models.AuditCheckpoint
+	err := s.chainRepo.StreamCheckpoints(ctx, func(checkpoint models.AuditCheckpoint) error {
+		checkpoints = append(checkpoints, checkpoint)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(checkpoints, func(i, j int) bool { return checkpoints[i].LastAuditId < checkpoints[j].LastAuditId })
+
+	trusted := ""
+	if trustedKey != nil {
+		trusted = base64.StdEncoding.EncodeToString(trustedKey)
+	}
+
+	chain := models.NewAuditChain()
+	next := 0
+	err = s.chainRepo.StreamChain(ctx, func(entry models.Audit) error {
+		// A checkpoint pointing before this entry covers one that is gone
+		if next < len(checkpoints) && checkpoints[next].LastAuditId < entry.Id {
+			return missingCheckpointEntry(checkpoints[next])
+		}
+		if chainBreak := chain.Append(entry); chainBreak != nil {
+			return chainBreak
+		}
+		for ; next < len(checkpoints) && checkpoints[next].LastAuditId == entry.Id; next++ {
+			if chainBreak := checkCheckpoint(checkpoints[next], chain.Head(), trusted); chainBreak != nil {
+				return chainBreak
+			}
+		}
+		return nil
+	})
+	if next < len(checkpoints) && err == nil {
+		err = missingCheckpointEntry(checkpoints[next])
+	}
+
+	head := chain.Head()
+	report := &models.AuditChainReport{Entries: head.Entries, Head: head.Hash, Checkpoints: next}
+	var chainBreak *models.AuditChainBreak
+	switch {
+	case errors.As(err, &chainBreak):
+		report.Break = chainBreak
+	case err != nil:
+		return nil, err
+	}
+	return report, nil
+}
+
+func checkCheckpoint(checkpoint models.AuditCheckpoint, head models.AuditChainHead, trustedKey string) *models.AuditChainBreak {
+	reason := ""
+	switch {
+	case !checkpoint.VerifySignature():
+		reason = fmt.Sprintf("checkpoint %d has an invalid signature", checkpoint.Id)
+	case trustedKey != "" && checkpoint.PublicKey != trustedKey:
+		reason = fmt.Sprintf("checkpoint %d was signed with an untrusted key", checkpoint.Id)
+	case checkpoint.HeadHash != head.Hash:
+		reason = fmt.Sprintf("hash doesn't match checkpoint %d", checkpoint.Id)
+	case checkpoint.Entries != head.Entries:
+		reason = fmt.Sprintf("checkpoint %d counted %d entries up to here, found %d", checkpoint.Id, checkpoint.Entries, head.Entries)
+	default:
+		return nil
+	}
+	return &models.AuditChainBreak{Id: head.LastAuditId, Reason: reason}
+}
+
+func missingCheckpointEntry(checkpoint models.AuditCheckpoint) *models.AuditChainBreak {
+	return &models.AuditChainBreak{
+		Id:     checkpoint.LastAuditId,
+		Reason: fmt.Sprintf("entry is missing but checkpoint %d covers it", checkpoint.Id),
+	}
+}
+
+func (s *auditChainService) CreateCheckpoint(ctx context.Context) (*models.AuditCheckpoint, error) {
+	if s.signingKey == nil {
+		return nil, ErrNoSigningKey
+	}
+
+	head, err := s.chainRepo.GetChainHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if head.Entries == 0 {
+		return nil, nil
+	}
+	latest, err := s.chainRepo.GetCheckpoints(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(latest) > 0 && latest[0].LastAuditId == head.LastAuditId {
+		return nil, nil
+	}
+
+	checkpoint := &models.AuditCheckpoint{
+		LastAuditId: head.LastAuditId,
+		HeadHash:    head.Hash,
+		Entries:     head.Entries,
+		CreatedAt:   s.now().UTC().Truncate(time.Second),
+	}
+	checkpoint.Sign(s.signingKey)
+	if err := s.chainRepo.AddCheckpoint(ctx, checkpoint); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+func (s *auditChainService) GetCheckpoints(ctx context.Context, limit int) ([]models.AuditCheckpoint, error) {
+	return s.chainRepo.GetCheckpoints(ctx, limit)
+}
+
+func (s *auditChainService) PublicKey() string {
+	if s.signingKey == nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(s.signingKey.Public().(ed25519.PublicKey))
+}
+
+func (s *auditChainService) RunCheckpoints(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checkpoint, err := s.CreateCheckpoint(ctx)
+			if err != nil {
+				log.Error(ctx, "Error creating audit checkpoint", "error", err.Error())
+			} else if checkpoint != nil {
+				log.Info(ctx, "Created audit checkpoint", "checkpoint_id", checkpoint.Id, "last_audit_id", checkpoint.LastAuditId)
+			}
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package services_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func buildAuditChain(count int) []models.Audit {
+	var audits []models.Audit
+	prev := models.AuditChainGenesis
+	for i := 1; i <= count; i++ {
+		audit := models.Audit{
+			Id:                   i,
+			RuleId:               sql.NullInt64{Int64: int64(i), Valid: true},
+			UserId:               sql.NullInt64{Int64: 1, Valid: true},
+			ModificationDate:     time.Date(2025, 3, 1, 10, i, 0, 0, time.UTC),
+			NatureOfModification: "Created Rule",
+			PrevHash:             prev,
+		}
+		audit.Hash = audit.ComputeHash()
+		prev = audit.Hash
+		audits = append(audits, audit)
+	}
+	return audits
+}
+
+func signedCheckpoint(key ed25519.PrivateKey, id int, audit models.Audit) models.AuditCheckpoint {
+	checkpoint := models.AuditCheckpoint{Id: id, LastAuditId: audit.Id, HeadHash: audit.Hash, Entries: audit.Id, CreatedAt: time.Now()}
+	checkpoint.Sign(key)
+	return checkpoint
+}
+
+func expectAuditChain(mockRepo *repositories.MockAuditChainRepository, audits []models.Audit, checkpoints []models.AuditCheckpoint) {
+	mockRepo.EXPECT().StreamCheckpoints(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, fn func(models.AuditCheckpoint) error) error {
+			for _, checkpoint := range checkpoints {
+				if err := fn(checkpoint); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	mockRepo.EXPECT().StreamChain(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, fn func(models.Audit) error) error {
+			for _, audit := range audits {
+				if err := fn(audit); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Maybe()
+}
+
+func TestAuditChainService_Verify(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	_, otherKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	audits := buildAuditChain(4)
+
+	tests := []struct {
+		name          string
+		audits        []models.Audit
+		checkpoints   []models.AuditCheckpoint
+		expectedBreak *models.AuditChainBreak
+	}{
+		{
+			name:        "intact",
+			audits:      audits,
+			checkpoints: []models.AuditCheckpoint{signedCheckpoint(key, 1, audits[1]), signedCheckpoint(key, 2, audits[3])},
+		},
+		{
+			name:          "last entries removed",
+			audits:        audits[:2],
+			checkpoints:   []models.AuditCheckpoint{signedCheckpoint(key, 1, audits[1]), signedCheckpoint(key, 2, audits[3])},
+			expectedBreak: &models.AuditChainBreak{Id: 4, Reason: "entry is missing but checkpoint 2 covers it"},
+		},
+		{
+			name:          "checkpoint entry removed",
+			audits:        append(append([]models.Audit{}, audits[:1]...), audits[2:]...),
+			checkpoints:   []models.AuditCheckpoint{signedCheckpoint(key, 1, audits[1])},
+			expectedBreak: &models.AuditChainBreak{Id: 2, Reason: "entry is missing but checkpoint 1 covers it"},
+		},
+		{
+			name:          "untrusted key",
+			audits:        audits,
+			checkpoints:   []models.AuditCheckpoint{signedCheckpoint(otherKey, 1, audits[1])},
+			expectedBreak: &models.AuditChainBreak{Id: 2, Reason: "checkpoint 1 was signed with an untrusted key"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repositories.NewMockAuditChainRepository(t)
+			service := services.NewAuditChainService(mockRepo, nil)
+			expectAuditChain(mockRepo, tt.audits, tt.checkpoints)
+
+			report, err := service.Verify(context.Background(), key.Public().(ed25519.PublicKey))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedBreak, report.Break)
+		})
+	}
+}
+
+func TestAuditChainService_Verify_RehashedEntry(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	audits := buildAuditChain(3)
+	checkpoints := []models.AuditCheckpoint{signedCheckpoint(key, 1, audits[2])}
+
+	// Rewriting the whole chain from an entry on keeps the links, but not the checkpoint
+	audits[1].NatureOfModification = "Deleted Rule"
+	audits[1].Hash = audits[1].ComputeHash()
+	audits[2].PrevHash = audits[1].Hash
+	audits[2].Hash = audits[2].ComputeHash()
+
+	mockRepo := repositories.NewMockAuditChainRepository(t)
+	expectAuditChain(mockRepo, audits, checkpoints)
+
+	report, err := services.NewAuditChainService(mockRepo, nil).Verify(context.Background(), nil)
+
+	require.NoError(t, err)
+	require.NotNil(t, report.Break)
+	assert.Equal(t, 3, report.Break.Id)
+	assert.Equal(t, 3, report.Entries)
+	assert.Equal(t, 0, report.Checkpoints)
+}
+
+func TestAuditChainService_CreateCheckpoint(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("signs the new head", func(t *testing.T) {
+		mockRepo := repositories.NewMockAuditChainRepository(t)
+		service := services.NewAuditChainService(mockRepo, key)
+
+		mockRepo.EXPECT().GetChainHead(ctx).Return(&models.AuditChainHead{LastAuditId: 9, Hash: "abc", Entries: 8}, nil)
+		mockRepo.EXPECT().GetCheckpoints(ctx, 1).Return([]models.AuditCheckpoint{{LastAuditId: 5}}, nil)
+		mockRepo.EXPECT().AddCheckpoint(ctx, mock.Anything).Return(nil)
+
+		checkpoint, err := service.CreateCheckpoint(ctx)
+
+		require.NoError(t, err)
+		require.NotNil(t, checkpoint)
+		assert.Equal(t, 9, checkpoint.LastAuditId)
+		assert.Equal(t, service.PublicKey(), checkpoint.PublicKey)
+		assert.True(t, checkpoint.VerifySignature())
+	})
+
+	t.Run("skips an unchanged head", func(t *testing.T) {
+		mockRepo := repositories.NewMockAuditChainRepository(t)
+		service := services.NewAuditChainService(mockRepo, key)
+
+		mockRepo.EXPECT().GetChainHead(ctx).Return(&models.AuditChainHead{LastAuditId: 9, Hash: "abc", Entries: 8}, nil)
+		mockRepo.EXPECT().GetCheckpoints(ctx, 1).Return([]models.AuditCheckpoint{{LastAuditId: 9}}, nil)
+
+		checkpoint, err := service.CreateCheckpoint(ctx)
+
+		require.NoError(t, err)
+		assert.Nil(t, checkpoint)
+	})
+
+	t.Run("without key", func(t *testing.T) {
+		service := services.NewAuditChainService(repositories.NewMockAuditChainRepository(t), nil)
+
+		_, err := service.CreateCheckpoint(ctx)
+
+		assert.ErrorIs(t, err, services.ErrNoSigningKey)
+	})
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	repo "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+)
+
+type AuditService interface {
+	Record(ctx context.Context, event *models.AuditEvent) error
+	ListEvents(ctx context.Context, request models.AuditListRequest) ([]models.AuditEvent, models.Pagination, error)
+	StreamEvents(ctx context.Context, filter models.AuditFilter, fn func(models.AuditEvent) error) error
+}
+
+type auditService struct {
+	auditRepo repo.AuditRepository
+}
+
+func NewAuditService(auditRepo repo.AuditRepository) *auditService {
+	return &auditService{auditRepo: auditRepo}
+}
+
+func (s *auditService) Record(ctx context.Context, event *models.AuditEvent) error {
+	return s.auditRepo.AddAuditEvent(ctx, event)
+}
+
+// ListEvents returns the requested page of events, newest first
+func (s *auditService) ListEvents(ctx context.Context, request models.AuditListRequest) ([]models.AuditEvent, models.Pagination, error) {
+	page := models.NewPagination(request.Page, request.PageSize)
+	events, total, err := s.auditRepo.GetAuditEvents(ctx, request.AuditFilter, page.PageSize, page.Offset())
+	if err != nil {
+		return nil, models.Pagination{}, err
+	}
+	page.Total = total
+	return events, page, nil
+}
+
+func (s *auditService) StreamEvents(ctx context.Context, filter models.AuditFilter, fn func(models.AuditEvent) error) error {
+	return s.auditRepo.StreamAuditEvents(ctx, filter, fn)
+}
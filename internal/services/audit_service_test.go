@@ -0,0 +1,42 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditService_ListEvents(t *testing.T) {
+	tests := []struct {
+		name           string
+		request        models.AuditListRequest
+		expectedLimit  int
+		expectedOffset int
+	}{
+		{name: "defaults", request: models.AuditListRequest{}, expectedLimit: models.AuditDefaultPageSize, expectedOffset: 0},
+		{name: "third page", request: models.AuditListRequest{Page: 3, PageSize: 20}, expectedLimit: 20, expectedOffset: 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repositories.NewMockAuditRepository(t)
+			service := services.NewAuditService(mockRepo)
+			ctx := context.Background()
+
+			mockRepo.EXPECT().GetAuditEvents(ctx, tt.request.AuditFilter, tt.expectedLimit, tt.expectedOffset).
+				Return([]models.AuditEvent{{Id: 1}}, 45, nil)
+
+			events, page, err := service.ListEvents(ctx, tt.request)
+
+			require.NoError(t, err)
+			assert.Len(t, events, 1)
+			assert.Equal(t, tt.expectedLimit, page.PageSize)
+			assert.Equal(t, 45, page.Total)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	repo "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+)
+
+// InboxService lets users go through the notifications they were sent. Entries are added by the UserService when notifying.
+type InboxService interface {
+	// GetInbox returns the requested page of the inbox of the user, newest first
+	GetInbox(ctx context.Context, userId int, request models.InboxListRequest) (models.Inbox, error)
+	GetInboxEntry(ctx context.Context, userId int, id int) (*models.InboxEntry, error)
+	MarkRead(ctx context.Context, userId int, id int) (*models.InboxEntry, error)
+	// MarkAllRead marks every entry of the user as read, returning how many were unread
+	MarkAllRead(ctx context.Context, userId int) (int, error)
+	DeleteInboxEntry(ctx context.Context, userId int, id int) error
+}
+
+type inboxService struct {
+	inboxRepo repo.InboxRepository
+}
+
+func NewInboxService(inboxRepo repo.InboxRepository) *inboxService {
+	return &inboxService{inboxRepo: inboxRepo}
+}
+
+func (s *inboxService) GetInbox(ctx context.Context, userId int, request models.InboxListRequest) (models.Inbox, error) {
+	page := models.NewPagination(request.Page, request.PageSize)
+	entries, total, unread, err := s.inboxRepo.GetInbox(ctx, userId, request.Unread, page.PageSize, page.Offset())
+	if err != nil {
+		return models.Inbox{}, err
+	}
+	page.Total = total
+	return models.Inbox{Entries: entries, Pagination: page, Unread: unread}, nil
+}
+
+func (s *inboxService) GetInboxEntry(ctx context.Context, userId int, id int) (*models.InboxEntry, error) {
+	return s.inboxRepo.GetInboxEntry(ctx, userId, id)
+}
+
+func (s *inboxService) MarkRead(ctx context.Context, userId int, id int) (*models.InboxEntry, error) {
+	return s.inboxRepo.MarkInboxEntryRead(ctx, userId, id)
+}
+
+func (s *inboxService) MarkAllRead(ctx context.Context, userId int) (int, error) {
+	return s.inboxRepo.MarkInboxRead(ctx, userId)
+}
+
+func (s *inboxService) DeleteInboxEntry(ctx context.Context, userId int, id int) error {
+	return s.inboxRepo.DeleteInboxEntry(ctx, userId, id)
+}
@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInboxService_GetInbox(t *testing.T) {
+	tests := []struct {
+		name           string
+		request        models.InboxListRequest
+		expectedLimit  int
+		expectedOffset int
+	}{
+		{name: "defaults", request: models.InboxListRequest{}, expectedLimit: models.AuditDefaultPageSize, expectedOffset: 0},
+		{name: "unread second page", request: models.InboxListRequest{Page: 2, PageSize: 10, Unread: true}, expectedLimit: 10, expectedOffset: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repositories.NewMockInboxRepository(t)
+			service := services.NewInboxService(mockRepo)
+			ctx := context.Background()
+
+			mockRepo.EXPECT().GetInbox(ctx, 3, tt.request.Unread, tt.expectedLimit, tt.expectedOffset).
+				Return([]models.InboxEntry{{Id: 12, UserId: 3}}, 15, 4, nil)
+
+			inbox, err := service.GetInbox(ctx, 3, tt.request)
+
+			require.NoError(t, err)
+			assert.Len(t, inbox.Entries, 1)
+			assert.Equal(t, tt.expectedLimit, inbox.Pagination.PageSize)
+			assert.Equal(t, 15, inbox.Pagination.Total)
+			assert.Equal(t, 4, inbox.Unread)
+		})
+	}
+}
+
+func TestInboxService_GetInbox_Error(t *testing.T) {
+	mockRepo := repositories.NewMockInboxRepository(t)
+	service := services.NewInboxService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.EXPECT().GetInbox(ctx, 3, false, models.AuditDefaultPageSize, 0).Return(nil, 0, 0, errors.New("db down"))
+
+	_, err := service.GetInbox(ctx, 3, models.InboxListRequest{})
+
+	assert.EqualError(t, err, "db down")
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/go-core/pkg/log"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/mailer"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	repo "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+)
+
+// JobService runs the background jobs of the outbox
+type JobService interface {
+	// ProcessJobs claims a batch of due jobs and runs them, returning how many it claimed
+	ProcessJobs(ctx context.Context) (int, error)
+	// RunWorker processes the due jobs every interval until the context is done
+	RunWorker(ctx context.Context, interval time.Duration)
+	GetDeadJobs(ctx context.Context, limit int) ([]models.DeadJob, error)
+	RequeueDeadJob(ctx context.Context, id int) (*models.Job, error)
+}
+
+const (
+	// JobBatchSize is how many jobs a worker claims at a time
+	JobBatchSize = 20
+	// JobLease is how long a claimed job is hidden from other workers. It's renewed when the job starts
+	// running, and JobTimeout is shorter, so a running job can't be claimed by another worker.
+	JobLease = 5 * time.Minute
+	// JobTimeout is how long a job can run, so a hung provider doesn't stop the rest of the queue
+	JobTimeout = 2 * time.Minute
+	// JobMaxAttempts is how many times a job runs before it is moved to the dead-letter table
+	JobMaxAttempts = 8
+	// JobBaseBackoff is the wait before the first retry, it doubles with every attempt up to JobMaxBackoff
+	JobBaseBackoff = 30 * time.Second
+	JobMaxBackoff  = 6 * time.Hour
+)
+
+// ErrJobUnretryable marks failures that won't go away by retrying, like a malformed payload.
+// Jobs failing with it are moved to the dead-letter table right away.
+var ErrJobUnretryable = errors.New("job can't be retried")
+
+// ErrJobInterrupted is the failure of a job that can't run twice and was claimed again after a worker
+// stopped while running it
+var ErrJobInterrupted = fmt.Errorf("%w: the job was interrupted", ErrJobUnretryable)
+
+// jobsRunOnce are the types of jobs that aren't run again once started, because they can't tell
+// what they already did
+var jobsRunOnce = map[string]bool{models.JobBroadcast: true}
+
+// JobHandler does the work of a kind of job given its payload
+type JobHandler func(ctx context.Context, payload json.RawMessage) error
+
+type jobService struct {
+	jobRepo  repo.JobRepository
+	handlers map[string]JobHandler
+	now      func() time.Time
+}
+
+func NewJobService(jobRepo repo.JobRepository, handlers map[string]JobHandler) *jobService {
+	return &jobService{jobRepo: jobRepo, handlers: handlers, now: time.Now}
+}
+
+// JobBackoff returns how long to wait before running a job again after it failed for the nth time
+func JobBackoff(attempts int) time.Duration {
+	backoff := JobBaseBackoff
+	for i := 1; i < attempts && backoff < JobMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, JobMaxBackoff)
+}
+
+func (s *jobService) ProcessJobs(ctx context.Context) (int, error) {
+	jobs, err := s.jobRepo.ClaimJobs(ctx, JobBatchSize, JobLease)
+	if err != nil {
+		return 0, err
+	}
+
+	var firstErr error
+	for _, job := range jobs {
+		// The jobs before it may have taken most of the lease of the batch
+		renewed, err := s.jobRepo.RenewJobLease(ctx, job.Id, job.Attempts, JobLease)
+		if !renewed {
+			// Another worker claimed it after the lease ran out
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if err := s.finish(ctx, job, s.run(ctx, job)); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return len(jobs), firstErr
+}
+
+func (s *jobService) run(ctx context.Context, job models.Job) error {
+	handler, ok := s.handlers[job.Type]
+	if !ok {
+		return fmt.Errorf("%w: unknown job type %q", ErrJobUnretryable, job.Type)
+	}
+	if jobsRunOnce[job.Type] && job.Attempts > 1 {
+		return ErrJobInterrupted
+	}
+	ctx, cancel := context.WithTimeout(ctx, JobTimeout)
+	defer cancel()
+	return handler(ctx, job.Payload)
+}
+
+// finish records the result of running the job: done, retried later with backoff or dead
+func (s *jobService) finish(ctx context.Context, job models.Job, err error) error {
+	if err == nil {
+		return s.jobRepo.CompleteJob(ctx, job.Id)
+	}
+	if job.Attempts >= JobMaxAttempts || errors.Is(err, ErrJobUnretryable) {
+		log.Error(ctx, "Job failed, moving it to the dead-letter table", "job_id", job.Id, "type", job.Type, "attempts", job.Attempts, "error", err.Error())
+		return s.jobRepo.BuryJob(ctx, job.Id, err.Error())
+	}
+	runAt := s.now().Add(JobBackoff(job.Attempts))
+	log.Warn(ctx, "Job failed, retrying later", "job_id", job.Id, "type", job.Type, "attempts", job.Attempts, "run_at", runAt, "error", err.Error())
+	return s.jobRepo.RetryJob(ctx, job.Id, runAt, err.Error())
+}
+
+func (s *jobService) RunWorker(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// A full batch means there may be more due jobs, keep going until the queue is drained
+			for ctx.Err() == nil {
+				claimed, err := s.ProcessJobs(ctx)
+				if err != nil {
+					log.Error(ctx, "Error processing jobs", "error", err.Error())
+				}
+				if claimed < JobBatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+func (s *jobService) GetDeadJobs(ctx context.Context, limit int) ([]models.DeadJob, error) {
+	return s.jobRepo.GetDeadJobs(ctx, limit)
+}
+
+func (s *jobService) RequeueDeadJob(ctx context.Context, id int) (*models.Job, error) {
+	return s.jobRepo.RequeueDeadJob(ctx, id)
+}
+
+// EmailJobHandler sends the message of email jobs
+func EmailJobHandler(emailClient mailer.Mailer) JobHandler {
+	return func(ctx context.Context, payload json.RawMessage) error {
+		var message mailer.Message
+		if err := json.Unmarshal(payload, &message); err != nil {
+			return fmt.Errorf("%w: %w", ErrJobUnretryable, err)
+		}
+		return emailClient.Send(ctx, message)
+	}
+}
+
+// PushJobHandler sends the notification of push jobs to the device
+func PushJobHandler(ctx context.Context, payload json.RawMessage) error {
+	var push models.PushJob
+	if err := json.Unmarshal(payload, &push); err != nil {
+		return fmt.Errorf("%w: %w", ErrJobUnretryable, err)
+	}
+	return sendNotifToDevice(ctx, push)
+}
+
+// UserNotifier sends a notification to every user
+type UserNotifier interface {
+	NotifyAllUsers(ctx context.Context, notification models.NotifyRequest) error
+}
+
+// BroadcastJobHandler sends the notification of broadcast jobs to every user.
+// It isn't retried once it started, not even if its worker stopped, so the users it already reached
+// don't get it twice.
+func BroadcastJobHandler(notifier UserNotifier) JobHandler {
+	return func(ctx context.Context, payload json.RawMessage) error {
+		var notification models.NotifyRequest
+		if err := json.Unmarshal(payload, &notification); err != nil {
+			return fmt.Errorf("%w: %w", ErrJobUnretryable, err)
+		}
+		if err := notifier.NotifyAllUsers(ctx, notification); err != nil {
+			return fmt.Errorf("%w: %w", ErrJobUnretryable, err)
+		}
+		return nil
+	}
+}
+
+// emailJob renders the template in the language of the request and addresses it
+func emailJob(ctx context.Context, template string, data any, to mailer.Address) (models.Job, error) {
+	email, err := mailer.Render(template, mailer.LocalesFromContext(ctx), data)
+	if err != nil {
+		return models.Job{}, err
+	}
+	return models.NewEmailJob(email.Message(to))
+}
+
+// secretEmailJob is an emailJob whose data has credentials, like tokens or pins, so the email isn't kept if it can't be sent
+func secretEmailJob(ctx context.Context, template string, data any, to mailer.Address) (models.Job, error) {
+	job, err := emailJob(ctx, template, data, to)
+	job.Secret = true
+	return job, err
+}
@@ -0,0 +1,198 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/mailer"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobBackoff(t *testing.T) {
+	assert.Equal(t, services.JobBaseBackoff, services.JobBackoff(1))
+	assert.Equal(t, 2*services.JobBaseBackoff, services.JobBackoff(2))
+	assert.Equal(t, 8*services.JobBaseBackoff, services.JobBackoff(4))
+	assert.Equal(t, services.JobMaxBackoff, services.JobBackoff(30))
+}
+
+func TestJobService_ProcessJobs(t *testing.T) {
+	mockRepo := repositories.NewMockJobRepository(t)
+	sendErr := errors.New("provider down")
+	var handled []string
+	service := services.NewJobService(mockRepo, map[string]services.JobHandler{
+		models.JobEmail: func(ctx context.Context, payload json.RawMessage) error {
+			// Every job runs with a deadline, so a hung provider doesn't stop the queue
+			_, hasDeadline := ctx.Deadline()
+			assert.True(t, hasDeadline)
+			handled = append(handled, string(payload))
+			if string(payload) == `"fails"` {
+				return sendErr
+			}
+			return nil
+		},
+	})
+
+	ctx := context.Background()
+	mockRepo.EXPECT().ClaimJobs(ctx, services.JobBatchSize, services.JobLease).Return([]models.Job{
+		{Id: 1, Type: models.JobEmail, Payload: json.RawMessage(`"works"`), Attempts: 1},
+		{Id: 2, Type: models.JobEmail, Payload: json.RawMessage(`"fails"`), Attempts: 2},
+		{Id: 3, Type: models.JobEmail, Payload: json.RawMessage(`"fails"`), Attempts: services.JobMaxAttempts},
+		{Id: 4, Type: "fax", Payload: json.RawMessage(`{}`), Attempts: 1},
+		{Id: 5, Type: models.JobEmail, Payload: json.RawMessage(`"late"`), Attempts: 1},
+	}, nil)
+	for _, job := range []struct{ id, attempts int }{{1, 1}, {2, 2}, {3, services.JobMaxAttempts}, {4, 1}} {
+		mockRepo.EXPECT().RenewJobLease(ctx, job.id, job.attempts, services.JobLease).Return(true, nil)
+	}
+	// The lease ran out before its turn and another worker has it now
+	mockRepo.EXPECT().RenewJobLease(ctx, 5, 1, services.JobLease).Return(false, nil)
+	mockRepo.EXPECT().CompleteJob(ctx, 1).Return(nil)
+	// Failed jobs run again later, waiting longer the more they failed
+	mockRepo.EXPECT().RetryJob(ctx, 2, mock.MatchedBy(func(runAt time.Time) bool {
+		return runAt.Sub(time.Now()) > services.JobBackoff(2)-time.Minute && runAt.Sub(time.Now()) <= services.JobBackoff(2)
+	}), "provider down").Return(nil)
+	// Until they run out of attempts or can't ever work
+	mockRepo.EXPECT().BuryJob(ctx, 3, "provider down").Return(nil)
+	mockRepo.EXPECT().BuryJob(ctx, 4, mock.MatchedBy(func(lastError string) bool {
+		return strings.Contains(lastError, `unknown job type "fax"`)
+	})).Return(nil)
+
+	claimed, err := service.ProcessJobs(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, claimed)
+	assert.Equal(t, []string{`"works"`, `"fails"`, `"fails"`}, handled)
+}
+
+func TestJobService_ProcessJobs_InterruptedBroadcastIsntRunAgain(t *testing.T) {
+	mockRepo := repositories.NewMockJobRepository(t)
+	service := services.NewJobService(mockRepo, map[string]services.JobHandler{
+		models.JobBroadcast: func(context.Context, json.RawMessage) error {
+			t.Fatal("an interrupted broadcast ran again")
+			return nil
+		},
+	})
+
+	ctx := context.Background()
+	mockRepo.EXPECT().ClaimJobs(ctx, services.JobBatchSize, services.JobLease).Return([]models.Job{
+		{Id: 1, Type: models.JobBroadcast, Payload: json.RawMessage(`{}`), Attempts: 2},
+	}, nil)
+	mockRepo.EXPECT().RenewJobLease(ctx, 1, 2, services.JobLease).Return(true, nil)
+	mockRepo.EXPECT().BuryJob(ctx, 1, services.ErrJobInterrupted.Error()).Return(nil)
+
+	_, err := service.ProcessJobs(ctx)
+	assert.NoError(t, err)
+}
+
+func TestJobService_ProcessJobs_ClaimError(t *testing.T) {
+	mockRepo := repositories.NewMockJobRepository(t)
+	service := services.NewJobService(mockRepo, nil)
+
+	mockRepo.EXPECT().ClaimJobs(mock.Anything, services.JobBatchSize, services.JobLease).Return(nil, errors.New("db down"))
+
+	claimed, err := service.ProcessJobs(context.Background())
+
+	assert.EqualError(t, err, "db down")
+	assert.Zero(t, claimed)
+}
+
+func TestJobService_RequeueDeadJob(t *testing.T) {
+	mockRepo := repositories.NewMockJobRepository(t)
+	service := services.NewJobService(mockRepo, nil)
+
+	mockRepo.EXPECT().RequeueDeadJob(mock.Anything, 5).Return(nil, repositories.ErrNotFound)
+
+	_, err := service.RequeueDeadJob(context.Background(), 5)
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+}
+
+func TestEmailJobHandler(t *testing.T) {
+	mockMailer := mailer.NewMockMailer(t)
+	handler := services.EmailJobHandler(mockMailer)
+
+	message := mailer.Message{To: []mailer.Address{{Name: "Ana", Email: "ana@example.com"}}, Subject: "Hola", Text: "Hola Ana"}
+	job, err := models.NewEmailJob(message)
+	require.NoError(t, err)
+	mockMailer.EXPECT().Send(mock.Anything, message).Return(nil)
+
+	assert.NoError(t, handler(context.Background(), job.Payload))
+
+	// A payload that isn't a message won't ever be sent
+	err = handler(context.Background(), json.RawMessage(`[]`))
+	assert.ErrorIs(t, err, services.ErrJobUnretryable)
+}
+
+func TestBroadcastJobHandler(t *testing.T) {
+	notifier := services.NewMockUserNotifier(t)
+	handler := services.BroadcastJobHandler(notifier)
+
+	notification := models.NotifyRequest{NotificationTitle: "New rule in force: Attendance", NotificationText: "Attend classes", NotificationType: models.RuleNotification}
+	job, err := models.NewBroadcastJob(notification)
+	require.NoError(t, err)
+	notifier.EXPECT().NotifyAllUsers(mock.Anything, notification).Return(nil).Once()
+
+	assert.NoError(t, handler(context.Background(), job.Payload))
+
+	// Retrying would notify again the users it already reached
+	notifier.EXPECT().NotifyAllUsers(mock.Anything, notification).Return(errors.New("tokens failed")).Once()
+	err = handler(context.Background(), job.Payload)
+	assert.ErrorIs(t, err, services.ErrJobUnretryable)
+}
+
+func TestPushJobHandler_Expo(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var sent map[string]any
+	httpmock.RegisterResponder("POST", "https://exp.host/--/api/v2/push/send",
+		func(req *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(req.Body).Decode(&sent); err != nil {
+				return nil, err
+			}
+			return httpmock.NewStringResponse(200, `{"status":"ok"}`), nil
+		},
+	)
+
+	job, err := models.NewPushJob("ExponentPushToken[1234567890]", models.InboxEntry{
+		Id:     12,
+		UserId: 1,
+		Title:  "Test Title",
+		Text:   "This is a test",
+	})
+	require.NoError(t, err)
+
+	err = services.PushJobHandler(context.Background(), job.Payload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Test Title", sent["title"])
+	// The app opens the inbox entry when the notification is tapped
+	assert.Equal(t, map[string]any{"inbox_id": "12"}, sent["data"])
+}
+
+func TestPushJobHandler_FirebaseServiceAccountNotSet(t *testing.T) {
+	t.Setenv("FIREBASE_SERVICE_ACCOUNT", "")
+
+	// Non-Expo token to trigger Firebase path
+	job, err := models.NewPushJob("firebase_token_123", models.InboxEntry{
+		Id:     12,
+		UserId: 1,
+		Title:  "Test Title",
+		Text:   "Test Body",
+	})
+	require.NoError(t, err)
+
+	err = services.PushJobHandler(context.Background(), job.Payload)
+
+	// Retried later, the configuration may be fixed by then
+	assert.EqualError(t, err, "FIREBASE_SERVICE_ACCOUNT not set")
+}
@@ -6,21 +6,20 @@ package services
 
 import (
 	"context"
+	"crypto/ed25519"
 	"time"
 
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
-	"github.com/sendgrid/rest"
-	"github.com/sendgrid/sendgrid-go/helpers/mail"
 	mock "github.com/stretchr/testify/mock"
 )
 
-// NewMockChatService creates a new instance of MockChatService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// NewMockAuditChainService creates a new instance of MockAuditChainService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockChatService(t interface {
+func NewMockAuditChainService(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockChatService {
-	mock := &MockChatService{}
+}) *MockAuditChainService {
+	mock := &MockAuditChainService{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -28,284 +27,275 @@ func NewMockChatService(t interface {
 	return mock
 }
 
-// MockChatService is an autogenerated mock type for the ChatService type
-type MockChatService struct {
+// MockAuditChainService is an autogenerated mock type for the AuditChainService type
+type MockAuditChainService struct {
 	mock.Mock
 }
 
-type MockChatService_Expecter struct {
+type MockAuditChainService_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *MockChatService) EXPECT() *MockChatService_Expecter {
-	return &MockChatService_Expecter{mock: &_m.Mock}
+func (_m *MockAuditChainService) EXPECT() *MockAuditChainService_Expecter {
+	return &MockAuditChainService_Expecter{mock: &_m.Mock}
 }
 
-// GetMessages provides a mock function for the type MockChatService
-func (_mock *MockChatService) GetMessages(ctx context.Context, id int) ([]models.ChatMessage, error) {
-	ret := _mock.Called(ctx, id)
+// CreateCheckpoint provides a mock function for the type MockAuditChainService
+func (_mock *MockAuditChainService) CreateCheckpoint(ctx context.Context) (*models.AuditCheckpoint, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetMessages")
+		panic("no return value specified for CreateCheckpoint")
 	}
 
-	var r0 []models.ChatMessage
+	var r0 *models.AuditCheckpoint
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]models.ChatMessage, error)); ok {
-		return returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*models.AuditCheckpoint, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []models.ChatMessage); ok {
-		r0 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *models.AuditCheckpoint); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.ChatMessage)
+			r0 = ret.Get(0).(*models.AuditCheckpoint)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockChatService_GetMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessages'
-type MockChatService_GetMessages_Call struct {
+// MockAuditChainService_CreateCheckpoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCheckpoint'
+type MockAuditChainService_CreateCheckpoint_Call struct {
 	*mock.Call
 }
 
-// GetMessages is a helper method to define mock.On call
+// CreateCheckpoint is a helper method to define mock.On call
 //   - ctx
-//   - id
-func (_e *MockChatService_Expecter) GetMessages(ctx interface{}, id interface{}) *MockChatService_GetMessages_Call {
-	return &MockChatService_GetMessages_Call{Call: _e.mock.On("GetMessages", ctx, id)}
+func (_e *MockAuditChainService_Expecter) CreateCheckpoint(ctx interface{}) *MockAuditChainService_CreateCheckpoint_Call {
+	return &MockAuditChainService_CreateCheckpoint_Call{Call: _e.mock.On("CreateCheckpoint", ctx)}
 }
 
-func (_c *MockChatService_GetMessages_Call) Run(run func(ctx context.Context, id int)) *MockChatService_GetMessages_Call {
+func (_c *MockAuditChainService_CreateCheckpoint_Call) Run(run func(ctx context.Context)) *MockAuditChainService_CreateCheckpoint_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context))
 	})
 	return _c
 }
 
-func (_c *MockChatService_GetMessages_Call) Return(chatMessages []models.ChatMessage, err error) *MockChatService_GetMessages_Call {
-	_c.Call.Return(chatMessages, err)
+func (_c *MockAuditChainService_CreateCheckpoint_Call) Return(auditCheckpoint *models.AuditCheckpoint, err error) *MockAuditChainService_CreateCheckpoint_Call {
+	_c.Call.Return(auditCheckpoint, err)
 	return _c
 }
 
-func (_c *MockChatService_GetMessages_Call) RunAndReturn(run func(ctx context.Context, id int) ([]models.ChatMessage, error)) *MockChatService_GetMessages_Call {
+func (_c *MockAuditChainService_CreateCheckpoint_Call) RunAndReturn(run func(ctx context.Context) (*models.AuditCheckpoint, error)) *MockAuditChainService_CreateCheckpoint_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewUserMessage provides a mock function for the type MockChatService
-func (_mock *MockChatService) NewUserMessage(context1 context.Context, id int, message models.ChatMessageRequest) error {
-	ret := _mock.Called(context1, id, message)
+// GetCheckpoints provides a mock function for the type MockAuditChainService
+func (_mock *MockAuditChainService) GetCheckpoints(ctx context.Context, limit int) ([]models.AuditCheckpoint, error) {
+	ret := _mock.Called(ctx, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for NewUserMessage")
+		panic("no return value specified for GetCheckpoints")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.ChatMessageRequest) error); ok {
-		r0 = returnFunc(context1, id, message)
+	var r0 []models.AuditCheckpoint
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]models.AuditCheckpoint, error)); ok {
+		return returnFunc(ctx, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []models.AuditCheckpoint); ok {
+		r0 = returnFunc(ctx, limit)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AuditCheckpoint)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockChatService_NewUserMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NewUserMessage'
-type MockChatService_NewUserMessage_Call struct {
+// MockAuditChainService_GetCheckpoints_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCheckpoints'
+type MockAuditChainService_GetCheckpoints_Call struct {
 	*mock.Call
 }
 
-// NewUserMessage is a helper method to define mock.On call
-//   - context1
-//   - id
-//   - message
-func (_e *MockChatService_Expecter) NewUserMessage(context1 interface{}, id interface{}, message interface{}) *MockChatService_NewUserMessage_Call {
-	return &MockChatService_NewUserMessage_Call{Call: _e.mock.On("NewUserMessage", context1, id, message)}
+// GetCheckpoints is a helper method to define mock.On call
+//   - ctx
+//   - limit
+func (_e *MockAuditChainService_Expecter) GetCheckpoints(ctx interface{}, limit interface{}) *MockAuditChainService_GetCheckpoints_Call {
+	return &MockAuditChainService_GetCheckpoints_Call{Call: _e.mock.On("GetCheckpoints", ctx, limit)}
 }
 
-func (_c *MockChatService_NewUserMessage_Call) Run(run func(context1 context.Context, id int, message models.ChatMessageRequest)) *MockChatService_NewUserMessage_Call {
+func (_c *MockAuditChainService_GetCheckpoints_Call) Run(run func(ctx context.Context, limit int)) *MockAuditChainService_GetCheckpoints_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(models.ChatMessageRequest))
+		run(args[0].(context.Context), args[1].(int))
 	})
 	return _c
 }
 
-func (_c *MockChatService_NewUserMessage_Call) Return(err error) *MockChatService_NewUserMessage_Call {
-	_c.Call.Return(err)
+func (_c *MockAuditChainService_GetCheckpoints_Call) Return(auditCheckpoints []models.AuditCheckpoint, err error) *MockAuditChainService_GetCheckpoints_Call {
+	_c.Call.Return(auditCheckpoints, err)
 	return _c
 }
 
-func (_c *MockChatService_NewUserMessage_Call) RunAndReturn(run func(context1 context.Context, id int, message models.ChatMessageRequest) error) *MockChatService_NewUserMessage_Call {
+func (_c *MockAuditChainService_GetCheckpoints_Call) RunAndReturn(run func(ctx context.Context, limit int) ([]models.AuditCheckpoint, error)) *MockAuditChainService_GetCheckpoints_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SendToAi provides a mock function for the type MockChatService
-func (_mock *MockChatService) SendToAi(ctx context.Context, id int, message models.ChatMessageRequest) (*models.ChatMessage, error) {
-	ret := _mock.Called(ctx, id, message)
+// PublicKey provides a mock function for the type MockAuditChainService
+func (_mock *MockAuditChainService) PublicKey() string {
+	ret := _mock.Called()
 
 	if len(ret) == 0 {
-		panic("no return value specified for SendToAi")
+		panic("no return value specified for PublicKey")
 	}
 
-	var r0 *models.ChatMessage
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.ChatMessageRequest) (*models.ChatMessage, error)); ok {
-		return returnFunc(ctx, id, message)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.ChatMessageRequest) *models.ChatMessage); ok {
-		r0 = returnFunc(ctx, id, message)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.ChatMessage)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, models.ChatMessageRequest) error); ok {
-		r1 = returnFunc(ctx, id, message)
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Get(0).(string)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockChatService_SendToAi_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendToAi'
-type MockChatService_SendToAi_Call struct {
+// MockAuditChainService_PublicKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PublicKey'
+type MockAuditChainService_PublicKey_Call struct {
 	*mock.Call
 }
 
-// SendToAi is a helper method to define mock.On call
-//   - ctx
-//   - id
-//   - message
-func (_e *MockChatService_Expecter) SendToAi(ctx interface{}, id interface{}, message interface{}) *MockChatService_SendToAi_Call {
-	return &MockChatService_SendToAi_Call{Call: _e.mock.On("SendToAi", ctx, id, message)}
+// PublicKey is a helper method to define mock.On call
+func (_e *MockAuditChainService_Expecter) PublicKey() *MockAuditChainService_PublicKey_Call {
+	return &MockAuditChainService_PublicKey_Call{Call: _e.mock.On("PublicKey")}
 }
 
-func (_c *MockChatService_SendToAi_Call) Run(run func(ctx context.Context, id int, message models.ChatMessageRequest)) *MockChatService_SendToAi_Call {
+func (_c *MockAuditChainService_PublicKey_Call) Run(run func()) *MockAuditChainService_PublicKey_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(models.ChatMessageRequest))
+		run()
 	})
 	return _c
 }
 
-func (_c *MockChatService_SendToAi_Call) Return(chatMessage *models.ChatMessage, err error) *MockChatService_SendToAi_Call {
-	_c.Call.Return(chatMessage, err)
+func (_c *MockAuditChainService_PublicKey_Call) Return(s string) *MockAuditChainService_PublicKey_Call {
+	_c.Call.Return(s)
 	return _c
 }
 
-func (_c *MockChatService_SendToAi_Call) RunAndReturn(run func(ctx context.Context, id int, message models.ChatMessageRequest) (*models.ChatMessage, error)) *MockChatService_SendToAi_Call {
+func (_c *MockAuditChainService_PublicKey_Call) RunAndReturn(run func() string) *MockAuditChainService_PublicKey_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateMessageFeedback provides a mock function for the type MockChatService
-func (_mock *MockChatService) UpdateMessageFeedback(context1 context.Context, userId int, messageId int, feedback string) error {
-	ret := _mock.Called(context1, userId, messageId, feedback)
-
-	if len(ret) == 0 {
-		panic("no return value specified for UpdateMessageFeedback")
-	}
-
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) error); ok {
-		r0 = returnFunc(context1, userId, messageId, feedback)
-	} else {
-		r0 = ret.Error(0)
-	}
-	return r0
+// RunCheckpoints provides a mock function for the type MockAuditChainService
+func (_mock *MockAuditChainService) RunCheckpoints(ctx context.Context, interval time.Duration) {
+	_mock.Called(ctx, interval)
+	return
 }
 
-// MockChatService_UpdateMessageFeedback_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMessageFeedback'
-type MockChatService_UpdateMessageFeedback_Call struct {
+// MockAuditChainService_RunCheckpoints_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunCheckpoints'
+type MockAuditChainService_RunCheckpoints_Call struct {
 	*mock.Call
 }
 
-// UpdateMessageFeedback is a helper method to define mock.On call
-//   - context1
-//   - userId
-//   - messageId
-//   - feedback
-func (_e *MockChatService_Expecter) UpdateMessageFeedback(context1 interface{}, userId interface{}, messageId interface{}, feedback interface{}) *MockChatService_UpdateMessageFeedback_Call {
-	return &MockChatService_UpdateMessageFeedback_Call{Call: _e.mock.On("UpdateMessageFeedback", context1, userId, messageId, feedback)}
+// RunCheckpoints is a helper method to define mock.On call
+//   - ctx
+//   - interval
+func (_e *MockAuditChainService_Expecter) RunCheckpoints(ctx interface{}, interval interface{}) *MockAuditChainService_RunCheckpoints_Call {
+	return &MockAuditChainService_RunCheckpoints_Call{Call: _e.mock.On("RunCheckpoints", ctx, interval)}
 }
 
-func (_c *MockChatService_UpdateMessageFeedback_Call) Run(run func(context1 context.Context, userId int, messageId int, feedback string)) *MockChatService_UpdateMessageFeedback_Call {
+func (_c *MockAuditChainService_RunCheckpoints_Call) Run(run func(ctx context.Context, interval time.Duration)) *MockAuditChainService_RunCheckpoints_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
+		run(args[0].(context.Context), args[1].(time.Duration))
 	})
 	return _c
 }
 
-func (_c *MockChatService_UpdateMessageFeedback_Call) Return(err error) *MockChatService_UpdateMessageFeedback_Call {
-	_c.Call.Return(err)
+func (_c *MockAuditChainService_RunCheckpoints_Call) Return() *MockAuditChainService_RunCheckpoints_Call {
+	_c.Call.Return()
 	return _c
 }
 
-func (_c *MockChatService_UpdateMessageFeedback_Call) RunAndReturn(run func(context1 context.Context, userId int, messageId int, feedback string) error) *MockChatService_UpdateMessageFeedback_Call {
-	_c.Call.Return(run)
+func (_c *MockAuditChainService_RunCheckpoints_Call) RunAndReturn(run func(ctx context.Context, interval time.Duration)) *MockAuditChainService_RunCheckpoints_Call {
+	_c.Run(run)
 	return _c
 }
 
-// UpdateMessageRating provides a mock function for the type MockChatService
-func (_mock *MockChatService) UpdateMessageRating(context1 context.Context, userId int, messageId int, rating int) error {
-	ret := _mock.Called(context1, userId, messageId, rating)
+// Verify provides a mock function for the type MockAuditChainService
+func (_mock *MockAuditChainService) Verify(ctx context.Context, trustedKey ed25519.PublicKey) (*models.AuditChainReport, error) {
+	ret := _mock.Called(ctx, trustedKey)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateMessageRating")
+		panic("no return value specified for Verify")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) error); ok {
-		r0 = returnFunc(context1, userId, messageId, rating)
+	var r0 *models.AuditChainReport
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, ed25519.PublicKey) (*models.AuditChainReport, error)); ok {
+		return returnFunc(ctx, trustedKey)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, ed25519.PublicKey) *models.AuditChainReport); ok {
+		r0 = returnFunc(ctx, trustedKey)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AuditChainReport)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, ed25519.PublicKey) error); ok {
+		r1 = returnFunc(ctx, trustedKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockChatService_UpdateMessageRating_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMessageRating'
-type MockChatService_UpdateMessageRating_Call struct {
+// MockAuditChainService_Verify_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Verify'
+type MockAuditChainService_Verify_Call struct {
 	*mock.Call
 }
 
-// UpdateMessageRating is a helper method to define mock.On call
-//   - context1
-//   - userId
-//   - messageId
-//   - rating
-func (_e *MockChatService_Expecter) UpdateMessageRating(context1 interface{}, userId interface{}, messageId interface{}, rating interface{}) *MockChatService_UpdateMessageRating_Call {
-	return &MockChatService_UpdateMessageRating_Call{Call: _e.mock.On("UpdateMessageRating", context1, userId, messageId, rating)}
+// Verify is a helper method to define mock.On call
+//   - ctx
+//   - trustedKey
+func (_e *MockAuditChainService_Expecter) Verify(ctx interface{}, trustedKey interface{}) *MockAuditChainService_Verify_Call {
+	return &MockAuditChainService_Verify_Call{Call: _e.mock.On("Verify", ctx, trustedKey)}
 }
 
-func (_c *MockChatService_UpdateMessageRating_Call) Run(run func(context1 context.Context, userId int, messageId int, rating int)) *MockChatService_UpdateMessageRating_Call {
+func (_c *MockAuditChainService_Verify_Call) Run(run func(ctx context.Context, trustedKey ed25519.PublicKey)) *MockAuditChainService_Verify_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+		run(args[0].(context.Context), args[1].(ed25519.PublicKey))
 	})
 	return _c
 }
 
-func (_c *MockChatService_UpdateMessageRating_Call) Return(err error) *MockChatService_UpdateMessageRating_Call {
-	_c.Call.Return(err)
+func (_c *MockAuditChainService_Verify_Call) Return(auditChainReport *models.AuditChainReport, err error) *MockAuditChainService_Verify_Call {
+	_c.Call.Return(auditChainReport, err)
 	return _c
 }
 
-func (_c *MockChatService_UpdateMessageRating_Call) RunAndReturn(run func(context1 context.Context, userId int, messageId int, rating int) error) *MockChatService_UpdateMessageRating_Call {
+func (_c *MockAuditChainService_Verify_Call) RunAndReturn(run func(ctx context.Context, trustedKey ed25519.PublicKey) (*models.AuditChainReport, error)) *MockAuditChainService_Verify_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockLoginAttemptService creates a new instance of MockLoginAttemptService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// NewMockAuditService creates a new instance of MockAuditService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockLoginAttemptService(t interface {
+func NewMockAuditService(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockLoginAttemptService {
-	mock := &MockLoginAttemptService{}
+}) *MockAuditService {
+	mock := &MockAuditService{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -313,134 +303,182 @@ func NewMockLoginAttemptService(t interface {
 	return mock
 }
 
-// MockLoginAttemptService is an autogenerated mock type for the LoginAttemptService type
-type MockLoginAttemptService struct {
+// MockAuditService is an autogenerated mock type for the AuditService type
+type MockAuditService struct {
 	mock.Mock
 }
 
-type MockLoginAttemptService_Expecter struct {
+type MockAuditService_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *MockLoginAttemptService) EXPECT() *MockLoginAttemptService_Expecter {
-	return &MockLoginAttemptService_Expecter{mock: &_m.Mock}
+func (_m *MockAuditService) EXPECT() *MockAuditService_Expecter {
+	return &MockAuditService_Expecter{mock: &_m.Mock}
 }
 
-// AddLoginAttempt provides a mock function for the type MockLoginAttemptService
-func (_mock *MockLoginAttemptService) AddLoginAttempt(ctx context.Context, userID int, ipAddress string, userAgent string, successful bool) error {
-	ret := _mock.Called(ctx, userID, ipAddress, userAgent, successful)
+// ListEvents provides a mock function for the type MockAuditService
+func (_mock *MockAuditService) ListEvents(ctx context.Context, request models.AuditListRequest) ([]models.AuditEvent, models.Pagination, error) {
+	ret := _mock.Called(ctx, request)
 
 	if len(ret) == 0 {
-		panic("no return value specified for AddLoginAttempt")
+		panic("no return value specified for ListEvents")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, string, bool) error); ok {
-		r0 = returnFunc(ctx, userID, ipAddress, userAgent, successful)
+	var r0 []models.AuditEvent
+	var r1 models.Pagination
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.AuditListRequest) ([]models.AuditEvent, models.Pagination, error)); ok {
+		return returnFunc(ctx, request)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.AuditListRequest) []models.AuditEvent); ok {
+		r0 = returnFunc(ctx, request)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AuditEvent)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.AuditListRequest) models.Pagination); ok {
+		r1 = returnFunc(ctx, request)
+	} else {
+		r1 = ret.Get(1).(models.Pagination)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, models.AuditListRequest) error); ok {
+		r2 = returnFunc(ctx, request)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
 }
 
-// MockLoginAttemptService_AddLoginAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddLoginAttempt'
-type MockLoginAttemptService_AddLoginAttempt_Call struct {
+// MockAuditService_ListEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListEvents'
+type MockAuditService_ListEvents_Call struct {
 	*mock.Call
 }
 
-// AddLoginAttempt is a helper method to define mock.On call
+// ListEvents is a helper method to define mock.On call
 //   - ctx
-//   - userID
-//   - ipAddress
-//   - userAgent
-//   - successful
-func (_e *MockLoginAttemptService_Expecter) AddLoginAttempt(ctx interface{}, userID interface{}, ipAddress interface{}, userAgent interface{}, successful interface{}) *MockLoginAttemptService_AddLoginAttempt_Call {
-	return &MockLoginAttemptService_AddLoginAttempt_Call{Call: _e.mock.On("AddLoginAttempt", ctx, userID, ipAddress, userAgent, successful)}
+//   - request
+func (_e *MockAuditService_Expecter) ListEvents(ctx interface{}, request interface{}) *MockAuditService_ListEvents_Call {
+	return &MockAuditService_ListEvents_Call{Call: _e.mock.On("ListEvents", ctx, request)}
 }
 
-func (_c *MockLoginAttemptService_AddLoginAttempt_Call) Run(run func(ctx context.Context, userID int, ipAddress string, userAgent string, successful bool)) *MockLoginAttemptService_AddLoginAttempt_Call {
+func (_c *MockAuditService_ListEvents_Call) Run(run func(ctx context.Context, request models.AuditListRequest)) *MockAuditService_ListEvents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string), args[4].(bool))
+		run(args[0].(context.Context), args[1].(models.AuditListRequest))
 	})
 	return _c
 }
 
-func (_c *MockLoginAttemptService_AddLoginAttempt_Call) Return(err error) *MockLoginAttemptService_AddLoginAttempt_Call {
-	_c.Call.Return(err)
+func (_c *MockAuditService_ListEvents_Call) Return(auditEvents []models.AuditEvent, pagination models.Pagination, err error) *MockAuditService_ListEvents_Call {
+	_c.Call.Return(auditEvents, pagination, err)
 	return _c
 }
 
-func (_c *MockLoginAttemptService_AddLoginAttempt_Call) RunAndReturn(run func(ctx context.Context, userID int, ipAddress string, userAgent string, successful bool) error) *MockLoginAttemptService_AddLoginAttempt_Call {
+func (_c *MockAuditService_ListEvents_Call) RunAndReturn(run func(ctx context.Context, request models.AuditListRequest) ([]models.AuditEvent, models.Pagination, error)) *MockAuditService_ListEvents_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetLoginsByUserId provides a mock function for the type MockLoginAttemptService
-func (_mock *MockLoginAttemptService) GetLoginsByUserId(ctx context.Context, userID int, limit int, offset int) ([]*models.LoginAttempt, error) {
-	ret := _mock.Called(ctx, userID, limit, offset)
+// Record provides a mock function for the type MockAuditService
+func (_mock *MockAuditService) Record(ctx context.Context, event *models.AuditEvent) error {
+	ret := _mock.Called(ctx, event)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetLoginsByUserId")
+		panic("no return value specified for Record")
 	}
 
-	var r0 []*models.LoginAttempt
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) ([]*models.LoginAttempt, error)); ok {
-		return returnFunc(ctx, userID, limit, offset)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) []*models.LoginAttempt); ok {
-		r0 = returnFunc(ctx, userID, limit, offset)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.LoginAttempt)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
-		r1 = returnFunc(ctx, userID, limit, offset)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.AuditEvent) error); ok {
+		r0 = returnFunc(ctx, event)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockLoginAttemptService_GetLoginsByUserId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLoginsByUserId'
-type MockLoginAttemptService_GetLoginsByUserId_Call struct {
+// MockAuditService_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type MockAuditService_Record_Call struct {
 	*mock.Call
 }
 
-// GetLoginsByUserId is a helper method to define mock.On call
+// Record is a helper method to define mock.On call
 //   - ctx
-//   - userID
-//   - limit
-//   - offset
-func (_e *MockLoginAttemptService_Expecter) GetLoginsByUserId(ctx interface{}, userID interface{}, limit interface{}, offset interface{}) *MockLoginAttemptService_GetLoginsByUserId_Call {
-	return &MockLoginAttemptService_GetLoginsByUserId_Call{Call: _e.mock.On("GetLoginsByUserId", ctx, userID, limit, offset)}
+//   - event
+func (_e *MockAuditService_Expecter) Record(ctx interface{}, event interface{}) *MockAuditService_Record_Call {
+	return &MockAuditService_Record_Call{Call: _e.mock.On("Record", ctx, event)}
 }
 
-func (_c *MockLoginAttemptService_GetLoginsByUserId_Call) Run(run func(ctx context.Context, userID int, limit int, offset int)) *MockLoginAttemptService_GetLoginsByUserId_Call {
+func (_c *MockAuditService_Record_Call) Run(run func(ctx context.Context, event *models.AuditEvent)) *MockAuditService_Record_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+		run(args[0].(context.Context), args[1].(*models.AuditEvent))
 	})
 	return _c
 }
 
-func (_c *MockLoginAttemptService_GetLoginsByUserId_Call) Return(loginAttempts []*models.LoginAttempt, err error) *MockLoginAttemptService_GetLoginsByUserId_Call {
-	_c.Call.Return(loginAttempts, err)
+func (_c *MockAuditService_Record_Call) Return(err error) *MockAuditService_Record_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockLoginAttemptService_GetLoginsByUserId_Call) RunAndReturn(run func(ctx context.Context, userID int, limit int, offset int) ([]*models.LoginAttempt, error)) *MockLoginAttemptService_GetLoginsByUserId_Call {
+func (_c *MockAuditService_Record_Call) RunAndReturn(run func(ctx context.Context, event *models.AuditEvent) error) *MockAuditService_Record_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockRulesService creates a new instance of MockRulesService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func NewMockRulesService(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *MockRulesService {
-	mock := &MockRulesService{}
+// StreamEvents provides a mock function for the type MockAuditService
+func (_mock *MockAuditService) StreamEvents(ctx context.Context, filter models.AuditFilter, fn func(models.AuditEvent) error) error {
+	ret := _mock.Called(ctx, filter, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamEvents")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.AuditFilter, func(models.AuditEvent) error) error); ok {
+		r0 = returnFunc(ctx, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAuditService_StreamEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StreamEvents'
+type MockAuditService_StreamEvents_Call struct {
+	*mock.Call
+}
+
+// StreamEvents is a helper method to define mock.On call
+//   - ctx
+//   - filter
+//   - fn
+func (_e *MockAuditService_Expecter) StreamEvents(ctx interface{}, filter interface{}, fn interface{}) *MockAuditService_StreamEvents_Call {
+	return &MockAuditService_StreamEvents_Call{Call: _e.mock.On("StreamEvents", ctx, filter, fn)}
+}
+
+func (_c *MockAuditService_StreamEvents_Call) Run(run func(ctx context.Context, filter models.AuditFilter, fn func(models.AuditEvent) error)) *MockAuditService_StreamEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.AuditFilter), args[2].(func(models.AuditEvent) error))
+	})
+	return _c
+}
+
+func (_c *MockAuditService_StreamEvents_Call) Return(err error) *MockAuditService_StreamEvents_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAuditService_StreamEvents_Call) RunAndReturn(run func(ctx context.Context, filter models.AuditFilter, fn func(models.AuditEvent) error) error) *MockAuditService_StreamEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockChatService creates a new instance of MockChatService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockChatService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockChatService {
+	mock := &MockChatService{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -448,280 +486,284 @@ func NewMockRulesService(t interface {
 	return mock
 }
 
-// MockRulesService is an autogenerated mock type for the RulesService type
-type MockRulesService struct {
+// MockChatService is an autogenerated mock type for the ChatService type
+type MockChatService struct {
 	mock.Mock
 }
 
-type MockRulesService_Expecter struct {
+type MockChatService_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *MockRulesService) EXPECT() *MockRulesService_Expecter {
-	return &MockRulesService_Expecter{mock: &_m.Mock}
+func (_m *MockChatService) EXPECT() *MockChatService_Expecter {
+	return &MockChatService_Expecter{mock: &_m.Mock}
 }
 
-// CreateRule provides a mock function for the type MockRulesService
-func (_mock *MockRulesService) CreateRule(ctx context.Context, rule models.Rule, userId int) error {
-	ret := _mock.Called(ctx, rule, userId)
+// GetMessages provides a mock function for the type MockChatService
+func (_mock *MockChatService) GetMessages(ctx context.Context, id int) ([]models.ChatMessage, error) {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateRule")
+		panic("no return value specified for GetMessages")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Rule, int) error); ok {
-		r0 = returnFunc(ctx, rule, userId)
+	var r0 []models.ChatMessage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]models.ChatMessage, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []models.ChatMessage); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ChatMessage)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockRulesService_CreateRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRule'
-type MockRulesService_CreateRule_Call struct {
+// MockChatService_GetMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessages'
+type MockChatService_GetMessages_Call struct {
 	*mock.Call
 }
 
-// CreateRule is a helper method to define mock.On call
+// GetMessages is a helper method to define mock.On call
 //   - ctx
-//   - rule
-//   - userId
-func (_e *MockRulesService_Expecter) CreateRule(ctx interface{}, rule interface{}, userId interface{}) *MockRulesService_CreateRule_Call {
-	return &MockRulesService_CreateRule_Call{Call: _e.mock.On("CreateRule", ctx, rule, userId)}
+//   - id
+func (_e *MockChatService_Expecter) GetMessages(ctx interface{}, id interface{}) *MockChatService_GetMessages_Call {
+	return &MockChatService_GetMessages_Call{Call: _e.mock.On("GetMessages", ctx, id)}
 }
 
-func (_c *MockRulesService_CreateRule_Call) Run(run func(ctx context.Context, rule models.Rule, userId int)) *MockRulesService_CreateRule_Call {
+func (_c *MockChatService_GetMessages_Call) Run(run func(ctx context.Context, id int)) *MockChatService_GetMessages_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(models.Rule), args[2].(int))
+		run(args[0].(context.Context), args[1].(int))
 	})
 	return _c
 }
 
-func (_c *MockRulesService_CreateRule_Call) Return(err error) *MockRulesService_CreateRule_Call {
-	_c.Call.Return(err)
+func (_c *MockChatService_GetMessages_Call) Return(chatMessages []models.ChatMessage, err error) *MockChatService_GetMessages_Call {
+	_c.Call.Return(chatMessages, err)
 	return _c
 }
 
-func (_c *MockRulesService_CreateRule_Call) RunAndReturn(run func(ctx context.Context, rule models.Rule, userId int) error) *MockRulesService_CreateRule_Call {
+func (_c *MockChatService_GetMessages_Call) RunAndReturn(run func(ctx context.Context, id int) ([]models.ChatMessage, error)) *MockChatService_GetMessages_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteRule provides a mock function for the type MockRulesService
-func (_mock *MockRulesService) DeleteRule(ctx context.Context, ruleId int, userId int) error {
-	ret := _mock.Called(ctx, ruleId, userId)
+// NewUserMessage provides a mock function for the type MockChatService
+func (_mock *MockChatService) NewUserMessage(context1 context.Context, id int, message models.ChatMessageRequest) error {
+	ret := _mock.Called(context1, id, message)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteRule")
+		panic("no return value specified for NewUserMessage")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
-		r0 = returnFunc(ctx, ruleId, userId)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.ChatMessageRequest) error); ok {
+		r0 = returnFunc(context1, id, message)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRulesService_DeleteRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRule'
-type MockRulesService_DeleteRule_Call struct {
+// MockChatService_NewUserMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NewUserMessage'
+type MockChatService_NewUserMessage_Call struct {
 	*mock.Call
 }
 
-// DeleteRule is a helper method to define mock.On call
-//   - ctx
-//   - ruleId
-//   - userId
-func (_e *MockRulesService_Expecter) DeleteRule(ctx interface{}, ruleId interface{}, userId interface{}) *MockRulesService_DeleteRule_Call {
-	return &MockRulesService_DeleteRule_Call{Call: _e.mock.On("DeleteRule", ctx, ruleId, userId)}
+// NewUserMessage is a helper method to define mock.On call
+//   - context1
+//   - id
+//   - message
+func (_e *MockChatService_Expecter) NewUserMessage(context1 interface{}, id interface{}, message interface{}) *MockChatService_NewUserMessage_Call {
+	return &MockChatService_NewUserMessage_Call{Call: _e.mock.On("NewUserMessage", context1, id, message)}
 }
 
-func (_c *MockRulesService_DeleteRule_Call) Run(run func(ctx context.Context, ruleId int, userId int)) *MockRulesService_DeleteRule_Call {
+func (_c *MockChatService_NewUserMessage_Call) Run(run func(context1 context.Context, id int, message models.ChatMessageRequest)) *MockChatService_NewUserMessage_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(int))
+		run(args[0].(context.Context), args[1].(int), args[2].(models.ChatMessageRequest))
 	})
 	return _c
 }
 
-func (_c *MockRulesService_DeleteRule_Call) Return(err error) *MockRulesService_DeleteRule_Call {
+func (_c *MockChatService_NewUserMessage_Call) Return(err error) *MockChatService_NewUserMessage_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRulesService_DeleteRule_Call) RunAndReturn(run func(ctx context.Context, ruleId int, userId int) error) *MockRulesService_DeleteRule_Call {
+func (_c *MockChatService_NewUserMessage_Call) RunAndReturn(run func(context1 context.Context, id int, message models.ChatMessageRequest) error) *MockChatService_NewUserMessage_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetAudits provides a mock function for the type MockRulesService
-func (_mock *MockRulesService) GetAudits(ctx context.Context) ([]models.Audit, error) {
-	ret := _mock.Called(ctx)
+// SendToAi provides a mock function for the type MockChatService
+func (_mock *MockChatService) SendToAi(ctx context.Context, id int, message models.ChatMessageRequest) (*models.ChatMessage, error) {
+	ret := _mock.Called(ctx, id, message)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAudits")
+		panic("no return value specified for SendToAi")
 	}
 
-	var r0 []models.Audit
+	var r0 *models.ChatMessage
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.Audit, error)); ok {
-		return returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.ChatMessageRequest) (*models.ChatMessage, error)); ok {
+		return returnFunc(ctx, id, message)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.Audit); ok {
-		r0 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.ChatMessageRequest) *models.ChatMessage); ok {
+		r0 = returnFunc(ctx, id, message)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Audit)
+			r0 = ret.Get(0).(*models.ChatMessage)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, models.ChatMessageRequest) error); ok {
+		r1 = returnFunc(ctx, id, message)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRulesService_GetAudits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAudits'
-type MockRulesService_GetAudits_Call struct {
+// MockChatService_SendToAi_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendToAi'
+type MockChatService_SendToAi_Call struct {
 	*mock.Call
 }
 
-// GetAudits is a helper method to define mock.On call
+// SendToAi is a helper method to define mock.On call
 //   - ctx
-func (_e *MockRulesService_Expecter) GetAudits(ctx interface{}) *MockRulesService_GetAudits_Call {
-	return &MockRulesService_GetAudits_Call{Call: _e.mock.On("GetAudits", ctx)}
+//   - id
+//   - message
+func (_e *MockChatService_Expecter) SendToAi(ctx interface{}, id interface{}, message interface{}) *MockChatService_SendToAi_Call {
+	return &MockChatService_SendToAi_Call{Call: _e.mock.On("SendToAi", ctx, id, message)}
 }
 
-func (_c *MockRulesService_GetAudits_Call) Run(run func(ctx context.Context)) *MockRulesService_GetAudits_Call {
+func (_c *MockChatService_SendToAi_Call) Run(run func(ctx context.Context, id int, message models.ChatMessageRequest)) *MockChatService_SendToAi_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(int), args[2].(models.ChatMessageRequest))
 	})
 	return _c
 }
 
-func (_c *MockRulesService_GetAudits_Call) Return(audits []models.Audit, err error) *MockRulesService_GetAudits_Call {
-	_c.Call.Return(audits, err)
+func (_c *MockChatService_SendToAi_Call) Return(chatMessage *models.ChatMessage, err error) *MockChatService_SendToAi_Call {
+	_c.Call.Return(chatMessage, err)
 	return _c
 }
 
-func (_c *MockRulesService_GetAudits_Call) RunAndReturn(run func(ctx context.Context) ([]models.Audit, error)) *MockRulesService_GetAudits_Call {
+func (_c *MockChatService_SendToAi_Call) RunAndReturn(run func(ctx context.Context, id int, message models.ChatMessageRequest) (*models.ChatMessage, error)) *MockChatService_SendToAi_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetRules provides a mock function for the type MockRulesService
-func (_mock *MockRulesService) GetRules(ctx context.Context) ([]models.Rule, error) {
-	ret := _mock.Called(ctx)
+// UpdateMessageFeedback provides a mock function for the type MockChatService
+func (_mock *MockChatService) UpdateMessageFeedback(context1 context.Context, userId int, messageId int, feedback string) error {
+	ret := _mock.Called(context1, userId, messageId, feedback)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetRules")
+		panic("no return value specified for UpdateMessageFeedback")
 	}
 
-	var r0 []models.Rule
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.Rule, error)); ok {
-		return returnFunc(ctx)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.Rule); ok {
-		r0 = returnFunc(ctx)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Rule)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) error); ok {
+		r0 = returnFunc(context1, userId, messageId, feedback)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockRulesService_GetRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRules'
-type MockRulesService_GetRules_Call struct {
+// MockChatService_UpdateMessageFeedback_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMessageFeedback'
+type MockChatService_UpdateMessageFeedback_Call struct {
 	*mock.Call
 }
 
-// GetRules is a helper method to define mock.On call
-//   - ctx
-func (_e *MockRulesService_Expecter) GetRules(ctx interface{}) *MockRulesService_GetRules_Call {
-	return &MockRulesService_GetRules_Call{Call: _e.mock.On("GetRules", ctx)}
+// UpdateMessageFeedback is a helper method to define mock.On call
+//   - context1
+//   - userId
+//   - messageId
+//   - feedback
+func (_e *MockChatService_Expecter) UpdateMessageFeedback(context1 interface{}, userId interface{}, messageId interface{}, feedback interface{}) *MockChatService_UpdateMessageFeedback_Call {
+	return &MockChatService_UpdateMessageFeedback_Call{Call: _e.mock.On("UpdateMessageFeedback", context1, userId, messageId, feedback)}
 }
 
-func (_c *MockRulesService_GetRules_Call) Run(run func(ctx context.Context)) *MockRulesService_GetRules_Call {
+func (_c *MockChatService_UpdateMessageFeedback_Call) Run(run func(context1 context.Context, userId int, messageId int, feedback string)) *MockChatService_UpdateMessageFeedback_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
 	})
 	return _c
 }
 
-func (_c *MockRulesService_GetRules_Call) Return(rules []models.Rule, err error) *MockRulesService_GetRules_Call {
-	_c.Call.Return(rules, err)
+func (_c *MockChatService_UpdateMessageFeedback_Call) Return(err error) *MockChatService_UpdateMessageFeedback_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRulesService_GetRules_Call) RunAndReturn(run func(ctx context.Context) ([]models.Rule, error)) *MockRulesService_GetRules_Call {
+func (_c *MockChatService_UpdateMessageFeedback_Call) RunAndReturn(run func(context1 context.Context, userId int, messageId int, feedback string) error) *MockChatService_UpdateMessageFeedback_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ModifyRule provides a mock function for the type MockRulesService
-func (_mock *MockRulesService) ModifyRule(ctx context.Context, ruleId int, modification models.RuleModify, userId int) error {
-	ret := _mock.Called(ctx, ruleId, modification, userId)
+// UpdateMessageRating provides a mock function for the type MockChatService
+func (_mock *MockChatService) UpdateMessageRating(context1 context.Context, userId int, messageId int, rating int) error {
+	ret := _mock.Called(context1, userId, messageId, rating)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ModifyRule")
+		panic("no return value specified for UpdateMessageRating")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.RuleModify, int) error); ok {
-		r0 = returnFunc(ctx, ruleId, modification, userId)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) error); ok {
+		r0 = returnFunc(context1, userId, messageId, rating)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRulesService_ModifyRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ModifyRule'
-type MockRulesService_ModifyRule_Call struct {
+// MockChatService_UpdateMessageRating_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMessageRating'
+type MockChatService_UpdateMessageRating_Call struct {
 	*mock.Call
 }
 
-// ModifyRule is a helper method to define mock.On call
-//   - ctx
-//   - ruleId
-//   - modification
+// UpdateMessageRating is a helper method to define mock.On call
+//   - context1
 //   - userId
-func (_e *MockRulesService_Expecter) ModifyRule(ctx interface{}, ruleId interface{}, modification interface{}, userId interface{}) *MockRulesService_ModifyRule_Call {
-	return &MockRulesService_ModifyRule_Call{Call: _e.mock.On("ModifyRule", ctx, ruleId, modification, userId)}
+//   - messageId
+//   - rating
+func (_e *MockChatService_Expecter) UpdateMessageRating(context1 interface{}, userId interface{}, messageId interface{}, rating interface{}) *MockChatService_UpdateMessageRating_Call {
+	return &MockChatService_UpdateMessageRating_Call{Call: _e.mock.On("UpdateMessageRating", context1, userId, messageId, rating)}
 }
 
-func (_c *MockRulesService_ModifyRule_Call) Run(run func(ctx context.Context, ruleId int, modification models.RuleModify, userId int)) *MockRulesService_ModifyRule_Call {
+func (_c *MockChatService_UpdateMessageRating_Call) Run(run func(context1 context.Context, userId int, messageId int, rating int)) *MockChatService_UpdateMessageRating_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(models.RuleModify), args[3].(int))
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
 	})
 	return _c
 }
 
-func (_c *MockRulesService_ModifyRule_Call) Return(err error) *MockRulesService_ModifyRule_Call {
+func (_c *MockChatService_UpdateMessageRating_Call) Return(err error) *MockChatService_UpdateMessageRating_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRulesService_ModifyRule_Call) RunAndReturn(run func(ctx context.Context, ruleId int, modification models.RuleModify, userId int) error) *MockRulesService_ModifyRule_Call {
+func (_c *MockChatService_UpdateMessageRating_Call) RunAndReturn(run func(context1 context.Context, userId int, messageId int, rating int) error) *MockChatService_UpdateMessageRating_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockUserService creates a new instance of MockUserService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// NewMockInboxService creates a new instance of MockInboxService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockUserService(t interface {
+func NewMockInboxService(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockUserService {
-	mock := &MockUserService{}
+}) *MockInboxService {
+	mock := &MockInboxService{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -729,30 +771,3214 @@ func NewMockUserService(t interface {
 	return mock
 }
 
-// MockUserService is an autogenerated mock type for the UserService type
-type MockUserService struct {
+// MockInboxService is an autogenerated mock type for the InboxService type
+type MockInboxService struct {
 	mock.Mock
 }
 
-type MockUserService_Expecter struct {
+type MockInboxService_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *MockUserService) EXPECT() *MockUserService_Expecter {
-	return &MockUserService_Expecter{mock: &_m.Mock}
+func (_m *MockInboxService) EXPECT() *MockInboxService_Expecter {
+	return &MockInboxService_Expecter{mock: &_m.Mock}
 }
 
-// AddNotificationToken provides a mock function for the type MockUserService
-func (_mock *MockUserService) AddNotificationToken(ctx context.Context, id int, text string) error {
-	ret := _mock.Called(ctx, id, text)
+// DeleteInboxEntry provides a mock function for the type MockInboxService
+func (_mock *MockInboxService) DeleteInboxEntry(ctx context.Context, userId int, id int) error {
+	ret := _mock.Called(ctx, userId, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for AddNotificationToken")
+		panic("no return value specified for DeleteInboxEntry")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
-		r0 = returnFunc(ctx, id, text)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = returnFunc(ctx, userId, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockInboxService_DeleteInboxEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteInboxEntry'
+type MockInboxService_DeleteInboxEntry_Call struct {
+	*mock.Call
+}
+
+// DeleteInboxEntry is a helper method to define mock.On call
+//   - ctx
+//   - userId
+//   - id
+func (_e *MockInboxService_Expecter) DeleteInboxEntry(ctx interface{}, userId interface{}, id interface{}) *MockInboxService_DeleteInboxEntry_Call {
+	return &MockInboxService_DeleteInboxEntry_Call{Call: _e.mock.On("DeleteInboxEntry", ctx, userId, id)}
+}
+
+func (_c *MockInboxService_DeleteInboxEntry_Call) Run(run func(ctx context.Context, userId int, id int)) *MockInboxService_DeleteInboxEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockInboxService_DeleteInboxEntry_Call) Return(err error) *MockInboxService_DeleteInboxEntry_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockInboxService_DeleteInboxEntry_Call) RunAndReturn(run func(ctx context.Context, userId int, id int) error) *MockInboxService_DeleteInboxEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetInbox provides a mock function for the type MockInboxService
+func (_mock *MockInboxService) GetInbox(ctx context.Context, userId int, request models.InboxListRequest) (models.Inbox, error) {
+	ret := _mock.Called(ctx, userId, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInbox")
+	}
+
+	var r0 models.Inbox
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.InboxListRequest) (models.Inbox, error)); ok {
+		return returnFunc(ctx, userId, request)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.InboxListRequest) models.Inbox); ok {
+		r0 = returnFunc(ctx, userId, request)
+	} else {
+		r0 = ret.Get(0).(models.Inbox)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, models.InboxListRequest) error); ok {
+		r1 = returnFunc(ctx, userId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInboxService_GetInbox_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetInbox'
+type MockInboxService_GetInbox_Call struct {
+	*mock.Call
+}
+
+// GetInbox is a helper method to define mock.On call
+//   - ctx
+//   - userId
+//   - request
+func (_e *MockInboxService_Expecter) GetInbox(ctx interface{}, userId interface{}, request interface{}) *MockInboxService_GetInbox_Call {
+	return &MockInboxService_GetInbox_Call{Call: _e.mock.On("GetInbox", ctx, userId, request)}
+}
+
+func (_c *MockInboxService_GetInbox_Call) Run(run func(ctx context.Context, userId int, request models.InboxListRequest)) *MockInboxService_GetInbox_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.InboxListRequest))
+	})
+	return _c
+}
+
+func (_c *MockInboxService_GetInbox_Call) Return(inbox models.Inbox, err error) *MockInboxService_GetInbox_Call {
+	_c.Call.Return(inbox, err)
+	return _c
+}
+
+func (_c *MockInboxService_GetInbox_Call) RunAndReturn(run func(ctx context.Context, userId int, request models.InboxListRequest) (models.Inbox, error)) *MockInboxService_GetInbox_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetInboxEntry provides a mock function for the type MockInboxService
+func (_mock *MockInboxService) GetInboxEntry(ctx context.Context, userId int, id int) (*models.InboxEntry, error) {
+	ret := _mock.Called(ctx, userId, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInboxEntry")
+	}
+
+	var r0 *models.InboxEntry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) (*models.InboxEntry, error)); ok {
+		return returnFunc(ctx, userId, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) *models.InboxEntry); ok {
+		r0 = returnFunc(ctx, userId, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.InboxEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = returnFunc(ctx, userId, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInboxService_GetInboxEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetInboxEntry'
+type MockInboxService_GetInboxEntry_Call struct {
+	*mock.Call
+}
+
+// GetInboxEntry is a helper method to define mock.On call
+//   - ctx
+//   - userId
+//   - id
+func (_e *MockInboxService_Expecter) GetInboxEntry(ctx interface{}, userId interface{}, id interface{}) *MockInboxService_GetInboxEntry_Call {
+	return &MockInboxService_GetInboxEntry_Call{Call: _e.mock.On("GetInboxEntry", ctx, userId, id)}
+}
+
+func (_c *MockInboxService_GetInboxEntry_Call) Run(run func(ctx context.Context, userId int, id int)) *MockInboxService_GetInboxEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockInboxService_GetInboxEntry_Call) Return(inboxEntry *models.InboxEntry, err error) *MockInboxService_GetInboxEntry_Call {
+	_c.Call.Return(inboxEntry, err)
+	return _c
+}
+
+func (_c *MockInboxService_GetInboxEntry_Call) RunAndReturn(run func(ctx context.Context, userId int, id int) (*models.InboxEntry, error)) *MockInboxService_GetInboxEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkAllRead provides a mock function for the type MockInboxService
+func (_mock *MockInboxService) MarkAllRead(ctx context.Context, userId int) (int, error) {
+	ret := _mock.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkAllRead")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return returnFunc(ctx, userId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = returnFunc(ctx, userId)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInboxService_MarkAllRead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkAllRead'
+type MockInboxService_MarkAllRead_Call struct {
+	*mock.Call
+}
+
+// MarkAllRead is a helper method to define mock.On call
+//   - ctx
+//   - userId
+func (_e *MockInboxService_Expecter) MarkAllRead(ctx interface{}, userId interface{}) *MockInboxService_MarkAllRead_Call {
+	return &MockInboxService_MarkAllRead_Call{Call: _e.mock.On("MarkAllRead", ctx, userId)}
+}
+
+func (_c *MockInboxService_MarkAllRead_Call) Run(run func(ctx context.Context, userId int)) *MockInboxService_MarkAllRead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockInboxService_MarkAllRead_Call) Return(n int, err error) *MockInboxService_MarkAllRead_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockInboxService_MarkAllRead_Call) RunAndReturn(run func(ctx context.Context, userId int) (int, error)) *MockInboxService_MarkAllRead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkRead provides a mock function for the type MockInboxService
+func (_mock *MockInboxService) MarkRead(ctx context.Context, userId int, id int) (*models.InboxEntry, error) {
+	ret := _mock.Called(ctx, userId, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkRead")
+	}
+
+	var r0 *models.InboxEntry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) (*models.InboxEntry, error)); ok {
+		return returnFunc(ctx, userId, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) *models.InboxEntry); ok {
+		r0 = returnFunc(ctx, userId, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.InboxEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = returnFunc(ctx, userId, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockInboxService_MarkRead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkRead'
+type MockInboxService_MarkRead_Call struct {
+	*mock.Call
+}
+
+// MarkRead is a helper method to define mock.On call
+//   - ctx
+//   - userId
+//   - id
+func (_e *MockInboxService_Expecter) MarkRead(ctx interface{}, userId interface{}, id interface{}) *MockInboxService_MarkRead_Call {
+	return &MockInboxService_MarkRead_Call{Call: _e.mock.On("MarkRead", ctx, userId, id)}
+}
+
+func (_c *MockInboxService_MarkRead_Call) Run(run func(ctx context.Context, userId int, id int)) *MockInboxService_MarkRead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockInboxService_MarkRead_Call) Return(inboxEntry *models.InboxEntry, err error) *MockInboxService_MarkRead_Call {
+	_c.Call.Return(inboxEntry, err)
+	return _c
+}
+
+func (_c *MockInboxService_MarkRead_Call) RunAndReturn(run func(ctx context.Context, userId int, id int) (*models.InboxEntry, error)) *MockInboxService_MarkRead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockJobService creates a new instance of MockJobService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockJobService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockJobService {
+	mock := &MockJobService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockJobService is an autogenerated mock type for the JobService type
+type MockJobService struct {
+	mock.Mock
+}
+
+type MockJobService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockJobService) EXPECT() *MockJobService_Expecter {
+	return &MockJobService_Expecter{mock: &_m.Mock}
+}
+
+// GetDeadJobs provides a mock function for the type MockJobService
+func (_mock *MockJobService) GetDeadJobs(ctx context.Context, limit int) ([]models.DeadJob, error) {
+	ret := _mock.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadJobs")
+	}
+
+	var r0 []models.DeadJob
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]models.DeadJob, error)); ok {
+		return returnFunc(ctx, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []models.DeadJob); ok {
+		r0 = returnFunc(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.DeadJob)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockJobService_GetDeadJobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeadJobs'
+type MockJobService_GetDeadJobs_Call struct {
+	*mock.Call
+}
+
+// GetDeadJobs is a helper method to define mock.On call
+//   - ctx
+//   - limit
+func (_e *MockJobService_Expecter) GetDeadJobs(ctx interface{}, limit interface{}) *MockJobService_GetDeadJobs_Call {
+	return &MockJobService_GetDeadJobs_Call{Call: _e.mock.On("GetDeadJobs", ctx, limit)}
+}
+
+func (_c *MockJobService_GetDeadJobs_Call) Run(run func(ctx context.Context, limit int)) *MockJobService_GetDeadJobs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockJobService_GetDeadJobs_Call) Return(deadJobs []models.DeadJob, err error) *MockJobService_GetDeadJobs_Call {
+	_c.Call.Return(deadJobs, err)
+	return _c
+}
+
+func (_c *MockJobService_GetDeadJobs_Call) RunAndReturn(run func(ctx context.Context, limit int) ([]models.DeadJob, error)) *MockJobService_GetDeadJobs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ProcessJobs provides a mock function for the type MockJobService
+func (_mock *MockJobService) ProcessJobs(ctx context.Context) (int, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProcessJobs")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockJobService_ProcessJobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProcessJobs'
+type MockJobService_ProcessJobs_Call struct {
+	*mock.Call
+}
+
+// ProcessJobs is a helper method to define mock.On call
+//   - ctx
+func (_e *MockJobService_Expecter) ProcessJobs(ctx interface{}) *MockJobService_ProcessJobs_Call {
+	return &MockJobService_ProcessJobs_Call{Call: _e.mock.On("ProcessJobs", ctx)}
+}
+
+func (_c *MockJobService_ProcessJobs_Call) Run(run func(ctx context.Context)) *MockJobService_ProcessJobs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockJobService_ProcessJobs_Call) Return(n int, err error) *MockJobService_ProcessJobs_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockJobService_ProcessJobs_Call) RunAndReturn(run func(ctx context.Context) (int, error)) *MockJobService_ProcessJobs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RequeueDeadJob provides a mock function for the type MockJobService
+func (_mock *MockJobService) RequeueDeadJob(ctx context.Context, id int) (*models.Job, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequeueDeadJob")
+	}
+
+	var r0 *models.Job
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.Job, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.Job); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Job)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockJobService_RequeueDeadJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequeueDeadJob'
+type MockJobService_RequeueDeadJob_Call struct {
+	*mock.Call
+}
+
+// RequeueDeadJob is a helper method to define mock.On call
+//   - ctx
+//   - id
+func (_e *MockJobService_Expecter) RequeueDeadJob(ctx interface{}, id interface{}) *MockJobService_RequeueDeadJob_Call {
+	return &MockJobService_RequeueDeadJob_Call{Call: _e.mock.On("RequeueDeadJob", ctx, id)}
+}
+
+func (_c *MockJobService_RequeueDeadJob_Call) Run(run func(ctx context.Context, id int)) *MockJobService_RequeueDeadJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockJobService_RequeueDeadJob_Call) Return(job *models.Job, err error) *MockJobService_RequeueDeadJob_Call {
+	_c.Call.Return(job, err)
+	return _c
+}
+
+func (_c *MockJobService_RequeueDeadJob_Call) RunAndReturn(run func(ctx context.Context, id int) (*models.Job, error)) *MockJobService_RequeueDeadJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RunWorker provides a mock function for the type MockJobService
+func (_mock *MockJobService) RunWorker(ctx context.Context, interval time.Duration) {
+	_mock.Called(ctx, interval)
+	return
+}
+
+// MockJobService_RunWorker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunWorker'
+type MockJobService_RunWorker_Call struct {
+	*mock.Call
+}
+
+// RunWorker is a helper method to define mock.On call
+//   - ctx
+//   - interval
+func (_e *MockJobService_Expecter) RunWorker(ctx interface{}, interval interface{}) *MockJobService_RunWorker_Call {
+	return &MockJobService_RunWorker_Call{Call: _e.mock.On("RunWorker", ctx, interval)}
+}
+
+func (_c *MockJobService_RunWorker_Call) Run(run func(ctx context.Context, interval time.Duration)) *MockJobService_RunWorker_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockJobService_RunWorker_Call) Return() *MockJobService_RunWorker_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockJobService_RunWorker_Call) RunAndReturn(run func(ctx context.Context, interval time.Duration)) *MockJobService_RunWorker_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewMockUserNotifier creates a new instance of MockUserNotifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserNotifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserNotifier {
+	mock := &MockUserNotifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockUserNotifier is an autogenerated mock type for the UserNotifier type
+type MockUserNotifier struct {
+	mock.Mock
+}
+
+type MockUserNotifier_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUserNotifier) EXPECT() *MockUserNotifier_Expecter {
+	return &MockUserNotifier_Expecter{mock: &_m.Mock}
+}
+
+// NotifyAllUsers provides a mock function for the type MockUserNotifier
+func (_mock *MockUserNotifier) NotifyAllUsers(ctx context.Context, notification models.NotifyRequest) error {
+	ret := _mock.Called(ctx, notification)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NotifyAllUsers")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.NotifyRequest) error); ok {
+		r0 = returnFunc(ctx, notification)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserNotifier_NotifyAllUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NotifyAllUsers'
+type MockUserNotifier_NotifyAllUsers_Call struct {
+	*mock.Call
+}
+
+// NotifyAllUsers is a helper method to define mock.On call
+//   - ctx
+//   - notification
+func (_e *MockUserNotifier_Expecter) NotifyAllUsers(ctx interface{}, notification interface{}) *MockUserNotifier_NotifyAllUsers_Call {
+	return &MockUserNotifier_NotifyAllUsers_Call{Call: _e.mock.On("NotifyAllUsers", ctx, notification)}
+}
+
+func (_c *MockUserNotifier_NotifyAllUsers_Call) Run(run func(ctx context.Context, notification models.NotifyRequest)) *MockUserNotifier_NotifyAllUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.NotifyRequest))
+	})
+	return _c
+}
+
+func (_c *MockUserNotifier_NotifyAllUsers_Call) Return(err error) *MockUserNotifier_NotifyAllUsers_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserNotifier_NotifyAllUsers_Call) RunAndReturn(run func(ctx context.Context, notification models.NotifyRequest) error) *MockUserNotifier_NotifyAllUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockLoginAttemptService creates a new instance of MockLoginAttemptService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLoginAttemptService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLoginAttemptService {
+	mock := &MockLoginAttemptService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockLoginAttemptService is an autogenerated mock type for the LoginAttemptService type
+type MockLoginAttemptService struct {
+	mock.Mock
+}
+
+type MockLoginAttemptService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockLoginAttemptService) EXPECT() *MockLoginAttemptService_Expecter {
+	return &MockLoginAttemptService_Expecter{mock: &_m.Mock}
+}
+
+// AddLoginAttempt provides a mock function for the type MockLoginAttemptService
+func (_mock *MockLoginAttemptService) AddLoginAttempt(ctx context.Context, userID int, ipAddress string, userAgent string, successful bool) error {
+	ret := _mock.Called(ctx, userID, ipAddress, userAgent, successful)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddLoginAttempt")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, string, bool) error); ok {
+		r0 = returnFunc(ctx, userID, ipAddress, userAgent, successful)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLoginAttemptService_AddLoginAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddLoginAttempt'
+type MockLoginAttemptService_AddLoginAttempt_Call struct {
+	*mock.Call
+}
+
+// AddLoginAttempt is a helper method to define mock.On call
+//   - ctx
+//   - userID
+//   - ipAddress
+//   - userAgent
+//   - successful
+func (_e *MockLoginAttemptService_Expecter) AddLoginAttempt(ctx interface{}, userID interface{}, ipAddress interface{}, userAgent interface{}, successful interface{}) *MockLoginAttemptService_AddLoginAttempt_Call {
+	return &MockLoginAttemptService_AddLoginAttempt_Call{Call: _e.mock.On("AddLoginAttempt", ctx, userID, ipAddress, userAgent, successful)}
+}
+
+func (_c *MockLoginAttemptService_AddLoginAttempt_Call) Run(run func(ctx context.Context, userID int, ipAddress string, userAgent string, successful bool)) *MockLoginAttemptService_AddLoginAttempt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string), args[4].(bool))
+	})
+	return _c
+}
+
+func (_c *MockLoginAttemptService_AddLoginAttempt_Call) Return(err error) *MockLoginAttemptService_AddLoginAttempt_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLoginAttemptService_AddLoginAttempt_Call) RunAndReturn(run func(ctx context.Context, userID int, ipAddress string, userAgent string, successful bool) error) *MockLoginAttemptService_AddLoginAttempt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLoginsByUserId provides a mock function for the type MockLoginAttemptService
+func (_mock *MockLoginAttemptService) GetLoginsByUserId(ctx context.Context, userID int, limit int, offset int) ([]*models.LoginAttempt, error) {
+	ret := _mock.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoginsByUserId")
+	}
+
+	var r0 []*models.LoginAttempt
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) ([]*models.LoginAttempt, error)); ok {
+		return returnFunc(ctx, userID, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) []*models.LoginAttempt); ok {
+		r0 = returnFunc(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.LoginAttempt)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = returnFunc(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLoginAttemptService_GetLoginsByUserId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLoginsByUserId'
+type MockLoginAttemptService_GetLoginsByUserId_Call struct {
+	*mock.Call
+}
+
+// GetLoginsByUserId is a helper method to define mock.On call
+//   - ctx
+//   - userID
+//   - limit
+//   - offset
+func (_e *MockLoginAttemptService_Expecter) GetLoginsByUserId(ctx interface{}, userID interface{}, limit interface{}, offset interface{}) *MockLoginAttemptService_GetLoginsByUserId_Call {
+	return &MockLoginAttemptService_GetLoginsByUserId_Call{Call: _e.mock.On("GetLoginsByUserId", ctx, userID, limit, offset)}
+}
+
+func (_c *MockLoginAttemptService_GetLoginsByUserId_Call) Run(run func(ctx context.Context, userID int, limit int, offset int)) *MockLoginAttemptService_GetLoginsByUserId_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockLoginAttemptService_GetLoginsByUserId_Call) Return(loginAttempts []*models.LoginAttempt, err error) *MockLoginAttemptService_GetLoginsByUserId_Call {
+	_c.Call.Return(loginAttempts, err)
+	return _c
+}
+
+func (_c *MockLoginAttemptService_GetLoginsByUserId_Call) RunAndReturn(run func(ctx context.Context, userID int, limit int, offset int) ([]*models.LoginAttempt, error)) *MockLoginAttemptService_GetLoginsByUserId_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockPasswordService creates a new instance of MockPasswordService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPasswordService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPasswordService {
+	mock := &MockPasswordService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockPasswordService is an autogenerated mock type for the PasswordService type
+type MockPasswordService struct {
+	mock.Mock
+}
+
+type MockPasswordService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPasswordService) EXPECT() *MockPasswordService_Expecter {
+	return &MockPasswordService_Expecter{mock: &_m.Mock}
+}
+
+// ChangePassword provides a mock function for the type MockPasswordService
+func (_mock *MockPasswordService) ChangePassword(ctx context.Context, id int, currentPassword string, newPassword string) error {
+	ret := _mock.Called(ctx, id, currentPassword, newPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, string) error); ok {
+		r0 = returnFunc(ctx, id, currentPassword, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPasswordService_ChangePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangePassword'
+type MockPasswordService_ChangePassword_Call struct {
+	*mock.Call
+}
+
+// ChangePassword is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - currentPassword
+//   - newPassword
+func (_e *MockPasswordService_Expecter) ChangePassword(ctx interface{}, id interface{}, currentPassword interface{}, newPassword interface{}) *MockPasswordService_ChangePassword_Call {
+	return &MockPasswordService_ChangePassword_Call{Call: _e.mock.On("ChangePassword", ctx, id, currentPassword, newPassword)}
+}
+
+func (_c *MockPasswordService_ChangePassword_Call) Run(run func(ctx context.Context, id int, currentPassword string, newPassword string)) *MockPasswordService_ChangePassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockPasswordService_ChangePassword_Call) Return(err error) *MockPasswordService_ChangePassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPasswordService_ChangePassword_Call) RunAndReturn(run func(ctx context.Context, id int, currentPassword string, newPassword string) error) *MockPasswordService_ChangePassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CheckPassword provides a mock function for the type MockPasswordService
+func (_mock *MockPasswordService) CheckPassword(ctx context.Context, id int, password string) error {
+	ret := _mock.Called(ctx, id, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckPassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, id, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPasswordService_CheckPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckPassword'
+type MockPasswordService_CheckPassword_Call struct {
+	*mock.Call
+}
+
+// CheckPassword is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - password
+func (_e *MockPasswordService_Expecter) CheckPassword(ctx interface{}, id interface{}, password interface{}) *MockPasswordService_CheckPassword_Call {
+	return &MockPasswordService_CheckPassword_Call{Call: _e.mock.On("CheckPassword", ctx, id, password)}
+}
+
+func (_c *MockPasswordService_CheckPassword_Call) Run(run func(ctx context.Context, id int, password string)) *MockPasswordService_CheckPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockPasswordService_CheckPassword_Call) Return(err error) *MockPasswordService_CheckPassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPasswordService_CheckPassword_Call) RunAndReturn(run func(ctx context.Context, id int, password string) error) *MockPasswordService_CheckPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CheckPolicy provides a mock function for the type MockPasswordService
+func (_mock *MockPasswordService) CheckPolicy(password string, personalInfo []string) error {
+	ret := _mock.Called(password, personalInfo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckPolicy")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, []string) error); ok {
+		r0 = returnFunc(password, personalInfo)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPasswordService_CheckPolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckPolicy'
+type MockPasswordService_CheckPolicy_Call struct {
+	*mock.Call
+}
+
+// CheckPolicy is a helper method to define mock.On call
+//   - password
+//   - personalInfo
+func (_e *MockPasswordService_Expecter) CheckPolicy(password interface{}, personalInfo interface{}) *MockPasswordService_CheckPolicy_Call {
+	return &MockPasswordService_CheckPolicy_Call{Call: _e.mock.On("CheckPolicy", password, personalInfo)}
+}
+
+func (_c *MockPasswordService_CheckPolicy_Call) Run(run func(password string, personalInfo []string)) *MockPasswordService_CheckPolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *MockPasswordService_CheckPolicy_Call) Return(err error) *MockPasswordService_CheckPolicy_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPasswordService_CheckPolicy_Call) RunAndReturn(run func(password string, personalInfo []string) error) *MockPasswordService_CheckPolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HashPassword provides a mock function for the type MockPasswordService
+func (_mock *MockPasswordService) HashPassword(password string) (string, error) {
+	ret := _mock.Called(password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HashPassword")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return returnFunc(password)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) string); ok {
+		r0 = returnFunc(password)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(password)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockPasswordService_HashPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HashPassword'
+type MockPasswordService_HashPassword_Call struct {
+	*mock.Call
+}
+
+// HashPassword is a helper method to define mock.On call
+//   - password
+func (_e *MockPasswordService_Expecter) HashPassword(password interface{}) *MockPasswordService_HashPassword_Call {
+	return &MockPasswordService_HashPassword_Call{Call: _e.mock.On("HashPassword", password)}
+}
+
+func (_c *MockPasswordService_HashPassword_Call) Run(run func(password string)) *MockPasswordService_HashPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockPasswordService_HashPassword_Call) Return(s string, err error) *MockPasswordService_HashPassword_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockPasswordService_HashPassword_Call) RunAndReturn(run func(password string) (string, error)) *MockPasswordService_HashPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetPassword provides a mock function for the type MockPasswordService
+func (_mock *MockPasswordService) ResetPassword(ctx context.Context, tokenId int, userId int, password string) error {
+	ret := _mock.Called(ctx, tokenId, userId, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) error); ok {
+		r0 = returnFunc(ctx, tokenId, userId, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPasswordService_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
+type MockPasswordService_ResetPassword_Call struct {
+	*mock.Call
+}
+
+// ResetPassword is a helper method to define mock.On call
+//   - ctx
+//   - tokenId
+//   - userId
+//   - password
+func (_e *MockPasswordService_Expecter) ResetPassword(ctx interface{}, tokenId interface{}, userId interface{}, password interface{}) *MockPasswordService_ResetPassword_Call {
+	return &MockPasswordService_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, tokenId, userId, password)}
+}
+
+func (_c *MockPasswordService_ResetPassword_Call) Run(run func(ctx context.Context, tokenId int, userId int, password string)) *MockPasswordService_ResetPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockPasswordService_ResetPassword_Call) Return(err error) *MockPasswordService_ResetPassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPasswordService_ResetPassword_Call) RunAndReturn(run func(ctx context.Context, tokenId int, userId int, password string) error) *MockPasswordService_ResetPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetPassword provides a mock function for the type MockPasswordService
+func (_mock *MockPasswordService) SetPassword(ctx context.Context, id int, password string) error {
+	ret := _mock.Called(ctx, id, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, id, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockPasswordService_SetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetPassword'
+type MockPasswordService_SetPassword_Call struct {
+	*mock.Call
+}
+
+// SetPassword is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - password
+func (_e *MockPasswordService_Expecter) SetPassword(ctx interface{}, id interface{}, password interface{}) *MockPasswordService_SetPassword_Call {
+	return &MockPasswordService_SetPassword_Call{Call: _e.mock.On("SetPassword", ctx, id, password)}
+}
+
+func (_c *MockPasswordService_SetPassword_Call) Run(run func(ctx context.Context, id int, password string)) *MockPasswordService_SetPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockPasswordService_SetPassword_Call) Return(err error) *MockPasswordService_SetPassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockPasswordService_SetPassword_Call) RunAndReturn(run func(ctx context.Context, id int, password string) error) *MockPasswordService_SetPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRuleProposalService creates a new instance of MockRuleProposalService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRuleProposalService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRuleProposalService {
+	mock := &MockRuleProposalService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockRuleProposalService is an autogenerated mock type for the RuleProposalService type
+type MockRuleProposalService struct {
+	mock.Mock
+}
+
+type MockRuleProposalService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRuleProposalService) EXPECT() *MockRuleProposalService_Expecter {
+	return &MockRuleProposalService_Expecter{mock: &_m.Mock}
+}
+
+// ApproveProposal provides a mock function for the type MockRuleProposalService
+func (_mock *MockRuleProposalService) ApproveProposal(ctx context.Context, proposalId int, userId int) (*models.RuleProposal, error) {
+	ret := _mock.Called(ctx, proposalId, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApproveProposal")
+	}
+
+	var r0 *models.RuleProposal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) (*models.RuleProposal, error)); ok {
+		return returnFunc(ctx, proposalId, userId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) *models.RuleProposal); ok {
+		r0 = returnFunc(ctx, proposalId, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleProposal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = returnFunc(ctx, proposalId, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleProposalService_ApproveProposal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApproveProposal'
+type MockRuleProposalService_ApproveProposal_Call struct {
+	*mock.Call
+}
+
+// ApproveProposal is a helper method to define mock.On call
+//   - ctx
+//   - proposalId
+//   - userId
+func (_e *MockRuleProposalService_Expecter) ApproveProposal(ctx interface{}, proposalId interface{}, userId interface{}) *MockRuleProposalService_ApproveProposal_Call {
+	return &MockRuleProposalService_ApproveProposal_Call{Call: _e.mock.On("ApproveProposal", ctx, proposalId, userId)}
+}
+
+func (_c *MockRuleProposalService_ApproveProposal_Call) Run(run func(ctx context.Context, proposalId int, userId int)) *MockRuleProposalService_ApproveProposal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockRuleProposalService_ApproveProposal_Call) Return(ruleProposal *models.RuleProposal, err error) *MockRuleProposalService_ApproveProposal_Call {
+	_c.Call.Return(ruleProposal, err)
+	return _c
+}
+
+func (_c *MockRuleProposalService_ApproveProposal_Call) RunAndReturn(run func(ctx context.Context, proposalId int, userId int) (*models.RuleProposal, error)) *MockRuleProposalService_ApproveProposal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CommentProposal provides a mock function for the type MockRuleProposalService
+func (_mock *MockRuleProposalService) CommentProposal(ctx context.Context, proposalId int, userId int, comment string) (*models.RuleProposalComment, error) {
+	ret := _mock.Called(ctx, proposalId, userId, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CommentProposal")
+	}
+
+	var r0 *models.RuleProposalComment
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) (*models.RuleProposalComment, error)); ok {
+		return returnFunc(ctx, proposalId, userId, comment)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) *models.RuleProposalComment); ok {
+		r0 = returnFunc(ctx, proposalId, userId, comment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleProposalComment)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, string) error); ok {
+		r1 = returnFunc(ctx, proposalId, userId, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleProposalService_CommentProposal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CommentProposal'
+type MockRuleProposalService_CommentProposal_Call struct {
+	*mock.Call
+}
+
+// CommentProposal is a helper method to define mock.On call
+//   - ctx
+//   - proposalId
+//   - userId
+//   - comment
+func (_e *MockRuleProposalService_Expecter) CommentProposal(ctx interface{}, proposalId interface{}, userId interface{}, comment interface{}) *MockRuleProposalService_CommentProposal_Call {
+	return &MockRuleProposalService_CommentProposal_Call{Call: _e.mock.On("CommentProposal", ctx, proposalId, userId, comment)}
+}
+
+func (_c *MockRuleProposalService_CommentProposal_Call) Run(run func(ctx context.Context, proposalId int, userId int, comment string)) *MockRuleProposalService_CommentProposal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockRuleProposalService_CommentProposal_Call) Return(ruleProposalComment *models.RuleProposalComment, err error) *MockRuleProposalService_CommentProposal_Call {
+	_c.Call.Return(ruleProposalComment, err)
+	return _c
+}
+
+func (_c *MockRuleProposalService_CommentProposal_Call) RunAndReturn(run func(ctx context.Context, proposalId int, userId int, comment string) (*models.RuleProposalComment, error)) *MockRuleProposalService_CommentProposal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetProposal provides a mock function for the type MockRuleProposalService
+func (_mock *MockRuleProposalService) GetProposal(ctx context.Context, proposalId int) (*models.RuleProposal, error) {
+	ret := _mock.Called(ctx, proposalId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProposal")
+	}
+
+	var r0 *models.RuleProposal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.RuleProposal, error)); ok {
+		return returnFunc(ctx, proposalId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.RuleProposal); ok {
+		r0 = returnFunc(ctx, proposalId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleProposal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, proposalId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleProposalService_GetProposal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProposal'
+type MockRuleProposalService_GetProposal_Call struct {
+	*mock.Call
+}
+
+// GetProposal is a helper method to define mock.On call
+//   - ctx
+//   - proposalId
+func (_e *MockRuleProposalService_Expecter) GetProposal(ctx interface{}, proposalId interface{}) *MockRuleProposalService_GetProposal_Call {
+	return &MockRuleProposalService_GetProposal_Call{Call: _e.mock.On("GetProposal", ctx, proposalId)}
+}
+
+func (_c *MockRuleProposalService_GetProposal_Call) Run(run func(ctx context.Context, proposalId int)) *MockRuleProposalService_GetProposal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockRuleProposalService_GetProposal_Call) Return(ruleProposal *models.RuleProposal, err error) *MockRuleProposalService_GetProposal_Call {
+	_c.Call.Return(ruleProposal, err)
+	return _c
+}
+
+func (_c *MockRuleProposalService_GetProposal_Call) RunAndReturn(run func(ctx context.Context, proposalId int) (*models.RuleProposal, error)) *MockRuleProposalService_GetProposal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetProposals provides a mock function for the type MockRuleProposalService
+func (_mock *MockRuleProposalService) GetProposals(ctx context.Context, filter models.RuleProposalListRequest) ([]models.RuleProposal, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProposals")
+	}
+
+	var r0 []models.RuleProposal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleProposalListRequest) ([]models.RuleProposal, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleProposalListRequest) []models.RuleProposal); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.RuleProposal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.RuleProposalListRequest) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleProposalService_GetProposals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProposals'
+type MockRuleProposalService_GetProposals_Call struct {
+	*mock.Call
+}
+
+// GetProposals is a helper method to define mock.On call
+//   - ctx
+//   - filter
+func (_e *MockRuleProposalService_Expecter) GetProposals(ctx interface{}, filter interface{}) *MockRuleProposalService_GetProposals_Call {
+	return &MockRuleProposalService_GetProposals_Call{Call: _e.mock.On("GetProposals", ctx, filter)}
+}
+
+func (_c *MockRuleProposalService_GetProposals_Call) Run(run func(ctx context.Context, filter models.RuleProposalListRequest)) *MockRuleProposalService_GetProposals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.RuleProposalListRequest))
+	})
+	return _c
+}
+
+func (_c *MockRuleProposalService_GetProposals_Call) Return(ruleProposals []models.RuleProposal, err error) *MockRuleProposalService_GetProposals_Call {
+	_c.Call.Return(ruleProposals, err)
+	return _c
+}
+
+func (_c *MockRuleProposalService_GetProposals_Call) RunAndReturn(run func(ctx context.Context, filter models.RuleProposalListRequest) ([]models.RuleProposal, error)) *MockRuleProposalService_GetProposals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ProposeDeletion provides a mock function for the type MockRuleProposalService
+func (_mock *MockRuleProposalService) ProposeDeletion(ctx context.Context, ruleId int, userId int, ifMatch string) (*models.RuleProposal, error) {
+	ret := _mock.Called(ctx, ruleId, userId, ifMatch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProposeDeletion")
+	}
+
+	var r0 *models.RuleProposal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) (*models.RuleProposal, error)); ok {
+		return returnFunc(ctx, ruleId, userId, ifMatch)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) *models.RuleProposal); ok {
+		r0 = returnFunc(ctx, ruleId, userId, ifMatch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleProposal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, string) error); ok {
+		r1 = returnFunc(ctx, ruleId, userId, ifMatch)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleProposalService_ProposeDeletion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProposeDeletion'
+type MockRuleProposalService_ProposeDeletion_Call struct {
+	*mock.Call
+}
+
+// ProposeDeletion is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+//   - userId
+//   - ifMatch
+func (_e *MockRuleProposalService_Expecter) ProposeDeletion(ctx interface{}, ruleId interface{}, userId interface{}, ifMatch interface{}) *MockRuleProposalService_ProposeDeletion_Call {
+	return &MockRuleProposalService_ProposeDeletion_Call{Call: _e.mock.On("ProposeDeletion", ctx, ruleId, userId, ifMatch)}
+}
+
+func (_c *MockRuleProposalService_ProposeDeletion_Call) Run(run func(ctx context.Context, ruleId int, userId int, ifMatch string)) *MockRuleProposalService_ProposeDeletion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockRuleProposalService_ProposeDeletion_Call) Return(ruleProposal *models.RuleProposal, err error) *MockRuleProposalService_ProposeDeletion_Call {
+	_c.Call.Return(ruleProposal, err)
+	return _c
+}
+
+func (_c *MockRuleProposalService_ProposeDeletion_Call) RunAndReturn(run func(ctx context.Context, ruleId int, userId int, ifMatch string) (*models.RuleProposal, error)) *MockRuleProposalService_ProposeDeletion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ProposeModification provides a mock function for the type MockRuleProposalService
+func (_mock *MockRuleProposalService) ProposeModification(ctx context.Context, ruleId int, modification models.RuleModify, userId int, ifMatch string) (*models.RuleProposal, error) {
+	ret := _mock.Called(ctx, ruleId, modification, userId, ifMatch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProposeModification")
+	}
+
+	var r0 *models.RuleProposal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.RuleModify, int, string) (*models.RuleProposal, error)); ok {
+		return returnFunc(ctx, ruleId, modification, userId, ifMatch)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.RuleModify, int, string) *models.RuleProposal); ok {
+		r0 = returnFunc(ctx, ruleId, modification, userId, ifMatch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleProposal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, models.RuleModify, int, string) error); ok {
+		r1 = returnFunc(ctx, ruleId, modification, userId, ifMatch)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleProposalService_ProposeModification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProposeModification'
+type MockRuleProposalService_ProposeModification_Call struct {
+	*mock.Call
+}
+
+// ProposeModification is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+//   - modification
+//   - userId
+//   - ifMatch
+func (_e *MockRuleProposalService_Expecter) ProposeModification(ctx interface{}, ruleId interface{}, modification interface{}, userId interface{}, ifMatch interface{}) *MockRuleProposalService_ProposeModification_Call {
+	return &MockRuleProposalService_ProposeModification_Call{Call: _e.mock.On("ProposeModification", ctx, ruleId, modification, userId, ifMatch)}
+}
+
+func (_c *MockRuleProposalService_ProposeModification_Call) Run(run func(ctx context.Context, ruleId int, modification models.RuleModify, userId int, ifMatch string)) *MockRuleProposalService_ProposeModification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.RuleModify), args[3].(int), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockRuleProposalService_ProposeModification_Call) Return(ruleProposal *models.RuleProposal, err error) *MockRuleProposalService_ProposeModification_Call {
+	_c.Call.Return(ruleProposal, err)
+	return _c
+}
+
+func (_c *MockRuleProposalService_ProposeModification_Call) RunAndReturn(run func(ctx context.Context, ruleId int, modification models.RuleModify, userId int, ifMatch string) (*models.RuleProposal, error)) *MockRuleProposalService_ProposeModification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ProposeRollback provides a mock function for the type MockRuleProposalService
+func (_mock *MockRuleProposalService) ProposeRollback(ctx context.Context, ruleId int, version int, userId int, ifMatch string) (*models.RuleProposal, error) {
+	ret := _mock.Called(ctx, ruleId, version, userId, ifMatch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProposeRollback")
+	}
+
+	var r0 *models.RuleProposal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int, string) (*models.RuleProposal, error)); ok {
+		return returnFunc(ctx, ruleId, version, userId, ifMatch)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int, string) *models.RuleProposal); ok {
+		r0 = returnFunc(ctx, ruleId, version, userId, ifMatch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleProposal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, int, string) error); ok {
+		r1 = returnFunc(ctx, ruleId, version, userId, ifMatch)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleProposalService_ProposeRollback_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProposeRollback'
+type MockRuleProposalService_ProposeRollback_Call struct {
+	*mock.Call
+}
+
+// ProposeRollback is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+//   - version
+//   - userId
+//   - ifMatch
+func (_e *MockRuleProposalService_Expecter) ProposeRollback(ctx interface{}, ruleId interface{}, version interface{}, userId interface{}, ifMatch interface{}) *MockRuleProposalService_ProposeRollback_Call {
+	return &MockRuleProposalService_ProposeRollback_Call{Call: _e.mock.On("ProposeRollback", ctx, ruleId, version, userId, ifMatch)}
+}
+
+func (_c *MockRuleProposalService_ProposeRollback_Call) Run(run func(ctx context.Context, ruleId int, version int, userId int, ifMatch string)) *MockRuleProposalService_ProposeRollback_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockRuleProposalService_ProposeRollback_Call) Return(ruleProposal *models.RuleProposal, err error) *MockRuleProposalService_ProposeRollback_Call {
+	_c.Call.Return(ruleProposal, err)
+	return _c
+}
+
+func (_c *MockRuleProposalService_ProposeRollback_Call) RunAndReturn(run func(ctx context.Context, ruleId int, version int, userId int, ifMatch string) (*models.RuleProposal, error)) *MockRuleProposalService_ProposeRollback_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ProposeRule provides a mock function for the type MockRuleProposalService
+func (_mock *MockRuleProposalService) ProposeRule(ctx context.Context, rule models.Rule, userId int) (*models.RuleProposal, error) {
+	ret := _mock.Called(ctx, rule, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProposeRule")
+	}
+
+	var r0 *models.RuleProposal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Rule, int) (*models.RuleProposal, error)); ok {
+		return returnFunc(ctx, rule, userId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Rule, int) *models.RuleProposal); ok {
+		r0 = returnFunc(ctx, rule, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleProposal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.Rule, int) error); ok {
+		r1 = returnFunc(ctx, rule, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleProposalService_ProposeRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProposeRule'
+type MockRuleProposalService_ProposeRule_Call struct {
+	*mock.Call
+}
+
+// ProposeRule is a helper method to define mock.On call
+//   - ctx
+//   - rule
+//   - userId
+func (_e *MockRuleProposalService_Expecter) ProposeRule(ctx interface{}, rule interface{}, userId interface{}) *MockRuleProposalService_ProposeRule_Call {
+	return &MockRuleProposalService_ProposeRule_Call{Call: _e.mock.On("ProposeRule", ctx, rule, userId)}
+}
+
+func (_c *MockRuleProposalService_ProposeRule_Call) Run(run func(ctx context.Context, rule models.Rule, userId int)) *MockRuleProposalService_ProposeRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Rule), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockRuleProposalService_ProposeRule_Call) Return(ruleProposal *models.RuleProposal, err error) *MockRuleProposalService_ProposeRule_Call {
+	_c.Call.Return(ruleProposal, err)
+	return _c
+}
+
+func (_c *MockRuleProposalService_ProposeRule_Call) RunAndReturn(run func(ctx context.Context, rule models.Rule, userId int) (*models.RuleProposal, error)) *MockRuleProposalService_ProposeRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ProposeStatusChange provides a mock function for the type MockRuleProposalService
+func (_mock *MockRuleProposalService) ProposeStatusChange(ctx context.Context, ruleId int, action models.RuleProposalAction, userId int, ifMatch string) (*models.RuleProposal, error) {
+	ret := _mock.Called(ctx, ruleId, action, userId, ifMatch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProposeStatusChange")
+	}
+
+	var r0 *models.RuleProposal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.RuleProposalAction, int, string) (*models.RuleProposal, error)); ok {
+		return returnFunc(ctx, ruleId, action, userId, ifMatch)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.RuleProposalAction, int, string) *models.RuleProposal); ok {
+		r0 = returnFunc(ctx, ruleId, action, userId, ifMatch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleProposal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, models.RuleProposalAction, int, string) error); ok {
+		r1 = returnFunc(ctx, ruleId, action, userId, ifMatch)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleProposalService_ProposeStatusChange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProposeStatusChange'
+type MockRuleProposalService_ProposeStatusChange_Call struct {
+	*mock.Call
+}
+
+// ProposeStatusChange is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+//   - action
+//   - userId
+//   - ifMatch
+func (_e *MockRuleProposalService_Expecter) ProposeStatusChange(ctx interface{}, ruleId interface{}, action interface{}, userId interface{}, ifMatch interface{}) *MockRuleProposalService_ProposeStatusChange_Call {
+	return &MockRuleProposalService_ProposeStatusChange_Call{Call: _e.mock.On("ProposeStatusChange", ctx, ruleId, action, userId, ifMatch)}
+}
+
+func (_c *MockRuleProposalService_ProposeStatusChange_Call) Run(run func(ctx context.Context, ruleId int, action models.RuleProposalAction, userId int, ifMatch string)) *MockRuleProposalService_ProposeStatusChange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.RuleProposalAction), args[3].(int), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockRuleProposalService_ProposeStatusChange_Call) Return(ruleProposal *models.RuleProposal, err error) *MockRuleProposalService_ProposeStatusChange_Call {
+	_c.Call.Return(ruleProposal, err)
+	return _c
+}
+
+func (_c *MockRuleProposalService_ProposeStatusChange_Call) RunAndReturn(run func(ctx context.Context, ruleId int, action models.RuleProposalAction, userId int, ifMatch string) (*models.RuleProposal, error)) *MockRuleProposalService_ProposeStatusChange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RejectProposal provides a mock function for the type MockRuleProposalService
+func (_mock *MockRuleProposalService) RejectProposal(ctx context.Context, proposalId int, userId int, reason string) (*models.RuleProposal, error) {
+	ret := _mock.Called(ctx, proposalId, userId, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectProposal")
+	}
+
+	var r0 *models.RuleProposal
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) (*models.RuleProposal, error)); ok {
+		return returnFunc(ctx, proposalId, userId, reason)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) *models.RuleProposal); ok {
+		r0 = returnFunc(ctx, proposalId, userId, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleProposal)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, string) error); ok {
+		r1 = returnFunc(ctx, proposalId, userId, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRuleProposalService_RejectProposal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RejectProposal'
+type MockRuleProposalService_RejectProposal_Call struct {
+	*mock.Call
+}
+
+// RejectProposal is a helper method to define mock.On call
+//   - ctx
+//   - proposalId
+//   - userId
+//   - reason
+func (_e *MockRuleProposalService_Expecter) RejectProposal(ctx interface{}, proposalId interface{}, userId interface{}, reason interface{}) *MockRuleProposalService_RejectProposal_Call {
+	return &MockRuleProposalService_RejectProposal_Call{Call: _e.mock.On("RejectProposal", ctx, proposalId, userId, reason)}
+}
+
+func (_c *MockRuleProposalService_RejectProposal_Call) Run(run func(ctx context.Context, proposalId int, userId int, reason string)) *MockRuleProposalService_RejectProposal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockRuleProposalService_RejectProposal_Call) Return(ruleProposal *models.RuleProposal, err error) *MockRuleProposalService_RejectProposal_Call {
+	_c.Call.Return(ruleProposal, err)
+	return _c
+}
+
+func (_c *MockRuleProposalService_RejectProposal_Call) RunAndReturn(run func(ctx context.Context, proposalId int, userId int, reason string) (*models.RuleProposal, error)) *MockRuleProposalService_RejectProposal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRulesService creates a new instance of MockRulesService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRulesService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRulesService {
+	mock := &MockRulesService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockRulesService is an autogenerated mock type for the RulesService type
+type MockRulesService struct {
+	mock.Mock
+}
+
+type MockRulesService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRulesService) EXPECT() *MockRulesService_Expecter {
+	return &MockRulesService_Expecter{mock: &_m.Mock}
+}
+
+// AcceptRule provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) AcceptRule(ctx context.Context, ruleId int, userId int, ifMatch string, ip string) (*models.RuleAcceptance, error) {
+	ret := _mock.Called(ctx, ruleId, userId, ifMatch, ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcceptRule")
+	}
+
+	var r0 *models.RuleAcceptance
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string, string) (*models.RuleAcceptance, error)); ok {
+		return returnFunc(ctx, ruleId, userId, ifMatch, ip)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string, string) *models.RuleAcceptance); ok {
+		r0 = returnFunc(ctx, ruleId, userId, ifMatch, ip)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleAcceptance)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, string, string) error); ok {
+		r1 = returnFunc(ctx, ruleId, userId, ifMatch, ip)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRulesService_AcceptRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AcceptRule'
+type MockRulesService_AcceptRule_Call struct {
+	*mock.Call
+}
+
+// AcceptRule is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+//   - userId
+//   - ifMatch
+//   - ip
+func (_e *MockRulesService_Expecter) AcceptRule(ctx interface{}, ruleId interface{}, userId interface{}, ifMatch interface{}, ip interface{}) *MockRulesService_AcceptRule_Call {
+	return &MockRulesService_AcceptRule_Call{Call: _e.mock.On("AcceptRule", ctx, ruleId, userId, ifMatch, ip)}
+}
+
+func (_c *MockRulesService_AcceptRule_Call) Run(run func(ctx context.Context, ruleId int, userId int, ifMatch string, ip string)) *MockRulesService_AcceptRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_AcceptRule_Call) Return(ruleAcceptance *models.RuleAcceptance, err error) *MockRulesService_AcceptRule_Call {
+	_c.Call.Return(ruleAcceptance, err)
+	return _c
+}
+
+func (_c *MockRulesService_AcceptRule_Call) RunAndReturn(run func(ctx context.Context, ruleId int, userId int, ifMatch string, ip string) (*models.RuleAcceptance, error)) *MockRulesService_AcceptRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ActivateDueRules provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) ActivateDueRules(ctx context.Context) ([]models.Rule, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ActivateDueRules")
+	}
+
+	var r0 []models.Rule
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.Rule, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.Rule); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Rule)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRulesService_ActivateDueRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ActivateDueRules'
+type MockRulesService_ActivateDueRules_Call struct {
+	*mock.Call
+}
+
+// ActivateDueRules is a helper method to define mock.On call
+//   - ctx
+func (_e *MockRulesService_Expecter) ActivateDueRules(ctx interface{}) *MockRulesService_ActivateDueRules_Call {
+	return &MockRulesService_ActivateDueRules_Call{Call: _e.mock.On("ActivateDueRules", ctx)}
+}
+
+func (_c *MockRulesService_ActivateDueRules_Call) Run(run func(ctx context.Context)) *MockRulesService_ActivateDueRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_ActivateDueRules_Call) Return(rules []models.Rule, err error) *MockRulesService_ActivateDueRules_Call {
+	_c.Call.Return(rules, err)
+	return _c
+}
+
+func (_c *MockRulesService_ActivateDueRules_Call) RunAndReturn(run func(ctx context.Context) ([]models.Rule, error)) *MockRulesService_ActivateDueRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateRule provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) CreateRule(ctx context.Context, rule models.Rule, userId int) error {
+	ret := _mock.Called(ctx, rule, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRule")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Rule, int) error); ok {
+		r0 = returnFunc(ctx, rule, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRulesService_CreateRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRule'
+type MockRulesService_CreateRule_Call struct {
+	*mock.Call
+}
+
+// CreateRule is a helper method to define mock.On call
+//   - ctx
+//   - rule
+//   - userId
+func (_e *MockRulesService_Expecter) CreateRule(ctx interface{}, rule interface{}, userId interface{}) *MockRulesService_CreateRule_Call {
+	return &MockRulesService_CreateRule_Call{Call: _e.mock.On("CreateRule", ctx, rule, userId)}
+}
+
+func (_c *MockRulesService_CreateRule_Call) Run(run func(ctx context.Context, rule models.Rule, userId int)) *MockRulesService_CreateRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Rule), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_CreateRule_Call) Return(err error) *MockRulesService_CreateRule_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRulesService_CreateRule_Call) RunAndReturn(run func(ctx context.Context, rule models.Rule, userId int) error) *MockRulesService_CreateRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteRule provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) DeleteRule(ctx context.Context, ruleId int, userId int, ifMatch string) error {
+	ret := _mock.Called(ctx, ruleId, userId, ifMatch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRule")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) error); ok {
+		r0 = returnFunc(ctx, ruleId, userId, ifMatch)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRulesService_DeleteRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRule'
+type MockRulesService_DeleteRule_Call struct {
+	*mock.Call
+}
+
+// DeleteRule is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+//   - userId
+//   - ifMatch
+func (_e *MockRulesService_Expecter) DeleteRule(ctx interface{}, ruleId interface{}, userId interface{}, ifMatch interface{}) *MockRulesService_DeleteRule_Call {
+	return &MockRulesService_DeleteRule_Call{Call: _e.mock.On("DeleteRule", ctx, ruleId, userId, ifMatch)}
+}
+
+func (_c *MockRulesService_DeleteRule_Call) Run(run func(ctx context.Context, ruleId int, userId int, ifMatch string)) *MockRulesService_DeleteRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_DeleteRule_Call) Return(err error) *MockRulesService_DeleteRule_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRulesService_DeleteRule_Call) RunAndReturn(run func(ctx context.Context, ruleId int, userId int, ifMatch string) error) *MockRulesService_DeleteRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DiffRuleVersions provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) DiffRuleVersions(ctx context.Context, ruleId int, version int, against int) (*models.RuleDiff, error) {
+	ret := _mock.Called(ctx, ruleId, version, against)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DiffRuleVersions")
+	}
+
+	var r0 *models.RuleDiff
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.RuleDiff, error)); ok {
+		return returnFunc(ctx, ruleId, version, against)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) *models.RuleDiff); ok {
+		r0 = returnFunc(ctx, ruleId, version, against)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RuleDiff)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = returnFunc(ctx, ruleId, version, against)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRulesService_DiffRuleVersions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DiffRuleVersions'
+type MockRulesService_DiffRuleVersions_Call struct {
+	*mock.Call
+}
+
+// DiffRuleVersions is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+//   - version
+//   - against
+func (_e *MockRulesService_Expecter) DiffRuleVersions(ctx interface{}, ruleId interface{}, version interface{}, against interface{}) *MockRulesService_DiffRuleVersions_Call {
+	return &MockRulesService_DiffRuleVersions_Call{Call: _e.mock.On("DiffRuleVersions", ctx, ruleId, version, against)}
+}
+
+func (_c *MockRulesService_DiffRuleVersions_Call) Run(run func(ctx context.Context, ruleId int, version int, against int)) *MockRulesService_DiffRuleVersions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_DiffRuleVersions_Call) Return(ruleDiff *models.RuleDiff, err error) *MockRulesService_DiffRuleVersions_Call {
+	_c.Call.Return(ruleDiff, err)
+	return _c
+}
+
+func (_c *MockRulesService_DiffRuleVersions_Call) RunAndReturn(run func(ctx context.Context, ruleId int, version int, against int) (*models.RuleDiff, error)) *MockRulesService_DiffRuleVersions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EvaluateCondition provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) EvaluateCondition(ctx context.Context, condition string, vars map[string]any) (bool, error) {
+	ret := _mock.Called(ctx, condition, vars)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EvaluateCondition")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, map[string]any) (bool, error)); ok {
+		return returnFunc(ctx, condition, vars)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, map[string]any) bool); ok {
+		r0 = returnFunc(ctx, condition, vars)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, map[string]any) error); ok {
+		r1 = returnFunc(ctx, condition, vars)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRulesService_EvaluateCondition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EvaluateCondition'
+type MockRulesService_EvaluateCondition_Call struct {
+	*mock.Call
+}
+
+// EvaluateCondition is a helper method to define mock.On call
+//   - ctx
+//   - condition
+//   - vars
+func (_e *MockRulesService_Expecter) EvaluateCondition(ctx interface{}, condition interface{}, vars interface{}) *MockRulesService_EvaluateCondition_Call {
+	return &MockRulesService_EvaluateCondition_Call{Call: _e.mock.On("EvaluateCondition", ctx, condition, vars)}
+}
+
+func (_c *MockRulesService_EvaluateCondition_Call) Run(run func(ctx context.Context, condition string, vars map[string]any)) *MockRulesService_EvaluateCondition_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(map[string]any))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_EvaluateCondition_Call) Return(b bool, err error) *MockRulesService_EvaluateCondition_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockRulesService_EvaluateCondition_Call) RunAndReturn(run func(ctx context.Context, condition string, vars map[string]any) (bool, error)) *MockRulesService_EvaluateCondition_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAcceptanceReport provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) GetAcceptanceReport(ctx context.Context) (models.RuleAcceptanceReport, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAcceptanceReport")
+	}
+
+	var r0 models.RuleAcceptanceReport
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (models.RuleAcceptanceReport, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) models.RuleAcceptanceReport); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(models.RuleAcceptanceReport)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRulesService_GetAcceptanceReport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAcceptanceReport'
+type MockRulesService_GetAcceptanceReport_Call struct {
+	*mock.Call
+}
+
+// GetAcceptanceReport is a helper method to define mock.On call
+//   - ctx
+func (_e *MockRulesService_Expecter) GetAcceptanceReport(ctx interface{}) *MockRulesService_GetAcceptanceReport_Call {
+	return &MockRulesService_GetAcceptanceReport_Call{Call: _e.mock.On("GetAcceptanceReport", ctx)}
+}
+
+func (_c *MockRulesService_GetAcceptanceReport_Call) Run(run func(ctx context.Context)) *MockRulesService_GetAcceptanceReport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_GetAcceptanceReport_Call) Return(ruleAcceptanceReport models.RuleAcceptanceReport, err error) *MockRulesService_GetAcceptanceReport_Call {
+	_c.Call.Return(ruleAcceptanceReport, err)
+	return _c
+}
+
+func (_c *MockRulesService_GetAcceptanceReport_Call) RunAndReturn(run func(ctx context.Context) (models.RuleAcceptanceReport, error)) *MockRulesService_GetAcceptanceReport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetApplicableRules provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) GetApplicableRules(ctx context.Context, user models.User) (models.ApplicableRules, error) {
+	ret := _mock.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetApplicableRules")
+	}
+
+	var r0 models.ApplicableRules
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.User) (models.ApplicableRules, error)); ok {
+		return returnFunc(ctx, user)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.User) models.ApplicableRules); ok {
+		r0 = returnFunc(ctx, user)
+	} else {
+		r0 = ret.Get(0).(models.ApplicableRules)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.User) error); ok {
+		r1 = returnFunc(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRulesService_GetApplicableRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetApplicableRules'
+type MockRulesService_GetApplicableRules_Call struct {
+	*mock.Call
+}
+
+// GetApplicableRules is a helper method to define mock.On call
+//   - ctx
+//   - user
+func (_e *MockRulesService_Expecter) GetApplicableRules(ctx interface{}, user interface{}) *MockRulesService_GetApplicableRules_Call {
+	return &MockRulesService_GetApplicableRules_Call{Call: _e.mock.On("GetApplicableRules", ctx, user)}
+}
+
+func (_c *MockRulesService_GetApplicableRules_Call) Run(run func(ctx context.Context, user models.User)) *MockRulesService_GetApplicableRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.User))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_GetApplicableRules_Call) Return(applicableRules models.ApplicableRules, err error) *MockRulesService_GetApplicableRules_Call {
+	_c.Call.Return(applicableRules, err)
+	return _c
+}
+
+func (_c *MockRulesService_GetApplicableRules_Call) RunAndReturn(run func(ctx context.Context, user models.User) (models.ApplicableRules, error)) *MockRulesService_GetApplicableRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAudits provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) GetAudits(ctx context.Context, request models.RuleAuditListRequest) ([]models.AuditData, models.Pagination, error) {
+	ret := _mock.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAudits")
+	}
+
+	var r0 []models.AuditData
+	var r1 models.Pagination
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleAuditListRequest) ([]models.AuditData, models.Pagination, error)); ok {
+		return returnFunc(ctx, request)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleAuditListRequest) []models.AuditData); ok {
+		r0 = returnFunc(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AuditData)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.RuleAuditListRequest) models.Pagination); ok {
+		r1 = returnFunc(ctx, request)
+	} else {
+		r1 = ret.Get(1).(models.Pagination)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, models.RuleAuditListRequest) error); ok {
+		r2 = returnFunc(ctx, request)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockRulesService_GetAudits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAudits'
+type MockRulesService_GetAudits_Call struct {
+	*mock.Call
+}
+
+// GetAudits is a helper method to define mock.On call
+//   - ctx
+//   - request
+func (_e *MockRulesService_Expecter) GetAudits(ctx interface{}, request interface{}) *MockRulesService_GetAudits_Call {
+	return &MockRulesService_GetAudits_Call{Call: _e.mock.On("GetAudits", ctx, request)}
+}
+
+func (_c *MockRulesService_GetAudits_Call) Run(run func(ctx context.Context, request models.RuleAuditListRequest)) *MockRulesService_GetAudits_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.RuleAuditListRequest))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_GetAudits_Call) Return(auditDatas []models.AuditData, pagination models.Pagination, err error) *MockRulesService_GetAudits_Call {
+	_c.Call.Return(auditDatas, pagination, err)
+	return _c
+}
+
+func (_c *MockRulesService_GetAudits_Call) RunAndReturn(run func(ctx context.Context, request models.RuleAuditListRequest) ([]models.AuditData, models.Pagination, error)) *MockRulesService_GetAudits_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPendingRules provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) GetPendingRules(ctx context.Context, userId int) ([]models.PendingRule, error) {
+	ret := _mock.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingRules")
+	}
+
+	var r0 []models.PendingRule
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]models.PendingRule, error)); ok {
+		return returnFunc(ctx, userId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []models.PendingRule); ok {
+		r0 = returnFunc(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PendingRule)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRulesService_GetPendingRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPendingRules'
+type MockRulesService_GetPendingRules_Call struct {
+	*mock.Call
+}
+
+// GetPendingRules is a helper method to define mock.On call
+//   - ctx
+//   - userId
+func (_e *MockRulesService_Expecter) GetPendingRules(ctx interface{}, userId interface{}) *MockRulesService_GetPendingRules_Call {
+	return &MockRulesService_GetPendingRules_Call{Call: _e.mock.On("GetPendingRules", ctx, userId)}
+}
+
+func (_c *MockRulesService_GetPendingRules_Call) Run(run func(ctx context.Context, userId int)) *MockRulesService_GetPendingRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_GetPendingRules_Call) Return(pendingRules []models.PendingRule, err error) *MockRulesService_GetPendingRules_Call {
+	_c.Call.Return(pendingRules, err)
+	return _c
+}
+
+func (_c *MockRulesService_GetPendingRules_Call) RunAndReturn(run func(ctx context.Context, userId int) ([]models.PendingRule, error)) *MockRulesService_GetPendingRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRule provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) GetRule(ctx context.Context, ruleId int) (*models.Rule, error) {
+	ret := _mock.Called(ctx, ruleId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRule")
+	}
+
+	var r0 *models.Rule
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.Rule, error)); ok {
+		return returnFunc(ctx, ruleId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.Rule); ok {
+		r0 = returnFunc(ctx, ruleId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Rule)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, ruleId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRulesService_GetRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRule'
+type MockRulesService_GetRule_Call struct {
+	*mock.Call
+}
+
+// GetRule is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+func (_e *MockRulesService_Expecter) GetRule(ctx interface{}, ruleId interface{}) *MockRulesService_GetRule_Call {
+	return &MockRulesService_GetRule_Call{Call: _e.mock.On("GetRule", ctx, ruleId)}
+}
+
+func (_c *MockRulesService_GetRule_Call) Run(run func(ctx context.Context, ruleId int)) *MockRulesService_GetRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_GetRule_Call) Return(rule *models.Rule, err error) *MockRulesService_GetRule_Call {
+	_c.Call.Return(rule, err)
+	return _c
+}
+
+func (_c *MockRulesService_GetRule_Call) RunAndReturn(run func(ctx context.Context, ruleId int) (*models.Rule, error)) *MockRulesService_GetRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRuleVersions provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) GetRuleVersions(ctx context.Context, ruleId int) ([]models.RuleVersion, error) {
+	ret := _mock.Called(ctx, ruleId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRuleVersions")
+	}
+
+	var r0 []models.RuleVersion
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]models.RuleVersion, error)); ok {
+		return returnFunc(ctx, ruleId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []models.RuleVersion); ok {
+		r0 = returnFunc(ctx, ruleId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.RuleVersion)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, ruleId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRulesService_GetRuleVersions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRuleVersions'
+type MockRulesService_GetRuleVersions_Call struct {
+	*mock.Call
+}
+
+// GetRuleVersions is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+func (_e *MockRulesService_Expecter) GetRuleVersions(ctx interface{}, ruleId interface{}) *MockRulesService_GetRuleVersions_Call {
+	return &MockRulesService_GetRuleVersions_Call{Call: _e.mock.On("GetRuleVersions", ctx, ruleId)}
+}
+
+func (_c *MockRulesService_GetRuleVersions_Call) Run(run func(ctx context.Context, ruleId int)) *MockRulesService_GetRuleVersions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_GetRuleVersions_Call) Return(ruleVersions []models.RuleVersion, err error) *MockRulesService_GetRuleVersions_Call {
+	_c.Call.Return(ruleVersions, err)
+	return _c
+}
+
+func (_c *MockRulesService_GetRuleVersions_Call) RunAndReturn(run func(ctx context.Context, ruleId int) ([]models.RuleVersion, error)) *MockRulesService_GetRuleVersions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRules provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) GetRules(ctx context.Context, filter models.RuleListRequest) ([]models.Rule, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRules")
+	}
+
+	var r0 []models.Rule
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleListRequest) ([]models.Rule, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.RuleListRequest) []models.Rule); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Rule)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.RuleListRequest) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRulesService_GetRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRules'
+type MockRulesService_GetRules_Call struct {
+	*mock.Call
+}
+
+// GetRules is a helper method to define mock.On call
+//   - ctx
+//   - filter
+func (_e *MockRulesService_Expecter) GetRules(ctx interface{}, filter interface{}) *MockRulesService_GetRules_Call {
+	return &MockRulesService_GetRules_Call{Call: _e.mock.On("GetRules", ctx, filter)}
+}
+
+func (_c *MockRulesService_GetRules_Call) Run(run func(ctx context.Context, filter models.RuleListRequest)) *MockRulesService_GetRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.RuleListRequest))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_GetRules_Call) Return(rules []models.Rule, err error) *MockRulesService_GetRules_Call {
+	_c.Call.Return(rules, err)
+	return _c
+}
+
+func (_c *MockRulesService_GetRules_Call) RunAndReturn(run func(ctx context.Context, filter models.RuleListRequest) ([]models.Rule, error)) *MockRulesService_GetRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ModifyRule provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) ModifyRule(ctx context.Context, ruleId int, modification models.RuleModify, userId int, ifMatch string) error {
+	ret := _mock.Called(ctx, ruleId, modification, userId, ifMatch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ModifyRule")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.RuleModify, int, string) error); ok {
+		r0 = returnFunc(ctx, ruleId, modification, userId, ifMatch)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRulesService_ModifyRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ModifyRule'
+type MockRulesService_ModifyRule_Call struct {
+	*mock.Call
+}
+
+// ModifyRule is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+//   - modification
+//   - userId
+//   - ifMatch
+func (_e *MockRulesService_Expecter) ModifyRule(ctx interface{}, ruleId interface{}, modification interface{}, userId interface{}, ifMatch interface{}) *MockRulesService_ModifyRule_Call {
+	return &MockRulesService_ModifyRule_Call{Call: _e.mock.On("ModifyRule", ctx, ruleId, modification, userId, ifMatch)}
+}
+
+func (_c *MockRulesService_ModifyRule_Call) Run(run func(ctx context.Context, ruleId int, modification models.RuleModify, userId int, ifMatch string)) *MockRulesService_ModifyRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.RuleModify), args[3].(int), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_ModifyRule_Call) Return(err error) *MockRulesService_ModifyRule_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRulesService_ModifyRule_Call) RunAndReturn(run func(ctx context.Context, ruleId int, modification models.RuleModify, userId int, ifMatch string) error) *MockRulesService_ModifyRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RollbackRule provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) RollbackRule(ctx context.Context, ruleId int, version int, userId int, ifMatch string) error {
+	ret := _mock.Called(ctx, ruleId, version, userId, ifMatch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RollbackRule")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int, string) error); ok {
+		r0 = returnFunc(ctx, ruleId, version, userId, ifMatch)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRulesService_RollbackRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RollbackRule'
+type MockRulesService_RollbackRule_Call struct {
+	*mock.Call
+}
+
+// RollbackRule is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+//   - version
+//   - userId
+//   - ifMatch
+func (_e *MockRulesService_Expecter) RollbackRule(ctx interface{}, ruleId interface{}, version interface{}, userId interface{}, ifMatch interface{}) *MockRulesService_RollbackRule_Call {
+	return &MockRulesService_RollbackRule_Call{Call: _e.mock.On("RollbackRule", ctx, ruleId, version, userId, ifMatch)}
+}
+
+func (_c *MockRulesService_RollbackRule_Call) Run(run func(ctx context.Context, ruleId int, version int, userId int, ifMatch string)) *MockRulesService_RollbackRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_RollbackRule_Call) Return(err error) *MockRulesService_RollbackRule_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRulesService_RollbackRule_Call) RunAndReturn(run func(ctx context.Context, ruleId int, version int, userId int, ifMatch string) error) *MockRulesService_RollbackRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RunScheduler provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) RunScheduler(ctx context.Context, interval time.Duration) {
+	_mock.Called(ctx, interval)
+	return
+}
+
+// MockRulesService_RunScheduler_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunScheduler'
+type MockRulesService_RunScheduler_Call struct {
+	*mock.Call
+}
+
+// RunScheduler is a helper method to define mock.On call
+//   - ctx
+//   - interval
+func (_e *MockRulesService_Expecter) RunScheduler(ctx interface{}, interval interface{}) *MockRulesService_RunScheduler_Call {
+	return &MockRulesService_RunScheduler_Call{Call: _e.mock.On("RunScheduler", ctx, interval)}
+}
+
+func (_c *MockRulesService_RunScheduler_Call) Run(run func(ctx context.Context, interval time.Duration)) *MockRulesService_RunScheduler_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_RunScheduler_Call) Return() *MockRulesService_RunScheduler_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockRulesService_RunScheduler_Call) RunAndReturn(run func(ctx context.Context, interval time.Duration)) *MockRulesService_RunScheduler_Call {
+	_c.Run(run)
+	return _c
+}
+
+// SetRuleStatus provides a mock function for the type MockRulesService
+func (_mock *MockRulesService) SetRuleStatus(ctx context.Context, ruleId int, status models.RuleStatus, userId int, ifMatch string) error {
+	ret := _mock.Called(ctx, ruleId, status, userId, ifMatch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRuleStatus")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.RuleStatus, int, string) error); ok {
+		r0 = returnFunc(ctx, ruleId, status, userId, ifMatch)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRulesService_SetRuleStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetRuleStatus'
+type MockRulesService_SetRuleStatus_Call struct {
+	*mock.Call
+}
+
+// SetRuleStatus is a helper method to define mock.On call
+//   - ctx
+//   - ruleId
+//   - status
+//   - userId
+//   - ifMatch
+func (_e *MockRulesService_Expecter) SetRuleStatus(ctx interface{}, ruleId interface{}, status interface{}, userId interface{}, ifMatch interface{}) *MockRulesService_SetRuleStatus_Call {
+	return &MockRulesService_SetRuleStatus_Call{Call: _e.mock.On("SetRuleStatus", ctx, ruleId, status, userId, ifMatch)}
+}
+
+func (_c *MockRulesService_SetRuleStatus_Call) Run(run func(ctx context.Context, ruleId int, status models.RuleStatus, userId int, ifMatch string)) *MockRulesService_SetRuleStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.RuleStatus), args[3].(int), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockRulesService_SetRuleStatus_Call) Return(err error) *MockRulesService_SetRuleStatus_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRulesService_SetRuleStatus_Call) RunAndReturn(run func(ctx context.Context, ruleId int, status models.RuleStatus, userId int, ifMatch string) error) *MockRulesService_SetRuleStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockStatsService creates a new instance of MockStatsService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockStatsService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockStatsService {
+	mock := &MockStatsService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockStatsService is an autogenerated mock type for the StatsService type
+type MockStatsService struct {
+	mock.Mock
+}
+
+type MockStatsService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockStatsService) EXPECT() *MockStatsService_Expecter {
+	return &MockStatsService_Expecter{mock: &_m.Mock}
+}
+
+// GetStats provides a mock function for the type MockStatsService
+func (_mock *MockStatsService) GetStats(ctx context.Context, request models.StatsRequest) (*models.AdminStats, error) {
+	ret := _mock.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStats")
+	}
+
+	var r0 *models.AdminStats
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.StatsRequest) (*models.AdminStats, error)); ok {
+		return returnFunc(ctx, request)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.StatsRequest) *models.AdminStats); ok {
+		r0 = returnFunc(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AdminStats)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.StatsRequest) error); ok {
+		r1 = returnFunc(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStatsService_GetStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStats'
+type MockStatsService_GetStats_Call struct {
+	*mock.Call
+}
+
+// GetStats is a helper method to define mock.On call
+//   - ctx
+//   - request
+func (_e *MockStatsService_Expecter) GetStats(ctx interface{}, request interface{}) *MockStatsService_GetStats_Call {
+	return &MockStatsService_GetStats_Call{Call: _e.mock.On("GetStats", ctx, request)}
+}
+
+func (_c *MockStatsService_GetStats_Call) Run(run func(ctx context.Context, request models.StatsRequest)) *MockStatsService_GetStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.StatsRequest))
+	})
+	return _c
+}
+
+func (_c *MockStatsService_GetStats_Call) Return(adminStats *models.AdminStats, err error) *MockStatsService_GetStats_Call {
+	_c.Call.Return(adminStats, err)
+	return _c
+}
+
+func (_c *MockStatsService_GetStats_Call) RunAndReturn(run func(ctx context.Context, request models.StatsRequest) (*models.AdminStats, error)) *MockStatsService_GetStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockStreamService creates a new instance of MockStreamService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockStreamService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockStreamService {
+	mock := &MockStreamService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockStreamService is an autogenerated mock type for the StreamService type
+type MockStreamService struct {
+	mock.Mock
+}
+
+type MockStreamService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockStreamService) EXPECT() *MockStreamService_Expecter {
+	return &MockStreamService_Expecter{mock: &_m.Mock}
+}
+
+// Missed provides a mock function for the type MockStreamService
+func (_mock *MockStreamService) Missed(ctx context.Context, userId int, lastEventId int) ([]models.UserEvent, error) {
+	ret := _mock.Called(ctx, userId, lastEventId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Missed")
+	}
+
+	var r0 []models.UserEvent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]models.UserEvent, error)); ok {
+		return returnFunc(ctx, userId, lastEventId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []models.UserEvent); ok {
+		r0 = returnFunc(ctx, userId, lastEventId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UserEvent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = returnFunc(ctx, userId, lastEventId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStreamService_Missed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Missed'
+type MockStreamService_Missed_Call struct {
+	*mock.Call
+}
+
+// Missed is a helper method to define mock.On call
+//   - ctx
+//   - userId
+//   - lastEventId
+func (_e *MockStreamService_Expecter) Missed(ctx interface{}, userId interface{}, lastEventId interface{}) *MockStreamService_Missed_Call {
+	return &MockStreamService_Missed_Call{Call: _e.mock.On("Missed", ctx, userId, lastEventId)}
+}
+
+func (_c *MockStreamService_Missed_Call) Run(run func(ctx context.Context, userId int, lastEventId int)) *MockStreamService_Missed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockStreamService_Missed_Call) Return(userEvents []models.UserEvent, err error) *MockStreamService_Missed_Call {
+	_c.Call.Return(userEvents, err)
+	return _c
+}
+
+func (_c *MockStreamService_Missed_Call) RunAndReturn(run func(ctx context.Context, userId int, lastEventId int) ([]models.UserEvent, error)) *MockStreamService_Missed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Run provides a mock function for the type MockStreamService
+func (_mock *MockStreamService) Run(ctx context.Context) {
+	_mock.Called(ctx)
+	return
+}
+
+// MockStreamService_Run_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Run'
+type MockStreamService_Run_Call struct {
+	*mock.Call
+}
+
+// Run is a helper method to define mock.On call
+//   - ctx
+func (_e *MockStreamService_Expecter) Run(ctx interface{}) *MockStreamService_Run_Call {
+	return &MockStreamService_Run_Call{Call: _e.mock.On("Run", ctx)}
+}
+
+func (_c *MockStreamService_Run_Call) Run(run func(ctx context.Context)) *MockStreamService_Run_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockStreamService_Run_Call) Return() *MockStreamService_Run_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockStreamService_Run_Call) RunAndReturn(run func(ctx context.Context)) *MockStreamService_Run_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Subscribe provides a mock function for the type MockStreamService
+func (_mock *MockStreamService) Subscribe(userId int) (<-chan models.UserEvent, func()) {
+	ret := _mock.Called(userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 <-chan models.UserEvent
+	var r1 func()
+	if returnFunc, ok := ret.Get(0).(func(int) (<-chan models.UserEvent, func())); ok {
+		return returnFunc(userId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(int) <-chan models.UserEvent); ok {
+		r0 = returnFunc(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan models.UserEvent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(int) func()); ok {
+		r1 = returnFunc(userId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func())
+		}
+	}
+	return r0, r1
+}
+
+// MockStreamService_Subscribe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Subscribe'
+type MockStreamService_Subscribe_Call struct {
+	*mock.Call
+}
+
+// Subscribe is a helper method to define mock.On call
+//   - userId
+func (_e *MockStreamService_Expecter) Subscribe(userId interface{}) *MockStreamService_Subscribe_Call {
+	return &MockStreamService_Subscribe_Call{Call: _e.mock.On("Subscribe", userId)}
+}
+
+func (_c *MockStreamService_Subscribe_Call) Run(run func(userId int)) *MockStreamService_Subscribe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int))
+	})
+	return _c
+}
+
+func (_c *MockStreamService_Subscribe_Call) Return(userEventCh <-chan models.UserEvent, fn func()) *MockStreamService_Subscribe_Call {
+	_c.Call.Return(userEventCh, fn)
+	return _c
+}
+
+func (_c *MockStreamService_Subscribe_Call) RunAndReturn(run func(userId int) (<-chan models.UserEvent, func())) *MockStreamService_Subscribe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockUserBulkService creates a new instance of MockUserBulkService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserBulkService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserBulkService {
+	mock := &MockUserBulkService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockUserBulkService is an autogenerated mock type for the UserBulkService type
+type MockUserBulkService struct {
+	mock.Mock
+}
+
+type MockUserBulkService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUserBulkService) EXPECT() *MockUserBulkService_Expecter {
+	return &MockUserBulkService_Expecter{mock: &_m.Mock}
+}
+
+// RunBulkAction provides a mock function for the type MockUserBulkService
+func (_mock *MockUserBulkService) RunBulkAction(ctx context.Context, actorId int, request models.BulkUserActionRequest) (*models.BulkUserActionReport, error) {
+	ret := _mock.Called(ctx, actorId, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunBulkAction")
+	}
+
+	var r0 *models.BulkUserActionReport
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.BulkUserActionRequest) (*models.BulkUserActionReport, error)); ok {
+		return returnFunc(ctx, actorId, request)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.BulkUserActionRequest) *models.BulkUserActionReport); ok {
+		r0 = returnFunc(ctx, actorId, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.BulkUserActionReport)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, models.BulkUserActionRequest) error); ok {
+		r1 = returnFunc(ctx, actorId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserBulkService_RunBulkAction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunBulkAction'
+type MockUserBulkService_RunBulkAction_Call struct {
+	*mock.Call
+}
+
+// RunBulkAction is a helper method to define mock.On call
+//   - ctx
+//   - actorId
+//   - request
+func (_e *MockUserBulkService_Expecter) RunBulkAction(ctx interface{}, actorId interface{}, request interface{}) *MockUserBulkService_RunBulkAction_Call {
+	return &MockUserBulkService_RunBulkAction_Call{Call: _e.mock.On("RunBulkAction", ctx, actorId, request)}
+}
+
+func (_c *MockUserBulkService_RunBulkAction_Call) Run(run func(ctx context.Context, actorId int, request models.BulkUserActionRequest)) *MockUserBulkService_RunBulkAction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.BulkUserActionRequest))
+	})
+	return _c
+}
+
+func (_c *MockUserBulkService_RunBulkAction_Call) Return(bulkUserActionReport *models.BulkUserActionReport, err error) *MockUserBulkService_RunBulkAction_Call {
+	_c.Call.Return(bulkUserActionReport, err)
+	return _c
+}
+
+func (_c *MockUserBulkService_RunBulkAction_Call) RunAndReturn(run func(ctx context.Context, actorId int, request models.BulkUserActionRequest) (*models.BulkUserActionReport, error)) *MockUserBulkService_RunBulkAction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockUserImportService creates a new instance of MockUserImportService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserImportService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserImportService {
+	mock := &MockUserImportService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockUserImportService is an autogenerated mock type for the UserImportService type
+type MockUserImportService struct {
+	mock.Mock
+}
+
+type MockUserImportService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUserImportService) EXPECT() *MockUserImportService_Expecter {
+	return &MockUserImportService_Expecter{mock: &_m.Mock}
+}
+
+// GetImportJob provides a mock function for the type MockUserImportService
+func (_mock *MockUserImportService) GetImportJob(ctx context.Context, id int) (*models.UserImportJob, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetImportJob")
+	}
+
+	var r0 *models.UserImportJob
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.UserImportJob, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.UserImportJob); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserImportJob)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserImportService_GetImportJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetImportJob'
+type MockUserImportService_GetImportJob_Call struct {
+	*mock.Call
+}
+
+// GetImportJob is a helper method to define mock.On call
+//   - ctx
+//   - id
+func (_e *MockUserImportService_Expecter) GetImportJob(ctx interface{}, id interface{}) *MockUserImportService_GetImportJob_Call {
+	return &MockUserImportService_GetImportJob_Call{Call: _e.mock.On("GetImportJob", ctx, id)}
+}
+
+func (_c *MockUserImportService_GetImportJob_Call) Run(run func(ctx context.Context, id int)) *MockUserImportService_GetImportJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserImportService_GetImportJob_Call) Return(userImportJob *models.UserImportJob, err error) *MockUserImportService_GetImportJob_Call {
+	_c.Call.Return(userImportJob, err)
+	return _c
+}
+
+func (_c *MockUserImportService_GetImportJob_Call) RunAndReturn(run func(ctx context.Context, id int) (*models.UserImportJob, error)) *MockUserImportService_GetImportJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Import provides a mock function for the type MockUserImportService
+func (_mock *MockUserImportService) Import(ctx context.Context, rows []models.UserImportRow, opts models.UserImportOptions) (*models.UserImportReport, error) {
+	ret := _mock.Called(ctx, rows, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 *models.UserImportReport
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.UserImportRow, models.UserImportOptions) (*models.UserImportReport, error)); ok {
+		return returnFunc(ctx, rows, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.UserImportRow, models.UserImportOptions) *models.UserImportReport); ok {
+		r0 = returnFunc(ctx, rows, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserImportReport)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []models.UserImportRow, models.UserImportOptions) error); ok {
+		r1 = returnFunc(ctx, rows, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserImportService_Import_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Import'
+type MockUserImportService_Import_Call struct {
+	*mock.Call
+}
+
+// Import is a helper method to define mock.On call
+//   - ctx
+//   - rows
+//   - opts
+func (_e *MockUserImportService_Expecter) Import(ctx interface{}, rows interface{}, opts interface{}) *MockUserImportService_Import_Call {
+	return &MockUserImportService_Import_Call{Call: _e.mock.On("Import", ctx, rows, opts)}
+}
+
+func (_c *MockUserImportService_Import_Call) Run(run func(ctx context.Context, rows []models.UserImportRow, opts models.UserImportOptions)) *MockUserImportService_Import_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.UserImportRow), args[2].(models.UserImportOptions))
+	})
+	return _c
+}
+
+func (_c *MockUserImportService_Import_Call) Return(userImportReport *models.UserImportReport, err error) *MockUserImportService_Import_Call {
+	_c.Call.Return(userImportReport, err)
+	return _c
+}
+
+func (_c *MockUserImportService_Import_Call) RunAndReturn(run func(ctx context.Context, rows []models.UserImportRow, opts models.UserImportOptions) (*models.UserImportReport, error)) *MockUserImportService_Import_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RunRecovery provides a mock function for the type MockUserImportService
+func (_mock *MockUserImportService) RunRecovery(ctx context.Context) {
+	_mock.Called(ctx)
+	return
+}
+
+// MockUserImportService_RunRecovery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunRecovery'
+type MockUserImportService_RunRecovery_Call struct {
+	*mock.Call
+}
+
+// RunRecovery is a helper method to define mock.On call
+//   - ctx
+func (_e *MockUserImportService_Expecter) RunRecovery(ctx interface{}) *MockUserImportService_RunRecovery_Call {
+	return &MockUserImportService_RunRecovery_Call{Call: _e.mock.On("RunRecovery", ctx)}
+}
+
+func (_c *MockUserImportService_RunRecovery_Call) Run(run func(ctx context.Context)) *MockUserImportService_RunRecovery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockUserImportService_RunRecovery_Call) Return() *MockUserImportService_RunRecovery_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockUserImportService_RunRecovery_Call) RunAndReturn(run func(ctx context.Context)) *MockUserImportService_RunRecovery_Call {
+	_c.Run(run)
+	return _c
+}
+
+// StartImportJob provides a mock function for the type MockUserImportService
+func (_mock *MockUserImportService) StartImportJob(ctx context.Context, createdBy int, rows []models.UserImportRow, opts models.UserImportOptions) (int, error) {
+	ret := _mock.Called(ctx, createdBy, rows, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartImportJob")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, []models.UserImportRow, models.UserImportOptions) (int, error)); ok {
+		return returnFunc(ctx, createdBy, rows, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, []models.UserImportRow, models.UserImportOptions) int); ok {
+		r0 = returnFunc(ctx, createdBy, rows, opts)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, []models.UserImportRow, models.UserImportOptions) error); ok {
+		r1 = returnFunc(ctx, createdBy, rows, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserImportService_StartImportJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartImportJob'
+type MockUserImportService_StartImportJob_Call struct {
+	*mock.Call
+}
+
+// StartImportJob is a helper method to define mock.On call
+//   - ctx
+//   - createdBy
+//   - rows
+//   - opts
+func (_e *MockUserImportService_Expecter) StartImportJob(ctx interface{}, createdBy interface{}, rows interface{}, opts interface{}) *MockUserImportService_StartImportJob_Call {
+	return &MockUserImportService_StartImportJob_Call{Call: _e.mock.On("StartImportJob", ctx, createdBy, rows, opts)}
+}
+
+func (_c *MockUserImportService_StartImportJob_Call) Run(run func(ctx context.Context, createdBy int, rows []models.UserImportRow, opts models.UserImportOptions)) *MockUserImportService_StartImportJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].([]models.UserImportRow), args[3].(models.UserImportOptions))
+	})
+	return _c
+}
+
+func (_c *MockUserImportService_StartImportJob_Call) Return(n int, err error) *MockUserImportService_StartImportJob_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockUserImportService_StartImportJob_Call) RunAndReturn(run func(ctx context.Context, createdBy int, rows []models.UserImportRow, opts models.UserImportOptions) (int, error)) *MockUserImportService_StartImportJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockUserService creates a new instance of MockUserService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserService {
+	mock := &MockUserService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockUserService is an autogenerated mock type for the UserService type
+type MockUserService struct {
+	mock.Mock
+}
+
+type MockUserService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUserService) EXPECT() *MockUserService_Expecter {
+	return &MockUserService_Expecter{mock: &_m.Mock}
+}
+
+// AddNotificationToken provides a mock function for the type MockUserService
+func (_mock *MockUserService) AddNotificationToken(ctx context.Context, id int, text string) error {
+	ret := _mock.Called(ctx, id, text)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddNotificationToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, id, text)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -764,725 +3990,1341 @@ type MockUserService_AddNotificationToken_Call struct {
 	*mock.Call
 }
 
-// AddNotificationToken is a helper method to define mock.On call
+// AddNotificationToken is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - text
+func (_e *MockUserService_Expecter) AddNotificationToken(ctx interface{}, id interface{}, text interface{}) *MockUserService_AddNotificationToken_Call {
+	return &MockUserService_AddNotificationToken_Call{Call: _e.mock.On("AddNotificationToken", ctx, id, text)}
+}
+
+func (_c *MockUserService_AddNotificationToken_Call) Run(run func(ctx context.Context, id int, text string)) *MockUserService_AddNotificationToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserService_AddNotificationToken_Call) Return(err error) *MockUserService_AddNotificationToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_AddNotificationToken_Call) RunAndReturn(run func(ctx context.Context, id int, text string) error) *MockUserService_AddNotificationToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddNotificationType provides a mock function for the type MockUserService
+func (_mock *MockUserService) AddNotificationType(ctx context.Context, notificationType *models.NotificationType) error {
+	ret := _mock.Called(ctx, notificationType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddNotificationType")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.NotificationType) error); ok {
+		r0 = returnFunc(ctx, notificationType)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_AddNotificationType_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddNotificationType'
+type MockUserService_AddNotificationType_Call struct {
+	*mock.Call
+}
+
+// AddNotificationType is a helper method to define mock.On call
+//   - ctx
+//   - notificationType
+func (_e *MockUserService_Expecter) AddNotificationType(ctx interface{}, notificationType interface{}) *MockUserService_AddNotificationType_Call {
+	return &MockUserService_AddNotificationType_Call{Call: _e.mock.On("AddNotificationType", ctx, notificationType)}
+}
+
+func (_c *MockUserService_AddNotificationType_Call) Run(run func(ctx context.Context, notificationType *models.NotificationType)) *MockUserService_AddNotificationType_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.NotificationType))
+	})
+	return _c
+}
+
+func (_c *MockUserService_AddNotificationType_Call) Return(err error) *MockUserService_AddNotificationType_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_AddNotificationType_Call) RunAndReturn(run func(ctx context.Context, notificationType *models.NotificationType) error) *MockUserService_AddNotificationType_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// BlockUser provides a mock function for the type MockUserService
+func (_mock *MockUserService) BlockUser(ctx context.Context, id int, reason string, blockerId *int, blockedUntil *time.Time) error {
+	ret := _mock.Called(ctx, id, reason, blockerId, blockedUntil)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BlockUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, *int, *time.Time) error); ok {
+		r0 = returnFunc(ctx, id, reason, blockerId, blockedUntil)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_BlockUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BlockUser'
+type MockUserService_BlockUser_Call struct {
+	*mock.Call
+}
+
+// BlockUser is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - reason
+//   - blockerId
+//   - blockedUntil
+func (_e *MockUserService_Expecter) BlockUser(ctx interface{}, id interface{}, reason interface{}, blockerId interface{}, blockedUntil interface{}) *MockUserService_BlockUser_Call {
+	return &MockUserService_BlockUser_Call{Call: _e.mock.On("BlockUser", ctx, id, reason, blockerId, blockedUntil)}
+}
+
+func (_c *MockUserService_BlockUser_Call) Run(run func(ctx context.Context, id int, reason string, blockerId *int, blockedUntil *time.Time)) *MockUserService_BlockUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(*int), args[4].(*time.Time))
+	})
+	return _c
+}
+
+func (_c *MockUserService_BlockUser_Call) Return(err error) *MockUserService_BlockUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_BlockUser_Call) RunAndReturn(run func(ctx context.Context, id int, reason string, blockerId *int, blockedUntil *time.Time) error) *MockUserService_BlockUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CancelEmailChange provides a mock function for the type MockUserService
+func (_mock *MockUserService) CancelEmailChange(ctx context.Context, token string) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelEmailChange")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_CancelEmailChange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelEmailChange'
+type MockUserService_CancelEmailChange_Call struct {
+	*mock.Call
+}
+
+// CancelEmailChange is a helper method to define mock.On call
+//   - ctx
+//   - token
+func (_e *MockUserService_Expecter) CancelEmailChange(ctx interface{}, token interface{}) *MockUserService_CancelEmailChange_Call {
+	return &MockUserService_CancelEmailChange_Call{Call: _e.mock.On("CancelEmailChange", ctx, token)}
+}
+
+func (_c *MockUserService_CancelEmailChange_Call) Run(run func(ctx context.Context, token string)) *MockUserService_CancelEmailChange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserService_CancelEmailChange_Call) Return(err error) *MockUserService_CancelEmailChange_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_CancelEmailChange_Call) RunAndReturn(run func(ctx context.Context, token string) error) *MockUserService_CancelEmailChange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ConfirmEmailChange provides a mock function for the type MockUserService
+func (_mock *MockUserService) ConfirmEmailChange(ctx context.Context, id int, pin string) error {
+	ret := _mock.Called(ctx, id, pin)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmEmailChange")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, id, pin)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_ConfirmEmailChange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfirmEmailChange'
+type MockUserService_ConfirmEmailChange_Call struct {
+	*mock.Call
+}
+
+// ConfirmEmailChange is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - pin
+func (_e *MockUserService_Expecter) ConfirmEmailChange(ctx interface{}, id interface{}, pin interface{}) *MockUserService_ConfirmEmailChange_Call {
+	return &MockUserService_ConfirmEmailChange_Call{Call: _e.mock.On("ConfirmEmailChange", ctx, id, pin)}
+}
+
+func (_c *MockUserService_ConfirmEmailChange_Call) Run(run func(ctx context.Context, id int, pin string)) *MockUserService_ConfirmEmailChange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserService_ConfirmEmailChange_Call) Return(err error) *MockUserService_ConfirmEmailChange_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_ConfirmEmailChange_Call) RunAndReturn(run func(ctx context.Context, id int, pin string) error) *MockUserService_ConfirmEmailChange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateUser provides a mock function for the type MockUserService
+func (_mock *MockUserService) CreateUser(ctx context.Context, request models.CreateUserRequest) (int, error) {
+	ret := _mock.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUser")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.CreateUserRequest) (int, error)); ok {
+		return returnFunc(ctx, request)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.CreateUserRequest) int); ok {
+		r0 = returnFunc(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.CreateUserRequest) error); ok {
+		r1 = returnFunc(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
+type MockUserService_CreateUser_Call struct {
+	*mock.Call
+}
+
+// CreateUser is a helper method to define mock.On call
+//   - ctx
+//   - request
+func (_e *MockUserService_Expecter) CreateUser(ctx interface{}, request interface{}) *MockUserService_CreateUser_Call {
+	return &MockUserService_CreateUser_Call{Call: _e.mock.On("CreateUser", ctx, request)}
+}
+
+func (_c *MockUserService_CreateUser_Call) Run(run func(ctx context.Context, request models.CreateUserRequest)) *MockUserService_CreateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.CreateUserRequest))
+	})
+	return _c
+}
+
+func (_c *MockUserService_CreateUser_Call) Return(n int, err error) *MockUserService_CreateUser_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockUserService_CreateUser_Call) RunAndReturn(run func(ctx context.Context, request models.CreateUserRequest) (int, error)) *MockUserService_CreateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteUser provides a mock function for the type MockUserService
+func (_mock *MockUserService) DeleteUser(ctx context.Context, id int, ifMatch string) error {
+	ret := _mock.Called(ctx, id, ifMatch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, id, ifMatch)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_DeleteUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUser'
+type MockUserService_DeleteUser_Call struct {
+	*mock.Call
+}
+
+// DeleteUser is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - ifMatch
+func (_e *MockUserService_Expecter) DeleteUser(ctx interface{}, id interface{}, ifMatch interface{}) *MockUserService_DeleteUser_Call {
+	return &MockUserService_DeleteUser_Call{Call: _e.mock.On("DeleteUser", ctx, id, ifMatch)}
+}
+
+func (_c *MockUserService_DeleteUser_Call) Run(run func(ctx context.Context, id int, ifMatch string)) *MockUserService_DeleteUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserService_DeleteUser_Call) Return(err error) *MockUserService_DeleteUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_DeleteUser_Call) RunAndReturn(run func(ctx context.Context, id int, ifMatch string) error) *MockUserService_DeleteUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllUsers provides a mock function for the type MockUserService
+func (_mock *MockUserService) GetAllUsers(ctx context.Context, filter models.UserFilter) ([]models.User, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllUsers")
+	}
+
+	var r0 []models.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.UserFilter) ([]models.User, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.UserFilter) []models.User); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.UserFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_GetAllUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllUsers'
+type MockUserService_GetAllUsers_Call struct {
+	*mock.Call
+}
+
+// GetAllUsers is a helper method to define mock.On call
+//   - ctx
+//   - filter
+func (_e *MockUserService_Expecter) GetAllUsers(ctx interface{}, filter interface{}) *MockUserService_GetAllUsers_Call {
+	return &MockUserService_GetAllUsers_Call{Call: _e.mock.On("GetAllUsers", ctx, filter)}
+}
+
+func (_c *MockUserService_GetAllUsers_Call) Run(run func(ctx context.Context, filter models.UserFilter)) *MockUserService_GetAllUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.UserFilter))
+	})
+	return _c
+}
+
+func (_c *MockUserService_GetAllUsers_Call) Return(users []models.User, err error) *MockUserService_GetAllUsers_Call {
+	_c.Call.Return(users, err)
+	return _c
+}
+
+func (_c *MockUserService_GetAllUsers_Call) RunAndReturn(run func(ctx context.Context, filter models.UserFilter) ([]models.User, error)) *MockUserService_GetAllUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetNotificationChannels provides a mock function for the type MockUserService
+func (_mock *MockUserService) GetNotificationChannels(ctx context.Context, id int, notificationType string) (map[string]bool, error) {
+	ret := _mock.Called(ctx, id, notificationType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNotificationChannels")
+	}
+
+	var r0 map[string]bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) (map[string]bool, error)); ok {
+		return returnFunc(ctx, id, notificationType)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) map[string]bool); ok {
+		r0 = returnFunc(ctx, id, notificationType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]bool)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = returnFunc(ctx, id, notificationType)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_GetNotificationChannels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNotificationChannels'
+type MockUserService_GetNotificationChannels_Call struct {
+	*mock.Call
+}
+
+// GetNotificationChannels is a helper method to define mock.On call
 //   - ctx
 //   - id
-//   - text
-func (_e *MockUserService_Expecter) AddNotificationToken(ctx interface{}, id interface{}, text interface{}) *MockUserService_AddNotificationToken_Call {
-	return &MockUserService_AddNotificationToken_Call{Call: _e.mock.On("AddNotificationToken", ctx, id, text)}
+//   - notificationType
+func (_e *MockUserService_Expecter) GetNotificationChannels(ctx interface{}, id interface{}, notificationType interface{}) *MockUserService_GetNotificationChannels_Call {
+	return &MockUserService_GetNotificationChannels_Call{Call: _e.mock.On("GetNotificationChannels", ctx, id, notificationType)}
 }
 
-func (_c *MockUserService_AddNotificationToken_Call) Run(run func(ctx context.Context, id int, text string)) *MockUserService_AddNotificationToken_Call {
+func (_c *MockUserService_GetNotificationChannels_Call) Run(run func(ctx context.Context, id int, notificationType string)) *MockUserService_GetNotificationChannels_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(int), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockUserService_AddNotificationToken_Call) Return(err error) *MockUserService_AddNotificationToken_Call {
-	_c.Call.Return(err)
+func (_c *MockUserService_GetNotificationChannels_Call) Return(stringToBool map[string]bool, err error) *MockUserService_GetNotificationChannels_Call {
+	_c.Call.Return(stringToBool, err)
 	return _c
 }
 
-func (_c *MockUserService_AddNotificationToken_Call) RunAndReturn(run func(ctx context.Context, id int, text string) error) *MockUserService_AddNotificationToken_Call {
+func (_c *MockUserService_GetNotificationChannels_Call) RunAndReturn(run func(ctx context.Context, id int, notificationType string) (map[string]bool, error)) *MockUserService_GetNotificationChannels_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// BlockUser provides a mock function for the type MockUserService
-func (_mock *MockUserService) BlockUser(ctx context.Context, id int, reason string, blockerId *int, blockedUntil *time.Time) error {
-	ret := _mock.Called(ctx, id, reason, blockerId, blockedUntil)
+// GetNotificationPreference provides a mock function for the type MockUserService
+func (_mock *MockUserService) GetNotificationPreference(ctx context.Context, id int) (*models.NotificationPreference, error) {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for BlockUser")
+		panic("no return value specified for GetNotificationPreference")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, *int, *time.Time) error); ok {
-		r0 = returnFunc(ctx, id, reason, blockerId, blockedUntil)
+	var r0 *models.NotificationPreference
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.NotificationPreference, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.NotificationPreference); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.NotificationPreference)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockUserService_BlockUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BlockUser'
-type MockUserService_BlockUser_Call struct {
+// MockUserService_GetNotificationPreference_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNotificationPreference'
+type MockUserService_GetNotificationPreference_Call struct {
 	*mock.Call
 }
 
-// BlockUser is a helper method to define mock.On call
+// GetNotificationPreference is a helper method to define mock.On call
 //   - ctx
 //   - id
-//   - reason
-//   - blockerId
-//   - blockedUntil
-func (_e *MockUserService_Expecter) BlockUser(ctx interface{}, id interface{}, reason interface{}, blockerId interface{}, blockedUntil interface{}) *MockUserService_BlockUser_Call {
-	return &MockUserService_BlockUser_Call{Call: _e.mock.On("BlockUser", ctx, id, reason, blockerId, blockedUntil)}
+func (_e *MockUserService_Expecter) GetNotificationPreference(ctx interface{}, id interface{}) *MockUserService_GetNotificationPreference_Call {
+	return &MockUserService_GetNotificationPreference_Call{Call: _e.mock.On("GetNotificationPreference", ctx, id)}
 }
 
-func (_c *MockUserService_BlockUser_Call) Run(run func(ctx context.Context, id int, reason string, blockerId *int, blockedUntil *time.Time)) *MockUserService_BlockUser_Call {
+func (_c *MockUserService_GetNotificationPreference_Call) Run(run func(ctx context.Context, id int)) *MockUserService_GetNotificationPreference_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(*int), args[4].(*time.Time))
+		run(args[0].(context.Context), args[1].(int))
 	})
 	return _c
 }
 
-func (_c *MockUserService_BlockUser_Call) Return(err error) *MockUserService_BlockUser_Call {
-	_c.Call.Return(err)
+func (_c *MockUserService_GetNotificationPreference_Call) Return(notificationPreference *models.NotificationPreference, err error) *MockUserService_GetNotificationPreference_Call {
+	_c.Call.Return(notificationPreference, err)
+	return _c
+}
+
+func (_c *MockUserService_GetNotificationPreference_Call) RunAndReturn(run func(ctx context.Context, id int) (*models.NotificationPreference, error)) *MockUserService_GetNotificationPreference_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetNotificationSettings provides a mock function for the type MockUserService
+func (_mock *MockUserService) GetNotificationSettings(ctx context.Context, id int) (*models.NotificationSettings, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNotificationSettings")
+	}
+
+	var r0 *models.NotificationSettings
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.NotificationSettings, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.NotificationSettings); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.NotificationSettings)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_GetNotificationSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNotificationSettings'
+type MockUserService_GetNotificationSettings_Call struct {
+	*mock.Call
+}
+
+// GetNotificationSettings is a helper method to define mock.On call
+//   - ctx
+//   - id
+func (_e *MockUserService_Expecter) GetNotificationSettings(ctx interface{}, id interface{}) *MockUserService_GetNotificationSettings_Call {
+	return &MockUserService_GetNotificationSettings_Call{Call: _e.mock.On("GetNotificationSettings", ctx, id)}
+}
+
+func (_c *MockUserService_GetNotificationSettings_Call) Run(run func(ctx context.Context, id int)) *MockUserService_GetNotificationSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserService_GetNotificationSettings_Call) Return(notificationSettings *models.NotificationSettings, err error) *MockUserService_GetNotificationSettings_Call {
+	_c.Call.Return(notificationSettings, err)
+	return _c
+}
+
+func (_c *MockUserService_GetNotificationSettings_Call) RunAndReturn(run func(ctx context.Context, id int) (*models.NotificationSettings, error)) *MockUserService_GetNotificationSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetNotificationTypes provides a mock function for the type MockUserService
+func (_mock *MockUserService) GetNotificationTypes(ctx context.Context) ([]models.NotificationType, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNotificationTypes")
+	}
+
+	var r0 []models.NotificationType
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.NotificationType, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.NotificationType); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.NotificationType)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_GetNotificationTypes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNotificationTypes'
+type MockUserService_GetNotificationTypes_Call struct {
+	*mock.Call
+}
+
+// GetNotificationTypes is a helper method to define mock.On call
+//   - ctx
+func (_e *MockUserService_Expecter) GetNotificationTypes(ctx interface{}) *MockUserService_GetNotificationTypes_Call {
+	return &MockUserService_GetNotificationTypes_Call{Call: _e.mock.On("GetNotificationTypes", ctx)}
+}
+
+func (_c *MockUserService_GetNotificationTypes_Call) Run(run func(ctx context.Context)) *MockUserService_GetNotificationTypes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockUserService_GetNotificationTypes_Call) Return(notificationTypes []models.NotificationType, err error) *MockUserService_GetNotificationTypes_Call {
+	_c.Call.Return(notificationTypes, err)
+	return _c
+}
+
+func (_c *MockUserService_GetNotificationTypes_Call) RunAndReturn(run func(ctx context.Context) ([]models.NotificationType, error)) *MockUserService_GetNotificationTypes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserByEmail provides a mock function for the type MockUserService
+func (_mock *MockUserService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	ret := _mock.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByEmail")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
+		return returnFunc(ctx, email)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = returnFunc(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_GetUserByEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByEmail'
+type MockUserService_GetUserByEmail_Call struct {
+	*mock.Call
+}
+
+// GetUserByEmail is a helper method to define mock.On call
+//   - ctx
+//   - email
+func (_e *MockUserService_Expecter) GetUserByEmail(ctx interface{}, email interface{}) *MockUserService_GetUserByEmail_Call {
+	return &MockUserService_GetUserByEmail_Call{Call: _e.mock.On("GetUserByEmail", ctx, email)}
+}
+
+func (_c *MockUserService_GetUserByEmail_Call) Run(run func(ctx context.Context, email string)) *MockUserService_GetUserByEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserService_GetUserByEmail_Call) Return(user *models.User, err error) *MockUserService_GetUserByEmail_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserService_GetUserByEmail_Call) RunAndReturn(run func(ctx context.Context, email string) (*models.User, error)) *MockUserService_GetUserByEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserById provides a mock function for the type MockUserService
+func (_mock *MockUserService) GetUserById(ctx context.Context, id int) (*models.User, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserById")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.User, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.User); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_GetUserById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserById'
+type MockUserService_GetUserById_Call struct {
+	*mock.Call
+}
+
+// GetUserById is a helper method to define mock.On call
+//   - ctx
+//   - id
+func (_e *MockUserService_Expecter) GetUserById(ctx interface{}, id interface{}) *MockUserService_GetUserById_Call {
+	return &MockUserService_GetUserById_Call{Call: _e.mock.On("GetUserById", ctx, id)}
+}
+
+func (_c *MockUserService_GetUserById_Call) Run(run func(ctx context.Context, id int)) *MockUserService_GetUserById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserService_GetUserById_Call) Return(user *models.User, err error) *MockUserService_GetUserById_Call {
+	_c.Call.Return(user, err)
 	return _c
 }
 
-func (_c *MockUserService_BlockUser_Call) RunAndReturn(run func(ctx context.Context, id int, reason string, blockerId *int, blockedUntil *time.Time) error) *MockUserService_BlockUser_Call {
+func (_c *MockUserService_GetUserById_Call) RunAndReturn(run func(ctx context.Context, id int) (*models.User, error)) *MockUserService_GetUserById_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CheckPreference provides a mock function for the type MockUserService
-func (_mock *MockUserService) CheckPreference(ctx context.Context, id int, notificationType string) (bool, error) {
-	ret := _mock.Called(ctx, id, notificationType)
+// GetUserNotificationsToken provides a mock function for the type MockUserService
+func (_mock *MockUserService) GetUserNotificationsToken(ctx context.Context, id int) (models.NotificationTokens, error) {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CheckPreference")
+		panic("no return value specified for GetUserNotificationsToken")
 	}
 
-	var r0 bool
+	var r0 models.NotificationTokens
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) (bool, error)); ok {
-		return returnFunc(ctx, id, notificationType)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (models.NotificationTokens, error)); ok {
+		return returnFunc(ctx, id)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) bool); ok {
-		r0 = returnFunc(ctx, id, notificationType)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) models.NotificationTokens); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Get(0).(models.NotificationTokens)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
-		r1 = returnFunc(ctx, id, notificationType)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockUserService_CheckPreference_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckPreference'
-type MockUserService_CheckPreference_Call struct {
+// MockUserService_GetUserNotificationsToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserNotificationsToken'
+type MockUserService_GetUserNotificationsToken_Call struct {
 	*mock.Call
 }
 
-// CheckPreference is a helper method to define mock.On call
+// GetUserNotificationsToken is a helper method to define mock.On call
 //   - ctx
 //   - id
-//   - notificationType
-func (_e *MockUserService_Expecter) CheckPreference(ctx interface{}, id interface{}, notificationType interface{}) *MockUserService_CheckPreference_Call {
-	return &MockUserService_CheckPreference_Call{Call: _e.mock.On("CheckPreference", ctx, id, notificationType)}
+func (_e *MockUserService_Expecter) GetUserNotificationsToken(ctx interface{}, id interface{}) *MockUserService_GetUserNotificationsToken_Call {
+	return &MockUserService_GetUserNotificationsToken_Call{Call: _e.mock.On("GetUserNotificationsToken", ctx, id)}
 }
 
-func (_c *MockUserService_CheckPreference_Call) Run(run func(ctx context.Context, id int, notificationType string)) *MockUserService_CheckPreference_Call {
+func (_c *MockUserService_GetUserNotificationsToken_Call) Run(run func(ctx context.Context, id int)) *MockUserService_GetUserNotificationsToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(string))
+		run(args[0].(context.Context), args[1].(int))
 	})
 	return _c
 }
 
-func (_c *MockUserService_CheckPreference_Call) Return(b bool, err error) *MockUserService_CheckPreference_Call {
-	_c.Call.Return(b, err)
+func (_c *MockUserService_GetUserNotificationsToken_Call) Return(notificationTokens models.NotificationTokens, err error) *MockUserService_GetUserNotificationsToken_Call {
+	_c.Call.Return(notificationTokens, err)
 	return _c
 }
 
-func (_c *MockUserService_CheckPreference_Call) RunAndReturn(run func(ctx context.Context, id int, notificationType string) (bool, error)) *MockUserService_CheckPreference_Call {
+func (_c *MockUserService_GetUserNotificationsToken_Call) RunAndReturn(run func(ctx context.Context, id int) (models.NotificationTokens, error)) *MockUserService_GetUserNotificationsToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateUser provides a mock function for the type MockUserService
-func (_mock *MockUserService) CreateUser(ctx context.Context, request models.CreateUserRequest) (int, error) {
-	ret := _mock.Called(ctx, request)
+// IsSessionRevoked provides a mock function for the type MockUserService
+func (_mock *MockUserService) IsSessionRevoked(ctx context.Context, id int, issuedAt time.Time) (bool, error) {
+	ret := _mock.Called(ctx, id, issuedAt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateUser")
+		panic("no return value specified for IsSessionRevoked")
 	}
 
-	var r0 int
+	var r0 bool
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, models.CreateUserRequest) (int, error)); ok {
-		return returnFunc(ctx, request)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Time) (bool, error)); ok {
+		return returnFunc(ctx, id, issuedAt)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, models.CreateUserRequest) int); ok {
-		r0 = returnFunc(ctx, request)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Time) bool); ok {
+		r0 = returnFunc(ctx, id, issuedAt)
 	} else {
-		r0 = ret.Get(0).(int)
+		r0 = ret.Get(0).(bool)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, models.CreateUserRequest) error); ok {
-		r1 = returnFunc(ctx, request)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, time.Time) error); ok {
+		r1 = returnFunc(ctx, id, issuedAt)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockUserService_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
-type MockUserService_CreateUser_Call struct {
+// MockUserService_IsSessionRevoked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsSessionRevoked'
+type MockUserService_IsSessionRevoked_Call struct {
 	*mock.Call
 }
 
-// CreateUser is a helper method to define mock.On call
+// IsSessionRevoked is a helper method to define mock.On call
 //   - ctx
-//   - request
-func (_e *MockUserService_Expecter) CreateUser(ctx interface{}, request interface{}) *MockUserService_CreateUser_Call {
-	return &MockUserService_CreateUser_Call{Call: _e.mock.On("CreateUser", ctx, request)}
+//   - id
+//   - issuedAt
+func (_e *MockUserService_Expecter) IsSessionRevoked(ctx interface{}, id interface{}, issuedAt interface{}) *MockUserService_IsSessionRevoked_Call {
+	return &MockUserService_IsSessionRevoked_Call{Call: _e.mock.On("IsSessionRevoked", ctx, id, issuedAt)}
 }
 
-func (_c *MockUserService_CreateUser_Call) Run(run func(ctx context.Context, request models.CreateUserRequest)) *MockUserService_CreateUser_Call {
+func (_c *MockUserService_IsSessionRevoked_Call) Run(run func(ctx context.Context, id int, issuedAt time.Time)) *MockUserService_IsSessionRevoked_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(models.CreateUserRequest))
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time))
 	})
 	return _c
 }
 
-func (_c *MockUserService_CreateUser_Call) Return(n int, err error) *MockUserService_CreateUser_Call {
-	_c.Call.Return(n, err)
+func (_c *MockUserService_IsSessionRevoked_Call) Return(b bool, err error) *MockUserService_IsSessionRevoked_Call {
+	_c.Call.Return(b, err)
 	return _c
 }
 
-func (_c *MockUserService_CreateUser_Call) RunAndReturn(run func(ctx context.Context, request models.CreateUserRequest) (int, error)) *MockUserService_CreateUser_Call {
+func (_c *MockUserService_IsSessionRevoked_Call) RunAndReturn(run func(ctx context.Context, id int, issuedAt time.Time) (bool, error)) *MockUserService_IsSessionRevoked_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteUser provides a mock function for the type MockUserService
-func (_mock *MockUserService) DeleteUser(ctx context.Context, id int) error {
+// IsUserBlocked provides a mock function for the type MockUserService
+func (_mock *MockUserService) IsUserBlocked(ctx context.Context, id int) (bool, error) {
 	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteUser")
+		panic("no return value specified for IsUserBlocked")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (bool, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) bool); ok {
 		r0 = returnFunc(ctx, id)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(bool)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockUserService_DeleteUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUser'
-type MockUserService_DeleteUser_Call struct {
+// MockUserService_IsUserBlocked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsUserBlocked'
+type MockUserService_IsUserBlocked_Call struct {
 	*mock.Call
 }
 
-// DeleteUser is a helper method to define mock.On call
+// IsUserBlocked is a helper method to define mock.On call
 //   - ctx
 //   - id
-func (_e *MockUserService_Expecter) DeleteUser(ctx interface{}, id interface{}) *MockUserService_DeleteUser_Call {
-	return &MockUserService_DeleteUser_Call{Call: _e.mock.On("DeleteUser", ctx, id)}
+func (_e *MockUserService_Expecter) IsUserBlocked(ctx interface{}, id interface{}) *MockUserService_IsUserBlocked_Call {
+	return &MockUserService_IsUserBlocked_Call{Call: _e.mock.On("IsUserBlocked", ctx, id)}
 }
 
-func (_c *MockUserService_DeleteUser_Call) Run(run func(ctx context.Context, id int)) *MockUserService_DeleteUser_Call {
+func (_c *MockUserService_IsUserBlocked_Call) Run(run func(ctx context.Context, id int)) *MockUserService_IsUserBlocked_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(int))
 	})
 	return _c
 }
 
-func (_c *MockUserService_DeleteUser_Call) Return(err error) *MockUserService_DeleteUser_Call {
-	_c.Call.Return(err)
+func (_c *MockUserService_IsUserBlocked_Call) Return(b bool, err error) *MockUserService_IsUserBlocked_Call {
+	_c.Call.Return(b, err)
 	return _c
 }
 
-func (_c *MockUserService_DeleteUser_Call) RunAndReturn(run func(ctx context.Context, id int) error) *MockUserService_DeleteUser_Call {
+func (_c *MockUserService_IsUserBlocked_Call) RunAndReturn(run func(ctx context.Context, id int) (bool, error)) *MockUserService_IsUserBlocked_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetAllUsers provides a mock function for the type MockUserService
-func (_mock *MockUserService) GetAllUsers(ctx context.Context) ([]models.User, error) {
-	ret := _mock.Called(ctx)
+// MakeTeacher provides a mock function for the type MockUserService
+func (_mock *MockUserService) MakeTeacher(ctx context.Context, id int) error {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAllUsers")
+		panic("no return value specified for MakeTeacher")
 	}
 
-	var r0 []models.User
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.User, error)); ok {
-		return returnFunc(ctx)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.User); ok {
-		r0 = returnFunc(ctx)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.User)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockUserService_GetAllUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllUsers'
-type MockUserService_GetAllUsers_Call struct {
+// MockUserService_MakeTeacher_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MakeTeacher'
+type MockUserService_MakeTeacher_Call struct {
 	*mock.Call
 }
 
-// GetAllUsers is a helper method to define mock.On call
+// MakeTeacher is a helper method to define mock.On call
 //   - ctx
-func (_e *MockUserService_Expecter) GetAllUsers(ctx interface{}) *MockUserService_GetAllUsers_Call {
-	return &MockUserService_GetAllUsers_Call{Call: _e.mock.On("GetAllUsers", ctx)}
+//   - id
+func (_e *MockUserService_Expecter) MakeTeacher(ctx interface{}, id interface{}) *MockUserService_MakeTeacher_Call {
+	return &MockUserService_MakeTeacher_Call{Call: _e.mock.On("MakeTeacher", ctx, id)}
 }
 
-func (_c *MockUserService_GetAllUsers_Call) Run(run func(ctx context.Context)) *MockUserService_GetAllUsers_Call {
+func (_c *MockUserService_MakeTeacher_Call) Run(run func(ctx context.Context, id int)) *MockUserService_MakeTeacher_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(int))
 	})
 	return _c
 }
 
-func (_c *MockUserService_GetAllUsers_Call) Return(users []models.User, err error) *MockUserService_GetAllUsers_Call {
-	_c.Call.Return(users, err)
+func (_c *MockUserService_MakeTeacher_Call) Return(err error) *MockUserService_MakeTeacher_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserService_GetAllUsers_Call) RunAndReturn(run func(ctx context.Context) ([]models.User, error)) *MockUserService_GetAllUsers_Call {
+func (_c *MockUserService_MakeTeacher_Call) RunAndReturn(run func(ctx context.Context, id int) error) *MockUserService_MakeTeacher_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetNotificationPreference provides a mock function for the type MockUserService
-func (_mock *MockUserService) GetNotificationPreference(ctx context.Context, id int) (*models.NotificationPreference, error) {
-	ret := _mock.Called(ctx, id)
+// ModifyPassword provides a mock function for the type MockUserService
+func (_mock *MockUserService) ModifyPassword(ctx context.Context, id int, password string) error {
+	ret := _mock.Called(ctx, id, password)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetNotificationPreference")
+		panic("no return value specified for ModifyPassword")
 	}
 
-	var r0 *models.NotificationPreference
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.NotificationPreference, error)); ok {
-		return returnFunc(ctx, id)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.NotificationPreference); ok {
-		r0 = returnFunc(ctx, id)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.NotificationPreference)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = returnFunc(ctx, id)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, id, password)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockUserService_GetNotificationPreference_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNotificationPreference'
-type MockUserService_GetNotificationPreference_Call struct {
+// MockUserService_ModifyPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ModifyPassword'
+type MockUserService_ModifyPassword_Call struct {
 	*mock.Call
 }
 
-// GetNotificationPreference is a helper method to define mock.On call
+// ModifyPassword is a helper method to define mock.On call
 //   - ctx
 //   - id
-func (_e *MockUserService_Expecter) GetNotificationPreference(ctx interface{}, id interface{}) *MockUserService_GetNotificationPreference_Call {
-	return &MockUserService_GetNotificationPreference_Call{Call: _e.mock.On("GetNotificationPreference", ctx, id)}
+//   - password
+func (_e *MockUserService_Expecter) ModifyPassword(ctx interface{}, id interface{}, password interface{}) *MockUserService_ModifyPassword_Call {
+	return &MockUserService_ModifyPassword_Call{Call: _e.mock.On("ModifyPassword", ctx, id, password)}
 }
 
-func (_c *MockUserService_GetNotificationPreference_Call) Run(run func(ctx context.Context, id int)) *MockUserService_GetNotificationPreference_Call {
+func (_c *MockUserService_ModifyPassword_Call) Run(run func(ctx context.Context, id int, password string)) *MockUserService_ModifyPassword_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockUserService_GetNotificationPreference_Call) Return(notificationPreference *models.NotificationPreference, err error) *MockUserService_GetNotificationPreference_Call {
-	_c.Call.Return(notificationPreference, err)
+func (_c *MockUserService_ModifyPassword_Call) Return(err error) *MockUserService_ModifyPassword_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserService_GetNotificationPreference_Call) RunAndReturn(run func(ctx context.Context, id int) (*models.NotificationPreference, error)) *MockUserService_GetNotificationPreference_Call {
+func (_c *MockUserService_ModifyPassword_Call) RunAndReturn(run func(ctx context.Context, id int, password string) error) *MockUserService_ModifyPassword_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserByEmail provides a mock function for the type MockUserService
-func (_mock *MockUserService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
-	ret := _mock.Called(ctx, email)
+// ModifyUser provides a mock function for the type MockUserService
+func (_mock *MockUserService) ModifyUser(ctx context.Context, id int, user models.UserUpdateDto, ifMatch string) (*models.User, error) {
+	ret := _mock.Called(ctx, id, user, ifMatch)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserByEmail")
+		panic("no return value specified for ModifyUser")
 	}
 
 	var r0 *models.User
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
-		return returnFunc(ctx, email)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.UserUpdateDto, string) (*models.User, error)); ok {
+		return returnFunc(ctx, id, user, ifMatch)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
-		r0 = returnFunc(ctx, email)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.UserUpdateDto, string) *models.User); ok {
+		r0 = returnFunc(ctx, id, user, ifMatch)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.User)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, email)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, models.UserUpdateDto, string) error); ok {
+		r1 = returnFunc(ctx, id, user, ifMatch)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockUserService_GetUserByEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByEmail'
-type MockUserService_GetUserByEmail_Call struct {
+// MockUserService_ModifyUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ModifyUser'
+type MockUserService_ModifyUser_Call struct {
 	*mock.Call
 }
 
-// GetUserByEmail is a helper method to define mock.On call
+// ModifyUser is a helper method to define mock.On call
 //   - ctx
-//   - email
-func (_e *MockUserService_Expecter) GetUserByEmail(ctx interface{}, email interface{}) *MockUserService_GetUserByEmail_Call {
-	return &MockUserService_GetUserByEmail_Call{Call: _e.mock.On("GetUserByEmail", ctx, email)}
+//   - id
+//   - user
+//   - ifMatch
+func (_e *MockUserService_Expecter) ModifyUser(ctx interface{}, id interface{}, user interface{}, ifMatch interface{}) *MockUserService_ModifyUser_Call {
+	return &MockUserService_ModifyUser_Call{Call: _e.mock.On("ModifyUser", ctx, id, user, ifMatch)}
 }
 
-func (_c *MockUserService_GetUserByEmail_Call) Run(run func(ctx context.Context, email string)) *MockUserService_GetUserByEmail_Call {
+func (_c *MockUserService_ModifyUser_Call) Run(run func(ctx context.Context, id int, user models.UserUpdateDto, ifMatch string)) *MockUserService_ModifyUser_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(int), args[2].(models.UserUpdateDto), args[3].(string))
 	})
 	return _c
 }
 
-func (_c *MockUserService_GetUserByEmail_Call) Return(user *models.User, err error) *MockUserService_GetUserByEmail_Call {
-	_c.Call.Return(user, err)
+func (_c *MockUserService_ModifyUser_Call) Return(user1 *models.User, err error) *MockUserService_ModifyUser_Call {
+	_c.Call.Return(user1, err)
 	return _c
 }
 
-func (_c *MockUserService_GetUserByEmail_Call) RunAndReturn(run func(ctx context.Context, email string) (*models.User, error)) *MockUserService_GetUserByEmail_Call {
+func (_c *MockUserService_ModifyUser_Call) RunAndReturn(run func(ctx context.Context, id int, user models.UserUpdateDto, ifMatch string) (*models.User, error)) *MockUserService_ModifyUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserById provides a mock function for the type MockUserService
-func (_mock *MockUserService) GetUserById(ctx context.Context, id int) (*models.User, error) {
-	ret := _mock.Called(ctx, id)
+// NotifyAllUsers provides a mock function for the type MockUserService
+func (_mock *MockUserService) NotifyAllUsers(ctx context.Context, notification models.NotifyRequest) error {
+	ret := _mock.Called(ctx, notification)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserById")
+		panic("no return value specified for NotifyAllUsers")
 	}
 
-	var r0 *models.User
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.User, error)); ok {
-		return returnFunc(ctx, id)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.User); ok {
-		r0 = returnFunc(ctx, id)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.User)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = returnFunc(ctx, id)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.NotifyRequest) error); ok {
+		r0 = returnFunc(ctx, notification)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockUserService_GetUserById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserById'
-type MockUserService_GetUserById_Call struct {
+// MockUserService_NotifyAllUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NotifyAllUsers'
+type MockUserService_NotifyAllUsers_Call struct {
 	*mock.Call
 }
 
-// GetUserById is a helper method to define mock.On call
+// NotifyAllUsers is a helper method to define mock.On call
 //   - ctx
-//   - id
-func (_e *MockUserService_Expecter) GetUserById(ctx interface{}, id interface{}) *MockUserService_GetUserById_Call {
-	return &MockUserService_GetUserById_Call{Call: _e.mock.On("GetUserById", ctx, id)}
+//   - notification
+func (_e *MockUserService_Expecter) NotifyAllUsers(ctx interface{}, notification interface{}) *MockUserService_NotifyAllUsers_Call {
+	return &MockUserService_NotifyAllUsers_Call{Call: _e.mock.On("NotifyAllUsers", ctx, notification)}
 }
 
-func (_c *MockUserService_GetUserById_Call) Run(run func(ctx context.Context, id int)) *MockUserService_GetUserById_Call {
+func (_c *MockUserService_NotifyAllUsers_Call) Run(run func(ctx context.Context, notification models.NotifyRequest)) *MockUserService_NotifyAllUsers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context), args[1].(models.NotifyRequest))
 	})
 	return _c
 }
 
-func (_c *MockUserService_GetUserById_Call) Return(user *models.User, err error) *MockUserService_GetUserById_Call {
-	_c.Call.Return(user, err)
+func (_c *MockUserService_NotifyAllUsers_Call) Return(err error) *MockUserService_NotifyAllUsers_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserService_GetUserById_Call) RunAndReturn(run func(ctx context.Context, id int) (*models.User, error)) *MockUserService_GetUserById_Call {
+func (_c *MockUserService_NotifyAllUsers_Call) RunAndReturn(run func(ctx context.Context, notification models.NotifyRequest) error) *MockUserService_NotifyAllUsers_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserNotificationsToken provides a mock function for the type MockUserService
-func (_mock *MockUserService) GetUserNotificationsToken(ctx context.Context, id int) (models.NotificationTokens, error) {
-	ret := _mock.Called(ctx, id)
+// NotifyUsers provides a mock function for the type MockUserService
+func (_mock *MockUserService) NotifyUsers(ctx context.Context, userIds []int, notification models.NotifyRequest) error {
+	ret := _mock.Called(ctx, userIds, notification)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserNotificationsToken")
+		panic("no return value specified for NotifyUsers")
 	}
 
-	var r0 models.NotificationTokens
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (models.NotificationTokens, error)); ok {
-		return returnFunc(ctx, id)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) models.NotificationTokens); ok {
-		r0 = returnFunc(ctx, id)
-	} else {
-		r0 = ret.Get(0).(models.NotificationTokens)
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = returnFunc(ctx, id)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int, models.NotifyRequest) error); ok {
+		r0 = returnFunc(ctx, userIds, notification)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockUserService_GetUserNotificationsToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserNotificationsToken'
-type MockUserService_GetUserNotificationsToken_Call struct {
+// MockUserService_NotifyUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NotifyUsers'
+type MockUserService_NotifyUsers_Call struct {
 	*mock.Call
 }
 
-// GetUserNotificationsToken is a helper method to define mock.On call
+// NotifyUsers is a helper method to define mock.On call
 //   - ctx
-//   - id
-func (_e *MockUserService_Expecter) GetUserNotificationsToken(ctx interface{}, id interface{}) *MockUserService_GetUserNotificationsToken_Call {
-	return &MockUserService_GetUserNotificationsToken_Call{Call: _e.mock.On("GetUserNotificationsToken", ctx, id)}
+//   - userIds
+//   - notification
+func (_e *MockUserService_Expecter) NotifyUsers(ctx interface{}, userIds interface{}, notification interface{}) *MockUserService_NotifyUsers_Call {
+	return &MockUserService_NotifyUsers_Call{Call: _e.mock.On("NotifyUsers", ctx, userIds, notification)}
 }
 
-func (_c *MockUserService_GetUserNotificationsToken_Call) Run(run func(ctx context.Context, id int)) *MockUserService_GetUserNotificationsToken_Call {
+func (_c *MockUserService_NotifyUsers_Call) Run(run func(ctx context.Context, userIds []int, notification models.NotifyRequest)) *MockUserService_NotifyUsers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context), args[1].([]int), args[2].(models.NotifyRequest))
 	})
 	return _c
 }
 
-func (_c *MockUserService_GetUserNotificationsToken_Call) Return(notificationTokens models.NotificationTokens, err error) *MockUserService_GetUserNotificationsToken_Call {
-	_c.Call.Return(notificationTokens, err)
+func (_c *MockUserService_NotifyUsers_Call) Return(err error) *MockUserService_NotifyUsers_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserService_GetUserNotificationsToken_Call) RunAndReturn(run func(ctx context.Context, id int) (models.NotificationTokens, error)) *MockUserService_GetUserNotificationsToken_Call {
+func (_c *MockUserService_NotifyUsers_Call) RunAndReturn(run func(ctx context.Context, userIds []int, notification models.NotifyRequest) error) *MockUserService_NotifyUsers_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// IsUserBlocked provides a mock function for the type MockUserService
-func (_mock *MockUserService) IsUserBlocked(ctx context.Context, id int) (bool, error) {
-	ret := _mock.Called(ctx, id)
+// PatchUser provides a mock function for the type MockUserService
+func (_mock *MockUserService) PatchUser(ctx context.Context, id int, patch models.UserPatch, ifMatch string) (*models.User, error) {
+	ret := _mock.Called(ctx, id, patch, ifMatch)
 
 	if len(ret) == 0 {
-		panic("no return value specified for IsUserBlocked")
+		panic("no return value specified for PatchUser")
 	}
 
-	var r0 bool
+	var r0 *models.User
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (bool, error)); ok {
-		return returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.UserPatch, string) (*models.User, error)); ok {
+		return returnFunc(ctx, id, patch, ifMatch)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) bool); ok {
-		r0 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.UserPatch, string) *models.User); ok {
+		r0 = returnFunc(ctx, id, patch, ifMatch)
 	} else {
-		r0 = ret.Get(0).(bool)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, models.UserPatch, string) error); ok {
+		r1 = returnFunc(ctx, id, patch, ifMatch)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockUserService_IsUserBlocked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsUserBlocked'
-type MockUserService_IsUserBlocked_Call struct {
+// MockUserService_PatchUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PatchUser'
+type MockUserService_PatchUser_Call struct {
 	*mock.Call
 }
 
-// IsUserBlocked is a helper method to define mock.On call
+// PatchUser is a helper method to define mock.On call
 //   - ctx
 //   - id
-func (_e *MockUserService_Expecter) IsUserBlocked(ctx interface{}, id interface{}) *MockUserService_IsUserBlocked_Call {
-	return &MockUserService_IsUserBlocked_Call{Call: _e.mock.On("IsUserBlocked", ctx, id)}
+//   - patch
+//   - ifMatch
+func (_e *MockUserService_Expecter) PatchUser(ctx interface{}, id interface{}, patch interface{}, ifMatch interface{}) *MockUserService_PatchUser_Call {
+	return &MockUserService_PatchUser_Call{Call: _e.mock.On("PatchUser", ctx, id, patch, ifMatch)}
 }
 
-func (_c *MockUserService_IsUserBlocked_Call) Run(run func(ctx context.Context, id int)) *MockUserService_IsUserBlocked_Call {
+func (_c *MockUserService_PatchUser_Call) Run(run func(ctx context.Context, id int, patch models.UserPatch, ifMatch string)) *MockUserService_PatchUser_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context), args[1].(int), args[2].(models.UserPatch), args[3].(string))
 	})
 	return _c
 }
 
-func (_c *MockUserService_IsUserBlocked_Call) Return(b bool, err error) *MockUserService_IsUserBlocked_Call {
-	_c.Call.Return(b, err)
+func (_c *MockUserService_PatchUser_Call) Return(user *models.User, err error) *MockUserService_PatchUser_Call {
+	_c.Call.Return(user, err)
 	return _c
 }
 
-func (_c *MockUserService_IsUserBlocked_Call) RunAndReturn(run func(ctx context.Context, id int) (bool, error)) *MockUserService_IsUserBlocked_Call {
+func (_c *MockUserService_PatchUser_Call) RunAndReturn(run func(ctx context.Context, id int, patch models.UserPatch, ifMatch string) (*models.User, error)) *MockUserService_PatchUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// MakeTeacher provides a mock function for the type MockUserService
-func (_mock *MockUserService) MakeTeacher(ctx context.Context, id int) error {
-	ret := _mock.Called(ctx, id)
+// RehashPassword provides a mock function for the type MockUserService
+func (_mock *MockUserService) RehashPassword(ctx context.Context, user models.User, password string) error {
+	ret := _mock.Called(ctx, user, password)
 
 	if len(ret) == 0 {
-		panic("no return value specified for MakeTeacher")
+		panic("no return value specified for RehashPassword")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
-		r0 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.User, string) error); ok {
+		r0 = returnFunc(ctx, user, password)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockUserService_MakeTeacher_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MakeTeacher'
-type MockUserService_MakeTeacher_Call struct {
+// MockUserService_RehashPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RehashPassword'
+type MockUserService_RehashPassword_Call struct {
 	*mock.Call
 }
 
-// MakeTeacher is a helper method to define mock.On call
+// RehashPassword is a helper method to define mock.On call
 //   - ctx
-//   - id
-func (_e *MockUserService_Expecter) MakeTeacher(ctx interface{}, id interface{}) *MockUserService_MakeTeacher_Call {
-	return &MockUserService_MakeTeacher_Call{Call: _e.mock.On("MakeTeacher", ctx, id)}
+//   - user
+//   - password
+func (_e *MockUserService_Expecter) RehashPassword(ctx interface{}, user interface{}, password interface{}) *MockUserService_RehashPassword_Call {
+	return &MockUserService_RehashPassword_Call{Call: _e.mock.On("RehashPassword", ctx, user, password)}
 }
 
-func (_c *MockUserService_MakeTeacher_Call) Run(run func(ctx context.Context, id int)) *MockUserService_MakeTeacher_Call {
+func (_c *MockUserService_RehashPassword_Call) Run(run func(ctx context.Context, user models.User, password string)) *MockUserService_RehashPassword_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context), args[1].(models.User), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockUserService_MakeTeacher_Call) Return(err error) *MockUserService_MakeTeacher_Call {
+func (_c *MockUserService_RehashPassword_Call) Return(err error) *MockUserService_RehashPassword_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserService_MakeTeacher_Call) RunAndReturn(run func(ctx context.Context, id int) error) *MockUserService_MakeTeacher_Call {
+func (_c *MockUserService_RehashPassword_Call) RunAndReturn(run func(ctx context.Context, user models.User, password string) error) *MockUserService_RehashPassword_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ModifyPassword provides a mock function for the type MockUserService
-func (_mock *MockUserService) ModifyPassword(ctx context.Context, id int, password string) error {
-	ret := _mock.Called(ctx, id, password)
+// RequestEmailChange provides a mock function for the type MockUserService
+func (_mock *MockUserService) RequestEmailChange(ctx context.Context, id int, newEmail string) error {
+	ret := _mock.Called(ctx, id, newEmail)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ModifyPassword")
+		panic("no return value specified for RequestEmailChange")
 	}
 
 	var r0 error
 	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
-		r0 = returnFunc(ctx, id, password)
+		r0 = returnFunc(ctx, id, newEmail)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockUserService_ModifyPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ModifyPassword'
-type MockUserService_ModifyPassword_Call struct {
+// MockUserService_RequestEmailChange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequestEmailChange'
+type MockUserService_RequestEmailChange_Call struct {
 	*mock.Call
 }
 
-// ModifyPassword is a helper method to define mock.On call
+// RequestEmailChange is a helper method to define mock.On call
 //   - ctx
 //   - id
-//   - password
-func (_e *MockUserService_Expecter) ModifyPassword(ctx interface{}, id interface{}, password interface{}) *MockUserService_ModifyPassword_Call {
-	return &MockUserService_ModifyPassword_Call{Call: _e.mock.On("ModifyPassword", ctx, id, password)}
+//   - newEmail
+func (_e *MockUserService_Expecter) RequestEmailChange(ctx interface{}, id interface{}, newEmail interface{}) *MockUserService_RequestEmailChange_Call {
+	return &MockUserService_RequestEmailChange_Call{Call: _e.mock.On("RequestEmailChange", ctx, id, newEmail)}
 }
 
-func (_c *MockUserService_ModifyPassword_Call) Run(run func(ctx context.Context, id int, password string)) *MockUserService_ModifyPassword_Call {
+func (_c *MockUserService_RequestEmailChange_Call) Run(run func(ctx context.Context, id int, newEmail string)) *MockUserService_RequestEmailChange_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(int), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockUserService_ModifyPassword_Call) Return(err error) *MockUserService_ModifyPassword_Call {
+func (_c *MockUserService_RequestEmailChange_Call) Return(err error) *MockUserService_RequestEmailChange_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserService_ModifyPassword_Call) RunAndReturn(run func(ctx context.Context, id int, password string) error) *MockUserService_ModifyPassword_Call {
+func (_c *MockUserService_RequestEmailChange_Call) RunAndReturn(run func(ctx context.Context, id int, newEmail string) error) *MockUserService_RequestEmailChange_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ModifyUser provides a mock function for the type MockUserService
-func (_mock *MockUserService) ModifyUser(ctx context.Context, id int, user models.UserUpdateDto) error {
-	ret := _mock.Called(ctx, id, user)
+// SendInvitation provides a mock function for the type MockUserService
+func (_mock *MockUserService) SendInvitation(ctx context.Context, user *models.User) error {
+	ret := _mock.Called(ctx, user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ModifyUser")
+		panic("no return value specified for SendInvitation")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.UserUpdateDto) error); ok {
-		r0 = returnFunc(ctx, id, user)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = returnFunc(ctx, user)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockUserService_ModifyUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ModifyUser'
-type MockUserService_ModifyUser_Call struct {
+// MockUserService_SendInvitation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendInvitation'
+type MockUserService_SendInvitation_Call struct {
 	*mock.Call
 }
 
-// ModifyUser is a helper method to define mock.On call
+// SendInvitation is a helper method to define mock.On call
 //   - ctx
-//   - id
 //   - user
-func (_e *MockUserService_Expecter) ModifyUser(ctx interface{}, id interface{}, user interface{}) *MockUserService_ModifyUser_Call {
-	return &MockUserService_ModifyUser_Call{Call: _e.mock.On("ModifyUser", ctx, id, user)}
+func (_e *MockUserService_Expecter) SendInvitation(ctx interface{}, user interface{}) *MockUserService_SendInvitation_Call {
+	return &MockUserService_SendInvitation_Call{Call: _e.mock.On("SendInvitation", ctx, user)}
 }
 
-func (_c *MockUserService_ModifyUser_Call) Run(run func(ctx context.Context, id int, user models.UserUpdateDto)) *MockUserService_ModifyUser_Call {
+func (_c *MockUserService_SendInvitation_Call) Run(run func(ctx context.Context, user *models.User)) *MockUserService_SendInvitation_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(models.UserUpdateDto))
+		run(args[0].(context.Context), args[1].(*models.User))
 	})
 	return _c
 }
 
-func (_c *MockUserService_ModifyUser_Call) Return(err error) *MockUserService_ModifyUser_Call {
+func (_c *MockUserService_SendInvitation_Call) Return(err error) *MockUserService_SendInvitation_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserService_ModifyUser_Call) RunAndReturn(run func(ctx context.Context, id int, user models.UserUpdateDto) error) *MockUserService_ModifyUser_Call {
+func (_c *MockUserService_SendInvitation_Call) RunAndReturn(run func(ctx context.Context, user *models.User) error) *MockUserService_SendInvitation_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // SendNotifByEmail provides a mock function for the type MockUserService
-func (_mock *MockUserService) SendNotifByEmail(cont context.Context, userId int, request models.NotifyRequest) error {
-	ret := _mock.Called(cont, userId, request)
+func (_mock *MockUserService) SendNotifByEmail(cont context.Context, entry models.InboxEntry) error {
+	ret := _mock.Called(cont, entry)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendNotifByEmail")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.NotifyRequest) error); ok {
-		r0 = returnFunc(cont, userId, request)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.InboxEntry) error); ok {
+		r0 = returnFunc(cont, entry)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1496,15 +5338,14 @@ type MockUserService_SendNotifByEmail_Call struct {
 
 // SendNotifByEmail is a helper method to define mock.On call
 //   - cont
-//   - userId
-//   - request
-func (_e *MockUserService_Expecter) SendNotifByEmail(cont interface{}, userId interface{}, request interface{}) *MockUserService_SendNotifByEmail_Call {
-	return &MockUserService_SendNotifByEmail_Call{Call: _e.mock.On("SendNotifByEmail", cont, userId, request)}
+//   - entry
+func (_e *MockUserService_Expecter) SendNotifByEmail(cont interface{}, entry interface{}) *MockUserService_SendNotifByEmail_Call {
+	return &MockUserService_SendNotifByEmail_Call{Call: _e.mock.On("SendNotifByEmail", cont, entry)}
 }
 
-func (_c *MockUserService_SendNotifByEmail_Call) Run(run func(cont context.Context, userId int, request models.NotifyRequest)) *MockUserService_SendNotifByEmail_Call {
+func (_c *MockUserService_SendNotifByEmail_Call) Run(run func(cont context.Context, entry models.InboxEntry)) *MockUserService_SendNotifByEmail_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(models.NotifyRequest))
+		run(args[0].(context.Context), args[1].(models.InboxEntry))
 	})
 	return _c
 }
@@ -1514,22 +5355,22 @@ func (_c *MockUserService_SendNotifByEmail_Call) Return(err error) *MockUserServ
 	return _c
 }
 
-func (_c *MockUserService_SendNotifByEmail_Call) RunAndReturn(run func(cont context.Context, userId int, request models.NotifyRequest) error) *MockUserService_SendNotifByEmail_Call {
+func (_c *MockUserService_SendNotifByEmail_Call) RunAndReturn(run func(cont context.Context, entry models.InboxEntry) error) *MockUserService_SendNotifByEmail_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // SendNotifByMobile provides a mock function for the type MockUserService
-func (_mock *MockUserService) SendNotifByMobile(cont context.Context, userId int, notification models.NotifyRequest) error {
-	ret := _mock.Called(cont, userId, notification)
+func (_mock *MockUserService) SendNotifByMobile(cont context.Context, entry models.InboxEntry) error {
+	ret := _mock.Called(cont, entry)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendNotifByMobile")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.NotifyRequest) error); ok {
-		r0 = returnFunc(cont, userId, notification)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.InboxEntry) error); ok {
+		r0 = returnFunc(cont, entry)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1543,15 +5384,14 @@ type MockUserService_SendNotifByMobile_Call struct {
 
 // SendNotifByMobile is a helper method to define mock.On call
 //   - cont
-//   - userId
-//   - notification
-func (_e *MockUserService_Expecter) SendNotifByMobile(cont interface{}, userId interface{}, notification interface{}) *MockUserService_SendNotifByMobile_Call {
-	return &MockUserService_SendNotifByMobile_Call{Call: _e.mock.On("SendNotifByMobile", cont, userId, notification)}
+//   - entry
+func (_e *MockUserService_Expecter) SendNotifByMobile(cont interface{}, entry interface{}) *MockUserService_SendNotifByMobile_Call {
+	return &MockUserService_SendNotifByMobile_Call{Call: _e.mock.On("SendNotifByMobile", cont, entry)}
 }
 
-func (_c *MockUserService_SendNotifByMobile_Call) Run(run func(cont context.Context, userId int, notification models.NotifyRequest)) *MockUserService_SendNotifByMobile_Call {
+func (_c *MockUserService_SendNotifByMobile_Call) Run(run func(cont context.Context, entry models.InboxEntry)) *MockUserService_SendNotifByMobile_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(models.NotifyRequest))
+		run(args[0].(context.Context), args[1].(models.InboxEntry))
 	})
 	return _c
 }
@@ -1561,7 +5401,7 @@ func (_c *MockUserService_SendNotifByMobile_Call) Return(err error) *MockUserSer
 	return _c
 }
 
-func (_c *MockUserService_SendNotifByMobile_Call) RunAndReturn(run func(cont context.Context, userId int, notification models.NotifyRequest) error) *MockUserService_SendNotifByMobile_Call {
+func (_c *MockUserService_SendNotifByMobile_Call) RunAndReturn(run func(cont context.Context, entry models.InboxEntry) error) *MockUserService_SendNotifByMobile_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1613,95 +5453,153 @@ func (_c *MockUserService_SetNotificationPreference_Call) RunAndReturn(run func(
 	return _c
 }
 
-// SetPasswordTokenUsed provides a mock function for the type MockUserService
-func (_mock *MockUserService) SetPasswordTokenUsed(ctx context.Context, token string) error {
-	ret := _mock.Called(ctx, token)
+// StartPasswordReset provides a mock function for the type MockUserService
+func (_mock *MockUserService) StartPasswordReset(ctx context.Context, email string) error {
+	ret := _mock.Called(ctx, email)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SetPasswordTokenUsed")
+		panic("no return value specified for StartPasswordReset")
 	}
 
 	var r0 error
 	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = returnFunc(ctx, token)
+		r0 = returnFunc(ctx, email)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockUserService_SetPasswordTokenUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetPasswordTokenUsed'
-type MockUserService_SetPasswordTokenUsed_Call struct {
+// MockUserService_StartPasswordReset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartPasswordReset'
+type MockUserService_StartPasswordReset_Call struct {
 	*mock.Call
 }
 
-// SetPasswordTokenUsed is a helper method to define mock.On call
+// StartPasswordReset is a helper method to define mock.On call
 //   - ctx
-//   - token
-func (_e *MockUserService_Expecter) SetPasswordTokenUsed(ctx interface{}, token interface{}) *MockUserService_SetPasswordTokenUsed_Call {
-	return &MockUserService_SetPasswordTokenUsed_Call{Call: _e.mock.On("SetPasswordTokenUsed", ctx, token)}
+//   - email
+func (_e *MockUserService_Expecter) StartPasswordReset(ctx interface{}, email interface{}) *MockUserService_StartPasswordReset_Call {
+	return &MockUserService_StartPasswordReset_Call{Call: _e.mock.On("StartPasswordReset", ctx, email)}
 }
 
-func (_c *MockUserService_SetPasswordTokenUsed_Call) Run(run func(ctx context.Context, token string)) *MockUserService_SetPasswordTokenUsed_Call {
+func (_c *MockUserService_StartPasswordReset_Call) Run(run func(ctx context.Context, email string)) *MockUserService_StartPasswordReset_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockUserService_SetPasswordTokenUsed_Call) Return(err error) *MockUserService_SetPasswordTokenUsed_Call {
+func (_c *MockUserService_StartPasswordReset_Call) Return(err error) *MockUserService_StartPasswordReset_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserService_SetPasswordTokenUsed_Call) RunAndReturn(run func(ctx context.Context, token string) error) *MockUserService_SetPasswordTokenUsed_Call {
+func (_c *MockUserService_StartPasswordReset_Call) RunAndReturn(run func(ctx context.Context, email string) error) *MockUserService_StartPasswordReset_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// StartPasswordReset provides a mock function for the type MockUserService
-func (_mock *MockUserService) StartPasswordReset(ctx context.Context, id int, email string) error {
-	ret := _mock.Called(ctx, id, email)
+// StreamUsers provides a mock function for the type MockUserService
+func (_mock *MockUserService) StreamUsers(ctx context.Context, filter models.UserFilter, fn func(models.User) error) error {
+	ret := _mock.Called(ctx, filter, fn)
 
 	if len(ret) == 0 {
-		panic("no return value specified for StartPasswordReset")
+		panic("no return value specified for StreamUsers")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
-		r0 = returnFunc(ctx, id, email)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.UserFilter, func(models.User) error) error); ok {
+		r0 = returnFunc(ctx, filter, fn)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockUserService_StartPasswordReset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartPasswordReset'
-type MockUserService_StartPasswordReset_Call struct {
+// MockUserService_StreamUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StreamUsers'
+type MockUserService_StreamUsers_Call struct {
 	*mock.Call
 }
 
-// StartPasswordReset is a helper method to define mock.On call
+// StreamUsers is a helper method to define mock.On call
 //   - ctx
-//   - id
-//   - email
-func (_e *MockUserService_Expecter) StartPasswordReset(ctx interface{}, id interface{}, email interface{}) *MockUserService_StartPasswordReset_Call {
-	return &MockUserService_StartPasswordReset_Call{Call: _e.mock.On("StartPasswordReset", ctx, id, email)}
+//   - filter
+//   - fn
+func (_e *MockUserService_Expecter) StreamUsers(ctx interface{}, filter interface{}, fn interface{}) *MockUserService_StreamUsers_Call {
+	return &MockUserService_StreamUsers_Call{Call: _e.mock.On("StreamUsers", ctx, filter, fn)}
 }
 
-func (_c *MockUserService_StartPasswordReset_Call) Run(run func(ctx context.Context, id int, email string)) *MockUserService_StartPasswordReset_Call {
+func (_c *MockUserService_StreamUsers_Call) Run(run func(ctx context.Context, filter models.UserFilter, fn func(models.User) error)) *MockUserService_StreamUsers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(string))
+		run(args[0].(context.Context), args[1].(models.UserFilter), args[2].(func(models.User) error))
 	})
 	return _c
 }
 
-func (_c *MockUserService_StartPasswordReset_Call) Return(err error) *MockUserService_StartPasswordReset_Call {
+func (_c *MockUserService_StreamUsers_Call) Return(err error) *MockUserService_StreamUsers_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserService_StartPasswordReset_Call) RunAndReturn(run func(ctx context.Context, id int, email string) error) *MockUserService_StartPasswordReset_Call {
+func (_c *MockUserService_StreamUsers_Call) RunAndReturn(run func(ctx context.Context, filter models.UserFilter, fn func(models.User) error) error) *MockUserService_StreamUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateNotificationPreferences provides a mock function for the type MockUserService
+func (_mock *MockUserService) UpdateNotificationPreferences(ctx context.Context, id int, preferences models.NotificationPreferences) (*models.NotificationSettings, error) {
+	ret := _mock.Called(ctx, id, preferences)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateNotificationPreferences")
+	}
+
+	var r0 *models.NotificationSettings
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.NotificationPreferences) (*models.NotificationSettings, error)); ok {
+		return returnFunc(ctx, id, preferences)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, models.NotificationPreferences) *models.NotificationSettings); ok {
+		r0 = returnFunc(ctx, id, preferences)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.NotificationSettings)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, models.NotificationPreferences) error); ok {
+		r1 = returnFunc(ctx, id, preferences)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_UpdateNotificationPreferences_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateNotificationPreferences'
+type MockUserService_UpdateNotificationPreferences_Call struct {
+	*mock.Call
+}
+
+// UpdateNotificationPreferences is a helper method to define mock.On call
+//   - ctx
+//   - id
+//   - preferences
+func (_e *MockUserService_Expecter) UpdateNotificationPreferences(ctx interface{}, id interface{}, preferences interface{}) *MockUserService_UpdateNotificationPreferences_Call {
+	return &MockUserService_UpdateNotificationPreferences_Call{Call: _e.mock.On("UpdateNotificationPreferences", ctx, id, preferences)}
+}
+
+func (_c *MockUserService_UpdateNotificationPreferences_Call) Run(run func(ctx context.Context, id int, preferences models.NotificationPreferences)) *MockUserService_UpdateNotificationPreferences_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.NotificationPreferences))
+	})
+	return _c
+}
+
+func (_c *MockUserService_UpdateNotificationPreferences_Call) Return(notificationSettings *models.NotificationSettings, err error) *MockUserService_UpdateNotificationPreferences_Call {
+	_c.Call.Return(notificationSettings, err)
+	return _c
+}
+
+func (_c *MockUserService_UpdateNotificationPreferences_Call) RunAndReturn(run func(ctx context.Context, id int, preferences models.NotificationPreferences) (*models.NotificationSettings, error)) *MockUserService_UpdateNotificationPreferences_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1809,89 +5707,6 @@ func (_c *MockUserService_VerifyUser_Call) RunAndReturn(run func(ctx context.Con
 	return _c
 }
 
-// NewMockEmailSender creates a new instance of MockEmailSender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func NewMockEmailSender(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *MockEmailSender {
-	mock := &MockEmailSender{}
-	mock.Mock.Test(t)
-
-	t.Cleanup(func() { mock.AssertExpectations(t) })
-
-	return mock
-}
-
-// MockEmailSender is an autogenerated mock type for the EmailSender type
-type MockEmailSender struct {
-	mock.Mock
-}
-
-type MockEmailSender_Expecter struct {
-	mock *mock.Mock
-}
-
-func (_m *MockEmailSender) EXPECT() *MockEmailSender_Expecter {
-	return &MockEmailSender_Expecter{mock: &_m.Mock}
-}
-
-// Send provides a mock function for the type MockEmailSender
-func (_mock *MockEmailSender) Send(email *mail.SGMailV3) (*rest.Response, error) {
-	ret := _mock.Called(email)
-
-	if len(ret) == 0 {
-		panic("no return value specified for Send")
-	}
-
-	var r0 *rest.Response
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(*mail.SGMailV3) (*rest.Response, error)); ok {
-		return returnFunc(email)
-	}
-	if returnFunc, ok := ret.Get(0).(func(*mail.SGMailV3) *rest.Response); ok {
-		r0 = returnFunc(email)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*rest.Response)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(*mail.SGMailV3) error); ok {
-		r1 = returnFunc(email)
-	} else {
-		r1 = ret.Error(1)
-	}
-	return r0, r1
-}
-
-// MockEmailSender_Send_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Send'
-type MockEmailSender_Send_Call struct {
-	*mock.Call
-}
-
-// Send is a helper method to define mock.On call
-//   - email
-func (_e *MockEmailSender_Expecter) Send(email interface{}) *MockEmailSender_Send_Call {
-	return &MockEmailSender_Send_Call{Call: _e.mock.On("Send", email)}
-}
-
-func (_c *MockEmailSender_Send_Call) Run(run func(email *mail.SGMailV3)) *MockEmailSender_Send_Call {
-	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*mail.SGMailV3))
-	})
-	return _c
-}
-
-func (_c *MockEmailSender_Send_Call) Return(response *rest.Response, err error) *MockEmailSender_Send_Call {
-	_c.Call.Return(response, err)
-	return _c
-}
-
-func (_c *MockEmailSender_Send_Call) RunAndReturn(run func(email *mail.SGMailV3) (*rest.Response, error)) *MockEmailSender_Send_Call {
-	_c.Call.Return(run)
-	return _c
-}
-
 // NewMockVerificationService creates a new instance of MockVerificationService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockVerificationService(t interface {
@@ -2022,6 +5837,61 @@ func (_c *MockVerificationService_GetVerification_Call) RunAndReturn(run func(ct
 	return _c
 }
 
+// RegisterUser provides a mock function for the type MockVerificationService
+func (_mock *MockVerificationService) RegisterUser(ctx context.Context, request models.CreateUserRequest) (int, error) {
+	ret := _mock.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegisterUser")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.CreateUserRequest) (int, error)); ok {
+		return returnFunc(ctx, request)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.CreateUserRequest) int); ok {
+		r0 = returnFunc(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.CreateUserRequest) error); ok {
+		r1 = returnFunc(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockVerificationService_RegisterUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RegisterUser'
+type MockVerificationService_RegisterUser_Call struct {
+	*mock.Call
+}
+
+// RegisterUser is a helper method to define mock.On call
+//   - ctx
+//   - request
+func (_e *MockVerificationService_Expecter) RegisterUser(ctx interface{}, request interface{}) *MockVerificationService_RegisterUser_Call {
+	return &MockVerificationService_RegisterUser_Call{Call: _e.mock.On("RegisterUser", ctx, request)}
+}
+
+func (_c *MockVerificationService_RegisterUser_Call) Run(run func(ctx context.Context, request models.CreateUserRequest)) *MockVerificationService_RegisterUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.CreateUserRequest))
+	})
+	return _c
+}
+
+func (_c *MockVerificationService_RegisterUser_Call) Return(n int, err error) *MockVerificationService_RegisterUser_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockVerificationService_RegisterUser_Call) RunAndReturn(run func(ctx context.Context, request models.CreateUserRequest) (int, error)) *MockVerificationService_RegisterUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SendVerificationEmail provides a mock function for the type MockVerificationService
 func (_mock *MockVerificationService) SendVerificationEmail(ctx context.Context, userId int, email string) error {
 	ret := _mock.Called(ctx, userId, email)
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	repo "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+)
+
+var ErrWrongPassword = errors.New("current password is incorrect")
+
+type PasswordService interface {
+	CheckPolicy(password string, personalInfo []string) error
+	CheckPassword(ctx context.Context, id int, password string) error
+	SetPassword(ctx context.Context, id int, password string) error
+	// ResetPassword changes the password of the user of a reset token that was already validated, using it up
+	ResetPassword(ctx context.Context, tokenId int, userId int, password string) error
+	ChangePassword(ctx context.Context, id int, currentPassword string, newPassword string) error
+	// HashPassword hashes a password with the configured algorithm and parameters
+	HashPassword(password string) (string, error)
+}
+
+type passwordService struct {
+	userRepo   repo.UserRepository
+	policy     models.PasswordPolicy
+	hashParams utils.HashParams
+}
+
+func NewPasswordService(userRepo repo.UserRepository, policy models.PasswordPolicy, hashParams utils.HashParams) *passwordService {
+	return &passwordService{userRepo: userRepo, policy: policy, hashParams: hashParams}
+}
+
+// CheckPolicy validates a password for a user that doesn't exist yet, so there is no history to check.
+// It returns models.ValidationErrors listing every broken rule.
+func (s *passwordService) CheckPolicy(password string, personalInfo []string) error {
+	if errs := s.policy.Validate(password, personalInfo); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// SetPassword replaces the user's password after checking it against the policy and the
+// user's password history. Used by flows where the user already proved their identity.
+func (s *passwordService) SetPassword(ctx context.Context, id int, password string) error {
+	user, err := s.userRepo.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.setPassword(ctx, user, password)
+}
+
+// ResetPassword checks the password like SetPassword, then uses up the token and changes the password
+// together, so the token is only gone if the password changed. It returns ErrInvalidResetToken if
+// another request used the token first.
+func (s *passwordService) ResetPassword(ctx context.Context, tokenId int, userId int, password string) error {
+	user, err := s.userRepo.GetUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if err := s.checkPassword(ctx, user, password); err != nil {
+		return err
+	}
+
+	hash, err := s.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	err = s.userRepo.ResetPassword(ctx, tokenId, userId, hash)
+	if errors.Is(err, repo.ErrNotFound) {
+		return ErrInvalidResetToken
+	}
+	return err
+}
+
+// CheckPassword validates a new password for the user against the policy and the user's password
+// history without saving it, so flows can reject it before using up anything.
+func (s *passwordService) CheckPassword(ctx context.Context, id int, password string) error {
+	user, err := s.userRepo.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.checkPassword(ctx, user, password)
+}
+
+// ChangePassword replaces the user's password only if currentPassword is the one stored.
+func (s *passwordService) ChangePassword(ctx context.Context, id int, currentPassword string, newPassword string) error {
+	user, err := s.userRepo.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if utils.CompareHashPassword(user.Password, currentPassword) != nil {
+		return ErrWrongPassword
+	}
+	return s.setPassword(ctx, user, newPassword)
+}
+
+func (s *passwordService) setPassword(ctx context.Context, user *models.User, password string) error {
+	if err := s.checkPassword(ctx, user, password); err != nil {
+		return err
+	}
+
+	hash, err := s.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	return s.userRepo.ModifyPassword(ctx, user.Id, hash)
+}
+
+func (s *passwordService) HashPassword(password string) (string, error) {
+	return s.hashParams.Hash(password)
+}
+
+func (s *passwordService) checkPassword(ctx context.Context, user *models.User, password string) error {
+	errs := s.policy.Validate(password, []string{user.Name, user.Surname, user.Email})
+
+	if s.policy.HistorySize > 0 {
+		history, err := s.userRepo.GetPasswordHistory(ctx, user.Id, s.policy.HistorySize)
+		if err != nil {
+			return err
+		}
+		for _, hash := range history {
+			if utils.CompareHashPassword(hash, password) == nil {
+				errs = append(errs, models.FieldError{
+					Field:   "password",
+					Message: fmt.Sprintf("must not be one of your last %d passwords", s.policy.HistorySize),
+				})
+				break
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPasswordService_CheckPolicy(t *testing.T) {
+	service := services.NewPasswordService(repositories.NewMockUserRepository(t), models.DefaultPasswordPolicy(), utils.DefaultHashParams())
+
+	assert.NoError(t, service.CheckPolicy("Correct4Horse", []string{"John", "Doe", "john@example.com"}))
+
+	err := service.CheckPolicy("John1234", []string{"John", "Doe", "john@example.com"})
+	var validationErrs models.ValidationErrors
+	assert.ErrorAs(t, err, &validationErrs)
+}
+
+func TestPasswordService_SetPassword(t *testing.T) {
+	mockRepo := repositories.NewMockUserRepository(t)
+	service := services.NewPasswordService(mockRepo, models.DefaultPasswordPolicy(), utils.DefaultHashParams())
+	ctx := context.Background()
+
+	oldHash, _ := utils.HashPassword("OldPassword1")
+	user := &models.User{Id: 1, Name: "John", Surname: "Doe", Email: "john@example.com", Password: oldHash}
+
+	mockRepo.EXPECT().GetUser(ctx, 1).Return(user, nil)
+	mockRepo.EXPECT().GetPasswordHistory(ctx, 1, 5).Return([]string{oldHash}, nil)
+	mockRepo.EXPECT().ModifyPassword(ctx, 1, mock.AnythingOfType("string")).
+		RunAndReturn(func(_ context.Context, _ int, hash string) error {
+			assert.NoError(t, utils.CompareHashPassword(hash, "NewPassword1"))
+			return nil
+		})
+
+	assert.NoError(t, service.SetPassword(ctx, 1, "NewPassword1"))
+}
+
+func TestPasswordService_SetPassword_ReusedPassword(t *testing.T) {
+	mockRepo := repositories.NewMockUserRepository(t)
+	service := services.NewPasswordService(mockRepo, models.DefaultPasswordPolicy(), utils.DefaultHashParams())
+	ctx := context.Background()
+
+	currentHash, _ := utils.HashPassword("Current4Pass")
+	oldHash, _ := utils.HashPassword("OldPassword1")
+	user := &models.User{Id: 1, Name: "John", Surname: "Doe", Email: "john@example.com", Password: currentHash}
+
+	mockRepo.EXPECT().GetUser(ctx, 1).Return(user, nil)
+	mockRepo.EXPECT().GetPasswordHistory(ctx, 1, 5).Return([]string{currentHash, oldHash}, nil)
+
+	err := service.SetPassword(ctx, 1, "OldPassword1")
+
+	var validationErrs models.ValidationErrors
+	assert.ErrorAs(t, err, &validationErrs)
+	assert.Equal(t, "must not be one of your last 5 passwords", validationErrs[0].Message)
+}
+
+func TestPasswordService_SetPassword_UserNotFound(t *testing.T) {
+	mockRepo := repositories.NewMockUserRepository(t)
+	service := services.NewPasswordService(mockRepo, models.DefaultPasswordPolicy(), utils.DefaultHashParams())
+	ctx := context.Background()
+
+	mockRepo.EXPECT().GetUser(ctx, 1).Return(nil, repositories.ErrNotFound)
+
+	assert.ErrorIs(t, service.SetPassword(ctx, 1, "NewPassword1"), repositories.ErrNotFound)
+}
+
+func TestPasswordService_ResetPassword(t *testing.T) {
+	mockRepo := repositories.NewMockUserRepository(t)
+	service := services.NewPasswordService(mockRepo, models.DefaultPasswordPolicy(), utils.DefaultHashParams())
+	ctx := context.Background()
+
+	oldHash, _ := utils.HashPassword("OldPassword1")
+	user := &models.User{Id: 1, Name: "John", Surname: "Doe", Email: "john@example.com", Password: oldHash}
+
+	mockRepo.EXPECT().GetUser(ctx, 1).Return(user, nil)
+	mockRepo.EXPECT().GetPasswordHistory(ctx, 1, 5).Return([]string{oldHash}, nil)
+	mockRepo.EXPECT().ResetPassword(ctx, 4, 1, mock.AnythingOfType("string")).
+		RunAndReturn(func(_ context.Context, _ int, _ int, hash string) error {
+			assert.NoError(t, utils.CompareHashPassword(hash, "NewPassword1"))
+			return nil
+		}).Once()
+	assert.NoError(t, service.ResetPassword(ctx, 4, 1, "NewPassword1"))
+
+	// Another request used the token first
+	mockRepo.EXPECT().ResetPassword(ctx, 4, 1, mock.AnythingOfType("string")).Return(repositories.ErrNotFound).Once()
+	assert.ErrorIs(t, service.ResetPassword(ctx, 4, 1, "NewPassword1"), services.ErrInvalidResetToken)
+}
+
+func TestPasswordService_ResetPassword_RejectedPasswordKeepsToken(t *testing.T) {
+	mockRepo := repositories.NewMockUserRepository(t)
+	service := services.NewPasswordService(mockRepo, models.DefaultPasswordPolicy(), utils.DefaultHashParams())
+	ctx := context.Background()
+
+	user := &models.User{Id: 1, Name: "John", Surname: "Doe", Email: "john@example.com"}
+	mockRepo.EXPECT().GetUser(ctx, 1).Return(user, nil)
+	mockRepo.EXPECT().GetPasswordHistory(ctx, 1, 5).Return(nil, nil)
+
+	err := service.ResetPassword(ctx, 4, 1, "short")
+
+	var validationErrs models.ValidationErrors
+	assert.ErrorAs(t, err, &validationErrs)
+}
+
+func TestPasswordService_CheckPassword(t *testing.T) {
+	mockRepo := repositories.NewMockUserRepository(t)
+	service := services.NewPasswordService(mockRepo, models.DefaultPasswordPolicy(), utils.DefaultHashParams())
+	ctx := context.Background()
+
+	oldHash, _ := utils.HashPassword("OldPassword1")
+	user := &models.User{Id: 1, Name: "John", Surname: "Doe", Email: "john@example.com", Password: oldHash}
+
+	// Nothing is saved
+	mockRepo.EXPECT().GetUser(ctx, 1).Return(user, nil).Times(2)
+	mockRepo.EXPECT().GetPasswordHistory(ctx, 1, 5).Return([]string{oldHash}, nil).Times(2)
+
+	assert.NoError(t, service.CheckPassword(ctx, 1, "NewPassword1"))
+
+	var validationErrs models.ValidationErrors
+	assert.ErrorAs(t, service.CheckPassword(ctx, 1, "OldPassword1"), &validationErrs)
+}
+
+func TestPasswordService_ChangePassword(t *testing.T) {
+	ctx := context.Background()
+	currentHash, _ := utils.HashPassword("Current4Pass")
+
+	t.Run("wrong current password", func(t *testing.T) {
+		mockRepo := repositories.NewMockUserRepository(t)
+		service := services.NewPasswordService(mockRepo, models.DefaultPasswordPolicy(), utils.DefaultHashParams())
+		mockRepo.EXPECT().GetUser(ctx, 1).Return(&models.User{Id: 1, Password: currentHash}, nil)
+
+		err := service.ChangePassword(ctx, 1, "NotMyPassword1", "NewPassword1")
+
+		assert.ErrorIs(t, err, services.ErrWrongPassword)
+	})
+
+	t.Run("history lookup fails", func(t *testing.T) {
+		mockRepo := repositories.NewMockUserRepository(t)
+		service := services.NewPasswordService(mockRepo, models.DefaultPasswordPolicy(), utils.DefaultHashParams())
+		mockRepo.EXPECT().GetUser(ctx, 1).Return(&models.User{Id: 1, Password: currentHash}, nil)
+		mockRepo.EXPECT().GetPasswordHistory(ctx, 1, 5).Return(nil, errors.New("db error"))
+
+		err := service.ChangePassword(ctx, 1, "Current4Pass", "NewPassword1")
+
+		assert.EqualError(t, err, "db error")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := repositories.NewMockUserRepository(t)
+		service := services.NewPasswordService(mockRepo, models.DefaultPasswordPolicy(), utils.DefaultHashParams())
+		mockRepo.EXPECT().GetUser(ctx, 1).Return(&models.User{Id: 1, Password: currentHash}, nil)
+		mockRepo.EXPECT().GetPasswordHistory(ctx, 1, 5).Return([]string{currentHash}, nil)
+		mockRepo.EXPECT().ModifyPassword(ctx, 1, mock.AnythingOfType("string")).Return(nil)
+
+		assert.NoError(t, service.ChangePassword(ctx, 1, "Current4Pass", "NewPassword1"))
+	})
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	repo "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+)
+
+// RuleProposalService is the four-eyes workflow for the rules: an admin proposes a change
+// and it is applied only when a different admin approves it
+type RuleProposalService interface {
+	ProposeRule(ctx context.Context, rule models.Rule, userId int) (*models.RuleProposal, error)
+	ProposeModification(ctx context.Context, ruleId int, modification models.RuleModify, userId int, ifMatch string) (*models.RuleProposal, error)
+	ProposeDeletion(ctx context.Context, ruleId int, userId int, ifMatch string) (*models.RuleProposal, error)
+	// ProposeRollback proposes restoring the content of an earlier version of the rule
+	ProposeRollback(ctx context.Context, ruleId int, version int, userId int, ifMatch string) (*models.RuleProposal, error)
+	// ProposeStatusChange proposes publishing or retiring the rule
+	ProposeStatusChange(ctx context.Context, ruleId int, action models.RuleProposalAction, userId int, ifMatch string) (*models.RuleProposal, error)
+	GetProposal(ctx context.Context, proposalId int) (*models.RuleProposal, error)
+	GetProposals(ctx context.Context, filter models.RuleProposalListRequest) ([]models.RuleProposal, error)
+	CommentProposal(ctx context.Context, proposalId int, userId int, comment string) (*models.RuleProposalComment, error)
+	// ApproveProposal applies the proposal, the admin approving it can't be the one who proposed it
+	ApproveProposal(ctx context.Context, proposalId int, userId int) (*models.RuleProposal, error)
+	// RejectProposal discards the proposal, the admin who proposed it can reject it to withdraw it
+	RejectProposal(ctx context.Context, proposalId int, userId int, reason string) (*models.RuleProposal, error)
+}
+
+var (
+	ErrSelfApproval      = errors.New("proposals must be approved by a different admin")
+	ErrProposalOutdated  = errors.New("the rule changed since the proposal was made, it can only be rejected")
+	ErrEmptyModification = errors.New("the modification doesn't change anything")
+)
+
+type ruleProposalService struct {
+	proposalRepo repo.RuleProposalRepository
+	rulesRepo    repo.RulesRepository
+}
+
+func NewRuleProposalService(proposalRepo repo.RuleProposalRepository, rulesRepo repo.RulesRepository) *ruleProposalService {
+	return &ruleProposalService{proposalRepo: proposalRepo, rulesRepo: rulesRepo}
+}
+
+func (s ruleProposalService) ProposeRule(ctx context.Context, rule models.Rule, userId int) (*models.RuleProposal, error) {
+	rule, err := normalizeNewRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	return s.proposalRepo.AddProposal(ctx, models.RuleProposal{Action: models.RuleProposalCreate, Rule: &rule, ProposedBy: userId})
+}
+
+func (s ruleProposalService) ProposeModification(ctx context.Context, ruleId int, modification models.RuleModify, userId int, ifMatch string) (*models.RuleProposal, error) {
+	if modification.IsEmpty() {
+		return nil, ErrEmptyModification
+	}
+	modification.Normalize()
+	rule, err := s.getRuleIfMatch(ctx, ruleId, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRuleModification(*rule, modification); err != nil {
+		return nil, err
+	}
+	return s.proposalRepo.AddProposal(ctx, models.RuleProposal{
+		Action: models.RuleProposalModify, RuleId: ruleId, RuleVersion: rule.Version, Modification: &modification, ProposedBy: userId,
+	})
+}
+
+func (s ruleProposalService) ProposeDeletion(ctx context.Context, ruleId int, userId int, ifMatch string) (*models.RuleProposal, error) {
+	rule, err := s.getRuleIfMatch(ctx, ruleId, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+	return s.proposalRepo.AddProposal(ctx, models.RuleProposal{
+		Action: models.RuleProposalDelete, RuleId: ruleId, RuleVersion: rule.Version, ProposedBy: userId,
+	})
+}
+
+func (s ruleProposalService) ProposeRollback(ctx context.Context, ruleId int, version int, userId int, ifMatch string) (*models.RuleProposal, error) {
+	rule, err := s.getRuleIfMatch(ctx, ruleId, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.rulesRepo.GetRuleVersion(ctx, ruleId, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRuleRollback(*rule, *target); err != nil {
+		return nil, err
+	}
+	return s.proposalRepo.AddProposal(ctx, models.RuleProposal{
+		Action: models.RuleProposalRollback, RuleId: ruleId, RuleVersion: rule.Version, Target: target, ProposedBy: userId,
+	})
+}
+
+func (s ruleProposalService) ProposeStatusChange(ctx context.Context, ruleId int, action models.RuleProposalAction, userId int, ifMatch string) (*models.RuleProposal, error) {
+	status := action.RuleStatus()
+	if status == "" {
+		return nil, fmt.Errorf("%q doesn't change the status of a rule", action)
+	}
+	rule, err := s.getRuleIfMatch(ctx, ruleId, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRuleTransition(*rule, status); err != nil {
+		return nil, err
+	}
+	return s.proposalRepo.AddProposal(ctx, models.RuleProposal{
+		Action: action, RuleId: ruleId, RuleVersion: rule.Version, ProposedBy: userId,
+	})
+}
+
+// getRuleIfMatch loads the rule the proposal is made against and checks it against an If-Match precondition
+func (s ruleProposalService) getRuleIfMatch(ctx context.Context, ruleId int, ifMatch string) (*models.Rule, error) {
+	rule, err := s.rulesRepo.GetRule(ctx, ruleId)
+	if err != nil {
+		return nil, err
+	}
+	if !utils.MatchesStrongETag(ifMatch, rule.ETag()) {
+		return nil, repo.ErrVersionMismatch
+	}
+	return rule, nil
+}
+
+func (s ruleProposalService) GetProposal(ctx context.Context, proposalId int) (*models.RuleProposal, error) {
+	return s.proposalRepo.GetProposal(ctx, proposalId)
+}
+
+func (s ruleProposalService) GetProposals(ctx context.Context, filter models.RuleProposalListRequest) ([]models.RuleProposal, error) {
+	return s.proposalRepo.GetProposals(ctx, filter)
+}
+
+func (s ruleProposalService) CommentProposal(ctx context.Context, proposalId int, userId int, comment string) (*models.RuleProposalComment, error) {
+	if _, err := s.proposalRepo.GetProposal(ctx, proposalId); err != nil {
+		return nil, err
+	}
+	return s.proposalRepo.AddComment(ctx, models.RuleProposalComment{ProposalId: proposalId, UserId: userId, Comment: comment})
+}
+
+func (s ruleProposalService) ApproveProposal(ctx context.Context, proposalId int, userId int) (*models.RuleProposal, error) {
+	proposal, err := s.proposalRepo.GetProposal(ctx, proposalId)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.ProposedBy == userId {
+		return nil, ErrSelfApproval
+	}
+
+	approved, err := s.proposalRepo.ApproveProposal(ctx, proposalId, userId)
+	if errors.Is(err, repo.ErrVersionMismatch) {
+		return nil, ErrProposalOutdated
+	}
+	return approved, err
+}
+
+func (s ruleProposalService) RejectProposal(ctx context.Context, proposalId int, userId int, reason string) (*models.RuleProposal, error) {
+	return s.proposalRepo.RejectProposal(ctx, proposalId, userId, reason)
+}
@@ -0,0 +1,221 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRuleProposalService_ProposeRule(t *testing.T) {
+	proposalRepo := repositories.NewMockRuleProposalRepository(t)
+	service := services.NewRuleProposalService(proposalRepo, repositories.NewMockRulesRepository(t))
+	ctx := context.Background()
+
+	rule := models.Rule{Title: "A", Description: "D", ApplicationCondition: `role == "student"`, EffectiveDate: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)}
+	proposalRepo.EXPECT().AddProposal(ctx, models.RuleProposal{Action: models.RuleProposalCreate, Rule: &rule, ProposedBy: 7}).
+		Return(&models.RuleProposal{Id: 1, Status: models.RuleProposalPending}, nil).Once()
+
+	proposal, err := service.ProposeRule(ctx, rule, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, proposal.Id)
+
+	// Like a rule created right away, it is in force from now on unless it has an effective date
+	proposalRepo.EXPECT().AddProposal(ctx, mock.MatchedBy(func(proposal models.RuleProposal) bool {
+		return !proposal.Rule.EffectiveDate.IsZero()
+	})).Return(&models.RuleProposal{Id: 2, Status: models.RuleProposalPending}, nil).Once()
+
+	_, err = service.ProposeRule(ctx, models.Rule{Title: "A", Description: "D", ApplicationCondition: "true"}, 7)
+	assert.NoError(t, err)
+
+	_, err = service.ProposeRule(ctx, models.Rule{Title: "A", ApplicationCondition: "students only"}, 7)
+	assert.ErrorIs(t, err, utils.ErrInvalidExpression)
+}
+
+func TestRuleProposalService_ProposeModification(t *testing.T) {
+	ctx := context.Background()
+	modification := models.RuleModify{Title: "New title"}
+
+	t.Run("against the current version", func(t *testing.T) {
+		proposalRepo := repositories.NewMockRuleProposalRepository(t)
+		rulesRepo := repositories.NewMockRulesRepository(t)
+		service := services.NewRuleProposalService(proposalRepo, rulesRepo)
+
+		rulesRepo.EXPECT().GetRule(ctx, 4).Return(&models.Rule{Id: 4, Version: 2, Status: models.RuleDraft}, nil)
+		proposalRepo.EXPECT().AddProposal(ctx, models.RuleProposal{
+			Action: models.RuleProposalModify, RuleId: 4, RuleVersion: 2, Modification: &modification, ProposedBy: 7,
+		}).Return(&models.RuleProposal{Id: 1}, nil)
+
+		_, err := service.ProposeModification(ctx, 4, modification, 7, `"2"`)
+		assert.NoError(t, err)
+	})
+
+	t.Run("stale If-Match", func(t *testing.T) {
+		rulesRepo := repositories.NewMockRulesRepository(t)
+		service := services.NewRuleProposalService(repositories.NewMockRuleProposalRepository(t), rulesRepo)
+
+		rulesRepo.EXPECT().GetRule(ctx, 4).Return(&models.Rule{Id: 4, Version: 3}, nil)
+
+		_, err := service.ProposeModification(ctx, 4, modification, 7, `"2"`)
+		assert.ErrorIs(t, err, repositories.ErrVersionMismatch)
+	})
+
+	t.Run("effective date of a rule in force", func(t *testing.T) {
+		rulesRepo := repositories.NewMockRulesRepository(t)
+		service := services.NewRuleProposalService(repositories.NewMockRuleProposalRepository(t), rulesRepo)
+
+		rulesRepo.EXPECT().GetRule(ctx, 4).Return(&models.Rule{Id: 4, Version: 2, Status: models.RuleActive}, nil)
+
+		date := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+		_, err := service.ProposeModification(ctx, 4, models.RuleModify{EffectiveDate: &date}, 7, "*")
+		assert.ErrorIs(t, err, services.ErrRuleInForce)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		service := services.NewRuleProposalService(repositories.NewMockRuleProposalRepository(t), repositories.NewMockRulesRepository(t))
+
+		_, err := service.ProposeModification(ctx, 4, models.RuleModify{}, 7, "*")
+		assert.ErrorIs(t, err, services.ErrEmptyModification)
+	})
+}
+
+func TestRuleProposalService_ProposeDeletion(t *testing.T) {
+	proposalRepo := repositories.NewMockRuleProposalRepository(t)
+	rulesRepo := repositories.NewMockRulesRepository(t)
+	service := services.NewRuleProposalService(proposalRepo, rulesRepo)
+	ctx := context.Background()
+
+	rulesRepo.EXPECT().GetRule(ctx, 4).Return(&models.Rule{Id: 4, Version: 2}, nil)
+	proposalRepo.EXPECT().AddProposal(ctx, models.RuleProposal{Action: models.RuleProposalDelete, RuleId: 4, RuleVersion: 2, ProposedBy: 7}).
+		Return(&models.RuleProposal{Id: 1}, nil)
+
+	_, err := service.ProposeDeletion(ctx, 4, 7, `"2"`)
+	assert.NoError(t, err)
+}
+
+func TestRuleProposalService_ProposeRollback(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("to an earlier version", func(t *testing.T) {
+		proposalRepo := repositories.NewMockRuleProposalRepository(t)
+		rulesRepo := repositories.NewMockRulesRepository(t)
+		service := services.NewRuleProposalService(proposalRepo, rulesRepo)
+
+		target := &models.RuleVersion{Rule: models.Rule{Id: 4, Title: "Old", Version: 1}}
+		rulesRepo.EXPECT().GetRule(ctx, 4).Return(&models.Rule{Id: 4, Version: 2, Status: models.RuleDraft}, nil)
+		rulesRepo.EXPECT().GetRuleVersion(ctx, 4, 1).Return(target, nil)
+		proposalRepo.EXPECT().AddProposal(ctx, models.RuleProposal{
+			Action: models.RuleProposalRollback, RuleId: 4, RuleVersion: 2, Target: target, ProposedBy: 7,
+		}).Return(&models.RuleProposal{Id: 1}, nil)
+
+		_, err := service.ProposeRollback(ctx, 4, 1, 7, `"2"`)
+		assert.NoError(t, err)
+	})
+
+	t.Run("to the current version", func(t *testing.T) {
+		rulesRepo := repositories.NewMockRulesRepository(t)
+		service := services.NewRuleProposalService(repositories.NewMockRuleProposalRepository(t), rulesRepo)
+
+		rulesRepo.EXPECT().GetRule(ctx, 4).Return(&models.Rule{Id: 4, Version: 2}, nil)
+		rulesRepo.EXPECT().GetRuleVersion(ctx, 4, 2).Return(&models.RuleVersion{Rule: models.Rule{Id: 4, Version: 2}}, nil)
+
+		_, err := service.ProposeRollback(ctx, 4, 2, 7, "*")
+		assert.ErrorIs(t, err, services.ErrRuleAlreadyAtVersion)
+	})
+}
+
+func TestRuleProposalService_ProposeStatusChange(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("publish a draft", func(t *testing.T) {
+		proposalRepo := repositories.NewMockRuleProposalRepository(t)
+		rulesRepo := repositories.NewMockRulesRepository(t)
+		service := services.NewRuleProposalService(proposalRepo, rulesRepo)
+
+		rulesRepo.EXPECT().GetRule(ctx, 4).Return(&models.Rule{Id: 4, Version: 2, Status: models.RuleDraft}, nil)
+		proposalRepo.EXPECT().AddProposal(ctx, models.RuleProposal{Action: models.RuleProposalPublish, RuleId: 4, RuleVersion: 2, ProposedBy: 7}).
+			Return(&models.RuleProposal{Id: 1}, nil)
+
+		_, err := service.ProposeStatusChange(ctx, 4, models.RuleProposalPublish, 7, `"2"`)
+		assert.NoError(t, err)
+	})
+
+	t.Run("retire a draft", func(t *testing.T) {
+		rulesRepo := repositories.NewMockRulesRepository(t)
+		service := services.NewRuleProposalService(repositories.NewMockRuleProposalRepository(t), rulesRepo)
+
+		rulesRepo.EXPECT().GetRule(ctx, 4).Return(&models.Rule{Id: 4, Version: 2, Status: models.RuleDraft}, nil)
+
+		_, err := service.ProposeStatusChange(ctx, 4, models.RuleProposalRetire, 7, "*")
+		assert.ErrorIs(t, err, services.ErrInvalidRuleTransition)
+	})
+
+	t.Run("not a status change", func(t *testing.T) {
+		service := services.NewRuleProposalService(repositories.NewMockRuleProposalRepository(t), repositories.NewMockRulesRepository(t))
+
+		_, err := service.ProposeStatusChange(ctx, 4, models.RuleProposalDelete, 7, "*")
+		assert.Error(t, err)
+	})
+}
+
+func TestRuleProposalService_ApproveProposal(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("by another admin", func(t *testing.T) {
+		proposalRepo := repositories.NewMockRuleProposalRepository(t)
+		service := services.NewRuleProposalService(proposalRepo, repositories.NewMockRulesRepository(t))
+
+		proposalRepo.EXPECT().GetProposal(ctx, 1).Return(&models.RuleProposal{Id: 1, ProposedBy: 7}, nil)
+		proposalRepo.EXPECT().ApproveProposal(ctx, 1, 8).Return(&models.RuleProposal{Id: 1, Status: models.RuleProposalApproved}, nil)
+
+		proposal, err := service.ApproveProposal(ctx, 1, 8)
+		assert.NoError(t, err)
+		assert.Equal(t, models.RuleProposalApproved, proposal.Status)
+	})
+
+	t.Run("by the proposer", func(t *testing.T) {
+		proposalRepo := repositories.NewMockRuleProposalRepository(t)
+		service := services.NewRuleProposalService(proposalRepo, repositories.NewMockRulesRepository(t))
+
+		proposalRepo.EXPECT().GetProposal(ctx, 1).Return(&models.RuleProposal{Id: 1, ProposedBy: 7}, nil)
+
+		_, err := service.ApproveProposal(ctx, 1, 7)
+		assert.ErrorIs(t, err, services.ErrSelfApproval)
+	})
+
+	t.Run("rule changed since", func(t *testing.T) {
+		proposalRepo := repositories.NewMockRuleProposalRepository(t)
+		service := services.NewRuleProposalService(proposalRepo, repositories.NewMockRulesRepository(t))
+
+		proposalRepo.EXPECT().GetProposal(ctx, 1).Return(&models.RuleProposal{Id: 1, ProposedBy: 7}, nil)
+		proposalRepo.EXPECT().ApproveProposal(ctx, 1, 8).Return(nil, repositories.ErrVersionMismatch)
+
+		_, err := service.ApproveProposal(ctx, 1, 8)
+		assert.ErrorIs(t, err, services.ErrProposalOutdated)
+	})
+}
+
+func TestRuleProposalService_CommentProposal(t *testing.T) {
+	proposalRepo := repositories.NewMockRuleProposalRepository(t)
+	service := services.NewRuleProposalService(proposalRepo, repositories.NewMockRulesRepository(t))
+	ctx := context.Background()
+
+	proposalRepo.EXPECT().GetProposal(ctx, 1).Return(&models.RuleProposal{Id: 1}, nil)
+	proposalRepo.EXPECT().AddComment(ctx, mock.MatchedBy(func(comment models.RuleProposalComment) bool {
+		return comment.ProposalId == 1 && comment.UserId == 8 && comment.Comment == "Why?"
+	})).Return(&models.RuleProposalComment{Id: 2}, nil)
+
+	comment, err := service.CommentProposal(ctx, 1, 8, "Why?")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, comment.Id)
+
+	proposalRepo.EXPECT().GetProposal(ctx, 2).Return(nil, repositories.ErrNotFound)
+	_, err = service.CommentProposal(ctx, 2, 8, "Why?")
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+}
@@ -2,47 +2,375 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/Ingenieria-de-Software-2-Gupo-14/go-core/pkg/log"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
 	repo "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
 )
 
 type RulesService interface {
 	CreateRule(ctx context.Context, rule models.Rule, userId int) error
-	DeleteRule(ctx context.Context, ruleId int, userId int) error
-	GetRules(ctx context.Context) ([]models.Rule, error)
-	ModifyRule(ctx context.Context, ruleId int, modification models.RuleModify, userId int) error
-	GetAudits(ctx context.Context) ([]models.Audit, error)
+	DeleteRule(ctx context.Context, ruleId int, userId int, ifMatch string) error
+	GetRule(ctx context.Context, ruleId int) (*models.Rule, error)
+	GetRules(ctx context.Context, filter models.RuleListRequest) ([]models.Rule, error)
+	ModifyRule(ctx context.Context, ruleId int, modification models.RuleModify, userId int, ifMatch string) error
+	GetAudits(ctx context.Context, request models.RuleAuditListRequest) ([]models.AuditData, models.Pagination, error)
+	GetRuleVersions(ctx context.Context, ruleId int) ([]models.RuleVersion, error)
+	// DiffRuleVersions compares a version of the rule against another one, by default the one before it
+	DiffRuleVersions(ctx context.Context, ruleId int, version int, against int) (*models.RuleDiff, error)
+	// RollbackRule restores the content of an earlier version as a new version of the rule
+	RollbackRule(ctx context.Context, ruleId int, version int, userId int, ifMatch string) error
+	// SetRuleStatus moves the rule along its lifecycle, see models.RuleStatus.CanTransitionTo
+	SetRuleStatus(ctx context.Context, ruleId int, status models.RuleStatus, userId int, ifMatch string) error
+	// ActivateDueRules activates the scheduled rules whose effective date has come and tells every user about them
+	ActivateDueRules(ctx context.Context) ([]models.Rule, error)
+	// RunScheduler activates the due rules every interval until the context is done
+	RunScheduler(ctx context.Context, interval time.Duration)
+	// AcceptRule records that the user accepted the current version of an active rule
+	AcceptRule(ctx context.Context, ruleId int, userId int, ifMatch string, ip string) (*models.RuleAcceptance, error)
+	// GetPendingRules returns the mandatory rules the user still has to accept
+	GetPendingRules(ctx context.Context, userId int) ([]models.PendingRule, error)
+	GetAcceptanceReport(ctx context.Context) (models.RuleAcceptanceReport, error)
+	// GetApplicableRules returns the active rules whose application condition holds for the user
+	GetApplicableRules(ctx context.Context, user models.User) (models.ApplicableRules, error)
+	// EvaluateCondition parses an application condition and evaluates it against the attributes of a user
+	EvaluateCondition(ctx context.Context, condition string, vars map[string]any) (bool, error)
 }
 
+var (
+	ErrRuleAlreadyAtVersion  = errors.New("rule is already at that version")
+	ErrInvalidRuleTransition = errors.New("invalid rule status change")
+	ErrRuleInForce           = errors.New("the effective date of a rule in force can't change")
+	ErrRuleNotActive         = errors.New("only active rules can be accepted")
+)
+
 type rulesService struct {
-	rulesRepo repo.RulesRepository
+	rulesRepo      repo.RulesRepository
+	acceptanceRepo repo.RuleAcceptanceRepository
+	now            func() time.Time
 }
 
 // NewRulesService creates and returns a database
-func NewRulesService(rulesRepo repo.RulesRepository) *rulesService {
-	return &rulesService{rulesRepo}
+func NewRulesService(rulesRepo repo.RulesRepository, acceptanceRepo repo.RuleAcceptanceRepository) *rulesService {
+	return &rulesService{rulesRepo: rulesRepo, acceptanceRepo: acceptanceRepo, now: time.Now}
 }
 
 func (s rulesService) CreateRule(ctx context.Context, rule models.Rule, userId int) error {
+	rule, err := normalizeNewRule(rule)
+	if err != nil {
+		return err
+	}
+	return s.rulesRepo.AddRule(ctx, rule, userId)
+}
+
+// normalizeNewRule normalizes and checks a rule to create, whether it's created now or proposed.
+// Without an effective date it is in force from now on.
+func normalizeNewRule(rule models.Rule) (models.Rule, error) {
+	rule.Normalize()
+	if err := checkNewRule(rule); err != nil {
+		return rule, err
+	}
 	if rule.EffectiveDate.IsZero() {
 		rule.EffectiveDate = time.Now()
 	}
-	return s.rulesRepo.AddRule(ctx, rule, userId)
+	return rule, nil
 }
 
-func (s rulesService) DeleteRule(ctx context.Context, ruleId int, userId int) error {
-	return s.rulesRepo.DeleteRule(ctx, ruleId, userId)
+func (s rulesService) DeleteRule(ctx context.Context, ruleId int, userId int, ifMatch string) error {
+	rule, err := s.getRuleIfMatch(ctx, ruleId, ifMatch)
+	if err != nil {
+		return err
+	}
+	return s.rulesRepo.DeleteRule(ctx, ruleId, userId, rule.Version)
 }
 
-func (s rulesService) GetRules(ctx context.Context) ([]models.Rule, error) {
-	return s.rulesRepo.GetRules(ctx)
+func (s rulesService) GetRule(ctx context.Context, ruleId int) (*models.Rule, error) {
+	return s.rulesRepo.GetRule(ctx, ruleId)
 }
-func (s rulesService) GetAudits(ctx context.Context) ([]models.Audit, error) {
-	return s.rulesRepo.GetAudit(ctx)
+
+// getRuleIfMatch loads the rule and checks it against an If-Match precondition,
+// returning repo.ErrVersionMismatch when the client's copy is stale.
+func (s rulesService) getRuleIfMatch(ctx context.Context, ruleId int, ifMatch string) (*models.Rule, error) {
+	rule, err := s.rulesRepo.GetRule(ctx, ruleId)
+	if err != nil {
+		return nil, err
+	}
+	if !utils.MatchesStrongETag(ifMatch, rule.ETag()) {
+		return nil, repo.ErrVersionMismatch
+	}
+	return rule, nil
 }
 
-func (s rulesService) ModifyRule(ctx context.Context, ruleId int, modification models.RuleModify, userId int) error {
-	return s.rulesRepo.ModifyRule(ctx, ruleId, modification, userId)
+func (s rulesService) GetRules(ctx context.Context, filter models.RuleListRequest) ([]models.Rule, error) {
+	// Tags are stored lowercase
+	for i, tag := range filter.Tags {
+		filter.Tags[i] = strings.ToLower(strings.TrimSpace(tag))
+	}
+	return s.rulesRepo.GetRules(ctx, filter)
+}
+
+// GetAudits returns the requested page of rules audit entries, newest first
+func (s rulesService) GetAudits(ctx context.Context, request models.RuleAuditListRequest) ([]models.AuditData, models.Pagination, error) {
+	page := models.NewPagination(request.Page, request.PageSize)
+	audits, total, err := s.rulesRepo.GetAudit(ctx, request.RuleAuditFilter, page.PageSize, page.Offset())
+	if err != nil {
+		return nil, models.Pagination{}, err
+	}
+	page.Total = total
+
+	data := make([]models.AuditData, 0, len(audits))
+	for _, audit := range audits {
+		data = append(data, audit.Data())
+	}
+	return data, page, nil
+}
+
+func (s rulesService) ModifyRule(ctx context.Context, ruleId int, modification models.RuleModify, userId int, ifMatch string) error {
+	rule, err := s.getRuleIfMatch(ctx, ruleId, ifMatch)
+	if err != nil {
+		return err
+	}
+	modification.Normalize()
+	if err := checkRuleModification(*rule, modification); err != nil {
+		return err
+	}
+	return s.rulesRepo.ModifyRule(ctx, ruleId, modification, userId, rule.Version)
+}
+
+func checkNewRule(rule models.Rule) error {
+	_, err := models.ParseRuleCondition(rule.ApplicationCondition)
+	return err
+}
+
+// checkRuleModification checks the modification can be applied to the rule
+func checkRuleModification(rule models.Rule, modification models.RuleModify) error {
+	if modification.EffectiveDate != nil && (rule.Status == models.RuleActive || rule.Status == models.RuleRetired) {
+		return ErrRuleInForce
+	}
+	if modification.ApplicationCondition != "" {
+		if _, err := models.ParseRuleCondition(modification.ApplicationCondition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetRuleVersions returns every version kept of the rule, newest first, even if it was deleted
+func (s rulesService) GetRuleVersions(ctx context.Context, ruleId int) ([]models.RuleVersion, error) {
+	versions, err := s.rulesRepo.GetRuleVersions(ctx, ruleId)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, repo.ErrNotFound
+	}
+	return versions, nil
+}
+
+func (s rulesService) DiffRuleVersions(ctx context.Context, ruleId int, version int, against int) (*models.RuleDiff, error) {
+	versions, err := s.GetRuleVersions(ctx, ruleId)
+	if err != nil {
+		return nil, err
+	}
+
+	var to, from *models.RuleVersion
+	for i := range versions {
+		if versions[i].Version == version {
+			to = &versions[i]
+			// Newest first, so the next one is the version before it
+			if against == 0 && i+1 < len(versions) {
+				from = &versions[i+1]
+			}
+		}
+		if against != 0 && versions[i].Version == against {
+			from = &versions[i]
+		}
+	}
+	if to == nil || (against != 0 && from == nil) {
+		return nil, repo.ErrNotFound
+	}
+	if from == nil {
+		// The first version kept is compared against an empty rule
+		from = &models.RuleVersion{Rule: models.Rule{Id: ruleId}}
+	}
+
+	diff := models.DiffRules(*from, *to)
+	return &diff, nil
+}
+
+func (s rulesService) RollbackRule(ctx context.Context, ruleId int, version int, userId int, ifMatch string) error {
+	rule, err := s.getRuleIfMatch(ctx, ruleId, ifMatch)
+	if err != nil {
+		return err
+	}
+	target, err := s.rulesRepo.GetRuleVersion(ctx, ruleId, version)
+	if err != nil {
+		return err
+	}
+	if err := checkRuleRollback(*rule, *target); err != nil {
+		return err
+	}
+	return s.rulesRepo.RollbackRule(ctx, ruleId, *target, userId, rule.Version)
+}
+
+// checkRuleRollback checks the rule can go back to the content of the target version.
+// Like a modification, it can't move the effective date of a rule in force.
+func checkRuleRollback(rule models.Rule, target models.RuleVersion) error {
+	if target.Version == rule.Version {
+		return ErrRuleAlreadyAtVersion
+	}
+	if !target.EffectiveDate.Equal(rule.EffectiveDate) && (rule.Status == models.RuleActive || rule.Status == models.RuleRetired) {
+		return ErrRuleInForce
+	}
+	return nil
+}
+
+func (s rulesService) SetRuleStatus(ctx context.Context, ruleId int, status models.RuleStatus, userId int, ifMatch string) error {
+	rule, err := s.getRuleIfMatch(ctx, ruleId, ifMatch)
+	if err != nil {
+		return err
+	}
+	if err := checkRuleTransition(*rule, status); err != nil {
+		return err
+	}
+	return s.rulesRepo.SetRuleStatus(ctx, ruleId, status, userId, rule.Version, nil)
+}
+
+func checkRuleTransition(rule models.Rule, status models.RuleStatus) error {
+	if !rule.Status.CanTransitionTo(status) {
+		return fmt.Errorf("%w: a %s rule can't become %s", ErrInvalidRuleTransition, rule.Status, status)
+	}
+	return nil
+}
+
+func (s rulesService) ActivateDueRules(ctx context.Context) ([]models.Rule, error) {
+	due, err := s.rulesRepo.GetDueRules(ctx, s.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	activated := []models.Rule{}
+	for _, rule := range due {
+		// Every user is told about the rule by a job queued along with the activation
+		outbox := func(int) ([]models.Job, error) {
+			job, err := models.NewBroadcastJob(ruleActiveNotification(rule))
+			return []models.Job{job}, err
+		}
+		err := s.rulesRepo.SetRuleStatus(ctx, rule.Id, models.RuleActive, 0, rule.Version, outbox)
+		if errors.Is(err, repo.ErrVersionMismatch) {
+			// Changed since it was read, or activated by another instance. It is looked at again on the next run.
+			continue
+		}
+		if err != nil {
+			return activated, err
+		}
+		rule.Status = models.RuleActive
+		activated = append(activated, rule)
+	}
+	return activated, nil
+}
+
+func ruleActiveNotification(rule models.Rule) models.NotifyRequest {
+	return models.NotifyRequest{
+		NotificationTitle: "New rule in force: " + rule.Title,
+		NotificationText:  rule.Description,
+		NotificationType:  models.RuleNotification,
+	}
+}
+
+func (s rulesService) RunScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			activated, err := s.ActivateDueRules(ctx)
+			if err != nil {
+				log.Error(ctx, "Error activating due rules", "error", err.Error())
+			}
+			for _, rule := range activated {
+				log.Info(ctx, "Activated rule", "rule_id", rule.Id)
+			}
+		}
+	}
+}
+
+func (s rulesService) AcceptRule(ctx context.Context, ruleId int, userId int, ifMatch string, ip string) (*models.RuleAcceptance, error) {
+	// The ETag proves the user is accepting the version they read
+	rule, err := s.getRuleIfMatch(ctx, ruleId, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+	if rule.Status != models.RuleActive {
+		return nil, ErrRuleNotActive
+	}
+
+	acceptance := models.RuleAcceptance{
+		UserId:      userId,
+		RuleId:      rule.Id,
+		RuleVersion: rule.Version,
+		IP:          ip,
+		AcceptedAt:  s.now().UTC(),
+	}
+	if err := s.acceptanceRepo.AddAcceptance(ctx, acceptance); err != nil {
+		return nil, err
+	}
+	return &acceptance, nil
+}
+
+func (s rulesService) GetPendingRules(ctx context.Context, userId int) ([]models.PendingRule, error) {
+	return s.acceptanceRepo.GetPendingRules(ctx, userId)
+}
+
+func (s rulesService) GetAcceptanceReport(ctx context.Context) (models.RuleAcceptanceReport, error) {
+	users, coverage, err := s.acceptanceRepo.GetAcceptanceCoverage(ctx)
+	if err != nil {
+		return models.RuleAcceptanceReport{}, err
+	}
+	return models.NewRuleAcceptanceReport(users, coverage), nil
+}
+
+func (s rulesService) GetApplicableRules(ctx context.Context, user models.User) (models.ApplicableRules, error) {
+	active, err := s.rulesRepo.GetRules(ctx, models.RuleListRequest{Status: models.RuleActive})
+	if err != nil {
+		return models.ApplicableRules{}, err
+	}
+
+	vars := models.RuleConditionVars(user)
+	applicable := models.ApplicableRules{UserId: user.Id, Rules: []models.Rule{}}
+	for _, rule := range active {
+		expression, err := models.ParseRuleCondition(rule.ApplicationCondition)
+		if err != nil {
+			applicable.Unevaluable = append(applicable.Unevaluable, rule.Id)
+			continue
+		}
+		applies, err := expression.Evaluate(vars)
+		if err != nil {
+			applicable.Unevaluable = append(applicable.Unevaluable, rule.Id)
+			continue
+		}
+		if applies {
+			applicable.Rules = append(applicable.Rules, rule)
+		}
+	}
+	return applicable, nil
+}
+
+func (s rulesService) EvaluateCondition(ctx context.Context, condition string, vars map[string]any) (bool, error) {
+	expression, err := models.ParseRuleCondition(condition)
+	if err != nil {
+		return false, err
+	}
+	return expression.Evaluate(vars)
 }
@@ -3,23 +3,27 @@ package services
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"slices"
 	"testing"
 	"time"
 )
 
 func TestNewRulesService(t *testing.T) {
 	mockRepo := repositories.NewMockRulesRepository(t)
-	service := NewRulesService(mockRepo)
+	service := NewRulesService(mockRepo, nil)
 	assert.NotNil(t, service)
 }
 
 func TestRulesService_CreateRule(t *testing.T) {
 	mockRepo := repositories.NewMockRulesRepository(t)
-	service := NewRulesService(mockRepo)
+	service := NewRulesService(mockRepo, nil)
 	c := context.Background()
 
 	userId := 1
@@ -28,7 +32,7 @@ func TestRulesService_CreateRule(t *testing.T) {
 		Title:                "title",
 		Description:          "description",
 		EffectiveDate:        time.Time{},
-		ApplicationCondition: "condition",
+		ApplicationCondition: `role == "student"`,
 	}
 
 	mockRepo.EXPECT().AddRule(c, mock.Anything, userId).Return(nil)
@@ -39,20 +43,32 @@ func TestRulesService_CreateRule(t *testing.T) {
 
 func TestRulesService_DeleteRule(t *testing.T) {
 	mockRepo := repositories.NewMockRulesRepository(t)
-	service := NewRulesService(mockRepo)
+	service := NewRulesService(mockRepo, nil)
 	c := context.Background()
 
 	userId := 1
 	ruleId := 1
-	mockRepo.EXPECT().DeleteRule(c, ruleId, userId).Return(nil)
+	mockRepo.EXPECT().GetRule(c, ruleId).Return(&models.Rule{Id: ruleId, Version: 2}, nil)
+	mockRepo.EXPECT().DeleteRule(c, ruleId, userId, 2).Return(nil)
 
-	err := service.DeleteRule(c, ruleId, userId)
+	err := service.DeleteRule(c, ruleId, userId, `"2"`)
 	assert.NoError(t, err)
 }
 
+func TestRulesService_DeleteRule_StaleETag(t *testing.T) {
+	mockRepo := repositories.NewMockRulesRepository(t)
+	service := NewRulesService(mockRepo, nil)
+	c := context.Background()
+
+	mockRepo.EXPECT().GetRule(c, 1).Return(&models.Rule{Id: 1, Version: 3}, nil)
+
+	err := service.DeleteRule(c, 1, 1, `"2"`)
+	assert.ErrorIs(t, err, repositories.ErrVersionMismatch)
+}
+
 func TestRulesService_GetRules(t *testing.T) {
 	mockRepo := repositories.NewMockRulesRepository(t)
-	service := NewRulesService(mockRepo)
+	service := NewRulesService(mockRepo, nil)
 	c := context.Background()
 
 	rule := models.Rule{
@@ -65,16 +81,43 @@ func TestRulesService_GetRules(t *testing.T) {
 
 	rules := []models.Rule{rule}
 
-	mockRepo.EXPECT().GetRules(c).Return(rules, nil)
+	mockRepo.EXPECT().GetRules(c, models.RuleListRequest{}).Return(rules, nil)
 
-	rulesResult, err := service.GetRules(c)
+	rulesResult, err := service.GetRules(c, models.RuleListRequest{})
 	assert.NoError(t, err)
 	assert.Equal(t, rules, rulesResult)
 }
 
+func TestRulesService_CreateRule_NormalizesTags(t *testing.T) {
+	mockRepo := repositories.NewMockRulesRepository(t)
+	service := NewRulesService(mockRepo, nil)
+	c := context.Background()
+
+	rule := models.Rule{Title: "title", Description: "description", ApplicationCondition: "true",
+		Tags: []string{"Exams", " exams ", "Data"}, Translations: map[string]models.RuleTranslation{"ES": {Title: "T", Description: "D"}}}
+
+	mockRepo.EXPECT().AddRule(c, mock.MatchedBy(func(rule models.Rule) bool {
+		_, translated := rule.Translations["es"]
+		return slices.Equal(rule.Tags, []string{"exams", "data"}) && translated
+	}), 1).Return(nil)
+
+	assert.NoError(t, service.CreateRule(c, rule, 1))
+}
+
+func TestRulesService_GetRules_ByTag(t *testing.T) {
+	mockRepo := repositories.NewMockRulesRepository(t)
+	service := NewRulesService(mockRepo, nil)
+	c := context.Background()
+
+	mockRepo.EXPECT().GetRules(c, models.RuleListRequest{Tags: []string{"exams"}}).Return(nil, nil)
+
+	_, err := service.GetRules(c, models.RuleListRequest{Tags: []string{" Exams"}})
+	assert.NoError(t, err)
+}
+
 func TestRulesService_GetAudits(t *testing.T) {
 	mockRepo := repositories.NewMockRulesRepository(t)
-	service := NewRulesService(mockRepo)
+	service := NewRulesService(mockRepo, nil)
 	c := context.Background()
 
 	audit := models.Audit{
@@ -85,17 +128,28 @@ func TestRulesService_GetAudits(t *testing.T) {
 		NatureOfModification: "modification",
 	}
 
-	audits := []models.Audit{audit}
-	mockRepo.EXPECT().GetAudit(c).Return(audits, nil)
+	audit.Action = models.RuleAuditUpdate
+	audit.After = json.RawMessage(`{"Title":"B"}`)
+	ruleId := 1
+	request := models.RuleAuditListRequest{RuleAuditFilter: models.RuleAuditFilter{RuleId: &ruleId}, Page: 2, PageSize: 10}
+	mockRepo.EXPECT().GetAudit(c, request.RuleAuditFilter, 10, 10).Return([]models.Audit{audit}, 11, nil)
 
-	auditsResult, err := service.GetAudits(c)
+	auditsResult, page, err := service.GetAudits(c, request)
 	assert.NoError(t, err)
-	assert.Equal(t, audits, auditsResult)
+	assert.Equal(t, []models.AuditData{{
+		Id:                   1,
+		RuleId:               1,
+		UserId:               1,
+		Action:               models.RuleAuditUpdate,
+		After:                json.RawMessage(`{"Title":"B"}`),
+		NatureOfModification: "modification",
+	}}, auditsResult)
+	assert.Equal(t, models.Pagination{Page: 2, PageSize: 10, Total: 11}, page)
 }
 
 func TestRulesService_ModifyRule(t *testing.T) {
 	mockRepo := repositories.NewMockRulesRepository(t)
-	service := NewRulesService(mockRepo)
+	service := NewRulesService(mockRepo, nil)
 	c := context.Background()
 
 	userId := 1
@@ -103,11 +157,362 @@ func TestRulesService_ModifyRule(t *testing.T) {
 	modification := models.RuleModify{
 		Title:                "title",
 		Description:          "description",
-		ApplicationCondition: "Application",
+		ApplicationCondition: `role in ["student", "teacher"]`,
+	}
+
+	mockRepo.EXPECT().GetRule(c, ruleId).Return(&models.Rule{Id: ruleId, Version: 4}, nil)
+	mockRepo.EXPECT().ModifyRule(c, ruleId, modification, userId, 4).Return(nil)
+
+	err := service.ModifyRule(c, ruleId, modification, userId, "*")
+	assert.NoError(t, err)
+}
+
+func TestRulesService_ModifyRule_NotFound(t *testing.T) {
+	mockRepo := repositories.NewMockRulesRepository(t)
+	service := NewRulesService(mockRepo, nil)
+	c := context.Background()
+
+	mockRepo.EXPECT().GetRule(c, 1).Return(nil, repositories.ErrNotFound)
+
+	err := service.ModifyRule(c, 1, models.RuleModify{Title: "title"}, 1, "*")
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+}
+
+func TestRulesService_DiffRuleVersions(t *testing.T) {
+	c := context.Background()
+	versions := []models.RuleVersion{
+		{Rule: models.Rule{Id: 1, Title: "C", Description: "Attend 80% of classes", Version: 3}},
+		{Rule: models.Rule{Id: 1, Title: "B", Description: "Attend 75% of classes", Version: 2}},
+		{Rule: models.Rule{Id: 1, Title: "B", Description: "Attend classes", Version: 1}},
 	}
 
-	mockRepo.EXPECT().ModifyRule(c, ruleId, modification, userId).Return(nil)
+	tests := []struct {
+		name          string
+		version       int
+		against       int
+		expectedFrom  int
+		expectedTitle bool
+		expectedErr   error
+	}{
+		{name: "against the previous version", version: 3, expectedFrom: 2, expectedTitle: true},
+		{name: "against a given version", version: 2, against: 1, expectedFrom: 1},
+		{name: "first version", version: 1, expectedFrom: 0, expectedTitle: true},
+		{name: "unknown version", version: 4, expectedErr: repositories.ErrNotFound},
+		{name: "unknown against version", version: 3, against: 9, expectedErr: repositories.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repositories.NewMockRulesRepository(t)
+			service := NewRulesService(mockRepo, nil)
+			mockRepo.EXPECT().GetRuleVersions(c, 1).Return(versions, nil)
+
+			diff, err := service.DiffRuleVersions(c, 1, tt.version, tt.against)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedFrom, diff.From)
+			assert.Equal(t, tt.version, diff.To)
+			assert.Equal(t, tt.expectedTitle, len(diff.Changes) == 2, "title and description changes: %v", diff.Changes)
+		})
+	}
+}
+
+func TestRulesService_DiffRuleVersions_Categories(t *testing.T) {
+	c := context.Background()
+	mockRepo := repositories.NewMockRulesRepository(t)
+	service := NewRulesService(mockRepo, nil)
+	mockRepo.EXPECT().GetRuleVersions(c, 1).Return([]models.RuleVersion{
+		{Rule: models.Rule{Id: 1, Title: "A", Version: 2, Category: models.RuleAcademic, Tags: []string{"exams"}}},
+		{Rule: models.Rule{Id: 1, Title: "A", Version: 1, Category: models.RuleGeneral}},
+	}, nil)
+
+	diff, err := service.DiffRuleVersions(c, 1, 2, 0)
 
-	err := service.ModifyRule(c, ruleId, modification, userId)
 	assert.NoError(t, err)
+	assert.Equal(t, []models.RuleFieldChange{
+		{Field: "category", From: models.RuleGeneral, To: models.RuleAcademic},
+		{Field: "tags", From: []string(nil), To: []string{"exams"}},
+	}, diff.Changes)
+}
+
+func TestRulesService_DiffRuleVersions_UnknownFields(t *testing.T) {
+	c := context.Background()
+	mockRepo := repositories.NewMockRulesRepository(t)
+	service := NewRulesService(mockRepo, nil)
+	mockRepo.EXPECT().GetRuleVersions(c, 1).Return([]models.RuleVersion{
+		{Rule: models.Rule{Id: 1, Title: "A", Version: 2, Mandatory: true, Category: models.RuleAcademic}},
+		// Recorded before categories and the rest were kept
+		{Rule: models.Rule{Id: 1, Title: "A", Version: 1}, UnknownFields: []string{"mandatory", "category", "tags", "translations"}},
+	}, nil)
+
+	diff, err := service.DiffRuleVersions(c, 1, 2, 0)
+
+	assert.NoError(t, err)
+	assert.Empty(t, diff.Changes)
+	assert.Equal(t, []string{"mandatory", "category", "tags", "translations"}, diff.UnknownFields)
+}
+
+func TestRulesService_GetRuleVersions_NotFound(t *testing.T) {
+	mockRepo := repositories.NewMockRulesRepository(t)
+	service := NewRulesService(mockRepo, nil)
+	c := context.Background()
+
+	mockRepo.EXPECT().GetRuleVersions(c, 1).Return([]models.RuleVersion{}, nil)
+
+	_, err := service.GetRuleVersions(c, 1)
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+}
+
+func TestRulesService_RollbackRule(t *testing.T) {
+	c := context.Background()
+	rule := &models.Rule{Id: 1, Title: "C", Version: 3}
+
+	t.Run("restores the version", func(t *testing.T) {
+		mockRepo := repositories.NewMockRulesRepository(t)
+		service := NewRulesService(mockRepo, nil)
+		target := &models.RuleVersion{Rule: models.Rule{Id: 1, Title: "A", Version: 1}}
+
+		mockRepo.EXPECT().GetRule(c, 1).Return(rule, nil)
+		mockRepo.EXPECT().GetRuleVersion(c, 1, 1).Return(target, nil)
+		mockRepo.EXPECT().RollbackRule(c, 1, *target, 7, 3).Return(nil)
+
+		assert.NoError(t, service.RollbackRule(c, 1, 1, 7, `"3"`))
+	})
+
+	t.Run("already at that version", func(t *testing.T) {
+		mockRepo := repositories.NewMockRulesRepository(t)
+		service := NewRulesService(mockRepo, nil)
+
+		mockRepo.EXPECT().GetRule(c, 1).Return(rule, nil)
+		mockRepo.EXPECT().GetRuleVersion(c, 1, 3).Return(&models.RuleVersion{Rule: *rule}, nil)
+
+		assert.ErrorIs(t, service.RollbackRule(c, 1, 3, 7, `"3"`), ErrRuleAlreadyAtVersion)
+	})
+
+	t.Run("effective date of a rule in force", func(t *testing.T) {
+		mockRepo := repositories.NewMockRulesRepository(t)
+		service := NewRulesService(mockRepo, nil)
+		active := &models.Rule{Id: 1, Title: "C", Version: 3, Status: models.RuleActive, EffectiveDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+		target := models.Rule{Id: 1, Title: "A", Version: 1, EffectiveDate: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)}
+
+		mockRepo.EXPECT().GetRule(c, 1).Return(active, nil)
+		mockRepo.EXPECT().GetRuleVersion(c, 1, 1).Return(&models.RuleVersion{Rule: target}, nil)
+
+		assert.ErrorIs(t, service.RollbackRule(c, 1, 1, 7, `"3"`), ErrRuleInForce)
+	})
+
+	t.Run("content of a rule in force", func(t *testing.T) {
+		mockRepo := repositories.NewMockRulesRepository(t)
+		service := NewRulesService(mockRepo, nil)
+		active := &models.Rule{Id: 1, Title: "C", Version: 3, Status: models.RuleActive, EffectiveDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+		target := &models.RuleVersion{Rule: models.Rule{Id: 1, Title: "A", Version: 1, EffectiveDate: active.EffectiveDate}}
+
+		mockRepo.EXPECT().GetRule(c, 1).Return(active, nil)
+		mockRepo.EXPECT().GetRuleVersion(c, 1, 1).Return(target, nil)
+		mockRepo.EXPECT().RollbackRule(c, 1, *target, 7, 3).Return(nil)
+
+		assert.NoError(t, service.RollbackRule(c, 1, 1, 7, `"3"`))
+	})
+
+	t.Run("stale etag", func(t *testing.T) {
+		mockRepo := repositories.NewMockRulesRepository(t)
+		service := NewRulesService(mockRepo, nil)
+
+		mockRepo.EXPECT().GetRule(c, 1).Return(rule, nil)
+
+		assert.ErrorIs(t, service.RollbackRule(c, 1, 1, 7, `"2"`), repositories.ErrVersionMismatch)
+	})
+}
+
+func TestRulesService_SetRuleStatus(t *testing.T) {
+	c := context.Background()
+
+	tests := []struct {
+		name        string
+		current     models.RuleStatus
+		status      models.RuleStatus
+		expectedErr error
+	}{
+		{name: "publish a draft", current: models.RuleDraft, status: models.RuleScheduled},
+		{name: "retire an active rule", current: models.RuleActive, status: models.RuleRetired},
+		{name: "publish an active rule", current: models.RuleActive, status: models.RuleScheduled, expectedErr: ErrInvalidRuleTransition},
+		{name: "retire a draft", current: models.RuleDraft, status: models.RuleRetired, expectedErr: ErrInvalidRuleTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repositories.NewMockRulesRepository(t)
+			service := NewRulesService(mockRepo, nil)
+
+			mockRepo.EXPECT().GetRule(c, 1).Return(&models.Rule{Id: 1, Version: 2, Status: tt.current}, nil)
+			if tt.expectedErr == nil {
+				mockRepo.EXPECT().SetRuleStatus(c, 1, tt.status, 7, 2, mock.Anything).Return(nil)
+			}
+
+			err := service.SetRuleStatus(c, 1, tt.status, 7, `"2"`)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRulesService_ModifyRule_EffectiveDateInForce(t *testing.T) {
+	mockRepo := repositories.NewMockRulesRepository(t)
+	service := NewRulesService(mockRepo, nil)
+	c := context.Background()
+
+	date := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.EXPECT().GetRule(c, 1).Return(&models.Rule{Id: 1, Version: 2, Status: models.RuleActive}, nil)
+
+	err := service.ModifyRule(c, 1, models.RuleModify{EffectiveDate: &date}, 7, `"2"`)
+	assert.ErrorIs(t, err, ErrRuleInForce)
+}
+
+func TestRulesService_ActivateDueRules(t *testing.T) {
+	mockRepo := repositories.NewMockRulesRepository(t)
+	service := NewRulesService(mockRepo, nil)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	c := context.Background()
+
+	due := []models.Rule{
+		{Id: 1, Title: "Attendance", Description: "Attend 75% of classes", Version: 2, Status: models.RuleScheduled},
+		{Id: 2, Title: "Changed meanwhile", Version: 5, Status: models.RuleScheduled},
+	}
+	mockRepo.EXPECT().GetDueRules(c, now).Return(due, nil)
+	// Every user is told about the rule by a job queued along with the activation
+	mockRepo.EXPECT().SetRuleStatus(c, 1, models.RuleActive, 0, 2, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ int, _ models.RuleStatus, _ int, _ int, outbox models.Outbox) error {
+			jobs, err := outbox(1)
+			require.NoError(t, err)
+			expected, err := models.NewBroadcastJob(models.NotifyRequest{
+				NotificationTitle: "New rule in force: Attendance",
+				NotificationText:  "Attend 75% of classes",
+				NotificationType:  models.RuleNotification,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []models.Job{expected}, jobs)
+			return nil
+		})
+	mockRepo.EXPECT().SetRuleStatus(c, 2, models.RuleActive, 0, 5, mock.Anything).Return(repositories.ErrVersionMismatch)
+
+	activated, err := service.ActivateDueRules(c)
+	assert.NoError(t, err)
+	assert.Len(t, activated, 1)
+	assert.Equal(t, 1, activated[0].Id)
+	assert.Equal(t, models.RuleActive, activated[0].Status)
+}
+
+func TestRulesService_AcceptRule(t *testing.T) {
+	c := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("accepts the current version", func(t *testing.T) {
+		mockRepo := repositories.NewMockRulesRepository(t)
+		acceptanceRepo := repositories.NewMockRuleAcceptanceRepository(t)
+		service := NewRulesService(mockRepo, acceptanceRepo)
+		service.now = func() time.Time { return now }
+
+		expected := models.RuleAcceptance{UserId: 7, RuleId: 1, RuleVersion: 4, IP: "10.0.0.1", AcceptedAt: now}
+		mockRepo.EXPECT().GetRule(c, 1).Return(&models.Rule{Id: 1, Version: 4, Status: models.RuleActive}, nil)
+		acceptanceRepo.EXPECT().AddAcceptance(c, expected).Return(nil)
+
+		acceptance, err := service.AcceptRule(c, 1, 7, `"4"`, "10.0.0.1")
+		assert.NoError(t, err)
+		assert.Equal(t, &expected, acceptance)
+	})
+
+	t.Run("rule not active", func(t *testing.T) {
+		mockRepo := repositories.NewMockRulesRepository(t)
+		service := NewRulesService(mockRepo, repositories.NewMockRuleAcceptanceRepository(t))
+
+		mockRepo.EXPECT().GetRule(c, 1).Return(&models.Rule{Id: 1, Version: 2, Status: models.RuleScheduled}, nil)
+
+		_, err := service.AcceptRule(c, 1, 7, `"2"`, "10.0.0.1")
+		assert.ErrorIs(t, err, ErrRuleNotActive)
+	})
+
+	t.Run("read an older version", func(t *testing.T) {
+		mockRepo := repositories.NewMockRulesRepository(t)
+		service := NewRulesService(mockRepo, repositories.NewMockRuleAcceptanceRepository(t))
+
+		mockRepo.EXPECT().GetRule(c, 1).Return(&models.Rule{Id: 1, Version: 4, Status: models.RuleActive}, nil)
+
+		_, err := service.AcceptRule(c, 1, 7, `"3"`, "10.0.0.1")
+		assert.ErrorIs(t, err, repositories.ErrVersionMismatch)
+	})
+}
+
+func TestRulesService_GetAcceptanceReport(t *testing.T) {
+	acceptanceRepo := repositories.NewMockRuleAcceptanceRepository(t)
+	service := NewRulesService(repositories.NewMockRulesRepository(t), acceptanceRepo)
+	c := context.Background()
+
+	acceptanceRepo.EXPECT().GetAcceptanceCoverage(c).Return(8, []models.RuleAcceptanceCoverage{
+		{RuleId: 1, Version: 4, Mandatory: true, Accepted: 6, AcceptedEarlierVersion: 1},
+	}, nil)
+
+	report, err := service.GetAcceptanceReport(c)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, report.Users)
+	assert.Equal(t, 2, report.Rules[0].Pending)
+	assert.Equal(t, 0.75, report.Rules[0].Coverage)
+}
+
+func TestRulesService_InvalidCondition(t *testing.T) {
+	mockRepo := repositories.NewMockRulesRepository(t)
+	service := NewRulesService(mockRepo, nil)
+	c := context.Background()
+
+	err := service.CreateRule(c, models.Rule{Title: "title", ApplicationCondition: "only students"}, 1)
+	assert.ErrorIs(t, err, utils.ErrInvalidExpression)
+
+	err = service.CreateRule(c, models.Rule{Title: "title", ApplicationCondition: `age > 18`}, 1)
+	assert.ErrorContains(t, err, `unknown attribute "age"`)
+
+	mockRepo.EXPECT().GetRule(c, 1).Return(&models.Rule{Id: 1, Version: 4}, nil)
+	err = service.ModifyRule(c, 1, models.RuleModify{ApplicationCondition: `role == `}, 1, "*")
+	assert.ErrorIs(t, err, utils.ErrInvalidExpression)
+}
+
+func TestRulesService_GetApplicableRules(t *testing.T) {
+	mockRepo := repositories.NewMockRulesRepository(t)
+	service := NewRulesService(mockRepo, nil)
+	c := context.Background()
+
+	user := models.User{Id: 7, Role: "student", Verified: true, CreatedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)}
+	mockRepo.EXPECT().GetRules(c, models.RuleListRequest{Status: models.RuleActive}).Return([]models.Rule{
+		{Id: 1, ApplicationCondition: `role == "student" && created_at < "2026-01-01"`},
+		{Id: 2, ApplicationCondition: `role == "teacher"`},
+		{Id: 3, ApplicationCondition: "Applies to every student"},
+		{Id: 4, ApplicationCondition: `true`},
+	}, nil)
+
+	applicable, err := service.GetApplicableRules(c, user)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, applicable.UserId)
+	assert.Len(t, applicable.Rules, 2)
+	assert.Equal(t, 1, applicable.Rules[0].Id)
+	assert.Equal(t, 4, applicable.Rules[1].Id)
+	assert.Equal(t, []int{3}, applicable.Unevaluable)
+}
+
+func TestRulesService_EvaluateCondition(t *testing.T) {
+	service := NewRulesService(repositories.NewMockRulesRepository(t), nil)
+	c := context.Background()
+	vars := models.RuleConditionVars(models.User{Id: 7, Role: "student"})
+
+	applies, err := service.EvaluateCondition(c, `role == "student" && id == 7`, vars)
+	assert.NoError(t, err)
+	assert.True(t, applies)
+
+	_, err = service.EvaluateCondition(c, `role == 7`, vars)
+	assert.ErrorIs(t, err, utils.ErrInvalidExpression)
 }
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	repo "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+)
+
+// StatsCacheTTL is how long computed statistics are served before being computed again
+const StatsCacheTTL = time.Minute
+
+type StatsService interface {
+	GetStats(ctx context.Context, request models.StatsRequest) (*models.AdminStats, error)
+}
+
+type cachedStats struct {
+	stats   *models.AdminStats
+	expires time.Time
+}
+
+type statsService struct {
+	statsRepo repo.StatsRepository
+	ttl       time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedStats
+}
+
+func NewStatsService(statsRepo repo.StatsRepository, ttl time.Duration) *statsService {
+	return &statsService{statsRepo: statsRepo, ttl: ttl, cache: make(map[string]cachedStats)}
+}
+
+// GetStats returns the statistics for the requested days, the last StatsDefaultDays by default.
+// Results are cached for a short time, so they can be slightly behind.
+func (s *statsService) GetStats(ctx context.Context, request models.StatsRequest) (*models.AdminStats, error) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	to := today
+	if request.To != nil {
+		to = request.To.UTC()
+	}
+	from := to.AddDate(0, 0, 1-models.StatsDefaultDays)
+	if request.From != nil {
+		from = request.From.UTC()
+	}
+	if from.After(to) || to.Sub(from) >= models.StatsMaxDays*24*time.Hour {
+		return nil, models.ErrInvalidStatsRange
+	}
+	interval := request.Interval
+	if interval == "" {
+		interval = models.StatsIntervalDay
+	}
+
+	// Keyed by the resolved range, so requests relying on the defaults share the entry
+	key := from.Format(time.DateOnly) + "/" + to.Format(time.DateOnly) + "/" + interval
+	s.mu.Lock()
+	if entry, ok := s.cache[key]; ok && now.Before(entry.expires) {
+		s.mu.Unlock()
+		return entry.stats, nil
+	}
+	s.mu.Unlock()
+
+	stats, err := s.statsRepo.GetStats(ctx, from, to.AddDate(0, 0, 1), interval)
+	if err != nil {
+		return nil, err
+	}
+	stats.From = from
+	stats.To = to
+	stats.Interval = interval
+	stats.GeneratedAt = now
+
+	s.mu.Lock()
+	for k, entry := range s.cache {
+		if !now.Before(entry.expires) {
+			delete(s.cache, k)
+		}
+	}
+	s.cache[key] = cachedStats{stats: stats, expires: now.Add(s.ttl)}
+	s.mu.Unlock()
+
+	return stats, nil
+}
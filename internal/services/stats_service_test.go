@@ -0,0 +1,86 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsService_GetStats_UsesRangeAndCaches(t *testing.T) {
+	mockRepo := repositories.NewMockStatsRepository(t)
+	service := services.NewStatsService(mockRepo, time.Minute)
+	ctx := context.Background()
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	mockRepo.EXPECT().
+		GetStats(ctx, from, to.AddDate(0, 0, 1), models.StatsIntervalDay).
+		Return(&models.AdminStats{Users: models.UserStats{Total: 5}}, nil).
+		Once()
+
+	request := models.StatsRequest{From: &from, To: &to}
+	first, err := service.GetStats(ctx, request)
+	require.NoError(t, err)
+	second, err := service.GetStats(ctx, request)
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+	assert.Equal(t, from, first.From)
+	assert.Equal(t, to, first.To)
+	assert.Equal(t, models.StatsIntervalDay, first.Interval)
+	assert.Equal(t, 5, first.Users.Total)
+}
+
+func TestStatsService_GetStats_DefaultRange(t *testing.T) {
+	mockRepo := repositories.NewMockStatsRepository(t)
+	service := services.NewStatsService(mockRepo, 0)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	mockRepo.EXPECT().
+		GetStats(ctx, today.AddDate(0, 0, 1-models.StatsDefaultDays), today.AddDate(0, 0, 1), models.StatsIntervalWeek).
+		Return(&models.AdminStats{}, nil).
+		Twice()
+
+	// Without a TTL nothing is cached
+	_, err := service.GetStats(ctx, models.StatsRequest{Interval: models.StatsIntervalWeek})
+	require.NoError(t, err)
+	_, err = service.GetStats(ctx, models.StatsRequest{Interval: models.StatsIntervalWeek})
+	require.NoError(t, err)
+}
+
+func TestStatsService_GetStats_InvalidRange(t *testing.T) {
+	service := services.NewStatsService(repositories.NewMockStatsRepository(t), time.Minute)
+
+	from := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	_, err := service.GetStats(context.Background(), models.StatsRequest{From: &from, To: &to})
+	assert.ErrorIs(t, err, models.ErrInvalidStatsRange)
+
+	from = to.AddDate(-2, 0, 0)
+	_, err = service.GetStats(context.Background(), models.StatsRequest{From: &from, To: &to})
+	assert.ErrorIs(t, err, models.ErrInvalidStatsRange)
+}
+
+func TestStatsService_GetStats_ErrorIsNotCached(t *testing.T) {
+	mockRepo := repositories.NewMockStatsRepository(t)
+	service := services.NewStatsService(mockRepo, time.Minute)
+	ctx := context.Background()
+
+	mockRepo.EXPECT().GetStats(ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db error")).Once()
+	mockRepo.EXPECT().GetStats(ctx, mock.Anything, mock.Anything, mock.Anything).Return(&models.AdminStats{}, nil).Once()
+
+	_, err := service.GetStats(ctx, models.StatsRequest{})
+	assert.Error(t, err)
+	_, err = service.GetStats(ctx, models.StatsRequest{})
+	assert.NoError(t, err)
+}
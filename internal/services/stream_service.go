@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/go-core/pkg/log"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	repo "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+)
+
+// StreamService is the hub of the live streams of users. The database announces the user events to
+// every instance of the API, and each one forwards them to the streams connected to it.
+type StreamService interface {
+	// Subscribe returns the events of the user from now on. The channel is closed by cancel, or when the
+	// subscriber falls behind, the client then reconnects and resumes from its last inbox entry.
+	Subscribe(userId int) (<-chan models.UserEvent, func())
+	// Missed returns the events of the inbox entries the user got after the one with the id, oldest first
+	Missed(ctx context.Context, userId int, lastEventId int) ([]models.UserEvent, error)
+	// Run listens for the user events and forwards them to the subscribers until the context is done
+	Run(ctx context.Context)
+}
+
+const (
+	// StreamBuffer is how many events a subscriber can fall behind before it's dropped
+	StreamBuffer = 16
+	// StreamResumeLimit is how many missed inbox entries are sent when a stream resumes
+	StreamResumeLimit = 100
+	// streamListenRetry is the wait before listening again when it fails
+	streamListenRetry = 5 * time.Second
+)
+
+type streamService struct {
+	listener  repo.UserEventListener
+	inboxRepo repo.InboxRepository
+
+	mu          sync.Mutex
+	subscribers map[int]map[chan models.UserEvent]struct{}
+}
+
+func NewStreamService(listener repo.UserEventListener, inboxRepo repo.InboxRepository) *streamService {
+	return &streamService{
+		listener:    listener,
+		inboxRepo:   inboxRepo,
+		subscribers: map[int]map[chan models.UserEvent]struct{}{},
+	}
+}
+
+func (s *streamService) Subscribe(userId int) (<-chan models.UserEvent, func()) {
+	events := make(chan models.UserEvent, StreamBuffer)
+	s.mu.Lock()
+	if s.subscribers[userId] == nil {
+		s.subscribers[userId] = map[chan models.UserEvent]struct{}{}
+	}
+	s.subscribers[userId][events] = struct{}{}
+	s.mu.Unlock()
+
+	return events, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.drop(userId, events)
+	}
+}
+
+// drop removes the subscriber and closes its channel, unless it was dropped already. The lock must be held.
+func (s *streamService) drop(userId int, events chan models.UserEvent) {
+	if _, ok := s.subscribers[userId][events]; !ok {
+		return
+	}
+	delete(s.subscribers[userId], events)
+	if len(s.subscribers[userId]) == 0 {
+		delete(s.subscribers, userId)
+	}
+	close(events)
+}
+
+func (s *streamService) Missed(ctx context.Context, userId int, lastEventId int) ([]models.UserEvent, error) {
+	entries, err := s.inboxRepo.GetInboxEntriesAfter(ctx, userId, lastEventId, StreamResumeLimit)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]models.UserEvent, 0, len(entries))
+	for _, entry := range entries {
+		events = append(events, models.NewInboxEvent(entry))
+	}
+	return events, nil
+}
+
+func (s *streamService) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := s.listener.Listen(ctx, func(event models.UserEvent) { s.publish(ctx, event) }, s.dropAll)
+		if err == nil {
+			return
+		}
+		log.Error(ctx, "Error listening for user events", "error", err.Error())
+		select {
+		case <-ctx.Done():
+		case <-time.After(streamListenRetry):
+		}
+	}
+}
+
+// publish sends the event to the subscribers of the user, dropping the ones that fell behind
+func (s *streamService) publish(ctx context.Context, event models.UserEvent) {
+	if !s.subscribed(event.UserId) {
+		return
+	}
+	if event.Type == models.UserEventInbox {
+		entry, err := s.inboxRepo.GetInboxEntry(ctx, event.UserId, event.Id)
+		if errors.Is(err, repo.ErrNotFound) {
+			// Deleted before it could be streamed
+			return
+		}
+		if err != nil {
+			log.Error(ctx, "Error loading inbox entry to stream", "user_id", event.UserId, "id", event.Id, "error", err.Error())
+			return
+		}
+		event = models.NewInboxEvent(*entry)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for events := range s.subscribers[event.UserId] {
+		select {
+		case events <- event:
+		default:
+			s.drop(event.UserId, events)
+		}
+	}
+}
+
+func (s *streamService) subscribed(userId int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscribers[userId]) > 0
+}
+
+// dropAll ends every stream, for their clients to reconnect and get the events that were lost
+func (s *streamService) dropAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for userId, subscribers := range s.subscribers {
+		for events := range subscribers {
+			s.drop(userId, events)
+		}
+	}
+}
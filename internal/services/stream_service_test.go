@@ -0,0 +1,114 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// runStream runs the hub with a listener that announces the events, and then calls reconnected if asked
+func runStream(t *testing.T, service services.StreamService, listener *repositories.MockUserEventListener, reconnect bool, events ...models.UserEvent) {
+	t.Helper()
+	listener.EXPECT().Listen(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, fn func(models.UserEvent), reconnected func()) error {
+			for _, event := range events {
+				fn(event)
+			}
+			if reconnect {
+				reconnected()
+			}
+			return nil
+		}).Once()
+	service.Run(context.Background())
+}
+
+func TestStreamService_Publish(t *testing.T) {
+	listener := repositories.NewMockUserEventListener(t)
+	inboxRepo := repositories.NewMockInboxRepository(t)
+	service := services.NewStreamService(listener, inboxRepo)
+
+	first, cancelFirst := service.Subscribe(3)
+	defer cancelFirst()
+	second, cancelSecond := service.Subscribe(3)
+	defer cancelSecond()
+	other, cancelOther := service.Subscribe(4)
+	defer cancelOther()
+
+	entry := models.InboxEntry{Id: 12, UserId: 3, Title: "Exam"}
+	inboxRepo.EXPECT().GetInboxEntry(mock.Anything, 3, 12).Return(&entry, nil)
+
+	runStream(t, service, listener, false,
+		models.UserEvent{Id: 12, UserId: 3, Type: models.UserEventInbox},
+		models.UserEvent{UserId: 3, Type: models.UserEventLogout},
+		// Nobody is subscribed to the user, the entry isn't loaded
+		models.UserEvent{Id: 13, UserId: 5, Type: models.UserEventInbox})
+
+	for _, events := range []<-chan models.UserEvent{first, second} {
+		event := <-events
+		assert.Equal(t, models.NewInboxEvent(entry), event)
+		event = <-events
+		assert.Equal(t, models.UserEventLogout, event.Type)
+	}
+	assert.Empty(t, other)
+}
+
+func TestStreamService_DropsSlowSubscribers(t *testing.T) {
+	listener := repositories.NewMockUserEventListener(t)
+	service := services.NewStreamService(listener, repositories.NewMockInboxRepository(t))
+
+	events, cancel := service.Subscribe(3)
+	defer cancel()
+
+	blocks := make([]models.UserEvent, services.StreamBuffer+1)
+	for i := range blocks {
+		blocks[i] = models.UserEvent{UserId: 3, Type: models.UserEventBlock}
+	}
+	runStream(t, service, listener, false, blocks...)
+
+	// The buffer is delivered and then the channel is closed, the client resumes after reconnecting
+	received := 0
+	for range events {
+		received++
+	}
+	assert.Equal(t, services.StreamBuffer, received)
+}
+
+func TestStreamService_DropsAllOnReconnect(t *testing.T) {
+	listener := repositories.NewMockUserEventListener(t)
+	service := services.NewStreamService(listener, repositories.NewMockInboxRepository(t))
+
+	first, cancelFirst := service.Subscribe(3)
+	defer cancelFirst()
+	second, cancelSecond := service.Subscribe(4)
+	defer cancelSecond()
+
+	runStream(t, service, listener, true)
+
+	_, open := <-first
+	assert.False(t, open)
+	_, open = <-second
+	assert.False(t, open)
+}
+
+func TestStreamService_Missed(t *testing.T) {
+	inboxRepo := repositories.NewMockInboxRepository(t)
+	service := services.NewStreamService(repositories.NewMockUserEventListener(t), inboxRepo)
+	ctx := context.Background()
+
+	inboxRepo.EXPECT().GetInboxEntriesAfter(ctx, 3, 10, services.StreamResumeLimit).
+		Return([]models.InboxEntry{{Id: 11, UserId: 3}, {Id: 12, UserId: 3}}, nil)
+
+	events, err := service.Missed(ctx, 3, 10)
+
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, 11, events[0].Id)
+	assert.Equal(t, models.UserEventInbox, events[1].Type)
+	assert.Equal(t, 12, events[1].Entry.Id)
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	repo "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+)
+
+type UserBulkService interface {
+	RunBulkAction(ctx context.Context, actorId int, request models.BulkUserActionRequest) (*models.BulkUserActionReport, error)
+}
+
+type userBulkService struct {
+	bulkRepo repo.UserBulkRepository
+}
+
+func NewUserBulkService(bulkRepo repo.UserBulkRepository) *userBulkService {
+	return &userBulkService{bulkRepo: bulkRepo}
+}
+
+// RunBulkAction validates the request and applies it on behalf of the admin with id actorId
+func (s *userBulkService) RunBulkAction(ctx context.Context, actorId int, request models.BulkUserActionRequest) (*models.BulkUserActionReport, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	action := models.BulkUserAction{
+		ActorId: actorId,
+		Action:  request.Action,
+		Ids:     request.Ids,
+		Filter:  request.Filter,
+	}
+	switch request.Action {
+	case models.BulkActionBlock:
+		action.Reason = request.Reason
+		if request.DurationMinutes > 0 {
+			until := time.Now().UTC().Add(time.Duration(request.DurationMinutes) * time.Minute)
+			action.BlockedUntil = &until
+		}
+	case models.BulkActionChangeRole:
+		action.Role = request.Role
+	}
+
+	return s.bulkRepo.RunBulkAction(ctx, action)
+}
@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserBulkService_RunBulkAction_Block(t *testing.T) {
+	mockRepo := repositories.NewMockUserBulkRepository(t)
+	service := services.NewUserBulkService(mockRepo)
+	ctx := context.Background()
+
+	report := &models.BulkUserActionReport{AuditId: 1}
+	mockRepo.EXPECT().
+		RunBulkAction(ctx, mock.MatchedBy(func(action models.BulkUserAction) bool {
+			return action.ActorId == 7 && action.Action == models.BulkActionBlock && action.Reason == "cheating" &&
+				action.BlockedUntil != nil && time.Until(*action.BlockedUntil) > 59*time.Minute && action.Role == ""
+		})).
+		Return(report, nil)
+
+	result, err := service.RunBulkAction(ctx, 7, models.BulkUserActionRequest{
+		Ids: []int{1, 2}, Action: models.BulkActionBlock, Reason: "cheating", DurationMinutes: 60, Role: "admin",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, report, result)
+}
+
+func TestUserBulkService_RunBulkAction_PermanentBlock(t *testing.T) {
+	mockRepo := repositories.NewMockUserBulkRepository(t)
+	service := services.NewUserBulkService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.EXPECT().
+		RunBulkAction(ctx, models.BulkUserAction{ActorId: 7, Action: models.BulkActionBlock, Ids: []int{1}, Reason: "spam"}).
+		Return(&models.BulkUserActionReport{}, nil)
+
+	_, err := service.RunBulkAction(ctx, 7, models.BulkUserActionRequest{Ids: []int{1}, Action: models.BulkActionBlock, Reason: "spam"})
+
+	assert.NoError(t, err)
+}
+
+func TestUserBulkService_RunBulkAction_Invalid(t *testing.T) {
+	service := services.NewUserBulkService(repositories.NewMockUserBulkRepository(t))
+
+	_, err := service.RunBulkAction(context.Background(), 7, models.BulkUserActionRequest{Ids: []int{1}, Action: models.BulkActionChangeRole})
+
+	assert.ErrorIs(t, err, models.ErrBulkMissingRole)
+}
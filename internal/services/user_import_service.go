@@ -0,0 +1,361 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/go-core/pkg/log"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	repo "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/sethvargo/go-password/password"
+)
+
+type UserImportService interface {
+	Import(ctx context.Context, rows []models.UserImportRow, opts models.UserImportOptions) (*models.UserImportReport, error)
+	StartImportJob(ctx context.Context, createdBy int, rows []models.UserImportRow, opts models.UserImportOptions) (int, error)
+	GetImportJob(ctx context.Context, id int) (*models.UserImportJob, error)
+	// RunRecovery fails the jobs left running by an instance that went down, once at startup
+	// and then periodically until the context is done
+	RunRecovery(ctx context.Context)
+}
+
+const (
+	// ImportJobHeartbeat is how often a running import job records that it's still alive
+	ImportJobHeartbeat = 30 * time.Second
+	// ImportJobStaleAfter is how long a running job can go without a heartbeat before it's taken as lost
+	ImportJobStaleAfter = 3 * ImportJobHeartbeat
+)
+
+// ErrImportJobInterrupted is the failure recorded for jobs whose instance stopped before they finished
+var ErrImportJobInterrupted = errors.New("the import was interrupted before finishing, it has to be started again")
+
+type userImportService struct {
+	importRepo      repo.UserImportRepository
+	userService     UserService
+	passwordService PasswordService
+}
+
+func NewUserImportService(importRepo repo.UserImportRepository, userService UserService, passwordService PasswordService) *userImportService {
+	return &userImportService{importRepo: importRepo, userService: userService, passwordService: passwordService}
+}
+
+// pendingUser is a row that passed validation, waiting to be created
+type pendingUser struct {
+	result *models.UserImportRowResult
+	user   *models.User
+}
+
+// Import validates every row and, unless it is a dry run, creates the valid users. In transactional
+// mode nothing is created if a single row fails, in best effort mode every valid row is created.
+func (s *userImportService) Import(ctx context.Context, rows []models.UserImportRow, opts models.UserImportOptions) (*models.UserImportReport, error) {
+	if opts.Mode == "" {
+		opts.Mode = models.ImportModeTransactional
+	}
+	report := &models.UserImportReport{
+		DryRun: opts.DryRun,
+		Mode:   opts.Mode,
+		Total:  len(rows),
+		Rows:   make([]models.UserImportRowResult, len(rows)),
+	}
+
+	pending, err := s.validateRows(ctx, rows, report)
+	if err != nil {
+		return nil, err
+	}
+	report.Valid = len(pending)
+	report.Failed = report.Total - report.Valid
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	for _, p := range pending {
+		if p.user.Password, err = s.passwordService.HashPassword(p.user.Password); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Mode == models.ImportModeTransactional {
+		err = s.createAll(ctx, pending, report)
+	} else {
+		s.createEach(ctx, pending, report)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.SendInvitations {
+		for _, p := range pending {
+			if p.result.Status != models.ImportRowCreated {
+				continue
+			}
+			if err := s.userService.SendInvitation(ctx, p.user); err != nil {
+				log.Warn(ctx, "Error sending invitation", "user_id", p.user.Id, "error", err.Error())
+				p.result.Errors = append(p.result.Errors, "invitation email could not be sent")
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// StartImportJob runs the import in the background and returns the id of the job that tracks it
+func (s *userImportService) StartImportJob(ctx context.Context, createdBy int, rows []models.UserImportRow, opts models.UserImportOptions) (int, error) {
+	id, err := s.importRepo.CreateImportJob(ctx, createdBy)
+	if err != nil {
+		return 0, err
+	}
+
+	// The job outlives the request, so it can't be cancelled with it
+	jobCtx := context.WithoutCancel(ctx)
+	go func() {
+		stop := s.keepAlive(jobCtx, id)
+		report, err := s.Import(jobCtx, rows, opts)
+		stop()
+		if err := s.importRepo.FinishImportJob(jobCtx, id, report, err); err != nil {
+			log.Error(jobCtx, "Error saving import job result", "job_id", id, "error", err.Error())
+		}
+	}()
+
+	return id, nil
+}
+
+func (s *userImportService) GetImportJob(ctx context.Context, id int) (*models.UserImportJob, error) {
+	return s.importRepo.GetImportJob(ctx, id)
+}
+
+// keepAlive refreshes the heartbeat of a job until the returned function is called
+func (s *userImportService) keepAlive(ctx context.Context, id int) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(ImportJobHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.importRepo.TouchImportJob(ctx, id); err != nil && ctx.Err() == nil {
+					log.Warn(ctx, "Error refreshing import job heartbeat", "job_id", id, "error", err.Error())
+				}
+			}
+		}
+	}()
+	return cancel
+}
+
+// RunRecovery fails the stale jobs every heartbeat. The rows of a job only live in the memory of the
+// instance running it, so a lost job can't be resumed, and jobs of other live instances never go stale.
+func (s *userImportService) RunRecovery(ctx context.Context) {
+	ticker := time.NewTicker(ImportJobHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		failed, err := s.importRepo.FailStaleImportJobs(ctx, ImportJobStaleAfter, ErrImportJobInterrupted.Error())
+		if err != nil {
+			log.Error(ctx, "Error failing interrupted import jobs", "error", err.Error())
+		} else if failed > 0 {
+			log.Warn(ctx, "Failed interrupted import jobs", "count", failed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// validateRows fills the report with the outcome of validating each row and returns the valid ones
+func (s *userImportService) validateRows(ctx context.Context, rows []models.UserImportRow, report *models.UserImportReport) ([]pendingUser, error) {
+	var pending []pendingUser
+	firstLine := make(map[string]int)
+
+	for i, row := range rows {
+		result := &report.Rows[i]
+		*result = models.UserImportRowResult{Line: row.Line, Email: row.Email, Status: models.ImportRowValid}
+
+		if row.ParseError != "" {
+			result.Errors = []string{row.ParseError}
+			continue
+		}
+
+		request := models.CreateUserRequest{
+			Email:    row.Email,
+			Password: row.Password,
+			Name:     row.Name,
+			Surname:  row.Surname,
+			Role:     row.Role,
+			Verified: true,
+		}
+		if request.Role == "" {
+			request.Role = "student"
+		}
+		generated := request.Password == ""
+		if generated {
+			var err error
+			if request.Password, err = password.Generate(24, 4, 4, false, true); err != nil {
+				return nil, err
+			}
+		}
+
+		result.Errors = validationMessages(binding.Validator.ValidateStruct(request))
+		if !generated {
+			var policyErrs models.ValidationErrors
+			if errors.As(s.passwordService.CheckPolicy(request.Password, []string{request.Name, request.Surname, request.Email}), &policyErrs) {
+				for _, fieldErr := range policyErrs {
+					result.Errors = append(result.Errors, fieldErr.Field+": "+fieldErr.Message)
+				}
+			}
+		}
+
+		key := strings.ToLower(request.Email)
+		if line, seen := firstLine[key]; seen && key != "" {
+			result.Errors = append(result.Errors, fmt.Sprintf("email: already used in line %d", line))
+		} else {
+			firstLine[key] = row.Line
+		}
+
+		if len(result.Errors) == 0 {
+			pending = append(pending, pendingUser{
+				result: result,
+				user: &models.User{
+					Email:    request.Email,
+					Password: request.Password,
+					Name:     request.Name,
+					Surname:  request.Surname,
+					Role:     request.Role,
+					Verified: request.Verified,
+				},
+			})
+		}
+	}
+
+	if len(pending) > 0 {
+		emails := make([]string, len(pending))
+		for i, p := range pending {
+			emails[i] = p.user.Email
+		}
+		existing, err := s.importRepo.ExistingEmails(ctx, emails)
+		if err != nil {
+			return nil, err
+		}
+
+		valid := pending[:0]
+		for _, p := range pending {
+			if existing[strings.ToLower(p.user.Email)] {
+				p.result.Errors = append(p.result.Errors, "email: already exists")
+				continue
+			}
+			valid = append(valid, p)
+		}
+		pending = valid
+	}
+
+	for i := range report.Rows {
+		if len(report.Rows[i].Errors) > 0 {
+			report.Rows[i].Status = models.ImportRowFailed
+		}
+	}
+	return pending, nil
+}
+
+// createAll creates every pending user in a single transaction, or none if any of them fails
+func (s *userImportService) createAll(ctx context.Context, pending []pendingUser, report *models.UserImportReport) error {
+	if report.Failed > 0 || len(pending) == 0 {
+		for _, p := range pending {
+			p.result.Status = models.ImportRowSkipped
+		}
+		return nil
+	}
+
+	users := make([]*models.User, len(pending))
+	for i, p := range pending {
+		users[i] = p.user
+	}
+
+	ids, err := s.importRepo.AddImportedUsers(ctx, users)
+	var rowErr *repo.ImportRowError
+	if errors.As(err, &rowErr) {
+		for i, p := range pending {
+			p.result.Status = models.ImportRowSkipped
+			if i == rowErr.Index {
+				p.result.Status = models.ImportRowFailed
+				p.result.Errors = append(p.result.Errors, importErrorMessage(rowErr.Err))
+			}
+		}
+		report.Failed++
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for i, p := range pending {
+		p.user.Id = ids[i]
+		p.result.Id = ids[i]
+		p.result.Status = models.ImportRowCreated
+	}
+	report.Created = len(pending)
+	return nil
+}
+
+// createEach creates the pending users one by one, a failure only affects its own row
+func (s *userImportService) createEach(ctx context.Context, pending []pendingUser, report *models.UserImportReport) {
+	for _, p := range pending {
+		id, err := s.importRepo.AddImportedUser(ctx, p.user)
+		if err != nil {
+			p.result.Status = models.ImportRowFailed
+			p.result.Errors = append(p.result.Errors, importErrorMessage(err))
+			report.Failed++
+			continue
+		}
+		p.user.Id = id
+		p.result.Id = id
+		p.result.Status = models.ImportRowCreated
+		report.Created++
+	}
+}
+
+func importErrorMessage(err error) string {
+	if errors.Is(err, repo.ErrEmailTaken) {
+		return "email: already exists"
+	}
+	return err.Error()
+}
+
+// validationMessages turns the errors of the binding validator into readable messages
+func validationMessages(err error) []string {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		if err != nil {
+			return []string{err.Error()}
+		}
+		return nil
+	}
+
+	messages := make([]string, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		field := strings.ToLower(fieldErr.Field())
+		switch fieldErr.Tag() {
+		case "required":
+			messages = append(messages, field+": is required")
+		case "email":
+			messages = append(messages, field+": must be a valid email")
+		case "min":
+			messages = append(messages, fmt.Sprintf("%s: must be at least %s characters long", field, fieldErr.Param()))
+		case "max":
+			messages = append(messages, fmt.Sprintf("%s: must be at most %s characters long", field, fieldErr.Param()))
+		case "oneof":
+			messages = append(messages, fmt.Sprintf("%s: must be one of %s", field, strings.ReplaceAll(fieldErr.Param(), " ", ", ")))
+		default:
+			messages = append(messages, fmt.Sprintf("%s: failed the %s check", field, fieldErr.Tag()))
+		}
+	}
+	return messages
+}
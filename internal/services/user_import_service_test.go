@@ -0,0 +1,202 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupImportTest(t *testing.T) (*repositories.MockUserImportRepository, *services.MockUserService, services.UserImportService) {
+	mockRepo := repositories.NewMockUserImportRepository(t)
+	mockUserService := services.NewMockUserService(t)
+	passwordService := services.NewPasswordService(repositories.NewMockUserRepository(t), models.DefaultPasswordPolicy(), utils.DefaultHashParams())
+	return mockRepo, mockUserService, services.NewUserImportService(mockRepo, mockUserService, passwordService)
+}
+
+func importRows() []models.UserImportRow {
+	return []models.UserImportRow{
+		{Line: 2, Email: "ana@example.com", Name: "Ana", Surname: "Lopez"},
+		{Line: 3, Email: "juan@example.com", Name: "Juan", Surname: "Perez", Role: "teacher", Password: "Correct4Horse"},
+	}
+}
+
+func TestUserImportService_Import_DryRunReportsEveryError(t *testing.T) {
+	mockRepo, _, service := setupImportTest(t)
+
+	rows := []models.UserImportRow{
+		{Line: 2, Email: "ana@example.com", Name: "Ana", Surname: "Lopez"},
+		{Line: 3, Email: "not-an-email", Name: "Jo", Surname: "Perez", Role: "janitor"},
+		{Line: 4, Email: "ANA@example.com", Name: "Anabel", Surname: "Lopez"},
+		{Line: 5, Email: "weak@example.com", Name: "Weak", Surname: "Password", Password: "password"},
+		{Line: 6, ParseError: "invalid JSON"},
+		{Line: 7, Email: "taken@example.com", Name: "Taken", Surname: "Email"},
+	}
+
+	mockRepo.EXPECT().
+		ExistingEmails(mock.Anything, []string{"ana@example.com", "taken@example.com"}).
+		Return(map[string]bool{"taken@example.com": true}, nil)
+
+	report, err := service.Import(context.Background(), rows, models.UserImportOptions{DryRun: true})
+	require.NoError(t, err)
+
+	assert.True(t, report.DryRun)
+	assert.Equal(t, models.ImportModeTransactional, report.Mode)
+	assert.Equal(t, 6, report.Total)
+	assert.Equal(t, 1, report.Valid)
+	assert.Equal(t, 5, report.Failed)
+	assert.Equal(t, 0, report.Created)
+
+	assert.Equal(t, models.ImportRowValid, report.Rows[0].Status)
+	assert.Equal(t, models.ImportRowFailed, report.Rows[1].Status)
+	assert.ElementsMatch(t, []string{
+		"email: must be a valid email",
+		"name: must be at least 3 characters long",
+		"role: must be one of student, teacher, admin",
+	}, report.Rows[1].Errors)
+	assert.Equal(t, []string{"email: already used in line 2"}, report.Rows[2].Errors)
+	assert.Contains(t, report.Rows[3].Errors, "password: is too common")
+	assert.Equal(t, []string{"invalid JSON"}, report.Rows[4].Errors)
+	assert.Equal(t, []string{"email: already exists"}, report.Rows[5].Errors)
+}
+
+func TestUserImportService_Import_Transactional(t *testing.T) {
+	mockRepo, mockUserService, service := setupImportTest(t)
+
+	mockRepo.EXPECT().ExistingEmails(mock.Anything, mock.Anything).Return(map[string]bool{}, nil)
+	mockRepo.EXPECT().AddImportedUsers(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, users []*models.User) ([]int, error) {
+			require.Len(t, users, 2)
+			assert.Equal(t, "student", users[0].Role)
+			assert.True(t, users[0].Verified)
+			assert.NoError(t, utils.CompareHashPassword(users[1].Password, "Correct4Horse"))
+			return []int{10, 11}, nil
+		})
+	mockUserService.EXPECT().SendInvitation(mock.Anything, mock.MatchedBy(func(u *models.User) bool { return u.Id == 10 })).Return(nil)
+	mockUserService.EXPECT().SendInvitation(mock.Anything, mock.MatchedBy(func(u *models.User) bool { return u.Id == 11 })).Return(errors.New("email down"))
+
+	report, err := service.Import(context.Background(), importRows(), models.UserImportOptions{SendInvitations: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, models.UserImportRowResult{Line: 2, Email: "ana@example.com", Id: 10, Status: models.ImportRowCreated}, report.Rows[0])
+	assert.Equal(t, models.ImportRowCreated, report.Rows[1].Status)
+	assert.Equal(t, []string{"invitation email could not be sent"}, report.Rows[1].Errors)
+}
+
+func TestUserImportService_Import_TransactionalCreatesNothingOnInvalidRow(t *testing.T) {
+	mockRepo, _, service := setupImportTest(t)
+
+	rows := append(importRows(), models.UserImportRow{Line: 4, Email: "bad", Name: "Bad", Surname: "Row"})
+	mockRepo.EXPECT().ExistingEmails(mock.Anything, mock.Anything).Return(map[string]bool{}, nil)
+
+	report, err := service.Import(context.Background(), rows, models.UserImportOptions{Mode: models.ImportModeTransactional})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, report.Created)
+	assert.Equal(t, models.ImportRowSkipped, report.Rows[0].Status)
+	assert.Equal(t, models.ImportRowSkipped, report.Rows[1].Status)
+	assert.Equal(t, models.ImportRowFailed, report.Rows[2].Status)
+}
+
+func TestUserImportService_Import_TransactionalRowFailsOnInsert(t *testing.T) {
+	mockRepo, _, service := setupImportTest(t)
+
+	mockRepo.EXPECT().ExistingEmails(mock.Anything, mock.Anything).Return(map[string]bool{}, nil)
+	mockRepo.EXPECT().AddImportedUsers(mock.Anything, mock.Anything).
+		Return(nil, &repositories.ImportRowError{Index: 1, Err: repositories.ErrEmailTaken})
+
+	report, err := service.Import(context.Background(), importRows(), models.UserImportOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, report.Created)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, models.ImportRowSkipped, report.Rows[0].Status)
+	assert.Equal(t, models.ImportRowFailed, report.Rows[1].Status)
+	assert.Equal(t, []string{"email: already exists"}, report.Rows[1].Errors)
+}
+
+func TestUserImportService_Import_BestEffort(t *testing.T) {
+	mockRepo, _, service := setupImportTest(t)
+
+	mockRepo.EXPECT().ExistingEmails(mock.Anything, mock.Anything).Return(map[string]bool{}, nil)
+	mockRepo.EXPECT().AddImportedUser(mock.Anything, mock.MatchedBy(func(u *models.User) bool { return u.Email == "ana@example.com" })).
+		Return(10, nil)
+	mockRepo.EXPECT().AddImportedUser(mock.Anything, mock.MatchedBy(func(u *models.User) bool { return u.Email == "juan@example.com" })).
+		Return(0, errors.New("db error"))
+
+	report, err := service.Import(context.Background(), importRows(), models.UserImportOptions{Mode: models.ImportModeBestEffort})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, models.ImportRowCreated, report.Rows[0].Status)
+	assert.Equal(t, []string{"db error"}, report.Rows[1].Errors)
+}
+
+func TestUserImportService_StartImportJob(t *testing.T) {
+	mockRepo, _, service := setupImportTest(t)
+
+	finished := make(chan *models.UserImportReport, 1)
+	mockRepo.EXPECT().CreateImportJob(mock.Anything, 1).Return(5, nil)
+	mockRepo.EXPECT().ExistingEmails(mock.Anything, mock.Anything).Return(map[string]bool{}, nil)
+	mockRepo.EXPECT().FinishImportJob(mock.Anything, 5, mock.Anything, nil).
+		RunAndReturn(func(_ context.Context, _ int, report *models.UserImportReport, _ error) error {
+			finished <- report
+			return nil
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	id, err := service.StartImportJob(ctx, 1, importRows(), models.UserImportOptions{DryRun: true})
+	// The request ending must not stop the job
+	cancel()
+	require.NoError(t, err)
+	assert.Equal(t, 5, id)
+
+	select {
+	case report := <-finished:
+		assert.Equal(t, 2, report.Valid)
+	case <-time.After(5 * time.Second):
+		t.Fatal("import job didn't finish")
+	}
+}
+
+func TestUserImportService_RunRecovery_FailsStaleJobsAtStartup(t *testing.T) {
+	mockRepo, _, service := setupImportTest(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockRepo.EXPECT().FailStaleImportJobs(mock.Anything, services.ImportJobStaleAfter, services.ErrImportJobInterrupted.Error()).
+		RunAndReturn(func(context.Context, time.Duration, string) (int64, error) {
+			cancel()
+			return 1, nil
+		}).Once()
+
+	done := make(chan struct{})
+	go func() {
+		service.RunRecovery(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("recovery didn't stop")
+	}
+}
+
+func TestUserImportService_GetImportJob(t *testing.T) {
+	mockRepo, _, service := setupImportTest(t)
+
+	mockRepo.EXPECT().GetImportJob(mock.Anything, 5).Return(nil, repositories.ErrNotFound)
+
+	_, err := service.GetImportJob(context.Background(), 5)
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+}
@@ -3,83 +3,168 @@ package services
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/sendgrid/sendgrid-go/helpers/mail"
 	"github.com/sethvargo/go-password/password"
 	"golang.org/x/oauth2/google"
 	"io"
 	"log"
+	"maps"
 	"net/http"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/mailer"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
 	repo "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
 )
 
 type UserService interface {
-	DeleteUser(ctx context.Context, id int) error
+	DeleteUser(ctx context.Context, id int, ifMatch string) error
 	CreateUser(ctx context.Context, request models.CreateUserRequest) (int, error)
 	GetUserById(ctx context.Context, id int) (*models.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
-	GetAllUsers(ctx context.Context) (users []models.User, err error)
-	ModifyUser(ctx context.Context, id int, user models.UserUpdateDto) error
+	GetAllUsers(ctx context.Context, filter models.UserFilter) (users []models.User, err error)
+	StreamUsers(ctx context.Context, filter models.UserFilter, fn func(models.User) error) error
+	ModifyUser(ctx context.Context, id int, user models.UserUpdateDto, ifMatch string) (*models.User, error)
+	PatchUser(ctx context.Context, id int, patch models.UserPatch, ifMatch string) (*models.User, error)
 	BlockUser(ctx context.Context, id int, reason string, blockerId *int, blockedUntil *time.Time) error
 	IsUserBlocked(ctx context.Context, id int) (bool, error)
 	ModifyPassword(ctx context.Context, id int, password string) error
+	// RehashPassword upgrades the hash of the user's password if it was made with an older algorithm or parameters,
+	// keeping the password
+	RehashPassword(ctx context.Context, user models.User, password string) error
 	AddNotificationToken(ctx context.Context, id int, text string) error
 	GetUserNotificationsToken(ctx context.Context, id int) (models.NotificationTokens, error)
-	SendNotifByMobile(cont context.Context, userId int, notification models.NotifyRequest) error
-	SendNotifByEmail(cont context.Context, userId int, request models.NotifyRequest) error
+	SendNotifByMobile(cont context.Context, entry models.InboxEntry) error
+	SendNotifByEmail(cont context.Context, entry models.InboxEntry) error
+	NotifyAllUsers(ctx context.Context, notification models.NotifyRequest) error
+	// NotifyUsers stores the notification in the inbox of each user along with the jobs that send it by mobile and email
+	NotifyUsers(ctx context.Context, userIds []int, notification models.NotifyRequest) error
 	VerifyUser(ctx context.Context, id int) error
-	StartPasswordReset(ctx context.Context, id int, email string) error
+	StartPasswordReset(ctx context.Context, email string) error
+	SendInvitation(ctx context.Context, user *models.User) error
 	ValidatePasswordResetToken(ctx context.Context, token string) (*models.PasswordResetData, error)
-	SetPasswordTokenUsed(ctx context.Context, token string) error
+	// SetNotificationPreference turns a type of notification on or off by every channel.
+	// Deprecated: UpdateNotificationPreferences sets each channel.
 	SetNotificationPreference(ctx context.Context, id int, preference models.NotificationPreferenceRequest) error
-	CheckPreference(ctx context.Context, id int, notificationType string) (bool, error)
+	// GetNotificationPreference tells whether the user gets the original three types of notification by any channel.
+	// Deprecated: GetNotificationSettings has every type by channel.
 	GetNotificationPreference(ctx context.Context, id int) (*models.NotificationPreference, error)
+	GetNotificationTypes(ctx context.Context) ([]models.NotificationType, error)
+	AddNotificationType(ctx context.Context, notificationType *models.NotificationType) error
+	// GetNotificationSettings returns the preferences matrix of the user
+	GetNotificationSettings(ctx context.Context, id int) (*models.NotificationSettings, error)
+	// UpdateNotificationPreferences changes the preferences given, returning the whole matrix afterwards
+	UpdateNotificationPreferences(ctx context.Context, id int, preferences models.NotificationPreferences) (*models.NotificationSettings, error)
+	// GetNotificationChannels returns by which channels the user gets the type of notification
+	GetNotificationChannels(ctx context.Context, id int, notificationType string) (map[string]bool, error)
 	MakeTeacher(ctx context.Context, id int) error
+	RequestEmailChange(ctx context.Context, id int, newEmail string) error
+	ConfirmEmailChange(ctx context.Context, id int, pin string) error
+	CancelEmailChange(ctx context.Context, token string) error
+	IsSessionRevoked(ctx context.Context, id int, issuedAt time.Time) (bool, error)
 }
 
+const (
+	// ResetTokenLifeTime is in minutes, like PinLifeTime
+	ResetTokenLifeTime = 5
+	// InvitationLifeTime is in minutes too, invited users have a week to choose their password
+	InvitationLifeTime = 7 * 24 * 60
+	// MaxResetAttempts is how many times a reset token can be checked before it's disabled
+	MaxResetAttempts = 5
+)
+
+var (
+	ErrSameEmail                  = errors.New("new email is the same as the current one")
+	ErrInvalidPin                 = errors.New("invalid verification pin")
+	ErrPinExpired                 = errors.New("verification pin expired")
+	ErrInvalidResetToken          = errors.New("invalid or expired reset token")
+	ErrResetAttemptsExceeded      = errors.New("too many attempts, request a new reset token")
+	ErrPinAttemptsExceeded        = errors.New("too many attempts, request a new email change")
+	ErrUnknownNotificationType    = errors.New("unknown notification type")
+	ErrUnknownNotificationChannel = errors.New("unknown notification channel")
+	// ErrMandatoryNotification is returned when turning off a type of notification every user gets
+	ErrMandatoryNotification = errors.New("mandatory notifications can't be turned off")
+	// ErrMandatoryNotificationSend is returned when asking to send a mandatory type, only the service itself sends them
+	ErrMandatoryNotificationSend = errors.New("mandatory notifications can't be sent through the API")
+)
+
+// userService sends emails and push notifications through the jobs outbox, they are delivered in the background.
+// Notifications are kept in the inbox of the user too, and the pushes and emails link to their entry.
 type userService struct {
 	userRepo      repo.UserRepository
 	blockUserRepo repo.BlockedUserRepository
-	emailClient   EmailSender
+	jobRepo       repo.JobRepository
+	inboxRepo     repo.InboxRepository
+	// Base URL of the API used in links sent to users
+	publicURL string
+	// Algorithm and parameters new password hashes are created with
+	hashParams utils.HashParams
 }
 
-func NewUserService(userRepo repo.UserRepository, blockedUserRepo repo.BlockedUserRepository, emailClient EmailSender) *userService {
-	return &userService{userRepo: userRepo, blockUserRepo: blockedUserRepo, emailClient: emailClient}
+func NewUserService(userRepo repo.UserRepository, blockedUserRepo repo.BlockedUserRepository, jobRepo repo.JobRepository, inboxRepo repo.InboxRepository, publicURL string, hashParams utils.HashParams) *userService {
+	return &userService{userRepo: userRepo, blockUserRepo: blockedUserRepo, jobRepo: jobRepo, inboxRepo: inboxRepo, publicURL: publicURL, hashParams: hashParams}
 }
 
 func (s *userService) MakeTeacher(ctx context.Context, id int) error {
 	return s.userRepo.MakeTeacher(ctx, id)
 }
 
-func (s *userService) DeleteUser(ctx context.Context, id int) error {
-	return s.userRepo.DeleteUser(ctx, id)
+func (s *userService) DeleteUser(ctx context.Context, id int, ifMatch string) error {
+	user, err := s.getUserIfMatch(ctx, id, ifMatch)
+	if err != nil {
+		return err
+	}
+	return s.userRepo.DeleteUser(ctx, id, user.Version)
 }
 
-func (s *userService) CreateUser(ctx context.Context, request models.CreateUserRequest) (int, error) {
+// getUserIfMatch loads the user and checks it against an If-Match precondition,
+// returning repo.ErrVersionMismatch when the client's copy is stale.
+func (s *userService) getUserIfMatch(ctx context.Context, id int, ifMatch string) (*models.User, error) {
+	user, err := s.userRepo.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !utils.MatchesStrongETag(ifMatch, user.ETag()) {
+		return nil, repo.ErrVersionMismatch
+	}
+	return user, nil
+}
 
-	hashPassword, err := utils.HashPassword(request.Password)
+func (s *userService) CreateUser(ctx context.Context, request models.CreateUserRequest) (int, error) {
+	user, err := newUser(request, s.hashParams)
 	if err != nil {
 		return 0, err
 	}
+	return s.userRepo.AddUser(ctx, user)
+}
+
+// newUser builds the user to store for the request, with the password hashed
+func newUser(request models.CreateUserRequest, hashParams utils.HashParams) (*models.User, error) {
+	hashPassword, err := hashParams.Hash(request.Password)
+	if err != nil {
+		return nil, err
+	}
 
-	user := &models.User{
+	return &models.User{
 		Email:    request.Email,
 		Password: hashPassword,
 		Name:     request.Name,
 		Surname:  request.Surname,
 		Role:     request.Role,
 		Verified: request.Verified,
-	}
-
-	return s.userRepo.AddUser(ctx, user)
+	}, nil
 }
 
 func (s *userService) GetUserById(ctx context.Context, id int) (*models.User, error) {
@@ -90,20 +175,44 @@ func (s *userService) GetUserByEmail(ctx context.Context, email string) (*models
 	return s.userRepo.GetUserByEmail(ctx, email)
 }
 
-func (s *userService) GetAllUsers(ctx context.Context) (users []models.User, err error) {
-	return s.userRepo.GetAllUsers(ctx)
+func (s *userService) GetAllUsers(ctx context.Context, filter models.UserFilter) (users []models.User, err error) {
+	return s.userRepo.GetAllUsers(ctx, filter)
+}
+
+func (s *userService) StreamUsers(ctx context.Context, filter models.UserFilter, fn func(models.User) error) error {
+	return s.userRepo.StreamUsers(ctx, filter, fn)
 }
 
-func (s *userService) ModifyUser(ctx context.Context, id int, user models.UserUpdateDto) error {
-	tableUser, err := s.userRepo.GetUser(ctx, id)
+func (s *userService) ModifyUser(ctx context.Context, id int, user models.UserUpdateDto, ifMatch string) (*models.User, error) {
+	tableUser, err := s.getUserIfMatch(ctx, id, ifMatch)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Update the existing user with the new values
 	tableUser.Update(user)
 
-	return s.userRepo.ModifyUser(ctx, tableUser)
+	if err := s.userRepo.ModifyUser(ctx, tableUser); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.GetUser(ctx, id)
+}
+
+// PatchUser applies a JSON Merge Patch to the stored user and returns the user as persisted.
+func (s *userService) PatchUser(ctx context.Context, id int, patch models.UserPatch, ifMatch string) (*models.User, error) {
+	tableUser, err := s.getUserIfMatch(ctx, id, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	tableUser.ApplyPatch(patch)
+
+	if err := s.userRepo.ModifyUser(ctx, tableUser); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.GetUser(ctx, id)
 }
 
 func (s *userService) IsUserBlocked(ctx context.Context, id int) (bool, error) {
@@ -133,13 +242,24 @@ func (s *userService) BlockUser(
 	return nil
 }
 func (s *userService) ModifyPassword(ctx context.Context, id int, password string) error {
-	hashPassword, err := utils.HashPassword(password)
+	hashPassword, err := s.hashParams.Hash(password)
 	if err != nil {
 		return err
 	}
 	return s.userRepo.ModifyPassword(ctx, id, hashPassword)
 }
 
+func (s *userService) RehashPassword(ctx context.Context, user models.User, password string) error {
+	if !s.hashParams.NeedsRehash(user.Password) {
+		return nil
+	}
+	hash, err := s.hashParams.Hash(password)
+	if err != nil {
+		return err
+	}
+	return s.userRepo.RehashPassword(ctx, user.Id, user.Password, hash)
+}
+
 func (s *userService) AddNotificationToken(ctx context.Context, id int, text string) error {
 	return s.userRepo.AddNotificationToken(ctx, id, text)
 }
@@ -151,39 +271,63 @@ func (s *userService) VerifyUser(ctx context.Context, id int) error {
 	return s.userRepo.SetVerifiedTrue(ctx, id)
 }
 
-func (s *userService) SendNotifByMobile(cont context.Context, userId int, notification models.NotifyRequest) error {
-	tokens, _ := s.GetUserNotificationsToken(cont, userId)
+func (s *userService) SendNotifByMobile(cont context.Context, entry models.InboxEntry) error {
+	tokens, err := s.GetUserNotificationsToken(cont, entry.UserId)
+	if err != nil {
+		return err
+	}
+	jobs, err := pushJobs(tokens, entry)
+	if err != nil {
+		return err
+	}
+	return s.jobRepo.Enqueue(cont, jobs...)
+}
+
+// pushJobs returns a job for each device of the user, so a device failing doesn't resend to the rest
+func pushJobs(tokens models.NotificationTokens, entry models.InboxEntry) ([]models.Job, error) {
+	jobs := make([]models.Job, 0, len(tokens.NotificationTokens))
 	for _, token := range tokens.NotificationTokens {
-		err := sendNotifToDevice(token.NotificationToken, notification)
+		job, err := models.NewPushJob(token.NotificationToken, entry)
 		if err != nil {
-			println(err.Error())
+			return nil, err
 		}
+		jobs = append(jobs, job)
 	}
-	return nil
+	return jobs, nil
 }
 
-func sendNotifToDevice(userToken string, notification models.NotifyRequest) error {
-	if strings.Contains(userToken, "ExponentPushToken[") {
-		return sendNotifExpo(userToken, notification)
+// pushData is the data sent along with the notification, linking it to its inbox entry
+func pushData(push models.PushJob) map[string]string {
+	if push.InboxId == 0 {
+		return nil
+	}
+	return map[string]string{"inbox_id": strconv.Itoa(push.InboxId)}
+}
+
+func sendNotifToDevice(ctx context.Context, push models.PushJob) error {
+	if strings.Contains(push.Token, "ExponentPushToken[") {
+		return sendNotifExpo(ctx, push)
 	}
 
 	url := fmt.Sprintf("https://fcm.googleapis.com/v1/projects/%s/messages:send", os.Getenv("FCM_PROJECT_ID"))
-	payload := map[string]interface{}{
-		"message": map[string]interface{}{
-			"token": userToken,
-			"notification": map[string]string{
-				"title": notification.NotificationTitle,
-				"body":  notification.NotificationText,
-			},
+	message := map[string]interface{}{
+		"token": push.Token,
+		"notification": map[string]string{
+			"title": push.Title,
+			"body":  push.Text,
 		},
 	}
+	if data := pushData(push); data != nil {
+		message["data"] = data
+	}
+	payload := map[string]interface{}{"message": message}
 
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("error marshalling payload: %v", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return fmt.Errorf("error creating HTTP request: %v", err)
 	}
@@ -201,7 +345,7 @@ func sendNotifToDevice(userToken string, notification models.NotifyRequest) erro
 		return fmt.Errorf("invalid service account JSON: %v", err)
 	}
 
-	client := conf.Client(context.Background())
+	client := conf.Client(ctx)
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -215,19 +359,20 @@ func sendNotifToDevice(userToken string, notification models.NotifyRequest) erro
 	return nil
 }
 
-func sendNotifExpo(userToken string, notification models.NotifyRequest) error {
-	log.Printf("%s", userToken)
+func sendNotifExpo(ctx context.Context, push models.PushJob) error {
 	type ExpoPushMessage struct {
-		To    string `json:"to"`
-		Title string `json:"title,omitempty"`
-		Body  string `json:"body,omitempty"`
-		Sound string `json:"sound,omitempty"`
+		To    string            `json:"to"`
+		Title string            `json:"title,omitempty"`
+		Body  string            `json:"body,omitempty"`
+		Sound string            `json:"sound,omitempty"`
+		Data  map[string]string `json:"data,omitempty"`
 	}
 	message := ExpoPushMessage{
-		To:    userToken,
-		Title: notification.NotificationTitle,
-		Body:  notification.NotificationText,
+		To:    push.Token,
+		Title: push.Title,
+		Body:  push.Text,
 		Sound: "default", // optional
+		Data:  pushData(push),
 	}
 
 	payload, err := json.Marshal(message)
@@ -235,7 +380,7 @@ func sendNotifExpo(userToken string, notification models.NotifyRequest) error {
 		return fmt.Errorf("failed to marshal payload: %v", err)
 	}
 
-	req, err := http.NewRequest("POST", "https://exp.host/--/api/v2/push/send", bytes.NewBuffer(payload))
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://exp.host/--/api/v2/push/send", bytes.NewBuffer(payload))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %v", err)
 	}
@@ -260,82 +405,416 @@ func sendNotifExpo(userToken string, notification models.NotifyRequest) error {
 	return nil
 }
 
-func (s *userService) SendNotifByEmail(cont context.Context, userId int, request models.NotifyRequest) error {
-	user, err := s.userRepo.GetUser(cont, userId)
+func (s *userService) SendNotifByEmail(cont context.Context, entry models.InboxEntry) error {
+	user, err := s.userRepo.GetUser(cont, entry.UserId)
 	if err != nil {
 		return err
 	}
-	from := mail.NewEmail("ClassConnect service", "bmorseletto@fi.uba.ar")
-	subject := request.NotificationTitle
-	to := mail.NewEmail("User", user.Email)
-	content := mail.NewContent("text/plain", request.NotificationText)
-	message := mail.NewV3MailInit(from, subject, to, content)
+	job, err := s.notificationEmailJob(cont, user.Email, entry)
+	if err != nil {
+		return err
+	}
+	return s.jobRepo.Enqueue(cont, job)
+}
 
-	_, err2 := s.emailClient.Send(message)
-	return err2
+// notificationEmailJob links the email to the inbox entry, unless the user doesn't keep the notification in the inbox
+func (s *userService) notificationEmailJob(ctx context.Context, email string, entry models.InboxEntry) (models.Job, error) {
+	var link string
+	if entry.Id != 0 {
+		link = fmt.Sprintf("%s/users/%d/inbox/%d", s.publicURL, entry.UserId, entry.Id)
+	}
+	return emailJob(ctx, mailer.TemplateNotification,
+		mailer.NotificationData{Title: entry.Title, Text: entry.Text, Link: link}, mailer.Address{Name: "User", Email: email})
 }
 
-func (s *userService) StartPasswordReset(ctx context.Context, id int, email string) error {
-	token, err := password.Generate(6, 2, 0, false, true)
+// NotifyAllUsers stores the notification in the inbox of every user that isn't blocked, queueing it to their devices and email along with the entry.
+// Each user only gets it by the channels their preferences allow. Failing to reach a user doesn't stop the rest, the first error is returned at the end.
+func (s *userService) NotifyAllUsers(ctx context.Context, notification models.NotifyRequest) error {
+	// Collected first so the query isn't held open while looking up the devices
+	var users []models.User
+	notBlocked := false
+	err := s.userRepo.StreamUsers(ctx, models.UserFilter{Blocked: &notBlocked}, func(user models.User) error {
+		users = append(users, user)
+		return nil
+	})
 	if err != nil {
 		return err
 	}
-	err = s.userRepo.AddPasswordResetToken(ctx, id, email, token, time.Now().Add(5*time.Minute))
+	return s.notifyUsers(ctx, users, notification)
+}
+
+// NotifyUsers sends the notification to the given users the same way NotifyAllUsers does.
+// It backs the public notify endpoint, so mandatory types, which skip the preferences, are refused.
+func (s *userService) NotifyUsers(ctx context.Context, userIds []int, notification models.NotifyRequest) error {
+	types, err := s.userRepo.GetNotificationTypes(ctx)
 	if err != nil {
 		return err
 	}
-	from := mail.NewEmail("ClassConnect service", "bmorseletto@fi.uba.ar")
-	subject := "Password Reset"
-	to := mail.NewEmail("User", email)
-	resetLink := token
-	plainTextContent := resetLink
-	htmlContent := fmt.Sprintf(`
-	<html>
-		<body>
-			<p>Hello,</p>
-			<p>Your Reset token is:</p>
-			<p>%s</p>
-		</body>
-	</html>`, resetLink)
+	for _, notificationType := range types {
+		if notificationType.Name == notification.NotificationType && notificationType.Mandatory {
+			return fmt.Errorf("%w: %s", ErrMandatoryNotificationSend, notificationType.Name)
+		}
+	}
+
+	var firstErr error
+	users := make([]models.User, 0, len(userIds))
+	for _, userId := range userIds {
+		user, err := s.userRepo.GetUser(ctx, userId)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		users = append(users, *user)
+	}
+	if err := s.notifyUsers(ctx, users, notification); err != nil {
+		return err
+	}
+	return firstErr
+}
 
-	message := mail.NewV3Mail()
-	message.SetFrom(from)
-	message.Subject = subject
+// notifyUsers stores the entry of each user along with the jobs that send it, so neither is kept without the other
+func (s *userService) notifyUsers(ctx context.Context, users []models.User, notification models.NotifyRequest) error {
+	var firstErr error
+	for _, user := range users {
+		channels, err := s.GetNotificationChannels(ctx, user.Id, notification.NotificationType)
+		if errors.Is(err, ErrUnknownNotificationType) {
+			return err
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		// Without the devices the notification still goes to the inbox and the email
+		var tokens models.NotificationTokens
+		if channels[models.NotificationChannelPush] {
+			tokens, err = s.GetUserNotificationsToken(ctx, user.Id)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		entry := models.NewInboxEntry(user.Id, notification)
+		outbox := func(id int) ([]models.Job, error) {
+			entry.Id = id
+			return s.notificationJobs(ctx, channels, tokens, user.Email, entry)
+		}
+		if channels[models.NotificationChannelInbox] {
+			err = s.inboxRepo.AddInboxEntry(ctx, &entry, outbox)
+		} else {
+			var jobs []models.Job
+			if jobs, err = outbox(0); err == nil {
+				err = s.jobRepo.Enqueue(ctx, jobs...)
+			}
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
 
-	p := mail.NewPersonalization()
-	p.AddTos(to)
-	message.AddPersonalizations(p)
+// notificationJobs returns the jobs that send the entry by the push and email channels that are enabled
+func (s *userService) notificationJobs(ctx context.Context, channels map[string]bool, tokens models.NotificationTokens, email string, entry models.InboxEntry) ([]models.Job, error) {
+	var jobs []models.Job
+	if channels[models.NotificationChannelPush] {
+		pushes, err := pushJobs(tokens, entry)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, pushes...)
+	}
+	if channels[models.NotificationChannelEmail] {
+		emailJob, err := s.notificationEmailJob(ctx, email, entry)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, emailJob)
+	}
+	return jobs, nil
+}
+
+// StartPasswordReset emails a reset token to the user with the given email. Unknown emails are
+// ignored without error so callers can't tell which emails have an account.
+func (s *userService) StartPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
 
-	message.AddContent(mail.NewContent("text/plain", plainTextContent))
-	message.AddContent(mail.NewContent("text/html", htmlContent))
+	return s.issueResetToken(ctx, user, ResetTokenLifeTime, mailer.TemplatePasswordReset, mailer.Address{Name: "User", Email: user.Email})
+}
 
-	_, err2 := s.emailClient.Send(message)
-	return err2
+// SendInvitation emails a user created by an admin a token to choose their password. It is a
+// password reset token that lasts InvitationLifeTime instead of ResetTokenLifeTime.
+func (s *userService) SendInvitation(ctx context.Context, user *models.User) error {
+	return s.issueResetToken(ctx, user, InvitationLifeTime, mailer.TemplateInvitation, mailer.Address{Name: user.Name, Email: user.Email})
 }
 
+// issueResetToken stores a new reset token for the user that lasts lifeTime minutes, along with the
+// email that sends it to the user with the link that opens the app on the reset screen.
+func (s *userService) issueResetToken(ctx context.Context, user *models.User, lifeTime int, template string, to mailer.Address) error {
+	secret, err := password.Generate(6, 2, 0, false, true)
+	if err != nil {
+		return err
+	}
+	_, err = s.userRepo.AddPasswordResetToken(ctx, user.Id, user.Email, hashResetToken(secret), time.Now().Add(time.Duration(lifeTime)*time.Minute),
+		func(resetId int) ([]models.Job, error) {
+			// Same "<id>-<secret>" format as the verification pins, the id lets us count failed attempts per token
+			token := fmt.Sprintf("%d-%s", resetId, secret)
+			resetLink := fmt.Sprintf("%s/users/reset/password?token=%s", s.publicURL, url.QueryEscape(token))
+			job, err := secretEmailJob(ctx, template, mailer.TokenData{Name: user.Name, Token: token, Link: resetLink}, to)
+			return []models.Job{job}, err
+		})
+	return err
+}
+
+// ValidatePasswordResetToken checks a "<id>-<secret>" reset token without consuming it. Every wrong
+// secret counts as an attempt, after MaxResetAttempts the token can't be used anymore. The attempt is
+// counted before comparing the secret, so parallel guesses can't get past the limit, and taken back
+// if the secret was right.
 func (s *userService) ValidatePasswordResetToken(ctx context.Context, token string) (*models.PasswordResetData, error) {
-	info, err := s.userRepo.GetPasswordResetTokenInfo(ctx, token)
+	idPart, secret, found := strings.Cut(token, "-")
+	if !found {
+		return nil, ErrInvalidResetToken
+	}
+	id, err := strconv.Atoi(idPart)
+	if err != nil {
+		return nil, ErrInvalidResetToken
+	}
+
+	info, err := s.userRepo.AddPasswordResetAttempt(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, ErrInvalidResetToken
+	}
 	if err != nil {
 		return nil, err
 	}
-	if info.Exp.Before(time.Now()) {
-		return nil, errors.New("Token Expired")
+	if info.Used || info.Exp.Before(time.Now()) {
+		return nil, ErrInvalidResetToken
+	}
+	if info.Attempts > MaxResetAttempts {
+		return nil, ErrResetAttemptsExceeded
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashResetToken(secret)), []byte(info.TokenHash)) != 1 {
+		return nil, ErrInvalidResetToken
+	}
+	if err := s.userRepo.ReleasePasswordResetAttempt(ctx, id); err != nil {
+		return nil, err
 	}
 	return info, nil
 }
 
-func (s *userService) SetPasswordTokenUsed(ctx context.Context, token string) error {
-	return s.userRepo.SetPasswordTokenUsed(ctx, token)
+func (s *userService) SetNotificationPreference(ctx context.Context, id int, preference models.NotificationPreferenceRequest) error {
+	preferences := models.NotificationPreferences{}
+	for _, channel := range models.NotificationChannels {
+		preferences.Set(preference.NotificationType, channel, preference.NotificationPreference)
+	}
+	_, err := s.UpdateNotificationPreferences(ctx, id, preferences)
+	return err
 }
 
-func (s *userService) SetNotificationPreference(ctx context.Context, id int, preference models.NotificationPreferenceRequest) error {
-	return s.userRepo.SetNotificationPreference(ctx, id, preference)
+func (s *userService) GetNotificationPreference(ctx context.Context, id int) (*models.NotificationPreference, error) {
+	preferences, err := s.userRepo.GetNotificationPreferences(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// A type was on unless every channel is off
+	anyChannel := func(notificationType string) bool {
+		return slices.Contains(slices.Collect(maps.Values(preferences[notificationType])), true)
+	}
+	return &models.NotificationPreference{
+		ExamNotification:     anyChannel("exam_notification"),
+		HomeworkNotification: anyChannel("homework_notification"),
+		SocialNotification:   anyChannel("social_notification"),
+	}, nil
 }
 
-func (s *userService) CheckPreference(ctx context.Context, id int, notificationType string) (bool, error) {
-	return s.userRepo.CheckPreference(ctx, id, notificationType)
+func (s *userService) GetNotificationTypes(ctx context.Context) ([]models.NotificationType, error) {
+	return s.userRepo.GetNotificationTypes(ctx)
 }
 
-func (s *userService) GetNotificationPreference(ctx context.Context, id int) (*models.NotificationPreference, error) {
-	return s.userRepo.GetNotificationPreference(ctx, id)
+func (s *userService) AddNotificationType(ctx context.Context, notificationType *models.NotificationType) error {
+	return s.userRepo.AddNotificationType(ctx, notificationType)
+}
+
+func (s *userService) GetNotificationSettings(ctx context.Context, id int) (*models.NotificationSettings, error) {
+	types, err := s.userRepo.GetNotificationTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	preferences, err := s.userRepo.GetNotificationPreferences(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NotificationSettings{Types: types, Channels: models.NotificationChannels, Preferences: preferences}, nil
+}
+
+// UpdateNotificationPreferences checks every type is registered and every channel exists before saving anything,
+// mandatory types can only be left on.
+func (s *userService) UpdateNotificationPreferences(ctx context.Context, id int, preferences models.NotificationPreferences) (*models.NotificationSettings, error) {
+	types, err := s.userRepo.GetNotificationTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	registered := make(map[string]models.NotificationType, len(types))
+	for _, notificationType := range types {
+		registered[notificationType.Name] = notificationType
+	}
+
+	for name, channels := range preferences {
+		notificationType, ok := registered[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownNotificationType, name)
+		}
+		for channel, enabled := range channels {
+			if !models.IsNotificationChannel(channel) {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownNotificationChannel, channel)
+			}
+			if notificationType.Mandatory && !enabled {
+				return nil, fmt.Errorf("%w: %s", ErrMandatoryNotification, name)
+			}
+		}
+	}
+
+	if err := s.userRepo.SetNotificationPreferences(ctx, id, preferences); err != nil {
+		return nil, err
+	}
+	return s.GetNotificationSettings(ctx, id)
+}
+
+func (s *userService) GetNotificationChannels(ctx context.Context, id int, notificationType string) (map[string]bool, error) {
+	channels, err := s.userRepo.GetNotificationChannels(ctx, id, notificationType)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownNotificationType, notificationType)
+	}
+	return channels, err
+}
+
+// RequestEmailChange starts an email change: the new address gets a confirmation pin and the
+// current one a link to cancel the change. Nothing changes until the pin is confirmed.
+func (s *userService) RequestEmailChange(ctx context.Context, id int, newEmail string) error {
+	user, err := s.userRepo.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(user.Email, newEmail) {
+		return ErrSameEmail
+	}
+
+	taken, err := s.userRepo.EmailExists(ctx, newEmail)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return repo.ErrEmailTaken
+	}
+
+	pin, err := password.Generate(6, 2, 0, false, true)
+	if err != nil {
+		return err
+	}
+	cancelToken, err := generateToken()
+	if err != nil {
+		return err
+	}
+
+	request := &models.EmailChangeRequest{
+		UserId:          id,
+		OldEmail:        user.Email,
+		NewEmail:        newEmail,
+		VerificationPin: pin,
+		CancelTokenHash: hashResetToken(cancelToken),
+		PinExpiration:   time.Now().Add(PinLifeTime * time.Minute),
+	}
+	// The new address gets the "<request id>-<pin>" code, the current one a link to cancel the change
+	_, err = s.userRepo.AddEmailChangeRequest(ctx, request, func(requestId int) ([]models.Job, error) {
+		confirmation, err := secretEmailJob(ctx, mailer.TemplateEmailChange,
+			mailer.CodeData{Code: fmt.Sprintf("%d-%s", requestId, pin), Minutes: PinLifeTime}, mailer.Address{Name: "User", Email: newEmail})
+		if err != nil {
+			return nil, err
+		}
+		cancelLink := fmt.Sprintf("%s/users/email/cancel?token=%s", s.publicURL, cancelToken)
+		notice, err := secretEmailJob(ctx, mailer.TemplateEmailChangeNotice,
+			mailer.EmailChangeNoticeData{NewEmail: newEmail, CancelLink: cancelLink}, mailer.Address{Name: "User", Email: user.Email})
+		return []models.Job{confirmation, notice}, err
+	})
+	return err
+}
+
+// ConfirmEmailChange applies a pending email change given the pin sent to the new address.
+// The pin has the same "<request id>-<pin>" format as the registration one.
+func (s *userService) ConfirmEmailChange(ctx context.Context, id int, pin string) error {
+	parts := strings.Split(pin, "-")
+	if len(parts) != 2 {
+		return ErrInvalidPin
+	}
+	requestId, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return ErrInvalidPin
+	}
+
+	// The attempt is counted before comparing, and given back only if the pin was right
+	request, err := s.userRepo.AddEmailChangeAttempt(ctx, requestId)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return ErrInvalidPin
+		}
+		return err
+	}
+	if request.Attempts > MaxResetAttempts {
+		return ErrPinAttemptsExceeded
+	}
+	if request.UserId != id || subtle.ConstantTimeCompare([]byte(request.VerificationPin), []byte(parts[1])) != 1 {
+		return ErrInvalidPin
+	}
+	if err := s.userRepo.ReleaseEmailChangeAttempt(ctx, requestId); err != nil {
+		return err
+	}
+	if time.Now().After(request.PinExpiration) {
+		return ErrPinExpired
+	}
+
+	return s.userRepo.ApplyEmailChange(ctx, request)
+}
+
+func (s *userService) CancelEmailChange(ctx context.Context, token string) error {
+	return s.userRepo.DeleteEmailChangeRequestByCancelToken(ctx, hashResetToken(token))
+}
+
+// IsSessionRevoked reports whether a token issued at issuedAt was revoked, for example by an email change
+func (s *userService) IsSessionRevoked(ctx context.Context, id int, issuedAt time.Time) (bool, error) {
+	validAfter, err := s.userRepo.GetSessionsValidAfter(ctx, id)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	if validAfter == nil {
+		return false, nil
+	}
+	// Tokens only carry second precision
+	return issuedAt.Before(validAfter.Truncate(time.Second)), nil
+}
+
+// hashResetToken is the only form in which reset and email change cancel tokens are stored
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateToken returns a random hex encoded token suitable for links sent by email
+func generateToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
 }
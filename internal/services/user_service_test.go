@@ -2,25 +2,32 @@ package services_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
-	"github.com/jarcoal/httpmock"
-	"github.com/sendgrid/rest"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/mailer"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/services"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+// testPublicURL is the base of the links in the emails sent by the service
+const testPublicURL = "https://api.example.com"
+
 func TestUserService_GetAllUsers(t *testing.T) {
 	// Arrange
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	expectedUsers := []models.User{
 		{Id: 1, Name: "John", Surname: "Doe", Email: "john@example.com"},
@@ -28,10 +35,10 @@ func TestUserService_GetAllUsers(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	mockRepo.EXPECT().GetAllUsers(ctx).Return(expectedUsers, nil)
+	mockRepo.EXPECT().GetAllUsers(ctx, models.UserFilter{}).Return(expectedUsers, nil)
 
 	// Act
-	users, err := service.GetAllUsers(ctx)
+	users, err := service.GetAllUsers(ctx, models.UserFilter{})
 
 	// Assert
 	assert.NoError(t, err)
@@ -42,8 +49,9 @@ func TestUserService_GetUserById(t *testing.T) {
 	// Arrange
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	expectedUser := &models.User{
 		Id:      1,
@@ -67,8 +75,9 @@ func TestUserService_GetUserById_Error(t *testing.T) {
 	// Arrange
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	expectedErr := errors.New("user not found")
 	ctx := context.Background()
@@ -87,8 +96,9 @@ func TestUserService_CreateUser(t *testing.T) {
 	// Arrange
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	createRequest := models.CreateUserRequest{
 		Name:     "John",
@@ -114,25 +124,46 @@ func TestUserService_DeleteUser(t *testing.T) {
 	// Arrange
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	ctx := context.Background()
-	mockRepo.EXPECT().DeleteUser(ctx, 1).Return(nil)
+	mockRepo.EXPECT().GetUser(ctx, 1).Return(&models.User{Id: 1, Version: 2}, nil)
+	mockRepo.EXPECT().DeleteUser(ctx, 1, 2).Return(nil)
 
 	// Act
-	err := service.DeleteUser(ctx, 1)
+	err := service.DeleteUser(ctx, 1, `"2"`)
 
 	// Assert
 	assert.NoError(t, err)
 }
 
+func TestUserService_DeleteUser_StaleETag(t *testing.T) {
+	// Arrange
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	mockRepo.EXPECT().GetUser(ctx, 1).Return(&models.User{Id: 1, Version: 3}, nil)
+
+	// Act
+	err := service.DeleteUser(ctx, 1, `"2"`)
+
+	// Assert
+	assert.ErrorIs(t, err, repositories.ErrVersionMismatch)
+}
+
 func TestUserService_MakeTeacher(t *testing.T) {
 	// Arrange
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	ctx := context.Background()
 	mockRepo.EXPECT().MakeTeacher(ctx, 1).Return(nil)
@@ -148,8 +179,9 @@ func TestUserService_ModifyUser(t *testing.T) {
 	// Arrange
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	userId := 1
 	userToModify := models.UserUpdateDto{
@@ -175,18 +207,20 @@ func TestUserService_ModifyUser(t *testing.T) {
 	})).Return(nil)
 
 	// Act
-	err := service.ModifyUser(ctx, userId, userToModify)
+	user, err := service.ModifyUser(ctx, userId, userToModify, "*")
 
 	// Assert
 	assert.NoError(t, err)
+	assert.Equal(t, "John Updated", user.Name)
 }
 
 func TestUserService_ModifyLocation(t *testing.T) {
 	// Arrange
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	ctx := context.Background()
 	userId := 1
@@ -216,7 +250,7 @@ func TestUserService_ModifyLocation(t *testing.T) {
 	}
 
 	// Act
-	err := service.ModifyUser(ctx, userId, locationUser)
+	_, err := service.ModifyUser(ctx, userId, locationUser, "*")
 
 	// Assert
 	assert.NoError(t, err)
@@ -224,12 +258,94 @@ func TestUserService_ModifyLocation(t *testing.T) {
 	assert.Equal(t, newLocation, existingUser.Location)
 }
 
+func TestUserService_ModifyUser_GetUserError(t *testing.T) {
+	// Arrange
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	mockRepo.EXPECT().GetUser(ctx, 1).Return(nil, repositories.ErrNotFound)
+
+	// Act
+	user, err := service.ModifyUser(ctx, 1, models.UserUpdateDto{Name: "John"}, "*")
+
+	// Assert
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+	assert.Nil(t, user)
+}
+
+func TestUserService_PatchUser(t *testing.T) {
+	// Arrange
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	photo := "https://example.com/photo.png"
+	existingUser := &models.User{
+		Id:           1,
+		Name:         "John",
+		Surname:      "Doe",
+		Location:     "Buenos Aires",
+		Description:  "Student",
+		ProfilePhoto: &photo,
+		Version:      1,
+	}
+	patch := models.UserPatch{
+		Name:         models.PatchField[string]{Set: true, Value: "Johnny"},
+		Location:     models.PatchField[string]{Set: true, Null: true},
+		ProfilePhoto: models.PatchField[string]{Set: true, Null: true},
+	}
+
+	ctx := context.Background()
+	mockRepo.EXPECT().GetUser(ctx, 1).Return(existingUser, nil)
+	mockRepo.EXPECT().ModifyUser(ctx, mock.MatchedBy(func(u *models.User) bool {
+		return u.Name == "Johnny" &&
+			u.Surname == "Doe" &&
+			u.Location == "" &&
+			u.Description == "Student" &&
+			u.ProfilePhoto == nil
+	})).Return(nil)
+
+	// Act
+	user, err := service.PatchUser(ctx, 1, patch, `"1"`)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, existingUser, user)
+}
+
+func TestUserService_PatchUser_ModifyError(t *testing.T) {
+	// Arrange
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	mockRepo.EXPECT().GetUser(ctx, 1).Return(&models.User{Id: 1}, nil)
+	mockRepo.EXPECT().ModifyUser(ctx, mock.Anything).Return(errors.New("db error"))
+
+	// Act
+	user, err := service.PatchUser(ctx, 1, models.UserPatch{}, "*")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, user)
+}
+
 func TestUserService_GetUserByEmail(t *testing.T) {
 	// Arrange
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	expectedUser := &models.User{
 		Id:      1,
@@ -253,8 +369,9 @@ func TestUserService_BlockUser(t *testing.T) {
 	// Arrange
 	mockUserRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockUserRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockUserRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	expectedUser := &models.User{
 		Id:      1,
@@ -278,8 +395,9 @@ func TestUserService_IsUserBlocked(t *testing.T) {
 	// Arrange
 	mockUserRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockUserRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockUserRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	ctx := context.Background()
 	userId := 1
@@ -299,8 +417,9 @@ func TestUserService_ModifyPassword(t *testing.T) {
 
 	mockUserRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockUserRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockUserRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	ctx := context.Background()
 
@@ -310,11 +429,37 @@ func TestUserService_ModifyPassword(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestUserService_RehashPassword(t *testing.T) {
+	mockUserRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockUserRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	mockUserRepo.EXPECT().RehashPassword(ctx, 1, "legacy hash", mock.Anything).
+		RunAndReturn(func(_ context.Context, _ int, _ string, hash string) error {
+			assert.NoError(t, utils.CompareHashPassword(hash, "Secret123"))
+			assert.False(t, utils.DefaultHashParams().NeedsRehash(hash))
+			return nil
+		}).Once()
+
+	err := service.RehashPassword(ctx, models.User{Id: 1, Password: "legacy hash"}, "Secret123")
+	assert.NoError(t, err)
+
+	// Hashes made with the current parameters are left as they are
+	current, err := utils.HashPassword("Secret123")
+	require.NoError(t, err)
+	err = service.RehashPassword(ctx, models.User{Id: 1, Password: current}, "Secret123")
+	assert.NoError(t, err)
+}
+
 func TestUserService_AddNotificationToken(t *testing.T) {
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	ctx := context.Background()
 	userID := 1
@@ -333,8 +478,9 @@ func TestUserService_AddNotificationToken(t *testing.T) {
 func TestUserService_GetUserNotificationsToken(t *testing.T) {
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	ctx := context.Background()
 	userID := 1
@@ -360,8 +506,9 @@ func TestUserService_GetUserNotificationsToken(t *testing.T) {
 func TestUserService_VerifyUser(t *testing.T) {
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	ctx := context.Background()
 	userID := 1
@@ -376,30 +523,19 @@ func TestUserService_VerifyUser(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
-func TestUserService_SetPasswordTokenUsed(t *testing.T) {
-	mockRepo := repositories.NewMockUserRepository(t)
-	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
-
-	ctx := context.Background()
-	token := "123456"
-
-	mockRepo.EXPECT().
-		SetPasswordTokenUsed(ctx, token).
-		Return(nil)
-
-	err := service.SetPasswordTokenUsed(ctx, token)
-
-	assert.NoError(t, err)
-	mockRepo.AssertExpectations(t)
+var notificationTypes = []models.NotificationType{
+	{Name: "exam_notification"},
+	{Name: "homework_notification"},
+	{Name: "social_notification"},
+	{Name: models.RuleNotification, Mandatory: true},
 }
 
 func TestUserService_SetNotificationPreference(t *testing.T) {
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	ctx := context.Background()
 	userID := 1
@@ -408,9 +544,14 @@ func TestUserService_SetNotificationPreference(t *testing.T) {
 		NotificationPreference: false,
 	}
 
+	// The type is turned off by every channel
+	mockRepo.EXPECT().GetNotificationTypes(ctx).Return(notificationTypes, nil).Times(2)
 	mockRepo.EXPECT().
-		SetNotificationPreference(ctx, userID, preference).
+		SetNotificationPreferences(ctx, userID, models.NotificationPreferences{
+			"exam_notification": {"inbox": false, "push": false, "email": false},
+		}).
 		Return(nil)
+	mockRepo.EXPECT().GetNotificationPreferences(ctx, userID).Return(models.NotificationPreferences{}, nil)
 
 	err := service.SetNotificationPreference(ctx, userID, preference)
 
@@ -418,70 +559,129 @@ func TestUserService_SetNotificationPreference(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
-func TestUserService_CheckPreference(t *testing.T) {
+func TestUserService_GetNotificationPreference(t *testing.T) {
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	ctx := context.Background()
 	userID := 1
-	notificationType := "email"
 
+	// A type is on while any channel is
 	mockRepo.EXPECT().
-		CheckPreference(ctx, userID, notificationType).
-		Return(true, nil)
+		GetNotificationPreferences(ctx, userID).
+		Return(models.NotificationPreferences{
+			"exam_notification":     {"inbox": false, "push": true, "email": false},
+			"homework_notification": {"inbox": false, "push": false, "email": false},
+			"social_notification":   {"inbox": false, "push": false, "email": false},
+		}, nil)
 
-	result, err := service.CheckPreference(ctx, userID, notificationType)
+	result, err := service.GetNotificationPreference(ctx, userID)
 
 	assert.NoError(t, err)
-	assert.True(t, result)
+	assert.Equal(t, &models.NotificationPreference{ExamNotification: true}, result)
 	mockRepo.AssertExpectations(t)
 }
 
-func TestUserService_GetNotificationPreference(t *testing.T) {
+func TestUserService_UpdateNotificationPreferences(t *testing.T) {
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	ctx := context.Background()
-	userID := 1
-	expected := &models.NotificationPreference{
-		ExamNotification:     true,
-		HomeworkNotification: false,
-		SocialNotification:   false,
+	preferences := models.NotificationPreferences{
+		"social_notification":   {"push": false, "email": false},
+		models.RuleNotification: {"inbox": true},
 	}
+	matrix := models.NotificationPreferences{"social_notification": {"inbox": true, "push": false, "email": false}}
 
-	mockRepo.EXPECT().
-		GetNotificationPreference(ctx, userID).
-		Return(expected, nil)
+	mockRepo.EXPECT().GetNotificationTypes(ctx).Return(notificationTypes, nil).Times(2)
+	mockRepo.EXPECT().SetNotificationPreferences(ctx, 1, preferences).Return(nil)
+	mockRepo.EXPECT().GetNotificationPreferences(ctx, 1).Return(matrix, nil)
 
-	result, err := service.GetNotificationPreference(ctx, userID)
+	settings, err := service.UpdateNotificationPreferences(ctx, 1, preferences)
 
 	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
-	mockRepo.AssertExpectations(t)
+	assert.Equal(t, &models.NotificationSettings{
+		Types:       notificationTypes,
+		Channels:    models.NotificationChannels,
+		Preferences: matrix,
+	}, settings)
 }
 
+func TestUserService_UpdateNotificationPreferences_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		preferences models.NotificationPreferences
+		err         error
+	}{
+		{"unknown type", models.NotificationPreferences{"grades": {"push": false}}, services.ErrUnknownNotificationType},
+		{"unknown channel", models.NotificationPreferences{"exam_notification": {"sms": false}}, services.ErrUnknownNotificationChannel},
+		{"mandatory type off", models.NotificationPreferences{models.RuleNotification: {"email": false}}, services.ErrMandatoryNotification},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repositories.NewMockUserRepository(t)
+			service := services.NewUserService(mockRepo, repositories.NewMockBlockedUserRepository(t),
+				repositories.NewMockJobRepository(t), repositories.NewMockInboxRepository(t), testPublicURL, utils.DefaultHashParams())
+
+			// Nothing is saved
+			mockRepo.EXPECT().GetNotificationTypes(mock.Anything).Return(notificationTypes, nil)
+
+			_, err := service.UpdateNotificationPreferences(context.Background(), 1, tt.preferences)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestUserService_GetNotificationChannels_UnknownType(t *testing.T) {
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	mockRepo.EXPECT().GetNotificationChannels(ctx, 1, "grades").Return(nil, repositories.ErrNotFound)
+
+	_, err := service.GetNotificationChannels(ctx, 1, "grades")
+	assert.ErrorIs(t, err, services.ErrUnknownNotificationType)
+}
+
+// sha256 of "abc123"
+const resetTokenHash = "6ca13d52ca70c883e0f0bb101e425a89e8624de51db2d2392593af6a84118090"
+
 func TestUserService_ValidatePasswordResetToken_Valid(t *testing.T) {
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	ctx := context.Background()
-	token := "valid-token"
+	token := "4-abc123"
 	expected := &models.PasswordResetData{
-		Email:  "test@email.com",
-		UserId: 1,
-		Exp:    time.Now().Add(1 * time.Hour),
-		Used:   false,
+		Id:        4,
+		Email:     "test@email.com",
+		UserId:    1,
+		TokenHash: resetTokenHash,
+		Exp:       time.Now().Add(1 * time.Hour),
+		Used:      false,
+		Attempts:  services.MaxResetAttempts,
 	}
 
+	// The last attempt allowed
 	mockRepo.EXPECT().
-		GetPasswordResetTokenInfo(ctx, token).
+		AddPasswordResetAttempt(ctx, 4).
 		Return(expected, nil)
+	// Only wrong secrets count, so link prefetches and rejected passwords don't lock out the user
+	mockRepo.EXPECT().
+		ReleasePasswordResetAttempt(ctx, 4).
+		Return(nil)
 
 	result, err := service.ValidatePasswordResetToken(ctx, token)
 
@@ -490,38 +690,84 @@ func TestUserService_ValidatePasswordResetToken_Valid(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
-func TestUserService_ValidatePasswordResetToken_Expired(t *testing.T) {
+func TestUserService_ValidatePasswordResetToken_Invalid(t *testing.T) {
+	valid := models.PasswordResetData{
+		Id:        4,
+		UserId:    1,
+		TokenHash: resetTokenHash,
+		Exp:       time.Now().Add(1 * time.Hour),
+	}
+	expired := valid
+	expired.Exp = time.Now().Add(-1 * time.Hour)
+	used := valid
+	used.Used = true
+	exhausted := valid
+	exhausted.Attempts = services.MaxResetAttempts + 1
+
+	tests := []struct {
+		name     string
+		token    string
+		data     *models.PasswordResetData
+		repoErr  error
+		expected error
+	}{
+		{name: "malformed", token: "abc123", expected: services.ErrInvalidResetToken},
+		{name: "non numeric id", token: "x-abc123", expected: services.ErrInvalidResetToken},
+		{name: "unknown id", token: "4-abc123", repoErr: repositories.ErrNotFound, expected: services.ErrInvalidResetToken},
+		{name: "expired", token: "4-abc123", data: &expired, expected: services.ErrInvalidResetToken},
+		{name: "already used", token: "4-abc123", data: &used, expected: services.ErrInvalidResetToken},
+		{name: "too many attempts", token: "4-abc123", data: &exhausted, expected: services.ErrResetAttemptsExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repositories.NewMockUserRepository(t)
+			service := services.NewUserService(mockRepo, repositories.NewMockBlockedUserRepository(t), repositories.NewMockJobRepository(t), repositories.NewMockInboxRepository(t), testPublicURL, utils.DefaultHashParams())
+			ctx := context.Background()
+
+			if tt.data != nil || tt.repoErr != nil {
+				mockRepo.EXPECT().AddPasswordResetAttempt(ctx, 4).Return(tt.data, tt.repoErr)
+			}
+
+			result, err := service.ValidatePasswordResetToken(ctx, tt.token)
+
+			assert.ErrorIs(t, err, tt.expected)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestUserService_ValidatePasswordResetToken_WrongSecretCountsAttempt(t *testing.T) {
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	ctx := context.Background()
-	token := "expired-token"
-	expired := &models.PasswordResetData{
-		Email:  "test@email.com",
-		UserId: 1,
-		Exp:    time.Now().Add(-1 * time.Hour),
-		Used:   false,
+	data := &models.PasswordResetData{
+		Id:        4,
+		UserId:    1,
+		TokenHash: resetTokenHash,
+		Exp:       time.Now().Add(1 * time.Hour),
+		Attempts:  1,
 	}
 
-	mockRepo.EXPECT().
-		GetPasswordResetTokenInfo(ctx, token).
-		Return(expired, nil)
+	// Counted before the secret is compared
+	mockRepo.EXPECT().AddPasswordResetAttempt(ctx, 4).Return(data, nil)
 
-	result, err := service.ValidatePasswordResetToken(ctx, token)
+	result, err := service.ValidatePasswordResetToken(ctx, "4-zzz999")
 
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, services.ErrInvalidResetToken)
 	assert.Nil(t, result)
-	assert.EqualError(t, err, "Token Expired")
-	mockRepo.AssertExpectations(t)
 }
 
 func TestSendNotifByEmail(t *testing.T) {
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	ctx := context.Background()
 	userId := 1
@@ -536,57 +782,111 @@ func TestSendNotifByEmail(t *testing.T) {
 		GetUser(ctx, userId).
 		Return(user, nil)
 
-	mockEmail.On("Send", mock.AnythingOfType("*mail.SGMailV3")).
-		Return(&rest.Response{StatusCode: 202}, nil)
+	var sent mailer.Message
+	mockJobs.EXPECT().Enqueue(ctx, mock.Anything).
+		RunAndReturn(func(_ context.Context, jobs ...models.Job) error {
+			require.Len(t, jobs, 1)
+			sent = jobEmail(t, jobs[0])
+			return nil
+		})
 
-	req := models.NotifyRequest{
-		NotificationTitle: "test",
-		NotificationText:  "test message",
-	}
+	entry := models.InboxEntry{Id: 12, UserId: userId, Title: "test", Text: "test message"}
 
-	err := service.SendNotifByEmail(ctx, userId, req)
+	err := service.SendNotifByEmail(ctx, entry)
 	assert.NoError(t, err)
+	assert.Equal(t, "test", sent.Subject)
+	assert.Equal(t, []mailer.Address{{Name: "User", Email: email}}, sent.To)
+	// The email links to the inbox entry
+	assert.Contains(t, sent.Text, "/users/1/inbox/12")
 
 	mockRepo.AssertExpectations(t)
-	mockEmail.AssertExpectations(t)
+}
+
+// jobEmail returns the message an email job sends
+func jobEmail(t *testing.T, job models.Job) mailer.Message {
+	t.Helper()
+	require.Equal(t, models.JobEmail, job.Type)
+	var message mailer.Message
+	require.NoError(t, json.Unmarshal(job.Payload, &message))
+	return message
+}
+
+// outboxEmails returns the messages the outbox sends for the row with the id
+func outboxEmails(t *testing.T, outbox models.Outbox, id int) []mailer.Message {
+	t.Helper()
+	jobs, err := outbox(id)
+	require.NoError(t, err)
+	messages := make([]mailer.Message, 0, len(jobs))
+	for _, job := range jobs {
+		messages = append(messages, jobEmail(t, job))
+	}
+	return messages
 }
 
 func TestStartPasswordReset(t *testing.T) {
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
-	ctx := context.Background()
+	// Emails are in Spanish unless the user accepts another language there is a variant for
+	ctx := mailer.WithLocales(context.Background(), []string{"fr", "en"})
 	userID := 1
 	email := "user@example.com"
 
 	mockRepo.EXPECT().
-		AddPasswordResetToken(ctx, userID, email, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
-		Return(nil)
+		GetUserByEmail(ctx, email).
+		Return(&models.User{Id: userID, Email: email}, nil)
 
-	mockEmail.On("Send", mock.AnythingOfType("*mail.SGMailV3")).
-		Return(&rest.Response{StatusCode: 202}, nil)
-
-	err := service.StartPasswordReset(ctx, userID, email)
+	var storedHash string
+	var sent []mailer.Message
+	mockRepo.EXPECT().
+		AddPasswordResetToken(ctx, userID, email, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), mock.Anything).
+		RunAndReturn(func(_ context.Context, _ int, _ string, tokenHash string, _ time.Time, outbox models.Outbox) (int, error) {
+			storedHash = tokenHash
+			sent = outboxEmails(t, outbox, 9)
+			return 9, nil
+		})
+
+	err := service.StartPasswordReset(ctx, email)
 	assert.NoError(t, err)
 
+	// Only the hash is stored, the email carries the "<id>-<secret>" token
+	require.Len(t, sent, 1)
+	body := sent[0].Text
+	assert.Contains(t, body, "Your reset token is 9-")
+	assert.Len(t, storedHash, 64)
+	assert.NotContains(t, body, storedHash)
+
 	mockRepo.AssertExpectations(t)
-	mockEmail.AssertExpectations(t)
 }
 
-func TestSendNotifByMobile_Expo(t *testing.T) {
-	httpmock.Activate()
-	defer httpmock.DeactivateAndReset()
+func TestStartPasswordReset_UnknownEmail(t *testing.T) {
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+
+	mockRepo.EXPECT().
+		GetUserByEmail(ctx, "nobody@example.com").
+		Return(nil, repositories.ErrNotFound)
 
-	httpmock.RegisterResponder("POST", "https://exp.host/--/api/v2/push/send",
-		httpmock.NewStringResponder(200, `{"status":"ok"}`),
-	)
+	err := service.StartPasswordReset(ctx, "nobody@example.com")
 
+	// No error and no email, so the response doesn't tell whether the account exists
+	assert.NoError(t, err)
+}
+
+func TestSendNotifByMobile(t *testing.T) {
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
 	mockRepo.
 		EXPECT().
@@ -594,43 +894,446 @@ func TestSendNotifByMobile_Expo(t *testing.T) {
 		Return(models.NotificationTokens{
 			NotificationTokens: []models.NotificationToken{
 				{NotificationToken: "ExponentPushToken[1234567890]"},
+				{NotificationToken: "firebase_token_123"},
 			},
 		}, nil)
 
-	err := service.SendNotifByMobile(context.Background(), 1, models.NotifyRequest{
-		NotificationTitle: "Test Title",
-		NotificationText:  "This is a test",
+	// One job per device, so a failing device doesn't resend to the rest
+	var pushes []models.PushJob
+	mockJobs.EXPECT().Enqueue(context.Background(), mock.Anything).
+		RunAndReturn(func(_ context.Context, jobs ...models.Job) error {
+			for _, job := range jobs {
+				assert.Equal(t, models.JobPush, job.Type)
+				var push models.PushJob
+				require.NoError(t, json.Unmarshal(job.Payload, &push))
+				pushes = append(pushes, push)
+			}
+			return nil
+		})
+
+	err := service.SendNotifByMobile(context.Background(), models.InboxEntry{
+		Id:     12,
+		UserId: 1,
+		Title:  "Test Title",
+		Text:   "This is a test",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.PushJob{
+		{UserId: 1, Token: "ExponentPushToken[1234567890]", Title: "Test Title", Text: "This is a test", InboxId: 12},
+		{UserId: 1, Token: "firebase_token_123", Title: "Test Title", Text: "This is a test", InboxId: 12},
+	}, pushes)
+}
+
+func TestUserService_NotifyUsers(t *testing.T) {
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	notification := models.NotifyRequest{NotificationTitle: "Exam", NotificationText: "Tomorrow", NotificationType: "exam_notification"}
+	userErr := errors.New("user not found")
+	mockRepo.EXPECT().GetNotificationTypes(ctx).Return([]models.NotificationType{{Name: "exam_notification"}, {Name: "rule_notification", Mandatory: true}}, nil)
+	mockRepo.EXPECT().GetUser(ctx, 3).Return(&models.User{Id: 3, Email: "third@example.com"}, nil)
+	mockRepo.EXPECT().GetUser(ctx, 4).Return(nil, userErr)
+	mockRepo.EXPECT().GetNotificationChannels(ctx, 3, "exam_notification").
+		Return(map[string]bool{"inbox": true, "push": true, "email": true}, nil)
+	mockRepo.EXPECT().GetUserNotificationsToken(ctx, 3).Return(models.NotificationTokens{
+		NotificationTokens: []models.NotificationToken{{NotificationToken: "ExponentPushToken[3]"}},
+	}, nil)
+
+	// The jobs are queued along with the entry, linking to it
+	mockInbox.EXPECT().AddInboxEntry(ctx, &models.InboxEntry{UserId: 3, Type: "exam_notification", Title: "Exam", Text: "Tomorrow"}, mock.Anything).
+		RunAndReturn(func(_ context.Context, entry *models.InboxEntry, outbox models.Outbox) error {
+			jobs, err := outbox(12)
+			require.NoError(t, err)
+			require.Len(t, jobs, 2)
+			assert.Equal(t, models.JobPush, jobs[0].Type)
+			message := jobEmail(t, jobs[1])
+			assert.Equal(t, "third@example.com", message.To[0].Email)
+			assert.Contains(t, message.Text, testPublicURL+"/users/3/inbox/12")
+			return nil
+		})
+
+	err := service.NotifyUsers(ctx, []int{3, 4}, notification)
+	assert.ErrorIs(t, err, userErr)
+}
+
+func TestUserService_NotifyUsers_Mandatory(t *testing.T) {
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	mockRepo.EXPECT().GetNotificationTypes(ctx).Return([]models.NotificationType{{Name: "rule_notification", Mandatory: true}}, nil)
+
+	// Nobody is looked up or notified
+	err := service.NotifyUsers(ctx, []int{3}, models.NotifyRequest{NotificationTitle: "Rule", NotificationText: "In force", NotificationType: "rule_notification"})
+	assert.ErrorIs(t, err, services.ErrMandatoryNotificationSend)
+}
+
+func TestUserService_RequestEmailChange(t *testing.T) {
+	// Arrange
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	var sent []mailer.Message
+	var stored *models.EmailChangeRequest
+	mockRepo.EXPECT().GetUser(ctx, 1).Return(&models.User{Id: 1, Email: "old@example.com"}, nil)
+	mockRepo.EXPECT().EmailExists(ctx, "new@example.com").Return(false, nil)
+	mockRepo.EXPECT().AddEmailChangeRequest(ctx, mock.MatchedBy(func(r *models.EmailChangeRequest) bool {
+		return r.UserId == 1 &&
+			r.OldEmail == "old@example.com" &&
+			r.NewEmail == "new@example.com" &&
+			len(r.VerificationPin) == 6 &&
+			len(r.CancelTokenHash) == 64 &&
+			r.PinExpiration.After(time.Now())
+	}), mock.Anything).RunAndReturn(func(_ context.Context, r *models.EmailChangeRequest, outbox models.Outbox) (int, error) {
+		stored = r
+		sent = outboxEmails(t, outbox, 7)
+		return 7, nil
 	})
 
+	// Act
+	err := service.RequestEmailChange(ctx, 1, "new@example.com")
+
+	// Assert
+	assert.NoError(t, err)
+	require.Len(t, sent, 2)
+	assert.Equal(t, "new@example.com", sent[0].To[0].Email)
+	assert.Contains(t, sent[0].Text, "7-")
+	assert.Equal(t, "old@example.com", sent[1].To[0].Email)
+	assert.Contains(t, sent[1].Text, "/users/email/cancel?token=")
+	// Only the hash of the token sent in the link is stored
+	assert.NotContains(t, sent[1].Text, stored.CancelTokenHash)
+}
+
+func TestUserService_CancelEmailChange(t *testing.T) {
+	// Arrange
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	mockRepo.EXPECT().DeleteEmailChangeRequestByCancelToken(ctx, resetTokenHash).Return(nil)
+
+	// Act
+	err := service.CancelEmailChange(ctx, "abc123")
+
+	// Assert
 	assert.NoError(t, err)
+}
+
+func TestUserService_RequestEmailChange_SameEmail(t *testing.T) {
+	// Arrange
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	mockRepo.EXPECT().GetUser(ctx, 1).Return(&models.User{Id: 1, Email: "old@example.com"}, nil)
+
+	// Act
+	err := service.RequestEmailChange(ctx, 1, "OLD@example.com")
 
+	// Assert
+	assert.ErrorIs(t, err, services.ErrSameEmail)
 }
 
-func TestSendNotifByMobile_FirebaseServiceAccountNotSet(t *testing.T) {
+func TestUserService_RequestEmailChange_EmailTaken(t *testing.T) {
+	// Arrange
 	mockRepo := repositories.NewMockUserRepository(t)
 	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
-	mockEmail := services.NewMockEmailSender(t)
-	service := services.NewUserService(mockRepo, mockBlockedRepo, mockEmail)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
 
-	t.Setenv("FIREBASE_SERVICE_ACCOUNT", "")
+	ctx := context.Background()
+	mockRepo.EXPECT().GetUser(ctx, 1).Return(&models.User{Id: 1, Email: "old@example.com"}, nil)
+	mockRepo.EXPECT().EmailExists(ctx, "Taken@example.com").Return(true, nil)
 
-	userId := 1
-	notification := models.NotifyRequest{
-		NotificationTitle: "Test Title",
-		NotificationText:  "Test Body",
+	// Act
+	err := service.RequestEmailChange(ctx, 1, "Taken@example.com")
+
+	// Assert
+	assert.ErrorIs(t, err, repositories.ErrEmailTaken)
+}
+
+func TestUserService_ConfirmEmailChange(t *testing.T) {
+	// Arrange
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	request := &models.EmailChangeRequest{
+		Id:              7,
+		UserId:          1,
+		NewEmail:        "new@example.com",
+		VerificationPin: "AB12CD",
+		PinExpiration:   time.Now().Add(time.Minute),
+	}
+	mockRepo.EXPECT().AddEmailChangeAttempt(ctx, 7).Return(request, nil)
+	mockRepo.EXPECT().ReleaseEmailChangeAttempt(ctx, 7).Return(nil)
+	mockRepo.EXPECT().ApplyEmailChange(ctx, request).Return(nil)
+
+	// Act
+	err := service.ConfirmEmailChange(ctx, 1, "7-AB12CD")
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestUserService_ConfirmEmailChange_InvalidPin(t *testing.T) {
+	// Arrange
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	request := &models.EmailChangeRequest{
+		Id:              7,
+		UserId:          1,
+		VerificationPin: "AB12CD",
+		PinExpiration:   time.Now().Add(time.Minute),
 	}
+	mockRepo.EXPECT().AddEmailChangeAttempt(ctx, 7).Return(request, nil)
+
+	// Act & Assert
+	assert.ErrorIs(t, service.ConfirmEmailChange(ctx, 1, "bad"), services.ErrInvalidPin)
+	assert.ErrorIs(t, service.ConfirmEmailChange(ctx, 1, "7-ZZZZZZ"), services.ErrInvalidPin)
+	// Another user's request can't be confirmed even with the right pin
+	assert.ErrorIs(t, service.ConfirmEmailChange(ctx, 2, "7-AB12CD"), services.ErrInvalidPin)
+}
+
+func TestUserService_ConfirmEmailChange_Expired(t *testing.T) {
+	// Arrange
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	mockRepo.EXPECT().AddEmailChangeAttempt(ctx, 7).Return(&models.EmailChangeRequest{
+		Id:              7,
+		UserId:          1,
+		VerificationPin: "AB12CD",
+		PinExpiration:   time.Now().Add(-time.Minute),
+	}, nil)
+	mockRepo.EXPECT().ReleaseEmailChangeAttempt(ctx, 7).Return(nil)
+
+	// Act
+	err := service.ConfirmEmailChange(ctx, 1, "7-AB12CD")
+
+	// Assert
+	assert.ErrorIs(t, err, services.ErrPinExpired)
+}
+
+func TestUserService_ConfirmEmailChange_AttemptsExceeded(t *testing.T) {
+	// Arrange
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	mockRepo.EXPECT().AddEmailChangeAttempt(ctx, 7).Return(&models.EmailChangeRequest{
+		Id:              7,
+		UserId:          1,
+		VerificationPin: "AB12CD",
+		PinExpiration:   time.Now().Add(time.Minute),
+		Attempts:        services.MaxResetAttempts + 1,
+	}, nil)
+
+	// Act
+	err := service.ConfirmEmailChange(ctx, 1, "7-AB12CD")
+
+	// Assert: even the right pin is rejected once the attempts ran out
+	assert.ErrorIs(t, err, services.ErrPinAttemptsExceeded)
+}
+
+func TestUserService_IsSessionRevoked(t *testing.T) {
+	// Arrange
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	validAfter := time.Date(2025, 6, 1, 12, 0, 0, 500, time.UTC)
+	mockRepo.EXPECT().GetSessionsValidAfter(ctx, 1).Return(&validAfter, nil)
+	mockRepo.EXPECT().GetSessionsValidAfter(ctx, 2).Return(nil, nil)
+	mockRepo.EXPECT().GetSessionsValidAfter(ctx, 3).Return(nil, repositories.ErrNotFound)
+
+	// Act & Assert
+	revoked, err := service.IsSessionRevoked(ctx, 1, validAfter.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = service.IsSessionRevoked(ctx, 1, validAfter.Truncate(time.Second))
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	revoked, err = service.IsSessionRevoked(ctx, 2, time.Now())
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	revoked, err = service.IsSessionRevoked(ctx, 3, time.Now())
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestUserService_SendInvitation(t *testing.T) {
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	user := &models.User{Id: 1, Name: "Ana", Email: "ana@example.com"}
 
-	// Non-Expo token to trigger Firebase path
+	var sent []mailer.Message
 	mockRepo.EXPECT().
-		GetUserNotificationsToken(context.Background(), userId).
-		Return(models.NotificationTokens{
-			NotificationTokens: []models.NotificationToken{
-				{NotificationToken: "firebase_token_123"},
-			},
-		}, nil).
-		Once()
+		AddPasswordResetToken(ctx, 1, user.Email, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), mock.Anything).
+		RunAndReturn(func(_ context.Context, _ int, _ string, _ string, expiration time.Time, outbox models.Outbox) (int, error) {
+			// Invitations last much longer than regular resets
+			assert.WithinDuration(t, time.Now().Add(services.InvitationLifeTime*time.Minute), expiration, time.Minute)
+			sent = outboxEmails(t, outbox, 3)
+			return 3, nil
+		})
+
+	err := service.SendInvitation(ctx, user)
 
-	err := service.SendNotifByMobile(context.Background(), userId, notification)
+	assert.NoError(t, err)
+	require.Len(t, sent, 1)
+	assert.Equal(t, []mailer.Address{{Name: "Ana", Email: user.Email}}, sent[0].To)
+	assert.Contains(t, sent[0].Text, "Choose your password with the token 3-")
+}
+
+func TestUserService_NotifyAllUsers(t *testing.T) {
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	notBlocked := false
+	notification := models.NotifyRequest{NotificationTitle: "New rule in force: Attendance", NotificationText: "Attend classes", NotificationType: models.RuleNotification}
+	mockRepo.EXPECT().StreamUsers(ctx, models.UserFilter{Blocked: &notBlocked}, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ models.UserFilter, fn func(models.User) error) error {
+			if err := fn(models.User{Id: 1, Email: "first@example.com"}); err != nil {
+				return err
+			}
+			return fn(models.User{Id: 2, Email: "second@example.com"})
+		})
+	allChannels := map[string]bool{"inbox": true, "push": true, "email": true}
+	mockRepo.EXPECT().GetNotificationChannels(ctx, 1, models.RuleNotification).Return(allChannels, nil)
+	mockRepo.EXPECT().GetNotificationChannels(ctx, 2, models.RuleNotification).Return(allChannels, nil)
+	tokensErr := errors.New("tokens failed")
+	mockRepo.EXPECT().GetUserNotificationsToken(ctx, 1).Return(models.NotificationTokens{}, tokensErr)
+	mockRepo.EXPECT().GetUserNotificationsToken(ctx, 2).Return(models.NotificationTokens{
+		NotificationTokens: []models.NotificationToken{{NotificationToken: "ExponentPushToken[2]"}},
+	}, nil)
+
+	// Each user gets an inbox entry with its jobs, a user failing doesn't stop the rest
+	var types []string
+	var emails []string
+	var inboxed []int
+	mockInbox.EXPECT().AddInboxEntry(ctx, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, entry *models.InboxEntry, outbox models.Outbox) error {
+			assert.Equal(t, models.RuleNotification, entry.Type)
+			inboxed = append(inboxed, entry.UserId)
+			jobs, err := outbox(10 + entry.UserId)
+			require.NoError(t, err)
+			for _, job := range jobs {
+				types = append(types, job.Type)
+				if job.Type == models.JobEmail {
+					message := jobEmail(t, job)
+					emails = append(emails, message.To[0].Email)
+					assert.Contains(t, message.Text, fmt.Sprintf("/users/%d/inbox/%d", entry.UserId, 10+entry.UserId))
+				}
+			}
+			return nil
+		}).Times(2)
+
+	err := service.NotifyAllUsers(ctx, notification)
+	assert.ErrorIs(t, err, tokensErr)
+	assert.Equal(t, []int{1, 2}, inboxed)
+	assert.Equal(t, []string{models.JobEmail, models.JobPush, models.JobEmail}, types)
+	assert.Equal(t, []string{"first@example.com", "second@example.com"}, emails)
+}
+
+func TestUserService_NotifyAllUsers_Channels(t *testing.T) {
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	notification := models.NotifyRequest{NotificationTitle: "Grades", NotificationText: "Grades are out", NotificationType: "social_notification"}
+	mockRepo.EXPECT().StreamUsers(ctx, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ models.UserFilter, fn func(models.User) error) error {
+			return fn(models.User{Id: 1, Email: "first@example.com"})
+		})
+	mockRepo.EXPECT().GetNotificationChannels(ctx, 1, "social_notification").
+		Return(map[string]bool{"inbox": false, "push": false, "email": true}, nil)
+
+	// Without the inbox only the email is queued, and it has nothing to link to
+	mockJobs.EXPECT().Enqueue(ctx, mock.Anything).
+		RunAndReturn(func(_ context.Context, jobs ...models.Job) error {
+			require.Len(t, jobs, 1)
+			message := jobEmail(t, jobs[0])
+			assert.Equal(t, "first@example.com", message.To[0].Email)
+			assert.NotContains(t, message.Text, "/inbox/")
+			return nil
+		})
+
+	err := service.NotifyAllUsers(ctx, notification)
+	assert.NoError(t, err)
+}
+
+func TestUserService_StreamUsers(t *testing.T) {
+	mockRepo := repositories.NewMockUserRepository(t)
+	mockBlockedRepo := repositories.NewMockBlockedUserRepository(t)
+	mockJobs := repositories.NewMockJobRepository(t)
+	mockInbox := repositories.NewMockInboxRepository(t)
+	service := services.NewUserService(mockRepo, mockBlockedRepo, mockJobs, mockInbox, testPublicURL, utils.DefaultHashParams())
+
+	ctx := context.Background()
+	filter := models.UserFilter{Role: "admin"}
+	mockRepo.EXPECT().StreamUsers(ctx, filter, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ models.UserFilter, fn func(models.User) error) error {
+			return fn(models.User{Id: 1})
+		})
+
+	var ids []int
+	err := service.StreamUsers(ctx, filter, func(user models.User) error {
+		ids = append(ids, user.Id)
+		return nil
+	})
 
 	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, ids)
 }
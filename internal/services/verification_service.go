@@ -4,22 +4,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/sendgrid/rest"
 	"time"
 
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/mailer"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
 	repo "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
-	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
 	"github.com/sethvargo/go-password/password"
 )
 
 const PinLifeTime = 5
 
-type EmailSender interface {
-	Send(email *mail.SGMailV3) (*rest.Response, error)
-}
-
 type VerificationService interface {
+	// RegisterUser creates the user and emails it a verification pin, both or neither
+	RegisterUser(ctx context.Context, request models.CreateUserRequest) (int, error)
 	SendVerificationEmail(ctx context.Context, userId int, email string) error
 	GetVerification(ctx context.Context, id int) (*models.UserVerification, error)
 	DeleteByUserId(ctx context.Context, userId int) error
@@ -28,46 +26,63 @@ type VerificationService interface {
 
 type verificationService struct {
 	verificationRepo repo.VerificationRepository
-	emailClient      EmailSender
+	// Algorithm and parameters the password of registered users is hashed with
+	hashParams utils.HashParams
 }
 
-func NewVerificationService(verificationRepo repo.VerificationRepository, emailClient EmailSender) *verificationService {
+func NewVerificationService(verificationRepo repo.VerificationRepository, hashParams utils.HashParams) *verificationService {
 	return &verificationService{
 		verificationRepo: verificationRepo,
-		emailClient:      emailClient,
+		hashParams:       hashParams,
 	}
 }
 
-func (s *verificationService) SendVerificationEmail(ctx context.Context, userId int, email string) error {
+// verificationOutbox emails the "<verification id>-<pin>" code to the address being verified
+func verificationOutbox(ctx context.Context, email string, pin string) models.Outbox {
+	return func(id int) ([]models.Job, error) {
+		job, err := secretEmailJob(ctx, mailer.TemplateVerification,
+			mailer.CodeData{Code: fmt.Sprintf("%d-%s", id, pin), Minutes: PinLifeTime}, mailer.Address{Name: "User", Email: email})
+		return []models.Job{job}, err
+	}
+}
+
+// newVerification makes a pin for the email that expires in PinLifeTime minutes
+func newVerification(userId int, email string) (*models.UserVerification, error) {
 	pin, err := password.Generate(6, 2, 0, false, true)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	println(userId)
-	verification := &models.UserVerification{
+	return &models.UserVerification{
 		UserEmail:       email,
 		UserId:          userId,
 		VerificationPin: pin,
 		PinExpiration:   time.Now().Add(PinLifeTime * time.Minute),
-	}
+	}, nil
+}
 
-	id, err := s.verificationRepo.AddPendingVerification(ctx, verification)
+func (s *verificationService) RegisterUser(ctx context.Context, request models.CreateUserRequest) (int, error) {
+	user, err := newUser(request, s.hashParams)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	verification, err := newVerification(0, request.Email)
+	if err != nil {
+		return 0, err
 	}
 
-	message := mail.NewV3MailInit(
-		mail.NewEmail("ClassConnect service", "bmorseletto@fi.uba.ar"),
-		"Verification Code",
-		mail.NewEmail("User", email),
-		mail.NewContent("text/plain", fmt.Sprintf("Your verification code is %d-%s", id, pin)),
-	)
+	// The email is sent in the background, registering doesn't wait for the email provider
+	return s.verificationRepo.AddUserPendingVerification(ctx, user, verification,
+		verificationOutbox(ctx, request.Email, verification.VerificationPin))
+}
 
-	if _, err := s.emailClient.Send(message); err != nil {
+func (s *verificationService) SendVerificationEmail(ctx context.Context, userId int, email string) error {
+	verification, err := newVerification(userId, email)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	_, err = s.verificationRepo.AddPendingVerification(ctx, verification, verificationOutbox(ctx, email, verification.VerificationPin))
+	return err
 }
 
 func (s *verificationService) GetVerification(ctx context.Context, id int) (*models.UserVerification, error) {
@@ -96,21 +111,5 @@ func (s *verificationService) UpdatePin(ctx context.Context, userId int, email s
 		return err
 	}
 
-	err = s.verificationRepo.UpdatePin(ctx, verification.Id, pin)
-	if err != nil {
-		return err
-	}
-
-	message := mail.NewV3MailInit(
-		mail.NewEmail("ClassConnect service", "bmorseletto@fi.uba.ar"),
-		"Verification Code",
-		mail.NewEmail("User", email),
-		mail.NewContent("text/plain", fmt.Sprintf("Your verification code is %d-%s", verification.Id, pin)),
-	)
-
-	if _, err := s.emailClient.Send(message); err != nil {
-		return err
-	}
-
-	return nil
+	return s.verificationRepo.UpdatePin(ctx, verification.Id, pin, verificationOutbox(ctx, email, pin))
 }
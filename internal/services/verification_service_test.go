@@ -2,26 +2,29 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/mailer"
 	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/models"
 	repo "github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/repositories"
-	"github.com/sendgrid/rest"
+	"github.com/Ingenieria-de-Software-2-Gupo-14/user-api/internal/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"testing"
 	"time"
 )
 
 func TestNewVerificationService(t *testing.T) {
 	mockRepo := repo.NewMockVerificationRepository(t)
-	mockEmail := NewMockEmailSender(t)
-	service := NewVerificationService(mockRepo, mockEmail)
+	service := NewVerificationService(mockRepo, utils.DefaultHashParams())
 
 	assert.NotNil(t, service)
 }
 
 func TestVerificationService_GetVerification(t *testing.T) {
 	mockRepo := repo.NewMockVerificationRepository(t)
-	service := NewVerificationService(mockRepo, nil)
+	service := NewVerificationService(mockRepo, utils.DefaultHashParams())
 
 	ctx := context.Background()
 	userID := 1
@@ -47,7 +50,7 @@ func TestVerificationService_GetVerification(t *testing.T) {
 
 func TestVerificationService_GetVerificationByEmail(t *testing.T) {
 	mockRepo := repo.NewMockVerificationRepository(t)
-	service := NewVerificationService(mockRepo, nil)
+	service := NewVerificationService(mockRepo, utils.DefaultHashParams())
 
 	ctx := context.Background()
 	email := "test@email.com"
@@ -74,7 +77,7 @@ func TestVerificationService_GetVerificationByEmail(t *testing.T) {
 
 func TestVerificationService_DeleteByUserId(t *testing.T) {
 	mockRepo := repo.NewMockVerificationRepository(t)
-	service := NewVerificationService(mockRepo, nil)
+	service := NewVerificationService(mockRepo, utils.DefaultHashParams())
 
 	ctx := context.Background()
 	userID := 1
@@ -89,36 +92,84 @@ func TestVerificationService_DeleteByUserId(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+// outboxEmail returns the email the outbox sends for the row with the id
+func outboxEmail(t *testing.T, outbox models.Outbox, id int) mailer.Message {
+	jobs, err := outbox(id)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, models.JobEmail, jobs[0].Type)
+	var message mailer.Message
+	require.NoError(t, json.Unmarshal(jobs[0].Payload, &message))
+	return message
+}
+
 func TestSendVerificationEmail(t *testing.T) {
 	mockRepo := repo.NewMockVerificationRepository(t)
-	mockEmail := NewMockEmailSender(t)
-
-	service := NewVerificationService(mockRepo, mockEmail)
+	service := NewVerificationService(mockRepo, utils.DefaultHashParams())
 
 	ctx := context.Background()
 	userID := 1
 	email := "user@test.com"
 
+	var sent mailer.Message
 	mockRepo.EXPECT().
-		AddPendingVerification(ctx, mock.AnythingOfType("*models.UserVerification")).
-		Return(123, nil)
-
-	mockEmail.On("Send", mock.AnythingOfType("*mail.SGMailV3")).
-		Return(&rest.Response{StatusCode: 202}, nil)
+		AddPendingVerification(ctx, mock.AnythingOfType("*models.UserVerification"), mock.Anything).
+		RunAndReturn(func(_ context.Context, verification *models.UserVerification, outbox models.Outbox) (int, error) {
+			sent = outboxEmail(t, outbox, 123)
+			return 123, nil
+		})
 
 	err := service.SendVerificationEmail(ctx, userID, email)
 
 	assert.NoError(t, err)
+	assert.Equal(t, []mailer.Address{{Name: "User", Email: email}}, sent.To)
+	assert.Contains(t, sent.Text, "Your verification code is 123-")
 	mockRepo.AssertExpectations(t)
-	mockEmail.AssertExpectations(t)
+}
+
+func TestSendVerificationEmail_Error(t *testing.T) {
+	mockRepo := repo.NewMockVerificationRepository(t)
+	service := NewVerificationService(mockRepo, utils.DefaultHashParams())
+
+	// Nothing is sent when the verification isn't stored
+	mockRepo.EXPECT().
+		AddPendingVerification(mock.Anything, mock.Anything, mock.Anything).
+		Return(0, errors.New("db down"))
+
+	err := service.SendVerificationEmail(context.Background(), 1, "user@test.com")
+	assert.EqualError(t, err, "db down")
+}
+
+func TestVerificationService_RegisterUser(t *testing.T) {
+	mockRepo := repo.NewMockVerificationRepository(t)
+	service := NewVerificationService(mockRepo, utils.DefaultHashParams())
+
+	request := models.CreateUserRequest{Email: "user@test.com", Password: "test1234", Name: "Ana", Surname: "Diaz", Role: "student"}
+
+	var sent mailer.Message
+	mockRepo.EXPECT().
+		AddUserPendingVerification(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, user *models.User, verification *models.UserVerification, outbox models.Outbox) (int, error) {
+			assert.Equal(t, request.Email, user.Email)
+			assert.NotEqual(t, request.Password, user.Password)
+			assert.Equal(t, request.Email, verification.UserEmail)
+			sent = outboxEmail(t, outbox, 3)
+			return 7, nil
+		})
+
+	id, err := service.RegisterUser(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, id)
+	assert.Equal(t, []mailer.Address{{Name: "User", Email: request.Email}}, sent.To)
+	assert.Contains(t, sent.Text, "Your verification code is 3-")
 }
 
 func TestUpdatePin(t *testing.T) {
 	mockRepo := repo.NewMockVerificationRepository(t)
-	mockEmail := NewMockEmailSender(t)
-	service := NewVerificationService(mockRepo, mockEmail)
+	service := NewVerificationService(mockRepo, utils.DefaultHashParams())
 
-	ctx := context.Background()
+	ctx := mailer.WithLocales(context.Background(), []string{"en"})
 	userID := 1
 	email := "user@test.com"
 	verification := &models.UserVerification{
@@ -131,24 +182,24 @@ func TestUpdatePin(t *testing.T) {
 		GetVerificationByEmail(ctx, email).
 		Return(verification, nil)
 
+	var sent mailer.Message
 	mockRepo.EXPECT().
-		UpdatePin(ctx, verification.Id, mock.AnythingOfType("string")).
-		Return(nil)
-
-	mockEmail.On("Send", mock.AnythingOfType("*mail.SGMailV3")).
-		Return(&rest.Response{StatusCode: 202}, nil)
+		UpdatePin(ctx, verification.Id, mock.AnythingOfType("string"), mock.Anything).
+		RunAndReturn(func(_ context.Context, id int, pin string, outbox models.Outbox) error {
+			sent = outboxEmail(t, outbox, id)
+			return nil
+		})
 
 	err := service.UpdatePin(ctx, userID, email)
 	assert.NoError(t, err)
+	assert.Contains(t, sent.Text, "Your verification code is 42-")
 
 	mockRepo.AssertExpectations(t)
-	mockEmail.AssertExpectations(t)
 }
 
 func TestUpdatePin_VerificationEmailNotFound(t *testing.T) {
 	mockRepo := repo.NewMockVerificationRepository(t)
-	mockEmail := NewMockEmailSender(t)
-	service := NewVerificationService(mockRepo, mockEmail)
+	service := NewVerificationService(mockRepo, utils.DefaultHashParams())
 
 	ctx := context.Background()
 	userID := 1
@@ -159,15 +210,11 @@ func TestUpdatePin_VerificationEmailNotFound(t *testing.T) {
 		Return(nil, repo.ErrNotFound)
 
 	mockRepo.EXPECT().
-		AddPendingVerification(ctx, mock.AnythingOfType("*models.UserVerification")).
+		AddPendingVerification(ctx, mock.AnythingOfType("*models.UserVerification"), mock.Anything).
 		Return(123, nil)
 
-	mockEmail.On("Send", mock.AnythingOfType("*mail.SGMailV3")).
-		Return(&rest.Response{StatusCode: 202}, nil)
-
 	err := service.UpdatePin(ctx, userID, email)
 	assert.NoError(t, err)
 
 	mockRepo.AssertExpectations(t)
-	mockEmail.AssertExpectations(t)
 }
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffChunk is a run of text that is in both texts, or only in one of them
+type DiffChunk struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// maxDiffCells bounds the memory used to diff two texts, larger ones are diffed as a whole replacement
+const maxDiffCells = 4_000_000
+
+var diffTokens = regexp.MustCompile(`\s+|[^\s]+`)
+
+// DiffWords returns the changes that turn from into to, word by word.
+// Joining the equal and delete chunks gives from back, joining the equal and insert chunks gives to.
+func DiffWords(from string, to string) []DiffChunk {
+	a := diffTokens.FindAllString(from, -1)
+	b := diffTokens.FindAllString(to, -1)
+
+	chunks := []DiffChunk{}
+	add := func(op DiffOp, text string) {
+		if last := len(chunks) - 1; last >= 0 && chunks[last].Op == op {
+			chunks[last].Text += text
+			return
+		}
+		chunks = append(chunks, DiffChunk{Op: op, Text: text})
+	}
+
+	// Common prefix and suffix don't need the table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	if prefix > 0 {
+		add(DiffEqual, strings.Join(a[:prefix], ""))
+	}
+	middleA, middleB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	if len(middleA)*len(middleB) > maxDiffCells {
+		if len(middleA) > 0 {
+			add(DiffDelete, strings.Join(middleA, ""))
+		}
+		if len(middleB) > 0 {
+			add(DiffInsert, strings.Join(middleB, ""))
+		}
+	} else {
+		// lcs[i][j] is the length of the longest common subsequence of middleA[i:] and middleB[j:]
+		lcs := make([][]int, len(middleA)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(middleB)+1)
+		}
+		for i := len(middleA) - 1; i >= 0; i-- {
+			for j := len(middleB) - 1; j >= 0; j-- {
+				if middleA[i] == middleB[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+
+		i, j := 0, 0
+		for i < len(middleA) && j < len(middleB) {
+			switch {
+			case middleA[i] == middleB[j]:
+				add(DiffEqual, middleA[i])
+				i++
+				j++
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				add(DiffDelete, middleA[i])
+				i++
+			default:
+				add(DiffInsert, middleB[j])
+				j++
+			}
+		}
+		for ; i < len(middleA); i++ {
+			add(DiffDelete, middleA[i])
+		}
+		for ; j < len(middleB); j++ {
+			add(DiffInsert, middleB[j])
+		}
+	}
+
+	if suffix > 0 {
+		add(DiffEqual, strings.Join(a[len(a)-suffix:], ""))
+	}
+	return chunks
+}
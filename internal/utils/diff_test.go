@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func joinDiff(chunks []DiffChunk, skip DiffOp) string {
+	var text strings.Builder
+	for _, chunk := range chunks {
+		if chunk.Op != skip {
+			text.WriteString(chunk.Text)
+		}
+	}
+	return text.String()
+}
+
+func TestDiffWords(t *testing.T) {
+	from := "Students must attend 75% of the classes to pass."
+	to := "Students must attend 80% of the practical classes to pass."
+
+	chunks := DiffWords(from, to)
+
+	assert.Equal(t, []DiffChunk{
+		{Op: DiffEqual, Text: "Students must attend "},
+		{Op: DiffDelete, Text: "75%"},
+		{Op: DiffInsert, Text: "80%"},
+		{Op: DiffEqual, Text: " of the"},
+		{Op: DiffInsert, Text: " practical"},
+		{Op: DiffEqual, Text: " classes to pass."},
+	}, chunks)
+	assert.Equal(t, from, joinDiff(chunks, DiffInsert))
+	assert.Equal(t, to, joinDiff(chunks, DiffDelete))
+}
+
+func TestDiffWords_EdgeCases(t *testing.T) {
+	assert.Equal(t, []DiffChunk{}, DiffWords("", ""))
+	assert.Equal(t, []DiffChunk{{Op: DiffEqual, Text: "same text"}}, DiffWords("same text", "same text"))
+	assert.Equal(t, []DiffChunk{{Op: DiffInsert, Text: "new rule"}}, DiffWords("", "new rule"))
+	assert.Equal(t, []DiffChunk{{Op: DiffDelete, Text: "old rule"}}, DiffWords("old rule", ""))
+}
@@ -0,0 +1,39 @@
+package utils
+
+import "strings"
+
+// MatchesETag reports whether an If-None-Match header value matches etag.
+// The header may hold "*" or a comma separated list of entity tags; weak validators
+// are compared by their opaque value.
+func MatchesETag(header string, etag string) bool {
+	return matchesETag(header, func(candidate string) bool {
+		return strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/")
+	})
+}
+
+// MatchesStrongETag reports whether an If-Match header value matches etag. Writes need
+// the exact representation the client read, so weak validators never match (RFC 9110 13.1.1).
+func MatchesStrongETag(header string, etag string) bool {
+	if strings.HasPrefix(etag, "W/") {
+		return false
+	}
+	return matchesETag(header, func(candidate string) bool {
+		return candidate == etag
+	})
+}
+
+func matchesETag(header string, matches func(candidate string) bool) bool {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		if matches(strings.TrimSpace(candidate)) {
+			return true
+		}
+	}
+	return false
+}
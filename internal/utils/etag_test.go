@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMatchesETag(t *testing.T) {
+	assert.True(t, MatchesETag(`"3"`, `"3"`))
+	assert.True(t, MatchesETag(`*`, `"3"`))
+	assert.True(t, MatchesETag(`"1", "3"`, `"3"`))
+	assert.True(t, MatchesETag(`W/"3"`, `"3"`))
+	assert.False(t, MatchesETag(`"2"`, `"3"`))
+	assert.False(t, MatchesETag(``, `"3"`))
+}
+
+func TestMatchesStrongETag(t *testing.T) {
+	assert.True(t, MatchesStrongETag(`"3"`, `"3"`))
+	assert.True(t, MatchesStrongETag(`*`, `"3"`))
+	assert.True(t, MatchesStrongETag(`"1", "3"`, `"3"`))
+	// Weak validators can't be used for writes
+	assert.False(t, MatchesStrongETag(`W/"3"`, `"3"`))
+	assert.False(t, MatchesStrongETag(`"3"`, `W/"3"`))
+	assert.False(t, MatchesStrongETag(`"2"`, `"3"`))
+	assert.False(t, MatchesStrongETag(``, `"3"`))
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var exportTime = time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+func writeTable(t *testing.T, format string) []byte {
+	var buf bytes.Buffer
+	writer, err := NewTableWriter(format, &buf, []string{"id", "email", "verified", "photo", "created_at"})
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteRow([]any{1, "ana@example.com", true, nil, exportTime}))
+	require.NoError(t, writer.WriteRow([]any{2, "=cmd<x>&y", false, "p.jpg", exportTime}))
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestTableWriter_CSV(t *testing.T) {
+	out := writeTable(t, "csv")
+
+	assert.Equal(t, "id,email,verified,photo,created_at\n"+
+		"1,ana@example.com,true,,2025-03-01T10:00:00Z\n"+
+		"2,'=cmd<x>&y,false,p.jpg,2025-03-01T10:00:00Z\n", string(out))
+}
+
+func TestTableWriter_JSONL(t *testing.T) {
+	out := writeTable(t, "jsonl")
+
+	assert.Equal(t, `{"id":1,"email":"ana@example.com","verified":true,"photo":null,"created_at":"2025-03-01T10:00:00Z"}`+"\n"+
+		`{"id":2,"email":"=cmd\u003cx\u003e\u0026y","verified":false,"photo":"p.jpg","created_at":"2025-03-01T10:00:00Z"}`+"\n", string(out))
+}
+
+func TestTableWriter_XLSX(t *testing.T) {
+	out := writeTable(t, "xlsx")
+
+	archive, err := zip.NewReader(bytes.NewReader(out), int64(len(out)))
+	require.NoError(t, err)
+
+	var names []string
+	var sheet string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			r, err := file.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(r)
+			require.NoError(t, err)
+			sheet = string(content)
+		}
+	}
+
+	assert.ElementsMatch(t, []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"}, names)
+	assert.Contains(t, sheet, `<c r="A1" t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`)
+	assert.Contains(t, sheet, `<row r="2"><c r="A2"><v>1</v></c>`)
+	assert.Contains(t, sheet, `<c r="C2" t="b"><v>1</v></c><c r="E2"`)
+	assert.Contains(t, sheet, `<t xml:space="preserve">=cmd&lt;x&gt;&amp;y</t>`)
+	assert.Contains(t, sheet, `</row></sheetData></worksheet>`)
+}
+
+func TestTableWriter_UnsupportedFormat(t *testing.T) {
+	_, err := NewTableWriter("pdf", io.Discard, []string{"id"})
+	assert.Error(t, err)
+}
+
+func TestXLSXColumnName(t *testing.T) {
+	assert.Equal(t, "A", xlsxColumnName(0))
+	assert.Equal(t, "Z", xlsxColumnName(25))
+	assert.Equal(t, "AA", xlsxColumnName(26))
+	assert.Equal(t, "AZ", xlsxColumnName(51))
+	assert.Equal(t, "BA", xlsxColumnName(52))
+}
@@ -0,0 +1,698 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Expressions are small boolean conditions over named attributes, like
+//
+//	role == "student" && created_at < "2026-01-01"
+//	!blocked && (role in ["student", "teacher"] || id == 1)
+//
+// They support string, number and boolean literals, lists of literals after in, comparisons,
+// &&, || and ! with the usual precedence, and parentheses. There are no function calls or
+// loops, so evaluating an expression takes time proportional to its length.
+// Times are compared against strings holding a date (YYYY-MM-DD) or an RFC 3339 timestamp.
+
+// ExprKind is the type of a value in an expression
+type ExprKind int
+
+const (
+	ExprString ExprKind = iota + 1
+	ExprNumber
+	ExprBool
+	ExprTime
+)
+
+func (k ExprKind) String() string {
+	switch k {
+	case ExprString:
+		return "string"
+	case ExprNumber:
+		return "number"
+	case ExprBool:
+		return "bool"
+	case ExprTime:
+		return "time"
+	}
+	return "unknown"
+}
+
+const (
+	// MaxExpressionLength bounds the size of the expressions accepted
+	MaxExpressionLength = 1000
+	// maxExpressionDepth bounds how deep parentheses and operators nest
+	maxExpressionDepth = 32
+)
+
+var ErrInvalidExpression = errors.New("invalid expression")
+
+// ExpressionError is a problem with an expression, at a byte offset of its source
+type ExpressionError struct {
+	Pos     int
+	Message string
+}
+
+func (e *ExpressionError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Pos)
+}
+
+func (e *ExpressionError) Unwrap() error {
+	return ErrInvalidExpression
+}
+
+func exprErrorf(pos int, format string, args ...any) *ExpressionError {
+	return &ExpressionError{Pos: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// Expression is a parsed expression, safe for concurrent use
+type Expression struct {
+	source string
+	root   exprNode
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+// ParseExpression parses the source, reporting the first syntax error found
+func ParseExpression(source string) (*Expression, error) {
+	if len(source) > MaxExpressionLength {
+		return nil, exprErrorf(MaxExpressionLength, "expression is longer than %d characters", MaxExpressionLength)
+	}
+	tokens, err := lexExpression(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, exprErrorf(next.pos, "unexpected %s", next)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+// Check verifies the expression only uses the attributes in the schema, with operators that suit
+// their types, and that it results in a boolean
+func (e *Expression) Check(schema map[string]ExprKind) error {
+	kind, err := e.root.check(schema)
+	if err != nil {
+		return err
+	}
+	if kind != ExprBool {
+		return exprErrorf(e.root.position(), "expression is a %s, not a condition", kind)
+	}
+	return nil
+}
+
+// Evaluate computes the expression with the given attribute values, which can be strings,
+// numbers, booleans or times. Expressions that passed Check only fail on missing attributes.
+func (e *Expression) Evaluate(vars map[string]any) (bool, error) {
+	value, err := e.root.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	result, ok := value.(bool)
+	if !ok {
+		return false, exprErrorf(e.root.position(), "expression is not a condition")
+	}
+	return result, nil
+}
+
+// Lexer
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenTrue
+	tokenFalse
+	tokenIn
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+	// Decoded value of string and number literals
+	value any
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// exprOperators are matched in order, longer ones first
+var exprOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!"}
+
+var exprPunctuation = map[byte]tokenKind{'(': tokenLParen, ')': tokenRParen, '[': tokenLBracket, ']': tokenRBracket, ',': tokenComma}
+
+func lexExpression(source string) ([]token, error) {
+	var tokens []token
+	pos := 0
+	for pos < len(source) {
+		c := source[pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			pos++
+		case c == '(' || c == ')' || c == '[' || c == ']' || c == ',':
+			tokens = append(tokens, token{kind: exprPunctuation[c], text: string(c), pos: pos})
+			pos++
+		case c == '"':
+			value, end, err := lexString(source, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: source[pos:end], pos: pos, value: value})
+			pos = end
+		// A dot only starts a number before a digit, so one after a name like user.role is reported as it is
+		case c >= '0' && c <= '9' || c == '-' || c == '.' && pos+1 < len(source) && source[pos+1] >= '0' && source[pos+1] <= '9':
+			end := pos + 1
+			for end < len(source) && (source[end] >= '0' && source[end] <= '9' || source[end] == '.') {
+				end++
+			}
+			number, err := strconv.ParseFloat(source[pos:end], 64)
+			if err != nil {
+				return nil, exprErrorf(pos, "invalid number %q", source[pos:end])
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: source[pos:end], pos: pos, value: number})
+			pos = end
+		case c == '_' || c < unicode.MaxASCII && unicode.IsLetter(rune(c)):
+			end := pos + 1
+			for end < len(source) && (source[end] == '_' || source[end] < unicode.MaxASCII &&
+				(unicode.IsLetter(rune(source[end])) || unicode.IsDigit(rune(source[end])))) {
+				end++
+			}
+			word := source[pos:end]
+			kind := tokenIdent
+			switch word {
+			case "true":
+				kind = tokenTrue
+			case "false":
+				kind = tokenFalse
+			case "in":
+				kind = tokenIn
+			}
+			tokens = append(tokens, token{kind: kind, text: word, pos: pos})
+			pos = end
+		default:
+			operator := ""
+			for _, candidate := range exprOperators {
+				if strings.HasPrefix(source[pos:], candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, exprErrorf(pos, "unexpected character %q", source[pos:pos+1])
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operator, pos: pos})
+			pos += len(operator)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(source)}), nil
+}
+
+// lexString decodes the string literal starting at pos, returning where it ends
+func lexString(source string, pos int) (string, int, error) {
+	var value strings.Builder
+	for i := pos + 1; i < len(source); i++ {
+		switch source[i] {
+		case '"':
+			return value.String(), i + 1, nil
+		case '\\':
+			if i+1 >= len(source) || (source[i+1] != '"' && source[i+1] != '\\') {
+				return "", 0, exprErrorf(i, `invalid escape, only \" and \\ are allowed`)
+			}
+			i++
+			value.WriteByte(source[i])
+		default:
+			value.WriteByte(source[i])
+		}
+	}
+	return "", 0, exprErrorf(pos, "unterminated string")
+}
+
+// Parser, one function per precedence level
+
+type exprParser struct {
+	tokens []token
+	next   int
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *exprParser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+func (p *exprParser) acceptOperator(operator string) bool {
+	if t := p.peek(); t.kind == tokenOperator && t.text == operator {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) parseOr(depth int) (exprNode, error) {
+	if depth > maxExpressionDepth {
+		return nil, exprErrorf(p.peek().pos, "expression nests deeper than %d levels", maxExpressionDepth)
+	}
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		pos := p.peek().pos
+		if !p.acceptOperator("||") {
+			return left, nil
+		}
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "||", left: left, right: right, pos: pos}
+	}
+}
+
+func (p *exprParser) parseAnd(depth int) (exprNode, error) {
+	left, err := p.parseNot(depth)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		pos := p.peek().pos
+		if !p.acceptOperator("&&") {
+			return left, nil
+		}
+		right, err := p.parseNot(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "&&", left: left, right: right, pos: pos}
+	}
+}
+
+func (p *exprParser) parseNot(depth int) (exprNode, error) {
+	pos := p.peek().pos
+	if p.acceptOperator("!") {
+		if depth+1 > maxExpressionDepth {
+			return nil, exprErrorf(pos, "expression nests deeper than %d levels", maxExpressionDepth)
+		}
+		operand, err := p.parseNot(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand, pos: pos}, nil
+	}
+	return p.parseComparison(depth)
+}
+
+func (p *exprParser) parseComparison(depth int) (exprNode, error) {
+	left, err := p.parseOperand(depth)
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	switch {
+	case t.kind == tokenIn:
+		p.advance()
+		list, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return &inNode{value: left, list: list, pos: t.pos}, nil
+	case t.kind == tokenOperator && isComparison(t.text):
+		p.advance()
+		right, err := p.parseOperand(depth)
+		if err != nil {
+			return nil, err
+		}
+		if next := p.peek(); next.kind == tokenOperator && isComparison(next.text) {
+			return nil, exprErrorf(next.pos, "comparisons can't be chained, use &&")
+		}
+		return &binaryNode{op: t.text, left: left, right: right, pos: t.pos}, nil
+	}
+	return left, nil
+}
+
+func isComparison(operator string) bool {
+	switch operator {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) parseOperand(depth int) (exprNode, error) {
+	t := p.advance()
+	switch t.kind {
+	case tokenIdent:
+		return &identNode{name: t.text, pos: t.pos}, nil
+	case tokenString, tokenNumber, tokenTrue, tokenFalse:
+		return literalFromToken(t), nil
+	case tokenLParen:
+		inner, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.advance(); closing.kind != tokenRParen {
+			return nil, exprErrorf(closing.pos, "expected \")\" but found %s", closing)
+		}
+		return inner, nil
+	}
+	return nil, exprErrorf(t.pos, "expected a value but found %s", t)
+}
+
+func (p *exprParser) parseList() ([]*literalNode, error) {
+	if open := p.advance(); open.kind != tokenLBracket {
+		return nil, exprErrorf(open.pos, "expected \"[\" after in but found %s", open)
+	}
+	var items []*literalNode
+	for {
+		t := p.advance()
+		switch t.kind {
+		case tokenString, tokenNumber, tokenTrue, tokenFalse:
+			items = append(items, literalFromToken(t))
+		default:
+			return nil, exprErrorf(t.pos, "lists can only hold literals, found %s", t)
+		}
+		separator := p.advance()
+		if separator.kind == tokenRBracket {
+			return items, nil
+		}
+		if separator.kind != tokenComma {
+			return nil, exprErrorf(separator.pos, "expected \",\" or \"]\" but found %s", separator)
+		}
+	}
+}
+
+func literalFromToken(t token) *literalNode {
+	switch t.kind {
+	case tokenString:
+		return &literalNode{value: t.value, kind: ExprString, pos: t.pos}
+	case tokenNumber:
+		return &literalNode{value: t.value, kind: ExprNumber, pos: t.pos}
+	}
+	return &literalNode{value: t.kind == tokenTrue, kind: ExprBool, pos: t.pos}
+}
+
+// Syntax tree
+
+type exprNode interface {
+	check(schema map[string]ExprKind) (ExprKind, error)
+	eval(vars map[string]any) (any, error)
+	position() int
+}
+
+type literalNode struct {
+	value any
+	kind  ExprKind
+	pos   int
+}
+
+func (n *literalNode) check(map[string]ExprKind) (ExprKind, error) { return n.kind, nil }
+func (n *literalNode) eval(map[string]any) (any, error)            { return n.value, nil }
+func (n *literalNode) position() int                               { return n.pos }
+
+type identNode struct {
+	name string
+	pos  int
+}
+
+func (n *identNode) check(schema map[string]ExprKind) (ExprKind, error) {
+	kind, ok := schema[n.name]
+	if !ok {
+		return 0, exprErrorf(n.pos, "unknown attribute %q", n.name)
+	}
+	return kind, nil
+}
+
+func (n *identNode) eval(vars map[string]any) (any, error) {
+	value, ok := vars[n.name]
+	if !ok {
+		return nil, exprErrorf(n.pos, "unknown attribute %q", n.name)
+	}
+	switch v := value.(type) {
+	case string, float64, bool, time.Time:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	}
+	return nil, exprErrorf(n.pos, "attribute %q has an unsupported type %T", n.name, value)
+}
+
+func (n *identNode) position() int { return n.pos }
+
+type notNode struct {
+	operand exprNode
+	pos     int
+}
+
+func (n *notNode) check(schema map[string]ExprKind) (ExprKind, error) {
+	kind, err := n.operand.check(schema)
+	if err != nil {
+		return 0, err
+	}
+	if kind != ExprBool {
+		return 0, exprErrorf(n.pos, "! needs a bool, not a %s", kind)
+	}
+	return ExprBool, nil
+}
+
+func (n *notNode) eval(vars map[string]any) (any, error) {
+	value, err := evalBool(n.operand, vars, "!")
+	if err != nil {
+		return nil, err
+	}
+	return !value, nil
+}
+
+func (n *notNode) position() int { return n.pos }
+
+type binaryNode struct {
+	op          string
+	left, right exprNode
+	pos         int
+}
+
+func (n *binaryNode) check(schema map[string]ExprKind) (ExprKind, error) {
+	left, err := n.left.check(schema)
+	if err != nil {
+		return 0, err
+	}
+	right, err := n.right.check(schema)
+	if err != nil {
+		return 0, err
+	}
+
+	if n.op == "&&" || n.op == "||" {
+		if left != ExprBool || right != ExprBool {
+			return 0, exprErrorf(n.pos, "%s needs bools, not a %s and a %s", n.op, left, right)
+		}
+		return ExprBool, nil
+	}
+	if err := checkComparable(n.op, left, n.left, right, n.right, n.pos); err != nil {
+		return 0, err
+	}
+	return ExprBool, nil
+}
+
+func (n *binaryNode) eval(vars map[string]any) (any, error) {
+	switch n.op {
+	case "&&", "||":
+		left, err := evalBool(n.left, vars, n.op)
+		if err != nil {
+			return nil, err
+		}
+		// Short circuit, like the operators it's named after
+		if left == (n.op == "||") {
+			return left, nil
+		}
+		return evalBool(n.right, vars, n.op)
+	}
+
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	order, err := compareValues(left, right, n.op, n.pos)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return order == 0, nil
+	case "!=":
+		return order != 0, nil
+	case "<":
+		return order < 0, nil
+	case "<=":
+		return order <= 0, nil
+	case ">":
+		return order > 0, nil
+	}
+	return order >= 0, nil
+}
+
+func (n *binaryNode) position() int { return n.pos }
+
+type inNode struct {
+	value exprNode
+	list  []*literalNode
+	pos   int
+}
+
+func (n *inNode) check(schema map[string]ExprKind) (ExprKind, error) {
+	kind, err := n.value.check(schema)
+	if err != nil {
+		return 0, err
+	}
+	for _, item := range n.list {
+		if err := checkComparable("in", kind, n.value, item.kind, item, item.pos); err != nil {
+			return 0, err
+		}
+	}
+	return ExprBool, nil
+}
+
+func (n *inNode) eval(vars map[string]any) (any, error) {
+	value, err := n.value.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range n.list {
+		order, err := compareValues(value, item.value, "in", item.pos)
+		if err != nil {
+			return nil, err
+		}
+		if order == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (n *inNode) position() int { return n.pos }
+
+func evalBool(node exprNode, vars map[string]any, operator string) (bool, error) {
+	value, err := node.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	result, ok := value.(bool)
+	if !ok {
+		return false, exprErrorf(node.position(), "%s needs a bool, not %v", operator, value)
+	}
+	return result, nil
+}
+
+// checkComparable verifies the operator can compare values of these kinds. Times can only be
+// compared against string literals, which must hold a valid date.
+func checkComparable(op string, left ExprKind, leftNode exprNode, right ExprKind, rightNode exprNode, pos int) error {
+	if left == ExprTime && right == ExprString || left == ExprString && right == ExprTime {
+		literal, ok := rightNode.(*literalNode)
+		if left == ExprString {
+			literal, ok = leftNode.(*literalNode)
+		}
+		if !ok {
+			return exprErrorf(pos, "times can only be compared with date literals")
+		}
+		if _, err := parseExprTime(literal.value.(string)); err != nil {
+			return exprErrorf(literal.pos, "%q is not a date, use YYYY-MM-DD or RFC 3339", literal.value)
+		}
+		return nil
+	}
+	if left != right {
+		return exprErrorf(pos, "can't compare a %s with a %s", left, right)
+	}
+	if left == ExprBool && op != "==" && op != "!=" && op != "in" {
+		return exprErrorf(pos, "bools can't be ordered with %s", op)
+	}
+	return nil
+}
+
+// compareValues returns -1, 0 or 1 as left is less than, equal to or greater than right
+func compareValues(left any, right any, op string, pos int) (int, error) {
+	// Times compared with strings take the string as a date
+	if _, ok := left.(time.Time); ok {
+		if text, ok := right.(string); ok {
+			parsed, err := parseExprTime(text)
+			if err != nil {
+				return 0, exprErrorf(pos, "%q is not a date", text)
+			}
+			right = parsed
+		}
+	} else if rightTime, ok := right.(time.Time); ok {
+		order, err := compareValues(rightTime, left, op, pos)
+		return -order, err
+	}
+
+	switch l := left.(type) {
+	case string:
+		if r, ok := right.(string); ok {
+			return strings.Compare(l, r), nil
+		}
+	case float64:
+		if r, ok := right.(float64); ok {
+			switch {
+			case l < r:
+				return -1, nil
+			case l > r:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case bool:
+		if r, ok := right.(bool); ok && (op == "==" || op == "!=" || op == "in") {
+			if l == r {
+				return 0, nil
+			}
+			return 1, nil
+		}
+	case time.Time:
+		if r, ok := right.(time.Time); ok {
+			return l.Compare(r), nil
+		}
+	}
+	return 0, exprErrorf(pos, "can't compare %v with %v using %s", left, right, op)
+}
+
+func parseExprTime(text string) (time.Time, error) {
+	if parsed, err := time.Parse(time.DateOnly, text); err == nil {
+		return parsed, nil
+	}
+	return time.Parse(time.RFC3339, text)
+}
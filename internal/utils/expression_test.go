@@ -0,0 +1,206 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = map[string]ExprKind{
+	"id":         ExprNumber,
+	"role":       ExprString,
+	"verified":   ExprBool,
+	"created_at": ExprTime,
+}
+
+var testVars = map[string]any{
+	"id":         7,
+	"role":       "student",
+	"verified":   true,
+	"created_at": time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC),
+}
+
+func TestExpression_Evaluate(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected bool
+	}{
+		{`true`, true},
+		{`false`, false},
+		{`verified`, true},
+		{`!verified`, false},
+		{`!!verified`, true},
+		{`role == "student"`, true},
+		{`role != "student"`, false},
+		{`role == "teacher" || role == "student"`, true},
+		{`role == "student" && created_at < "2026-01-01"`, true},
+		{`created_at >= "2025-11-03T10:00:00Z"`, true},
+		{`created_at > "2025-11-03T10:00:00Z"`, false},
+		{`"2026-01-01" > created_at`, true},
+		{`id > 5 && id <= 7`, true},
+		{`id == 7.0`, true},
+		{`id < -1`, false},
+		{`id > .5`, true},
+		{`role in ["teacher", "student"]`, true},
+		{`role in ["teacher"]`, false},
+		{`id in [1, 2, 3]`, false},
+		{`created_at in ["2025-11-03T10:00:00Z"]`, true},
+		{`verified == true`, true},
+		// && binds tighter than ||
+		{`true || false && false`, true},
+		{`(true || false) && false`, false},
+		{`!(role == "teacher") && verified`, true},
+		{`role == "say \"hi\""`, false},
+		{`role < "teacher"`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			expression, err := ParseExpression(tt.source)
+			require.NoError(t, err)
+			require.NoError(t, expression.Check(testSchema))
+
+			result, err := expression.Evaluate(testVars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, tt.source, expression.String())
+		})
+	}
+}
+
+func TestExpression_ShortCircuit(t *testing.T) {
+	// The right side would fail on the missing attribute if it were evaluated
+	expression, err := ParseExpression(`verified || missing == 1`)
+	require.NoError(t, err)
+
+	result, err := expression.Evaluate(testVars)
+	require.NoError(t, err)
+	assert.True(t, result)
+}
+
+func TestParseExpression_Malformed(t *testing.T) {
+	tests := []struct {
+		source  string
+		pos     int
+		message string
+	}{
+		{``, 0, "expected a value but found end of expression"},
+		{`   `, 3, "expected a value but found end of expression"},
+		{`role ==`, 7, "expected a value but found end of expression"},
+		{`== "student"`, 0, `expected a value but found "=="`},
+		{`role = "student"`, 5, `unexpected character "="`},
+		{`role == "student`, 8, "unterminated string"},
+		{`role == "a\nb"`, 10, "invalid escape"},
+		{`(role == "student"`, 18, `expected ")" but found end of expression`},
+		{`role == "student")`, 17, `unexpected ")"`},
+		{`role == "student" verified`, 18, `unexpected "verified"`},
+		{`role in "student"`, 8, `expected "[" after in`},
+		{`role in []`, 9, "lists can only hold literals"},
+		{`role in ["a" "b"]`, 13, `expected "," or "]"`},
+		{`role in ["a", role]`, 14, "lists can only hold literals"},
+		{`role in ["a",`, 13, "lists can only hold literals"},
+		{`id == 1.2.3`, 6, "invalid number"},
+		{`id == -`, 6, "invalid number"},
+		{`1 < id < 3`, 7, "comparisons can't be chained"},
+		{`role && && verified`, 8, "expected a value"},
+		{`role == 'student'`, 8, "unexpected character"},
+		{`user.role == "student"`, 4, `unexpected character "."`},
+		{`id == .`, 6, `unexpected character "."`},
+		{`rôle == "student"`, 1, "unexpected character"},
+		{`!`, 1, "expected a value"},
+		{`()`, 1, "expected a value"},
+		{`role == "student" ||`, 20, "expected a value"},
+		{`role; DROP TABLE rules`, 4, "unexpected character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			_, err := ParseExpression(tt.source)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrInvalidExpression)
+
+			var expressionErr *ExpressionError
+			require.ErrorAs(t, err, &expressionErr)
+			assert.Equal(t, tt.pos, expressionErr.Pos, expressionErr.Error())
+			assert.Contains(t, expressionErr.Message, tt.message)
+		})
+	}
+}
+
+func TestParseExpression_Limits(t *testing.T) {
+	_, err := ParseExpression(strings.Repeat("true || ", 200) + "true")
+	assert.ErrorIs(t, err, ErrInvalidExpression)
+
+	_, err = ParseExpression(strings.Repeat("(", 40) + "true" + strings.Repeat(")", 40))
+	assert.ErrorContains(t, err, "nests deeper")
+
+	_, err = ParseExpression(strings.Repeat("!", 40) + "true")
+	assert.ErrorContains(t, err, "nests deeper")
+
+	// Long chains don't nest
+	expression, err := ParseExpression(strings.Repeat("true && ", 100) + "true")
+	require.NoError(t, err)
+	result, err := expression.Evaluate(nil)
+	require.NoError(t, err)
+	assert.True(t, result)
+}
+
+func TestExpression_Check(t *testing.T) {
+	tests := []struct {
+		source  string
+		message string
+	}{
+		{`role`, "expression is a string, not a condition"},
+		{`id`, "expression is a number, not a condition"},
+		{`age > 18`, `unknown attribute "age"`},
+		{`role == 1`, "can't compare a string with a number"},
+		{`id == "7"`, "can't compare a number with a string"},
+		{`verified < true`, "bools can't be ordered with <"},
+		{`!role`, "! needs a bool, not a string"},
+		{`role && verified`, "&& needs bools, not a string and a bool"},
+		{`verified || id`, "|| needs bools, not a bool and a number"},
+		{`created_at < "yesterday"`, `"yesterday" is not a date`},
+		{`created_at < role`, "times can only be compared with date literals"},
+		{`created_at < 2026`, "can't compare a time with a number"},
+		{`role in ["student", 1]`, "can't compare a string with a number"},
+		{`created_at in ["soon"]`, `"soon" is not a date`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			expression, err := ParseExpression(tt.source)
+			require.NoError(t, err)
+
+			err = expression.Check(testSchema)
+			assert.ErrorIs(t, err, ErrInvalidExpression)
+			assert.ErrorContains(t, err, tt.message)
+		})
+	}
+}
+
+func TestExpression_EvaluateErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		vars   map[string]any
+	}{
+		{`missing`, testVars},
+		{`role == "student"`, map[string]any{"role": []string{"student"}}},
+		{`role == 1`, testVars},
+		{`role`, testVars},
+		{`!role`, testVars},
+		{`created_at < "yesterday"`, testVars},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			expression, err := ParseExpression(tt.source)
+			require.NoError(t, err)
+
+			_, err = expression.Evaluate(tt.vars)
+			assert.ErrorIs(t, err, ErrInvalidExpression)
+		})
+	}
+}
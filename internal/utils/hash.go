@@ -1,18 +1,168 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	HashAlgorithmBcrypt   = "bcrypt"
+	HashAlgorithmArgon2id = "argon2id"
+	// BcryptMaxPasswordBytes is the longest password bcrypt can hash
+	BcryptMaxPasswordBytes = 72
+)
+
+var (
+	ErrMismatchedHashAndPassword = errors.New("hashed password is not the hash of the given password")
+	ErrUnknownHashFormat         = errors.New("unknown password hash format")
+)
+
+// HashParams selects the algorithm new password hashes are created with and its parameters.
+// Hashes made with other parameters still verify, and NeedsRehash reports them as outdated.
+type HashParams struct {
+	Algorithm  string
+	BcryptCost int
+	// Argon2 memory is in KiB
+	Argon2Memory      uint32
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+	Argon2SaltLength  uint32
+	Argon2KeyLength   uint32
+}
+
+// DefaultHashParams returns Argon2id with the parameters recommended by OWASP.
+func DefaultHashParams() HashParams {
+	return HashParams{
+		Algorithm:         HashAlgorithmArgon2id,
+		BcryptCost:        bcrypt.DefaultCost,
+		Argon2Memory:      19 * 1024,
+		Argon2Iterations:  2,
+		Argon2Parallelism: 1,
+		Argon2SaltLength:  16,
+		Argon2KeyLength:   32,
+	}
+}
+
+// Validate checks the algorithm is known and its parameters are ones it can hash with
+func (params HashParams) Validate() error {
+	switch params.Algorithm {
+	case HashAlgorithmBcrypt:
+		if params.BcryptCost < bcrypt.MinCost || params.BcryptCost > bcrypt.MaxCost {
+			return fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case HashAlgorithmArgon2id:
+		if params.Argon2Iterations < 1 || params.Argon2Parallelism < 1 || params.Argon2SaltLength < 1 || params.Argon2KeyLength < 1 {
+			return errors.New("argon2 iterations, parallelism, salt and key lengths must be at least 1")
+		}
+		// Argon2 needs at least 8 KiB for each thread
+		if params.Argon2Memory < 8*uint32(params.Argon2Parallelism) {
+			return fmt.Errorf("argon2 memory must be at least %d KiB", 8*uint32(params.Argon2Parallelism))
+		}
+	default:
+		return fmt.Errorf("unknown hash algorithm %q, use %q or %q", params.Algorithm, HashAlgorithmArgon2id, HashAlgorithmBcrypt)
+	}
+	return nil
+}
+
+// HashPassword hashes password with the default parameters
 func HashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
+	return DefaultHashParams().Hash(password)
+}
+
+// Hash hashes password with the algorithm and parameters
+func (params HashParams) Hash(password string) (string, error) {
+	if params.Algorithm == HashAlgorithmBcrypt {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), params.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hashedPassword), nil
+	}
+
+	salt := make([]byte, params.Argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
 		return "", err
 	}
+	key := argon2.IDKey([]byte(password), salt, params.Argon2Iterations, params.Argon2Memory, params.Argon2Parallelism, params.Argon2KeyLength)
 
-	return string(hashedPassword), nil
+	// PHC string format, the same one used by the reference implementation
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.Argon2Memory, params.Argon2Iterations, params.Argon2Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
 }
 
+// CompareHashPassword checks password against a bcrypt or Argon2id hash, returning nil on a match.
 func CompareHashPassword(hashedPassword, password string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	if !strings.HasPrefix(hashedPassword, "$argon2id$") {
+		return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	}
+
+	params, salt, key, err := decodeArgon2id(hashedPassword)
+	if err != nil {
+		return err
+	}
+	other := argon2.IDKey([]byte(password), salt, params.Argon2Iterations, params.Argon2Memory, params.Argon2Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrMismatchedHashAndPassword
+	}
+	return nil
+}
+
+// NeedsRehash reports whether hashedPassword was made with a different algorithm or
+// parameters than these.
+func (params HashParams) NeedsRehash(hashedPassword string) bool {
+	if !strings.HasPrefix(hashedPassword, "$argon2id$") {
+		if params.Algorithm != HashAlgorithmBcrypt {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(hashedPassword))
+		return err != nil || cost != params.BcryptCost
+	}
+
+	if params.Algorithm != HashAlgorithmArgon2id {
+		return true
+	}
+	used, salt, key, err := decodeArgon2id(hashedPassword)
+	if err != nil {
+		return true
+	}
+	return used.Argon2Memory != params.Argon2Memory ||
+		used.Argon2Iterations != params.Argon2Iterations ||
+		used.Argon2Parallelism != params.Argon2Parallelism ||
+		uint32(len(salt)) != params.Argon2SaltLength ||
+		uint32(len(key)) != params.Argon2KeyLength
+}
+
+func decodeArgon2id(hashedPassword string) (HashParams, []byte, []byte, error) {
+	var params HashParams
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Argon2Memory, &params.Argon2Iterations, &params.Argon2Parallelism); err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+	params.Algorithm = HashAlgorithmArgon2id
+	return params, salt, key, nil
 }
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashPassword_Argon2id(t *testing.T) {
+	hash, err := HashPassword("Secret123")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=19456,t=2,p=1$"))
+	assert.NoError(t, CompareHashPassword(hash, "Secret123"))
+	assert.ErrorIs(t, CompareHashPassword(hash, "Secret124"), ErrMismatchedHashAndPassword)
+	assert.False(t, DefaultHashParams().NeedsRehash(hash))
+}
+
+func TestHashPassword_Bcrypt(t *testing.T) {
+	params := DefaultHashParams()
+	params.Algorithm = HashAlgorithmBcrypt
+	params.BcryptCost = bcrypt.MinCost
+
+	hash, err := params.Hash("Secret123")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(hash, "$2a$"))
+	assert.NoError(t, CompareHashPassword(hash, "Secret123"))
+	assert.False(t, params.NeedsRehash(hash))
+}
+
+func TestNeedsRehash(t *testing.T) {
+	params := DefaultHashParams()
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("Secret123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	assert.True(t, params.NeedsRehash(string(legacy)))
+	// Legacy hashes keep working until they are upgraded
+	assert.NoError(t, CompareHashPassword(string(legacy), "Secret123"))
+
+	weaker := DefaultHashParams()
+	weaker.Argon2Iterations = 1
+	oldArgon, err := weaker.Hash("Secret123")
+	require.NoError(t, err)
+
+	assert.True(t, params.NeedsRehash(oldArgon))
+	assert.NoError(t, CompareHashPassword(oldArgon, "Secret123"))
+}
+
+func TestCompareHashPassword_MalformedArgon2id(t *testing.T) {
+	for _, hash := range []string{
+		"$argon2id$v=19$m=19456,t=2,p=1$onlysalt",
+		"$argon2id$v=18$m=19456,t=2,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=x,t=2,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=19456,t=2,p=1$c2FsdA$!!",
+	} {
+		assert.ErrorIs(t, CompareHashPassword(hash, "Secret123"), ErrUnknownHashFormat, hash)
+		assert.True(t, DefaultHashParams().NeedsRehash(hash), hash)
+	}
+}
+
+func TestHashParams_Validate(t *testing.T) {
+	assert.NoError(t, DefaultHashParams().Validate())
+
+	for name, change := range map[string]func(*HashParams){
+		"unknown algorithm":   func(p *HashParams) { p.Algorithm = "argon2" },
+		"no parallelism":      func(p *HashParams) { p.Argon2Parallelism = 0 },
+		"no iterations":       func(p *HashParams) { p.Argon2Iterations = 0 },
+		"too little memory":   func(p *HashParams) { p.Argon2Parallelism = 4; p.Argon2Memory = 31 },
+		"bcrypt cost too low": func(p *HashParams) { p.Algorithm = HashAlgorithmBcrypt; p.BcryptCost = 3 },
+		"bcrypt cost too high": func(p *HashParams) {
+			p.Algorithm = HashAlgorithmBcrypt
+			p.BcryptCost = 32
+		},
+	} {
+		params := DefaultHashParams()
+		change(&params)
+		assert.Error(t, params.Validate(), name)
+	}
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is the language content is written in when there is nothing for the locales the client
+// accepts. Both the base title and description of the rules and the fallback variant of the emails are in it.
+const DefaultLocale = "en"
+
+// AcceptedLocales returns the locales in an Accept-Language header, lowercase and most preferred first.
+// The base language follows every regional locale so "es-AR" falls back to "es". Wildcards and invalid headers accept none.
+func AcceptedLocales(header string) []string {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+	var locales []string
+	seen := map[string]bool{}
+	add := func(locale string) {
+		locale = strings.ToLower(locale)
+		if locale != "und" && locale != "mul" && !seen[locale] {
+			seen[locale] = true
+			locales = append(locales, locale)
+		}
+	}
+	for _, tag := range tags {
+		add(tag.String())
+		if base, confidence := tag.Base(); confidence != language.No {
+			add(base.String())
+		}
+	}
+	return locales
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcceptedLocales(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected []string
+	}{
+		{"es", []string{"es"}},
+		{"es-AR,es;q=0.9,en;q=0.8", []string{"es-ar", "es", "en"}},
+		{"en;q=0.5, pt-BR", []string{"pt-br", "pt", "en"}},
+		{"*", nil},
+		{"", nil},
+		{"not a;;language", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.expected, AcceptedLocales(tt.header))
+		})
+	}
+}